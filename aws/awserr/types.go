@@ -108,6 +108,20 @@ func (b baseError) OrigErrs() []error {
 	return b.errs
 }
 
+// Unwrap returns the original error if one was set, so that the original
+// errors are part of the error's chain for errors.Is and errors.As. If
+// multiple errors were set they are all part of the chain.
+func (b baseError) Unwrap() error {
+	switch len(b.errs) {
+	case 0:
+		return nil
+	case 1:
+		return b.errs[0]
+	default:
+		return errorList(b.errs)
+	}
+}
+
 // So that the Error interface type can be included as an anonymous field
 // in the requestError struct and not conflict with the error.Error() method.
 type awsError Error
@@ -161,6 +175,12 @@ func (r requestError) RequestID() string {
 	return r.requestID
 }
 
+// Unwrap returns the wrapped error, so that the wrapped error and its
+// original errors are part of the error's chain for errors.Is and errors.As.
+func (r requestError) Unwrap() error {
+	return r.awsError
+}
+
 // OrigErrs returns the original errors if one was set. An empty slice is
 // returned if no error was set.
 func (r requestError) OrigErrs() []error {
@@ -0,0 +1,26 @@
+// +build go1.13
+
+package awserr
+
+import "errors"
+
+// Is returns whether any error of the list matches the target.
+func (e errorList) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error of the list that matches the target, and if so,
+// sets target to that error value and returns true.
+func (e errorList) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+// +build go1.13
+
+package awserr_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+func TestErrorIs(t *testing.T) {
+	sentinel := awserr.New("Sentinel", "sentinel error", nil)
+
+	cases := map[string]struct {
+		Err    error
+		Target error
+		Expect bool
+	}{
+		"orig error": {
+			Err:    awserr.New("Code", "message", io.ErrUnexpectedEOF),
+			Target: io.ErrUnexpectedEOF,
+			Expect: true,
+		},
+		"nested orig error": {
+			Err: awserr.New("Outer", "message",
+				awserr.New("Inner", "message", context.Canceled)),
+			Target: context.Canceled,
+			Expect: true,
+		},
+		"awserr orig error": {
+			Err:    awserr.New("Code", "message", sentinel),
+			Target: sentinel,
+			Expect: true,
+		},
+		"request failure": {
+			Err: awserr.NewRequestFailure(
+				awserr.New("Code", "message", sentinel), 400, "requestID"),
+			Target: sentinel,
+			Expect: true,
+		},
+		"batch error": {
+			Err: awserr.NewBatchError("Code", "message",
+				[]error{io.EOF, sentinel}),
+			Target: sentinel,
+			Expect: true,
+		},
+		"no orig error": {
+			Err:    awserr.New("Code", "message", nil),
+			Target: io.EOF,
+		},
+		"other error": {
+			Err:    awserr.New("Code", "message", io.ErrUnexpectedEOF),
+			Target: io.EOF,
+		},
+	}
+
+	for name, c := range cases {
+		if e, a := c.Expect, errors.Is(c.Err, c.Target); e != a {
+			t.Errorf("%s, expect %v, got %v", name, e, a)
+		}
+	}
+}
+
+func TestErrorAs(t *testing.T) {
+	err := awserr.NewRequestFailure(awserr.New("Code", "message",
+		&os.PathError{Op: "open", Path: "file", Err: os.ErrNotExist}), 500, "requestID")
+
+	var pathErr *os.PathError
+	if !errors.As(err, &pathErr) {
+		t.Fatalf("expect PathError in chain, got none")
+	}
+	if e, a := "file", pathErr.Path; e != a {
+		t.Errorf("expect %v path, got %v", e, a)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expect error to be %v", os.ErrNotExist)
+	}
+
+	var reqErr awserr.RequestFailure
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("expect RequestFailure in chain, got none")
+	}
+	if e, a := 500, reqErr.StatusCode(); e != a {
+		t.Errorf("expect %v status code, got %v", e, a)
+	}
+}
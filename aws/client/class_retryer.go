@@ -0,0 +1,81 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// An OperationClass is the class of an API operation, used to configure the
+// retries of reads, listings, and writes separately.
+type OperationClass int
+
+const (
+	// ReadOperation is the class of GET and HEAD operations which are not
+	// listing operations, such as GetObject and HeadObject.
+	ReadOperation OperationClass = iota
+
+	// ListOperation is the class of GET operations listing resources, whose
+	// name starts with "List", or which are paginated, such as ListObjects.
+	ListOperation
+
+	// WriteOperation is the class of every other operation, such as
+	// PutObject, DeleteObject, and CreateMultipartUpload.
+	WriteOperation
+)
+
+// ClassifyOperation returns the OperationClass of an API operation.
+func ClassifyOperation(op *request.Operation) OperationClass {
+	switch op.HTTPMethod {
+	case "GET":
+		if strings.HasPrefix(op.Name, "List") || op.Paginator != nil {
+			return ListOperation
+		}
+		return ReadOperation
+	case "HEAD":
+		return ReadOperation
+	default:
+		return WriteOperation
+	}
+}
+
+// ClassRetryer is a request.Retryer retrying reads, listings, and writes
+// with different retryers, such as retrying reads aggressively, and writes
+// conservatively. The requests of each class are retried with the class's
+// retryer, or with the embedded default Retryer if the class has none.
+//
+//     retryer := client.ClassRetryer{
+//         Retryer: client.DefaultRetryer{NumMaxRetries: 3},
+//         Read:    client.DefaultRetryer{NumMaxRetries: 10},
+//         Write:   client.DefaultRetryer{NumMaxRetries: 1},
+//     }
+//     svc := s3.New(sess, request.WithRetryer(aws.NewConfig(), retryer))
+type ClassRetryer struct {
+	// The default Retryer of operations whose class has no retryer. Must be
+	// set.
+	request.Retryer
+
+	// The Retryers of ReadOperation, ListOperation, and WriteOperation
+	// requests.
+	Read  request.Retryer
+	List  request.Retryer
+	Write request.Retryer
+}
+
+// RetryerForOperation returns the Retryer of the operation's class.
+func (c ClassRetryer) RetryerForOperation(op *request.Operation) request.Retryer {
+	var retryer request.Retryer
+	switch ClassifyOperation(op) {
+	case ReadOperation:
+		retryer = c.Read
+	case ListOperation:
+		retryer = c.List
+	case WriteOperation:
+		retryer = c.Write
+	}
+
+	if retryer == nil {
+		return c.Retryer
+	}
+	return retryer
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+func TestClassifyOperation(t *testing.T) {
+	cases := []struct {
+		op     *request.Operation
+		expect OperationClass
+	}{
+		{&request.Operation{Name: "GetObject", HTTPMethod: "GET"}, ReadOperation},
+		{&request.Operation{Name: "HeadObject", HTTPMethod: "HEAD"}, ReadOperation},
+		{&request.Operation{Name: "ListBuckets", HTTPMethod: "GET"}, ListOperation},
+		{&request.Operation{Name: "GetPages", HTTPMethod: "GET", Paginator: &request.Paginator{}}, ListOperation},
+		{&request.Operation{Name: "PutObject", HTTPMethod: "PUT"}, WriteOperation},
+		{&request.Operation{Name: "CreateMultipartUpload", HTTPMethod: "POST"}, WriteOperation},
+	}
+
+	for i, c := range cases {
+		if e, a := c.expect, ClassifyOperation(c.op); e != a {
+			t.Errorf("%d, expect %v class for %s, got %v", i, e, c.op.Name, a)
+		}
+	}
+}
+
+func TestClassRetryer(t *testing.T) {
+	retryer := ClassRetryer{
+		Retryer: DefaultRetryer{NumMaxRetries: 3},
+		Read:    DefaultRetryer{NumMaxRetries: 10},
+		Write:   DefaultRetryer{NumMaxRetries: 1},
+	}
+	c := New(aws.Config{Retryer: retryer}, metadata.ClientInfo{}, request.Handlers{})
+
+	cases := []struct {
+		op     *request.Operation
+		expect int
+	}{
+		{&request.Operation{Name: "GetObject", HTTPMethod: "GET"}, 10},
+		{&request.Operation{Name: "ListObjects", HTTPMethod: "GET"}, 3},
+		{&request.Operation{Name: "PutObject", HTTPMethod: "PUT"}, 1},
+	}
+
+	for i, tc := range cases {
+		r := c.NewRequest(tc.op, nil, nil)
+		if e, a := tc.expect, r.MaxRetries(); e != a {
+			t.Errorf("%d, expect %v max retries for %s, got %v", i, e, tc.op.Name, a)
+		}
+	}
+
+	if e, a := 3, c.MaxRetries(); e != a {
+		t.Errorf("expect %v client max retries, got %v", e, a)
+	}
+}
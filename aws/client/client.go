@@ -75,7 +75,9 @@ func New(cfg aws.Config, info metadata.ClientInfo, handlers request.Handlers, op
 // NewRequest returns a new Request pointer for the service API
 // operation and parameters.
 func (c *Client) NewRequest(operation *request.Operation, params interface{}, data interface{}) *request.Request {
-	return request.New(c.Config, c.ClientInfo, c.Handlers, c.Retryer, operation, params, data)
+	// The handlers are copied, instead of passed by value, as they may be
+	// modified concurrently.
+	return request.New(c.Config, c.ClientInfo, c.Handlers.Copy(), c.Retryer, operation, params, data)
 }
 
 // AddDebugHandlers injects debug logging handlers into the service to log request
@@ -76,3 +76,28 @@ func TestNewClient_CopyHandlers(t *testing.T) {
 	}
 
 }
+
+func TestNewRequest_ConcurrentHandlerModification(t *testing.T) {
+	c := New(aws.Config{}, metadata.ClientInfo{}, request.Handlers{})
+	c.Handlers.Send.PushBackNamed(request.NamedHandler{Name: "send", Fn: func(*request.Request) {}})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			c.Handlers.Send.PushBack(func(*request.Request) {})
+			c.Handlers.Send.SwapNamed(request.NamedHandler{Name: "send", Fn: func(*request.Request) {}})
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		r := c.NewRequest(&request.Operation{Name: "Operation"}, nil, nil)
+		r.Handlers.Send.PushBack(func(*request.Request) {})
+		r.Handlers.Send.Run(r)
+	}
+	<-done
+
+	if e, a := 101, c.Handlers.Send.Len(); e != a {
+		t.Errorf("expect %d handlers, got %d", e, a)
+	}
+}
@@ -22,6 +22,12 @@ import (
 //    func (d retryer) MaxRetries() int { return 100 }
 type DefaultRetryer struct {
 	NumMaxRetries int
+
+	// MaxRetryAfter is the longest Retry-After delay of throttled 429 and 503
+	// responses honored. If greater than 0, retries of these responses are
+	// delayed by at least their Retry-After delay, shortened to
+	// MaxRetryAfter. The Retry-After header is ignored if not set.
+	MaxRetryAfter time.Duration
 }
 
 // MaxRetries returns the number of maximum returns the service will use to make
@@ -48,8 +54,31 @@ func (d DefaultRetryer) RetryRules(r *request.Request) time.Duration {
 		retryCount = 8
 	}
 
-	delay := (1 << uint(retryCount)) * (seededRand.Intn(minTime) + minTime)
-	return time.Duration(delay) * time.Millisecond
+	delay := time.Duration((1<<uint(retryCount))*(seededRand.Intn(minTime)+minTime)) * time.Millisecond
+	if retryAfter, ok := d.retryAfter(r); ok && retryAfter > delay {
+		delay = retryAfter
+	}
+	return delay
+}
+
+// retryAfter returns the Retry-After delay of throttled 429 and 503
+// responses, shortened to MaxRetryAfter, if MaxRetryAfter is set.
+func (d DefaultRetryer) retryAfter(r *request.Request) (time.Duration, bool) {
+	if d.MaxRetryAfter <= 0 || r.HTTPResponse == nil {
+		return 0, false
+	}
+	if code := r.HTTPResponse.StatusCode; code != 429 && code != 503 {
+		return 0, false
+	}
+
+	delay, ok := r.RetryAfter()
+	if !ok {
+		return 0, false
+	}
+	if delay > d.MaxRetryAfter {
+		delay = d.MaxRetryAfter
+	}
+	return delay, true
 }
 
 // ShouldRetry returns true if the request should be retried.
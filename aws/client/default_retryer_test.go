@@ -0,0 +1,39 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+func TestDefaultRetryerRetryAfter(t *testing.T) {
+	cases := map[string]struct {
+		MaxRetryAfter time.Duration
+		Status        int
+		Header        string
+		Min, Max      time.Duration
+	}{
+		"not set":      {0, 503, "3", 500 * time.Millisecond, 1000 * time.Millisecond},
+		"throttled":    {time.Minute, 503, "3", 3 * time.Second, 3 * time.Second},
+		"too many":     {time.Minute, 429, "3", 3 * time.Second, 3 * time.Second},
+		"capped":       {2 * time.Second, 503, "3600", 2 * time.Second, 2 * time.Second},
+		"date":         {time.Minute, 503, time.Now().Add(5 * time.Second).UTC().Format(http.TimeFormat), 3 * time.Second, 5 * time.Second},
+		"no header":    {time.Minute, 503, "", 500 * time.Millisecond, 1000 * time.Millisecond},
+		"not throttle": {time.Minute, 500, "3", 30 * time.Millisecond, 60 * time.Millisecond},
+	}
+
+	for name, c := range cases {
+		resp := &http.Response{StatusCode: c.Status, Header: http.Header{}}
+		if len(c.Header) != 0 {
+			resp.Header.Set("Retry-After", c.Header)
+		}
+
+		d := DefaultRetryer{MaxRetryAfter: c.MaxRetryAfter}
+		delay := d.RetryRules(&request.Request{HTTPResponse: resp})
+		if delay < c.Min || delay > c.Max {
+			t.Errorf("%s, expect delay between %v and %v, got %v", name, c.Min, c.Max, delay)
+		}
+	}
+}
@@ -11,12 +11,12 @@ import (
 	"github.com/aws/aws-sdk-go/aws/request"
 )
 
-const logReqMsg = `DEBUG: Request %s/%s Details:
+const logReqMsg = `DEBUG: Request %s/%s Details:%s
 ---[ REQUEST POST-SIGN ]-----------------------------
 %s
 -----------------------------------------------------`
 
-const logReqErrMsg = `DEBUG ERROR: Request %s/%s:
+const logReqErrMsg = `DEBUG ERROR: Request %s/%s:%s
 ---[ REQUEST DUMP ERROR ]-----------------------------
 %s
 ------------------------------------------------------`
@@ -48,7 +48,7 @@ func logRequest(r *request.Request) {
 	logBody := r.Config.LogLevel.Matches(aws.LogDebugWithHTTPBody)
 	dumpedBody, err := httputil.DumpRequestOut(r.HTTPRequest, logBody)
 	if err != nil {
-		r.Config.Logger.Log(fmt.Sprintf(logReqErrMsg, r.ClientInfo.ServiceName, r.Operation.Name, err))
+		r.Config.Logger.Log(fmt.Sprintf(logReqErrMsg, r.ClientInfo.ServiceName, r.Operation.Name, logMetadata(r), err))
 		return
 	}
 
@@ -59,15 +59,15 @@ func logRequest(r *request.Request) {
 		r.ResetBody()
 	}
 
-	r.Config.Logger.Log(fmt.Sprintf(logReqMsg, r.ClientInfo.ServiceName, r.Operation.Name, string(dumpedBody)))
+	r.Config.Logger.Log(fmt.Sprintf(logReqMsg, r.ClientInfo.ServiceName, r.Operation.Name, logMetadata(r), string(dumpedBody)))
 }
 
-const logRespMsg = `DEBUG: Response %s/%s Details:
+const logRespMsg = `DEBUG: Response %s/%s Details:%s
 ---[ RESPONSE ]--------------------------------------
 %s
 -----------------------------------------------------`
 
-const logRespErrMsg = `DEBUG ERROR: Response %s/%s:
+const logRespErrMsg = `DEBUG ERROR: Response %s/%s:%s
 ---[ RESPONSE DUMP ERROR ]-----------------------------
 %s
 -----------------------------------------------------`
@@ -82,16 +82,16 @@ func logResponse(r *request.Request) {
 	handlerFn := func(req *request.Request) {
 		body, err := httputil.DumpResponse(req.HTTPResponse, false)
 		if err != nil {
-			lw.Logger.Log(fmt.Sprintf(logRespErrMsg, req.ClientInfo.ServiceName, req.Operation.Name, err))
+			lw.Logger.Log(fmt.Sprintf(logRespErrMsg, req.ClientInfo.ServiceName, req.Operation.Name, logMetadata(req), err))
 			return
 		}
 
 		b, err := ioutil.ReadAll(lw.buf)
 		if err != nil {
-			lw.Logger.Log(fmt.Sprintf(logRespErrMsg, req.ClientInfo.ServiceName, req.Operation.Name, err))
+			lw.Logger.Log(fmt.Sprintf(logRespErrMsg, req.ClientInfo.ServiceName, req.Operation.Name, logMetadata(req), err))
 			return
 		}
-		lw.Logger.Log(fmt.Sprintf(logRespMsg, req.ClientInfo.ServiceName, req.Operation.Name, string(body)))
+		lw.Logger.Log(fmt.Sprintf(logRespMsg, req.ClientInfo.ServiceName, req.Operation.Name, logMetadata(req), string(body)))
 		if req.Config.LogLevel.Matches(aws.LogDebugWithHTTPBody) {
 			lw.Logger.Log(string(b))
 		}
@@ -106,3 +106,12 @@ func logResponse(r *request.Request) {
 		Name: handlerName, Fn: handlerFn,
 	})
 }
+
+// logMetadata returns the request's metadata formatted for the first line
+// of log messages, or an empty string if the request has no metadata.
+func logMetadata(r *request.Request) string {
+	if len(r.Metadata()) == 0 {
+		return ""
+	}
+	return " [" + request.FormatMetadata(r.Metadata()) + "]"
+}
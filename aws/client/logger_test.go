@@ -2,8 +2,14 @@ package client
 
 import (
 	"bytes"
+	"fmt"
 	"io"
+	"strings"
 	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
 )
 
 type mockCloser struct {
@@ -55,3 +61,25 @@ func TestLogWriter(t *testing.T) {
 		t.Errorf("Expected %q, but received %q", expected, lw.buf.String())
 	}
 }
+
+func TestLogRequestMetadata(t *testing.T) {
+	var logs []string
+	cfg := aws.Config{
+		LogLevel: aws.LogLevel(aws.LogDebug),
+		Logger: aws.LoggerFunc(func(args ...interface{}) {
+			logs = append(logs, fmt.Sprint(args...))
+		}),
+	}
+	c := New(cfg, metadata.ClientInfo{ServiceName: "service", Endpoint: "https://example.com"}, request.Handlers{})
+
+	r := c.NewRequest(&request.Operation{Name: "Operation"}, nil, nil)
+	r.ApplyOptions(request.WithMetadata(map[string]string{"tenant": "acme", "job": "42"}))
+	logRequest(r)
+
+	if e, a := 1, len(logs); e != a {
+		t.Fatalf("expect %v logs, got %v", e, a)
+	}
+	if e, a := "DEBUG: Request service/Operation Details: [job=42 tenant=acme]\n", logs[0]; !strings.HasPrefix(a, e) {
+		t.Errorf("expect %q log prefix, got %q", e, a)
+	}
+}
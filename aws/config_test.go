@@ -84,3 +84,17 @@ func TestMerge(t *testing.T) {
 		}
 	}
 }
+
+func TestMergeHeaders(t *testing.T) {
+	session := (&Config{}).WithHeader("X-Gateway-Route", "cos").WithHeader("x-tenant", "acme")
+	client := session.Copy(&Config{Headers: http.Header{"X-Tenant": []string{"other"}}})
+
+	expect := http.Header{"X-Gateway-Route": []string{"cos"}, "X-Tenant": []string{"other"}}
+	if e, a := expect, client.Headers; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	expect = http.Header{"X-Gateway-Route": []string{"cos"}, "X-Tenant": []string{"acme"}}
+	if e, a := expect, session.Headers; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect session headers not modified, %v, got %v", e, a)
+	}
+}
@@ -297,6 +297,20 @@ func Float64ValueMap(src map[string]*float64) map[string]float64 {
 	return dst
 }
 
+// Duration returns a pointer to the time.Duration value passed in.
+func Duration(v time.Duration) *time.Duration {
+	return &v
+}
+
+// DurationValue returns the value of the time.Duration pointer passed in or
+// 0 if the pointer is nil.
+func DurationValue(v *time.Duration) time.Duration {
+	if v != nil {
+		return *v
+	}
+	return 0
+}
+
 // Time returns a pointer to the time.Time value passed in.
 func Time(v time.Time) *time.Time {
 	return &v
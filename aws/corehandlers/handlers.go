@@ -205,6 +205,11 @@ var AfterRetryHandler = request.NamedHandler{Name: "core.AfterRetryHandler", Fn:
 		r.Retryable = aws.Bool(r.ShouldRetry(r))
 	}
 
+	// Retries are not made once the retry budget, if any, is exhausted.
+	if r.WillRetry() && r.Config.RetryBudget != nil && !r.Config.RetryBudget.AllowRetry() {
+		r.Retryable = aws.Bool(false)
+	}
+
 	if r.WillRetry() {
 		r.RetryDelay = r.RetryRules(r)
 
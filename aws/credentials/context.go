@@ -0,0 +1,28 @@
+package credentials
+
+import "time"
+
+// Context is a copy of the aws.Context interface, the interface of the Go
+// v1.7 stdlib's context.Context. It is duplicated as the credentials package
+// cannot import the aws package. Values of either type can be used as the
+// other.
+//
+// See https://golang.org/pkg/context on how to use contexts.
+type Context interface {
+	// Deadline returns the time when work done on behalf of this context
+	// should be canceled. Deadline returns ok==false when no deadline is
+	// set.
+	Deadline() (deadline time.Time, ok bool)
+
+	// Done returns a channel that's closed when work done on behalf of this
+	// context should be canceled. Done may return nil if this context can
+	// never be canceled.
+	Done() <-chan struct{}
+
+	// Err returns a non-nil error value after Done is closed.
+	Err() error
+
+	// Value returns the value associated with this context for key, or nil
+	// if no value is associated with key.
+	Value(key interface{}) interface{}
+}
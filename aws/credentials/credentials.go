@@ -50,6 +50,7 @@ package credentials
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -102,6 +103,18 @@ type Provider interface {
 	IsExpired() bool
 }
 
+// A ProviderWithContext is a Provider which can retrieve credentials with a
+// context, such as the context of the request the credentials are retrieved
+// to sign. The provider's requests are canceled with the context, and bounded
+// by its deadline.
+type ProviderWithContext interface {
+	Provider
+
+	// RetrieveWithContext returns the credentials value the same as
+	// Retrieve, canceling the retrieval if the context is canceled.
+	RetrieveWithContext(Context) (Value, error)
+}
+
 // An ErrorProvider is a stub credentials provider that always returns an error
 // this is used by the SDK when construction a known provider is not possible
 // due to an error.
@@ -161,10 +174,52 @@ func (e *Expiry) SetExpiration(expiration time.Time, window time.Duration) {
 
 // IsExpired returns if the credentials are expired.
 func (e *Expiry) IsExpired() bool {
+	return e.expiration.Before(e.clock()())
+}
+
+// ExpiresAt returns the expiration time of the credentials, reduced by the
+// expiry window, set by SetExpiration.
+func (e *Expiry) ExpiresAt() time.Time {
+	return e.expiration
+}
+
+// clock returns the function IsExpired gets the current time with.
+func (e *Expiry) clock() func() time.Time {
 	if e.CurrentTime == nil {
-		e.CurrentTime = time.Now
+		return time.Now
 	}
-	return e.expiration.Before(e.CurrentTime())
+	return e.CurrentTime
+}
+
+// An AsyncRefreshProvider is a Provider opting in to Credentials returning its
+// cached credentials without locking, and refreshing them in the background
+// before they expire. Embedding Expiry does not implement the interface,
+// Providers must implement RefreshTimes to opt in.
+//
+// While the credentials of an AsyncRefreshProvider are cached, the
+// Credentials' Get and IsExpired do not call the Provider's IsExpired. The
+// credentials are expired at the expiresAt time returned by RefreshTimes
+// instead.
+type AsyncRefreshProvider interface {
+	Provider
+
+	// RefreshTimes returns the times of the credentials last retrieved. The
+	// first call to Get after refreshAt refreshes the credentials in the
+	// background, and returns the cached credentials. After expiresAt the
+	// cached credentials are no longer returned, and calls to Get wait for
+	// the credentials to be retrieved.
+	RefreshTimes() (refreshAt, expiresAt time.Time)
+}
+
+// asyncRefreshRetryDelay is the delay before the credentials of an
+// AsyncRefreshProvider are refreshed in the background again, after a
+// background refresh failed.
+const asyncRefreshRetryDelay = 10 * time.Second
+
+// expiryClock is implemented by the Providers embedding Expiry, whose
+// expiration is compared to the time of their CurrentTime.
+type expiryClock interface {
+	clock() func() time.Time
 }
 
 // A Credentials provides synchronous safe retrieval of AWS credentials Value.
@@ -178,11 +233,23 @@ func (e *Expiry) IsExpired() bool {
 // The first Credentials.Get() will always call Provider.Retrieve() to get the
 // first instance of the credentials Value. All calls to Get() after that
 // will return the cached credentials Value until IsExpired() returns true.
+//
+// If the Provider is an AsyncRefreshProvider, the cached credentials Value is
+// returned without locking, and refreshed in the background, until the
+// credentials expire. See AsyncRefreshProvider.
 type Credentials struct {
 	creds        Value
 	forceRefresh bool
 	m            sync.Mutex
 
+	// cached is the *cachedValue of an AsyncRefreshProvider's credentials,
+	// read by Get without locking.
+	cached atomic.Value
+
+	// refreshing is set while the credentials are refreshed in the
+	// background.
+	refreshing int32
+
 	provider        Provider
 	credentialsType string
 }
@@ -214,21 +281,126 @@ func NewTypedCredentials(provider Provider, credentialsType string) *Credentials
 // If Credentials.Expire() was called the credentials Value will be force
 // expired, and the next call to Get() will cause them to be refreshed.
 func (c *Credentials) Get() (Value, error) {
+	return c.get(nil)
+}
+
+// GetWithContext returns the credentials value the same as Get, with the
+// addition of the ability to pass a context. If the credentials need to be
+// refreshed, and the Provider is a ProviderWithContext, the credentials are
+// retrieved with the context, canceling the retrieval if the context is
+// canceled. Otherwise the context is ignored.
+//
+// The context must be non-nil. Calls waiting for another call's retrieval of
+// the credentials to complete are not canceled by their context.
+func (c *Credentials) GetWithContext(ctx Context) (Value, error) {
+	return c.get(ctx)
+}
+
+// get returns the credentials value, retrieving the credentials with the
+// context if set and supported by the Provider.
+func (c *Credentials) get(ctx Context) (Value, error) {
+	if v, ok := c.cachedCreds(); ok {
+		if !c.now().Before(v.refreshAt) {
+			c.refreshAsync()
+		}
+		return v.creds, nil
+	}
+
 	c.m.Lock()
 	defer c.m.Unlock()
 
 	if c.isExpired() {
-		creds, err := c.provider.Retrieve()
+		var creds Value
+		var err error
+		if p, ok := c.provider.(ProviderWithContext); ok && ctx != nil {
+			creds, err = p.RetrieveWithContext(ctx)
+		} else {
+			creds, err = c.provider.Retrieve()
+		}
 		if err != nil {
 			return Value{}, err
 		}
 		c.creds = creds
 		c.forceRefresh = false
+		c.cacheValue()
 	}
 
 	return c.creds, nil
 }
 
+// cachedValue is the credentials Value of an AsyncRefreshProvider, with the
+// times it is refreshed and expires at.
+type cachedValue struct {
+	creds     Value
+	refreshAt time.Time
+	expiresAt time.Time
+}
+
+// cacheValue caches the credentials of the Provider for Get, if the Provider
+// is an AsyncRefreshProvider. Must be called with the lock held.
+func (c *Credentials) cacheValue() {
+	p, ok := c.provider.(AsyncRefreshProvider)
+	if !ok {
+		return
+	}
+
+	refreshAt, expiresAt := p.RefreshTimes()
+	c.cached.Store(&cachedValue{creds: c.creds, refreshAt: refreshAt, expiresAt: expiresAt})
+}
+
+// refreshAsync refreshes the cached credentials in the background, unless
+// they are already being refreshed. The cached credentials continue to be
+// returned by Get until they expire. If the refresh fails it is retried
+// after asyncRefreshRetryDelay.
+func (c *Credentials) refreshAsync() {
+	if !atomic.CompareAndSwapInt32(&c.refreshing, 0, 1) {
+		return
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&c.refreshing, 0)
+
+		c.m.Lock()
+		defer c.m.Unlock()
+
+		// The credentials may have been retrieved, or expired, since the
+		// refresh was started.
+		v, ok := c.cachedCreds()
+		if !ok || c.now().Before(v.refreshAt) {
+			return
+		}
+
+		creds, err := c.provider.Retrieve()
+		if err != nil {
+			retry := *v
+			retry.refreshAt = c.now().Add(asyncRefreshRetryDelay)
+			c.cached.Store(&retry)
+			return
+		}
+		c.creds = creds
+		c.forceRefresh = false
+		c.cacheValue()
+	}()
+}
+
+// cachedCreds returns the cached credentials, if they are cached and have
+// not expired.
+func (c *Credentials) cachedCreds() (*cachedValue, bool) {
+	v, _ := c.cached.Load().(*cachedValue)
+	if v == nil || !c.now().Before(v.expiresAt) {
+		return nil, false
+	}
+	return v, true
+}
+
+// now returns the current time of the Provider, if it embeds Expiry.
+func (c *Credentials) now() time.Time {
+	if e, ok := c.provider.(expiryClock); ok {
+		return e.clock()()
+	}
+	return time.Now()
+}
+
 // Expire expires the credentials and forces them to be retrieved on the
 // next call to Get().
 //
@@ -239,6 +411,7 @@ func (c *Credentials) Expire() {
 	defer c.m.Unlock()
 
 	c.forceRefresh = true
+	c.cached.Store((*cachedValue)(nil))
 }
 
 // IsExpired returns if the credentials are no longer valid, and need
@@ -247,6 +420,10 @@ func (c *Credentials) Expire() {
 // If the Credentials were forced to be expired with Expire() this will
 // reflect that override.
 func (c *Credentials) IsExpired() bool {
+	if _, ok := c.cachedCreds(); ok {
+		return false
+	}
+
 	c.m.Lock()
 	defer c.m.Unlock()
 
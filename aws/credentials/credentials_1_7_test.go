@@ -0,0 +1,31 @@
+// +build go1.7
+
+package credentials
+
+import "testing"
+
+func BenchmarkCredentials_Get(b *testing.B) {
+	// The AsyncRefreshProvider's credentials are returned without locking,
+	// while the credentials of the Expiry provider are returned with the
+	// lock held.
+	cases := map[string]Provider{
+		"AsyncRefreshProvider": &stubAsyncRefreshProvider{},
+		"Expiry":               &stubExpiryProvider{},
+	}
+
+	for name, p := range cases {
+		b.Run(name, func(b *testing.B) {
+			c := NewCredentials(p)
+			if _, err := c.Get(); err != nil {
+				b.Fatalf("expect no error, got %v", err)
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					c.Get()
+				}
+			})
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package credentials
 
 import (
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/stretchr/testify/assert"
@@ -71,3 +74,230 @@ func TestCredentialsGetWithProviderName(t *testing.T) {
 	assert.Nil(t, err, "Expected no error")
 	assert.Equal(t, creds.ProviderName, "stubProvider", "Expected provider name to match")
 }
+
+type stubContext struct {
+	value string
+}
+
+func (c stubContext) Deadline() (time.Time, bool)       { return time.Time{}, false }
+func (c stubContext) Done() <-chan struct{}             { return nil }
+func (c stubContext) Err() error                        { return nil }
+func (c stubContext) Value(key interface{}) interface{} { return c.value }
+
+type stubContextProvider struct {
+	stubProvider
+	ctx Context
+}
+
+func (s *stubContextProvider) RetrieveWithContext(ctx Context) (Value, error) {
+	s.ctx = ctx
+	return s.Retrieve()
+}
+
+func TestCredentialsGetWithContext(t *testing.T) {
+	stub := &stubContextProvider{stubProvider: stubProvider{
+		creds:   Value{AccessKeyID: "AKID"},
+		expired: true,
+	}}
+	c := NewCredentials(stub)
+
+	creds, err := c.GetWithContext(stubContext{value: "ctx"})
+	assert.Nil(t, err, "Expected no error")
+	assert.Equal(t, "AKID", creds.AccessKeyID, "Expect access key ID to match")
+	assert.Equal(t, "ctx", stub.ctx.Value(nil), "Expect provider to retrieve with the context")
+
+	stub.ctx = nil
+	c.Expire()
+	_, err = c.Get()
+	assert.Nil(t, err, "Expected no error")
+	assert.Nil(t, stub.ctx, "Expect provider to retrieve without a context")
+}
+
+func TestCredentialsGetWithContext_Provider(t *testing.T) {
+	c := NewCredentials(&stubProvider{creds: Value{AccessKeyID: "AKID"}, expired: true})
+
+	creds, err := c.GetWithContext(stubContext{})
+	assert.Nil(t, err, "Expected no error")
+	assert.Equal(t, "AKID", creds.AccessKeyID, "Expect access key ID to match")
+}
+
+type stubExpiryProvider struct {
+	Expiry
+	retrieved int32
+	isExpired int32
+	failing   int32
+}
+
+func (s *stubExpiryProvider) Retrieve() (Value, error) {
+	atomic.AddInt32(&s.retrieved, 1)
+	if atomic.LoadInt32(&s.failing) != 0 {
+		return Value{}, awserr.New("stubError", "failed to retrieve", nil)
+	}
+	s.SetExpiration(s.clock()().Add(time.Hour), 0)
+	return Value{AccessKeyID: "AKID", ProviderName: "stubExpiryProvider"}, nil
+}
+
+func (s *stubExpiryProvider) IsExpired() bool {
+	atomic.AddInt32(&s.isExpired, 1)
+	return s.Expiry.IsExpired()
+}
+
+type stubAsyncRefreshProvider struct {
+	stubExpiryProvider
+	window time.Duration
+}
+
+func (s *stubAsyncRefreshProvider) RefreshTimes() (time.Time, time.Time) {
+	return s.ExpiresAt().Add(-s.window), s.ExpiresAt()
+}
+
+type stubClock struct {
+	m   sync.Mutex
+	now time.Time
+}
+
+func (c *stubClock) Now() time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.now
+}
+
+func (c *stubClock) Add(d time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func waitRetrieved(t *testing.T, s *stubExpiryProvider, n int32) {
+	for i := 0; i < 100 && atomic.LoadInt32(&s.retrieved) < n; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if e, a := n, atomic.LoadInt32(&s.retrieved); e != a {
+		t.Fatalf("expect %v retrievals, got %v", e, a)
+	}
+}
+
+func TestCredentialsGet_Expiry(t *testing.T) {
+	// Providers embedding Expiry without opting in to the lock-free cache
+	// have their IsExpired called.
+	stub := &stubExpiryProvider{}
+	c := NewCredentials(stub)
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Get(); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+	if e, a := int32(1), stub.retrieved; e != a {
+		t.Errorf("expect %v retrievals, got %v", e, a)
+	}
+	if e, a := int32(2), stub.isExpired; e != a {
+		t.Errorf("expect %v IsExpired calls, got %v", e, a)
+	}
+}
+
+func TestCredentialsGet_AsyncRefreshProvider(t *testing.T) {
+	clock := &stubClock{now: time.Now()}
+	stub := &stubAsyncRefreshProvider{}
+	stub.CurrentTime = clock.Now
+	c := NewCredentials(stub)
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Get(); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+	if e, a := int32(1), stub.retrieved; e != a {
+		t.Errorf("expect %v retrievals, got %v", e, a)
+	}
+	if e, a := int32(0), stub.isExpired; e != a {
+		t.Errorf("expect %v IsExpired calls, got %v", e, a)
+	}
+	if c.IsExpired() {
+		t.Errorf("expect credentials not to be expired")
+	}
+
+	c.Expire()
+	if !c.IsExpired() {
+		t.Errorf("expect credentials to be expired")
+	}
+	c.Get()
+	if e, a := int32(2), stub.retrieved; e != a {
+		t.Errorf("expect %v retrievals, got %v", e, a)
+	}
+
+	clock.Add(2 * time.Hour)
+	if !c.IsExpired() {
+		t.Errorf("expect credentials to be expired")
+	}
+	c.Get()
+	if e, a := int32(3), stub.retrieved; e != a {
+		t.Errorf("expect %v retrievals, got %v", e, a)
+	}
+}
+
+func TestCredentialsGet_AsyncRefresh(t *testing.T) {
+	clock := &stubClock{now: time.Now()}
+	stub := &stubAsyncRefreshProvider{window: 30 * time.Minute}
+	stub.CurrentTime = clock.Now
+	c := NewCredentials(stub)
+
+	if _, err := c.Get(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// Within the refresh window the cached credentials are returned, and
+	// refreshed in the background.
+	clock.Add(45 * time.Minute)
+	if _, err := c.Get(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	waitRetrieved(t, &stub.stubExpiryProvider, 2)
+
+	// A failed background refresh keeps the cached credentials, and is
+	// retried after a delay.
+	atomic.StoreInt32(&stub.failing, 1)
+	clock.Add(45 * time.Minute)
+	if _, err := c.Get(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	waitRetrieved(t, &stub.stubExpiryProvider, 3)
+
+	if _, err := c.Get(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if e, a := int32(3), atomic.LoadInt32(&stub.retrieved); e != a {
+		t.Errorf("expect %v retrievals, got %v", e, a)
+	}
+
+	atomic.StoreInt32(&stub.failing, 0)
+	clock.Add(asyncRefreshRetryDelay)
+	if _, err := c.Get(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	waitRetrieved(t, &stub.stubExpiryProvider, 4)
+}
+
+func TestCredentialsGet_AsyncRefreshConcurrent(t *testing.T) {
+	stub := &stubAsyncRefreshProvider{}
+	c := NewCredentials(stub)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := c.Get(); err != nil {
+					t.Errorf("expect no error, got %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if e, a := int32(1), atomic.LoadInt32(&stub.retrieved); e != a {
+		t.Errorf("expect %v retrievals, got %v", e, a)
+	}
+}
@@ -0,0 +1,98 @@
+// Package credentialstest provides a mock credentials.Provider for unit
+// testing code which uses the SDK's credentials, such as code which must
+// handle credential refresh failures, or IBM IAM tokens expiring.
+//
+//     provider := &credentialstest.MockProvider{
+//         Value: credentials.Value{
+//             SessionToken:      "token",
+//             ServiceInstanceID: "instanceID",
+//         },
+//     }
+//     creds := credentials.NewTypedCredentials(provider, "ibm-iam")
+//
+//     // Force the next Get to retrieve new credentials.
+//     provider.Expire()
+package credentialstest
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// MockProviderName is the name of the MockProvider.
+const MockProviderName = "MockProvider"
+
+// MockProvider is a credentials.Provider which returns the configured Value,
+// or error, and records how many times it was retrieved. The MockProvider is
+// expired until it has been successfully retrieved, and after Expire is
+// called.
+//
+// The MockProvider is safe to use concurrently.
+type MockProvider struct {
+	// Value is the credentials value returned by Retrieve.
+	Value credentials.Value
+
+	// Err is the error returned by Retrieve if set.
+	Err error
+
+	// RetrieveFn if set will be called by Retrieve instead of returning
+	// Value and Err.
+	RetrieveFn func() (credentials.Value, error)
+
+	m         sync.Mutex
+	retrieved bool
+	count     int
+}
+
+var _ credentials.Provider = (*MockProvider)(nil)
+
+// Retrieve returns the MockProvider's credentials value, or error.
+func (p *MockProvider) Retrieve() (credentials.Value, error) {
+	p.m.Lock()
+	p.count++
+	fn, v, err := p.RetrieveFn, p.Value, p.Err
+	p.m.Unlock()
+
+	if fn != nil {
+		v, err = fn()
+	}
+	if len(v.ProviderName) == 0 {
+		v.ProviderName = MockProviderName
+	}
+	if err != nil {
+		return credentials.Value{ProviderName: v.ProviderName}, err
+	}
+
+	p.m.Lock()
+	p.retrieved = true
+	p.m.Unlock()
+
+	return v, nil
+}
+
+// IsExpired returns if the MockProvider has not been retrieved successfully
+// since it was created, or last expired.
+func (p *MockProvider) IsExpired() bool {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return !p.retrieved
+}
+
+// Expire marks the MockProvider as expired, so that credentials using it will
+// retrieve new values.
+func (p *MockProvider) Expire() {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.retrieved = false
+}
+
+// RetrieveCount returns the number of times Retrieve has been called.
+func (p *MockProvider) RetrieveCount() int {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return p.count
+}
@@ -0,0 +1,59 @@
+package credentialstest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/credentialstest"
+)
+
+func TestMockProvider(t *testing.T) {
+	provider := &credentialstest.MockProvider{
+		Value: credentials.Value{SessionToken: "token", ServiceInstanceID: "instanceID"},
+	}
+	creds := credentials.NewTypedCredentials(provider, "ibm-iam")
+
+	for i := 0; i < 2; i++ {
+		v, err := creds.Get()
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if e, a := "token", v.SessionToken; e != a {
+			t.Errorf("expect %v token, got %v", e, a)
+		}
+		if e, a := credentialstest.MockProviderName, v.ProviderName; e != a {
+			t.Errorf("expect %v provider name, got %v", e, a)
+		}
+	}
+	if e, a := 1, provider.RetrieveCount(); e != a {
+		t.Errorf("expect %v retrieves, got %v", e, a)
+	}
+
+	provider.Expire()
+	if !creds.IsExpired() {
+		t.Errorf("expect credentials to be expired")
+	}
+	if _, err := creds.Get(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 2, provider.RetrieveCount(); e != a {
+		t.Errorf("expect %v retrieves, got %v", e, a)
+	}
+}
+
+func TestMockProvider_Error(t *testing.T) {
+	provider := &credentialstest.MockProvider{
+		RetrieveFn: func() (credentials.Value, error) {
+			return credentials.Value{}, fmt.Errorf("refresh failed")
+		},
+	}
+	creds := credentials.NewCredentials(provider)
+
+	if _, err := creds.Get(); err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if !provider.IsExpired() {
+		t.Errorf("expect provider to remain expired after failed retrieve")
+	}
+}
@@ -0,0 +1,143 @@
+package credentials
+
+import (
+	"os"
+	"time"
+)
+
+// DefaultFileWatchPollInterval is the PollInterval of the FileWatchProvider
+// of NewWatchedSharedCredentials.
+const DefaultFileWatchPollInterval = 30 * time.Second
+
+// A FileWatchProvider wraps a Provider whose credentials are read from files,
+// such as the SharedCredentialsProvider, and expires its credentials when one
+// of the files changes. Long-lived processes reload credentials rotated in
+// the files without restarting, the next time the credentials are used.
+//
+// A file is changed if its modification time or size changes, or if it is
+// created or removed. The files are checked at most once per PollInterval,
+// when the expiry of the credentials is checked.
+//
+//     creds := credentials.NewCredentials(&credentials.FileWatchProvider{
+//         Provider:     &credentials.SharedCredentialsProvider{Filename: filename},
+//         Filenames:    []string{filename},
+//         PollInterval: time.Minute,
+//     })
+//
+// FileWatchProvider does not provide any synchronization and it is not safe
+// to share this value across multiple Credentials, Sessions, or service
+// clients without also sharing the same Credentials instance.
+type FileWatchProvider struct {
+	// Provider the credentials are retrieved from.
+	Provider Provider
+
+	// Filenames of the files watched for changes.
+	Filenames []string
+
+	// PollInterval is the minimum duration between checks of the files. The
+	// files are checked every time the expiry of the credentials is checked
+	// if PollInterval is 0 or less.
+	PollInterval time.Duration
+
+	// CurrentTime returns the time the files were checked at. Defaults to
+	// time.Now if not set.
+	CurrentTime func() time.Time
+
+	files     map[string]fileState
+	lastCheck time.Time
+	changed   bool
+}
+
+// fileState is the state of a watched file compared to detect changes.
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// Retrieve returns the credentials of the wrapped Provider, recording the
+// state of the files before they are read by it.
+func (p *FileWatchProvider) Retrieve() (Value, error) {
+	p.snapshot()
+	return p.Provider.Retrieve()
+}
+
+// RetrieveWithContext returns the credentials of the wrapped Provider the
+// same as Retrieve, with the context if the Provider supports it.
+func (p *FileWatchProvider) RetrieveWithContext(ctx Context) (Value, error) {
+	p.snapshot()
+	if pc, ok := p.Provider.(ProviderWithContext); ok {
+		return pc.RetrieveWithContext(ctx)
+	}
+	return p.Provider.Retrieve()
+}
+
+// IsExpired returns true if the credentials of the wrapped Provider are
+// expired, or one of the files changed since they were retrieved.
+func (p *FileWatchProvider) IsExpired() bool {
+	if p.Provider.IsExpired() || p.changed {
+		return true
+	}
+
+	now := p.now()
+	if p.PollInterval > 0 && now.Sub(p.lastCheck) < p.PollInterval {
+		return false
+	}
+	p.lastCheck = now
+
+	for _, filename := range p.Filenames {
+		if statFile(filename) != p.files[filename] {
+			p.changed = true
+			return true
+		}
+	}
+	return false
+}
+
+// snapshot records the state of the files, which changes are detected
+// against.
+func (p *FileWatchProvider) snapshot() {
+	p.files = make(map[string]fileState, len(p.Filenames))
+	for _, filename := range p.Filenames {
+		p.files[filename] = statFile(filename)
+	}
+	p.lastCheck = p.now()
+	p.changed = false
+}
+
+func (p *FileWatchProvider) now() time.Time {
+	if p.CurrentTime == nil {
+		return time.Now()
+	}
+	return p.CurrentTime()
+}
+
+func statFile(filename string) fileState {
+	fi, err := os.Stat(filename)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, modTime: fi.ModTime(), size: fi.Size()}
+}
+
+// NewWatchedSharedCredentials returns a pointer to a new Credentials object
+// wrapping the shared credentials file provider, which reloads the
+// credentials when the shared credentials file changes, checked at most
+// once per DefaultFileWatchPollInterval.
+func NewWatchedSharedCredentials(filename, profile string) *Credentials {
+	p := &SharedCredentialsProvider{
+		Filename: filename,
+		Profile:  profile,
+	}
+
+	var filenames []string
+	if filename, err := p.filename(); err == nil {
+		filenames = append(filenames, filename)
+	}
+
+	return NewCredentials(&FileWatchProvider{
+		Provider:     p,
+		Filenames:    filenames,
+		PollInterval: DefaultFileWatchPollInterval,
+	})
+}
@@ -0,0 +1,71 @@
+package credentials
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileWatchProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file-watch-provider")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "credentials")
+	write := func(id string) {
+		content := "[default]\naws_access_key_id = " + id + "\naws_secret_access_key = secret\n"
+		if err := ioutil.WriteFile(filename, []byte(content), 0600); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+	write("first")
+
+	now := time.Now()
+	creds := NewCredentials(&FileWatchProvider{
+		Provider:     &SharedCredentialsProvider{Filename: filename, Profile: "default"},
+		Filenames:    []string{filename},
+		PollInterval: time.Minute,
+		CurrentTime:  func() time.Time { return now },
+	})
+
+	get := func() string {
+		v, err := creds.Get()
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		return v.AccessKeyID
+	}
+	if e, a := "first", get(); e != a {
+		t.Errorf("expect %v access key ID, got %v", e, a)
+	}
+
+	write("rotated")
+
+	// The file is not checked again until the poll interval elapsed.
+	if e, a := "first", get(); e != a {
+		t.Errorf("expect %v access key ID, got %v", e, a)
+	}
+
+	now = now.Add(time.Minute)
+	if !creds.IsExpired() {
+		t.Errorf("expect credentials expired after file changed")
+	}
+	if e, a := "rotated", get(); e != a {
+		t.Errorf("expect %v access key ID, got %v", e, a)
+	}
+
+	now = now.Add(time.Minute)
+	if creds.IsExpired() {
+		t.Errorf("expect credentials not expired, file unchanged")
+	}
+
+	os.Remove(filename)
+	now = now.Add(time.Minute)
+	if !creds.IsExpired() {
+		t.Errorf("expect credentials expired after file removed")
+	}
+}
@@ -0,0 +1,150 @@
+package ibmcreds
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// APIKeyFileProviderName is the name of the API key file credentials
+// provider.
+const APIKeyFileProviderName = "IBMAPIKeyFileProvider"
+
+// ErrCodeAPIKeyFile is the error code returned when the API key file cannot
+// be read.
+const ErrCodeAPIKeyFile = "APIKeyFileError"
+
+// APIKeyFileProvider is a credentials.Provider which retrieves IBM IAM
+// credentials with the API key read from a file, such as a secret mounted
+// into a container. Tokens are requested with a new API key when the file's
+// API key changes, so that long-lived processes use API keys rotated by ops
+// tooling without restarting.
+//
+// The credentials of NewAPIKeyFileCredentials expire when the file changes,
+// checked at most once per PollInterval, see credentials.FileWatchProvider.
+//
+// APIKeyFileProvider does not provide any synchronization and it is not safe
+// to share this value across multiple Credentials, Sessions, or service
+// clients without also sharing the same Credentials instance.
+type APIKeyFileProvider struct {
+	// Filename of the file the API key is read from. Whitespace around the
+	// API key is ignored.
+	Filename string
+
+	// ServiceInstanceID of the credentials.
+	ServiceInstanceID string
+
+	// IAMEndpoint is the IAM endpoint tokens are requested from, see
+	// Provider.
+	IAMEndpoint string
+
+	// Client is the HTTP client used to request tokens from the IAM endpoint.
+	// Defaults to http.DefaultClient if not set.
+	Client *http.Client
+
+	// ExpiryWindow of the credentials, see Provider.
+	ExpiryWindow time.Duration
+
+	// AccountID is the ID of the account the tokens are requested for, see
+	// Provider.
+	AccountID string
+
+	// PollInterval is the minimum duration between checks of the file for
+	// changes by the credentials of NewAPIKeyFileCredentials. Defaults to
+	// credentials.DefaultFileWatchPollInterval if not set.
+	PollInterval time.Duration
+
+	apiKey   string
+	provider *Provider
+	value    credentials.Value
+}
+
+// NewAPIKeyFileCredentials returns a Credentials wrapper retrieving IBM IAM
+// credentials with the API key read from the file, which reload the API key
+// when the file changes.
+//
+//     creds := ibmcreds.NewAPIKeyFileCredentials("/etc/secrets/ibm-api-key",
+//         instanceID, func(p *ibmcreds.APIKeyFileProvider) {
+//             p.PollInterval = time.Minute
+//         })
+func NewAPIKeyFileCredentials(filename, serviceInstanceID string, options ...func(*APIKeyFileProvider)) *credentials.Credentials {
+	p := &APIKeyFileProvider{
+		Filename:          filename,
+		ServiceInstanceID: serviceInstanceID,
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	pollInterval := p.PollInterval
+	if pollInterval == 0 {
+		pollInterval = credentials.DefaultFileWatchPollInterval
+	}
+
+	return credentials.NewTypedCredentials(&credentials.FileWatchProvider{
+		Provider:     p,
+		Filenames:    []string{filename},
+		PollInterval: pollInterval,
+	}, "ibm-iam")
+}
+
+// IsExpired returns true if the credentials retrieved are expired, or not yet
+// retrieved.
+func (p *APIKeyFileProvider) IsExpired() bool {
+	return p.provider == nil || p.provider.IsExpired()
+}
+
+// Retrieve reads the API key from the file, and requests credentials with it
+// from the IAM endpoint if they are expired or the API key changed.
+func (p *APIKeyFileProvider) Retrieve() (credentials.Value, error) {
+	return p.RetrieveWithContext(aws.BackgroundContext())
+}
+
+// RetrieveWithContext retrieves the credentials the same as Retrieve,
+// canceling the request to the IAM endpoint if the context is canceled.
+func (p *APIKeyFileProvider) RetrieveWithContext(ctx credentials.Context) (credentials.Value, error) {
+	apiKey, err := p.readAPIKey()
+	if err != nil {
+		return credentials.Value{ProviderName: APIKeyFileProviderName}, err
+	}
+
+	if p.provider != nil && apiKey == p.apiKey && !p.provider.IsExpired() {
+		return p.value, nil
+	}
+
+	provider := NewProviderClient(apiKey, p.ServiceInstanceID, p.IAMEndpoint, func(provider *Provider) {
+		provider.Client = p.Client
+		provider.ExpiryWindow = p.ExpiryWindow
+		provider.AccountID = p.AccountID
+	}).(*Provider)
+
+	v, err := provider.RetrieveWithContext(ctx)
+	if err != nil {
+		return credentials.Value{ProviderName: APIKeyFileProviderName}, err
+	}
+	v.ProviderName = APIKeyFileProviderName
+
+	p.apiKey, p.provider, p.value = apiKey, provider, v
+	return v, nil
+}
+
+func (p *APIKeyFileProvider) readAPIKey() (string, error) {
+	b, err := ioutil.ReadFile(p.Filename)
+	if err != nil {
+		return "", newCredentialsError(ErrMissingConfiguration,
+			awserr.New(ErrCodeAPIKeyFile, "failed to read API key file "+p.Filename, err))
+	}
+
+	apiKey := strings.TrimSpace(string(b))
+	if len(apiKey) == 0 {
+		return "", newCredentialsError(ErrMissingConfiguration,
+			awserr.New(ErrCodeAPIKeyFile, "API key file "+p.Filename+" is empty", nil))
+	}
+	return apiKey, nil
+}
@@ -0,0 +1,76 @@
+package ibmcreds_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+)
+
+func TestAPIKeyFileCredentials(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+	dir, cleanup := newTokenCacheDir(t)
+	defer cleanup()
+
+	filename := filepath.Join(dir, "apikey")
+	write := func(apiKey string) {
+		if err := ioutil.WriteFile(filename, []byte(apiKey+"\n"), 0600); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+	write("first-key")
+
+	creds := ibmcreds.NewAPIKeyFileCredentials(filename, "instanceID", func(p *ibmcreds.APIKeyFileProvider) {
+		p.IAMEndpoint = server.URL
+		p.PollInterval = time.Nanosecond
+	})
+	get := func() string {
+		v, err := creds.Get()
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if e, a := ibmcreds.APIKeyFileProviderName, v.ProviderName; e != a {
+			t.Errorf("expect %v provider, got %v", e, a)
+		}
+		return v.SessionToken
+	}
+
+	token := get()
+	if e, a := token, get(); e != a {
+		t.Errorf("expect %v token, got %v", e, a)
+	}
+
+	write("rotated-key")
+	if e, a := token, get(); e == a {
+		t.Errorf("expect new token after API key rotated, got %v", a)
+	}
+
+	reqs := server.Requests()
+	if e, a := 2, len(reqs); e != a {
+		t.Fatalf("expect %v token requests, got %v", e, a)
+	}
+	for i, e := range []string{"first-key", "rotated-key"} {
+		if a := reqs[i].APIKey; e != a {
+			t.Errorf("expect %v API key, got %v", e, a)
+		}
+	}
+}
+
+func TestAPIKeyFileCredentialsMissingFile(t *testing.T) {
+	creds := ibmcreds.NewAPIKeyFileCredentials(filepath.Join("testdata", "missing"), "instanceID")
+
+	_, err := creds.Get()
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := ibmcreds.ErrMissingConfiguration, err.(*ibmcreds.CredentialsError).Class; e != a {
+		t.Errorf("expect %v class, got %v", e, a)
+	}
+	if e, a := ibmcreds.ErrCodeAPIKeyFile, err.(*ibmcreds.CredentialsError).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
@@ -0,0 +1,175 @@
+package ibmcreds
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// AuthenticatorProviderName is the name of the credentials provider wrapping
+// an Authenticator.
+const AuthenticatorProviderName = "IBMAuthenticatorProvider"
+
+// AuthenticationTypeIAM is the authentication type of the Authenticator
+// returned by NewAuthenticator.
+const AuthenticationTypeIAM = "iam"
+
+// Authenticator is the authenticator interface of the IBM Cloud Go SDK core,
+// github.com/IBM/go-sdk-core. The interface is declared here so that
+// authenticators can be adapted without depending on the SDK core, any
+// authenticator from the SDK core satisfies it, and the Authenticator returned
+// by NewAuthenticator satisfies the SDK core's interface.
+type Authenticator interface {
+	AuthenticationType() string
+	Authenticate(*http.Request) error
+	Validate() error
+}
+
+// CredentialsAuthenticator is an Authenticator which authenticates requests
+// with the bearer token of IBM IAM credentials. Use this to share a single
+// set of IAM credentials, and their tokens, with IBM Cloud Go SDK clients.
+type CredentialsAuthenticator struct {
+	Credentials *credentials.Credentials
+}
+
+// NewAuthenticator returns an Authenticator for the credentials provided.
+//
+//     creds := ibmcreds.NewCredentialsClient(apiKey, serviceInstanceID, "")
+//     service, err := someibmservicev1.NewSomeIBMServiceV1(&someibmservicev1.SomeIBMServiceV1Options{
+//         Authenticator: ibmcreds.NewAuthenticator(creds),
+//     })
+func NewAuthenticator(creds *credentials.Credentials) *CredentialsAuthenticator {
+	return &CredentialsAuthenticator{Credentials: creds}
+}
+
+// AuthenticationType returns the authentication type of the Authenticator.
+func (a *CredentialsAuthenticator) AuthenticationType() string {
+	return AuthenticationTypeIAM
+}
+
+// Authenticate sets the request's Authorization header with the bearer token
+// of the credentials, retrieving new credentials if they have expired.
+func (a *CredentialsAuthenticator) Authenticate(r *http.Request) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+
+	v, err := a.Credentials.Get()
+	if err != nil {
+		return err
+	}
+
+	r.Header.Set("Authorization", "Bearer "+v.SessionToken)
+	return nil
+}
+
+// Validate returns an error if the Authenticator has no credentials.
+func (a *CredentialsAuthenticator) Validate() error {
+	if a.Credentials == nil {
+		return awserr.New("InvalidAuthenticator", "authenticator credentials not set", nil)
+	}
+	return nil
+}
+
+// AuthenticatorProvider is a credentials.Provider which retrieves IBM IAM
+// bearer tokens from an Authenticator, such as an IAM authenticator of the
+// IBM Cloud Go SDK core. Use this to share an authenticator's tokens with
+// this SDK's clients.
+//
+// The expiration of JWT bearer tokens is read from the token's exp claim.
+// Tokens without a readable expiration are retrieved from the Authenticator
+// for each request, relying on the Authenticator to cache tokens.
+type AuthenticatorProvider struct {
+	credentials.Expiry
+
+	Authenticator     Authenticator
+	ServiceInstanceID string
+
+	// ExpiryWindow will allow the credentials to trigger refreshing prior to
+	// the token actually expiring.
+	//
+	// If ExpiryWindow is 0 or less it will be ignored.
+	ExpiryWindow time.Duration
+}
+
+// NewAuthenticatorCredentials returns a Credentials wrapper retrieving IBM
+// IAM credentials from the Authenticator provided.
+//
+//     authenticator := &core.IamAuthenticator{ApiKey: apiKey}
+//     sess := session.Must(session.NewSession(&aws.Config{
+//         Credentials: ibmcreds.NewAuthenticatorCredentials(authenticator, serviceInstanceID),
+//     }))
+func NewAuthenticatorCredentials(auth Authenticator, serviceInstanceID string, options ...func(*AuthenticatorProvider)) *credentials.Credentials {
+	p := &AuthenticatorProvider{
+		Authenticator:     auth,
+		ServiceInstanceID: serviceInstanceID,
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return credentials.NewTypedCredentials(p, "ibm-iam")
+}
+
+// Retrieve authenticates a request with the Authenticator, and returns the
+// bearer token it was authenticated with.
+func (p *AuthenticatorProvider) Retrieve() (credentials.Value, error) {
+	v := credentials.Value{
+		ServiceInstanceID: p.ServiceInstanceID,
+		ProviderName:      AuthenticatorProviderName,
+	}
+
+	if p.Authenticator == nil {
+		return v, awserr.New("InvalidAuthenticator", "authenticator not set", nil)
+	}
+
+	r, _ := http.NewRequest("GET", "https://localhost", nil)
+	if err := p.Authenticator.Authenticate(r); err != nil {
+		return v, awserr.New("AuthenticatorError", "failed to authenticate", err)
+	}
+
+	const prefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, prefix) {
+		return v, awserr.New("AuthenticatorError",
+			"authenticator did not provide a bearer token", nil)
+	}
+	v.SessionToken = strings.TrimPrefix(auth, prefix)
+
+	if exp, ok := jwtExpiration(v.SessionToken); ok {
+		p.SetExpiration(exp, p.ExpiryWindow)
+	} else {
+		p.SetExpiration(time.Time{}, 0)
+	}
+
+	return v, nil
+}
+
+// jwtExpiration returns the time of a JWT's exp claim, or false if the token
+// is not a JWT with an exp claim. The token's signature is not verified.
+func jwtExpiration(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		ExpiresAt *int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ExpiresAt == nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(*claims.ExpiresAt, 0), true
+}
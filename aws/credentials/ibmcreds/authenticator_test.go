@@ -0,0 +1,122 @@
+package ibmcreds_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+)
+
+type mockAuthenticator struct {
+	tokens []string
+	calls  int
+	err    error
+}
+
+func (m *mockAuthenticator) AuthenticationType() string { return "mock" }
+func (m *mockAuthenticator) Validate() error            { return nil }
+func (m *mockAuthenticator) Authenticate(r *http.Request) error {
+	if m.err != nil {
+		return m.err
+	}
+	token := m.tokens[m.calls]
+	m.calls++
+	r.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+
+	auth := ibmcreds.NewAuthenticator(
+		ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL))
+	var _ ibmcreds.Authenticator = auth
+
+	for i := 0; i < 2; i++ {
+		r, _ := http.NewRequest("GET", "https://example.cloud.ibm.com", nil)
+		if err := auth.Authenticate(r); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if e, a := "Bearer "+server.Tokens()[0].AccessToken, r.Header.Get("Authorization"); e != a {
+			t.Errorf("expect %v authorization, got %v", e, a)
+		}
+	}
+	if e, a := 1, len(server.Requests()); e != a {
+		t.Errorf("expect token to be shared, got %v token requests", a)
+	}
+
+	if err := (&ibmcreds.CredentialsAuthenticator{}).Validate(); err == nil {
+		t.Errorf("expect error for authenticator without credentials")
+	}
+}
+
+func TestAuthenticatorCredentials_JWT(t *testing.T) {
+	now := time.Now()
+	var tokens []string
+	for _, ttl := range []time.Duration{-time.Minute, time.Hour} {
+		token, err := ibmcredstest.NewJWT(ibmcredstest.NewClaims("account", now, ttl))
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	auth := &mockAuthenticator{tokens: tokens}
+	creds := ibmcreds.NewAuthenticatorCredentials(auth, "instanceID")
+
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := tokens[0], v.SessionToken; e != a {
+		t.Errorf("expect %v token, got %v", e, a)
+	}
+	if e, a := "instanceID", v.ServiceInstanceID; e != a {
+		t.Errorf("expect %v service instance ID, got %v", e, a)
+	}
+	if e, a := "ibm-iam", creds.GetCredentialsType(); e != a {
+		t.Errorf("expect %v credentials type, got %v", e, a)
+	}
+	if !creds.IsExpired() {
+		t.Errorf("expect credentials with expired token to be expired")
+	}
+
+	v, err = creds.Get()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := tokens[1], v.SessionToken; e != a {
+		t.Errorf("expect %v token, got %v", e, a)
+	}
+	if creds.IsExpired() {
+		t.Errorf("expect credentials with valid token to not be expired")
+	}
+}
+
+func TestAuthenticatorCredentials_OpaqueToken(t *testing.T) {
+	auth := &mockAuthenticator{tokens: []string{"opaque-1", "opaque-2"}}
+	creds := ibmcreds.NewAuthenticatorCredentials(auth, "instanceID")
+
+	for i, e := range auth.tokens {
+		v, err := creds.Get()
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		if a := v.SessionToken; e != a {
+			t.Errorf("%d, expect %v token, got %v", i, e, a)
+		}
+	}
+}
+
+func TestAuthenticatorCredentials_Error(t *testing.T) {
+	auth := &mockAuthenticator{err: fmt.Errorf("authentication failed")}
+	creds := ibmcreds.NewAuthenticatorCredentials(auth, "instanceID")
+
+	if _, err := creds.Get(); err == nil {
+		t.Errorf("expect error, got none")
+	}
+}
@@ -0,0 +1,257 @@
+package ibmcreds
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+const (
+	// ErrCodeNotAuthorized is the error code returned by CheckAuthorized when
+	// the credentials are not authorized to perform the action.
+	ErrCodeNotAuthorized = "NotAuthorized"
+
+	// ErrCodeInactiveToken is the error code returned when IAM reports the
+	// credentials' access token is not active, such as when it has expired
+	// or been revoked.
+	ErrCodeInactiveToken = "InactiveToken"
+
+	// ErrCodeAuthorizationCheck is the error code returned when the
+	// authorization of the credentials could not be checked.
+	ErrCodeAuthorizationCheck = "AuthorizationCheckError"
+)
+
+// TokenInfo is the introspection of an IAM access token.
+type TokenInfo struct {
+	// Whether the token is active, neither expired nor revoked.
+	Active bool
+
+	// IAM ID of the identity the token was issued to, such as
+	// "IBMid-..." or "iam-ServiceId-...".
+	IAMID string
+
+	// Subject of the token, such as the user's email address, or the
+	// service ID.
+	Subject string
+
+	// ID of the account the token was issued for.
+	AccountID string
+
+	// Scope of the token.
+	Scope string
+
+	// ExpiresAt is the time the token expires.
+	ExpiresAt time.Time
+}
+
+// An Authorizer checks whether IBM IAM credentials are authorized to perform
+// actions on resources, such as writing the objects of a COS bucket, so that
+// applications can fail fast with a meaningful error, instead of debugging
+// the 403 responses of their requests.
+//
+//     authz := ibmcreds.NewAuthorizer(sess.Config.Credentials)
+//     err := authz.CheckAuthorized(ctx, bucketCRN, "cloud-object-storage.object.put")
+//     if err != nil {
+//         // Missing a Writer role for the bucket, or the check failed.
+//     }
+type Authorizer struct {
+	// Credentials authorized. The credentials' session token must be an IAM
+	// access token.
+	Credentials *credentials.Credentials
+
+	// IAMEndpoint is the IAM endpoint tokens are introspected, and
+	// authorizations checked, with. Defaults to https://iam.cloud.ibm.com.
+	IAMEndpoint string
+
+	// Client is the HTTP client used to call IAM. Defaults to
+	// http.DefaultClient if not set.
+	Client *http.Client
+}
+
+// NewAuthorizer returns an Authorizer of the credentials. Pass in additional
+// functional options to customize the authorizer.
+func NewAuthorizer(creds *credentials.Credentials, options ...func(*Authorizer)) *Authorizer {
+	a := &Authorizer{Credentials: creds}
+	for _, option := range options {
+		option(a)
+	}
+
+	return a
+}
+
+// Introspect returns the introspection of the credentials' access token.
+func (a *Authorizer) Introspect(ctx aws.Context) (*TokenInfo, error) {
+	token, err := a.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		Active  bool   `json:"active"`
+		IAMID   string `json:"iam_id"`
+		Subject string `json:"sub"`
+		Scope   string `json:"scope"`
+		Exp     int64  `json:"exp"`
+		Account struct {
+			BSS string `json:"bss"`
+		} `json:"account"`
+	}
+	body := strings.NewReader(url.Values{"token": {token}}.Encode())
+	if err := a.do(ctx, "/identity/introspect", "application/x-www-form-urlencoded", body, "", &out); err != nil {
+		return nil, awserr.New(ErrCodeAuthorizationCheck, "failed to introspect IAM token", err)
+	}
+
+	info := &TokenInfo{
+		Active:    out.Active,
+		IAMID:     out.IAMID,
+		Subject:   out.Subject,
+		AccountID: out.Account.BSS,
+		Scope:     out.Scope,
+	}
+	if out.Exp != 0 {
+		info.ExpiresAt = time.Unix(out.Exp, 0)
+	}
+
+	return info, nil
+}
+
+// IsAuthorized returns whether the credentials are authorized to perform the
+// action on the resource with the CRN provided, such as the
+// "cloud-object-storage.object.put" action on a COS bucket. The identity of
+// the credentials is introspected, and the authorization decision of IAM for
+// the identity, action, and resource is returned.
+func (a *Authorizer) IsAuthorized(ctx aws.Context, resourceCRN, action string) (bool, error) {
+	info, err := a.Introspect(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !info.Active {
+		return false, awserr.New(ErrCodeInactiveToken, "IAM token is not active", nil)
+	}
+
+	token, err := a.token(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	type attributes struct {
+		ID string `json:"id"`
+	}
+	req := []struct {
+		Subject struct {
+			Attributes attributes `json:"attributes"`
+		} `json:"subject"`
+		Action   string `json:"action"`
+		Resource struct {
+			CRN string `json:"crn"`
+		} `json:"resource"`
+	}{{Action: action}}
+	req[0].Subject.Attributes.ID = info.IAMID
+	req[0].Resource.CRN = resourceCRN
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return false, err
+	}
+
+	var out struct {
+		Responses []struct {
+			Decision struct {
+				Permitted bool `json:"permitted"`
+			} `json:"authorizationDecision"`
+		} `json:"responses"`
+	}
+	if err := a.do(ctx, "/v2/authz", "application/json", bytes.NewReader(b), token, &out); err != nil {
+		return false, awserr.New(ErrCodeAuthorizationCheck, "failed to check authorization", err)
+	}
+	if len(out.Responses) == 0 {
+		return false, awserr.New(ErrCodeAuthorizationCheck, "failed to check authorization",
+			fmt.Errorf("server returned no authorization decision"))
+	}
+
+	return out.Responses[0].Decision.Permitted, nil
+}
+
+// CheckAuthorized returns an error with the ErrCodeNotAuthorized code if the
+// credentials are not authorized to perform the action on the resource with
+// the CRN provided. See IsAuthorized.
+func (a *Authorizer) CheckAuthorized(ctx aws.Context, resourceCRN, action string) error {
+	ok, err := a.IsAuthorized(ctx, resourceCRN, action)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return awserr.New(ErrCodeNotAuthorized,
+			fmt.Sprintf("credentials are not authorized to perform %s on %s, check the IAM roles granted for the resource",
+				action, resourceCRN), nil)
+	}
+
+	return nil
+}
+
+// token returns the IAM access token of the credentials, retrieved with the
+// context if they need to be refreshed.
+func (a *Authorizer) token(ctx aws.Context) (string, error) {
+	if a.Credentials == nil {
+		return "", newCredentialsError(ErrMissingConfiguration,
+			awserr.New(ErrCodeAuthorizationCheck, "credentials not set", nil))
+	}
+	v, err := a.Credentials.GetWithContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(v.SessionToken) == 0 {
+		return "", awserr.New(ErrCodeAuthorizationCheck,
+			"credentials do not provide an IAM access token", nil)
+	}
+
+	return v.SessionToken, nil
+}
+
+// do sends a POST request to the IAM endpoint path, decoding the JSON
+// response into out. The request is authorized with the bearer token if set.
+func (a *Authorizer) do(ctx aws.Context, path, contentType string, body io.Reader, token string, out interface{}) error {
+	endpoint := a.IAMEndpoint
+	if len(endpoint) == 0 {
+		endpoint = defaultTrustedProfileIAMEndpoint
+	}
+
+	client := a.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequest("POST", strings.TrimRight(endpoint, "/")+path, body)
+	if err != nil {
+		return err
+	}
+	req = requestWithContext(req, ctx)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+	if len(token) != 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		respErr := &iamResponseError{StatusCode: resp.StatusCode}
+		json.NewDecoder(resp.Body).Decode(respErr)
+		return respErr
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
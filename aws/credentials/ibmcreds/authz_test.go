@@ -0,0 +1,130 @@
+package ibmcreds_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/credentialstest"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+)
+
+const testBucketCRN = "crn:v1:bluemix:public:cloud-object-storage:global:a/account:instance::bucket:bucket"
+
+func newAuthzTestServer(t *testing.T, active bool, permitted map[string]bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/identity/introspect":
+			if e, a := "token", r.FormValue("token"); e != a {
+				t.Errorf("expect %v token, got %v", e, a)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"active":  active,
+				"iam_id":  "iam-ServiceId-1234",
+				"sub":     "ServiceId-1234",
+				"exp":     1600000000,
+				"account": map[string]string{"bss": "account"},
+			})
+		case "/v2/authz":
+			if e, a := "Bearer token", r.Header.Get("Authorization"); e != a {
+				t.Errorf("expect %v authorization, got %v", e, a)
+			}
+			var req []struct {
+				Subject struct {
+					Attributes struct {
+						ID string `json:"id"`
+					} `json:"attributes"`
+				} `json:"subject"`
+				Action   string `json:"action"`
+				Resource struct {
+					CRN string `json:"crn"`
+				} `json:"resource"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req) != 1 {
+				t.Errorf("expect one authorization request, got %v, %v", req, err)
+				return
+			}
+			if e, a := "iam-ServiceId-1234", req[0].Subject.Attributes.ID; e != a {
+				t.Errorf("expect %v subject, got %v", e, a)
+			}
+			if e, a := testBucketCRN, req[0].Resource.CRN; e != a {
+				t.Errorf("expect %v resource, got %v", e, a)
+			}
+			w.Write([]byte(`{"responses":[{"authorizationDecision":{"permitted":` +
+				strconv.FormatBool(permitted[req[0].Action]) + `}}]}`))
+		default:
+			t.Errorf("unexpected request %v", r.URL.Path)
+		}
+	}))
+}
+
+func newAuthorizer(endpoint string) *ibmcreds.Authorizer {
+	creds := credentials.NewTypedCredentials(&credentialstest.MockProvider{
+		Value: credentials.Value{SessionToken: "token"},
+	}, "ibm-iam")
+
+	return ibmcreds.NewAuthorizer(creds, func(a *ibmcreds.Authorizer) {
+		a.IAMEndpoint = endpoint
+	})
+}
+
+func TestAuthorizerIntrospect(t *testing.T) {
+	server := newAuthzTestServer(t, true, nil)
+	defer server.Close()
+
+	info, err := newAuthorizer(server.URL).Introspect(aws.BackgroundContext())
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !info.Active {
+		t.Errorf("expect active token")
+	}
+	if e, a := "iam-ServiceId-1234", info.IAMID; e != a {
+		t.Errorf("expect %v IAM ID, got %v", e, a)
+	}
+	if e, a := "account", info.AccountID; e != a {
+		t.Errorf("expect %v account ID, got %v", e, a)
+	}
+	if e, a := int64(1600000000), info.ExpiresAt.Unix(); e != a {
+		t.Errorf("expect %v expiration, got %v", e, a)
+	}
+}
+
+func TestAuthorizerCheckAuthorized(t *testing.T) {
+	server := newAuthzTestServer(t, true, map[string]bool{
+		"cloud-object-storage.object.get": true,
+	})
+	defer server.Close()
+
+	authz := newAuthorizer(server.URL)
+
+	if err := authz.CheckAuthorized(aws.BackgroundContext(), testBucketCRN, "cloud-object-storage.object.get"); err != nil {
+		t.Errorf("expect no error, got %v", err)
+	}
+
+	err := authz.CheckAuthorized(aws.BackgroundContext(), testBucketCRN, "cloud-object-storage.object.put")
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := ibmcreds.ErrCodeNotAuthorized, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
+
+func TestAuthorizerInactiveToken(t *testing.T) {
+	server := newAuthzTestServer(t, false, nil)
+	defer server.Close()
+
+	_, err := newAuthorizer(server.URL).IsAuthorized(aws.BackgroundContext(), testBucketCRN, "cloud-object-storage.object.get")
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := ibmcreds.ErrCodeInactiveToken, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
@@ -0,0 +1,133 @@
+package ibmcreds
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// ChainProvider is a credentials.Provider which retrieves IBM IAM credentials
+// from the first of its Providers which returns credentials, such as an API
+// key from the environment, then the IBM Cloud CLI session. The provider
+// credentials were retrieved from is used until it expires.
+//
+// Unlike the credentials package's ChainProvider, the providers tried, and
+// why each was skipped or failed, are logged at the LogDebug level, and
+// included in the NoCredentialProviders error returned when none of the
+// providers return credentials. Providers failing with an error of the
+// ErrMissingConfiguration class, such as a missing API key, are reported as
+// skipped.
+//
+//     creds := ibmcreds.NewChainCredentials([]credentials.Provider{
+//         ibmcreds.NewProviderClient(os.Getenv("IBM_API_KEY_ID"), instanceID, ""),
+//         &ibmcreds.CLISessionProvider{ServiceInstanceID: instanceID},
+//     }, func(p *ibmcreds.ChainProvider) {
+//         p.Logger = sess.Config.Logger
+//         p.LogLevel = sess.Config.LogLevel
+//     })
+//
+// ChainProvider does not provide any synchronization and it is not safe to
+// share this value across multiple Credentials, Sessions, or service clients
+// without also sharing the same Credentials instance.
+type ChainProvider struct {
+	Providers []credentials.Provider
+
+	// Logger the providers tried are logged to, if the LogLevel is at least
+	// LogDebug.
+	Logger   aws.Logger
+	LogLevel *aws.LogLevelType
+
+	curr credentials.Provider
+}
+
+// NewChainCredentials returns a Credentials wrapper retrieving IBM IAM
+// credentials from the first of the providers which returns credentials.
+func NewChainCredentials(providers []credentials.Provider, options ...func(*ChainProvider)) *credentials.Credentials {
+	p := &ChainProvider{
+		Providers: append([]credentials.Provider{}, providers...),
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return credentials.NewTypedCredentials(p, "ibm-iam")
+}
+
+// Retrieve returns the credentials of the first provider which returns
+// credentials, or a NoCredentialProviders error with the errors of all the
+// providers.
+func (c *ChainProvider) Retrieve() (credentials.Value, error) {
+	return c.RetrieveWithContext(aws.BackgroundContext())
+}
+
+// RetrieveWithContext retrieves the credentials the same as Retrieve, with the
+// context for providers which support it.
+func (c *ChainProvider) RetrieveWithContext(ctx credentials.Context) (credentials.Value, error) {
+	var errs []error
+	var trail []string
+	for i, p := range c.Providers {
+		var v credentials.Value
+		var err error
+		if pc, ok := p.(credentials.ProviderWithContext); ok {
+			v, err = pc.RetrieveWithContext(ctx)
+		} else {
+			v, err = p.Retrieve()
+		}
+
+		name := v.ProviderName
+		if len(name) == 0 {
+			name = fmt.Sprintf("%T", p)
+		}
+		if err == nil {
+			c.debugf("credentials chain provider %d, %s, retrieved credentials", i+1, name)
+			c.curr = p
+			return v, nil
+		}
+
+		status := "failed"
+		if credsErr, ok := err.(*CredentialsError); ok && credsErr.Class == ErrMissingConfiguration {
+			status = "skipped"
+		}
+		c.debugf("credentials chain provider %d, %s, %s: %v", i+1, name, status, err)
+		trail = append(trail, fmt.Sprintf("%s %s, %s", name, status, chainErrorMessage(err)))
+		errs = append(errs, err)
+	}
+	c.curr = nil
+
+	msg := "no valid providers in chain"
+	if len(trail) != 0 {
+		msg += ", tried: " + strings.Join(trail, "; ")
+	}
+	return credentials.Value{}, awserr.NewBatchError("NoCredentialProviders", msg, errs)
+}
+
+// IsExpired returns the expired state of the provider credentials were last
+// retrieved from, or true if credentials have not been retrieved.
+func (c *ChainProvider) IsExpired() bool {
+	if c.curr != nil {
+		return c.curr.IsExpired()
+	}
+
+	return true
+}
+
+func (c *ChainProvider) debugf(format string, args ...interface{}) {
+	if c.Logger == nil || !c.LogLevel.AtLeast(aws.LogDebug) {
+		return
+	}
+	c.Logger.Log(fmt.Sprintf(format, args...))
+}
+
+// chainErrorMessage returns the message of the error for the trail of the
+// chain's error, without the error's code or original errors, which are part
+// of the chain's error.
+func chainErrorMessage(err error) string {
+	if aerr, ok := err.(awserr.Error); ok {
+		return aerr.Message()
+	}
+	return err.Error()
+}
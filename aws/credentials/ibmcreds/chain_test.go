@@ -0,0 +1,96 @@
+package ibmcreds_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+)
+
+func TestChainProvider(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+
+	var logs []string
+	creds := ibmcreds.NewChainCredentials([]credentials.Provider{
+		ibmcreds.NewProviderClient("", "instanceID", server.URL),
+		ibmcreds.NewProviderClient("apikey", "instanceID", server.URL),
+	}, func(p *ibmcreds.ChainProvider) {
+		p.Logger = aws.LoggerFunc(func(args ...interface{}) {
+			logs = append(logs, fmt.Sprint(args...))
+		})
+		p.LogLevel = aws.LogLevel(aws.LogDebug)
+	})
+
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := server.Tokens()[0].AccessToken, v.SessionToken; e != a {
+		t.Errorf("expect %v token, got %v", e, a)
+	}
+
+	if e, a := 2, len(logs); e != a {
+		t.Fatalf("expect %v log messages, got %v, %v", e, a, logs)
+	}
+	if e, a := "provider 1, IBMIAMProvider, skipped", logs[0]; !strings.Contains(a, e) {
+		t.Errorf("expect %q in log, got %q", e, a)
+	}
+	if e, a := "provider 2, IBMIAMProvider, retrieved credentials", logs[1]; !strings.Contains(a, e) {
+		t.Errorf("expect %q in log, got %q", e, a)
+	}
+}
+
+func TestChainProviderNoCredentials(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+	server.FailNext(1, 400, "BXNIM0415E", "Provided API key could not be found.")
+
+	var logs []string
+	p := &ibmcreds.ChainProvider{
+		Providers: []credentials.Provider{
+			ibmcreds.NewProviderClient("", "instanceID", server.URL),
+			ibmcreds.NewProviderClient("apikey", "instanceID", server.URL),
+			credentials.ErrorProvider{
+				Err:          awserr.New("EnvAccessKeyNotFound", "IBM_API_KEY_ID not set", nil),
+				ProviderName: "EnvProvider",
+			},
+		},
+		Logger: aws.LoggerFunc(func(args ...interface{}) {
+			logs = append(logs, fmt.Sprint(args...))
+		}),
+	}
+
+	_, err := p.Retrieve()
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := "NoCredentialProviders", err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+	if e, a := 3, len(err.(awserr.BatchedErrors).OrigErrs()); e != a {
+		t.Errorf("expect %v errors, got %v", e, a)
+	}
+	for _, e := range []string{
+		"IBMIAMProvider skipped, failed to load credentials, API key not set",
+		"IBMIAMProvider failed, failed to load credentials",
+		"EnvProvider failed, IBM_API_KEY_ID not set",
+	} {
+		if a := err.Error(); !strings.Contains(a, e) {
+			t.Errorf("expect %q in error, got %q", e, a)
+		}
+	}
+	if !p.IsExpired() {
+		t.Errorf("expect expired without credentials")
+	}
+
+	// Diagnostics are only logged at the LogDebug level.
+	if e, a := 0, len(logs); e != a {
+		t.Errorf("expect %v log messages, got %v", e, a)
+	}
+}
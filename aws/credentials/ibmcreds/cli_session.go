@@ -0,0 +1,257 @@
+package ibmcreds
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/internal/shareddefaults"
+)
+
+// CLISessionProviderName is the name of the credentials provider reading the
+// IBM Cloud CLI session.
+const CLISessionProviderName = "IBMCLISessionProvider"
+
+// defaultCLIIAMEndpoint is the IAM endpoint used to refresh the CLI session's
+// token if the CLI configuration does not specify one.
+const defaultCLIIAMEndpoint = "https://iam.cloud.ibm.com"
+
+// cliRefreshClientID is the OAuth client ID the IBM Cloud CLI requests tokens
+// with. Refresh tokens issued to the CLI can only be redeemed by this client.
+const cliRefreshClientID = "bx"
+
+var (
+	// ErrCLISessionNotFound is returned when the IBM Cloud CLI configuration
+	// file cannot be found, or the CLI is not logged in.
+	ErrCLISessionNotFound = awserr.New("CLISessionNotFound",
+		"IBM Cloud CLI session not found, log in with `ibmcloud login`", nil)
+
+	// ErrCLISessionExpired is returned when the IBM Cloud CLI session's token
+	// has expired, and cannot be refreshed.
+	ErrCLISessionExpired = awserr.New("CLISessionExpired",
+		"IBM Cloud CLI session expired, log in with `ibmcloud login`", nil)
+)
+
+// CLISessionProvider is a credentials.Provider which retrieves IBM IAM
+// credentials from the session of the IBM Cloud CLI, the ibmcloud command,
+// in ~/.bluemix/config.json. Use this in developer tooling to reuse the
+// CLI's login instead of requiring a separate API key.
+//
+// The CLI configuration is read for each retrieval, so tokens refreshed by the
+// CLI are picked up. If the CLI's token has expired it is refreshed with the
+// CLI's refresh token. Refreshed tokens are kept in memory only, the CLI's
+// configuration file is never written.
+type CLISessionProvider struct {
+	credentials.Expiry
+
+	// Filename is the path of the IBM Cloud CLI configuration file.
+	//
+	// If empty will look for "IBMCLOUD_HOME" env variable, then
+	// "BLUEMIX_HOME" env variable, and use the .bluemix/config.json file in
+	// that directory. If neither is set will look in
+	// $HOME/.bluemix/config.json on Linux/Unix based systems, and
+	// %USERPROFILE%\.bluemix\config.json on Windows.
+	Filename string
+
+	ServiceInstanceID string
+
+	// IAMEndpoint is the IAM endpoint used to refresh the CLI session's
+	// token. Defaults to the IAMEndpoint of the CLI configuration.
+	IAMEndpoint string
+
+	// Client is the HTTP client used to refresh tokens. Defaults to
+	// http.DefaultClient if not set.
+	Client *http.Client
+
+	// ExpiryWindow will allow the credentials to trigger refreshing prior to
+	// the token actually expiring.
+	//
+	// If ExpiryWindow is 0 or less it will be ignored.
+	ExpiryWindow time.Duration
+
+	// refreshed is the last token refreshed by the provider, and the CLI
+	// token it was refreshed from.
+	refreshed     *cliRefreshOutput
+	refreshedFrom string
+}
+
+// NewCLISessionCredentials returns a Credentials wrapper retrieving IBM IAM
+// credentials from the IBM Cloud CLI session.
+//
+//     sess := session.Must(session.NewSession(&aws.Config{
+//         Credentials: ibmcreds.NewCLISessionCredentials(serviceInstanceID),
+//     }))
+func NewCLISessionCredentials(serviceInstanceID string, options ...func(*CLISessionProvider)) *credentials.Credentials {
+	p := &CLISessionProvider{
+		ServiceInstanceID: serviceInstanceID,
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return credentials.NewTypedCredentials(p, "ibm-iam")
+}
+
+// cliConfig is the subset of the IBM Cloud CLI configuration read.
+type cliConfig struct {
+	IAMEndpoint     string `json:"IAMEndpoint"`
+	IAMToken        string `json:"IAMToken"`
+	IAMRefreshToken string `json:"IAMRefreshToken"`
+}
+
+// Retrieve reads the IBM Cloud CLI session, refreshing its token if it has
+// expired.
+func (p *CLISessionProvider) Retrieve() (credentials.Value, error) {
+	v := credentials.Value{
+		ServiceInstanceID: p.ServiceInstanceID,
+		ProviderName:      CLISessionProviderName,
+	}
+
+	cfg, err := p.loadConfig()
+	if err != nil {
+		return v, err
+	}
+
+	token := strings.TrimSpace(strings.TrimPrefix(cfg.IAMToken, "Bearer "))
+	if len(token) == 0 {
+		return v, newCredentialsError(ErrMissingConfiguration, ErrCLISessionNotFound)
+	}
+
+	exp, ok := jwtExpiration(token)
+	if !ok {
+		return v, awserr.New("CLISessionError",
+			"IBM Cloud CLI session token is not a valid IAM token", nil)
+	}
+
+	if time.Now().Before(exp) {
+		v.SessionToken = token
+		p.SetExpiration(exp, p.ExpiryWindow)
+		return v, nil
+	}
+
+	if r := p.refreshed; r != nil && p.refreshedFrom == token &&
+		time.Now().Before(time.Unix(r.Expiration, 0)) {
+		v.SessionToken = r.AccessToken
+		p.SetExpiration(time.Unix(r.Expiration, 0), p.ExpiryWindow)
+		return v, nil
+	}
+
+	if len(cfg.IAMRefreshToken) == 0 {
+		return v, newCredentialsError(ErrTokenExpired, ErrCLISessionExpired)
+	}
+
+	r, err := p.refresh(cfg)
+	if err != nil {
+		class := ErrTokenExpired
+		if iamErrorClass(err) == ErrIAMUnreachable {
+			class = ErrIAMUnreachable
+		}
+		return v, newCredentialsError(class,
+			awserr.New(ErrCLISessionExpired.Code(), ErrCLISessionExpired.Message(), err))
+	}
+	p.refreshed, p.refreshedFrom = r, token
+
+	v.SessionToken = r.AccessToken
+	p.SetExpiration(time.Unix(r.Expiration, 0), p.ExpiryWindow)
+	return v, nil
+}
+
+func (p *CLISessionProvider) filename() string {
+	if len(p.Filename) != 0 {
+		return p.Filename
+	}
+
+	home := os.Getenv("IBMCLOUD_HOME")
+	if len(home) == 0 {
+		home = os.Getenv("BLUEMIX_HOME")
+	}
+	if len(home) == 0 {
+		home = shareddefaults.UserHomeDir()
+	}
+
+	return filepath.Join(home, ".bluemix", "config.json")
+}
+
+func (p *CLISessionProvider) loadConfig() (*cliConfig, error) {
+	filename := p.filename()
+
+	b, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, newCredentialsError(ErrMissingConfiguration, ErrCLISessionNotFound)
+	} else if err != nil {
+		return nil, awserr.New("CLISessionError",
+			fmt.Sprintf("failed to read IBM Cloud CLI config, %s", filename), err)
+	}
+
+	cfg := &cliConfig{}
+	if err := json.Unmarshal(b, cfg); err != nil {
+		return nil, awserr.New("CLISessionError",
+			fmt.Sprintf("failed to parse IBM Cloud CLI config, %s", filename), err)
+	}
+
+	return cfg, nil
+}
+
+type cliRefreshOutput struct {
+	Expiration  int64  `json:"expiration"`
+	AccessToken string `json:"access_token"`
+}
+
+func (p *CLISessionProvider) refresh(cfg *cliConfig) (*cliRefreshOutput, error) {
+	endpoint := p.IAMEndpoint
+	if len(endpoint) == 0 {
+		endpoint = cfg.IAMEndpoint
+	}
+	if len(endpoint) == 0 {
+		endpoint = defaultCLIIAMEndpoint
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	body := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {cfg.IAMRefreshToken},
+	}
+	req, err := http.NewRequest("POST", strings.TrimRight(endpoint, "/")+"/identity/token",
+		strings.NewReader(body.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(cliRefreshClientID, cliRefreshClientID)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		respErr := &iamResponseError{StatusCode: resp.StatusCode}
+		json.NewDecoder(resp.Body).Decode(respErr)
+		return nil, respErr
+	}
+
+	out := &cliRefreshOutput{}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, err
+	}
+	if len(out.AccessToken) == 0 {
+		return nil, fmt.Errorf("server returned no access token")
+	}
+
+	return out, nil
+}
@@ -0,0 +1,184 @@
+package ibmcreds_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+	"github.com/aws/aws-sdk-go/awstesting"
+)
+
+func writeCLIConfig(t *testing.T, dir string, cfg map[string]string) string {
+	if err := os.MkdirAll(filepath.Join(dir, ".bluemix"), 0700); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	b, _ := json.Marshal(cfg)
+	filename := filepath.Join(dir, ".bluemix", "config.json")
+	if err := ioutil.WriteFile(filename, b, 0600); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return filename
+}
+
+func newCLIToken(t *testing.T, ttl time.Duration) string {
+	token, err := ibmcredstest.NewJWT(ibmcredstest.NewClaims("account", time.Now(), ttl))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return token
+}
+
+func TestCLISessionCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ibmcreds")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	oldEnv := awstesting.StashEnv()
+	defer awstesting.PopEnv(oldEnv)
+	os.Setenv("IBMCLOUD_HOME", dir)
+
+	token := newCLIToken(t, time.Hour)
+	writeCLIConfig(t, dir, map[string]string{"IAMToken": "Bearer " + token})
+
+	creds := ibmcreds.NewCLISessionCredentials("instanceID")
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := token, v.SessionToken; e != a {
+		t.Errorf("expect %v token, got %v", e, a)
+	}
+	if e, a := "instanceID", v.ServiceInstanceID; e != a {
+		t.Errorf("expect %v service instance ID, got %v", e, a)
+	}
+	if e, a := ibmcreds.CLISessionProviderName, v.ProviderName; e != a {
+		t.Errorf("expect %v provider name, got %v", e, a)
+	}
+	if e, a := "ibm-iam", creds.GetCredentialsType(); e != a {
+		t.Errorf("expect %v credentials type, got %v", e, a)
+	}
+	if creds.IsExpired() {
+		t.Errorf("expect credentials to not be expired")
+	}
+}
+
+func TestCLISessionCredentials_Refresh(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+
+	// Obtain a refresh token issued by the server.
+	if _, err := ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL).Get(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	refreshToken := server.Tokens()[0].RefreshToken
+
+	dir, err := ioutil.TempDir("", "ibmcreds")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	expired := newCLIToken(t, -time.Minute)
+	filename := writeCLIConfig(t, dir, map[string]string{
+		"IAMEndpoint":     server.URL,
+		"IAMToken":        "Bearer " + expired,
+		"IAMRefreshToken": refreshToken,
+	})
+	before, _ := ioutil.ReadFile(filename)
+
+	creds := ibmcreds.NewCLISessionCredentials("instanceID", func(p *ibmcreds.CLISessionProvider) {
+		p.Filename = filename
+	})
+	for i := 0; i < 2; i++ {
+		v, err := creds.Get()
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		if e, a := server.Tokens()[1].AccessToken, v.SessionToken; e != a {
+			t.Errorf("%d, expect refreshed %v token, got %v", i, e, a)
+		}
+		creds.Expire()
+	}
+
+	reqs := server.Requests()
+	if e, a := 2, len(reqs); e != a {
+		t.Fatalf("expect %v requests, got %v", e, a)
+	}
+	if e, a := ibmcredstest.IdentityTokenPath, reqs[1].Path; e != a {
+		t.Errorf("expect %v path, got %v", e, a)
+	}
+	if e, a := ibmcredstest.RefreshTokenGrantType, reqs[1].GrantType; e != a {
+		t.Errorf("expect %v grant type, got %v", e, a)
+	}
+
+	after, _ := ioutil.ReadFile(filename)
+	if e, a := string(before), string(after); e != a {
+		t.Errorf("expect CLI config to not be modified")
+	}
+}
+
+func TestCLISessionCredentials_Errors(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "ibmcreds")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cases := map[string]struct {
+		Config map[string]string
+		Code   string
+	}{
+		"no config": {
+			Code: "CLISessionNotFound",
+		},
+		"logged out": {
+			Config: map[string]string{"IAMToken": ""},
+			Code:   "CLISessionNotFound",
+		},
+		"expired": {
+			Config: map[string]string{"IAMToken": newCLIToken(t, -time.Minute)},
+			Code:   "CLISessionExpired",
+		},
+		"invalid refresh token": {
+			Config: map[string]string{
+				"IAMEndpoint":     server.URL,
+				"IAMToken":        newCLIToken(t, -time.Minute),
+				"IAMRefreshToken": "invalid",
+			},
+			Code: "CLISessionExpired",
+		},
+		"opaque token": {
+			Config: map[string]string{"IAMToken": "opaque"},
+			Code:   "CLISessionError",
+		},
+	}
+
+	for name, c := range cases {
+		home := filepath.Join(dir, name)
+		if c.Config != nil {
+			writeCLIConfig(t, home, c.Config)
+		}
+
+		creds := ibmcreds.NewCLISessionCredentials("instanceID", func(p *ibmcreds.CLISessionProvider) {
+			p.Filename = filepath.Join(home, ".bluemix", "config.json")
+		})
+		_, err := creds.Get()
+		if err == nil {
+			t.Fatalf("%s, expect error, got none", name)
+		}
+		if e, a := c.Code, err.(awserr.Error).Code(); e != a {
+			t.Errorf("%s, expect %v error code, got %v", name, e, a)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+// +build go1.7
+
+package ibmcreds
+
+import (
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// requestWithContext returns a shallow copy of the request made with the
+// context.
+func requestWithContext(req *http.Request, ctx credentials.Context) *http.Request {
+	return req.WithContext(ctx)
+}
@@ -0,0 +1,256 @@
+package ibmcreds
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// EndpointProviderName is the name of the credentials provider retrieving
+// credentials from a local HTTP endpoint.
+const EndpointProviderName = "IBMCredentialsEndpointProvider"
+
+const (
+	// EndpointEnvVar is the environment variable the EndpointProvider reads
+	// the credentials endpoint URI from if its Endpoint is not set.
+	EndpointEnvVar = "IBM_CREDENTIALS_FULL_URI"
+
+	// EndpointAuthorizationEnvVar is the environment variable the
+	// EndpointProvider reads the Authorization header value sent to the
+	// credentials endpoint from, if its AuthorizationToken is not set.
+	EndpointAuthorizationEnvVar = "IBM_CREDENTIALS_AUTHORIZATION_TOKEN"
+)
+
+// EndpointProvider is a credentials.Provider which retrieves IBM IAM
+// credentials from a local HTTP endpoint, such as a credentials sidecar
+// injected by the platform the application runs on. This mirrors the
+// container credentials endpoint of the endpointcreds package.
+//
+// The endpoint may return either an IAM access token,
+//
+//     {
+//         "access_token": "eyJraWQiOi...",
+//         "expiration": 1507000000,
+//         "service_instance_id": "..."
+//     }
+//
+// or an API key, which is exchanged for an IAM access token with the IAM
+// endpoint.
+//
+//     {
+//         "apikey": "...",
+//         "service_instance_id": "..."
+//     }
+//
+// The expiration and service_instance_id values are optional. If the access
+// token's expiration is not provided, it is read from the token's exp claim.
+// The service instance ID returned overrides the provider's ServiceInstanceID.
+//
+// Errors should be returned with 400 or 500 HTTP status codes, in the
+// following format.
+//
+//     {
+//         "code": "ErrorCode",
+//         "message": "Helpful error message."
+//     }
+type EndpointProvider struct {
+	credentials.Expiry
+
+	// Endpoint is the URI of the credentials endpoint. Only endpoints on
+	// localhost, or a loopback address, are allowed.
+	//
+	// If empty will use the value of the "IBM_CREDENTIALS_FULL_URI" env
+	// variable.
+	Endpoint string
+
+	// AuthorizationToken is the Authorization header value sent to the
+	// credentials endpoint.
+	//
+	// If empty will use the value of the "IBM_CREDENTIALS_AUTHORIZATION_TOKEN"
+	// env variable.
+	AuthorizationToken string
+
+	ServiceInstanceID string
+
+	// IAMEndpoint is the IAM endpoint API keys returned by the credentials
+	// endpoint are exchanged with.
+	IAMEndpoint string
+
+	// Client is the HTTP client used to request credentials from the
+	// credentials endpoint, and tokens from the IAM endpoint. Defaults to
+	// http.DefaultClient if not set.
+	Client *http.Client
+
+	// ExpiryWindow will allow the credentials to trigger refreshing prior to
+	// the token actually expiring.
+	//
+	// If ExpiryWindow is 0 or less it will be ignored.
+	ExpiryWindow time.Duration
+}
+
+// NewEndpointCredentials returns a Credentials wrapper retrieving IBM IAM
+// credentials from the local HTTP endpoint provided. If the endpoint is empty
+// the "IBM_CREDENTIALS_FULL_URI" env variable is used.
+//
+//     sess := session.Must(session.NewSession(&aws.Config{
+//         Credentials: ibmcreds.NewEndpointCredentials("", serviceInstanceID),
+//     }))
+func NewEndpointCredentials(endpoint, serviceInstanceID string, options ...func(*EndpointProvider)) *credentials.Credentials {
+	p := &EndpointProvider{
+		Endpoint:          endpoint,
+		ServiceInstanceID: serviceInstanceID,
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return credentials.NewTypedCredentials(p, "ibm-iam")
+}
+
+// Retrieve requests credentials from the credentials endpoint, exchanging an
+// API key returned for an IAM access token.
+func (p *EndpointProvider) Retrieve() (credentials.Value, error) {
+	return p.RetrieveWithContext(aws.BackgroundContext())
+}
+
+// RetrieveWithContext requests credentials from the credentials endpoint the
+// same as Retrieve, canceling the requests to the credentials and IAM
+// endpoints if the context is canceled.
+func (p *EndpointProvider) RetrieveWithContext(ctx credentials.Context) (credentials.Value, error) {
+	v := credentials.Value{
+		ServiceInstanceID: p.ServiceInstanceID,
+		ProviderName:      EndpointProviderName,
+	}
+
+	resp, err := p.getCredentials(ctx)
+	if err == aws.ErrMissingEndpoint {
+		return v, newCredentialsError(ErrMissingConfiguration,
+			awserr.New("CredentialsEndpointError", "failed to load credentials", err))
+	} else if err != nil {
+		return v, awserr.New("CredentialsEndpointError", "failed to load credentials", err)
+	}
+	if len(resp.ServiceInstanceID) != 0 {
+		v.ServiceInstanceID = resp.ServiceInstanceID
+	}
+
+	switch {
+	case len(resp.AccessToken) != 0:
+		v.SessionToken = resp.AccessToken
+		if resp.Expiration != 0 {
+			p.SetExpiration(time.Unix(resp.Expiration, 0), p.ExpiryWindow)
+		} else if exp, ok := jwtExpiration(resp.AccessToken); ok {
+			p.SetExpiration(exp, p.ExpiryWindow)
+		} else {
+			p.SetExpiration(time.Time{}, 0)
+		}
+
+	case len(resp.APIKey) != 0:
+		iam := &Provider{
+			apiKey:      resp.APIKey,
+			IAMEndpoint: p.IAMEndpoint,
+			Client:      p.Client,
+		}
+		token, err := iam.getCredentials(ctx)
+		if err != nil {
+			return v, newCredentialsError(iamErrorClass(err),
+				awserr.New("CredentialsEndpointError", "failed to exchange API key from credentials endpoint", err))
+		}
+		v.SessionToken = token.AccessToken
+		p.SetExpiration(time.Unix(token.Expiration, 0), p.ExpiryWindow)
+
+	default:
+		return v, awserr.New("CredentialsEndpointError",
+			"credentials endpoint returned neither an access token nor an API key", nil)
+	}
+
+	return v, nil
+}
+
+type getEndpointCredentialsOutput struct {
+	AccessToken       string `json:"access_token"`
+	Expiration        int64  `json:"expiration"`
+	APIKey            string `json:"apikey"`
+	ServiceInstanceID string `json:"service_instance_id"`
+}
+
+type endpointErrorOutput struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (p *EndpointProvider) endpoint() (string, error) {
+	endpoint := p.Endpoint
+	if len(endpoint) == 0 {
+		endpoint = os.Getenv(EndpointEnvVar)
+	}
+	if len(endpoint) == 0 {
+		return "", aws.ErrMissingEndpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL, %v", err)
+	}
+	if host := aws.URLHostname(u); !(host == "localhost" || host == "127.0.0.1" || host == "::1") {
+		return "", fmt.Errorf("invalid host address, %q, only localhost and loopback addresses are valid", host)
+	}
+
+	return endpoint, nil
+}
+
+func (p *EndpointProvider) getCredentials(ctx credentials.Context) (*getEndpointCredentialsOutput, error) {
+	endpoint, err := p.endpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = requestWithContext(req, ctx)
+	req.Header.Set("Accept", "application/json")
+
+	auth := p.AuthorizationToken
+	if len(auth) == 0 {
+		auth = os.Getenv(EndpointAuthorizationEnvVar)
+	}
+	if len(auth) != 0 {
+		req.Header.Set("Authorization", auth)
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		var errOut endpointErrorOutput
+		if err := json.NewDecoder(resp.Body).Decode(&errOut); err != nil || len(errOut.Code) == 0 {
+			return nil, fmt.Errorf("server returned status %d instead of 200", resp.StatusCode)
+		}
+		return nil, awserr.New(errOut.Code, errOut.Message, nil)
+	}
+
+	out := &getEndpointCredentialsOutput{}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, awserr.New("SerializationError",
+			"failed to decode endpoint credentials", err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,116 @@
+package ibmcreds_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+	"github.com/aws/aws-sdk-go/awstesting"
+)
+
+func TestEndpointCredentials_AccessToken(t *testing.T) {
+	token := newCLIToken(t, time.Hour)
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		fmt.Fprintf(w, `{"access_token":%q,"service_instance_id":"sidecarInstanceID"}`, token)
+	}))
+	defer server.Close()
+
+	oldEnv := awstesting.StashEnv()
+	defer awstesting.PopEnv(oldEnv)
+	os.Setenv(ibmcreds.EndpointEnvVar, server.URL+"/credentials")
+	os.Setenv(ibmcreds.EndpointAuthorizationEnvVar, "Basic abc123")
+
+	creds := ibmcreds.NewEndpointCredentials("", "instanceID")
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := token, v.SessionToken; e != a {
+		t.Errorf("expect %v token, got %v", e, a)
+	}
+	if e, a := "sidecarInstanceID", v.ServiceInstanceID; e != a {
+		t.Errorf("expect %v service instance ID, got %v", e, a)
+	}
+	if e, a := ibmcreds.EndpointProviderName, v.ProviderName; e != a {
+		t.Errorf("expect %v provider name, got %v", e, a)
+	}
+	if e, a := "Basic abc123", auth; e != a {
+		t.Errorf("expect %v authorization, got %v", e, a)
+	}
+	if e, a := "ibm-iam", creds.GetCredentialsType(); e != a {
+		t.Errorf("expect %v credentials type, got %v", e, a)
+	}
+	if creds.IsExpired() {
+		t.Errorf("expect credentials to not be expired")
+	}
+}
+
+func TestEndpointCredentials_APIKey(t *testing.T) {
+	iam := ibmcredstest.NewServer(func(s *ibmcredstest.Server) {
+		s.APIKey = "sidecarAPIKey"
+	})
+	defer iam.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"apikey":"sidecarAPIKey"}`)
+	}))
+	defer server.Close()
+
+	creds := ibmcreds.NewEndpointCredentials(server.URL, "instanceID", func(p *ibmcreds.EndpointProvider) {
+		p.IAMEndpoint = iam.URL
+	})
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := iam.Tokens()[0].AccessToken, v.SessionToken; e != a {
+		t.Errorf("expect %v token, got %v", e, a)
+	}
+	if e, a := "instanceID", v.ServiceInstanceID; e != a {
+		t.Errorf("expect %v service instance ID, got %v", e, a)
+	}
+	if creds.IsExpired() {
+		t.Errorf("expect credentials to not be expired")
+	}
+}
+
+func TestEndpointCredentials_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/error":
+			w.WriteHeader(400)
+			fmt.Fprint(w, `{"code":"Unauthorized","message":"not allowed"}`)
+		case "/empty":
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+	defer server.Close()
+
+	oldEnv := awstesting.StashEnv()
+	defer awstesting.PopEnv(oldEnv)
+
+	cases := map[string]string{
+		"no endpoint":     "",
+		"remote endpoint": "http://169.254.170.2/credentials",
+		"error response":  server.URL + "/error",
+		"empty response":  server.URL + "/empty",
+	}
+
+	for name, endpoint := range cases {
+		_, err := ibmcreds.NewEndpointCredentials(endpoint, "instanceID").Get()
+		if err == nil {
+			t.Fatalf("%s, expect error, got none", name)
+		}
+		if e, a := "CredentialsEndpointError", err.(awserr.Error).Code(); e != a {
+			t.Errorf("%s, expect %v error code, got %v", name, e, a)
+		}
+	}
+}
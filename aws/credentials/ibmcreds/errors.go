@@ -0,0 +1,103 @@
+package ibmcreds
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+// Classes of credential failures. Errors returned by the providers of this
+// package are CredentialsErrors, which match their class with errors.Is.
+//
+//     _, err := creds.Get()
+//     if errors.Is(err, ibmcreds.ErrInvalidAPIKey) {
+//         // prompt for a new API key
+//     }
+var (
+	// ErrInvalidAPIKey is the class of errors caused by IAM rejecting the
+	// API key, or token, credentials were requested with.
+	ErrInvalidAPIKey = awserr.New("InvalidAPIKey", "IAM rejected the API key", nil)
+
+	// ErrIAMUnreachable is the class of errors caused by failing to reach the
+	// IAM endpoint, or the IAM endpoint failing to respond successfully.
+	ErrIAMUnreachable = awserr.New("IAMUnreachable", "IAM endpoint unreachable", nil)
+
+	// ErrTokenExpired is the class of errors caused by an IAM token which has
+	// expired and cannot be refreshed.
+	ErrTokenExpired = awserr.New("TokenExpired", "IAM token expired", nil)
+
+	// ErrMissingConfiguration is the class of errors caused by the provider
+	// missing the configuration needed to retrieve credentials.
+	ErrMissingConfiguration = awserr.New("MissingConfiguration",
+		"credentials configuration missing", nil)
+)
+
+// So that the awserr.Error interface type can be included as an anonymous
+// field in the CredentialsError struct and not conflict with the
+// error.Error() method.
+type awsError awserr.Error
+
+// A CredentialsError is an awserr.Error returned by the providers of this
+// package, classifying the failure. The error's Code, Message, and OrigErr are
+// those of the underlying error, so existing code checks are unchanged.
+//
+// Use errors.Is with the class, one of ErrInvalidAPIKey, ErrIAMUnreachable,
+// ErrTokenExpired, or ErrMissingConfiguration, to branch on the failure, or
+// errors.As to retrieve the CredentialsError.
+type CredentialsError struct {
+	awsError
+
+	// Class of the failure.
+	Class awserr.Error
+}
+
+func newCredentialsError(class, err awserr.Error) *CredentialsError {
+	return &CredentialsError{awsError: err, Class: class}
+}
+
+// Error returns the string representation of the error.
+func (e *CredentialsError) Error() string {
+	return e.awsError.Error()
+}
+
+// String returns the string representation of the error.
+func (e *CredentialsError) String() string {
+	return e.Error()
+}
+
+// Is returns whether the target is the error's class.
+func (e *CredentialsError) Is(target error) bool {
+	return target == e.Class
+}
+
+// Unwrap returns the underlying error, so that it and its original errors
+// are part of the error's chain.
+func (e *CredentialsError) Unwrap() error {
+	return e.awsError
+}
+
+// iamResponseError is an unsuccessful response of an IAM token endpoint.
+type iamResponseError struct {
+	StatusCode int
+	Code       string `json:"errorCode"`
+	Message    string `json:"errorMessage"`
+}
+
+func (e *iamResponseError) Error() string {
+	msg := fmt.Sprintf("server returned status %d instead of 200", e.StatusCode)
+	if len(e.Code) != 0 {
+		msg = fmt.Sprintf("%s, %s: %s", msg, e.Code, e.Message)
+	}
+	return msg
+}
+
+// iamErrorClass returns the class of an error requesting a token from IAM.
+// Client errors are caused by the credentials requested with, and all other
+// failures by IAM itself.
+func iamErrorClass(err error) awserr.Error {
+	if respErr, ok := err.(*iamResponseError); ok &&
+		respErr.StatusCode >= 400 && respErr.StatusCode < 500 {
+		return ErrInvalidAPIKey
+	}
+	return ErrIAMUnreachable
+}
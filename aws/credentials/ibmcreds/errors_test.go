@@ -0,0 +1,156 @@
+// +build go1.13
+
+package ibmcreds_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestCredentialsErrorClass(t *testing.T) {
+	server := ibmcredstest.NewServer(func(s *ibmcredstest.Server) {
+		s.APIKey = "apikey"
+	})
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "ibmcreds")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+	writeCLIConfig(t, dir, map[string]string{"IAMToken": newCLIToken(t, -time.Minute)})
+
+	cases := map[string]struct {
+		Creds    *credentials.Credentials
+		Setup    func()
+		Class    error
+		Code     string
+		NotClass []error
+	}{
+		"invalid API key": {
+			Creds: ibmcreds.NewCredentialsClient("other", "instanceID", server.URL),
+			Class: ibmcreds.ErrInvalidAPIKey,
+			Code:  "CredentialsEndpointError",
+		},
+		"missing API key": {
+			Creds: ibmcreds.NewCredentialsClient("", "instanceID", server.URL),
+			Class: ibmcreds.ErrMissingConfiguration,
+			Code:  "CredentialsEndpointError",
+		},
+		"IAM unavailable": {
+			Creds: ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL),
+			Setup: func() { server.FailNext(1, 503, "BXNIM0400E", "unavailable") },
+			Class: ibmcreds.ErrIAMUnreachable,
+			Code:  "CredentialsEndpointError",
+		},
+		"IAM unreachable": {
+			Creds: ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL),
+			Setup: func() { server.DropNext(1) },
+			Class: ibmcreds.ErrIAMUnreachable,
+			Code:  "CredentialsEndpointError",
+		},
+		"CLI session expired": {
+			Creds: ibmcreds.NewCLISessionCredentials("instanceID", func(p *ibmcreds.CLISessionProvider) {
+				p.Filename = filepath.Join(dir, ".bluemix", "config.json")
+			}),
+			Class: ibmcreds.ErrTokenExpired,
+			Code:  "CLISessionExpired",
+		},
+		"CLI session not found": {
+			Creds: ibmcreds.NewCLISessionCredentials("instanceID", func(p *ibmcreds.CLISessionProvider) {
+				p.Filename = filepath.Join(dir, "missing.json")
+			}),
+			Class: ibmcreds.ErrMissingConfiguration,
+			Code:  "CLISessionNotFound",
+		},
+		"trusted profile not set": {
+			Creds: ibmcreds.AssumeTrustedProfile(
+				ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL), ""),
+			Class: ibmcreds.ErrMissingConfiguration,
+			Code:  "InvalidTrustedProfile",
+		},
+	}
+
+	classes := []error{
+		ibmcreds.ErrInvalidAPIKey, ibmcreds.ErrIAMUnreachable,
+		ibmcreds.ErrTokenExpired, ibmcreds.ErrMissingConfiguration,
+	}
+
+	for name, c := range cases {
+		if c.Setup != nil {
+			c.Setup()
+		}
+
+		_, err := c.Creds.Get()
+		if err == nil {
+			t.Fatalf("%s, expect error, got none", name)
+		}
+		for _, class := range classes {
+			if e, a := class == c.Class, errors.Is(err, class); e != a {
+				t.Errorf("%s, expect errors.Is %v to be %v, got %v", name, class.(awserr.Error).Code(), e, a)
+			}
+		}
+
+		var credsErr *ibmcreds.CredentialsError
+		if !errors.As(err, &credsErr) {
+			t.Fatalf("%s, expect CredentialsError, got %T", name, err)
+		}
+		if e, a := c.Class, credsErr.Class; e != a {
+			t.Errorf("%s, expect %v class, got %v", name, e, a)
+		}
+		if e, a := c.Code, err.(awserr.Error).Code(); e != a {
+			t.Errorf("%s, expect %v error code, got %v", name, e, a)
+		}
+	}
+}
+
+func TestCredentialsErrorIsUnderlying(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ibmcreds")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	creds := ibmcreds.NewCLISessionCredentials("instanceID", func(p *ibmcreds.CLISessionProvider) {
+		p.Filename = filepath.Join(dir, "missing.json")
+	})
+	_, err = creds.Get()
+	if !errors.Is(err, ibmcreds.ErrCLISessionNotFound) {
+		t.Errorf("expect error to be %v, got %v", ibmcreds.ErrCLISessionNotFound, err)
+	}
+}
+
+func TestCredentialsErrorThroughRequest(t *testing.T) {
+	server := ibmcredstest.NewServer(func(s *ibmcredstest.Server) {
+		s.APIKey = "apikey"
+	})
+	defer server.Close()
+
+	svc := s3.New(unit.Session, &aws.Config{
+		Credentials: ibmcreds.NewCredentialsClient("other", "instanceID", server.URL),
+		Region:      aws.String("us-south"),
+		MaxRetries:  aws.Int(0),
+	})
+	_, err := svc.ListBuckets(&s3.ListBucketsInput{})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if !errors.Is(err, ibmcreds.ErrInvalidAPIKey) {
+		t.Errorf("expect error to be %v, got %v", ibmcreds.ErrInvalidAPIKey, err)
+	}
+	if e, a := "CredentialsEndpointError", err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
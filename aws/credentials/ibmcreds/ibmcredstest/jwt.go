@@ -0,0 +1,137 @@
+package ibmcredstest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// jwtSignature is the placeholder signature of the tokens minted. Tokens are
+// syntactically valid, but their signatures cannot be verified.
+var jwtSignature = []byte("ibmcredstest-signature")
+
+// claimNames are the JSON names of the Claims fields.
+var claimNames = []string{
+	"iam_id", "id", "realmid", "sub", "sub_type", "account", "grant_type",
+	"scope", "client_id", "iss", "iat", "exp",
+}
+
+// Account is the account claim of an IAM access token.
+type Account struct {
+	BSS string `json:"bss,omitempty"`
+}
+
+// Claims are the claims of an IAM style JWT access token. Claims with zero
+// values are omitted from the token.
+type Claims struct {
+	IAMID     string   `json:"iam_id,omitempty"`
+	ID        string   `json:"id,omitempty"`
+	RealmID   string   `json:"realmid,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	SubType   string   `json:"sub_type,omitempty"`
+	Account   *Account `json:"account,omitempty"`
+	GrantType string   `json:"grant_type,omitempty"`
+	Scope     string   `json:"scope,omitempty"`
+	ClientID  string   `json:"client_id,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+
+	// Extra are additional claims to include in the token. Extra claims
+	// override the claims above with the same name.
+	Extra map[string]interface{} `json:"-"`
+}
+
+// NewClaims returns the claims of a typical IAM access token issued from an
+// API key, for the account ID provided. The token is issued at the time
+// provided, and expires after the TTL.
+func NewClaims(accountID string, issued time.Time, ttl time.Duration) Claims {
+	return Claims{
+		IAMID:     "iam-ServiceId-ibmcredstest",
+		ID:        "iam-ServiceId-ibmcredstest",
+		RealmID:   "iam",
+		Subject:   "ServiceId-ibmcredstest",
+		SubType:   "ServiceId",
+		Account:   &Account{BSS: accountID},
+		GrantType: APIKeyGrantType,
+		Scope:     "ibm openid",
+		ClientID:  "default",
+		Issuer:    "https://iam.cloud.ibm.com/identity",
+		IssuedAt:  issued.Unix(),
+		ExpiresAt: issued.Add(ttl).Unix(),
+	}
+}
+
+// NewJWT returns a syntactically valid JWT with the claims provided. The
+// token's signature is a placeholder, and cannot be verified.
+func NewJWT(claims Claims) (string, error) {
+	header, err := json.Marshal(map[string]string{
+		"kid": "ibmcredstest",
+		"alg": "RS256",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := marshalClaims(claims)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{
+		base64.RawURLEncoding.EncodeToString(header),
+		base64.RawURLEncoding.EncodeToString(payload),
+		base64.RawURLEncoding.EncodeToString(jwtSignature),
+	}, "."), nil
+}
+
+// ParseJWTClaims decodes the claims of a JWT without verifying its signature.
+// Claims which are not fields of Claims are returned in Extra.
+func ParseJWTClaims(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, fmt.Errorf("ibmcredstest: malformed JWT, expect 3 parts, got %d", len(parts))
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Claims{}, fmt.Errorf("ibmcredstest: malformed JWT payload, %v", err)
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return Claims{}, fmt.Errorf("ibmcredstest: malformed JWT claims, %v", err)
+	}
+
+	var all map[string]interface{}
+	if err := json.Unmarshal(payload, &all); err != nil {
+		return Claims{}, fmt.Errorf("ibmcredstest: malformed JWT claims, %v", err)
+	}
+	for _, name := range claimNames {
+		delete(all, name)
+	}
+	if len(all) > 0 {
+		claims.Extra = all
+	}
+
+	return claims, nil
+}
+
+func marshalClaims(claims Claims) ([]byte, error) {
+	b, err := json.Marshal(claims)
+	if err != nil || len(claims.Extra) == 0 {
+		return b, err
+	}
+
+	var all map[string]interface{}
+	if err := json.Unmarshal(b, &all); err != nil {
+		return nil, err
+	}
+	for k, v := range claims.Extra {
+		all[k] = v
+	}
+
+	return json.Marshal(all)
+}
@@ -0,0 +1,95 @@
+package ibmcredstest_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+)
+
+func TestNewJWT(t *testing.T) {
+	issued := time.Date(2017, 10, 1, 0, 0, 0, 0, time.UTC)
+	claims := ibmcredstest.NewClaims("account-id", issued, time.Hour)
+	claims.Extra = map[string]interface{}{"custom": "value"}
+
+	token, err := ibmcredstest.NewJWT(claims)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	if e, a := 3, len(parts); e != a {
+		t.Fatalf("expect %v parts, got %v", e, a)
+	}
+	for i, part := range parts {
+		if _, err := base64.RawURLEncoding.DecodeString(part); err != nil {
+			t.Errorf("%d, expect base64url encoded part, got %v", i, err)
+		}
+	}
+
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var raw map[string]interface{}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		t.Fatalf("expect JSON claims, got %v", err)
+	}
+	if e, a := float64(issued.Add(time.Hour).Unix()), raw["exp"]; e != a {
+		t.Errorf("expect %v exp, got %v", e, a)
+	}
+	if e, a := "value", raw["custom"]; e != a {
+		t.Errorf("expect %v custom claim, got %v", e, a)
+	}
+
+	parsed, err := ibmcredstest.ParseJWTClaims(token)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "account-id", parsed.Account.BSS; e != a {
+		t.Errorf("expect %v account, got %v", e, a)
+	}
+	if e, a := claims.IAMID, parsed.IAMID; e != a {
+		t.Errorf("expect %v iam_id, got %v", e, a)
+	}
+	if e, a := 1, len(parsed.Extra); e != a {
+		t.Errorf("expect %v extra claims, got %v", e, a)
+	}
+}
+
+func TestParseJWTClaims_Malformed(t *testing.T) {
+	for _, token := range []string{"", "a.b", "a.!!!.c", "a." + base64.RawURLEncoding.EncodeToString([]byte("{")) + ".c"} {
+		if _, err := ibmcredstest.ParseJWTClaims(token); err == nil {
+			t.Errorf("%q, expect error, got none", token)
+		}
+	}
+}
+
+func TestServerIssuesJWT(t *testing.T) {
+	server := ibmcredstest.NewServer(func(s *ibmcredstest.Server) {
+		s.AccountID = "account-id"
+		s.TokenTTL = 20 * time.Minute
+	})
+	defer server.Close()
+
+	creds := ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL)
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	claims, err := ibmcredstest.ParseJWTClaims(v.SessionToken)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "account-id", claims.Account.BSS; e != a {
+		t.Errorf("expect %v account, got %v", e, a)
+	}
+	if e, a := int64(20*60), claims.ExpiresAt-claims.IssuedAt; e != a {
+		t.Errorf("expect %v second lifetime, got %v", e, a)
+	}
+	if e, a := server.Tokens()[0].Expiration.Unix(), claims.ExpiresAt; e != a {
+		t.Errorf("expect %v exp, got %v", e, a)
+	}
+}
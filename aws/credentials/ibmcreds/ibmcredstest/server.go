@@ -0,0 +1,439 @@
+// Package ibmcredstest provides a fake IBM IAM token server for testing
+// applications using the ibmcreds credentials provider.
+//
+// The Server implements the IAM token API key, passcode, and refresh token
+// grants, with configurable latency, token lifetimes, and failure injection.
+// Requests made to the Server are recorded so that tests can assert on when,
+// and how, credentials were refreshed. The access tokens issued are IAM style
+// JWTs minted with NewJWT, whose claims can be decoded with ParseJWTClaims.
+//
+//     server := ibmcredstest.NewServer(func(s *ibmcredstest.Server) {
+//         s.TokenTTL = time.Minute
+//     })
+//     defer server.Close()
+//
+//     creds := ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL)
+package ibmcredstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// TokenPath is the path the Server serves IAM token requests on.
+const TokenPath = "/oidc/token"
+
+// IdentityTokenPath is the alternate path the Server serves IAM token
+// requests on.
+const IdentityTokenPath = "/identity/token"
+
+// APIKeyGrantType is the grant type of IAM API key token requests.
+const APIKeyGrantType = "urn:ibm:params:oauth:grant-type:apikey"
+
+// RefreshTokenGrantType is the grant type of IAM refresh token requests.
+// Only refresh tokens issued by the Server are accepted.
+const RefreshTokenGrantType = "refresh_token"
+
+// PasscodeGrantType is the grant type of IAM one-time passcode token
+// requests.
+const PasscodeGrantType = "urn:ibm:params:oauth:grant-type:passcode"
+
+// CRTokenGrantType is the grant type of IAM trusted profile token requests
+// with a JWT of an external identity provider, or compute resource.
+const CRTokenGrantType = "urn:ibm:params:oauth:grant-type:cr-token"
+
+// AssumeGrantType is the grant type of IAM trusted profile token requests.
+// Only access tokens issued by the Server are accepted.
+const AssumeGrantType = "urn:ibm:params:oauth:grant-type:assume"
+
+// DefaultTokenTTL is the lifetime of tokens issued by the Server when its
+// TokenTTL is not set.
+const DefaultTokenTTL = time.Hour
+
+// DefaultAccountID is the account claim of tokens issued by the Server when
+// its AccountID is not set.
+const DefaultAccountID = "ibmcredstest-account"
+
+// A Request is a token request received by the Server.
+type Request struct {
+	Time         time.Time
+	Path         string
+	Header       http.Header
+	GrantType    string
+	ResponseType string
+	APIKey       string
+	Passcode     string
+	RefreshToken string
+	AccessToken  string
+	CRToken      string
+
+	// Account is the account token requests are pinned to, if any.
+	Account string
+
+	// Profile is the profile_crn, profile_id, or profile_name of trusted
+	// profile token requests.
+	Profile string
+}
+
+// A Token is an access token issued by the Server. The AccessToken is an IAM
+// style JWT with the Claims.
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	Expiration   time.Time
+	Claims       Claims
+}
+
+type failure struct {
+	statusCode int
+	code       string
+	message    string
+}
+
+// Server is a fake IBM IAM token server. The Server's URL can be used as the
+// ibmcreds provider's IAM endpoint. The Server is safe to use concurrently,
+// and may be reconfigured with its methods while requests are being served.
+type Server struct {
+	*httptest.Server
+
+	// APIKey is the API key token requests must provide. If set, requests
+	// for other API keys fail with a 400 status code. Any API key is accepted
+	// if empty.
+	APIKey string
+
+	// Passcode is the one-time passcode passcode token requests must
+	// provide. If set, requests for other passcodes fail with a 400 status
+	// code. Any passcode is accepted if empty.
+	Passcode string
+
+	// Audience is the audience the JWTs of compute resource token requests
+	// must be issued for. If set, requests with JWTs whose aud claim does
+	// not include the Audience fail with a 400 status code. Any JWT is
+	// accepted if empty.
+	Audience string
+
+	// Latency is the duration the Server waits before responding to each
+	// request.
+	Latency time.Duration
+
+	// TokenTTL is the lifetime of the tokens issued. If zero DefaultTokenTTL
+	// will be used. A negative TTL issues tokens which have already expired.
+	TokenTTL time.Duration
+
+	// AccountID is the account claim of the tokens issued, unless the token
+	// request is pinned to another account. Defaults to DefaultAccountID.
+	AccountID string
+
+	// CurrentTime is used to determine the issue time of tokens. Defaults to
+	// time.Now.
+	CurrentTime func() time.Time
+
+	mu       sync.Mutex
+	failures []failure
+	requests []Request
+	tokens   []Token
+}
+
+// NewServer starts and returns a new Server. Functional options can be
+// provided to configure the Server before it is started. The caller should
+// call Close when finished, to shut it down.
+func NewServer(options ...func(*Server)) *Server {
+	s := &Server{}
+	for _, option := range options {
+		option(s)
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// SetLatency sets the duration the Server waits before responding.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Latency = d
+}
+
+// SetTokenTTL sets the lifetime of the tokens the Server issues.
+func (s *Server) SetTokenTTL(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.TokenTTL = d
+}
+
+// FailNext causes the next n token requests to fail with the HTTP status
+// code provided. The response body is an IAM error with the error code and
+// message provided.
+func (s *Server) FailNext(n int, statusCode int, code, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := 0; i < n; i++ {
+		s.failures = append(s.failures, failure{
+			statusCode: statusCode,
+			code:       code,
+			message:    message,
+		})
+	}
+}
+
+// DropNext causes the Server to close the connection of the next n token
+// requests without writing a response, simulating a network failure.
+func (s *Server) DropNext(n int) {
+	s.FailNext(n, 0, "", "")
+}
+
+// Requests returns the token requests received by the Server.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request{}, s.requests...)
+}
+
+// Tokens returns the tokens issued by the Server, in the order issued.
+func (s *Server) Tokens() []Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Token{}, s.tokens...)
+}
+
+// Reset clears the Server's recorded requests, issued tokens, and pending
+// failures.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = nil
+	s.requests = nil
+	s.tokens = nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != TokenPath && r.URL.Path != IdentityTokenPath {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != "POST" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	r.ParseForm()
+
+	s.mu.Lock()
+	now := s.now()
+	s.requests = append(s.requests, Request{
+		Time:         now,
+		Path:         r.URL.Path,
+		Header:       r.Header,
+		GrantType:    r.PostForm.Get("grant_type"),
+		ResponseType: r.PostForm.Get("response_type"),
+		APIKey:       r.PostForm.Get("apikey"),
+		Passcode:     r.PostForm.Get("passcode"),
+		RefreshToken: r.PostForm.Get("refresh_token"),
+		AccessToken:  r.PostForm.Get("access_token"),
+		CRToken:      r.PostForm.Get("cr_token"),
+		Account:      r.PostForm.Get("account"),
+		Profile:      formProfile(r),
+	})
+	latency := s.Latency
+	var fail *failure
+	if len(s.failures) > 0 {
+		fail = &s.failures[0]
+		s.failures = s.failures[1:]
+	}
+	s.mu.Unlock()
+
+	if latency > 0 && !waitLatency(w, r, latency) {
+		return
+	}
+
+	switch {
+	case fail != nil && fail.statusCode == 0:
+		dropConnection(w)
+		return
+	case fail != nil:
+		writeError(w, fail.statusCode, fail.code, fail.message)
+		return
+	}
+
+	switch r.PostForm.Get("grant_type") {
+	case APIKeyGrantType:
+		if len(s.APIKey) != 0 && r.PostForm.Get("apikey") != s.APIKey {
+			writeError(w, http.StatusBadRequest, "BXNIM0415E", "Provided API key could not be found.")
+			return
+		}
+	case PasscodeGrantType:
+		if len(s.Passcode) != 0 && r.PostForm.Get("passcode") != s.Passcode {
+			writeError(w, http.StatusBadRequest, "BXNIM0407E", "Provided passcode is invalid.")
+			return
+		}
+	case RefreshTokenGrantType:
+		if !s.validRefreshToken(r.PostForm.Get("refresh_token")) {
+			writeError(w, http.StatusBadRequest, "BXNIM0407E", "Provided refresh token is invalid.")
+			return
+		}
+	case AssumeGrantType:
+		if !s.validAccessToken(r.PostForm.Get("access_token")) {
+			writeError(w, http.StatusBadRequest, "BXNIM0407E", "Provided access token is invalid.")
+			return
+		}
+		if len(formProfile(r)) == 0 {
+			writeError(w, http.StatusBadRequest, "BXNIM0109E", "Property missing or empty. Operation not allowed.")
+			return
+		}
+	case CRTokenGrantType:
+		if !s.validCRToken(r.PostForm.Get("cr_token")) {
+			writeError(w, http.StatusBadRequest, "BXNIM0407E", "Provided compute resource token is invalid.")
+			return
+		}
+		if len(formProfile(r)) == 0 {
+			writeError(w, http.StatusBadRequest, "BXNIM0109E", "Property missing or empty. Operation not allowed.")
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, "BXNIM0109E", "Property missing or empty. Operation not allowed.")
+		return
+	}
+
+	token := s.issueToken(now, r.PostForm.Get("grant_type"), r.PostForm.Get("account"))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token":  token.AccessToken,
+		"refresh_token": token.RefreshToken,
+		"token_type":    "Bearer",
+		"expires_in":    int64(token.Expiration.Sub(now) / time.Second),
+		"expiration":    token.Expiration.Unix(),
+	})
+}
+
+func (s *Server) issueToken(now time.Time, grantType, account string) Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ttl := s.TokenTTL
+	if ttl == 0 {
+		ttl = DefaultTokenTTL
+	}
+
+	accountID := account
+	if len(accountID) == 0 {
+		accountID = s.AccountID
+	}
+	if len(accountID) == 0 {
+		accountID = DefaultAccountID
+	}
+
+	id := len(s.tokens) + 1
+	claims := NewClaims(accountID, now, ttl)
+	claims.GrantType = grantType
+	claims.Extra = map[string]interface{}{
+		"jti": fmt.Sprintf("ibmcredstest-token-%d", id),
+	}
+	jwt, err := NewJWT(claims)
+	if err != nil {
+		panic(fmt.Sprintf("ibmcredstest: failed to mint token, %v", err))
+	}
+
+	token := Token{
+		AccessToken:  jwt,
+		RefreshToken: fmt.Sprintf("ibmcredstest-refresh-%d", id),
+		Expiration:   time.Unix(claims.ExpiresAt, 0),
+		Claims:       claims,
+	}
+	s.tokens = append(s.tokens, token)
+
+	return token
+}
+
+func (s *Server) validRefreshToken(refreshToken string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, token := range s.tokens {
+		if token.RefreshToken == refreshToken {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) validAccessToken(accessToken string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, token := range s.tokens {
+		if token.AccessToken == accessToken {
+			return true
+		}
+	}
+	return false
+}
+
+// validCRToken returns whether the token is a JWT issued for the Server's
+// Audience.
+func (s *Server) validCRToken(token string) bool {
+	claims, err := ParseJWTClaims(token)
+	if err != nil {
+		return false
+	}
+	if len(s.Audience) == 0 {
+		return true
+	}
+
+	switch aud := claims.Extra["aud"].(type) {
+	case string:
+		return aud == s.Audience
+	case []interface{}:
+		for _, a := range aud {
+			if a == s.Audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func formProfile(r *http.Request) string {
+	for _, name := range []string{"profile_crn", "profile_id", "profile_name"} {
+		if v := r.PostForm.Get(name); len(v) != 0 {
+			return v
+		}
+	}
+	return ""
+}
+
+func (s *Server) now() time.Time {
+	if s.CurrentTime != nil {
+		return s.CurrentTime()
+	}
+	return time.Now()
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errorCode":    code,
+		"errorMessage": message,
+	})
+}
+
+func dropConnection(w http.ResponseWriter) {
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		panic("ibmcredstest: connection cannot be dropped, hijacking not supported")
+	}
+	conn, _, err := hj.Hijack()
+	if err != nil {
+		panic(fmt.Sprintf("ibmcredstest: failed to hijack connection, %v", err))
+	}
+	conn.Close()
+}
@@ -0,0 +1,23 @@
+// +build !go1.7
+
+package ibmcredstest
+
+import (
+	"net/http"
+	"time"
+)
+
+// waitLatency waits for the latency, returning false if the client closes
+// the connection first, as requests have no context before Go 1.7.
+func waitLatency(w http.ResponseWriter, r *http.Request, latency time.Duration) bool {
+	var closed <-chan bool
+	if n, ok := w.(http.CloseNotifier); ok {
+		closed = n.CloseNotify()
+	}
+	select {
+	case <-time.After(latency):
+		return true
+	case <-closed:
+		return false
+	}
+}
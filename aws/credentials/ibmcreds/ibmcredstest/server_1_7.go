@@ -0,0 +1,19 @@
+// +build go1.7
+
+package ibmcredstest
+
+import (
+	"net/http"
+	"time"
+)
+
+// waitLatency waits for the latency, returning false if the request is
+// canceled first.
+func waitLatency(w http.ResponseWriter, r *http.Request, latency time.Duration) bool {
+	select {
+	case <-time.After(latency):
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
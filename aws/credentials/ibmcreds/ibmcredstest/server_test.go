@@ -0,0 +1,126 @@
+package ibmcredstest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+)
+
+func TestServerIssuesToken(t *testing.T) {
+	server := ibmcredstest.NewServer(func(s *ibmcredstest.Server) {
+		s.APIKey = "apikey"
+		s.TokenTTL = 10 * time.Minute
+	})
+	defer server.Close()
+
+	creds := ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL)
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	tokens := server.Tokens()
+	if e, a := 1, len(tokens); e != a {
+		t.Fatalf("expect %v tokens, got %v", e, a)
+	}
+	if e, a := tokens[0].AccessToken, v.SessionToken; e != a {
+		t.Errorf("expect %v token, got %v", e, a)
+	}
+	if e, a := "instanceID", v.ServiceInstanceID; e != a {
+		t.Errorf("expect %v service instance ID, got %v", e, a)
+	}
+
+	reqs := server.Requests()
+	if e, a := 1, len(reqs); e != a {
+		t.Fatalf("expect %v requests, got %v", e, a)
+	}
+	if e, a := ibmcredstest.APIKeyGrantType, reqs[0].GrantType; e != a {
+		t.Errorf("expect %v grant type, got %v", e, a)
+	}
+	if e, a := "apikey", reqs[0].APIKey; e != a {
+		t.Errorf("expect %v API key, got %v", e, a)
+	}
+}
+
+func TestServerInvalidAPIKey(t *testing.T) {
+	server := ibmcredstest.NewServer(func(s *ibmcredstest.Server) {
+		s.APIKey = "apikey"
+	})
+	defer server.Close()
+
+	creds := ibmcreds.NewCredentialsClient("other", "instanceID", server.URL)
+	if _, err := creds.Get(); err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := 0, len(server.Tokens()); e != a {
+		t.Errorf("expect %v tokens, got %v", e, a)
+	}
+}
+
+func TestServerFailureInjection(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+
+	server.FailNext(1, 500, "BXNIM0400E", "internal error")
+	server.DropNext(1)
+
+	creds := ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL)
+	for i := 0; i < 2; i++ {
+		if _, err := creds.Get(); err == nil {
+			t.Errorf("%d, expect error, got none", i)
+		}
+	}
+	if _, err := creds.Get(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := 3, len(server.Requests()); e != a {
+		t.Errorf("expect %v requests, got %v", e, a)
+	}
+	if e, a := 1, len(server.Tokens()); e != a {
+		t.Errorf("expect %v tokens, got %v", e, a)
+	}
+}
+
+func TestServerTokenExpiry(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+	server.SetTokenTTL(-time.Minute)
+
+	creds := ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL)
+	if _, err := creds.Get(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !creds.IsExpired() {
+		t.Errorf("expect credentials issued with %v TTL to be expired", -time.Minute)
+	}
+
+	server.SetTokenTTL(time.Hour)
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := server.Tokens()[1].AccessToken, v.SessionToken; e != a {
+		t.Errorf("expect refreshed %v token, got %v", e, a)
+	}
+	if creds.IsExpired() {
+		t.Errorf("expect refreshed credentials to not be expired")
+	}
+}
+
+func TestServerLatency(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+	server.SetLatency(50 * time.Millisecond)
+
+	creds := ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL)
+	start := time.Now()
+	if _, err := creds.Get(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expect request to take at least the server latency, took %v", elapsed)
+	}
+}
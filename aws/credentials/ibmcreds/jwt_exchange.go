@@ -0,0 +1,262 @@
+package ibmcreds
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// JWTExchangeProviderName is the name of the credentials provider exchanging
+// JWTs of external identity providers for IAM access tokens.
+const JWTExchangeProviderName = "IBMJWTExchangeProvider"
+
+// ErrCodeJWTExchange is the error code returned when a JWT cannot be fetched,
+// or exchanged for an IAM access token.
+const ErrCodeJWTExchange = "JWTExchangeError"
+
+// The environment variables GitHub Actions provides to jobs with the
+// id-token: write permission, to request OIDC tokens.
+const (
+	githubActionsTokenURLEnvVar   = "ACTIONS_ID_TOKEN_REQUEST_URL"
+	githubActionsTokenTokenEnvVar = "ACTIONS_ID_TOKEN_REQUEST_TOKEN"
+)
+
+// A JWTFetcher fetches the JWT of an external identity provider, issued for
+// the audience, to exchange for an IAM access token.
+type JWTFetcher interface {
+	FetchJWT(ctx credentials.Context, audience string) (string, error)
+}
+
+// JWTFetcherFunc is a function implementing JWTFetcher.
+type JWTFetcherFunc func(ctx credentials.Context, audience string) (string, error)
+
+// FetchJWT calls the function.
+func (fn JWTFetcherFunc) FetchJWT(ctx credentials.Context, audience string) (string, error) {
+	return fn(ctx, audience)
+}
+
+// JWTFile is a JWTFetcher reading the JWT from the file of the path, such as
+// a Kubernetes projected service account token, or the JWT-SVID written by a
+// SPIFFE helper. The file is read for each fetch, so tokens rotated in the
+// file are picked up. The audience of the token is configured by the writer
+// of the file.
+type JWTFile string
+
+// FetchJWT reads the JWT from the file.
+func (f JWTFile) FetchJWT(ctx credentials.Context, audience string) (string, error) {
+	b, err := ioutil.ReadFile(string(f))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// GitHubActionsJWTFetcher is a JWTFetcher requesting an OIDC token of the
+// GitHub Actions job the process runs in. The job must be granted the
+// id-token: write permission.
+type GitHubActionsJWTFetcher struct {
+	// RequestURL is the URL tokens are requested from. Defaults to the
+	// ACTIONS_ID_TOKEN_REQUEST_URL environment variable.
+	RequestURL string
+
+	// RequestToken is the bearer token tokens are requested with. Defaults
+	// to the ACTIONS_ID_TOKEN_REQUEST_TOKEN environment variable.
+	RequestToken string
+
+	// Client is the HTTP client used to request tokens. Defaults to
+	// http.DefaultClient if not set.
+	Client *http.Client
+}
+
+// FetchJWT requests an OIDC token for the audience from GitHub Actions.
+func (f *GitHubActionsJWTFetcher) FetchJWT(ctx credentials.Context, audience string) (string, error) {
+	requestURL, requestToken := f.RequestURL, f.RequestToken
+	if len(requestURL) == 0 {
+		requestURL = os.Getenv(githubActionsTokenURLEnvVar)
+	}
+	if len(requestToken) == 0 {
+		requestToken = os.Getenv(githubActionsTokenTokenEnvVar)
+	}
+	if len(requestURL) == 0 || len(requestToken) == 0 {
+		return "", fmt.Errorf("%s and %s not set, the job requires the id-token: write permission",
+			githubActionsTokenURLEnvVar, githubActionsTokenTokenEnvVar)
+	}
+
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "", err
+	}
+	if len(audience) != 0 {
+		q := u.Query()
+		q.Set("audience", audience)
+		u.RawQuery = q.Encode()
+	}
+
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req = requestWithContext(req, ctx)
+	req.Header.Set("Authorization", "Bearer "+requestToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("GitHub Actions token request returned status %d instead of 200", resp.StatusCode)
+	}
+
+	var out struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", err
+	}
+	if len(out.Value) == 0 {
+		return "", fmt.Errorf("GitHub Actions token request returned no token")
+	}
+	return out.Value, nil
+}
+
+// JWTExchangeProvider is a credentials.Provider which exchanges the JWT of an
+// external identity provider for an access token of an IAM trusted profile,
+// federated with the identity provider. Workloads such as GitHub Actions
+// jobs, Kubernetes pods, and SPIFFE workloads authenticate without API keys.
+//
+// The profile to assume is identified by its ProfileCRN, its ProfileID, or
+// its ProfileName and AccountID, in that order of precedence. A new JWT is
+// fetched each time the access token expires.
+//
+// JWTExchangeProvider does not provide any synchronization and it is not
+// safe to share this value across multiple Credentials, Sessions, or service
+// clients without also sharing the same Credentials instance.
+type JWTExchangeProvider struct {
+	credentials.Expiry
+
+	// Fetcher fetches the JWT to exchange.
+	Fetcher JWTFetcher
+
+	// Audience is the audience the JWT is requested for, which the trusted
+	// profile's federation trusts.
+	Audience string
+
+	// CRN of the trusted profile to assume.
+	ProfileCRN string
+
+	// ID of the trusted profile to assume.
+	ProfileID string
+
+	// Name of the trusted profile to assume, in the account AccountID.
+	ProfileName string
+	AccountID   string
+
+	ServiceInstanceID string
+
+	// IAMEndpoint is the IAM endpoint the JWT is exchanged with.
+	IAMEndpoint string
+
+	// Client is the HTTP client used to exchange the JWT. Defaults to
+	// http.DefaultClient if not set.
+	Client *http.Client
+
+	// ExpiryWindow will allow the credentials to trigger refreshing prior to
+	// the token actually expiring.
+	//
+	// If ExpiryWindow is 0 or less it will be ignored.
+	ExpiryWindow time.Duration
+}
+
+// NewJWTExchangeCredentials returns a Credentials wrapper which exchanges the
+// JWTs of the fetcher for access tokens of the IAM trusted profile with the
+// CRN provided.
+//
+//     creds := ibmcreds.NewJWTExchangeCredentials(&ibmcreds.GitHubActionsJWTFetcher{},
+//         profileCRN, func(p *ibmcreds.JWTExchangeProvider) {
+//             p.Audience = "ibm-cloud"
+//             p.ServiceInstanceID = serviceInstanceID
+//         })
+func NewJWTExchangeCredentials(fetcher JWTFetcher, profileCRN string, options ...func(*JWTExchangeProvider)) *credentials.Credentials {
+	p := &JWTExchangeProvider{
+		Fetcher:    fetcher,
+		ProfileCRN: profileCRN,
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return credentials.NewTypedCredentials(p, "ibm-iam")
+}
+
+// Retrieve fetches a JWT, and exchanges it for an access token of the trusted
+// profile.
+func (p *JWTExchangeProvider) Retrieve() (credentials.Value, error) {
+	return p.RetrieveWithContext(aws.BackgroundContext())
+}
+
+// RetrieveWithContext fetches and exchanges a JWT the same as Retrieve, with
+// the context.
+func (p *JWTExchangeProvider) RetrieveWithContext(ctx credentials.Context) (credentials.Value, error) {
+	v := credentials.Value{
+		ServiceInstanceID: p.ServiceInstanceID,
+		ProviderName:      JWTExchangeProviderName,
+	}
+
+	// The trusted profile is assumed the same as with an IAM access token,
+	// with the JWT as a compute resource token.
+	tp := &TrustedProfileProvider{
+		ProfileCRN:  p.ProfileCRN,
+		ProfileID:   p.ProfileID,
+		ProfileName: p.ProfileName,
+		AccountID:   p.AccountID,
+		IAMEndpoint: p.IAMEndpoint,
+		Client:      p.Client,
+	}
+	form, err := tp.profileForm()
+	if err != nil {
+		return v, err
+	}
+
+	if p.Fetcher == nil {
+		return v, newCredentialsError(ErrMissingConfiguration,
+			awserr.New(ErrCodeJWTExchange, "JWT fetcher not set", nil))
+	}
+	jwt, err := p.Fetcher.FetchJWT(ctx, p.Audience)
+	if err != nil {
+		return v, awserr.New(ErrCodeJWTExchange, "failed to fetch JWT", err)
+	}
+	if len(jwt) == 0 {
+		return v, awserr.New(ErrCodeJWTExchange, "JWT fetcher returned no token", nil)
+	}
+
+	form.Set("grant_type", "urn:ibm:params:oauth:grant-type:cr-token")
+	form.Set("cr_token", jwt)
+
+	out, err := tp.assume(ctx, form)
+	if err != nil {
+		return v, newCredentialsError(iamErrorClass(err),
+			awserr.New(ErrCodeJWTExchange, "failed to exchange JWT for IAM access token", err))
+	}
+
+	v.SessionToken = out.AccessToken
+	p.SetExpiration(time.Unix(out.Expiration, 0), p.ExpiryWindow)
+	return v, nil
+}
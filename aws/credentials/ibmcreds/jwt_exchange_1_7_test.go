@@ -0,0 +1,47 @@
+// +build go1.7
+
+package ibmcreds_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+)
+
+func TestJWTExchangeProviderErrors(t *testing.T) {
+	cases := map[string]struct {
+		Provider *ibmcreds.JWTExchangeProvider
+		Class    awserr.Error
+	}{
+		"no profile": {
+			Provider: &ibmcreds.JWTExchangeProvider{Fetcher: ibmcreds.JWTFile("token")},
+			Class:    ibmcreds.ErrMissingConfiguration,
+		},
+		"no fetcher": {
+			Provider: &ibmcreds.JWTExchangeProvider{ProfileID: "Profile-1234"},
+			Class:    ibmcreds.ErrMissingConfiguration,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := c.Provider.Retrieve()
+			if e, a := c.Class, err.(*ibmcreds.CredentialsError).Class; e != a {
+				t.Errorf("expect %v error class, got %v", e, a)
+			}
+		})
+	}
+
+	p := &ibmcreds.JWTExchangeProvider{
+		ProfileID: "Profile-1234",
+		Fetcher: ibmcreds.JWTFetcherFunc(func(credentials.Context, string) (string, error) {
+			return "", nil
+		}),
+	}
+	_, err := p.Retrieve()
+	if e, a := ibmcreds.ErrCodeJWTExchange, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
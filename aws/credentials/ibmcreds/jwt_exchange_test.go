@@ -0,0 +1,125 @@
+package ibmcreds_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+)
+
+func newExternalJWT(t *testing.T, audience string) string {
+	claims := ibmcredstest.NewClaims("", time.Now(), time.Hour)
+	claims.Issuer = "https://token.actions.githubusercontent.com"
+	claims.Extra = map[string]interface{}{"aud": audience}
+	jwt, err := ibmcredstest.NewJWT(claims)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return jwt
+}
+
+func TestJWTExchangeProviderGitHubActions(t *testing.T) {
+	server := ibmcredstest.NewServer(func(s *ibmcredstest.Server) {
+		s.Audience = "ibm-cloud"
+	})
+	defer server.Close()
+
+	jwt := newExternalJWT(t, "ibm-cloud")
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if e, a := "Bearer request-token", r.Header.Get("Authorization"); e != a {
+			t.Errorf("expect %v authorization, got %v", e, a)
+		}
+		if e, a := "ibm-cloud", r.URL.Query().Get("audience"); e != a {
+			t.Errorf("expect %v audience, got %v", e, a)
+		}
+		if e, a := "1", r.URL.Query().Get("api-version"); e != a {
+			t.Errorf("expect %v api-version, got %v", e, a)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"value": jwt})
+	}))
+	defer github.Close()
+
+	fetcher := &ibmcreds.GitHubActionsJWTFetcher{
+		RequestURL:   github.URL + "/token?api-version=1",
+		RequestToken: "request-token",
+	}
+	creds := ibmcreds.NewJWTExchangeCredentials(fetcher, testProfileCRN, func(p *ibmcreds.JWTExchangeProvider) {
+		p.Audience = "ibm-cloud"
+		p.ServiceInstanceID = "instanceID"
+		p.IAMEndpoint = server.URL
+	})
+
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := server.Tokens()[0].AccessToken, v.SessionToken; e != a {
+		t.Errorf("expect %v token, got %v", e, a)
+	}
+	if e, a := ibmcreds.JWTExchangeProviderName, v.ProviderName; e != a {
+		t.Errorf("expect %v provider name, got %v", e, a)
+	}
+	if e, a := "instanceID", v.ServiceInstanceID; e != a {
+		t.Errorf("expect %v service instance ID, got %v", e, a)
+	}
+
+	req := server.Requests()[0]
+	if e, a := ibmcredstest.CRTokenGrantType, req.GrantType; e != a {
+		t.Errorf("expect %v grant type, got %v", e, a)
+	}
+	if e, a := jwt, req.CRToken; e != a {
+		t.Errorf("expect %v JWT, got %v", e, a)
+	}
+	if e, a := testProfileCRN, req.Profile; e != a {
+		t.Errorf("expect %v profile, got %v", e, a)
+	}
+}
+
+func TestJWTExchangeProviderFile(t *testing.T) {
+	server := ibmcredstest.NewServer(func(s *ibmcredstest.Server) {
+		s.Audience = "ibm-cloud"
+	})
+	defer server.Close()
+	dir, cleanup := newTokenCacheDir(t)
+	defer cleanup()
+
+	filename := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(filename, []byte(newExternalJWT(t, "other")+"\n"), 0600); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	p := &ibmcreds.JWTExchangeProvider{
+		Fetcher:     ibmcreds.JWTFile(filename),
+		ProfileID:   "Profile-1234",
+		IAMEndpoint: server.URL,
+	}
+	_, err := p.Retrieve()
+	if e, a := ibmcreds.ErrInvalidAPIKey, err.(*ibmcreds.CredentialsError).Class; e != a {
+		t.Errorf("expect %v error class, got %v", e, a)
+	}
+	if e, a := ibmcreds.ErrCodeJWTExchange, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+
+	// The rotated token is read from the file.
+	jwt := newExternalJWT(t, "ibm-cloud")
+	if err := ioutil.WriteFile(filename, []byte(jwt), 0600); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if _, err := p.Retrieve(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := jwt, server.Requests()[1].CRToken; e != a {
+		t.Errorf("expect %v JWT, got %v", e, a)
+	}
+	if e, a := "Profile-1234", server.Requests()[1].Profile; e != a {
+		t.Errorf("expect %v profile, got %v", e, a)
+	}
+}
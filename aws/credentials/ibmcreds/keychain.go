@@ -0,0 +1,250 @@
+package ibmcreds
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// KeychainProviderName is the name of the credentials provider reading API
+// keys from the OS keychain.
+const KeychainProviderName = "IBMKeychainProvider"
+
+// The names of the OS keychain backends.
+const (
+	// KeychainMacOS is the macOS Keychain, accessed with the security
+	// command.
+	KeychainMacOS = "macos-keychain"
+
+	// KeychainWindows is the Windows Credential Manager. It is only
+	// available on Windows.
+	KeychainWindows = "wincred"
+
+	// KeychainSecretService is the freedesktop.org Secret Service, such as
+	// GNOME Keyring or KWallet, accessed with the secret-tool command of
+	// libsecret.
+	KeychainSecretService = "secret-service"
+)
+
+// DefaultKeychainService is the service name API keys are stored under in the
+// OS keychain if not set.
+const DefaultKeychainService = "ibm-cos-sdk-go"
+
+// ErrCodeKeychain is the error code returned when the OS keychain cannot be
+// read or written.
+const ErrCodeKeychain = "KeychainError"
+
+// ErrKeychainItemNotFound is returned when the OS keychain has no secret for
+// the service and account.
+var ErrKeychainItemNotFound = awserr.New("KeychainItemNotFound",
+	"secret not found in the OS keychain", nil)
+
+// A Keychain stores secrets in an OS keychain, identified by a service and an
+// account name.
+type Keychain interface {
+	// Get returns the secret of the service and account, or
+	// ErrKeychainItemNotFound if the keychain has none.
+	Get(service, account string) (string, error)
+
+	// Set stores the secret of the service and account, replacing the
+	// existing secret if any.
+	Set(service, account, secret string) error
+
+	// Delete deletes the secret of the service and account, or returns
+	// ErrKeychainItemNotFound if the keychain has none.
+	Delete(service, account string) error
+}
+
+var keychains = struct {
+	sync.Mutex
+	m map[string]func() Keychain
+}{
+	m: map[string]func() Keychain{
+		KeychainMacOS:         func() Keychain { return macOSKeychain{} },
+		KeychainSecretService: func() Keychain { return secretServiceKeychain{} },
+	},
+}
+
+// RegisterKeychain registers a Keychain backend with the name, selectable by
+// name with NewKeychain and the KeychainProvider. Registering a name again
+// replaces its backend.
+func RegisterKeychain(name string, fn func() Keychain) {
+	keychains.Lock()
+	defer keychains.Unlock()
+
+	keychains.m[name] = fn
+}
+
+// NewKeychain returns the Keychain backend of the name. If the name is empty
+// the backend of the OS is returned, KeychainMacOS on macOS, KeychainWindows
+// on Windows, and KeychainSecretService on other systems.
+func NewKeychain(name string) (Keychain, error) {
+	if len(name) == 0 {
+		switch runtime.GOOS {
+		case "darwin":
+			name = KeychainMacOS
+		case "windows":
+			name = KeychainWindows
+		default:
+			name = KeychainSecretService
+		}
+	}
+
+	keychains.Lock()
+	defer keychains.Unlock()
+
+	fn, ok := keychains.m[name]
+	if !ok {
+		names := make([]string, 0, len(keychains.m))
+		for n := range keychains.m {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, awserr.New(ErrCodeKeychain,
+			fmt.Sprintf("keychain %q not available on %s, available keychains: %s",
+				name, runtime.GOOS, strings.Join(names, ", ")), nil)
+	}
+	return fn(), nil
+}
+
+// StoreKeychainAPIKey stores the API key in the OS keychain of the name, for
+// a KeychainProvider with the same keychain, service, and account to read. If
+// service is empty the DefaultKeychainService is used.
+//
+//     err := ibmcreds.StoreKeychainAPIKey("", "", "my-profile", apiKey)
+func StoreKeychainAPIKey(keychain, service, account, apiKey string) error {
+	kc, err := NewKeychain(keychain)
+	if err != nil {
+		return err
+	}
+	if len(service) == 0 {
+		service = DefaultKeychainService
+	}
+	return kc.Set(service, account, apiKey)
+}
+
+// KeychainProvider is a credentials.Provider which reads the API key of the
+// credentials from the OS keychain, and exchanges it for an IAM access token.
+// Use this in developer tooling, so API keys are not stored in plaintext
+// configuration files.
+//
+// The API key is read from the keychain for each retrieval, so API keys
+// rotated in the keychain are picked up when the token expires.
+type KeychainProvider struct {
+	// Keychain is the name of the keychain backend, one of the Keychain
+	// values, or a name registered with RegisterKeychain. Defaults to the
+	// keychain of the OS.
+	Keychain string
+
+	// Service is the service name the API key is stored under. Defaults to
+	// DefaultKeychainService.
+	Service string
+
+	// Account is the account name the API key is stored under, such as the
+	// name of a profile.
+	Account string
+
+	ServiceInstanceID string
+
+	// IAMEndpoint is the IAM endpoint the API key is exchanged with.
+	IAMEndpoint string
+
+	// Client is the HTTP client used to request tokens from the IAM endpoint.
+	// Defaults to http.DefaultClient if not set.
+	Client *http.Client
+
+	// ExpiryWindow will allow the credentials to trigger refreshing prior to
+	// the token actually expiring.
+	//
+	// If ExpiryWindow is 0 or less it will be ignored.
+	ExpiryWindow time.Duration
+
+	// AccountID is the ID of the account the tokens are requested for.
+	// Defaults to the default account of the API key's identity.
+	AccountID string
+
+	// provider is the provider of the API key last read.
+	provider *Provider
+}
+
+// NewKeychainCredentials returns a Credentials wrapper retrieving IBM IAM
+// credentials with the API key stored in the OS keychain under the account.
+//
+//     sess := session.Must(session.NewSession(&aws.Config{
+//         Credentials: ibmcreds.NewKeychainCredentials("my-profile", serviceInstanceID),
+//     }))
+func NewKeychainCredentials(account, serviceInstanceID string, options ...func(*KeychainProvider)) *credentials.Credentials {
+	p := &KeychainProvider{
+		Account:           account,
+		ServiceInstanceID: serviceInstanceID,
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return credentials.NewTypedCredentials(p, "ibm-iam")
+}
+
+// IsExpired returns true if the credentials retrieved are expired, or not yet
+// retrieved.
+func (p *KeychainProvider) IsExpired() bool {
+	return p.provider == nil || p.provider.IsExpired()
+}
+
+// Retrieve reads the API key from the OS keychain, and exchanges it for an
+// IAM access token.
+func (p *KeychainProvider) Retrieve() (credentials.Value, error) {
+	return p.RetrieveWithContext(aws.BackgroundContext())
+}
+
+// RetrieveWithContext reads the API key from the OS keychain the same as
+// Retrieve, canceling the request to the IAM endpoint if the context is
+// canceled.
+func (p *KeychainProvider) RetrieveWithContext(ctx credentials.Context) (credentials.Value, error) {
+	v := credentials.Value{
+		ServiceInstanceID: p.ServiceInstanceID,
+		ProviderName:      KeychainProviderName,
+	}
+
+	kc, err := NewKeychain(p.Keychain)
+	if err != nil {
+		return v, newCredentialsError(ErrMissingConfiguration, err.(awserr.Error))
+	}
+	service := p.Service
+	if len(service) == 0 {
+		service = DefaultKeychainService
+	}
+
+	apiKey, err := kc.Get(service, p.Account)
+	if err == ErrKeychainItemNotFound {
+		return v, newCredentialsError(ErrMissingConfiguration,
+			awserr.New(ErrKeychainItemNotFound.Code(),
+				fmt.Sprintf("API key of account %q not found in the OS keychain", p.Account), nil))
+	} else if err != nil {
+		return v, awserr.New(ErrCodeKeychain, "failed to read API key from the OS keychain", err)
+	}
+
+	if p.provider == nil || p.provider.apiKey != apiKey {
+		p.provider = &Provider{
+			apiKey:            apiKey,
+			serviceInstanceID: p.ServiceInstanceID,
+			IAMEndpoint:       p.IAMEndpoint,
+			Client:            p.Client,
+			ExpiryWindow:      p.ExpiryWindow,
+			AccountID:         p.AccountID,
+		}
+	}
+
+	creds, err := p.provider.RetrieveWithContext(ctx)
+	creds.ProviderName = KeychainProviderName
+	return creds, err
+}
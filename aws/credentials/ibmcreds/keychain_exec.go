@@ -0,0 +1,121 @@
+package ibmcreds
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// runKeychainCommand runs the keychain command with the input, returning its
+// output. The error of a failed command includes its standard error.
+func runKeychainCommand(input string, name string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); len(msg) != 0 {
+			return stdout.String(), fmt.Errorf("%s: %v, %s", name, err, msg)
+		}
+		return stdout.String(), fmt.Errorf("%s: %v", name, err)
+	}
+	return stdout.String(), nil
+}
+
+// macOSKeychain stores secrets as generic passwords of the macOS Keychain,
+// with the security command.
+type macOSKeychain struct{}
+
+// macOSItemNotFound is the error the security command reports for missing
+// keychain items.
+const macOSItemNotFound = "could not be found"
+
+func (macOSKeychain) Get(service, account string) (string, error) {
+	out, err := runKeychainCommand("", "security", "find-generic-password",
+		"-s", service, "-a", account, "-w")
+	if err != nil {
+		if strings.Contains(err.Error(), macOSItemNotFound) {
+			return "", ErrKeychainItemNotFound
+		}
+		return "", err
+	}
+	return strings.TrimSuffix(out, "\n"), nil
+}
+
+func (macOSKeychain) Set(service, account, secret string) error {
+	s, err := macOSQuote(service)
+	if err != nil {
+		return err
+	}
+	a, err := macOSQuote(account)
+	if err != nil {
+		return err
+	}
+
+	// The secret is passed on standard input, in the security command's
+	// interactive mode, instead of as an argument visible to other
+	// processes.
+	_, err = runKeychainCommand(
+		fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n", s, a, hex.EncodeToString([]byte(secret))),
+		"security", "-i")
+	return err
+}
+
+func (macOSKeychain) Delete(service, account string) error {
+	_, err := runKeychainCommand("", "security", "delete-generic-password",
+		"-s", service, "-a", account)
+	if err != nil && strings.Contains(err.Error(), macOSItemNotFound) {
+		return ErrKeychainItemNotFound
+	}
+	return err
+}
+
+// macOSQuote quotes the value as an argument of the security command's
+// interactive mode.
+func macOSQuote(v string) (string, error) {
+	if strings.ContainsAny(v, "\"\\\n") {
+		return "", fmt.Errorf("keychain service and account names cannot contain quotes, backslashes, or newlines, %q", v)
+	}
+	return `"` + v + `"`, nil
+}
+
+// secretServiceKeychain stores secrets in the freedesktop.org Secret Service,
+// with the secret-tool command. Secrets are identified by the service and
+// account attributes.
+type secretServiceKeychain struct{}
+
+func (secretServiceKeychain) Get(service, account string) (string, error) {
+	out, err := runKeychainCommand("", "secret-tool", "lookup",
+		"service", service, "account", account)
+	if len(out) == 0 {
+		// secret-tool fails without output if the secret is not found.
+		if _, lookErr := exec.LookPath("secret-tool"); lookErr != nil {
+			return "", lookErr
+		}
+		return "", ErrKeychainItemNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+func (secretServiceKeychain) Set(service, account, secret string) error {
+	_, err := runKeychainCommand(secret, "secret-tool", "store",
+		"--label", service+": "+account,
+		"service", service, "account", account)
+	return err
+}
+
+func (k secretServiceKeychain) Delete(service, account string) error {
+	if _, err := k.Get(service, account); err != nil {
+		return err
+	}
+	_, err := runKeychainCommand("", "secret-tool", "clear",
+		"service", service, "account", account)
+	return err
+}
@@ -0,0 +1,183 @@
+package ibmcreds_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+)
+
+// memKeychain is an in-memory Keychain.
+type memKeychain struct {
+	m       sync.Mutex
+	secrets map[string]string
+}
+
+func (k *memKeychain) Get(service, account string) (string, error) {
+	k.m.Lock()
+	defer k.m.Unlock()
+	s, ok := k.secrets[service+"/"+account]
+	if !ok {
+		return "", ibmcreds.ErrKeychainItemNotFound
+	}
+	return s, nil
+}
+
+func (k *memKeychain) Set(service, account, secret string) error {
+	k.m.Lock()
+	defer k.m.Unlock()
+	k.secrets[service+"/"+account] = secret
+	return nil
+}
+
+func (k *memKeychain) Delete(service, account string) error {
+	k.m.Lock()
+	defer k.m.Unlock()
+	delete(k.secrets, service+"/"+account)
+	return nil
+}
+
+func TestKeychainProvider(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+
+	kc := &memKeychain{secrets: map[string]string{}}
+	ibmcreds.RegisterKeychain("test-keychain", func() ibmcreds.Keychain { return kc })
+
+	if err := ibmcreds.StoreKeychainAPIKey("test-keychain", "", "profile", "apikey-1"); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "apikey-1", kc.secrets[ibmcreds.DefaultKeychainService+"/profile"]; e != a {
+		t.Errorf("expect %v stored, got %v", e, a)
+	}
+
+	creds := ibmcreds.NewKeychainCredentials("profile", "instanceID", func(p *ibmcreds.KeychainProvider) {
+		p.Keychain = "test-keychain"
+		p.IAMEndpoint = server.URL
+	})
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := server.Tokens()[0].AccessToken, v.SessionToken; e != a {
+		t.Errorf("expect %v token, got %v", e, a)
+	}
+	if e, a := ibmcreds.KeychainProviderName, v.ProviderName; e != a {
+		t.Errorf("expect %v provider, got %v", e, a)
+	}
+
+	// API keys rotated in the keychain are used once the token is refreshed.
+	kc.Set(ibmcreds.DefaultKeychainService, "profile", "apikey-2")
+	creds.Expire()
+	if _, err := creds.Get(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	requests := server.Requests()
+	if e, a := 2, len(requests); e != a {
+		t.Fatalf("expect %v token requests, got %v", e, a)
+	}
+	if e, a := "apikey-2", requests[1].APIKey; e != a {
+		t.Errorf("expect %v API key, got %v", e, a)
+	}
+}
+
+func TestKeychainProvider_Errors(t *testing.T) {
+	ibmcreds.RegisterKeychain("empty-keychain", func() ibmcreds.Keychain {
+		return &memKeychain{secrets: map[string]string{}}
+	})
+
+	cases := map[string]struct {
+		Keychain string
+		Code     string
+	}{
+		"not found": {Keychain: "empty-keychain", Code: ibmcreds.ErrKeychainItemNotFound.Code()},
+		"unknown":   {Keychain: "unknown-keychain", Code: ibmcreds.ErrCodeKeychain},
+	}
+
+	for name, c := range cases {
+		creds := ibmcreds.NewKeychainCredentials("profile", "instanceID", func(p *ibmcreds.KeychainProvider) {
+			p.Keychain = c.Keychain
+		})
+		_, err := creds.Get()
+		if err == nil {
+			t.Fatalf("%s, expect error, got none", name)
+		}
+		if e, a := c.Code, err.(awserr.Error).Code(); e != a {
+			t.Errorf("%s, expect %v error code, got %v", name, e, a)
+		}
+		if e, a := ibmcreds.ErrMissingConfiguration, err.(*ibmcreds.CredentialsError).Class; e != a {
+			t.Errorf("%s, expect %v error class, got %v", name, e, a)
+		}
+	}
+}
+
+func TestSecretServiceKeychain(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("secret-tool is not available on windows")
+	}
+
+	dir, err := ioutil.TempDir("", "ibmcreds-secret-tool")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A fake secret-tool storing a single secret in a file, recording the
+	// arguments of each call.
+	script := `#!/bin/sh
+echo "$@" >> "` + dir + `/args"
+case "$1" in
+store) cat > "` + dir + `/secret" ;;
+lookup) cat "` + dir + `/secret" 2>/dev/null || exit 1 ;;
+clear) rm -f "` + dir + `/secret" ;;
+esac
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, "secret-tool"), []byte(script), 0755); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	kc, err := ibmcreds.NewKeychain(ibmcreds.KeychainSecretService)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if _, err := kc.Get("svc", "acct"); err != ibmcreds.ErrKeychainItemNotFound {
+		t.Errorf("expect not found error, got %v", err)
+	}
+	if err := kc.Set("svc", "acct", "apikey"); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	secret, err := kc.Get("svc", "acct")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "apikey", secret; e != a {
+		t.Errorf("expect %v secret, got %v", e, a)
+	}
+	if err := kc.Delete("svc", "acct"); err != nil {
+		t.Errorf("expect no error, got %v", err)
+	}
+
+	args, _ := ioutil.ReadFile(filepath.Join(dir, "args"))
+	expect := []string{
+		"lookup service svc account acct",
+		"store --label svc: acct service svc account acct",
+		"lookup service svc account acct",
+		"lookup service svc account acct",
+		"clear service svc account acct",
+	}
+	if e, a := strings.Join(expect, "\n")+"\n", string(args); e != a {
+		t.Errorf("expect %q calls, got %q", e, a)
+	}
+	if strings.Contains(string(args), "apikey") {
+		t.Errorf("expect secret not to be passed as an argument")
+	}
+}
@@ -0,0 +1,193 @@
+package ibmcreds
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// DefaultManagerConcurrency is the number of credentials a Manager refreshes
+// concurrently if its Concurrency is not set.
+const DefaultManagerConcurrency = 8
+
+// A Manager caches the IBM IAM credentials of many API key and service
+// instance ID pairs, such as a backend acting on behalf of many tenants'
+// COS instances. Each pair's Credentials are created once, and shared by all
+// callers, so that each pair's token is only requested once, and refreshed
+// when it expires.
+//
+// A Manager is safe to use concurrently.
+//
+//     manager := ibmcreds.NewManager(func(m *ibmcreds.Manager) {
+//         m.MaxIdle = time.Hour
+//     })
+//
+//     svc := s3.New(sess, &aws.Config{
+//         Credentials: manager.Credentials(tenant.APIKey, tenant.ServiceInstanceID),
+//     })
+type Manager struct {
+	// IAMEndpoint of the credentials created by the Manager.
+	IAMEndpoint string
+
+	// Client is the HTTP client the credentials created by the Manager
+	// request tokens with. Defaults to http.DefaultClient if not set.
+	Client *http.Client
+
+	// ExpiryWindow of the credentials created by the Manager.
+	ExpiryWindow time.Duration
+
+	// MaxIdle is the duration after which credentials which have not been
+	// requested from the Manager are removed from it. If MaxIdle is 0 or less
+	// credentials are never removed.
+	MaxIdle time.Duration
+
+	// Concurrency is the number of credentials RefreshExpired refreshes
+	// concurrently. Defaults to DefaultManagerConcurrency if not set.
+	Concurrency int
+
+	mu        sync.Mutex
+	entries   map[managerKey]*managerEntry
+	lastSweep time.Time
+}
+
+type managerKey struct {
+	apiKey            string
+	serviceInstanceID string
+}
+
+type managerEntry struct {
+	creds    *credentials.Credentials
+	lastUsed time.Time
+}
+
+// NewManager returns a Manager configured by the options provided.
+func NewManager(options ...func(*Manager)) *Manager {
+	m := &Manager{}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	return m
+}
+
+// Credentials returns the credentials of the API key and service instance ID
+// pair, creating them if the Manager does not have them.
+func (m *Manager) Credentials(apiKey, serviceInstanceID string) *credentials.Credentials {
+	key := managerKey{apiKey: apiKey, serviceInstanceID: serviceInstanceID}
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(now)
+
+	if m.entries == nil {
+		m.entries = map[managerKey]*managerEntry{}
+	}
+
+	e, ok := m.entries[key]
+	if !ok {
+		e = &managerEntry{
+			creds: NewCredentialsClient(apiKey, serviceInstanceID, m.IAMEndpoint,
+				func(p *Provider) {
+					p.Client = m.Client
+					p.ExpiryWindow = m.ExpiryWindow
+				}),
+		}
+		m.entries[key] = e
+	}
+	e.lastUsed = now
+
+	return e.creds
+}
+
+// Remove removes the credentials of the API key and service instance ID pair
+// from the Manager, such as when a tenant is deleted, or its API key is
+// rotated. Credentials previously returned by the Manager remain usable.
+func (m *Manager) Remove(apiKey, serviceInstanceID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, managerKey{apiKey: apiKey, serviceInstanceID: serviceInstanceID})
+}
+
+// Len returns the number of credentials the Manager has.
+func (m *Manager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.entries)
+}
+
+// RefreshExpired refreshes the Manager's credentials which have expired, or
+// have not been retrieved, up to Concurrency at a time. Call this
+// periodically to refresh tokens ahead of requests instead of when requests
+// are made.
+//
+// A BatchedErrors error is returned with the errors of the credentials which
+// failed to refresh.
+func (m *Manager) RefreshExpired() error {
+	m.mu.Lock()
+	m.sweep(time.Now())
+	var expired []*credentials.Credentials
+	for _, e := range m.entries {
+		if e.creds.IsExpired() {
+			expired = append(expired, e.creds)
+		}
+	}
+	m.mu.Unlock()
+
+	concurrency := m.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultManagerConcurrency
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+		sem  = make(chan struct{}, concurrency)
+	)
+	for _, creds := range expired {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(creds *credentials.Credentials) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if _, err := creds.Get(); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(creds)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return awserr.NewBatchError("RefreshCredentialsError",
+			"failed to refresh credentials", errs)
+	}
+	return nil
+}
+
+// sweep removes idle entries, at most once every MaxIdle. The Manager's lock
+// must be held.
+func (m *Manager) sweep(now time.Time) {
+	if m.MaxIdle <= 0 || now.Sub(m.lastSweep) < m.MaxIdle {
+		return
+	}
+	m.lastSweep = now
+
+	for key, e := range m.entries {
+		if now.Sub(e.lastUsed) >= m.MaxIdle {
+			delete(m.entries, key)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package ibmcreds_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+)
+
+func TestManagerCredentials(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+
+	manager := ibmcreds.NewManager(func(m *ibmcreds.Manager) {
+		m.IAMEndpoint = server.URL
+	})
+
+	const tenants = 10
+	var wg sync.WaitGroup
+	for i := 0; i < tenants*5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tenant := i % tenants
+			creds := manager.Credentials(fmt.Sprintf("apikey-%d", tenant), fmt.Sprintf("instance-%d", tenant))
+			v, err := creds.Get()
+			if err != nil {
+				t.Errorf("%d, expect no error, got %v", i, err)
+				return
+			}
+			if e, a := fmt.Sprintf("instance-%d", tenant), v.ServiceInstanceID; e != a {
+				t.Errorf("%d, expect %v service instance ID, got %v", i, e, a)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if e, a := tenants, manager.Len(); e != a {
+		t.Errorf("expect %v credentials, got %v", e, a)
+	}
+	if e, a := tenants, len(server.Requests()); e != a {
+		t.Errorf("expect one token request per tenant, got %v", a)
+	}
+
+	if a, b := manager.Credentials("apikey-1", "instance-1"), manager.Credentials("apikey-1", "instance-2"); a == b {
+		t.Errorf("expect distinct credentials per service instance ID")
+	}
+
+	manager.Remove("apikey-1", "instance-2")
+	if e, a := tenants, manager.Len(); e != a {
+		t.Errorf("expect %v credentials, got %v", e, a)
+	}
+}
+
+func TestManagerRefreshExpired(t *testing.T) {
+	server := ibmcredstest.NewServer(func(s *ibmcredstest.Server) {
+		s.APIKey = "apikey"
+	})
+	defer server.Close()
+
+	manager := ibmcreds.NewManager(func(m *ibmcreds.Manager) {
+		m.IAMEndpoint = server.URL
+		m.Concurrency = 2
+	})
+	for i := 0; i < 5; i++ {
+		manager.Credentials("apikey", fmt.Sprintf("instance-%d", i))
+	}
+	manager.Credentials("invalid", "instance")
+
+	err := manager.RefreshExpired()
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := 1, len(err.(awserr.BatchedErrors).OrigErrs()); e != a {
+		t.Errorf("expect %v errors, got %v", e, a)
+	}
+	if e, a := 6, len(server.Requests()); e != a {
+		t.Errorf("expect %v token requests, got %v", e, a)
+	}
+
+	manager.Remove("invalid", "instance")
+	if err := manager.RefreshExpired(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 6, len(server.Requests()); e != a {
+		t.Errorf("expect no token requests for unexpired credentials, got %v", a-e)
+	}
+}
+
+func TestManagerMaxIdle(t *testing.T) {
+	manager := ibmcreds.NewManager(func(m *ibmcreds.Manager) {
+		m.MaxIdle = 50 * time.Millisecond
+	})
+
+	idle := manager.Credentials("apikey", "idle")
+	manager.Credentials("apikey", "active")
+	time.Sleep(30 * time.Millisecond)
+	manager.Credentials("apikey", "active")
+	time.Sleep(30 * time.Millisecond)
+
+	active := manager.Credentials("apikey", "active")
+	if e, a := 1, manager.Len(); e != a {
+		t.Errorf("expect %v credentials, got %v", e, a)
+	}
+	if manager.Credentials("apikey", "idle") == idle {
+		t.Errorf("expect idle credentials to be recreated")
+	}
+	if manager.Credentials("apikey", "active") != active {
+		t.Errorf("expect active credentials to be reused")
+	}
+}
@@ -0,0 +1,240 @@
+package ibmcreds
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// OperateAsProviderName is the name of the credentials provider operating as
+// the identity of a trusted profile link.
+const OperateAsProviderName = "IBMOperateAsProvider"
+
+const (
+	// ErrCodeOperateAs is the error code returned when the identity of a
+	// trusted profile link cannot be operated as.
+	ErrCodeOperateAs = "OperateAsError"
+
+	// ErrCodeProfileLinkNotFound is the error code returned when the trusted
+	// profile link operated through does not exist.
+	ErrCodeProfileLinkNotFound = "TrustedProfileLinkNotFound"
+)
+
+// A ProfileLink is a link of an IAM trusted profile to the identity allowed
+// to operate as the profile, such as a compute resource of a customer.
+type ProfileLink struct {
+	// ID of the link.
+	ID string `json:"id"`
+
+	// Name of the link.
+	Name string `json:"name"`
+
+	// Type of the linked compute resource, such as "VSI", "IKS_SA", or
+	// "ROKS_SA".
+	CRType string `json:"cr_type"`
+
+	// Link is the linked identity.
+	Link struct {
+		// CRN of the linked resource.
+		CRN string `json:"crn"`
+
+		// Namespace and Name of the linked Kubernetes service account.
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+	} `json:"link"`
+}
+
+// OperateAsProvider is a credentials.Provider which operates as the identity
+// of an IAM trusted profile link, for admin tooling managing the COS
+// instances of customers. The link is read with the base credentials, which
+// must be able to read the trusted profile, before the profile is assumed
+// with them, so credentials are only issued through links that exist.
+//
+// The credentials operating as the link are distinct from the base
+// credentials, requests signed with them are authorized, and audited, as the
+// trusted profile.
+//
+// OperateAsProvider does not provide any synchronization and it is not safe
+// to share this value across multiple Credentials, Sessions, or service
+// clients without also sharing the same Credentials instance.
+type OperateAsProvider struct {
+	credentials.Expiry
+
+	// Credentials of the admin identity operating as the link. The
+	// credentials' session token must be an IAM access token.
+	Credentials *credentials.Credentials
+
+	// ID of the trusted profile linked.
+	ProfileID string
+
+	// ID of the link of the trusted profile operated through.
+	LinkID string
+
+	// ServiceInstanceID of the credentials. Defaults to the service instance
+	// ID of the base credentials.
+	ServiceInstanceID string
+
+	// IAMEndpoint is the IAM endpoint the link is read, and the trusted
+	// profile assumed, with. Defaults to https://iam.cloud.ibm.com.
+	IAMEndpoint string
+
+	// Client is the HTTP client used to call IAM. Defaults to
+	// http.DefaultClient if not set.
+	Client *http.Client
+
+	// ExpiryWindow will allow the credentials to trigger refreshing prior to
+	// the token actually expiring.
+	//
+	// If ExpiryWindow is 0 or less it will be ignored.
+	ExpiryWindow time.Duration
+
+	link *ProfileLink
+}
+
+// OperateAs returns a Credentials wrapper operating as the identity of the
+// link of the trusted profile, with the base credentials. The link is read
+// again each time the credentials are refreshed, so credentials stop being
+// issued once the link is removed.
+//
+//     adminCreds := ibmcreds.NewCredentialsClient(apiKey, "", "")
+//     creds := ibmcreds.OperateAs(adminCreds, profileID, linkID,
+//         func(p *ibmcreds.OperateAsProvider) {
+//             p.ServiceInstanceID = customerInstanceID
+//         })
+//     svc := s3.New(sess, &aws.Config{Credentials: creds})
+func OperateAs(baseCreds *credentials.Credentials, profileID, linkID string, options ...func(*OperateAsProvider)) *credentials.Credentials {
+	p := &OperateAsProvider{
+		Credentials: baseCreds,
+		ProfileID:   profileID,
+		LinkID:      linkID,
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return credentials.NewTypedCredentials(p, "ibm-iam")
+}
+
+// Link returns the trusted profile link last read by the provider, or nil if
+// the credentials have not been retrieved.
+func (p *OperateAsProvider) Link() *ProfileLink {
+	return p.link
+}
+
+// Retrieve reads the trusted profile link, and assumes the trusted profile
+// with the base credentials' access token.
+func (p *OperateAsProvider) Retrieve() (credentials.Value, error) {
+	return p.RetrieveWithContext(aws.BackgroundContext())
+}
+
+// RetrieveWithContext reads the trusted profile link, and assumes the trusted
+// profile, the same as Retrieve, with the context.
+func (p *OperateAsProvider) RetrieveWithContext(ctx credentials.Context) (credentials.Value, error) {
+	v := credentials.Value{
+		ServiceInstanceID: p.ServiceInstanceID,
+		ProviderName:      OperateAsProviderName,
+	}
+
+	if len(p.ProfileID) == 0 || len(p.LinkID) == 0 {
+		return v, newCredentialsError(ErrMissingConfiguration,
+			awserr.New(ErrCodeOperateAs, "trusted profile ID and link ID must be set", nil))
+	}
+	if p.Credentials == nil {
+		return v, newCredentialsError(ErrMissingConfiguration,
+			awserr.New(ErrCodeOperateAs, "base credentials not set", nil))
+	}
+	base, err := p.Credentials.GetWithContext(ctx)
+	if err != nil {
+		return v, awserr.New(ErrCodeOperateAs, "failed to retrieve base credentials", err)
+	}
+	if len(base.SessionToken) == 0 {
+		return v, awserr.New(ErrCodeOperateAs,
+			"base credentials do not provide an IAM access token", nil)
+	}
+	if len(v.ServiceInstanceID) == 0 {
+		v.ServiceInstanceID = base.ServiceInstanceID
+	}
+
+	link, err := p.getLink(ctx, base.SessionToken)
+	if respErr, ok := err.(*iamResponseError); ok && respErr.StatusCode == 404 {
+		return v, newCredentialsError(ErrMissingConfiguration,
+			awserr.New(ErrCodeProfileLinkNotFound,
+				fmt.Sprintf("link %s of trusted profile %s not found", p.LinkID, p.ProfileID), err))
+	} else if err != nil {
+		return v, newCredentialsError(iamErrorClass(err),
+			awserr.New(ErrCodeOperateAs, "failed to read trusted profile link", err))
+	}
+	p.link = link
+
+	tp := &TrustedProfileProvider{
+		IAMEndpoint: p.IAMEndpoint,
+		Client:      p.Client,
+	}
+	out, err := tp.assume(ctx, url.Values{
+		"grant_type":   {"urn:ibm:params:oauth:grant-type:assume"},
+		"access_token": {base.SessionToken},
+		"profile_id":   {p.ProfileID},
+	})
+	if err != nil {
+		return v, newCredentialsError(iamErrorClass(err),
+			awserr.New(ErrCodeOperateAs, "failed to assume linked trusted profile", err))
+	}
+
+	v.SessionToken = out.AccessToken
+	p.SetExpiration(time.Unix(out.Expiration, 0), p.ExpiryWindow)
+	return v, nil
+}
+
+// getLink reads the trusted profile link from the IAM Identity API, with the
+// access token.
+func (p *OperateAsProvider) getLink(ctx credentials.Context, token string) (*ProfileLink, error) {
+	endpoint := p.IAMEndpoint
+	if len(endpoint) == 0 {
+		endpoint = defaultTrustedProfileIAMEndpoint
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = strings.TrimRight(u.Path, "/") + "/v1/profiles/" + p.ProfileID + "/links/" + p.LinkID
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = requestWithContext(req, ctx)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		respErr := &iamResponseError{StatusCode: resp.StatusCode}
+		json.NewDecoder(resp.Body).Decode(respErr)
+		return nil, respErr
+	}
+
+	link := &ProfileLink{}
+	if err := json.NewDecoder(resp.Body).Decode(link); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
@@ -0,0 +1,128 @@
+package ibmcreds_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+)
+
+// newOperateAsTestServer returns a server of the trusted profile links of
+// Profile-1234, forwarding token requests to the IAM token server.
+func newOperateAsTestServer(t *testing.T, iam *ibmcredstest.Server, links ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/v1/profiles/") {
+			iam.Config.Handler.ServeHTTP(w, r)
+			return
+		}
+
+		if e, a := "Bearer "+iam.Tokens()[0].AccessToken, r.Header.Get("Authorization"); e != a {
+			t.Errorf("expect %v authorization, got %v", e, a)
+		}
+		for _, id := range links {
+			if r.URL.Path == "/v1/profiles/Profile-1234/links/"+id {
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"id":      id,
+					"name":    "customer-cluster",
+					"cr_type": "IKS_SA",
+					"link": map[string]string{
+						"crn":       "crn:v1:bluemix:public:containers-kubernetes::a/customer::cluster:1234",
+						"namespace": "default",
+						"name":      "cos-admin",
+					},
+				})
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errorCode":"BXNIM0102E","errorMessage":"Link not found."}`))
+	}))
+}
+
+func TestOperateAs(t *testing.T) {
+	iam := ibmcredstest.NewServer()
+	defer iam.Close()
+	server := newOperateAsTestServer(t, iam, "Link-1234")
+	defer server.Close()
+
+	base := ibmcreds.NewCredentialsClient("apikey", "instanceID", iam.URL)
+	var provider *ibmcreds.OperateAsProvider
+	creds := ibmcreds.OperateAs(base, "Profile-1234", "Link-1234", func(p *ibmcreds.OperateAsProvider) {
+		p.IAMEndpoint = server.URL
+		p.ServiceInstanceID = "customerInstanceID"
+		provider = p
+	})
+
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	tokens := iam.Tokens()
+	if e, a := 2, len(tokens); e != a {
+		t.Fatalf("expect %v tokens, got %v", e, a)
+	}
+	if e, a := tokens[1].AccessToken, v.SessionToken; e != a {
+		t.Errorf("expect %v token, got %v", e, a)
+	}
+	if v.SessionToken == tokens[0].AccessToken {
+		t.Errorf("expect token distinct from base credentials")
+	}
+	if e, a := "customerInstanceID", v.ServiceInstanceID; e != a {
+		t.Errorf("expect %v service instance ID, got %v", e, a)
+	}
+	if e, a := ibmcreds.OperateAsProviderName, v.ProviderName; e != a {
+		t.Errorf("expect %v provider name, got %v", e, a)
+	}
+
+	req := iam.Requests()[1]
+	if e, a := ibmcredstest.AssumeGrantType, req.GrantType; e != a {
+		t.Errorf("expect %v grant type, got %v", e, a)
+	}
+	if e, a := "Profile-1234", req.Profile; e != a {
+		t.Errorf("expect %v profile, got %v", e, a)
+	}
+
+	link := provider.Link()
+	if link == nil {
+		t.Fatalf("expect link, got none")
+	}
+	if e, a := "IKS_SA", link.CRType; e != a {
+		t.Errorf("expect %v link type, got %v", e, a)
+	}
+	if e, a := "cos-admin", link.Link.Name; e != a {
+		t.Errorf("expect %v linked service account, got %v", e, a)
+	}
+}
+
+func TestOperateAsLinkNotFound(t *testing.T) {
+	iam := ibmcredstest.NewServer()
+	defer iam.Close()
+	server := newOperateAsTestServer(t, iam)
+	defer server.Close()
+
+	base := ibmcreds.NewCredentialsClient("apikey", "instanceID", iam.URL)
+	creds := ibmcreds.OperateAs(base, "Profile-1234", "Link-1234", func(p *ibmcreds.OperateAsProvider) {
+		p.IAMEndpoint = server.URL
+	})
+
+	_, err := creds.Get()
+	if e, a := ibmcreds.ErrCodeProfileLinkNotFound, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+	if e, a := ibmcreds.ErrMissingConfiguration, err.(*ibmcreds.CredentialsError).Class; e != a {
+		t.Errorf("expect %v error class, got %v", e, a)
+	}
+	if e, a := 1, len(iam.Tokens()); e != a {
+		t.Errorf("expect only the base token issued, got %v tokens", a)
+	}
+
+	_, err = ibmcreds.OperateAs(base, "Profile-1234", "").Get()
+	if e, a := ibmcreds.ErrMissingConfiguration, err.(*ibmcreds.CredentialsError).Class; e != a {
+		t.Errorf("expect %v error class, got %v", e, a)
+	}
+}
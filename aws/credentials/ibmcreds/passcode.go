@@ -0,0 +1,317 @@
+package ibmcreds
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// PasscodeProviderName is the name of the credentials provider logging in
+// with one-time passcodes.
+const PasscodeProviderName = "IBMPasscodeProvider"
+
+// ErrCodePasscodeLogin is the error code returned when logging in with a
+// one-time passcode fails.
+const ErrCodePasscodeLogin = "PasscodeLoginError"
+
+// passcodeGrantType is the grant type of IAM one-time passcode token
+// requests.
+const passcodeGrantType = "urn:ibm:params:oauth:grant-type:passcode"
+
+// PasscodeProvider is a credentials.Provider which logs in to IBM Cloud with
+// a one-time passcode, for CLIs and developer tools to offer a login without
+// API keys. The user obtains the passcode from the IAM passcode page, after
+// logging in with their browser, including with SSO, and enters it at the
+// provider's Prompt.
+//
+// The access token is refreshed with the refresh token of the login, until
+// the refresh token expires, when the user is prompted to log in again. Set
+// Cache to persist the login's tokens, so the user is not prompted for each
+// process.
+type PasscodeProvider struct {
+	credentials.Expiry
+
+	ServiceInstanceID string
+
+	// IAMEndpoint is the IAM endpoint passcodes are obtained from, and
+	// exchanged with. Defaults to https://iam.cloud.ibm.com.
+	IAMEndpoint string
+
+	// Client is the HTTP client used to request tokens. Defaults to
+	// http.DefaultClient if not set.
+	Client *http.Client
+
+	// ExpiryWindow will allow the credentials to trigger refreshing prior to
+	// the token actually expiring.
+	//
+	// If ExpiryWindow is 0 or less it will be ignored.
+	ExpiryWindow time.Duration
+
+	// AccountID is the ID of the account the tokens are requested for, for
+	// users belonging to multiple accounts. Defaults to the user's default
+	// account.
+	AccountID string
+
+	// Prompt is called with the URL of the IAM passcode page when the user
+	// must log in, and returns the passcode the user entered. See
+	// TerminalPasscodePrompt. If nil, credentials are only retrieved from the
+	// Cache.
+	Prompt func(passcodeURL string) (string, error)
+
+	// Cache persists the tokens of the login, if set.
+	Cache TokenCache
+
+	// CacheKey identifies the login in the Cache, such as the name of a
+	// profile, for a Cache to hold multiple logins. Defaults to "default".
+	CacheKey string
+
+	tokens *TokenPair
+}
+
+// NewPasscodeCredentials returns a Credentials wrapper logging in with a
+// one-time passcode entered at the prompt.
+//
+//     creds := ibmcreds.NewPasscodeCredentials(serviceInstanceID,
+//         ibmcreds.TerminalPasscodePrompt(os.Stdin, os.Stderr, true),
+//         func(p *ibmcreds.PasscodeProvider) {
+//             p.Cache = &ibmcreds.FileTokenCache{Key: key}
+//         })
+func NewPasscodeCredentials(serviceInstanceID string, prompt func(passcodeURL string) (string, error), options ...func(*PasscodeProvider)) *credentials.Credentials {
+	p := &PasscodeProvider{
+		ServiceInstanceID: serviceInstanceID,
+		Prompt:            prompt,
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return credentials.NewTypedCredentials(p, "ibm-iam")
+}
+
+// PasscodeURL returns the URL of the IAM page the user obtains a one-time
+// passcode from.
+func (p *PasscodeProvider) PasscodeURL() string {
+	return p.endpoint() + "/identity/passcode"
+}
+
+// Retrieve returns the access token of the login, refreshing it, or prompting
+// the user to log in, if it has expired.
+func (p *PasscodeProvider) Retrieve() (credentials.Value, error) {
+	return p.RetrieveWithContext(aws.BackgroundContext())
+}
+
+// RetrieveWithContext returns the access token of the login the same as
+// Retrieve, canceling the requests to the IAM endpoint if the context is
+// canceled.
+func (p *PasscodeProvider) RetrieveWithContext(ctx credentials.Context) (credentials.Value, error) {
+	v := credentials.Value{
+		ServiceInstanceID: p.ServiceInstanceID,
+		ProviderName:      PasscodeProviderName,
+	}
+
+	if p.tokens == nil && p.Cache != nil {
+		if tokens, ok := p.Cache.Load(p.cacheKey()); ok {
+			p.tokens = &tokens
+		}
+	}
+
+	now := time.Now()
+	if t := p.tokens; t != nil {
+		expiration := t.AccessTokenExpiration
+		if p.ExpiryWindow > 0 {
+			expiration = expiration.Add(-p.ExpiryWindow)
+		}
+		if len(t.AccessToken) != 0 && expiration.After(now) {
+			return p.value(v), nil
+		}
+
+		if len(t.RefreshToken) != 0 && (t.RefreshTokenExpiration.IsZero() || t.RefreshTokenExpiration.After(now)) {
+			out, err := p.requestToken(ctx, url.Values{
+				"grant_type":    {"refresh_token"},
+				"refresh_token": {t.RefreshToken.Reveal()},
+			})
+			if err == nil {
+				p.setTokens(out)
+				return p.value(v), nil
+			}
+			if iamErrorClass(err) == ErrIAMUnreachable {
+				return v, newCredentialsError(ErrIAMUnreachable,
+					awserr.New(ErrCodePasscodeLogin, "failed to refresh login", err))
+			}
+			// The refresh token was rejected, the user must log in again.
+		}
+	}
+
+	if p.Prompt == nil {
+		return v, newCredentialsError(ErrTokenExpired,
+			awserr.New(ErrCodePasscodeLogin, "login required, but no passcode prompt is set", nil))
+	}
+	passcode, err := p.Prompt(p.PasscodeURL())
+	if err != nil {
+		return v, awserr.New(ErrCodePasscodeLogin, "failed to prompt for passcode", err)
+	}
+
+	out, err := p.requestToken(ctx, url.Values{
+		"grant_type": {passcodeGrantType},
+		"passcode":   {strings.TrimSpace(passcode)},
+	})
+	if err != nil {
+		return v, newCredentialsError(iamErrorClass(err),
+			awserr.New(ErrCodePasscodeLogin, "failed to exchange passcode", err))
+	}
+	p.setTokens(out)
+
+	return p.value(v), nil
+}
+
+// value returns the credentials value of the provider's tokens, setting the
+// provider's expiration.
+func (p *PasscodeProvider) value(v credentials.Value) credentials.Value {
+	p.SetExpiration(p.tokens.AccessTokenExpiration, p.ExpiryWindow)
+	v.SessionToken = p.tokens.AccessToken.Reveal()
+	return v
+}
+
+// setTokens sets the provider's tokens to the tokens of the IAM response,
+// storing them in the cache. Failing to store the tokens is ignored, the
+// user is prompted to log in again by the next process.
+func (p *PasscodeProvider) setTokens(out *getCredentialsOutput) {
+	p.tokens = out.tokenPair()
+	if p.Cache != nil {
+		p.Cache.Store(p.cacheKey(), *p.tokens)
+	}
+}
+
+func (p *PasscodeProvider) endpoint() string {
+	endpoint := p.IAMEndpoint
+	if len(endpoint) == 0 {
+		endpoint = defaultCLIIAMEndpoint
+	}
+	return strings.TrimRight(endpoint, "/")
+}
+
+func (p *PasscodeProvider) cacheKey() string {
+	key := p.CacheKey
+	if len(key) == 0 {
+		key = "default"
+	}
+	return tokenCacheKey("passcode:"+key, p.endpoint(), p.AccountID)
+}
+
+// requestToken requests a token from IAM with the form, as the IBM Cloud CLI
+// client, whose passcodes and refresh tokens are exchanged.
+func (p *PasscodeProvider) requestToken(ctx credentials.Context, form url.Values) (*getCredentialsOutput, error) {
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	form.Set("response_type", "cloud_iam")
+	if len(p.AccountID) != 0 {
+		form.Set("account", p.AccountID)
+	}
+	req, err := http.NewRequest("POST", p.endpoint()+"/identity/token",
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req = requestWithContext(req, ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(cliRefreshClientID, cliRefreshClientID)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		respErr := &iamResponseError{StatusCode: resp.StatusCode}
+		json.NewDecoder(resp.Body).Decode(respErr)
+		return nil, respErr
+	}
+
+	out := &getCredentialsOutput{}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, err
+	}
+	if len(out.AccessToken) == 0 {
+		return nil, fmt.Errorf("server returned no access token")
+	}
+
+	return out, nil
+}
+
+// TerminalPasscodePrompt returns a PasscodeProvider Prompt for terminals,
+// writing the passcode URL to w, and reading the passcode from a line of r.
+// If openBrowser is true the passcode URL is also opened in the user's
+// browser with OpenBrowser.
+//
+//     prompt := ibmcreds.TerminalPasscodePrompt(os.Stdin, os.Stderr, true)
+func TerminalPasscodePrompt(r io.Reader, w io.Writer, openBrowser bool) func(passcodeURL string) (string, error) {
+	return func(passcodeURL string) (string, error) {
+		fmt.Fprintf(w, "Get a one-time passcode from %s\n", passcodeURL)
+		if openBrowser {
+			if err := OpenBrowser(passcodeURL); err == nil {
+				fmt.Fprintln(w, "The page has been opened in your browser.")
+			}
+		}
+		fmt.Fprint(w, "Passcode: ")
+
+		// The line is read a byte at a time, so no input past the line is
+		// consumed from r.
+		var line []byte
+		b := make([]byte, 1)
+		for {
+			n, err := r.Read(b)
+			if n == 1 {
+				if b[0] == '\n' {
+					break
+				}
+				line = append(line, b[0])
+			}
+			if err == io.EOF && len(line) != 0 {
+				break
+			} else if err != nil {
+				return "", err
+			}
+		}
+
+		passcode := strings.TrimSpace(string(line))
+		if len(passcode) == 0 {
+			return "", fmt.Errorf("no passcode entered")
+		}
+		return passcode, nil
+	}
+}
+
+// OpenBrowser opens the URL in the user's browser, with the open command on
+// macOS, the URL protocol handler on Windows, and xdg-open on other systems.
+func OpenBrowser(u string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", u)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		cmd = exec.Command("xdg-open", u)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
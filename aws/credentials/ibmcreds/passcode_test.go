@@ -0,0 +1,174 @@
+package ibmcreds_test
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+)
+
+func TestPasscodeProvider(t *testing.T) {
+	server := ibmcredstest.NewServer(func(s *ibmcredstest.Server) {
+		s.Passcode = "abc123"
+	})
+	defer server.Close()
+	dir, cleanup := newTokenCacheDir(t)
+	defer cleanup()
+
+	filename := filepath.Join(dir, "tokens.json")
+	var prompts []string
+	newProvider := func(passcode string) *ibmcreds.PasscodeProvider {
+		return &ibmcreds.PasscodeProvider{
+			ServiceInstanceID: "instanceID",
+			IAMEndpoint:       server.URL,
+			Cache:             &ibmcreds.FileTokenCache{Filename: filename},
+			Prompt: func(passcodeURL string) (string, error) {
+				prompts = append(prompts, passcodeURL)
+				return passcode, nil
+			},
+		}
+	}
+
+	v, err := newProvider(" abc123\n").Retrieve()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := server.Tokens()[0].AccessToken, v.SessionToken; e != a {
+		t.Errorf("expect %v token, got %v", e, a)
+	}
+	if e, a := ibmcreds.PasscodeProviderName, v.ProviderName; e != a {
+		t.Errorf("expect %v provider, got %v", e, a)
+	}
+	if e, a := []string{server.URL + "/identity/passcode"}, prompts; fmt.Sprint(e) != fmt.Sprint(a) {
+		t.Errorf("expect %v prompts, got %v", e, a)
+	}
+	req := server.Requests()[0]
+	if e, a := ibmcredstest.PasscodeGrantType, req.GrantType; e != a {
+		t.Errorf("expect %v grant type, got %v", e, a)
+	}
+	if e, a := "abc123", req.Passcode; e != a {
+		t.Errorf("expect %v passcode, got %v", e, a)
+	}
+
+	// Another process reuses the cached login without prompting.
+	v, err = newProvider("").Retrieve()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := server.Tokens()[0].AccessToken, v.SessionToken; e != a {
+		t.Errorf("expect cached %v token, got %v", e, a)
+	}
+	if e, a := 1, len(prompts); e != a {
+		t.Errorf("expect %v prompt, got %v", e, a)
+	}
+	if e, a := 1, len(server.Requests()); e != a {
+		t.Errorf("expect %v token request, got %v", e, a)
+	}
+}
+
+func TestPasscodeProviderRefresh(t *testing.T) {
+	server := ibmcredstest.NewServer(func(s *ibmcredstest.Server) {
+		s.TokenTTL = -time.Minute
+	})
+	defer server.Close()
+
+	prompts := 0
+	p := &ibmcreds.PasscodeProvider{
+		IAMEndpoint: server.URL,
+		Prompt: func(string) (string, error) {
+			prompts++
+			return "abc123", nil
+		},
+	}
+	if _, err := p.Retrieve(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !p.IsExpired() {
+		t.Errorf("expect expired token")
+	}
+
+	server.SetTokenTTL(time.Hour)
+	v, err := p.Retrieve()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := server.Tokens()[1].AccessToken, v.SessionToken; e != a {
+		t.Errorf("expect %v token, got %v", e, a)
+	}
+	if e, a := 1, prompts; e != a {
+		t.Errorf("expect %v prompt, got %v", e, a)
+	}
+	req := server.Requests()[1]
+	if e, a := ibmcredstest.RefreshTokenGrantType, req.GrantType; e != a {
+		t.Errorf("expect %v grant type, got %v", e, a)
+	}
+	if e, a := server.Tokens()[0].RefreshToken, req.RefreshToken; e != a {
+		t.Errorf("expect %v refresh token, got %v", e, a)
+	}
+
+	// A rejected refresh token prompts the user to log in again.
+	server.SetTokenTTL(-time.Minute)
+	p.ExpiryWindow = 2 * time.Hour
+	server.FailNext(1, 400, "BXNIM0407E", "Provided refresh token is invalid.")
+	if _, err := p.Retrieve(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 2, prompts; e != a {
+		t.Errorf("expect %v prompts, got %v", e, a)
+	}
+	if e, a := ibmcredstest.PasscodeGrantType, server.Requests()[3].GrantType; e != a {
+		t.Errorf("expect %v grant type, got %v", e, a)
+	}
+}
+
+func TestPasscodeProviderErrors(t *testing.T) {
+	server := ibmcredstest.NewServer(func(s *ibmcredstest.Server) {
+		s.Passcode = "abc123"
+	})
+	defer server.Close()
+
+	p := &ibmcreds.PasscodeProvider{IAMEndpoint: server.URL}
+	_, err := p.Retrieve()
+	if e, a := ibmcreds.ErrTokenExpired, err.(*ibmcreds.CredentialsError).Class; e != a {
+		t.Errorf("expect %v error class, got %v", e, a)
+	}
+	if e, a := ibmcreds.ErrCodePasscodeLogin, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+
+	p.Prompt = func(string) (string, error) { return "wrong", nil }
+	_, err = p.Retrieve()
+	if e, a := ibmcreds.ErrInvalidAPIKey, err.(*ibmcreds.CredentialsError).Class; e != a {
+		t.Errorf("expect %v error class, got %v", e, a)
+	}
+}
+
+func TestTerminalPasscodePrompt(t *testing.T) {
+	var out bytes.Buffer
+	in := strings.NewReader("abc123\r\nnext line\n")
+	prompt := ibmcreds.TerminalPasscodePrompt(in, &out, false)
+
+	passcode, err := prompt("https://iam.example.com/identity/passcode")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "abc123", passcode; e != a {
+		t.Errorf("expect %v passcode, got %v", e, a)
+	}
+	if e, a := "next line\n", in.Len(); len(e) != a {
+		t.Errorf("expect %v bytes unread, got %v", len(e), a)
+	}
+	if e, a := "https://iam.example.com/identity/passcode", out.String(); !strings.Contains(a, e) {
+		t.Errorf("expect %q in prompt, got %q", e, a)
+	}
+
+	if _, err := ibmcreds.TerminalPasscodePrompt(strings.NewReader("\n"), &out, false)(""); err == nil {
+		t.Errorf("expect error for empty passcode")
+	}
+}
@@ -0,0 +1,71 @@
+package ibmcreds
+
+import (
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// DefaultPrivateIAMEndpoint is the IAM endpoint of the IBM Cloud private
+// network, reachable from VPCs without public egress.
+const DefaultPrivateIAMEndpoint = "https://private.iam.cloud.ibm.com"
+
+type serviceEndpointKey struct{}
+
+// serviceEndpointContext is a context with the endpoint of the service the
+// credentials are retrieved for. The context is wrapped instead of derived
+// with the context package, which is not available before Go 1.7.
+type serviceEndpointContext struct {
+	aws.Context
+	endpoint string
+}
+
+func (c serviceEndpointContext) Value(key interface{}) interface{} {
+	if key == (serviceEndpointKey{}) {
+		return c.endpoint
+	}
+	return c.Context.Value(key)
+}
+
+// WithServiceEndpoint returns a copy of the context with the endpoint host of
+// the service the credentials are retrieved with the context for. The IBM IAM
+// signer sets the host of the request being signed, so that the Provider
+// requests tokens from the private IAM endpoint for private and direct COS
+// endpoints.
+func WithServiceEndpoint(ctx aws.Context, endpoint string) aws.Context {
+	return serviceEndpointContext{Context: ctx, endpoint: endpoint}
+}
+
+// serviceEndpoint returns the service endpoint host of the context, if any.
+func serviceEndpoint(ctx credentials.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	endpoint, _ := ctx.Value(serviceEndpointKey{}).(string)
+	return endpoint
+}
+
+// isPrivateCOSEndpoint returns whether the host is a private or direct COS
+// endpoint, such as "s3.direct.us-south.cloud-object-storage.appdomain.cloud",
+// or a legacy private endpoint of the IBM Cloud private network.
+func isPrivateCOSEndpoint(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	if strings.HasSuffix(host, ".service.networklayer.com") {
+		return true
+	}
+	if !strings.HasSuffix(host, ".cloud-object-storage.appdomain.cloud") {
+		return false
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "private" || label == "direct" {
+			return true
+		}
+	}
+	return false
+}
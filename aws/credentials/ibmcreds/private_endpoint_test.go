@@ -0,0 +1,82 @@
+// +build go1.7
+
+package ibmcreds_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
+
+func TestProviderPrivateIAMEndpoint(t *testing.T) {
+	cases := map[string]struct {
+		ServiceEndpoint    string
+		IAMEndpoint        string
+		PrivateIAMEndpoint string
+		Expect             string
+	}{
+		"public": {
+			ServiceEndpoint: "s3.us-south.cloud-object-storage.appdomain.cloud",
+			Expect:          "https://iam.bluemix.net/oidc/token",
+		},
+		"no service endpoint": {
+			Expect: "https://iam.bluemix.net/oidc/token",
+		},
+		"direct": {
+			ServiceEndpoint: "s3.direct.us-south.cloud-object-storage.appdomain.cloud",
+			Expect:          "https://private.iam.cloud.ibm.com/oidc/token",
+		},
+		"private with port": {
+			ServiceEndpoint: "s3.private.eu-de.cloud-object-storage.appdomain.cloud:443",
+			Expect:          "https://private.iam.cloud.ibm.com/oidc/token",
+		},
+		"legacy private": {
+			ServiceEndpoint: "s3.dal.us.cloud-object-storage.service.networklayer.com",
+			Expect:          "https://private.iam.cloud.ibm.com/oidc/token",
+		},
+		"private IAM endpoint": {
+			ServiceEndpoint:    "s3.direct.us-south.cloud-object-storage.appdomain.cloud",
+			PrivateIAMEndpoint: "https://private.us-south.iam.cloud.ibm.com/",
+			Expect:             "https://private.us-south.iam.cloud.ibm.com/oidc/token",
+		},
+		"IAM endpoint overrides": {
+			ServiceEndpoint: "s3.direct.us-south.cloud-object-storage.appdomain.cloud",
+			IAMEndpoint:     "https://iam.cloud.ibm.com",
+			Expect:          "https://iam.cloud.ibm.com/oidc/token",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var requested string
+			client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				requested = r.URL.String()
+				return nil, fmt.Errorf("no network")
+			})}
+			p := ibmcreds.NewProviderClient("apikey", "instanceID", c.IAMEndpoint, func(p *ibmcreds.Provider) {
+				p.Client = client
+				p.PrivateIAMEndpoint = c.PrivateIAMEndpoint
+			}).(*ibmcreds.Provider)
+
+			ctx := aws.BackgroundContext()
+			if len(c.ServiceEndpoint) != 0 {
+				ctx = ibmcreds.WithServiceEndpoint(ctx, c.ServiceEndpoint)
+			}
+			if _, err := p.RetrieveWithContext(ctx); err == nil {
+				t.Fatalf("expect error, got none")
+			}
+			if e, a := c.Expect, requested; e != a {
+				t.Errorf("expect %v requested, got %v", e, a)
+			}
+		})
+	}
+}
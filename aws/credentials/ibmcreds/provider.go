@@ -5,12 +5,14 @@ package ibmcreds
 import (
 	"encoding/json"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
-	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
-	"net/url"
 )
 
 // ProviderName is the name of the credentials provider.
@@ -27,9 +29,31 @@ type Provider struct {
 	apiKey            string
 	serviceInstanceID string
 
-	// IAMEndpoint
+	// IAMEndpoint is the IAM endpoint tokens are requested from. If not set,
+	// tokens are requested from the PrivateIAMEndpoint when the credentials
+	// are retrieved to sign requests to private or direct COS endpoints, and
+	// from the public IAM endpoint otherwise.
 	IAMEndpoint string
 
+	// PrivateIAMEndpoint is the IAM endpoint tokens are requested from for
+	// private and direct COS endpoints, if IAMEndpoint is not set. Defaults
+	// to DefaultPrivateIAMEndpoint. The IAM endpoint is selected by the
+	// first retrieval, credentials shared by clients of public and private
+	// COS endpoints should set IAMEndpoint.
+	PrivateIAMEndpoint string
+
+	// Client is the HTTP client used to request tokens from the IAM endpoint.
+	// Defaults to http.DefaultClient if not set. Set this to the session's
+	// HTTPClient to share its transport, e.g. the transport configured with
+	// the aws.Config DNSCache resolver, ProxyURL, or DisableHTTP2 values, or
+	// to a client created from the same config with session.NewHTTPClient.
+	//
+	//     creds := ibmcreds.NewCredentialsClient(apiKey, instanceID, "",
+	//         func(p *ibmcreds.Provider) {
+	//             p.Client = sess.Config.HTTPClient
+	//         })
+	Client *http.Client
+
 	// ExpiryWindow will allow the credentials to trigger refreshing prior to
 	// the credentials actually expiring. This is beneficial so race conditions
 	// with expiring credentials do not cause request to fail unexpectedly
@@ -40,24 +64,59 @@ type Provider struct {
 	//
 	// If ExpiryWindow is 0 or less it will be ignored.
 	ExpiryWindow time.Duration
+
+	// AsyncRefreshWindow enables refreshing the credentials in the background
+	// the duration before they expire, accounting for the ExpiryWindow.
+	// While the credentials are refreshed, calls to the Credentials' Get
+	// continue to return the cached credentials instead of waiting for the
+	// refresh. If zero or less, the credentials are refreshed once expired,
+	// by a call to Get which the other calls wait for.
+	AsyncRefreshWindow time.Duration
+
+	// AccountID is the ID of the account the tokens are requested for. Set
+	// this for identities belonging to multiple accounts, to the account
+	// owning the COS instance, as requests authorized with a token of
+	// another account are denied. Defaults to the identity's default
+	// account.
+	AccountID string
+
+	// RetainTokens opts in to the provider keeping the access and refresh
+	// tokens of its last retrieval, returned by TokenPair. The refresh token
+	// is discarded if this is not set.
+	RetainTokens bool
+
+	// Cache persists the tokens retrieved, if set. A cached token which has
+	// not expired, accounting for the ExpiryWindow, is used instead of
+	// requesting a token from IAM, such as the token of a previous process
+	// using the same API key. Failing to store a token in the cache does not
+	// fail the retrieval. The refresh token is only stored if RetainTokens
+	// is set.
+	Cache TokenCache
+
+	m      sync.Mutex
+	tokens *TokenPair
 }
 
 // NewProviderClient returns a credentials Provider for retrieving IBM IAM
 // credentials from IBM IAM endpoint.
-func NewProviderClient(apiKey, serviceInstanceID, iamEndpoint string) credentials.Provider {
+func NewProviderClient(apiKey, serviceInstanceID, iamEndpoint string, options ...func(*Provider)) credentials.Provider {
 	p := &Provider{
 		serviceInstanceID: serviceInstanceID,
 		apiKey:            apiKey,
 		IAMEndpoint:       iamEndpoint,
 	}
 
+	for _, option := range options {
+		option(p)
+	}
+
 	return p
 }
 
 // NewCredentialsClient returns a Credentials wrapper for retrieving credentials
 // from IBM IAM endpoint.
-func NewCredentialsClient(apiKey, serviceInstanceID, iamEndpoint string) *credentials.Credentials {
-	return credentials.NewTypedCredentials(NewProviderClient(apiKey, serviceInstanceID, iamEndpoint), "ibm-iam")
+func NewCredentialsClient(apiKey, serviceInstanceID, iamEndpoint string, options ...func(*Provider)) *credentials.Credentials {
+	return credentials.NewTypedCredentials(NewProviderClient(apiKey, serviceInstanceID, iamEndpoint, options...), "ibm-iam")
 }
 
 // IsExpired returns true if the credentials retrieved are expired, or not yet
@@ -66,42 +125,170 @@ func (p *Provider) IsExpired() bool {
 	return p.Expiry.IsExpired()
 }
 
+// RefreshTimes returns the times the credentials last retrieved are refreshed
+// in the background at, and expire at, accounting for the AsyncRefreshWindow
+// and ExpiryWindow. Implements credentials.AsyncRefreshProvider, so that the
+// Credentials return the cached credentials without locking.
+func (p *Provider) RefreshTimes() (refreshAt, expiresAt time.Time) {
+	expiresAt = p.ExpiresAt()
+	refreshAt = expiresAt
+	if p.AsyncRefreshWindow > 0 {
+		refreshAt = expiresAt.Add(-p.AsyncRefreshWindow)
+	}
+	return refreshAt, expiresAt
+}
+
 // Retrieve will attempt to request the credentials from the endpoint the Provider
 // was configured for. And error will be returned if the retrieval fails.
 func (p *Provider) Retrieve() (credentials.Value, error) {
-	resp, err := p.getCredentials()
-	if err != nil {
+	return p.RetrieveWithContext(aws.BackgroundContext())
+}
+
+// RetrieveWithContext requests the credentials the same as Retrieve, canceling
+// the request to the IAM endpoint if the context is canceled. Credentials
+// retrieved to sign a request are retrieved with the request's context.
+func (p *Provider) RetrieveWithContext(ctx credentials.Context) (credentials.Value, error) {
+	if len(p.apiKey) == 0 {
 		return credentials.Value{ProviderName: ProviderName},
-			awserr.New("CredentialsEndpointError", "failed to load credentials", err)
+			newCredentialsError(ErrMissingConfiguration,
+				awserr.New("CredentialsEndpointError", "failed to load credentials, API key not set", nil))
 	}
 
-	p.SetExpiration(time.Unix(resp.Expiration, 0), p.ExpiryWindow)
+	tokens, ok := p.cachedTokens()
+	if !ok {
+		resp, err := p.getCredentials(ctx)
+		if err != nil {
+			return credentials.Value{ProviderName: ProviderName},
+				newCredentialsError(iamErrorClass(err),
+					awserr.New("CredentialsEndpointError", "failed to load credentials", err))
+		}
+		tokens = resp.tokenPair()
+		if p.Cache != nil {
+			cached := *tokens
+			if !p.RetainTokens {
+				cached.RefreshToken = ""
+				cached.RefreshTokenExpiration = time.Time{}
+			}
+			p.Cache.Store(tokenCacheKey(p.apiKey, p.IAMEndpoint, p.AccountID), cached)
+		}
+	}
+
+	p.SetExpiration(tokens.AccessTokenExpiration, p.ExpiryWindow)
+	if p.RetainTokens {
+		p.m.Lock()
+		p.tokens = tokens
+		p.m.Unlock()
+	}
 
 	return credentials.Value{
 		ServiceInstanceID: p.serviceInstanceID,
-		SessionToken:      resp.AccessToken,
+		SessionToken:      tokens.AccessToken.Reveal(),
 		ProviderName:      ProviderName,
 	}, nil
 }
 
+// cachedTokens returns the tokens of the provider's cache, if cached and not
+// expired.
+func (p *Provider) cachedTokens() (*TokenPair, bool) {
+	if p.Cache == nil {
+		return nil, false
+	}
+	tokens, ok := p.Cache.Load(tokenCacheKey(p.apiKey, p.IAMEndpoint, p.AccountID))
+	if !ok || len(tokens.AccessToken) == 0 {
+		return nil, false
+	}
+
+	expiration := tokens.AccessTokenExpiration
+	if p.ExpiryWindow > 0 {
+		expiration = expiration.Add(-p.ExpiryWindow)
+	}
+	if !expiration.After(time.Now()) {
+		return nil, false
+	}
+	return &tokens, true
+}
+
+// TokenPair returns the access and refresh tokens of the provider's last
+// retrieval, and their expirations, for handing the session to a component
+// which is not part of the application. The provider must be configured with
+// RetainTokens, and the credentials retrieved, such as with the Credentials'
+// Get, for the tokens to be available. Otherwise an error with the code
+// ErrCodeTokenPairNotAvailable is returned.
+//
+// The tokens grant access to the identity of the API key. Hand them only to
+// trusted components, and avoid logging or persisting them.
+//
+//     var provider *ibmcreds.Provider
+//     creds := ibmcreds.NewCredentialsClient(apiKey, instanceID, "",
+//         func(p *ibmcreds.Provider) {
+//             p.RetainTokens = true
+//             provider = p
+//         })
+//     if _, err := creds.Get(); err != nil {
+//         return err
+//     }
+//     pair, err := provider.TokenPair()
+//     if err != nil {
+//         return err
+//     }
+//     cmd.Env = append(os.Environ(),
+//         "ACCESS_TOKEN="+pair.AccessToken.Reveal(),
+//         "REFRESH_TOKEN="+pair.RefreshToken.Reveal())
+func (p *Provider) TokenPair() (TokenPair, error) {
+	if !p.RetainTokens {
+		return TokenPair{}, awserr.New(ErrCodeTokenPairNotAvailable,
+			"provider does not retain tokens, set RetainTokens", nil)
+	}
+
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if p.tokens == nil {
+		return TokenPair{}, awserr.New(ErrCodeTokenPairNotAvailable,
+			"credentials not retrieved yet", nil)
+	}
+	return *p.tokens, nil
+}
+
 type getCredentialsOutput struct {
-	Expiration  int64  `json:"expiration"`
-	AccessToken string `json:"access_token"`
+	Expiration             int64  `json:"expiration"`
+	AccessToken            string `json:"access_token"`
+	RefreshToken           string `json:"refresh_token"`
+	RefreshTokenExpiration int64  `json:"refresh_token_expiration"`
 }
 
-func (p *Provider) getCredentials() (*getCredentialsOutput, error) {
+func (p *Provider) getCredentials(ctx credentials.Context) (*getCredentialsOutput, error) {
 	var IAMEndpointURL string
 	if p.IAMEndpoint != "" {
 		IAMEndpointURL = p.IAMEndpoint + "/oidc/token"
+	} else if isPrivateCOSEndpoint(serviceEndpoint(ctx)) {
+		endpoint := p.PrivateIAMEndpoint
+		if len(endpoint) == 0 {
+			endpoint = DefaultPrivateIAMEndpoint
+		}
+		IAMEndpointURL = strings.TrimRight(endpoint, "/") + "/oidc/token"
 	} else {
 		IAMEndpointURL = defaultIAMEndPoint
 	}
-	resp, err := http.PostForm(IAMEndpointURL,
-		url.Values{
-			"grant_type":    {"urn:ibm:params:oauth:grant-type:apikey"},
-			"response_type": {"cloud_iam"},
-			"apikey":        {p.apiKey}})
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	form := url.Values{
+		"grant_type":    {"urn:ibm:params:oauth:grant-type:apikey"},
+		"response_type": {"cloud_iam"},
+		"apikey":        {p.apiKey}}
+	if len(p.AccountID) != 0 {
+		form.Set("account", p.AccountID)
+	}
+	req, err := http.NewRequest("POST", IAMEndpointURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req = requestWithContext(req, ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -109,7 +296,9 @@ func (p *Provider) getCredentials() (*getCredentialsOutput, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("server returned status %d instead of 200", resp.StatusCode)
+		respErr := &iamResponseError{StatusCode: resp.StatusCode}
+		json.NewDecoder(resp.Body).Decode(respErr)
+		return nil, respErr
 	}
 
 	out := &getCredentialsOutput{}
@@ -0,0 +1,55 @@
+// +build go1.13
+
+package ibmcreds_test
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+)
+
+// BenchmarkProviderConcurrentGet reports the p99 latency of the Get calls of
+// 1000 goroutines sharing the credentials, which are retrieved once and
+// returned from the cache without locking.
+func BenchmarkProviderConcurrentGet(b *testing.B) {
+	const goroutines = 1000
+
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+
+	creds := ibmcreds.NewCredentialsClient("apikey", "instance", server.URL)
+	if _, err := creds.Get(); err != nil {
+		b.Fatalf("expect no error, got %v", err)
+	}
+
+	latencies := make([]time.Duration, b.N)
+	var next int64 = -1
+
+	b.ResetTimer()
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				n := atomic.AddInt64(&next, 1)
+				if n >= int64(b.N) {
+					return
+				}
+				start := time.Now()
+				creds.Get()
+				latencies[n] = time.Since(start)
+			}
+		}()
+	}
+	wg.Wait()
+	b.StopTimer()
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	b.ReportMetric(float64(latencies[len(latencies)*99/100].Nanoseconds()), "p99-ns")
+}
@@ -0,0 +1,103 @@
+package ibmcreds_test
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+)
+
+func TestProviderAccountID(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+	dir, cleanup := newTokenCacheDir(t)
+	defer cleanup()
+
+	cache := &ibmcreds.FileTokenCache{Filename: filepath.Join(dir, "tokens.json")}
+	get := func(accountID string) string {
+		creds := ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL, func(p *ibmcreds.Provider) {
+			p.AccountID = accountID
+			p.Cache = cache
+		})
+		v, err := creds.Get()
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		return v.SessionToken
+	}
+
+	token := get("account-2")
+	claims, err := ibmcredstest.ParseJWTClaims(token)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "account-2", claims.Account.BSS; e != a {
+		t.Errorf("expect %v account, got %v", e, a)
+	}
+	if e, a := "account-2", server.Requests()[0].Account; e != a {
+		t.Errorf("expect %v account requested, got %v", e, a)
+	}
+
+	// Tokens are cached per account.
+	if e, a := token, get("account-2"); e != a {
+		t.Errorf("expect cached %v token, got %v", e, a)
+	}
+	if e, a := token, get(""); e == a {
+		t.Errorf("expect token of default account, got cached %v", a)
+	}
+	reqs := server.Requests()
+	if e, a := 2, len(reqs); e != a {
+		t.Fatalf("expect %v token requests, got %v", e, a)
+	}
+	if e, a := "", reqs[1].Account; e != a {
+		t.Errorf("expect no account requested, got %v", a)
+	}
+}
+
+func TestProviderConcurrentGet(t *testing.T) {
+	server := ibmcredstest.NewServer(func(s *ibmcredstest.Server) {
+		s.Latency = 10 * time.Millisecond
+	})
+	defer server.Close()
+
+	creds := ibmcreds.NewCredentialsClient("apikey", "instance", server.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 1000; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := creds.Get(); err != nil {
+				t.Errorf("expect no error, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if e, a := 1, len(server.Requests()); e != a {
+		t.Errorf("expect %v token requests, got %v", e, a)
+	}
+}
+
+func TestProviderRefreshTimes(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+
+	p := ibmcreds.NewProviderClient("apikey", "instance", server.URL, func(p *ibmcreds.Provider) {
+		p.AsyncRefreshWindow = 5 * time.Minute
+	}).(*ibmcreds.Provider)
+	if _, err := p.Retrieve(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	refreshAt, expiresAt := p.RefreshTimes()
+	if e, a := p.ExpiresAt(), expiresAt; !e.Equal(a) {
+		t.Errorf("expect %v expiration, got %v", e, a)
+	}
+	if e, a := 5*time.Minute, expiresAt.Sub(refreshAt); e != a {
+		t.Errorf("expect refresh %v before expiration, got %v", e, a)
+	}
+}
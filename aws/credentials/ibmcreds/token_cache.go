@@ -0,0 +1,268 @@
+package ibmcreds
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/internal/shareddefaults"
+)
+
+// ErrCodeTokenCache is the error code returned when the tokens of a
+// TokenCache cannot be stored.
+const ErrCodeTokenCache = "TokenCacheError"
+
+// A TokenCache persists the tokens retrieved by a Provider, so processes
+// started with the same API key reuse the token of a previous process until
+// it expires, instead of requesting a token from IAM each time they start.
+type TokenCache interface {
+	// Load returns the tokens cached for the key, and whether they were
+	// found.
+	Load(key string) (TokenPair, bool)
+
+	// Store caches the tokens for the key.
+	Store(key string, tokens TokenPair) error
+}
+
+// A FileTokenCache is a TokenCache storing tokens in a JSON file, readable
+// and writable only by its owner. By default the tokens are stored in
+// plaintext. Set Key to encrypt the tokens at rest with AES-GCM, so bearer
+// tokens are not stored in plaintext on laptops and shared hosts. The key can be read from the OS keyring, or a secrets manager, with
+// the Key function.
+//
+// The cache file is written atomically, it is safe to share between
+// processes. Tokens which cannot be decrypted, such as tokens encrypted with
+// another key or stored in plaintext, are not found, and are overwritten by
+// the next Store.
+//
+//     cache := &ibmcreds.FileTokenCache{
+//         Key: func() ([]byte, error) {
+//             // Read the 32 byte key from the OS keyring, or generate
+//             // and save it there on first use.
+//             return loadKeyFromKeyring("my-app", "token-cache")
+//         },
+//     }
+//     creds := ibmcreds.NewCredentialsClient(apiKey, instanceID, "",
+//         func(p *ibmcreds.Provider) {
+//             p.Cache = cache
+//         })
+type FileTokenCache struct {
+	// Filename is the path of the cache file. If empty the
+	// .bluemix/cos_token_cache.json file in the user's home directory is
+	// used.
+	Filename string
+
+	// Key returns the AES key the tokens are encrypted with, 16, 24, or 32
+	// bytes long. The tokens are stored in plaintext if Key is nil.
+	Key func() ([]byte, error)
+
+	m sync.Mutex
+}
+
+// tokenCacheFile is the content of the cache file. Tokens are stored in
+// Tokens, or encrypted in Ciphertext if the cache has a key.
+type tokenCacheFile struct {
+	Tokens     map[string]cachedTokens `json:"tokens,omitempty"`
+	Ciphertext []byte                  `json:"ciphertext,omitempty"`
+}
+
+// cachedTokens is the cached representation of a TokenPair, whose
+// SensitiveTokens are redacted when encoded.
+type cachedTokens struct {
+	AccessToken            string `json:"access_token"`
+	Expiration             int64  `json:"expiration"`
+	RefreshToken           string `json:"refresh_token,omitempty"`
+	RefreshTokenExpiration int64  `json:"refresh_token_expiration,omitempty"`
+}
+
+// Load returns the tokens cached for the key, and whether they were found.
+func (c *FileTokenCache) Load(key string) (TokenPair, bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	tokens, err := c.read()
+	if err != nil {
+		return TokenPair{}, false
+	}
+	t, ok := tokens[key]
+	if !ok {
+		return TokenPair{}, false
+	}
+
+	pair := TokenPair{
+		AccessToken:           SensitiveToken(t.AccessToken),
+		AccessTokenExpiration: time.Unix(t.Expiration, 0),
+		RefreshToken:          SensitiveToken(t.RefreshToken),
+	}
+	if t.RefreshTokenExpiration != 0 {
+		pair.RefreshTokenExpiration = time.Unix(t.RefreshTokenExpiration, 0)
+	}
+	return pair, true
+}
+
+// Store caches the tokens for the key, dropping the expired tokens of other
+// keys from the cache. Tokens whose refresh token has not expired are kept.
+func (c *FileTokenCache) Store(key string, tokens TokenPair) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	cached, err := c.read()
+	if err != nil {
+		cached = map[string]cachedTokens{}
+	}
+	now := time.Now().Unix()
+	for k, t := range cached {
+		refreshable := len(t.RefreshToken) != 0 &&
+			(t.RefreshTokenExpiration == 0 || t.RefreshTokenExpiration > now)
+		if t.Expiration <= now && !refreshable {
+			delete(cached, k)
+		}
+	}
+
+	t := cachedTokens{
+		AccessToken:  tokens.AccessToken.Reveal(),
+		Expiration:   tokens.AccessTokenExpiration.Unix(),
+		RefreshToken: tokens.RefreshToken.Reveal(),
+	}
+	if !tokens.RefreshTokenExpiration.IsZero() {
+		t.RefreshTokenExpiration = tokens.RefreshTokenExpiration.Unix()
+	}
+	cached[key] = t
+
+	if err := c.write(cached); err != nil {
+		return awserr.New(ErrCodeTokenCache,
+			fmt.Sprintf("failed to write token cache, %s", c.filename()), err)
+	}
+	return nil
+}
+
+func (c *FileTokenCache) filename() string {
+	if len(c.Filename) != 0 {
+		return c.Filename
+	}
+	return filepath.Join(shareddefaults.UserHomeDir(), ".bluemix", "cos_token_cache.json")
+}
+
+// read returns the tokens of the cache file, decrypting them if the cache has
+// a key.
+func (c *FileTokenCache) read() (map[string]cachedTokens, error) {
+	b, err := ioutil.ReadFile(c.filename())
+	if err != nil {
+		return nil, err
+	}
+
+	var file tokenCacheFile
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, err
+	}
+	if c.Key == nil {
+		if file.Tokens == nil {
+			return nil, fmt.Errorf("token cache has no plaintext tokens")
+		}
+		return file.Tokens, nil
+	}
+
+	aead, err := c.aead()
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("token cache has no encrypted tokens")
+	}
+	nonce, ciphertext := file.Ciphertext[:aead.NonceSize()], file.Ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens map[string]cachedTokens
+	if err := json.Unmarshal(plaintext, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// write writes the tokens to the cache file, encrypting them if the cache has
+// a key. The file is written to a temporary file renamed over the cache file.
+func (c *FileTokenCache) write(tokens map[string]cachedTokens) error {
+	var file tokenCacheFile
+	if c.Key == nil {
+		file.Tokens = tokens
+	} else {
+		aead, err := c.aead()
+		if err != nil {
+			return err
+		}
+		plaintext, err := json.Marshal(tokens)
+		if err != nil {
+			return err
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return err
+		}
+		file.Ciphertext = aead.Seal(nonce, nonce, plaintext, nil)
+	}
+
+	b, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+
+	filename := c.filename()
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(filename), filepath.Base(filename)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		// TempFile creates the file readable and writable only by its owner.
+		err = os.Rename(f.Name(), filename)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// aead returns the AES-GCM cipher of the cache's key.
+func (c *FileTokenCache) aead() (cipher.AEAD, error) {
+	key, err := c.Key()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// tokenCacheKey returns the key of the tokens of the API key in a TokenCache,
+// a digest of the API key, IAM endpoint, and the account the tokens are
+// issued for, if any. API keys are not stored in the cache.
+func tokenCacheKey(apiKey, endpoint, accountID string) string {
+	key := endpoint + "\n" + apiKey
+	if len(accountID) != 0 {
+		key += "\n" + accountID
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
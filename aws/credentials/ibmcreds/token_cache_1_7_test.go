@@ -0,0 +1,49 @@
+// +build go1.7
+
+package ibmcreds_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+)
+
+func TestProviderFileTokenCache_RefreshToken(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+	dir, cleanup := newTokenCacheDir(t)
+	defer cleanup()
+
+	cases := map[string]struct {
+		retain        bool
+		expectRefresh bool
+	}{
+		"discarded": {retain: false, expectRefresh: false},
+		"retained":  {retain: true, expectRefresh: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(dir, name+".json")
+			creds := ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL, func(p *ibmcreds.Provider) {
+				p.Cache = &ibmcreds.FileTokenCache{Filename: filename}
+				p.RetainTokens = c.retain
+			})
+			if _, err := creds.Get(); err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			b, err := ioutil.ReadFile(filename)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.expectRefresh, bytes.Contains(b, []byte("ibmcredstest-refresh")); e != a {
+				t.Errorf("expect refresh token stored %v, got %v", e, a)
+			}
+		})
+	}
+}
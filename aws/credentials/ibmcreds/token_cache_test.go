@@ -0,0 +1,107 @@
+package ibmcreds_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+)
+
+func newTokenCacheDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "ibmcreds-token-cache")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func staticKey(key []byte) func() ([]byte, error) {
+	return func() ([]byte, error) { return key, nil }
+}
+
+func TestProviderFileTokenCache(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+	dir, cleanup := newTokenCacheDir(t)
+	defer cleanup()
+
+	filename := filepath.Join(dir, "cache", "tokens.json")
+	key := bytes.Repeat([]byte{1}, 32)
+	newCreds := func(key []byte) (string, error) {
+		creds := ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL, func(p *ibmcreds.Provider) {
+			p.Cache = &ibmcreds.FileTokenCache{Filename: filename, Key: staticKey(key)}
+		})
+		v, err := creds.Get()
+		return v.SessionToken, err
+	}
+
+	token, err := newCreds(key)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	cached, err := newCreds(key)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := token, cached; e != a {
+		t.Errorf("expect cached %v token, got %v", e, a)
+	}
+	if e, a := 1, len(server.Requests()); e != a {
+		t.Errorf("expect %v token request, got %v", e, a)
+	}
+
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if bytes.Contains(b, []byte(token)) || bytes.Contains(b, []byte("ibmcredstest-refresh")) {
+		t.Errorf("expect tokens to be encrypted, got %s", b)
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := os.FileMode(0600), info.Mode().Perm(); e != a {
+		t.Errorf("expect %v file mode, got %v", e, a)
+	}
+
+	// Tokens encrypted with another key are not found.
+	if _, err := newCreds(bytes.Repeat([]byte{2}, 32)); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 2, len(server.Requests()); e != a {
+		t.Errorf("expect %v token requests, got %v", e, a)
+	}
+}
+
+func TestFileTokenCache_Plaintext(t *testing.T) {
+	dir, cleanup := newTokenCacheDir(t)
+	defer cleanup()
+
+	filename := filepath.Join(dir, "tokens.json")
+	cache := &ibmcreds.FileTokenCache{Filename: filename}
+	if err := cache.Store("key", ibmcreds.TokenPair{AccessToken: "access"}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	pair, ok := cache.Load("key")
+	if !ok {
+		t.Fatalf("expect tokens to be found")
+	}
+	if e, a := "access", pair.AccessToken.Reveal(); e != a {
+		t.Errorf("expect %v token, got %v", e, a)
+	}
+	if _, ok := cache.Load("other"); ok {
+		t.Errorf("expect other key not to be found")
+	}
+
+	// Plaintext tokens are not read by a cache with a key.
+	cache = &ibmcreds.FileTokenCache{Filename: filename, Key: staticKey(bytes.Repeat([]byte{1}, 16))}
+	if _, ok := cache.Load("key"); ok {
+		t.Errorf("expect plaintext tokens not to be found")
+	}
+}
@@ -0,0 +1,75 @@
+package ibmcreds
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrCodeTokenPairNotAvailable is the error code returned when the token pair
+// of a Provider is requested, but the provider does not retain its tokens or
+// has not retrieved credentials yet.
+const ErrCodeTokenPairNotAvailable = "TokenPairNotAvailable"
+
+// redacted is the representation of a SensitiveToken when formatted.
+const redacted = "[REDACTED]"
+
+// A SensitiveToken is an IAM token which grants access to the identity it was
+// issued to. It is redacted when formatted, with fmt's %v, %s, %q, or %#v
+// verbs, or encoded as JSON, to avoid the token leaking into logs. Use Reveal
+// to retrieve the token's value.
+type SensitiveToken string
+
+// Reveal returns the value of the token.
+func (t SensitiveToken) Reveal() string {
+	return string(t)
+}
+
+// String returns the redacted representation of the token.
+func (t SensitiveToken) String() string {
+	if len(t) == 0 {
+		return ""
+	}
+	return redacted
+}
+
+// GoString returns the redacted representation of the token.
+func (t SensitiveToken) GoString() string {
+	return fmt.Sprintf("%q", t.String())
+}
+
+// MarshalJSON encodes the redacted representation of the token.
+func (t SensitiveToken) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("%q", t.String())), nil
+}
+
+// A TokenPair is the access token, and refresh token, of a session with IAM.
+// The pair can be handed to a component which is not part of the application,
+// such as an ibmcloud CLI subprocess, to act on behalf of the session.
+type TokenPair struct {
+	// The access token, and when it expires.
+	AccessToken           SensitiveToken
+	AccessTokenExpiration time.Time
+
+	// The refresh token, and when it expires. The refresh token is empty if
+	// IAM did not issue one, and its expiration zero if IAM did not report
+	// it.
+	RefreshToken           SensitiveToken
+	RefreshTokenExpiration time.Time
+}
+
+// tokenPair returns the token pair of the IAM token response.
+func (out *getCredentialsOutput) tokenPair() *TokenPair {
+	pair := &TokenPair{
+		AccessToken:           SensitiveToken(out.AccessToken),
+		AccessTokenExpiration: time.Unix(out.Expiration, 0),
+	}
+	// IAM returns "not_supported" instead of a refresh token for grants
+	// which do not support refreshing.
+	if len(out.RefreshToken) != 0 && out.RefreshToken != "not_supported" {
+		pair.RefreshToken = SensitiveToken(out.RefreshToken)
+		if out.RefreshTokenExpiration != 0 {
+			pair.RefreshTokenExpiration = time.Unix(out.RefreshTokenExpiration, 0)
+		}
+	}
+	return pair
+}
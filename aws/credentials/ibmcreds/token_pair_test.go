@@ -0,0 +1,97 @@
+package ibmcreds_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+)
+
+func TestProviderTokenPair(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+
+	var provider *ibmcreds.Provider
+	creds := ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL, func(p *ibmcreds.Provider) {
+		p.RetainTokens = true
+		provider = p
+	})
+
+	_, err := provider.TokenPair()
+	if e, a := ibmcreds.ErrCodeTokenPairNotAvailable, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+
+	if _, err := creds.Get(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	pair, err := provider.TokenPair()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	token := server.Tokens()[0]
+	if e, a := token.AccessToken, pair.AccessToken.Reveal(); e != a {
+		t.Errorf("expect %v access token, got %v", e, a)
+	}
+	if e, a := token.RefreshToken, pair.RefreshToken.Reveal(); e != a {
+		t.Errorf("expect %v refresh token, got %v", e, a)
+	}
+	if e, a := token.Expiration, pair.AccessTokenExpiration; !e.Equal(a) {
+		t.Errorf("expect %v expiration, got %v", e, a)
+	}
+}
+
+func TestProviderTokenPair_NotRetained(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+
+	var provider *ibmcreds.Provider
+	creds := ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL, func(p *ibmcreds.Provider) {
+		provider = p
+	})
+	if _, err := creds.Get(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	_, err := provider.TokenPair()
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := ibmcreds.ErrCodeTokenPairNotAvailable, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
+
+func TestSensitiveTokenRedacted(t *testing.T) {
+	pair := ibmcreds.TokenPair{
+		AccessToken:  ibmcreds.SensitiveToken("secret-access"),
+		RefreshToken: ibmcreds.SensitiveToken("secret-refresh"),
+	}
+
+	b, err := json.Marshal(pair)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	for _, s := range []string{
+		fmt.Sprintf("%v", pair),
+		fmt.Sprintf("%+v", pair),
+		fmt.Sprintf("%#v", pair),
+		fmt.Sprintf("%s %q", pair.AccessToken, pair.RefreshToken),
+		string(b),
+	} {
+		if strings.Contains(s, "secret") {
+			t.Errorf("expect token to be redacted, got %v", s)
+		}
+		if !strings.Contains(s, "[REDACTED]") {
+			t.Errorf("expect redacted token, got %v", s)
+		}
+	}
+	if e, a := "secret-access", pair.AccessToken.Reveal(); e != a {
+		t.Errorf("expect %v token, got %v", e, a)
+	}
+}
@@ -0,0 +1,206 @@
+package ibmcreds
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// TrustedProfileProviderName is the name of the credentials provider assuming
+// IAM trusted profiles.
+const TrustedProfileProviderName = "IBMTrustedProfileProvider"
+
+// defaultTrustedProfileIAMEndpoint is the IAM endpoint trusted profiles are
+// assumed with if the provider's IAMEndpoint is not set.
+const defaultTrustedProfileIAMEndpoint = "https://iam.cloud.ibm.com"
+
+// TrustedProfileProvider is a credentials.Provider which exchanges the IAM
+// access token of a base identity for an access token of an IAM trusted
+// profile, similar to assuming a role with STS.
+//
+// The profile to assume is identified by its ProfileCRN, its ProfileID, or
+// its ProfileName and AccountID, in that order of precedence.
+//
+// TrustedProfileProvider does not provide any synchronization and it is not
+// safe to share this value across multiple Credentials, Sessions, or service
+// clients without also sharing the same Credentials instance.
+type TrustedProfileProvider struct {
+	credentials.Expiry
+
+	// Credentials of the base identity assuming the trusted profile. The
+	// credentials' session token must be an IAM access token.
+	Credentials *credentials.Credentials
+
+	// CRN of the trusted profile to assume.
+	ProfileCRN string
+
+	// ID of the trusted profile to assume.
+	ProfileID string
+
+	// Name of the trusted profile to assume, in the account AccountID.
+	ProfileName string
+	AccountID   string
+
+	// ServiceInstanceID of the assumed credentials. Defaults to the service
+	// instance ID of the base credentials.
+	ServiceInstanceID string
+
+	// IAMEndpoint is the IAM endpoint the trusted profile is assumed with.
+	IAMEndpoint string
+
+	// Client is the HTTP client used to assume the trusted profile. Defaults
+	// to http.DefaultClient if not set.
+	Client *http.Client
+
+	// ExpiryWindow will allow the credentials to trigger refreshing prior to
+	// the token actually expiring.
+	//
+	// If ExpiryWindow is 0 or less it will be ignored.
+	ExpiryWindow time.Duration
+}
+
+// AssumeTrustedProfile returns a Credentials wrapper which assumes the IAM
+// trusted profile with the CRN provided, using the base credentials' access
+// token. The trusted profile is assumed again when its token expires.
+//
+//     baseCreds := ibmcreds.NewCredentialsClient(apiKey, serviceInstanceID, "")
+//     sess := session.Must(session.NewSession(&aws.Config{
+//         Credentials: ibmcreds.AssumeTrustedProfile(baseCreds, profileCRN),
+//     }))
+func AssumeTrustedProfile(baseCreds *credentials.Credentials, profileCRN string, options ...func(*TrustedProfileProvider)) *credentials.Credentials {
+	p := &TrustedProfileProvider{
+		Credentials: baseCreds,
+		ProfileCRN:  profileCRN,
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return credentials.NewTypedCredentials(p, "ibm-iam")
+}
+
+// Retrieve exchanges the base credentials' access token for an access token
+// of the trusted profile.
+func (p *TrustedProfileProvider) Retrieve() (credentials.Value, error) {
+	return p.RetrieveWithContext(aws.BackgroundContext())
+}
+
+// RetrieveWithContext exchanges the base credentials' access token the same
+// as Retrieve, retrieving the base credentials and assuming the trusted
+// profile with the context.
+func (p *TrustedProfileProvider) RetrieveWithContext(ctx credentials.Context) (credentials.Value, error) {
+	v := credentials.Value{
+		ServiceInstanceID: p.ServiceInstanceID,
+		ProviderName:      TrustedProfileProviderName,
+	}
+
+	form, err := p.profileForm()
+	if err != nil {
+		return v, err
+	}
+
+	if p.Credentials == nil {
+		return v, newCredentialsError(ErrMissingConfiguration,
+			awserr.New("InvalidTrustedProfile", "base credentials not set", nil))
+	}
+	base, err := p.Credentials.GetWithContext(ctx)
+	if err != nil {
+		return v, awserr.New("TrustedProfileError", "failed to retrieve base credentials", err)
+	}
+	if len(base.SessionToken) == 0 {
+		return v, awserr.New("TrustedProfileError",
+			"base credentials do not provide an IAM access token", nil)
+	}
+	if len(v.ServiceInstanceID) == 0 {
+		v.ServiceInstanceID = base.ServiceInstanceID
+	}
+
+	form.Set("grant_type", "urn:ibm:params:oauth:grant-type:assume")
+	form.Set("access_token", base.SessionToken)
+
+	out, err := p.assume(ctx, form)
+	if err != nil {
+		return v, newCredentialsError(iamErrorClass(err),
+			awserr.New("TrustedProfileError", "failed to assume trusted profile", err))
+	}
+
+	v.SessionToken = out.AccessToken
+	p.SetExpiration(time.Unix(out.Expiration, 0), p.ExpiryWindow)
+	return v, nil
+}
+
+func (p *TrustedProfileProvider) profileForm() (url.Values, error) {
+	switch {
+	case len(p.ProfileCRN) != 0:
+		return url.Values{"profile_crn": {p.ProfileCRN}}, nil
+	case len(p.ProfileID) != 0:
+		return url.Values{"profile_id": {p.ProfileID}}, nil
+	case len(p.ProfileName) != 0 && len(p.AccountID) != 0:
+		return url.Values{
+			"profile_name": {p.ProfileName},
+			"account":      {p.AccountID},
+		}, nil
+	case len(p.ProfileName) != 0:
+		return nil, newCredentialsError(ErrMissingConfiguration,
+			awserr.New("InvalidTrustedProfile", "trusted profile name requires an account ID", nil))
+	default:
+		return nil, newCredentialsError(ErrMissingConfiguration,
+			awserr.New("InvalidTrustedProfile", "trusted profile CRN, ID, or name not set", nil))
+	}
+}
+
+type assumeOutput struct {
+	Expiration  int64  `json:"expiration"`
+	AccessToken string `json:"access_token"`
+}
+
+func (p *TrustedProfileProvider) assume(ctx credentials.Context, form url.Values) (*assumeOutput, error) {
+	endpoint := p.IAMEndpoint
+	if len(endpoint) == 0 {
+		endpoint = defaultTrustedProfileIAMEndpoint
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequest("POST", strings.TrimRight(endpoint, "/")+"/identity/token",
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req = requestWithContext(req, ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		respErr := &iamResponseError{StatusCode: resp.StatusCode}
+		json.NewDecoder(resp.Body).Decode(respErr)
+		return nil, respErr
+	}
+
+	out := &assumeOutput{}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, err
+	}
+	if len(out.AccessToken) == 0 {
+		return nil, fmt.Errorf("server returned no access token")
+	}
+
+	return out, nil
+}
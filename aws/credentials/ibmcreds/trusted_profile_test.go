@@ -0,0 +1,157 @@
+package ibmcreds_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+)
+
+const testProfileCRN = "crn:v1:bluemix:public:iam-identity::a/account::profile:Profile-1234"
+
+func TestAssumeTrustedProfile(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+
+	base := ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL)
+	creds := ibmcreds.AssumeTrustedProfile(base, testProfileCRN, func(p *ibmcreds.TrustedProfileProvider) {
+		p.IAMEndpoint = server.URL
+	})
+
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	tokens := server.Tokens()
+	if e, a := 2, len(tokens); e != a {
+		t.Fatalf("expect %v tokens, got %v", e, a)
+	}
+	if e, a := tokens[1].AccessToken, v.SessionToken; e != a {
+		t.Errorf("expect %v token, got %v", e, a)
+	}
+	if e, a := ibmcredstest.AssumeGrantType, tokens[1].Claims.GrantType; e != a {
+		t.Errorf("expect %v grant type, got %v", e, a)
+	}
+	if e, a := "instanceID", v.ServiceInstanceID; e != a {
+		t.Errorf("expect %v service instance ID, got %v", e, a)
+	}
+	if e, a := ibmcreds.TrustedProfileProviderName, v.ProviderName; e != a {
+		t.Errorf("expect %v provider name, got %v", e, a)
+	}
+	if e, a := "ibm-iam", creds.GetCredentialsType(); e != a {
+		t.Errorf("expect %v credentials type, got %v", e, a)
+	}
+
+	req := server.Requests()[1]
+	if e, a := ibmcredstest.IdentityTokenPath, req.Path; e != a {
+		t.Errorf("expect %v path, got %v", e, a)
+	}
+	if e, a := tokens[0].AccessToken, req.AccessToken; e != a {
+		t.Errorf("expect base %v token, got %v", e, a)
+	}
+	if e, a := testProfileCRN, req.Profile; e != a {
+		t.Errorf("expect %v profile, got %v", e, a)
+	}
+}
+
+func TestAssumeTrustedProfile_Refresh(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+	server.SetTokenTTL(-time.Minute)
+
+	base := ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL)
+	creds := ibmcreds.AssumeTrustedProfile(base, "", func(p *ibmcreds.TrustedProfileProvider) {
+		p.IAMEndpoint = server.URL
+		p.ProfileName = "profile"
+		p.AccountID = "account"
+		p.ServiceInstanceID = "otherInstanceID"
+	})
+
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "otherInstanceID", v.ServiceInstanceID; e != a {
+		t.Errorf("expect %v service instance ID, got %v", e, a)
+	}
+	if !creds.IsExpired() {
+		t.Errorf("expect assumed credentials with expired token to be expired")
+	}
+
+	server.SetTokenTTL(time.Hour)
+	if _, err := creds.Get(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if creds.IsExpired() {
+		t.Errorf("expect assumed credentials to not be expired")
+	}
+	if e, a := 4, len(server.Tokens()); e != a {
+		t.Errorf("expect base credentials and profile to be refreshed, got %v tokens", a)
+	}
+	if e, a := "profile", server.Requests()[3].Profile; e != a {
+		t.Errorf("expect %v profile, got %v", e, a)
+	}
+}
+
+func TestAssumeTrustedProfile_Errors(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+
+	cases := map[string]struct {
+		Base    *credentials.Credentials
+		Profile func(*ibmcreds.TrustedProfileProvider)
+		Code    string
+	}{
+		"no profile": {
+			Base: ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL),
+			Code: "InvalidTrustedProfile",
+		},
+		"name without account": {
+			Base: ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL),
+			Profile: func(p *ibmcreds.TrustedProfileProvider) {
+				p.ProfileName = "profile"
+			},
+			Code: "InvalidTrustedProfile",
+		},
+		"no base credentials": {
+			Profile: func(p *ibmcreds.TrustedProfileProvider) {
+				p.ProfileID = "Profile-1234"
+			},
+			Code: "InvalidTrustedProfile",
+		},
+		"base without token": {
+			Base: credentials.NewStaticCredentials("AKID", "SECRET", ""),
+			Profile: func(p *ibmcreds.TrustedProfileProvider) {
+				p.ProfileID = "Profile-1234"
+			},
+			Code: "TrustedProfileError",
+		},
+		"unknown base token": {
+			Base: credentials.NewStaticCredentials("", "", "unknown"),
+			Profile: func(p *ibmcreds.TrustedProfileProvider) {
+				p.ProfileID = "Profile-1234"
+			},
+			Code: "TrustedProfileError",
+		},
+	}
+
+	for name, c := range cases {
+		creds := ibmcreds.AssumeTrustedProfile(c.Base, "", func(p *ibmcreds.TrustedProfileProvider) {
+			p.IAMEndpoint = server.URL
+			if c.Profile != nil {
+				c.Profile(p)
+			}
+		})
+		_, err := creds.Get()
+		if err == nil {
+			t.Fatalf("%s, expect error, got none", name)
+		}
+		if e, a := c.Code, err.(awserr.Error).Code(); e != a {
+			t.Errorf("%s, expect %v error code, got %v", name, e, a)
+		}
+	}
+}
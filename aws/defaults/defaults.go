@@ -73,11 +73,22 @@ func Handlers() request.Handlers {
 	handlers.Validate.AfterEachFn = request.HandlerListStopOnError
 	handlers.Build.PushBackNamed(corehandlers.SDKVersionUserAgentHandler)
 	handlers.Build.AfterEachFn = request.HandlerListStopOnError
+	handlers.Sign.PushBackNamed(request.RouteEndpointHandler)
 	handlers.Sign.PushBackNamed(corehandlers.BuildContentLengthHandler)
 	handlers.Send.PushBackNamed(corehandlers.ValidateReqSigHandler)
+	handlers.Send.PushBackNamed(request.AcceptGzipEncodingHandler)
 	handlers.Send.PushBackNamed(corehandlers.SendHandler)
+	handlers.Retry.PushBackNamed(request.ResponseBodySizeErrorHandler)
 	handlers.AfterRetry.PushBackNamed(corehandlers.AfterRetryHandler)
+	handlers.UnmarshalMeta.PushBackNamed(request.DecompressGzipResponseHandler)
+	handlers.UnmarshalMeta.PushBackNamed(request.LimitResponseBodyHandler)
+	handlers.UnmarshalMeta.PushBackNamed(request.LimitResponseBodySizeHandler)
 	handlers.ValidateResponse.PushBackNamed(corehandlers.ValidateResponseHandler)
+	handlers.BeforeSign.AfterEachFn = request.HandlerListStopOnError
+	handlers.AfterSign.AfterEachFn = request.HandlerListStopOnError
+	handlers.BeforeSend.PushBackNamed(request.WaitRateLimitHandler)
+	handlers.BeforeSend.AfterEachFn = request.HandlerListStopOnError
+	handlers.AfterUnmarshal.AfterEachFn = request.HandlerListStopOnError
 
 	return handlers
 }
@@ -0,0 +1,195 @@
+package defaults
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// IBMCOSMaxRetries is the maximum number of retries of IBM COS clients.
+const IBMCOSMaxRetries = 5
+
+// IBMCOSEndpointDomain is the domain of the IBM COS endpoints.
+const IBMCOSEndpointDomain = "cloud-object-storage.appdomain.cloud"
+
+// ErrCodeIBMCOSFIPSEndpointNotAvailable is the error code returned when the
+// FIPS endpoint of a region without one is resolved.
+const ErrCodeIBMCOSFIPSEndpointNotAvailable = "FIPSEndpointNotAvailable"
+
+// ibmCOSFIPSRegions are the regions with IBM COS FIPS endpoints.
+var ibmCOSFIPSRegions = map[string]bool{
+	"us-south": true,
+	"us-east":  true,
+}
+
+// ibmCOSEndpointsID is the endpoints ID of the S3 service, whose endpoints
+// are resolved to IBM COS endpoints.
+const ibmCOSEndpointsID = "s3"
+
+// Timeouts of the IBM COS HTTP client's transport. The client has no overall
+// timeout, so that large objects can be streamed.
+const (
+	ibmCOSDialTimeout           = 30 * time.Second
+	ibmCOSKeepAlive             = 30 * time.Second
+	ibmCOSTLSHandshakeTimeout   = 10 * time.Second
+	ibmCOSResponseHeaderTimeout = 60 * time.Second
+	ibmCOSIdleConnTimeout       = 90 * time.Second
+	ibmCOSExpectContinueTimeout = 1 * time.Second
+	ibmCOSMaxIdleConnsPerHost   = 64
+)
+
+// IBMCOSConfig returns the SDK's default values for IBM Cloud Object Storage
+// (COS) clients. The Config and Handlers are the SDK defaults with:
+//
+//   * the client configuration of IBMCOSClientConfig, resolving the public
+//     COS endpoints and retrying requests up to IBMCOSMaxRetries times,
+//   * the HTTP client of IBMCOSHTTPClient,
+//   * the handlers of IBMCOSHandlers, signing requests with the sign handler
+//     provided, such as ibm.SignRequestHandler.
+//
+// Each piece is returned by its own function, so that it can be replaced
+// individually, and the Config can be merged with values overriding the
+// preset's. The preset is used to create sessions for COS clients, the
+// Retryer is left unset so that the COS retryer set by cos.New is used.
+//
+//     d := defaults.IBMCOSConfig(ibm.SignRequestHandler)
+//     d.Config.EndpointResolver = defaults.IBMCOSEndpointResolver("private")
+//     sess := session.Must(session.NewSession(d.Config, &aws.Config{
+//         Region:      aws.String("us-south"),
+//         Credentials: creds,
+//     }))
+//     svc := cos.New(sess)
+func IBMCOSConfig(signer request.NamedHandler) Defaults {
+	cfg := Config().WithHTTPClient(IBMCOSHTTPClient())
+	cfg.MergeIn(IBMCOSClientConfig())
+
+	return Defaults{
+		Config:   cfg,
+		Handlers: IBMCOSHandlers(signer),
+	}
+}
+
+// IBMCOSClientConfig returns the configuration COS clients are created with
+// by cos.New, which is merged over the configuration of the session. It
+// only sets the values specific to COS clients: the EndpointResolver of the
+// public COS endpoints, the MaxRetries of IBMCOSMaxRetries, and the
+// validation of the bucket names and LocationConstraint of CreateBucket
+// requests.
+func IBMCOSClientConfig() *aws.Config {
+	return aws.NewConfig().
+		WithEndpointResolver(IBMCOSEndpointResolver("")).
+		WithMaxRetries(IBMCOSMaxRetries).
+		WithS3ValidateCOSBucket(true)
+}
+
+// IBMCOSHTTPClient returns a new HTTP client for IBM COS. Its transport
+// bounds the time spent dialing, negotiating TLS, and waiting for response
+// headers, and keeps enough idle connections to COS endpoints for concurrent
+// multipart uploads and downloads. The client itself has no timeout, so that
+// large objects can be streamed.
+func IBMCOSHTTPClient() *http.Client {
+	t := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		MaxIdleConnsPerHost:   ibmCOSMaxIdleConnsPerHost,
+		TLSHandshakeTimeout:   ibmCOSTLSHandshakeTimeout,
+		ResponseHeaderTimeout: ibmCOSResponseHeaderTimeout,
+	}
+	setIBMCOSTransportOptions(t, &net.Dialer{
+		Timeout:   ibmCOSDialTimeout,
+		KeepAlive: ibmCOSKeepAlive,
+	})
+
+	return &http.Client{Transport: t}
+}
+
+// IBMCOSHandlers returns the default request handlers of IBM COS clients,
+// the handlers of Handlers signing requests with the sign handler provided.
+// The sign handler is provided by the caller as the IBM IAM signer,
+// ibm.SignRequestHandler, depends on packages which depend on this package.
+func IBMCOSHandlers(signer request.NamedHandler) request.Handlers {
+	handlers := Handlers()
+	handlers.Sign.PushBackNamed(signer)
+
+	return handlers
+}
+
+// IBMCOSEndpoint returns the IBM COS endpoint host of the region and endpoint
+// type, such as "s3.us-south.cloud-object-storage.appdomain.cloud" for the
+// public endpoint of the us-south region. An empty endpoint type is the
+// public endpoint type.
+func IBMCOSEndpoint(region, endpointType string) string {
+	switch endpointType {
+	case "", "public":
+		return "s3." + region + "." + IBMCOSEndpointDomain
+	default:
+		return "s3." + endpointType + "." + region + "." + IBMCOSEndpointDomain
+	}
+}
+
+// IBMCOSFIPSEndpoint returns the IBM COS FIPS endpoint host of the region and
+// endpoint type, such as "s3.fips.us-south.cloud-object-storage.appdomain.cloud"
+// for the public FIPS endpoint of the us-south region. An error with the code
+// ErrCodeIBMCOSFIPSEndpointNotAvailable is returned if the region has no FIPS
+// endpoint.
+func IBMCOSFIPSEndpoint(region, endpointType string) (string, error) {
+	if !ibmCOSFIPSRegions[region] {
+		regions := make([]string, 0, len(ibmCOSFIPSRegions))
+		for r := range ibmCOSFIPSRegions {
+			regions = append(regions, r)
+		}
+		sort.Strings(regions)
+		return "", awserr.New(ErrCodeIBMCOSFIPSEndpointNotAvailable,
+			fmt.Sprintf("region %q has no IBM COS FIPS endpoint, FIPS endpoints are available in %s",
+				region, strings.Join(regions, ", ")), nil)
+	}
+
+	switch endpointType {
+	case "", "public":
+		return "s3.fips." + region + "." + IBMCOSEndpointDomain, nil
+	default:
+		return "s3." + endpointType + ".fips." + region + "." + IBMCOSEndpointDomain, nil
+	}
+}
+
+// IBMCOSEndpointResolver returns an endpoints.Resolver resolving the S3
+// endpoints of regions to the IBM COS endpoints of the endpoint type
+// provided, such as "private", or of the EndpointType option if set. The
+// FIPS endpoints are resolved if the UseFIPSEndpoint option is set, see
+// IBMCOSFIPSEndpoint. Endpoints of other services are resolved by the
+// default resolver.
+func IBMCOSEndpointResolver(endpointType string) endpoints.Resolver {
+	return endpoints.ResolverFunc(func(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+		if service != ibmCOSEndpointsID {
+			return endpoints.DefaultResolver().EndpointFor(service, region, opts...)
+		}
+
+		var o endpoints.Options
+		o.Set(opts...)
+
+		endpointType := endpointType
+		if len(o.EndpointType) != 0 {
+			endpointType = o.EndpointType
+		}
+
+		host := IBMCOSEndpoint(region, endpointType)
+		if o.UseFIPSEndpoint {
+			var err error
+			if host, err = IBMCOSFIPSEndpoint(region, endpointType); err != nil {
+				return endpoints.ResolvedEndpoint{}, err
+			}
+		}
+
+		return endpoints.ResolvedEndpoint{
+			URL:           endpoints.AddScheme(host, o.DisableSSL),
+			SigningRegion: region,
+		}, nil
+	})
+}
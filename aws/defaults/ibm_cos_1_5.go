@@ -0,0 +1,14 @@
+// +build !go1.6
+
+package defaults
+
+import (
+	"net"
+	"net/http"
+)
+
+// setIBMCOSTransportOptions sets the transport to dial with the dialer. The
+// timeout of the continue status cannot be configured before Go 1.6.
+func setIBMCOSTransportOptions(t *http.Transport, d *net.Dialer) {
+	t.Dial = d.Dial
+}
@@ -0,0 +1,15 @@
+// +build go1.6,!go1.7
+
+package defaults
+
+import (
+	"net"
+	"net/http"
+)
+
+// setIBMCOSTransportOptions sets the transport to dial with the dialer. Idle
+// connections are not limited in total, or timed out, before Go 1.7.
+func setIBMCOSTransportOptions(t *http.Transport, d *net.Dialer) {
+	t.Dial = d.Dial
+	t.ExpectContinueTimeout = ibmCOSExpectContinueTimeout
+}
@@ -0,0 +1,17 @@
+// +build go1.7
+
+package defaults
+
+import (
+	"net"
+	"net/http"
+)
+
+// setIBMCOSTransportOptions sets the options of the IBM COS HTTP client's
+// transport which are only available as of Go 1.7.
+func setIBMCOSTransportOptions(t *http.Transport, d *net.Dialer) {
+	t.DialContext = d.DialContext
+	t.MaxIdleConns = 100
+	t.IdleConnTimeout = ibmCOSIdleConnTimeout
+	t.ExpectContinueTimeout = ibmCOSExpectContinueTimeout
+}
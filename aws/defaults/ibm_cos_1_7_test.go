@@ -0,0 +1,59 @@
+// +build go1.7
+
+package defaults
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+func TestIBMCOSEndpointResolverFIPS(t *testing.T) {
+	cases := map[string]struct {
+		EndpointType string
+		Region       string
+		Expect       string
+		ErrCode      string
+	}{
+		"public": {
+			Region: "us-south",
+			Expect: "https://s3.fips.us-south.cloud-object-storage.appdomain.cloud",
+		},
+		"private": {
+			EndpointType: "private",
+			Region:       "us-east",
+			Expect:       "https://s3.private.fips.us-east.cloud-object-storage.appdomain.cloud",
+		},
+		"no FIPS endpoint": {
+			Region:  "eu-de",
+			ErrCode: ErrCodeIBMCOSFIPSEndpointNotAvailable,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := IBMCOSEndpointResolver(c.EndpointType)
+			e, err := r.EndpointFor("s3", c.Region, endpoints.UseFIPSEndpointOption)
+			if len(c.ErrCode) != 0 {
+				if err == nil {
+					t.Fatalf("expect error, got none")
+				}
+				if e, a := c.ErrCode, err.(awserr.Error).Code(); e != a {
+					t.Errorf("expect %v error code, got %v", e, a)
+				}
+				if e, a := "us-east, us-south", err.Error(); !strings.Contains(a, e) {
+					t.Errorf("expect %q in error, got %q", e, a)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, e.URL; e != a {
+				t.Errorf("expect %v endpoint, got %v", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package defaults
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+// ErrCodeIBMCOSRegionNotAllowed is the error code returned when the endpoint
+// of a region not allowed by an IBMCOSRegionPolicy is resolved.
+const ErrCodeIBMCOSRegionNotAllowed = "RegionNotAllowed"
+
+// IBMCOSRegionPolicy is a compliance constraint on the IBM COS regions
+// clients may use, such as the EU-managed regions of IBMCOSEUManagedPolicy.
+// Cross-region and single site locations, such as "eu" or "ams03", are only
+// allowed if they are listed in the Regions.
+type IBMCOSRegionPolicy struct {
+	// Name of the policy, included in the errors of regions not allowed.
+	Name string
+
+	// Regions clients may use.
+	Regions []string
+
+	// DefaultRegion is the region used by clients without a region, which
+	// must be one of the Regions. Clients without a region fail to resolve
+	// their endpoint if empty.
+	DefaultRegion string
+}
+
+// IBMCOSEUManagedPolicy returns the IBMCOSRegionPolicy of the EU-managed
+// COS regions, eu-de and eu-gb, which defaults to eu-de. The "eu"
+// cross-region location is not allowed.
+func IBMCOSEUManagedPolicy() IBMCOSRegionPolicy {
+	return IBMCOSRegionPolicy{
+		Name:          "EU-managed",
+		Regions:       []string{"eu-de", "eu-gb"},
+		DefaultRegion: "eu-de",
+	}
+}
+
+// Allowed returns whether the region is one of the policy's regions.
+func (p IBMCOSRegionPolicy) Allowed(region string) bool {
+	for _, r := range p.Regions {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate returns an error with the code ErrCodeIBMCOSRegionNotAllowed if
+// the region is not allowed by the policy.
+func (p IBMCOSRegionPolicy) Validate(region string) error {
+	if p.Allowed(region) {
+		return nil
+	}
+
+	return awserr.New(ErrCodeIBMCOSRegionNotAllowed,
+		fmt.Sprintf("region %q is not allowed by the %s COS region policy, allowed regions are %s",
+			region, p.Name, strings.Join(p.Regions, ", ")), nil)
+}
+
+// IBMCOSRegulatedEndpointResolver returns an endpoints.Resolver resolving
+// the S3 endpoints of the regions allowed by the policy to the IBM COS
+// endpoints of the endpoint type, the same as IBMCOSEndpointResolver. The
+// endpoints of regions not allowed by the policy fail to resolve, so that
+// compliance constraints are enforced by the configuration of clients.
+// Clients without a region use the policy's DefaultRegion. Endpoints of
+// other services, such as the global IBM IAM endpoint, are resolved by the
+// default resolver.
+//
+//     sess := session.Must(session.NewSession(&aws.Config{
+//         EndpointResolver: defaults.IBMCOSRegulatedEndpointResolver(
+//             defaults.IBMCOSEUManagedPolicy(), "private"),
+//         Region:      aws.String("eu-gb"),
+//         Credentials: creds,
+//     }))
+//     svc := cos.New(sess)
+//
+// Requests of clients created for other regions fail with an error with the
+// code ErrCodeIBMCOSRegionNotAllowed.
+func IBMCOSRegulatedEndpointResolver(policy IBMCOSRegionPolicy, endpointType string) endpoints.Resolver {
+	resolver := IBMCOSEndpointResolver(endpointType)
+
+	return endpoints.ResolverFunc(func(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+		if service != ibmCOSEndpointsID {
+			return resolver.EndpointFor(service, region, opts...)
+		}
+
+		if len(region) == 0 {
+			region = policy.DefaultRegion
+		}
+		if err := policy.Validate(region); err != nil {
+			return endpoints.ResolvedEndpoint{}, err
+		}
+
+		return resolver.EndpointFor(service, region, opts...)
+	})
+}
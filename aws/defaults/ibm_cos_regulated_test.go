@@ -0,0 +1,69 @@
+// +build go1.7
+
+package defaults
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+func TestIBMCOSRegulatedEndpointResolver(t *testing.T) {
+	cases := map[string]struct {
+		Service string
+		Region  string
+		Expect  string
+		ErrCode string
+	}{
+		"allowed": {
+			Service: "s3",
+			Region:  "eu-gb",
+			Expect:  "https://s3.direct.eu-gb.cloud-object-storage.appdomain.cloud",
+		},
+		"default region": {
+			Service: "s3",
+			Expect:  "https://s3.direct.eu-de.cloud-object-storage.appdomain.cloud",
+		},
+		"region not allowed": {
+			Service: "s3",
+			Region:  "us-south",
+			ErrCode: ErrCodeIBMCOSRegionNotAllowed,
+		},
+		"cross-region not allowed": {
+			Service: "s3",
+			Region:  "eu",
+			ErrCode: ErrCodeIBMCOSRegionNotAllowed,
+		},
+		"other service": {
+			Service: "sts",
+			Region:  "us-east-1",
+			Expect:  "https://sts.amazonaws.com",
+		},
+	}
+
+	r := IBMCOSRegulatedEndpointResolver(IBMCOSEUManagedPolicy(), "direct")
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			e, err := r.EndpointFor(c.Service, c.Region)
+			if len(c.ErrCode) != 0 {
+				if err == nil {
+					t.Fatalf("expect error, got none")
+				}
+				if e, a := c.ErrCode, err.(awserr.Error).Code(); e != a {
+					t.Errorf("expect %v error code, got %v", e, a)
+				}
+				if e, a := "allowed regions are eu-de, eu-gb", err.Error(); !strings.Contains(a, e) {
+					t.Errorf("expect %q in error, got %q", e, a)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, e.URL; e != a {
+				t.Errorf("expect %v endpoint, got %v", e, a)
+			}
+		})
+	}
+}
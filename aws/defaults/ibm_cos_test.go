@@ -0,0 +1,74 @@
+package defaults
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+var testIBMCOSSigner = request.NamedHandler{
+	Name: "test.Signer", Fn: func(r *request.Request) {},
+}
+
+func TestIBMCOSConfig(t *testing.T) {
+	d := IBMCOSConfig(testIBMCOSSigner)
+
+	e, err := d.Config.EndpointResolver.EndpointFor("s3", "us-south")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "https://s3.us-south.cloud-object-storage.appdomain.cloud", e.URL; e != a {
+		t.Errorf("expect %v endpoint, got %v", e, a)
+	}
+	if e, a := IBMCOSMaxRetries, aws.IntValue(d.Config.MaxRetries); e != a {
+		t.Errorf("expect %v max retries, got %v", e, a)
+	}
+	if !aws.BoolValue(d.Config.S3ValidateCOSBucket) {
+		t.Errorf("expect COS bucket validation")
+	}
+	if d.Config.HTTPClient == http.DefaultClient {
+		t.Errorf("expect COS HTTP client, got default client")
+	}
+	if t1, ok := d.Config.HTTPClient.Transport.(*http.Transport); !ok || t1.ResponseHeaderTimeout == 0 {
+		t.Errorf("expect transport with response header timeout, got %v", d.Config.HTTPClient.Transport)
+	}
+	if d.Config.Credentials == nil || d.Config.Logger == nil {
+		t.Errorf("expect SDK defaults to be set")
+	}
+	if !d.Handlers.Sign.SwapNamed(testIBMCOSSigner) {
+		t.Errorf("expect sign handler")
+	}
+}
+
+func TestIBMCOSConfigOverride(t *testing.T) {
+	d := IBMCOSConfig(testIBMCOSSigner)
+	d.Config.MergeIn(&aws.Config{
+		EndpointResolver: IBMCOSEndpointResolver("private"),
+		MaxRetries:       aws.Int(2),
+	})
+
+	e, err := d.Config.EndpointResolver.EndpointFor("s3", "eu-de")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "https://s3.private.eu-de.cloud-object-storage.appdomain.cloud", e.URL; e != a {
+		t.Errorf("expect %v endpoint, got %v", e, a)
+	}
+	if e, a := 2, aws.IntValue(d.Config.MaxRetries); e != a {
+		t.Errorf("expect %v max retries, got %v", e, a)
+	}
+	if !aws.BoolValue(d.Config.S3ValidateCOSBucket) {
+		t.Errorf("expect COS bucket validation")
+	}
+
+	// Endpoints of other services are resolved by the default resolver.
+	e, err = d.Config.EndpointResolver.EndpointFor("sts", "us-east-1")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "https://sts.amazonaws.com", e.URL; e != a {
+		t.Errorf("expect %v endpoint, got %v", e, a)
+	}
+}
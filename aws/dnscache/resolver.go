@@ -0,0 +1,201 @@
+// Package dnscache provides an opt-in caching DNS resolver for the SDK's HTTP
+// transport.
+//
+// High request rate workloads against IBM COS and IAM endpoints resolve the
+// same small set of hostnames over and over. The Resolver caches the result
+// of each lookup for a configurable TTL, optionally refreshes cached hosts in
+// the background, and will continue to serve the last known good addresses
+// when a refresh fails. This shields requests from latency spikes caused by
+// slow or briefly unavailable local resolvers.
+//
+// The Resolver is enabled by setting the aws.Config DNSCache field when
+// creating a Session.
+//
+//     sess := session.Must(session.NewSession(&aws.Config{
+//         DNSCache: dnscache.New(5*time.Minute, func(r *dnscache.Resolver) {
+//             r.RefreshInterval = time.Minute
+//         }),
+//     }))
+//
+// Go 1.8 and later:
+// The Resolver's Lookup and Refresh methods take a context, and connections
+// are dialed with the Resolver's DialContext method.
+//
+// Go 1.7 and before:
+// The Resolver's Lookup and Refresh methods take no context, and connections
+// are dialed with the Resolver's Dial method.
+package dnscache
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is the duration a lookup is cached for when the Resolver's TTL
+// is not set.
+const DefaultTTL = 5 * time.Minute
+
+// A Resolver caches DNS lookups and dials connections using the cached
+// addresses. It is safe to use concurrently, and a single Resolver may be
+// shared by multiple HTTP transports.
+type Resolver struct {
+	// TTL is the duration a successful lookup will be cached for. If zero
+	// DefaultTTL will be used.
+	TTL time.Duration
+
+	// RefreshInterval enables refreshing all cached hosts in the background
+	// at the interval provided. Refreshing keeps entries warm so requests
+	// never wait on a lookup for a host that has been seen before. Disabled
+	// if zero or less.
+	RefreshInterval time.Duration
+
+	// LookupHost is the function used to resolve a host to its addresses.
+	// Defaults to net.DefaultResolver.LookupHost, or net.LookupHost before
+	// Go 1.8.
+	LookupHost LookupHostFunc
+
+	// Dialer is used to establish connections to the resolved addresses.
+	// Defaults to a net.Dialer with a 30 second timeout and keep alive.
+	Dialer *net.Dialer
+
+	// If set will be used to determine the current time. Defaults to
+	// time.Now. Available for testing.
+	CurrentTime func() time.Time
+
+	mu      sync.RWMutex
+	entries map[string]*entry
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stop      chan struct{}
+}
+
+type entry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// New returns a Resolver which caches lookups for the TTL provided. Additional
+// functional options can be provided to configure the Resolver.
+func New(ttl time.Duration, options ...func(*Resolver)) *Resolver {
+	r := &Resolver{
+		TTL: ttl,
+	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
+}
+
+// Forget removes the cached entry of the host from the Resolver, so that
+// the host is resolved again when next looked up, such as when its cached
+// addresses are no longer reachable.
+func (r *Resolver) Forget(host string) {
+	r.mu.Lock()
+	delete(r.entries, host)
+	r.mu.Unlock()
+}
+
+// Flush removes all cached entries from the Resolver.
+func (r *Resolver) Flush() {
+	r.mu.Lock()
+	r.entries = nil
+	r.mu.Unlock()
+}
+
+// Stop stops the background refresh of cached hosts if it was started. The
+// Resolver can continue to be used after Stop, but will no longer refresh
+// cached hosts in the background.
+func (r *Resolver) Stop() {
+	r.startOnce.Do(func() {})
+	r.stopOnce.Do(func() {
+		if r.stop != nil {
+			close(r.stop)
+		}
+	})
+}
+
+// cached returns the cached entry of the host, if any, and whether it has
+// not expired.
+func (r *Resolver) cached(host string) (*entry, bool) {
+	r.mu.RLock()
+	e, ok := r.entries[host]
+	r.mu.RUnlock()
+
+	return e, ok && r.now().Before(e.expires)
+}
+
+// store caches the addresses of the host for the Resolver's TTL.
+func (r *Resolver) store(host string, addrs []string) {
+	ttl := r.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	r.mu.Lock()
+	if r.entries == nil {
+		r.entries = map[string]*entry{}
+	}
+	r.entries[host] = &entry{
+		addrs:   addrs,
+		expires: r.now().Add(ttl),
+	}
+	r.mu.Unlock()
+}
+
+// hosts returns the hosts which are cached.
+func (r *Resolver) hosts() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	hosts := make([]string, 0, len(r.entries))
+	for host := range r.entries {
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+func (r *Resolver) startRefresh() {
+	if r.RefreshInterval <= 0 {
+		return
+	}
+
+	r.startOnce.Do(func() {
+		r.stop = make(chan struct{})
+		go r.refreshLoop(r.RefreshInterval, r.stop)
+	})
+}
+
+func (r *Resolver) refreshLoop(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.refresh()
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (r *Resolver) dialer() *net.Dialer {
+	if r.Dialer != nil {
+		return r.Dialer
+	}
+	return &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+}
+
+func (r *Resolver) now() time.Time {
+	if r.CurrentTime != nil {
+		return r.CurrentTime()
+	}
+	return time.Now()
+}
@@ -0,0 +1,90 @@
+// +build !go1.8
+
+package dnscache
+
+import "net"
+
+// LookupHostFunc resolves the host to its addresses.
+type LookupHostFunc func(host string) ([]string, error)
+
+// Lookup returns the addresses for the host. Cached addresses are returned
+// if they have not expired. If the lookup fails, and addresses for the host
+// were previously cached, the stale addresses will be returned instead of
+// the error.
+func (r *Resolver) Lookup(host string) ([]string, error) {
+	r.startRefresh()
+
+	e, fresh := r.cached(host)
+	if fresh {
+		return e.addrs, nil
+	}
+
+	addrs, err := r.lookup(host)
+	if err != nil {
+		if e != nil {
+			return e.addrs, nil
+		}
+		return nil, err
+	}
+
+	return addrs, nil
+}
+
+// Dial dials the address on the named network using the cached addresses of
+// the address's host. Each of the host's addresses will be tried in order
+// until a connection is established. Dial satisfies the signature of
+// http.Transport's Dial field.
+func (r *Resolver) Dial(network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := r.dialer()
+	if net.ParseIP(host) != nil {
+		return dialer.Dial(network, address)
+	}
+
+	addrs, err := r.Lookup(host)
+	if err != nil {
+		return nil, err
+	}
+
+	var conn net.Conn
+	for _, addr := range addrs {
+		conn, err = dialer.Dial(network, net.JoinHostPort(addr, port))
+		if err == nil {
+			return conn, nil
+		}
+	}
+
+	return nil, err
+}
+
+// Refresh resolves all cached hosts again, updating the cached addresses of
+// each host successfully resolved. Hosts that fail to resolve keep their
+// previously cached addresses.
+func (r *Resolver) Refresh() {
+	for _, host := range r.hosts() {
+		r.lookup(host)
+	}
+}
+
+func (r *Resolver) refresh() {
+	r.Refresh()
+}
+
+func (r *Resolver) lookup(host string) ([]string, error) {
+	lookupFn := r.LookupHost
+	if lookupFn == nil {
+		lookupFn = net.LookupHost
+	}
+
+	addrs, err := lookupFn(host)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store(host, addrs)
+	return addrs, nil
+}
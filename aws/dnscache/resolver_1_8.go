@@ -0,0 +1,93 @@
+// +build go1.8
+
+package dnscache
+
+import (
+	"context"
+	"net"
+)
+
+// LookupHostFunc resolves the host to its addresses.
+type LookupHostFunc func(ctx context.Context, host string) ([]string, error)
+
+// Lookup returns the addresses for the host. Cached addresses are returned
+// if they have not expired. If the lookup fails, and addresses for the host
+// were previously cached, the stale addresses will be returned instead of
+// the error.
+func (r *Resolver) Lookup(ctx context.Context, host string) ([]string, error) {
+	r.startRefresh()
+
+	e, fresh := r.cached(host)
+	if fresh {
+		return e.addrs, nil
+	}
+
+	addrs, err := r.lookup(ctx, host)
+	if err != nil {
+		if e != nil {
+			return e.addrs, nil
+		}
+		return nil, err
+	}
+
+	return addrs, nil
+}
+
+// DialContext dials the address on the named network using the cached
+// addresses of the address's host. Each of the host's addresses will be
+// tried in order until a connection is established. DialContext satisfies
+// the signature of http.Transport's DialContext field.
+func (r *Resolver) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := r.dialer()
+	if net.ParseIP(host) != nil {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	addrs, err := r.Lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var conn net.Conn
+	for _, addr := range addrs {
+		conn, err = dialer.DialContext(ctx, network, net.JoinHostPort(addr, port))
+		if err == nil {
+			return conn, nil
+		}
+	}
+
+	return nil, err
+}
+
+// Refresh resolves all cached hosts again, updating the cached addresses of
+// each host successfully resolved. Hosts that fail to resolve keep their
+// previously cached addresses.
+func (r *Resolver) Refresh(ctx context.Context) {
+	for _, host := range r.hosts() {
+		r.lookup(ctx, host)
+	}
+}
+
+func (r *Resolver) refresh() {
+	r.Refresh(context.Background())
+}
+
+func (r *Resolver) lookup(ctx context.Context, host string) ([]string, error) {
+	lookupFn := r.LookupHost
+	if lookupFn == nil {
+		lookupFn = net.DefaultResolver.LookupHost
+	}
+
+	addrs, err := lookupFn(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store(host, addrs)
+	return addrs, nil
+}
@@ -0,0 +1,182 @@
+// +build go1.8
+
+package dnscache
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type mockLookup struct {
+	calls int
+	addrs []string
+	err   error
+}
+
+func (m *mockLookup) LookupHost(ctx context.Context, host string) ([]string, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.addrs, nil
+}
+
+func TestResolverLookupCached(t *testing.T) {
+	m := &mockLookup{addrs: []string{"127.0.0.1"}}
+	now := time.Now()
+	r := New(time.Minute, func(r *Resolver) {
+		r.LookupHost = m.LookupHost
+		r.CurrentTime = func() time.Time { return now }
+	})
+
+	for i := 0; i < 3; i++ {
+		addrs, err := r.Lookup(context.Background(), "s3.us-south.cloud-object-storage.appdomain.cloud")
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if e, a := "127.0.0.1", addrs[0]; e != a {
+			t.Errorf("expect %v address, got %v", e, a)
+		}
+	}
+	if e, a := 1, m.calls; e != a {
+		t.Errorf("expect %v lookups, got %v", e, a)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := r.Lookup(context.Background(), "s3.us-south.cloud-object-storage.appdomain.cloud"); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 2, m.calls; e != a {
+		t.Errorf("expect %v lookups after expiry, got %v", e, a)
+	}
+}
+
+func TestResolverLookupStaleOnError(t *testing.T) {
+	m := &mockLookup{addrs: []string{"127.0.0.1"}}
+	now := time.Now()
+	r := New(time.Minute, func(r *Resolver) {
+		r.LookupHost = m.LookupHost
+		r.CurrentTime = func() time.Time { return now }
+	})
+
+	if _, err := r.Lookup(context.Background(), "iam.cloud.ibm.com"); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	m.err = fmt.Errorf("lookup failed")
+
+	addrs, err := r.Lookup(context.Background(), "iam.cloud.ibm.com")
+	if err != nil {
+		t.Fatalf("expect stale addresses, got error %v", err)
+	}
+	if e, a := "127.0.0.1", addrs[0]; e != a {
+		t.Errorf("expect %v address, got %v", e, a)
+	}
+
+	if _, err := r.Lookup(context.Background(), "unknown.host"); err == nil {
+		t.Errorf("expect error for host never resolved")
+	}
+}
+
+func TestResolverRefresh(t *testing.T) {
+	m := &mockLookup{addrs: []string{"127.0.0.1"}}
+	r := New(time.Hour, func(r *Resolver) {
+		r.LookupHost = m.LookupHost
+	})
+
+	r.Lookup(context.Background(), "iam.cloud.ibm.com")
+	m.addrs = []string{"127.0.0.2"}
+	r.Refresh(context.Background())
+
+	addrs, _ := r.Lookup(context.Background(), "iam.cloud.ibm.com")
+	if e, a := "127.0.0.2", addrs[0]; e != a {
+		t.Errorf("expect %v address, got %v", e, a)
+	}
+	if e, a := 2, m.calls; e != a {
+		t.Errorf("expect %v lookups, got %v", e, a)
+	}
+
+	r.Flush()
+	r.Lookup(context.Background(), "iam.cloud.ibm.com")
+	if e, a := 3, m.calls; e != a {
+		t.Errorf("expect %v lookups after flush, got %v", e, a)
+	}
+}
+
+func TestResolverDialContext(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen, %v", err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+
+	m := &mockLookup{addrs: []string{"127.0.0.1"}}
+	r := New(time.Minute, func(r *Resolver) {
+		r.LookupHost = m.LookupHost
+	})
+
+	conn, err := r.DialContext(context.Background(), "tcp", net.JoinHostPort("cos.example", port))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	conn.Close()
+
+	if e, a := 1, m.calls; e != a {
+		t.Errorf("expect %v lookups, got %v", e, a)
+	}
+}
+
+func TestResolverBackgroundRefresh(t *testing.T) {
+	var calls int32
+	r := New(time.Hour, func(r *Resolver) {
+		r.RefreshInterval = 10 * time.Millisecond
+		r.LookupHost = func(ctx context.Context, host string) ([]string, error) {
+			atomic.AddInt32(&calls, 1)
+			return []string{"127.0.0.1"}, nil
+		}
+	})
+	defer r.Stop()
+
+	r.Lookup(context.Background(), "iam.cloud.ibm.com")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&calls) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expect cached host to be refreshed in the background")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestResolverForget(t *testing.T) {
+	m := &mockLookup{addrs: []string{"127.0.0.1"}}
+	r := New(time.Minute, func(r *Resolver) {
+		r.LookupHost = m.LookupHost
+	})
+
+	const host = "s3.us-south.cloud-object-storage.appdomain.cloud"
+	r.Lookup(context.Background(), host)
+	r.Forget(host)
+	r.Forget("unknown.example.com")
+	r.Lookup(context.Background(), host)
+
+	if e, a := 2, m.calls; e != a {
+		t.Errorf("expect %v lookups, got %v", e, a)
+	}
+}
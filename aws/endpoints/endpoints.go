@@ -22,6 +22,18 @@ type Options struct {
 	// dualstack endpoints.
 	UseDualStack bool
 
+	// Sets the resolver to resolve the endpoint as a FIPS endpoint for the
+	// service. Resolvers supporting FIPS endpoints, such as the IBM COS
+	// endpoint resolver, return an error if the region has no FIPS
+	// endpoint. Other resolvers ignore the option.
+	UseFIPSEndpoint bool
+
+	// Sets the type of the endpoint resolved, such as "private". Resolvers
+	// supporting endpoint types, such as the IBM COS endpoint resolver, use
+	// it instead of their own endpoint type. Other resolvers ignore the
+	// option.
+	EndpointType string
+
 	// Enables strict matching of services and regions resolved endpoints.
 	// If the partition doesn't enumerate the exact service and region an
 	// error will be returned. This option will prevent returning endpoints
@@ -67,6 +79,12 @@ func UseDualStackOption(o *Options) {
 	o.UseDualStack = true
 }
 
+// UseFIPSEndpointOption sets the UseFIPSEndpoint option. Can be used as a
+// functional option when resolving endpoints.
+func UseFIPSEndpointOption(o *Options) {
+	o.UseFIPSEndpoint = true
+}
+
 // StrictMatchingOption sets the StrictMatching option. Can be used as a functional
 // option when resolving endpoints.
 func StrictMatchingOption(o *Options) {
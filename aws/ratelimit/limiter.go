@@ -0,0 +1,185 @@
+// Package ratelimit provides an opt-in client-side rate limiter for the SDK's
+// API requests.
+//
+// A Limiter limits the rate of requests, and the bandwidth of their request
+// and response bodies, with token buckets. A single Limiter may be shared by
+// the Configs of any number of service clients in a process, so that all of
+// their requests together respect an aggregate budget, such as the request
+// rate a single COS instance is provisioned for.
+//
+// The Limiter is enabled by setting the aws.Config RateLimiter field.
+//
+//     limiter := ratelimit.New(100, 50*1024*1024)
+//
+//     sess := session.Must(session.NewSession(&aws.Config{
+//         RateLimiter: limiter,
+//     }))
+//     uploads := s3.New(sess)
+//     archive := s3.New(sess, &aws.Config{Region: aws.String("eu-de")})
+package ratelimit
+
+import (
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+// A Limiter limits the rate of API requests, and the bandwidth of their
+// bodies. Each attempt of a request, including retries, counts as a request.
+// It is safe to use concurrently, and a single Limiter may be shared by
+// multiple service clients. The Limiter's fields must not be modified once it
+// is in use.
+type Limiter struct {
+	// RequestsPerSecond is the maximum rate of requests. Requests are not
+	// limited if zero or less.
+	RequestsPerSecond float64
+
+	// RequestBurst is the number of requests which may be made at once,
+	// before being limited to RequestsPerSecond. If zero, the greater of one
+	// and RequestsPerSecond will be used.
+	RequestBurst int
+
+	// BytesPerSecond is the maximum bandwidth, in bytes per second, of the
+	// request and response bodies together. Bodies are read up to one
+	// second's worth of bytes at once. Bandwidth is not limited if zero or
+	// less.
+	BytesPerSecond int64
+
+	// If set will be used to determine the current time. Defaults to
+	// time.Now. Available for testing.
+	CurrentTime func() time.Time
+
+	mu       sync.Mutex
+	requests bucket
+	bytes    bucket
+}
+
+// New returns a Limiter limiting requests to requestsPerSecond, and the
+// bandwidth of their bodies to bytesPerSecond. Either limit is disabled if
+// zero. Pass in additional functional options to customize the limiter.
+func New(requestsPerSecond float64, bytesPerSecond int64, options ...func(*Limiter)) *Limiter {
+	l := &Limiter{
+		RequestsPerSecond: requestsPerSecond,
+		BytesPerSecond:    bytesPerSecond,
+	}
+	for _, option := range options {
+		option(l)
+	}
+
+	return l
+}
+
+// Wait blocks until the Limiter allows another request, or the context is
+// canceled, returning the context's error.
+func (l *Limiter) Wait(ctx Context) error {
+	if l.RequestsPerSecond <= 0 {
+		return nil
+	}
+
+	burst := float64(l.RequestBurst)
+	if burst <= 0 {
+		burst = math.Max(1, l.RequestsPerSecond)
+	}
+	return l.wait(ctx, &l.requests, l.RequestsPerSecond, burst, 1)
+}
+
+// WaitBytes blocks until the Limiter allows another n bytes to be
+// transferred, or the context is canceled, returning the context's error.
+func (l *Limiter) WaitBytes(ctx Context, n int64) error {
+	if l.BytesPerSecond <= 0 || n <= 0 {
+		return nil
+	}
+
+	rate := float64(l.BytesPerSecond)
+	return l.wait(ctx, &l.bytes, rate, rate, float64(n))
+}
+
+// ReadCloser returns a reader limiting the bandwidth the body is read at.
+// The body is returned unchanged if the bandwidth is not limited.
+func (l *Limiter) ReadCloser(ctx Context, body io.ReadCloser) io.ReadCloser {
+	if l.BytesPerSecond <= 0 {
+		return body
+	}
+	return &limitedReadCloser{ReadCloser: body, ctx: ctx, l: l}
+}
+
+// wait reserves n tokens of the bucket, and waits until they are available.
+// The tokens are returned to the bucket if the context is canceled first.
+func (l *Limiter) wait(ctx Context, b *bucket, rate, burst, n float64) error {
+	l.mu.Lock()
+	delay := b.reserve(l.now(), rate, burst, n)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		b.tokens += n
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+func (l *Limiter) now() time.Time {
+	if l.CurrentTime != nil {
+		return l.CurrentTime()
+	}
+	return time.Now()
+}
+
+// bucket is a token bucket. Tokens are reserved ahead of their availability,
+// leaving the bucket in debt, so that waiters are served in order.
+type bucket struct {
+	tokens  float64
+	last    time.Time
+	started bool
+}
+
+// reserve takes n tokens from the bucket, and returns the duration until
+// the tokens are available. The bucket starts full.
+func (b *bucket) reserve(now time.Time, rate, burst, n float64) time.Duration {
+	if !b.started {
+		b.tokens = burst
+		b.last = now
+		b.started = true
+	}
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed.Seconds()*rate)
+		b.last = now
+	}
+
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / rate * float64(time.Second))
+}
+
+// limitedReadCloser limits the bandwidth a body is read at.
+type limitedReadCloser struct {
+	io.ReadCloser
+	ctx Context
+	l   *Limiter
+}
+
+func (r *limitedReadCloser) Read(p []byte) (int, error) {
+	if max := r.l.BytesPerSecond; int64(len(p)) > max {
+		p = p[:max]
+	}
+
+	n, err := r.ReadCloser.Read(p)
+	if waitErr := r.l.WaitBytes(r.ctx, int64(n)); waitErr != nil && err == nil {
+		err = waitErr
+	}
+	return n, err
+}
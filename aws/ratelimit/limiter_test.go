@@ -0,0 +1,113 @@
+// +build go1.7
+
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestBucketReserve(t *testing.T) {
+	now := time.Now()
+	var b bucket
+
+	cases := []struct {
+		advance time.Duration
+		n       float64
+		expect  time.Duration
+	}{
+		// The bucket starts full with the burst of 2 tokens.
+		{0, 1, 0},
+		{0, 1, 0},
+		// Empty, the next token is available in 1/rate seconds.
+		{0, 1, 100 * time.Millisecond},
+		{0, 1, 200 * time.Millisecond},
+		// Refilled by 5 tokens, capped at the burst, after the debt of 2.
+		{500 * time.Millisecond, 1, 0},
+		{0, 2, 100 * time.Millisecond},
+	}
+
+	for i, c := range cases {
+		now = now.Add(c.advance)
+		if e, a := c.expect, b.reserve(now, 10, 2, c.n); e != a {
+			t.Errorf("%d, expect %v delay, got %v", i, e, a)
+		}
+	}
+}
+
+func TestLimiterWait(t *testing.T) {
+	l := New(100, 0, func(l *Limiter) {
+		l.RequestBurst = 1
+	})
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("expect 5 requests to take at least 40ms, took %v", elapsed)
+	}
+}
+
+func TestLimiterWaitCanceled(t *testing.T) {
+	now := time.Now()
+	l := New(1, 0, func(l *Limiter) {
+		l.CurrentTime = func() time.Time { return now }
+	})
+
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if e, a := context.Canceled, l.Wait(ctx); e != a {
+		t.Errorf("expect %v error, got %v", e, a)
+	}
+
+	// The tokens of the canceled wait are returned to the bucket.
+	now = now.Add(time.Second)
+	if err := l.Wait(ctx); err != nil {
+		t.Errorf("expect no error, got %v", err)
+	}
+}
+
+func TestLimiterUnlimited(t *testing.T) {
+	l := New(0, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 100; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+
+	body := ioutil.NopCloser(bytes.NewReader(nil))
+	if l.ReadCloser(ctx, body) != body {
+		t.Errorf("expect body not to be wrapped")
+	}
+}
+
+func TestLimiterReadCloser(t *testing.T) {
+	l := New(0, 1000)
+
+	start := time.Now()
+	b, err := ioutil.ReadAll(l.ReadCloser(context.Background(),
+		ioutil.NopCloser(bytes.NewReader(make([]byte, 1200)))))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 1200, len(b); e != a {
+		t.Errorf("expect %v bytes, got %v", e, a)
+	}
+	// The first 1000 bytes are the burst, the next 200 take 200ms.
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("expect read to take at least 150ms, took %v", elapsed)
+	}
+}
@@ -0,0 +1,229 @@
+package request
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+const (
+	// ErrCodeBufferBody is the error code returned when the body of a request
+	// could not be buffered by its BodyBuffer.
+	ErrCodeBufferBody = "BufferBodyError"
+
+	// ErrCodeBodyTooLarge is the error code returned when the body of a
+	// request is larger than the MaxSize of its SpillBodyBuffer.
+	ErrCodeBodyTooLarge = "BodyTooLarge"
+)
+
+const (
+	// DefaultSpillMemoryLimit is the size of the bodies a SpillBodyBuffer
+	// buffers in memory if its MemoryLimit is not set.
+	DefaultSpillMemoryLimit = 5 * 1024 * 1024
+)
+
+// BufferBodyHandlerName is the name of the Build handler buffering request
+// bodies which are not seekable, added by WithBodyBuffer.
+const BufferBodyHandlerName = "core.BufferBodyHandler"
+
+// A BufferedBody is a seekable copy of a request's body, released by Close
+// once the request completes.
+type BufferedBody interface {
+	io.ReadSeeker
+	io.Closer
+}
+
+// A BodyBuffer buffers the bodies of requests which are not seekable, such
+// as an aws.ReadSeekCloser of a pipe, so that the body can be rewound when
+// the request is retried, and read by signers computing its checksum.
+//
+// Implementations must be safe to use concurrently.
+type BodyBuffer interface {
+	// BufferBody reads the body until EOF, returning a copy of it rewound
+	// to its start.
+	BufferBody(body io.Reader) (BufferedBody, error)
+}
+
+// WithBodyBuffer is a request option buffering the request's body with the
+// BodyBuffer if it is not seekable, instead of failing to rewind it when the
+// request is retried. Seekable bodies are not buffered.
+//
+//     pr, pw := io.Pipe()
+//     go func() {
+//         pw.CloseWithError(cmd.Run())
+//     }()
+//     _, err := svc.PutObjectWithContext(ctx, &s3.PutObjectInput{
+//         Bucket: aws.String("bucket"),
+//         Key:    aws.String("key"),
+//         Body:   aws.ReadSeekCloser(pr),
+//     }, request.WithBodyBuffer(&request.SpillBodyBuffer{MaxSize: 1 << 30}))
+//
+// Use AddBodyBufferHandler to buffer the bodies of all requests of a client.
+func WithBodyBuffer(buf BodyBuffer) Option {
+	return func(r *Request) {
+		AddBodyBufferHandler(&r.Handlers, buf)
+	}
+}
+
+// AddBodyBufferHandler adds the Build handler buffering the bodies of
+// requests which are not seekable with the BodyBuffer to the handlers. The
+// handler must run after the body is set by the protocol's Build handler.
+//
+//     svc := s3.New(sess)
+//     request.AddBodyBufferHandler(&svc.Handlers, &request.SpillBodyBuffer{})
+func AddBodyBufferHandler(h *Handlers, buf BodyBuffer) {
+	h.Build.SetBackNamed(NamedHandler{
+		Name: BufferBodyHandlerName,
+		Fn: func(r *Request) {
+			bufferBody(r, buf)
+		},
+	})
+}
+
+// bufferBody replaces the request's body with a copy buffered by the
+// BodyBuffer, if the body is not seekable. The copy is released when the
+// request completes.
+func bufferBody(r *Request, buf BodyBuffer) {
+	if r.Body == nil || isSeekable(r.Body) {
+		return
+	}
+
+	body, err := buf.BufferBody(r.Body)
+	if err != nil {
+		if _, ok := err.(awserr.Error); !ok {
+			err = awserr.New(ErrCodeBufferBody, "failed to buffer request body", err)
+		}
+		r.Error = err
+		return
+	}
+
+	r.SetReaderBody(body)
+	r.Handlers.Complete.PushBack(func(*Request) {
+		body.Close()
+	})
+}
+
+// isSeekable returns false if the body is an aws.ReaderSeekerCloser of a
+// reader which is not an io.Seeker.
+func isSeekable(body io.ReadSeeker) bool {
+	switch v := body.(type) {
+	case aws.ReaderSeekerCloser:
+		return v.IsSeeker()
+	case *aws.ReaderSeekerCloser:
+		return v.IsSeeker()
+	}
+	return true
+}
+
+// SpillBodyBuffer is a BodyBuffer buffering bodies in memory up to its
+// MemoryLimit, and spilling larger bodies to temporary files, which are
+// removed once their requests complete.
+type SpillBodyBuffer struct {
+	// MemoryLimit is the size of the bodies buffered in memory. Larger bodies
+	// are buffered in temporary files. Defaults to DefaultSpillMemoryLimit
+	// if 0.
+	MemoryLimit int64
+
+	// MaxSize is the size of the largest body buffered. Requests with larger
+	// bodies fail with an error with the code ErrCodeBodyTooLarge. Bodies of
+	// any size are buffered if 0.
+	MaxSize int64
+
+	// Dir is the directory of the temporary files. Defaults to the
+	// os.TempDir if empty.
+	Dir string
+}
+
+// BufferBody reads the body into memory, or into a temporary file if it is
+// larger than the MemoryLimit.
+func (b *SpillBodyBuffer) BufferBody(body io.Reader) (BufferedBody, error) {
+	memoryLimit := b.MemoryLimit
+	if memoryLimit <= 0 {
+		memoryLimit = DefaultSpillMemoryLimit
+	}
+	if b.MaxSize > 0 && b.MaxSize < memoryLimit {
+		memoryLimit = b.MaxSize
+	}
+
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, body, memoryLimit+1)
+	if err == io.EOF {
+		return memoryBody{bytes.NewReader(buf.Bytes())}, nil
+	} else if err != nil {
+		return nil, awserr.New(ErrCodeBufferBody, "failed to read request body", err)
+	}
+	if err := b.checkSize(n); err != nil {
+		return nil, err
+	}
+
+	f, err := ioutil.TempFile(b.Dir, "aws-sdk-go-body-")
+	if err != nil {
+		return nil, awserr.New(ErrCodeBufferBody, "failed to create request body file", err)
+	}
+	spilled := &fileBody{File: f}
+
+	if _, err := buf.WriteTo(f); err != nil {
+		spilled.Close()
+		return nil, awserr.New(ErrCodeBufferBody, "failed to write request body file", err)
+	}
+
+	var rest int64
+	if b.MaxSize > 0 {
+		rest, err = io.CopyN(f, body, b.MaxSize-n+1)
+		if err == io.EOF {
+			err = nil
+		}
+	} else {
+		rest, err = io.Copy(f, body)
+	}
+	if err != nil {
+		spilled.Close()
+		return nil, awserr.New(ErrCodeBufferBody, "failed to buffer request body to file", err)
+	}
+	if err := b.checkSize(n + rest); err != nil {
+		spilled.Close()
+		return nil, err
+	}
+
+	if _, err := f.Seek(0, 0); err != nil { // io.SeekStart
+		spilled.Close()
+		return nil, awserr.New(ErrCodeBufferBody, "failed to rewind request body file", err)
+	}
+	return spilled, nil
+}
+
+// checkSize returns an error with the code ErrCodeBodyTooLarge if the size
+// is larger than the MaxSize.
+func (b *SpillBodyBuffer) checkSize(size int64) error {
+	if b.MaxSize > 0 && size > b.MaxSize {
+		return awserr.New(ErrCodeBodyTooLarge,
+			fmt.Sprintf("request body is larger than the %d bytes that can be buffered", b.MaxSize), nil)
+	}
+	return nil
+}
+
+// memoryBody is a BufferedBody buffered in memory.
+type memoryBody struct {
+	*bytes.Reader
+}
+
+func (memoryBody) Close() error { return nil }
+
+// fileBody is a BufferedBody buffered in a temporary file, removed when it
+// is closed.
+type fileBody struct {
+	*os.File
+}
+
+func (b *fileBody) Close() error {
+	err := b.File.Close()
+	if rmErr := os.Remove(b.File.Name()); err == nil {
+		err = rmErr
+	}
+	return err
+}
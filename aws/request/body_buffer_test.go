@@ -0,0 +1,139 @@
+package request_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting"
+)
+
+func TestWithBodyBuffer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "body-buffer")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := strings.Repeat("0123456789", 10)
+
+	cases := map[string]struct {
+		Buffer  *request.SpillBodyBuffer
+		Files   int
+		ErrCode string
+	}{
+		"memory": {
+			Buffer: &request.SpillBodyBuffer{Dir: dir},
+		},
+		"spilled": {
+			Buffer: &request.SpillBodyBuffer{Dir: dir, MemoryLimit: 10},
+			Files:  1,
+		},
+		"max size": {
+			Buffer: &request.SpillBodyBuffer{Dir: dir, MemoryLimit: 10, MaxSize: 100},
+			Files:  1,
+		},
+		"too large": {
+			Buffer:  &request.SpillBodyBuffer{Dir: dir, MemoryLimit: 10, MaxSize: 99},
+			ErrCode: request.ErrCodeBodyTooLarge,
+		},
+		"too large in memory": {
+			Buffer:  &request.SpillBodyBuffer{Dir: dir, MaxSize: 50},
+			ErrCode: request.ErrCodeBodyTooLarge,
+		},
+	}
+
+	for name, c := range cases {
+		var bodies []string
+		var files int
+
+		s := awstesting.NewClient(aws.NewConfig().WithMaxRetries(1).WithSleepDelay(func(time.Duration) {}))
+		s.Handlers.Validate.Clear()
+		s.Handlers.Build.PushBack(func(r *request.Request) {
+			// The reader of the body is not an io.Seeker.
+			r.SetReaderBody(aws.ReadSeekCloser(struct{ io.Reader }{strings.NewReader(content)}))
+		})
+		s.Handlers.Send.Clear()
+		s.Handlers.Send.PushBack(func(r *request.Request) {
+			infos, _ := ioutil.ReadDir(dir)
+			files = len(infos)
+
+			b, _ := ioutil.ReadAll(r.GetBody())
+			bodies = append(bodies, string(b))
+
+			status := 200
+			if len(bodies) == 1 {
+				status = 500
+			}
+			r.HTTPResponse = &http.Response{StatusCode: status, Body: ioutil.NopCloser(bytes.NewReader(nil))}
+		})
+		s.Handlers.UnmarshalError.PushBack(func(r *request.Request) {
+			r.Error = awserr.New("InternalError", "internal error", nil)
+		})
+
+		r := s.NewRequest(&request.Operation{Name: "Operation", HTTPMethod: "PUT"}, nil, nil)
+		r.ApplyOptions(request.WithBodyBuffer(c.Buffer))
+		err := r.Send()
+
+		if len(c.ErrCode) != 0 {
+			if err == nil {
+				t.Fatalf("%s, expect error, got none", name)
+			}
+			if e, a := c.ErrCode, err.(awserr.Error).Code(); e != a {
+				t.Errorf("%s, expect %v error code, got %v", name, e, a)
+			}
+			if len(bodies) != 0 {
+				t.Errorf("%s, expect request not to be sent, got %v attempts", name, len(bodies))
+			}
+		} else {
+			if err != nil {
+				t.Fatalf("%s, expect no error, got %v", name, err)
+			}
+			if e, a := 2, len(bodies); e != a {
+				t.Fatalf("%s, expect %v attempts, got %v", name, e, a)
+			}
+			for i, body := range bodies {
+				if e, a := content, body; e != a {
+					t.Errorf("%s, %d, expect %q body, got %q", name, i, e, a)
+				}
+			}
+			if e, a := c.Files, files; e != a {
+				t.Errorf("%s, expect %v files while sending, got %v", name, e, a)
+			}
+		}
+
+		if infos, _ := ioutil.ReadDir(dir); len(infos) != 0 {
+			t.Errorf("%s, expect files to be removed, got %v", name, len(infos))
+		}
+	}
+}
+
+func TestWithBodyBuffer_Seekable(t *testing.T) {
+	body := strings.NewReader("content")
+
+	s := awstesting.NewClient()
+	s.Handlers.Validate.Clear()
+	s.Handlers.Send.Clear()
+	s.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewReader(nil))}
+	})
+
+	r := s.NewRequest(&request.Operation{Name: "Operation", HTTPMethod: "PUT"}, nil, nil)
+	r.SetReaderBody(body)
+	r.ApplyOptions(request.WithBodyBuffer(&request.SpillBodyBuffer{}))
+	if err := r.Send(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if r.Body != body {
+		t.Errorf("expect seekable body not to be buffered")
+	}
+}
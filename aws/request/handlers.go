@@ -3,10 +3,21 @@ package request
 import (
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"unsafe"
 )
 
 // A Handlers provides a collection of request handlers for various
 // stages of handling requests.
+//
+// The BeforeSign, AfterSign, BeforeSend, and AfterUnmarshal lists are
+// extension points for user handlers. The SDK does not add handlers to them,
+// other than the WaitRateLimitHandler at the front of the BeforeSend
+// handlers, so the position of handlers added to them does not depend on the
+// order of the SDK's handlers, which may change between releases. Each list
+// stops at the first handler failing the request, if created with the
+// defaults.Handlers.
 type Handlers struct {
 	Validate         HandlerList
 	Build            HandlerList
@@ -19,6 +30,24 @@ type Handlers struct {
 	Retry            HandlerList
 	AfterRetry       HandlerList
 	Complete         HandlerList
+
+	// BeforeSign handlers are run before the Sign handlers each time the
+	// request is signed, once the request has been built. A failed request
+	// is not signed.
+	BeforeSign HandlerList
+
+	// AfterSign handlers are run after the Sign handlers each time the
+	// request is signed successfully, including when it is presigned.
+	AfterSign HandlerList
+
+	// BeforeSend handlers are run before each attempt of the request is
+	// sent, once signed. A failed request is not sent, nor retried.
+	BeforeSend HandlerList
+
+	// AfterUnmarshal handlers are run after the response of the request has
+	// been unmarshaled successfully. A request failed by an AfterUnmarshal
+	// handler is retried the same as if it failed to unmarshal.
+	AfterUnmarshal HandlerList
 }
 
 // Copy returns of this handler's lists.
@@ -35,6 +64,10 @@ func (h *Handlers) Copy() Handlers {
 		Retry:            h.Retry.copy(),
 		AfterRetry:       h.AfterRetry.copy(),
 		Complete:         h.Complete.copy(),
+		BeforeSign:       h.BeforeSign.copy(),
+		AfterSign:        h.AfterSign.copy(),
+		BeforeSend:       h.BeforeSend.copy(),
+		AfterUnmarshal:   h.AfterUnmarshal.copy(),
 	}
 }
 
@@ -51,6 +84,10 @@ func (h *Handlers) Clear() {
 	h.Retry.Clear()
 	h.AfterRetry.Clear()
 	h.Complete.Clear()
+	h.BeforeSign.Clear()
+	h.AfterSign.Clear()
+	h.BeforeSend.Clear()
+	h.AfterUnmarshal.Clear()
 }
 
 // A HandlerListRunItem represents an entry in the HandlerList which
@@ -62,8 +99,17 @@ type HandlerListRunItem struct {
 }
 
 // A HandlerList manages zero or more handlers in a list.
+//
+// The handlers of a list may be modified while the list is run, or copied
+// for new requests, by concurrent goroutines, such as when adding a handler
+// to a service client in use. The list is copied on write, so requests
+// created before a modification run the handlers they were created with.
+// AfterEachFn is not safe to modify concurrently.
 type HandlerList struct {
-	list []NamedHandler
+	// list points to the []NamedHandler of the list, which is never
+	// modified once stored. The pointer is loaded and stored atomically, and
+	// is shared by copies of the list until either is modified.
+	list unsafe.Pointer
 
 	// Called after each request handler in the list is called. If set
 	// and the func returns true the HandlerList will continue to iterate
@@ -83,27 +129,48 @@ type NamedHandler struct {
 	Fn   func(*Request)
 }
 
-// copy creates a copy of the handler list.
+// handlerListWriteMu serializes the modifications of handler lists. Lists
+// are rarely modified once requests are made, so a single lock is shared.
+var handlerListWriteMu sync.Mutex
+
+// handlers returns the handlers of the list, which must not be modified.
+func (l *HandlerList) handlers() []NamedHandler {
+	p := atomic.LoadPointer(&l.list)
+	if p == nil {
+		return nil
+	}
+	return *(*[]NamedHandler)(p)
+}
+
+// update replaces the handlers of the list with those returned by fn, which
+// must return a new slice instead of modifying the handlers it is passed.
+func (l *HandlerList) update(fn func([]NamedHandler) []NamedHandler) {
+	handlerListWriteMu.Lock()
+	defer handlerListWriteMu.Unlock()
+
+	list := fn(l.handlers())
+	atomic.StorePointer(&l.list, unsafe.Pointer(&list))
+}
+
+// copy creates a copy of the handler list. The handlers are shared until
+// either list is modified.
 func (l *HandlerList) copy() HandlerList {
-	n := HandlerList{
+	return HandlerList{
+		list:        atomic.LoadPointer(&l.list),
 		AfterEachFn: l.AfterEachFn,
 	}
-	if len(l.list) == 0 {
-		return n
-	}
-
-	n.list = append(make([]NamedHandler, 0, len(l.list)), l.list...)
-	return n
 }
 
 // Clear clears the handler list.
 func (l *HandlerList) Clear() {
-	l.list = l.list[0:0]
+	l.update(func([]NamedHandler) []NamedHandler {
+		return []NamedHandler{}
+	})
 }
 
 // Len returns the number of handlers in the list.
 func (l *HandlerList) Len() int {
-	return len(l.list)
+	return len(l.handlers())
 }
 
 // PushBack pushes handler f to the back of the handler list.
@@ -113,10 +180,9 @@ func (l *HandlerList) PushBack(f func(*Request)) {
 
 // PushBackNamed pushes named handler f to the back of the handler list.
 func (l *HandlerList) PushBackNamed(n NamedHandler) {
-	if cap(l.list) == 0 {
-		l.list = make([]NamedHandler, 0, 5)
-	}
-	l.list = append(l.list, n)
+	l.update(func(list []NamedHandler) []NamedHandler {
+		return insertHandler(list, len(list), n)
+	})
 }
 
 // PushFront pushes handler f to the front of the handler list.
@@ -126,15 +192,54 @@ func (l *HandlerList) PushFront(f func(*Request)) {
 
 // PushFrontNamed pushes named handler f to the front of the handler list.
 func (l *HandlerList) PushFrontNamed(n NamedHandler) {
-	if cap(l.list) == len(l.list) {
-		// Allocating new list required
-		l.list = append([]NamedHandler{n}, l.list...)
-	} else {
-		// Enough room to prepend into list.
-		l.list = append(l.list, NamedHandler{})
-		copy(l.list[1:], l.list)
-		l.list[0] = n
-	}
+	l.update(func(list []NamedHandler) []NamedHandler {
+		return insertHandler(list, 0, n)
+	})
+}
+
+// InsertBeforeNamed inserts the named handler n before the first handler of
+// the list with the name provided, such as before the
+// "v4.SignRequestHandler", returning true if inserted. False is returned,
+// and the handler not inserted, if the list has no handler with the name.
+func (l *HandlerList) InsertBeforeNamed(name string, n NamedHandler) (inserted bool) {
+	l.update(func(list []NamedHandler) []NamedHandler {
+		for i := 0; i < len(list); i++ {
+			if list[i].Name == name {
+				inserted = true
+				return insertHandler(list, i, n)
+			}
+		}
+		return list
+	})
+
+	return inserted
+}
+
+// InsertAfterNamed inserts the named handler n after the last handler of
+// the list with the name provided, returning true if inserted. False is
+// returned, and the handler not inserted, if the list has no handler with
+// the name.
+func (l *HandlerList) InsertAfterNamed(name string, n NamedHandler) (inserted bool) {
+	l.update(func(list []NamedHandler) []NamedHandler {
+		for i := len(list) - 1; i >= 0; i-- {
+			if list[i].Name == name {
+				inserted = true
+				return insertHandler(list, i+1, n)
+			}
+		}
+		return list
+	})
+
+	return inserted
+}
+
+// insertHandler returns a copy of the handlers with the named handler n
+// inserted at index i.
+func insertHandler(list []NamedHandler, i int, n NamedHandler) []NamedHandler {
+	newList := make([]NamedHandler, 0, len(list)+1)
+	newList = append(newList, list[:i]...)
+	newList = append(newList, n)
+	return append(newList, list[i:]...)
 }
 
 // Remove removes a NamedHandler n
@@ -144,30 +249,26 @@ func (l *HandlerList) Remove(n NamedHandler) {
 
 // RemoveByName removes a NamedHandler by name.
 func (l *HandlerList) RemoveByName(name string) {
-	for i := 0; i < len(l.list); i++ {
-		m := l.list[i]
-		if m.Name == name {
-			// Shift array preventing creating new arrays
-			copy(l.list[i:], l.list[i+1:])
-			l.list[len(l.list)-1] = NamedHandler{}
-			l.list = l.list[:len(l.list)-1]
-
-			// decrement list so next check to length is correct
-			i--
+	l.update(func(list []NamedHandler) []NamedHandler {
+		newList := make([]NamedHandler, 0, len(list))
+		for _, m := range list {
+			if m.Name != name {
+				newList = append(newList, m)
+			}
 		}
-	}
+		return newList
+	})
 }
 
 // SwapNamed will swap out any existing handlers with the same name as the
 // passed in NamedHandler returning true if handlers were swapped. False is
 // returned otherwise.
 func (l *HandlerList) SwapNamed(n NamedHandler) (swapped bool) {
-	for i := 0; i < len(l.list); i++ {
-		if l.list[i].Name == n.Name {
-			l.list[i].Fn = n.Fn
-			swapped = true
-		}
-	}
+	l.update(func(list []NamedHandler) []NamedHandler {
+		var newList []NamedHandler
+		newList, swapped = swapHandler(list, n)
+		return newList
+	})
 
 	return swapped
 }
@@ -175,23 +276,44 @@ func (l *HandlerList) SwapNamed(n NamedHandler) (swapped bool) {
 // SetBackNamed will replace the named handler if it exists in the handler list.
 // If the handler does not exist the handler will be added to the end of the list.
 func (l *HandlerList) SetBackNamed(n NamedHandler) {
-	if !l.SwapNamed(n) {
-		l.PushBackNamed(n)
-	}
+	l.update(func(list []NamedHandler) []NamedHandler {
+		if newList, ok := swapHandler(list, n); ok {
+			return newList
+		}
+		return insertHandler(list, len(list), n)
+	})
 }
 
 // SetFrontNamed will replace the named handler if it exists in the handler list.
 // If the handler does not exist the handler will be added to the beginning of
 // the list.
 func (l *HandlerList) SetFrontNamed(n NamedHandler) {
-	if !l.SwapNamed(n) {
-		l.PushFrontNamed(n)
+	l.update(func(list []NamedHandler) []NamedHandler {
+		if newList, ok := swapHandler(list, n); ok {
+			return newList
+		}
+		return insertHandler(list, 0, n)
+	})
+}
+
+// swapHandler returns a copy of the handlers with the function of handlers
+// named the same as n replaced, and whether any handler was replaced.
+func swapHandler(list []NamedHandler, n NamedHandler) ([]NamedHandler, bool) {
+	swapped := false
+	newList := append(make([]NamedHandler, 0, len(list)), list...)
+	for i := 0; i < len(newList); i++ {
+		if newList[i].Name == n.Name {
+			newList[i].Fn = n.Fn
+			swapped = true
+		}
 	}
+
+	return newList, swapped
 }
 
 // Run executes all handlers in the list with a given request object.
 func (l *HandlerList) Run(r *Request) {
-	for i, h := range l.list {
+	for i, h := range l.handlers() {
 		h.Fn(r)
 		item := HandlerListRunItem{
 			Index: i, Handler: h, Request: r,
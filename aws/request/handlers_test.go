@@ -197,6 +197,71 @@ func TestStopHandlers(t *testing.T) {
 	}
 }
 
+func TestInsertNamed(t *testing.T) {
+	var names []string
+	handler := func(name string) request.NamedHandler {
+		return request.NamedHandler{Name: name, Fn: func(r *request.Request) {
+			names = append(names, name)
+		}}
+	}
+
+	l := request.HandlerList{}
+	l.PushBackNamed(handler("a"))
+	l.PushBackNamed(handler("b"))
+	l.PushBackNamed(handler("c"))
+
+	if !l.InsertBeforeNamed("b", handler("before-b")) {
+		t.Errorf("expect handler inserted before b")
+	}
+	if !l.InsertAfterNamed("b", handler("after-b")) {
+		t.Errorf("expect handler inserted after b")
+	}
+	if !l.InsertAfterNamed("c", handler("after-c")) {
+		t.Errorf("expect handler inserted after c")
+	}
+	if l.InsertBeforeNamed("missing", handler("missing")) {
+		t.Errorf("expect handler not inserted before missing handler")
+	}
+	l.Run(&request.Request{})
+
+	if e, a := []string{"a", "before-b", "b", "after-b", "c", "after-c"}, names; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestHandlerExtensionPoints(t *testing.T) {
+	var phases []string
+	record := func(phase string) func(*request.Request) {
+		return func(r *request.Request) {
+			phases = append(phases, phase)
+		}
+	}
+
+	svc := s3.New(unit.Session)
+	svc.Handlers.Sign.PushBack(record("Sign"))
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(record("Send"))
+	svc.Handlers.Unmarshal.Clear()
+	svc.Handlers.Unmarshal.PushBack(record("Unmarshal"))
+	svc.Handlers.UnmarshalMeta.Clear()
+	svc.Handlers.ValidateResponse.Clear()
+
+	svc.Handlers.AfterUnmarshal.PushBack(record("AfterUnmarshal"))
+	svc.Handlers.BeforeSend.PushBack(record("BeforeSend"))
+	svc.Handlers.AfterSign.PushBack(record("AfterSign"))
+	svc.Handlers.BeforeSign.PushBack(record("BeforeSign"))
+
+	req, _ := svc.HeadBucketRequest(&s3.HeadBucketInput{Bucket: aws.String("bucket")})
+	if err := req.Send(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := []string{"BeforeSign", "Sign", "AfterSign", "BeforeSend", "Send", "Unmarshal", "AfterUnmarshal"}
+	if e, a := expect, phases; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
 func BenchmarkNewRequest(b *testing.B) {
 	svc := s3.New(unit.Session)
 
@@ -264,3 +329,22 @@ func BenchmarkHandlersClear(b *testing.B) {
 		h.Clear()
 	}
 }
+
+func TestHandlerListValueCopy(t *testing.T) {
+	h := request.Handlers{}
+	h.Send.PushBack(func(*request.Request) {})
+
+	c := h
+	c.Send.PushBack(func(*request.Request) {})
+	if e, a := 1, h.Send.Len(); e != a {
+		t.Errorf("expect %d handlers, got %d", e, a)
+	}
+	if e, a := 2, c.Send.Len(); e != a {
+		t.Errorf("expect %d handlers of copy, got %d", e, a)
+	}
+
+	h.Send.Clear()
+	if e, a := 2, c.Send.Len(); e != a {
+		t.Errorf("expect %d handlers of copy, got %d", e, a)
+	}
+}
@@ -60,7 +60,8 @@ type Request struct {
 	LastSignedAt           time.Time
 	DisableFollowRedirects bool
 
-	context aws.Context
+	context  aws.Context
+	metadata map[string]string
 
 	built bool
 
@@ -69,6 +70,13 @@ type Request struct {
 	// to the HTTP request's body after the client has returned. This value is
 	// safe to use concurrently and wrap the input Body for each HTTP request.
 	safeBody *offsetReader
+
+	operationTimeout *timeoutCanceler
+	attemptTimeout   *timeoutCanceler
+	responseBody     *releaseOnCloseBody
+	gzipBody         *gzipResponseBody
+	sizeLimitedBody  *sizeLimitedBody
+	dialFailures     int
 }
 
 // An Operation is the service API operation to be made.
@@ -103,6 +111,11 @@ func New(cfg aws.Config, clientInfo metadata.ClientInfo, handlers Handlers,
 		httpReq.URL = &url.URL{}
 		err = awserr.New("InvalidEndpointURL", "invalid endpoint uri", err)
 	}
+	httpReq.Host = aws.StringValue(cfg.HostOverride)
+
+	if s, ok := retryer.(OperationRetryer); ok {
+		retryer = s.RetryerForOperation(operation)
+	}
 
 	r := &Request{
 		Config:     cfg,
@@ -205,11 +218,21 @@ func (r *Request) Context() aws.Context {
 // The http.Request.WithContext will be used to set the context on the underlying
 // http.Request. This will create a shallow copy of the http.Request. The SDK
 // may create sub contexts in the future for nested requests such as retries.
+//
+// If the context has credentials set with ContextWithCredentials the Request
+// will be signed with them. Metadata set on the context with
+// ContextWithMetadata is added to the Request's metadata, without replacing
+// the values of keys already set.
 func (r *Request) SetContext(ctx aws.Context) {
 	if ctx == nil {
 		panic("context cannot be nil")
 	}
 	setRequestContext(r, ctx)
+
+	if creds := CredentialsFromContext(ctx); creds != nil {
+		r.Config.Credentials = creds
+	}
+	r.metadata = mergeMetadata(MetadataFromContext(ctx), r.metadata)
 }
 
 // WillRetry returns if the request's can be retried.
@@ -297,8 +320,17 @@ func debugLogReqError(r *Request, stage string, retrying bool, err error) {
 		retryStr = "will retry"
 	}
 
-	r.Config.Logger.Log(fmt.Sprintf("DEBUG: %s %s/%s failed, %s, error %v",
-		stage, r.ClientInfo.ServiceName, r.Operation.Name, retryStr, err))
+	r.Config.Logger.Log(fmt.Sprintf("DEBUG: %s %s/%s failed, %s, error %v%s",
+		stage, r.ClientInfo.ServiceName, r.Operation.Name, retryStr, err, logMetadata(r)))
+}
+
+// logMetadata returns the request's metadata formatted for log messages, or
+// an empty string if the request has no metadata.
+func logMetadata(r *Request) string {
+	if len(r.metadata) == 0 {
+		return ""
+	}
+	return " [" + FormatMetadata(r.metadata) + "]"
 }
 
 // Build will build the request's object so it can be signed and sent
@@ -323,6 +355,7 @@ func (r *Request) Build() error {
 			debugLogReqError(r, "Build Request", false, r.Error)
 			return r.Error
 		}
+		r.addConfigHeaders(r.Config.Headers)
 		r.built = true
 	}
 
@@ -340,7 +373,17 @@ func (r *Request) Sign() error {
 		return r.Error
 	}
 
+	r.Handlers.BeforeSign.Run(r)
+	if r.Error != nil {
+		return r.Error
+	}
+
 	r.Handlers.Sign.Run(r)
+	if r.Error != nil {
+		return r.Error
+	}
+
+	r.Handlers.AfterSign.Run(r)
 	return r.Error
 }
 
@@ -449,13 +492,23 @@ func (r *Request) GetBody() io.ReadSeeker {
 // https://github.com/golang/go/blob/master/src/net/http/transport.go
 //
 // Send will not close the request.Request's body.
-func (r *Request) Send() error {
+func (r *Request) Send() (err error) {
 	defer func() {
 		// Regardless of success or failure of the request trigger the Complete
 		// request handlers.
 		r.Handlers.Complete.Run(r)
 	}()
 
+	if b := r.Config.RetryBudget; b != nil {
+		b.RecordRequest()
+	}
+
+	r.startOperationTimeout()
+	defer func() {
+		r.endTimeouts()
+		err = r.Error
+	}()
+
 	for {
 		if aws.BoolValue(r.Retryable) {
 			if r.Config.LogLevel.Matches(aws.LogDebugWithRequestRetries) {
@@ -481,10 +534,19 @@ func (r *Request) Send() error {
 			return r.Error
 		}
 
+		r.Handlers.BeforeSend.Run(r)
+		if r.Error != nil {
+			return r.Error
+		}
+
 		r.Retryable = nil
 
-		r.Handlers.Send.Run(r)
+		r.startAttemptTimeout()
+		r.addConfigHeaders(r.Config.UnsignedHeaders)
+		r.sendAttempt()
+		r.wrapResponseBody()
 		if r.Error != nil {
+			r.adaptAttemptTimeoutError()
 			if !shouldRetryCancel(r) {
 				return r.Error
 			}
@@ -497,12 +559,15 @@ func (r *Request) Send() error {
 				return r.Error
 			}
 			debugLogReqError(r, "Send Request", true, err)
+			r.reResolveEndpoint(err)
 			continue
 		}
+		r.dialFailures = 0
 		r.Handlers.UnmarshalMeta.Run(r)
 		r.Handlers.ValidateResponse.Run(r)
 		if r.Error != nil {
 			r.Handlers.UnmarshalError.Run(r)
+			r.adaptAttemptTimeoutError()
 			err := r.Error
 
 			r.Handlers.Retry.Run(r)
@@ -516,7 +581,11 @@ func (r *Request) Send() error {
 		}
 
 		r.Handlers.Unmarshal.Run(r)
+		if r.Error == nil {
+			r.Handlers.AfterUnmarshal.Run(r)
+		}
 		if r.Error != nil {
+			r.adaptAttemptTimeoutError()
 			err := r.Error
 			r.Handlers.Retry.Run(r)
 			r.Handlers.AfterRetry.Run(r)
@@ -0,0 +1,182 @@
+package request
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// ErrCodeResponseBodyTooLarge is the error code of the
+// ResponseBodyTooLargeError returned when a response body read into memory
+// exceeds the aws.Config MaxResponseBodySize.
+const ErrCodeResponseBodyTooLarge = "ResponseBodyTooLarge"
+
+// WithMaxResponseBodySize is a request option that will set the maximum size
+// of the request's response body, if read into memory, overriding the
+// aws.Config MaxResponseBodySize value.
+//
+//     svc.ListObjectsV2WithContext(ctx, params, request.WithMaxResponseBodySize(1024*1024))
+func WithMaxResponseBodySize(size int64) Option {
+	return func(r *Request) {
+		r.Config.MaxResponseBodySize = aws.Int64(size)
+	}
+}
+
+// A ResponseBodyTooLargeError is returned by requests whose response body,
+// read into memory to be unmarshaled, exceeds the aws.Config
+// MaxResponseBodySize. Requests failing with the error are not retried.
+//
+//     if berr, ok := err.(*request.ResponseBodyTooLargeError); ok {
+//         fmt.Println("response larger than", berr.MaxSize, "bytes")
+//     }
+type ResponseBodyTooLargeError struct {
+	// MaxSize is the maximum size of the response body, in bytes.
+	MaxSize int64
+
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	origErr error
+}
+
+// Code returns the ErrCodeResponseBodyTooLarge error code.
+func (e *ResponseBodyTooLargeError) Code() string {
+	return ErrCodeResponseBodyTooLarge
+}
+
+// Message returns the error's message.
+func (e *ResponseBodyTooLargeError) Message() string {
+	return fmt.Sprintf("response body of status %d exceeds the maximum size of %d bytes",
+		e.StatusCode, e.MaxSize)
+}
+
+// OrigErr returns the error the request failed with when unmarshaling the
+// response body, if any.
+func (e *ResponseBodyTooLargeError) OrigErr() error {
+	return e.origErr
+}
+
+// Error satisfies the error interface.
+func (e *ResponseBodyTooLargeError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message())
+}
+
+// LimitResponseBodySizeHandler is an UnmarshalMeta handler limiting the size
+// of the response body to the Config's MaxResponseBodySize, if set, and the
+// body is read into memory to be unmarshaled. It is added to the
+// UnmarshalMeta handlers by defaults.Handlers.
+var LimitResponseBodySizeHandler = NamedHandler{
+	Name: "core.LimitResponseBodySizeHandler",
+	Fn: func(r *Request) {
+		r.limitResponseBodySize()
+	},
+}
+
+// ResponseBodySizeErrorHandler is a Retry handler replacing the error of
+// requests whose response body exceeded the Config's MaxResponseBodySize
+// with a ResponseBodyTooLargeError, which is not retried. It is added to the
+// Retry handlers by defaults.Handlers.
+var ResponseBodySizeErrorHandler = NamedHandler{
+	Name: "core.ResponseBodySizeErrorHandler",
+	Fn: func(r *Request) {
+		r.adaptResponseBodySizeError()
+	},
+}
+
+// sizeLimitedBody is a response body which fails to be read once more than
+// its maximum size is read.
+type sizeLimitedBody struct {
+	io.ReadCloser
+	remaining int64
+	exceeded  *ResponseBodyTooLargeError
+}
+
+func (b *sizeLimitedBody) Read(p []byte) (int, error) {
+	if b.exceeded != nil {
+		return 0, b.exceeded
+	}
+
+	// Read one byte more than remains, so that bodies of exactly the maximum
+	// size are read to EOF.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) > b.remaining {
+		n = int(b.remaining)
+		b.remaining = 0
+		b.exceeded = &ResponseBodyTooLargeError{}
+		return n, b.exceeded
+	}
+	b.remaining -= int64(n)
+	return n, err
+}
+
+// limitResponseBodySize limits the size of the response body to the
+// Config's MaxResponseBodySize, if set, and the body is read into memory to
+// be unmarshaled. The bodies of error responses are always read into memory.
+func (r *Request) limitResponseBodySize() {
+	r.sizeLimitedBody = nil
+	max := aws.Int64Value(r.Config.MaxResponseBodySize)
+	if max <= 0 || r.HTTPResponse == nil || r.HTTPResponse.Body == nil {
+		return
+	}
+	if r.HTTPResponse.StatusCode < 300 && hasStreamingPayload(r.Data) {
+		return
+	}
+
+	r.sizeLimitedBody = &sizeLimitedBody{
+		ReadCloser: r.HTTPResponse.Body,
+		remaining:  max,
+	}
+	r.HTTPResponse.Body = r.sizeLimitedBody
+}
+
+// adaptResponseBodySizeError replaces the request's error with a
+// ResponseBodyTooLargeError if its response body exceeded the maximum size.
+// The error the request failed with, such as the error of unmarshaling the
+// truncated body, is the ResponseBodyTooLargeError's original error.
+func (r *Request) adaptResponseBodySizeError() {
+	if r.Error == nil || r.sizeLimitedBody == nil || r.sizeLimitedBody.exceeded == nil {
+		return
+	}
+	if _, ok := r.Error.(*ResponseBodyTooLargeError); ok {
+		return
+	}
+
+	r.Error = &ResponseBodyTooLargeError{
+		MaxSize:    aws.Int64Value(r.Config.MaxResponseBodySize),
+		StatusCode: r.HTTPResponse.StatusCode,
+		origErr:    r.Error,
+	}
+	r.Retryable = aws.Bool(false)
+}
+
+var readCloserType = reflect.TypeOf((*io.ReadCloser)(nil)).Elem()
+
+// hasStreamingPayload returns whether the output's payload is streamed to
+// the caller, instead of being read into memory, such as the io.ReadCloser
+// Body of S3 GetObject outputs, or the event streams of outputs with one.
+func hasStreamingPayload(data interface{}) bool {
+	v := reflect.Indirect(reflect.ValueOf(data))
+	if v.Kind() != reflect.Struct {
+		return false
+	}
+	field, ok := v.Type().FieldByName("_")
+	if !ok {
+		return false
+	}
+	name := field.Tag.Get("payload")
+	if len(name) == 0 {
+		return false
+	}
+
+	payload, ok := v.Type().FieldByName(name)
+	if !ok {
+		// Outputs with event streams name a payload they have no field for.
+		return true
+	}
+	return payload.Type == readCloserType
+}
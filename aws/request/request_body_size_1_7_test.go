@@ -0,0 +1,57 @@
+// +build go1.7
+
+package request_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestMaxResponseBodySize(t *testing.T) {
+	listBody := func(keys int) string {
+		return `<ListBucketResult>` + strings.Repeat(`<Contents><Key>k</Key></Contents>`, keys) + `</ListBucketResult>`
+	}
+
+	cases := map[string]struct {
+		Status int
+		Body   string
+		Expect int
+	}{
+		"within size":   {Status: 200, Body: listBody(1)},
+		"exceeds size":  {Status: 200, Body: listBody(10), Expect: 200},
+		"error exceeds": {Status: 500, Body: `<Error><Code>InternalError</Code><Message>` + strings.Repeat("m", 100) + `</Message></Error>`, Expect: 500},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var sends int
+			svc := newResponseBodySizeClient(c.Status, c.Body, &sends)
+
+			_, err := svc.ListObjectsV2(&s3.ListObjectsV2Input{Bucket: aws.String("bucket")})
+			if c.Expect == 0 {
+				if err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+				return
+			}
+
+			berr, ok := err.(*request.ResponseBodyTooLargeError)
+			if !ok {
+				t.Fatalf("expect ResponseBodyTooLargeError, got %T, %v", err, err)
+			}
+			if e, a := int64(128), berr.MaxSize; e != a {
+				t.Errorf("expect %v max size, got %v", e, a)
+			}
+			if e, a := c.Expect, berr.StatusCode; e != a {
+				t.Errorf("expect %v status code, got %v", e, a)
+			}
+			if e, a := 1, sends; e != a {
+				t.Errorf("expect %v sends, got %v", e, a)
+			}
+		})
+	}
+}
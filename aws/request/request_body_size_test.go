@@ -0,0 +1,66 @@
+package request_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func newResponseBodySizeClient(status int, body string, sends *int) *s3.S3 {
+	svc := s3.New(unit.Session, &aws.Config{
+		MaxRetries:          aws.Int(2),
+		MaxResponseBodySize: aws.Int64(128),
+		SleepDelay:          func(time.Duration) {},
+	})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		*sends++
+		r.HTTPResponse = &http.Response{
+			StatusCode: status,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		}
+	})
+	return svc
+}
+
+func TestMaxResponseBodySize_Streaming(t *testing.T) {
+	body := strings.Repeat("b", 1000)
+	var sends int
+	svc := newResponseBodySizeClient(200, body, &sends)
+
+	resp, err := svc.GetObject(&s3.GetObjectInput{Bucket: aws.String("bucket"), Key: aws.String("key")})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := body, string(b); e != a {
+		t.Errorf("expect body not limited, got %v bytes", len(a))
+	}
+}
+
+func TestMaxResponseBodySize_HandlerRemoved(t *testing.T) {
+	body := `<ListBucketResult>` + strings.Repeat(`<Contents><Key>k</Key></Contents>`, 10) + `</ListBucketResult>`
+	var sends int
+	svc := newResponseBodySizeClient(200, body, &sends)
+	svc.Handlers.UnmarshalMeta.Remove(request.LimitResponseBodySizeHandler)
+
+	out, err := svc.ListObjectsV2(&s3.ListObjectsV2Input{Bucket: aws.String("bucket")})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 10, len(out.Contents); e != a {
+		t.Errorf("expect %v keys, got %v", e, a)
+	}
+}
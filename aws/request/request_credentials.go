@@ -0,0 +1,32 @@
+package request
+
+import "github.com/aws/aws-sdk-go/aws/credentials"
+
+// credentialsContextKey is the context key of the credentials set with
+// ContextWithCredentials.
+type credentialsContextKey struct{}
+
+// WithCredentials is a request option that will set the request to be signed
+// with the credentials provided, instead of the client's credentials. Use
+// this to make calls on behalf of a request scoped identity, such as a
+// tenant's IAM token, with a shared client and its connection pool.
+//
+// The client's signer is used to sign the request, so the credentials should
+// be of the same type as the client's. IBM IAM credentials can only be used
+// with clients created with IBM IAM credentials.
+//
+//     svc.GetObjectWithContext(ctx, params, request.WithCredentials(tenantCreds))
+func WithCredentials(creds *credentials.Credentials) Option {
+	return func(r *Request) {
+		r.Config.Credentials = creds
+	}
+}
+
+// CredentialsFromContext returns the credentials set on the context with
+// ContextWithCredentials, or nil if none were set.
+func CredentialsFromContext(ctx interface {
+	Value(key interface{}) interface{}
+}) *credentials.Credentials {
+	creds, _ := ctx.Value(credentialsContextKey{}).(*credentials.Credentials)
+	return creds
+}
@@ -0,0 +1,21 @@
+// +build go1.7
+
+package request
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// ContextWithCredentials returns a copy of the context with the credentials
+// provided. Requests made with the context are signed with the credentials,
+// instead of the client's credentials, as with the WithCredentials request
+// option. Credentials set with the WithCredentials option take precedence.
+//
+//     ctx := request.ContextWithCredentials(r.Context(), tenantCreds)
+//     svc.GetObjectWithContext(ctx, params)
+func ContextWithCredentials(ctx aws.Context, creds *credentials.Credentials) aws.Context {
+	return context.WithValue(ctx, credentialsContextKey{}, creds)
+}
@@ -0,0 +1,65 @@
+// +build go1.7
+
+package request_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+)
+
+func TestRequestCredentialsOverride(t *testing.T) {
+	clientCreds := credentials.NewStaticCredentials("CLIENT", "SECRET", "")
+	optionCreds := credentials.NewStaticCredentials("OPTION", "SECRET", "")
+	ctxCreds := credentials.NewStaticCredentials("CONTEXT", "SECRET", "")
+
+	cfg := unit.Session.Config.Copy(&aws.Config{Credentials: clientCreds})
+	c := client.New(*cfg, metadata.ClientInfo{Endpoint: "https://example.com"}, unit.Session.Handlers)
+
+	cases := map[string]struct {
+		Context aws.Context
+		Options []request.Option
+		Expect  *credentials.Credentials
+	}{
+		"client": {
+			Expect: clientCreds,
+		},
+		"option": {
+			Options: []request.Option{request.WithCredentials(optionCreds)},
+			Expect:  optionCreds,
+		},
+		"context": {
+			Context: request.ContextWithCredentials(aws.BackgroundContext(), ctxCreds),
+			Expect:  ctxCreds,
+		},
+		"option precedence": {
+			Context: request.ContextWithCredentials(aws.BackgroundContext(), ctxCreds),
+			Options: []request.Option{request.WithCredentials(optionCreds)},
+			Expect:  optionCreds,
+		},
+	}
+
+	for name, tc := range cases {
+		r := c.NewRequest(&request.Operation{Name: "Operation"}, nil, nil)
+		if tc.Context != nil {
+			r.SetContext(tc.Context)
+		}
+		r.ApplyOptions(tc.Options...)
+
+		if e, a := tc.Expect, r.Config.Credentials; e != a {
+			t.Errorf("%s, expect %v credentials, got %v", name, e, a)
+		}
+	}
+
+	if e, a := clientCreds, c.Config.Credentials; e != a {
+		t.Errorf("expect client credentials to be unchanged")
+	}
+	if creds := request.CredentialsFromContext(aws.BackgroundContext()); creds != nil {
+		t.Errorf("expect no credentials, got %v", creds)
+	}
+}
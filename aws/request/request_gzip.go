@@ -0,0 +1,168 @@
+package request
+
+import (
+	"compress/gzip"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+// ErrCodeResponseDecompression is the error code returned by the Read method
+// of response bodies which fail to be decompressed when the Config's
+// AcceptGzipEncoding is set.
+const ErrCodeResponseDecompression = "ResponseDecompressionError"
+
+// AcceptGzipEncodingHandler is a Send handler setting the Accept-Encoding
+// header of GET requests to gzip, if the Config's AcceptGzipEncoding is set.
+// It is added to the Send handlers by defaults.Handlers, before the
+// corehandlers.SendHandler.
+var AcceptGzipEncodingHandler = NamedHandler{
+	Name: "core.AcceptGzipEncodingHandler",
+	Fn: func(r *Request) {
+		r.acceptGzipEncoding()
+	},
+}
+
+// DecompressGzipResponseHandler is an UnmarshalMeta handler decompressing
+// the bodies of responses compressed with gzip, if the Config's
+// AcceptGzipEncoding is set. It is added to the front of the UnmarshalMeta
+// handlers by defaults.Handlers, so the response's metadata is unmarshaled
+// from the headers of the decompressed body.
+var DecompressGzipResponseHandler = NamedHandler{
+	Name: "core.DecompressGzipResponseHandler",
+	Fn: func(r *Request) {
+		r.decompressGzipResponse()
+	},
+}
+
+// acceptGzipEncoding sets the Accept-Encoding header of GET requests to gzip
+// if the Config's AcceptGzipEncoding is set. Range requests, and requests
+// with an Accept-Encoding already set, are not modified. The header is set
+// when the request is sent, so it is not signed, nor required by presigned
+// URLs.
+func (r *Request) acceptGzipEncoding() {
+	if !aws.BoolValue(r.Config.AcceptGzipEncoding) || r.HTTPRequest.Method != "GET" {
+		return
+	}
+
+	h := r.HTTPRequest.Header
+	if h.Get("Accept-Encoding") != "" || h.Get("Range") != "" {
+		return
+	}
+	h.Set("Accept-Encoding", "gzip")
+}
+
+// decompressGzipResponse replaces the body of responses compressed with gzip
+// with their decompression, if the Config's AcceptGzipEncoding is set. The
+// response's Content-Encoding and Content-Length are removed, as they
+// describe the compressed body.
+func (r *Request) decompressGzipResponse() {
+	r.gzipBody = nil
+	if !aws.BoolValue(r.Config.AcceptGzipEncoding) || r.Error != nil {
+		return
+	}
+	resp := r.HTTPResponse
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	if !strings.EqualFold(strings.TrimSpace(resp.Header.Get("Content-Encoding")), "gzip") {
+		return
+	}
+
+	body := &gzipResponseBody{compressed: &countingReader{r: resp.Body}, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	setUncompressed(resp)
+	resp.Body = body
+	r.gzipBody = body
+}
+
+// GzipResponseBytes returns the number of compressed bytes read from the
+// response, and the number of decompressed bytes read from its body, if the
+// response was decompressed because the Config's AcceptGzipEncoding is set.
+// The counts include the bytes read so far, and are final once the body has
+// been read to the end, such as after the request completes, or after the
+// caller reads a streaming body such as GetObject's Body.
+//
+//     req, out := svc.GetObjectRequest(input)
+//     err := req.Send()
+//     ...
+//     io.Copy(w, out.Body)
+//     if compressed, decompressed, ok := req.GzipResponseBytes(); ok {
+//         fmt.Println(compressed, decompressed)
+//     }
+func (r *Request) GzipResponseBytes() (compressed, decompressed int64, ok bool) {
+	b := r.gzipBody
+	if b == nil {
+		return 0, 0, false
+	}
+
+	return atomic.LoadInt64(&b.compressed.n), atomic.LoadInt64(&b.decompressed), true
+}
+
+// gzipResponseBody is the decompressed body of a response compressed with
+// gzip. The gzip reader is created on the first Read, as it reads the gzip
+// header.
+type gzipResponseBody struct {
+	body       io.Closer
+	compressed *countingReader
+	gz         *gzip.Reader
+	err        error
+
+	decompressed int64
+}
+
+func (b *gzipResponseBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if b.gz == nil {
+		gz, err := gzip.NewReader(b.compressed)
+		if err == io.EOF { // empty body
+			return 0, io.EOF
+		} else if err != nil {
+			b.err = newDecompressionError(err)
+			return 0, b.err
+		}
+		b.gz = gz
+	}
+
+	n, err := b.gz.Read(p)
+	atomic.AddInt64(&b.decompressed, int64(n))
+	if err != nil && err != io.EOF {
+		b.err = newDecompressionError(err)
+		return n, b.err
+	}
+
+	return n, err
+}
+
+func (b *gzipResponseBody) Close() error {
+	return b.body.Close()
+}
+
+// newDecompressionError returns the error of a response body failing to be
+// decompressed. SDK errors reading the compressed body, such as response
+// timeouts, are returned as is.
+func newDecompressionError(err error) error {
+	if _, ok := err.(awserr.Error); ok {
+		return err
+	}
+	return awserr.New(ErrCodeResponseDecompression, "failed to decompress response body", err)
+}
+
+// countingReader counts the bytes read from a reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	atomic.AddInt64(&c.n, int64(n))
+	return n, err
+}
@@ -0,0 +1,9 @@
+// +build !go1.7
+
+package request
+
+import "net/http"
+
+// setUncompressed does nothing, as responses cannot be marked as
+// decompressed before Go 1.7.
+func setUncompressed(resp *http.Response) {}
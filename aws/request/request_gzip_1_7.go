@@ -0,0 +1,11 @@
+// +build go1.7
+
+package request
+
+import "net/http"
+
+// setUncompressed marks the response as decompressed by the SDK, as
+// responses decompressed by the HTTP transport are.
+func setUncompressed(resp *http.Response) {
+	resp.Uncompressed = true
+}
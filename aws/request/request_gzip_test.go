@@ -0,0 +1,136 @@
+// +build go1.7
+
+package request_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+func newGzipTestServer(t *testing.T, body []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" || r.Header.Get("Range") != "" {
+			w.Write(body)
+			return
+		}
+
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write(body)
+		gz.Close()
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(buf.Bytes())
+	}))
+}
+
+func sendGzipTestRequest(url string, opts ...request.Option) (*request.Request, []byte, error) {
+	var body []byte
+	req := newTimeoutTestRequest(&aws.Config{AcceptGzipEncoding: aws.Bool(true)}, url, opts...)
+	req.Handlers.Unmarshal.Clear()
+	req.Handlers.Unmarshal.PushBack(func(r *request.Request) {
+		if r.HTTPResponse.Header.Get("Content-Encoding") != "" {
+			r.Error = awserr.New("UnexpectedEncoding", "expect no content encoding", nil)
+			return
+		}
+		b, err := ioutil.ReadAll(r.HTTPResponse.Body)
+		if err != nil {
+			r.Error = err
+		}
+		body = b
+	})
+
+	err := req.Send()
+	return req, body, err
+}
+
+func TestRequestAcceptGzipEncoding(t *testing.T) {
+	expect := []byte(strings.Repeat("0123456789", 1000))
+	server := newGzipTestServer(t, expect)
+	defer server.Close()
+
+	req, body, err := sendGzipTestRequest(server.URL)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !bytes.Equal(expect, body) {
+		t.Errorf("expect decompressed body, got %d bytes", len(body))
+	}
+
+	compressed, decompressed, ok := req.GzipResponseBytes()
+	if !ok {
+		t.Fatalf("expect response to be decompressed")
+	}
+	if e, a := int64(len(expect)), decompressed; e != a {
+		t.Errorf("expect %v decompressed bytes, got %v", e, a)
+	}
+	if compressed <= 0 || compressed >= decompressed {
+		t.Errorf("expect compressed bytes to be less than %v, got %v", decompressed, compressed)
+	}
+}
+
+func TestRequestAcceptGzipEncoding_Range(t *testing.T) {
+	expect := []byte("0123456789")
+	server := newGzipTestServer(t, expect)
+	defer server.Close()
+
+	req, body, err := sendGzipTestRequest(server.URL, func(r *request.Request) {
+		r.HTTPRequest.Header.Set("Range", "bytes=0-9")
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !bytes.Equal(expect, body) {
+		t.Errorf("expect %q body, got %q", expect, body)
+	}
+	if _, _, ok := req.GzipResponseBytes(); ok {
+		t.Errorf("expect response not to be decompressed")
+	}
+}
+
+func TestRequestAcceptGzipEncoding_InvalidBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write([]byte("not gzip"))
+	}))
+	defer server.Close()
+
+	_, _, err := sendGzipTestRequest(server.URL, func(r *request.Request) {
+		r.Config.MaxRetries = aws.Int(0)
+	})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := request.ErrCodeResponseDecompression, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
+
+func TestRequestAcceptGzipEncoding_HandlersRemoved(t *testing.T) {
+	expect := []byte(strings.Repeat("0123456789", 1000))
+	server := newGzipTestServer(t, expect)
+	defer server.Close()
+
+	req, body, err := sendGzipTestRequest(server.URL, func(r *request.Request) {
+		r.Handlers.Send.Remove(request.AcceptGzipEncodingHandler)
+		r.Handlers.UnmarshalMeta.Remove(request.DecompressGzipResponseHandler)
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !bytes.Equal(expect, body) {
+		t.Errorf("expect uncompressed body, got %d bytes", len(body))
+	}
+	if _, _, ok := req.GzipResponseBytes(); ok {
+		t.Errorf("expect response not to be decompressed")
+	}
+}
@@ -0,0 +1,20 @@
+package request
+
+import "net/http"
+
+// addConfigHeaders adds the headers to the request's HTTP request, such as
+// the Config's Headers, skipping the headers the HTTP request already has.
+func (r *Request) addConfigHeaders(headers http.Header) {
+	if len(headers) == 0 {
+		return
+	}
+
+	h := r.HTTPRequest.Header
+	for k, v := range headers {
+		k = http.CanonicalHeaderKey(k)
+		if _, ok := h[k]; ok {
+			continue
+		}
+		h[k] = append([]string(nil), v...)
+	}
+}
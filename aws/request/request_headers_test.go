@@ -0,0 +1,56 @@
+package request_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestConfigHeaders(t *testing.T) {
+	cfg := (&aws.Config{}).
+		WithHeader("X-Gateway-Route", "cos").
+		WithHeader("Content-Type", "text/plain").
+		WithUnsignedHeader("X-Proxy-Trace", "trace")
+	svc := s3.New(unit.Session, cfg)
+
+	var sent http.Header
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		sent = r.HTTPRequest.Header
+		r.HTTPResponse = &http.Response{StatusCode: 200, Header: http.Header{}, Body: ioutil.NopCloser(bytes.NewReader(nil))}
+	})
+
+	_, err := svc.PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String("bucket"),
+		Key:         aws.String("key"),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := "cos", sent.Get("X-Gateway-Route"); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := "trace", sent.Get("X-Proxy-Trace"); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := "application/json", sent.Get("Content-Type"); e != a {
+		t.Errorf("expect parameter header not replaced, %v, got %v", e, a)
+	}
+
+	auth := sent.Get("Authorization")
+	if e, a := "x-gateway-route", auth; !strings.Contains(a, e) {
+		t.Errorf("expect %v signed, got %v", e, a)
+	}
+	if e, a := "x-proxy-trace", auth; strings.Contains(a, e) {
+		t.Errorf("expect %v not signed, got %v", e, a)
+	}
+}
@@ -0,0 +1,9 @@
+// +build !go1.7
+
+package request
+
+// sendAttempt sends an attempt of the request with the Send handlers. Hedging
+// requires Go 1.7 or later, and the Config's HedgePolicy is ignored.
+func (r *Request) sendAttempt() {
+	r.Handlers.Send.Run(r)
+}
@@ -0,0 +1,150 @@
+// +build go1.7
+
+package request
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// sendAttempt sends an attempt of the request with the Send handlers. If the
+// Config's HedgePolicy is set, attempts of idempotent requests are hedged.
+func (r *Request) sendAttempt() {
+	p := r.Config.HedgePolicy
+	if p == nil || !r.isHedgeable() {
+		r.Handlers.Send.Run(r)
+		return
+	}
+
+	delay, ok := p.Delay()
+	if !ok {
+		start := time.Now()
+		r.Handlers.Send.Run(r)
+		if r.Error == nil {
+			p.Observe(time.Since(start))
+		}
+		return
+	}
+
+	r.sendHedged(delay)
+}
+
+// isHedgeable returns whether attempts of the request may be hedged. Only
+// GET and HEAD requests, which are idempotent and have no body, are hedged.
+func (r *Request) isHedgeable() bool {
+	switch r.HTTPRequest.Method {
+	case "GET", "HEAD":
+		return true
+	default:
+		return false
+	}
+}
+
+// hedgeAttempt is a concurrent attempt of a hedged request.
+type hedgeAttempt struct {
+	r       *Request
+	cancel  context.CancelFunc
+	latency time.Duration
+}
+
+// sendHedged sends an attempt of the request, and a second, hedged, attempt
+// if the first has not responded once the delay elapses. The first attempt
+// to respond without error is used, and the other attempt is canceled. The
+// hedged attempt is taken from the Config's RetryBudget, if set, and is not
+// made if the budget is exhausted.
+func (r *Request) sendHedged(delay time.Duration) {
+	results := make(chan *hedgeAttempt, 2)
+	var attempts []*hedgeAttempt
+
+	send := func() {
+		ctx, cancel := context.WithCancel(r.HTTPRequest.Context())
+		a := &hedgeAttempt{r: new(Request), cancel: cancel}
+		*a.r = *r
+		a.r.HTTPRequest = copyHTTPRequest(r.HTTPRequest, r.HTTPRequest.Body).WithContext(ctx)
+		attempts = append(attempts, a)
+
+		go func() {
+			start := time.Now()
+			a.r.Handlers.Send.Run(a.r)
+			a.latency = time.Since(start)
+			results <- a
+		}()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	send()
+	pending := 1
+
+	var failed *hedgeAttempt
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			if b := r.Config.RetryBudget; b != nil && !b.AllowRetry() {
+				continue
+			}
+			if r.Config.LogLevel.Matches(aws.LogDebugWithRequestRetries) {
+				r.Config.Logger.Log(fmt.Sprintf("DEBUG: Hedging Request %s/%s, after %v",
+					r.ClientInfo.ServiceName, r.Operation.Name, delay))
+			}
+			send()
+			pending++
+
+		case a := <-results:
+			pending--
+			if a.r.Error != nil {
+				a.cancel()
+				if failed == nil {
+					failed = a
+				}
+				continue
+			}
+
+			r.Config.HedgePolicy.Observe(a.latency)
+			// The winning attempt's context is released once its
+			// response body is closed.
+			if resp := a.r.HTTPResponse; resp != nil && resp.Body != nil {
+				resp.Body = &releaseOnCloseBody{
+					ReadCloser: resp.Body,
+					fns:        []func(){a.cancel},
+				}
+			} else {
+				a.cancel()
+			}
+			r.useHedgeAttempt(a)
+
+			for _, other := range attempts {
+				if other != a {
+					other.cancel()
+				}
+			}
+			go discardHedgeAttempts(results, pending)
+			return
+		}
+	}
+
+	// All attempts failed, the first failure is used.
+	r.useHedgeAttempt(failed)
+}
+
+// useHedgeAttempt sets the request's response and error to the attempt's.
+func (r *Request) useHedgeAttempt(a *hedgeAttempt) {
+	r.HTTPResponse = a.r.HTTPResponse
+	r.Error = a.r.Error
+	r.Retryable = a.r.Retryable
+}
+
+// discardHedgeAttempts waits for the n canceled attempts remaining, closing
+// their response bodies.
+func discardHedgeAttempts(results <-chan *hedgeAttempt, n int) {
+	for i := 0; i < n; i++ {
+		a := <-results
+		if a.r.Error == nil && a.r.HTTPResponse != nil && a.r.HTTPResponse.Body != nil {
+			a.r.HTTPResponse.Body.Close()
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// +build go1.7
+
+package request_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/retry"
+)
+
+func newHedgeTestPolicy(latency time.Duration) *retry.HedgePolicy {
+	p := retry.NewHedgePolicy(0.5, func(p *retry.HedgePolicy) {
+		p.MinSamples = 1
+	})
+	p.Observe(latency)
+	return p
+}
+
+func TestRequestHedgePolicy_Hedged(t *testing.T) {
+	var reqNum int32
+	done := make(chan struct{})
+	defer close(done)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&reqNum, 1) == 1 {
+			// The first attempt stalls until canceled.
+			select {
+			case <-done:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &aws.Config{HedgePolicy: newHedgeTestPolicy(10 * time.Millisecond)}
+	req := newTimeoutTestRequest(cfg, server.URL)
+
+	start := time.Now()
+	if err := req.Send(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expect hedged attempt to respond, took %v", elapsed)
+	}
+	if e, a := int32(2), atomic.LoadInt32(&reqNum); e != a {
+		t.Errorf("expect %v requests, got %v", e, a)
+	}
+	if e, a := 0, req.RetryCount; e != a {
+		t.Errorf("expect %v retries, got %v", e, a)
+	}
+}
+
+func TestRequestHedgePolicy_BudgetExhausted(t *testing.T) {
+	var reqNum int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&reqNum, 1)
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	budget := retry.NewBudget(0, func(b *retry.Budget) {
+		b.Burst = 1
+	})
+	budget.AllowRetry()
+
+	cfg := &aws.Config{
+		HedgePolicy: newHedgeTestPolicy(time.Millisecond),
+		RetryBudget: budget,
+	}
+	if err := newTimeoutTestRequest(cfg, server.URL).Send(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := int32(1), atomic.LoadInt32(&reqNum); e != a {
+		t.Errorf("expect %v requests, got %v", e, a)
+	}
+}
+
+func TestRequestRetryBudget(t *testing.T) {
+	var reqNum int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&reqNum, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	budget := retry.NewBudget(0, func(b *retry.Budget) {
+		b.Burst = 2
+	})
+	cfg := &aws.Config{MaxRetries: aws.Int(5), RetryBudget: budget}
+
+	req := newTimeoutTestRequest(cfg, server.URL)
+	if err := req.Send(); err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := int32(3), atomic.LoadInt32(&reqNum); e != a {
+		t.Errorf("expect %v requests, got %v", e, a)
+	}
+	if e, a := 2, req.RetryCount; e != a {
+		t.Errorf("expect %v retries, got %v", e, a)
+	}
+
+	// The exhausted budget allows no retries for later requests.
+	atomic.StoreInt32(&reqNum, 0)
+	if err := newTimeoutTestRequest(cfg, server.URL).Send(); err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := int32(1), atomic.LoadInt32(&reqNum); e != a {
+		t.Errorf("expect %v requests, got %v", e, a)
+	}
+}
@@ -0,0 +1,52 @@
+package request
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// WithIdempotencyToken returns a request option sending a generated
+// idempotency token in the header provided, such as "X-Idempotency-Key",
+// with requests of mutating operations, those whose HTTP method is not GET,
+// HEAD, or OPTIONS. The token is not replaced if the header is already set.
+//
+// The token is generated once, when the request is built, and is sent with
+// every retry of the request, so services and gateways deduplicating requests
+// by the token do not apply the operation twice when an attempt fails
+// ambiguously, such as when the connection is reset after the request was
+// sent.
+//
+//     out, err := svc.CreateResourceKeyWithContext(ctx, input,
+//         request.WithIdempotencyToken("X-Idempotency-Key"))
+func WithIdempotencyToken(header string) Option {
+	return func(r *Request) {
+		r.Handlers.Build.PushBack(func(r *Request) {
+			switch r.HTTPRequest.Method {
+			case "GET", "HEAD", "OPTIONS":
+				return
+			}
+			if r.HTTPRequest.Header.Get(header) != "" {
+				return
+			}
+
+			token, err := newIdempotencyToken()
+			if err != nil {
+				r.Error = err
+				return
+			}
+			r.HTTPRequest.Header.Set(header, token)
+		})
+	}
+}
+
+// newIdempotencyToken returns a random version 4 UUID.
+func newIdempotencyToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate idempotency token, %v", err)
+	}
+	b[6] = (b[6] | 0x40) & 0x4F
+	b[8] = (b[8] | 0x80) & 0xBF
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -0,0 +1,78 @@
+package request_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting"
+)
+
+func TestWithIdempotencyToken_Retried(t *testing.T) {
+	var m sync.Mutex
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		tokens = append(tokens, r.Header.Get("X-Idempotency-Key"))
+		attempt := len(tokens)
+		m.Unlock()
+
+		if attempt < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	newRequest := func(method string) *request.Request {
+		svc := awstesting.NewClient(&aws.Config{
+			Region:     aws.String("mock-region"),
+			Endpoint:   aws.String(server.URL),
+			DisableSSL: aws.Bool(true),
+			MaxRetries: aws.Int(3),
+			SleepDelay: func(time.Duration) {},
+		})
+		req := svc.NewRequest(&request.Operation{
+			Name: "name", HTTPMethod: method, HTTPPath: "/path",
+		}, &struct{}{}, &struct{}{})
+		req.ApplyOptions(request.WithIdempotencyToken("X-Idempotency-Key"))
+		return req
+	}
+
+	if err := newRequest("POST").Send(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 3, len(tokens); e != a {
+		t.Fatalf("expect %v attempts, got %v", e, a)
+	}
+	if len(tokens[0]) != 36 {
+		t.Errorf("expect UUID token, got %q", tokens[0])
+	}
+	for i, token := range tokens {
+		if e, a := tokens[0], token; e != a {
+			t.Errorf("%d, expect %v token, got %v", i, e, a)
+		}
+	}
+
+	first := tokens[0]
+	tokens = nil
+	if err := newRequest("POST").Send(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if a := tokens[0]; a == "" || a == first {
+		t.Errorf("expect new token for each request, got %q", a)
+	}
+
+	tokens = nil
+	if err := newRequest("GET").Send(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "", tokens[0]; e != a {
+		t.Errorf("expect no token for GET requests, got %v", a)
+	}
+}
@@ -0,0 +1,75 @@
+package request
+
+import (
+	"sort"
+	"strings"
+)
+
+// metadataContextKey is the context key of the metadata set with
+// ContextWithMetadata.
+type metadataContextKey struct{}
+
+// WithMetadata is a request option that will add the key/value pairs
+// provided to the request's metadata, replacing the values of keys already
+// set. Use this to correlate calls with their business context, such as the
+// tenant or job they are made for.
+//
+// The metadata is available to the request's handlers, such as handlers
+// recording metrics or traces, with the request's Metadata method, and is
+// included in the request's debug logs.
+//
+//     svc.GetObjectWithContext(ctx, params, request.WithMetadata(map[string]string{
+//         "tenant": tenantID,
+//         "job":    jobID,
+//     }))
+func WithMetadata(kv map[string]string) Option {
+	return func(r *Request) {
+		r.metadata = mergeMetadata(r.metadata, kv)
+	}
+}
+
+// Metadata returns the request's metadata, set with the WithMetadata
+// request option, or on the request's context with ContextWithMetadata. Nil
+// is returned if the request has no metadata. The map returned must not be
+// modified.
+func (r *Request) Metadata() map[string]string {
+	return r.metadata
+}
+
+// MetadataFromContext returns the metadata set on the context with
+// ContextWithMetadata, or nil if none was set. The map returned must not be
+// modified.
+func MetadataFromContext(ctx interface {
+	Value(key interface{}) interface{}
+}) map[string]string {
+	kv, _ := ctx.Value(metadataContextKey{}).(map[string]string)
+	return kv
+}
+
+// mergeMetadata returns a copy of the metadata with the key/value pairs
+// provided.
+func mergeMetadata(metadata, kv map[string]string) map[string]string {
+	if len(kv) == 0 {
+		return metadata
+	}
+
+	merged := make(map[string]string, len(metadata)+len(kv))
+	for k, v := range metadata {
+		merged[k] = v
+	}
+	for k, v := range kv {
+		merged[k] = v
+	}
+	return merged
+}
+
+// FormatMetadata returns the metadata formatted as space separated key=value
+// pairs sorted by key, such as "job=42 tenant=acme", for log messages.
+func FormatMetadata(metadata map[string]string) string {
+	pairs := make([]string, 0, len(metadata))
+	for k, v := range metadata {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,21 @@
+// +build go1.7
+
+package request
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// ContextWithMetadata returns a copy of the context with the key/value pairs
+// provided added to the context's metadata. Requests made with the context,
+// including the requests made on their behalf, such as the requests of IAM
+// tokens, have the metadata, as with the WithMetadata request option.
+// Metadata set with the WithMetadata option takes precedence.
+//
+//     ctx := request.ContextWithMetadata(r.Context(), map[string]string{"tenant": tenantID})
+//     svc.GetObjectWithContext(ctx, params)
+func ContextWithMetadata(ctx aws.Context, kv map[string]string) aws.Context {
+	return context.WithValue(ctx, metadataContextKey{}, mergeMetadata(MetadataFromContext(ctx), kv))
+}
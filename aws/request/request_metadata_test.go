@@ -0,0 +1,63 @@
+// +build go1.7
+
+package request_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+)
+
+func TestRequestMetadata(t *testing.T) {
+	c := client.New(*unit.Session.Config, metadata.ClientInfo{Endpoint: "https://example.com"}, unit.Session.Handlers)
+
+	ctx := request.ContextWithMetadata(aws.BackgroundContext(), map[string]string{"tenant": "acme", "job": "1"})
+	ctx = request.ContextWithMetadata(ctx, map[string]string{"job": "2"})
+
+	cases := map[string]struct {
+		Context aws.Context
+		Options []request.Option
+		Expect  string
+	}{
+		"none": {},
+		"option": {
+			Options: []request.Option{
+				request.WithMetadata(map[string]string{"tenant": "acme"}),
+				request.WithMetadata(map[string]string{"job": "3"}),
+			},
+			Expect: "job=3 tenant=acme",
+		},
+		"context": {
+			Context: ctx,
+			Expect:  "job=2 tenant=acme",
+		},
+		"option precedence": {
+			Context: ctx,
+			Options: []request.Option{request.WithMetadata(map[string]string{"job": "3"})},
+			Expect:  "job=3 tenant=acme",
+		},
+	}
+
+	for name, tc := range cases {
+		r := c.NewRequest(&request.Operation{Name: "Operation"}, nil, nil)
+		r.ApplyOptions(tc.Options...)
+		if tc.Context != nil {
+			r.SetContext(tc.Context)
+		}
+
+		if e, a := tc.Expect, request.FormatMetadata(r.Metadata()); e != a {
+			t.Errorf("%s, expect %q metadata, got %q", name, e, a)
+		}
+	}
+
+	if e, a := "job=2 tenant=acme", request.FormatMetadata(request.MetadataFromContext(ctx)); e != a {
+		t.Errorf("expect %q context metadata, got %q", e, a)
+	}
+	if a := request.MetadataFromContext(aws.BackgroundContext()); a != nil {
+		t.Errorf("expect no context metadata, got %v", a)
+	}
+}
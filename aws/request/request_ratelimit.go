@@ -0,0 +1,59 @@
+package request
+
+import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+// WaitRateLimitHandler is a BeforeSend handler waiting for the Config's
+// RateLimiter, if set, to allow another attempt of the request. It is added
+// to the front of the BeforeSend handlers by defaults.Handlers, so the wait
+// is not part of the attempt's AttemptTimeout.
+var WaitRateLimitHandler = NamedHandler{
+	Name: "core.WaitRateLimitHandler",
+	Fn: func(r *Request) {
+		r.waitRateLimit()
+	},
+}
+
+// LimitResponseBodyHandler is an UnmarshalMeta handler limiting the
+// bandwidth of the response body with the Config's RateLimiter, if set. It
+// is added to the UnmarshalMeta handlers by defaults.Handlers, after the
+// DecompressGzipResponseHandler.
+var LimitResponseBodyHandler = NamedHandler{
+	Name: "core.LimitResponseBodyHandler",
+	Fn: func(r *Request) {
+		r.limitResponseBody()
+	},
+}
+
+// waitRateLimit waits for the Config's RateLimiter, if set, to allow another
+// attempt of the request, and limits the bandwidth of the attempt's body.
+// The request's error is set if the request's context is canceled first.
+func (r *Request) waitRateLimit() {
+	l := r.Config.RateLimiter
+	if l == nil {
+		return
+	}
+
+	if err := l.Wait(r.Context()); err != nil {
+		r.Error = awserr.New(CanceledErrorCode, "request context canceled", err)
+		return
+	}
+
+	// Bodies of unknown length are not wrapped, as the HTTP client would
+	// send a body wrapping an empty body with chunked transfer encoding.
+	if r.HTTPRequest.Body != nil && r.HTTPRequest.ContentLength > 0 {
+		r.HTTPRequest.Body = l.ReadCloser(r.Context(), r.HTTPRequest.Body)
+	}
+}
+
+// limitResponseBody limits the bandwidth of the response body with the
+// Config's RateLimiter, if set.
+func (r *Request) limitResponseBody() {
+	l := r.Config.RateLimiter
+	if l == nil || r.HTTPResponse == nil || r.HTTPResponse.Body == nil {
+		return
+	}
+
+	r.HTTPResponse.Body = l.ReadCloser(r.Context(), r.HTTPResponse.Body)
+}
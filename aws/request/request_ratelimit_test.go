@@ -0,0 +1,102 @@
+// +build go1.7
+
+package request_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/ratelimit"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting"
+)
+
+func TestRequestRateLimiter_Shared(t *testing.T) {
+	var reqNum int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&reqNum, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	limiter := ratelimit.New(50, 0, func(l *ratelimit.Limiter) {
+		l.RequestBurst = 1
+	})
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		// Each request is made by a different client sharing the limiter.
+		req := newTimeoutTestRequest(&aws.Config{RateLimiter: limiter}, server.URL)
+		if err := req.Send(); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+
+	if e, a := int32(6), atomic.LoadInt32(&reqNum); e != a {
+		t.Errorf("expect %v requests, got %v", e, a)
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expect 6 requests to take at least 90ms, took %v", elapsed)
+	}
+}
+
+func TestRequestRateLimiter_Canceled(t *testing.T) {
+	var reqNum int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&reqNum, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	limiter := ratelimit.New(1, 0)
+	cfg := &aws.Config{RateLimiter: limiter}
+
+	if err := newTimeoutTestRequest(cfg, server.URL).Send(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	ctx := &awstesting.FakeContext{DoneCh: make(chan struct{})}
+	ctx.Error = fmt.Errorf("context canceled")
+	close(ctx.DoneCh)
+
+	req := newTimeoutTestRequest(cfg, server.URL)
+	req.SetContext(ctx)
+	err := req.Send()
+	aerr, ok := err.(awserr.Error)
+	if !ok {
+		t.Fatalf("expect awserr.Error, got %v", err)
+	}
+	if e, a := request.CanceledErrorCode, aerr.Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+	if e, a := int32(1), atomic.LoadInt32(&reqNum); e != a {
+		t.Errorf("expect %v requests, got %v", e, a)
+	}
+}
+
+func TestRequestRateLimiter_HandlerRemoved(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	limiter := ratelimit.New(1, 0)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		req := newTimeoutTestRequest(&aws.Config{RateLimiter: limiter}, server.URL)
+		req.Handlers.BeforeSend.Remove(request.WaitRateLimitHandler)
+		if err := req.Send(); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expect requests not to be limited, took %v", elapsed)
+	}
+}
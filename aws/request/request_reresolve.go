@@ -0,0 +1,130 @@
+package request
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+// RouteEndpointHandler is a Sign handler routing each attempt to the
+// endpoint variant selected by the Config's EndpointReResolver, if set.
+// Attempts are routed before they are signed, as the host is signed. It is
+// added to the front of the Sign handlers by defaults.Handlers.
+var RouteEndpointHandler = NamedHandler{
+	Name: "core.RouteEndpointHandler",
+	Fn: func(r *Request) {
+		r.routeEndpoint()
+	},
+}
+
+// routeEndpoint routes the attempt to the endpoint variant selected by the
+// Config's EndpointReResolver, if set.
+func (r *Request) routeEndpoint() {
+	e := r.Config.EndpointReResolver
+	if e == nil || r.HTTPRequest == nil || r.HTTPRequest.URL == nil {
+		return
+	}
+
+	endpoint := r.endpointHost()
+	if len(endpoint) == 0 {
+		return
+	}
+	r.setHost(e.Route(endpoint, aws.URLHostname(r.HTTPRequest.URL)))
+}
+
+// reResolveEndpoint counts the consecutive attempts of the request which
+// failed with DNS or connection errors, such as the err of the attempt
+// being retried, and re-resolves the request's
+// endpoint once the Config's EndpointReResolver threshold is reached. The
+// endpoint's host is evicted from the Config's DNSCache, the idle
+// connections of the HTTPClient are closed, and the request is routed to the
+// endpoint's next variant.
+func (r *Request) reResolveEndpoint(err error) {
+	e := r.Config.EndpointReResolver
+	if e == nil || r.HTTPRequest == nil || r.HTTPRequest.URL == nil {
+		return
+	}
+
+	if !isErrDialFailure(err) {
+		r.dialFailures = 0
+		return
+	}
+	r.dialFailures++
+	if r.dialFailures < e.ReResolveAttempts() {
+		return
+	}
+	r.dialFailures = 0
+
+	host := aws.URLHostname(r.HTTPRequest.URL)
+	if c := r.Config.DNSCache; c != nil {
+		c.Forget(host)
+	}
+	closeIdleConnections(r.Config.HTTPClient)
+
+	if endpoint := r.endpointHost(); len(endpoint) != 0 {
+		r.setHost(e.ReResolve(endpoint, host))
+	}
+}
+
+// endpointHost returns the host of the client's endpoint, the host requests
+// are routed to variants of.
+func (r *Request) endpointHost() string {
+	u, err := url.Parse(r.ClientInfo.Endpoint)
+	if err != nil {
+		return ""
+	}
+	return aws.URLHostname(u)
+}
+
+// setHost sets the host of the request's URL, keeping its port.
+func (r *Request) setHost(host string) {
+	u := r.HTTPRequest.URL
+	hostname := aws.URLHostname(u)
+	if host == hostname {
+		return
+	}
+	u.Host = strings.Replace(u.Host, hostname, host, 1)
+	if len(r.HTTPRequest.Host) != 0 {
+		r.HTTPRequest.Host = u.Host
+	}
+}
+
+// closeIdleConnections closes the idle connections of the client's
+// transport, so that the next attempt dials a new connection.
+func closeIdleConnections(client *http.Client) {
+	if client == nil {
+		return
+	}
+	t := client.Transport
+	if t == nil {
+		t = http.DefaultTransport
+	}
+	if c, ok := t.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
+
+// isErrDialFailure returns whether the error is the error of an attempt which
+// could not connect to its host, because the host could not be resolved, or
+// its address could not be dialed.
+func isErrDialFailure(err error) bool {
+	for err != nil {
+		switch e := err.(type) {
+		case *net.DNSError:
+			return true
+		case *net.OpError:
+			return e.Op == "dial"
+		case *url.Error:
+			err = e.Err
+		case awserr.Error:
+			err = e.OrigErr()
+		default:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+// +build go1.8
+
+package request_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/dnscache"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/retry"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	reResolveEndpoint = "s3.us-south.cloud-object-storage.appdomain.cloud"
+	reResolveVariant  = "s3.direct.us-south.cloud-object-storage.appdomain.cloud"
+)
+
+func newReResolveClient(e *retry.EndpointReResolver, cache *dnscache.Resolver, down map[string]bool, hosts *[]string) *s3.S3 {
+	svc := s3.New(unit.Session, &aws.Config{
+		Endpoint:           aws.String("https://" + reResolveEndpoint),
+		MaxRetries:         aws.Int(4),
+		SleepDelay:         func(time.Duration) {},
+		EndpointReResolver: e,
+		DNSCache:           cache,
+	})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		host := r.HTTPRequest.URL.Host
+		*hosts = append(*hosts, host)
+		r.HTTPResponse = &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+		}
+		if down[host] {
+			r.HTTPResponse.StatusCode = 0
+			r.Retryable = aws.Bool(true)
+			r.Error = awserr.New("RequestError", "send request failed", &url.Error{
+				Op:  "Get",
+				URL: r.HTTPRequest.URL.String(),
+				Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+			})
+		}
+	})
+	return svc
+}
+
+func TestEndpointReResolver(t *testing.T) {
+	var reResolved []string
+	e := retry.NewEndpointReResolver(func(e *retry.EndpointReResolver) {
+		e.Variants = func(host string) []string {
+			return []string{host, reResolveVariant}
+		}
+		e.OnReResolve = func(endpoint, from, to string) {
+			reResolved = append(reResolved, from+" "+to)
+		}
+	})
+
+	var lookups int
+	cache := dnscache.New(time.Minute, func(r *dnscache.Resolver) {
+		r.LookupHost = func(context.Context, string) ([]string, error) {
+			lookups++
+			return []string{"127.0.0.1"}, nil
+		}
+	})
+	cache.Lookup(context.Background(), "bucket."+reResolveEndpoint)
+
+	var hosts []string
+	svc := newReResolveClient(e, cache, map[string]bool{"bucket." + reResolveEndpoint: true}, &hosts)
+
+	if _, err := svc.HeadObject(&s3.HeadObjectInput{Bucket: aws.String("bucket"), Key: aws.String("key")}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := []string{"bucket." + reResolveEndpoint, "bucket." + reResolveEndpoint, "bucket." + reResolveVariant}
+	if e, a := len(expect), len(hosts); e != a {
+		t.Fatalf("expect %v attempts, got %v, %v", e, a, hosts)
+	}
+	for i := range expect {
+		if e, a := expect[i], hosts[i]; e != a {
+			t.Errorf("%d, expect %v host, got %v", i, e, a)
+		}
+	}
+	if e, a := 1, len(reResolved); e != a {
+		t.Fatalf("expect %v re-resolution, got %v", e, a)
+	}
+	if e, a := "bucket."+reResolveEndpoint+" bucket."+reResolveVariant, reResolved[0]; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	cache.Lookup(context.Background(), "bucket."+reResolveEndpoint)
+	if e, a := 2, lookups; e != a {
+		t.Errorf("expect %v lookups after the host was forgotten, got %v", e, a)
+	}
+
+	hosts = nil
+	if _, err := svc.HeadObject(&s3.HeadObjectInput{Bucket: aws.String("bucket"), Key: aws.String("key")}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := []string{"bucket." + reResolveVariant}, hosts; len(a) != 1 || e[0] != a[0] {
+		t.Errorf("expect %v hosts, got %v", e, a)
+	}
+}
+
+func TestEndpointReResolver_NoVariants(t *testing.T) {
+	var reResolved int
+	e := retry.NewEndpointReResolver(func(e *retry.EndpointReResolver) {
+		e.OnReResolve = func(endpoint, from, to string) {
+			reResolved++
+			if from != to {
+				t.Errorf("expect host not to change, got %v to %v", from, to)
+			}
+		}
+	})
+
+	var hosts []string
+	svc := newReResolveClient(e, nil, map[string]bool{"bucket." + reResolveEndpoint: true}, &hosts)
+
+	_, err := svc.HeadObject(&s3.HeadObjectInput{Bucket: aws.String("bucket"), Key: aws.String("key")})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := 5, len(hosts); e != a {
+		t.Errorf("expect %v attempts, got %v", e, a)
+	}
+	if e, a := 2, reResolved; e != a {
+		t.Errorf("expect %v re-resolutions, got %v", e, a)
+	}
+}
+
+func TestEndpointReResolver_HandlerRemoved(t *testing.T) {
+	e := retry.NewEndpointReResolver(func(e *retry.EndpointReResolver) {
+		e.Variants = func(host string) []string {
+			return []string{host, reResolveVariant}
+		}
+	})
+
+	var hosts []string
+	svc := newReResolveClient(e, nil, map[string]bool{"bucket." + reResolveEndpoint: true}, &hosts)
+	if _, err := svc.HeadObject(&s3.HeadObjectInput{Bucket: aws.String("bucket"), Key: aws.String("key")}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	hosts = nil
+	svc.Handlers.Sign.Remove(request.RouteEndpointHandler)
+	svc.HeadObject(&s3.HeadObjectInput{Bucket: aws.String("bucket"), Key: aws.String("key")})
+	if len(hosts) == 0 {
+		t.Fatalf("expect attempts, got none")
+	}
+	if e, a := "bucket."+reResolveEndpoint, hosts[0]; e != a {
+		t.Errorf("expect %v host, got %v", e, a)
+	}
+}
@@ -0,0 +1,228 @@
+package request
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+const (
+	// ErrCodeAttemptTimeout is the error code returned when a single attempt
+	// of an API operation request exceeds the aws.Config AttemptTimeout.
+	// Attempts which time out are retryable.
+	ErrCodeAttemptTimeout = "AttemptTimeout"
+
+	// ErrCodeOperationTimeout is the error code returned when an API operation
+	// request, including all of its retries, exceeds the aws.Config
+	// OperationTimeout.
+	ErrCodeOperationTimeout = "OperationTimeout"
+)
+
+// WithAttemptTimeout is a request option that will set the maximum duration
+// a single attempt of the request may take, overriding the aws.Config
+// AttemptTimeout value.
+//
+//     svc.GetObjectWithContext(ctx, params, request.WithAttemptTimeout(10*time.Second))
+func WithAttemptTimeout(timeout time.Duration) Option {
+	return func(r *Request) {
+		r.Config.AttemptTimeout = aws.Duration(timeout)
+	}
+}
+
+// WithOperationTimeout is a request option that will set the maximum duration
+// the request may take across all of its attempts, overriding the aws.Config
+// OperationTimeout value.
+//
+//     svc.PutObjectWithContext(ctx, params, request.WithOperationTimeout(time.Minute))
+func WithOperationTimeout(timeout time.Duration) Option {
+	return func(r *Request) {
+		r.Config.OperationTimeout = aws.Duration(timeout)
+	}
+}
+
+// timeoutCanceler cancels a context once its timeout duration elapses.
+type timeoutCanceler struct {
+	timer   *time.Timer
+	cancel  func()
+	expired int32
+}
+
+func newTimeoutContext(parent aws.Context, timeout time.Duration) (aws.Context, *timeoutCanceler) {
+	ctx, cancel := withCancel(parent)
+
+	t := &timeoutCanceler{cancel: cancel}
+	t.timer = time.AfterFunc(timeout, func() {
+		atomic.StoreInt32(&t.expired, 1)
+		cancel()
+	})
+
+	return ctx, t
+}
+
+// Expired returns if the timeout elapsed before the timer was stopped.
+func (t *timeoutCanceler) Expired() bool {
+	return atomic.LoadInt32(&t.expired) == 1
+}
+
+// stop stops the timer without canceling the context.
+func (t *timeoutCanceler) stop() {
+	t.timer.Stop()
+}
+
+// release stops the timer, and cancels the context releasing its resources.
+func (t *timeoutCanceler) release() {
+	t.timer.Stop()
+	t.cancel()
+}
+
+// releaseOnCloseBody wraps a response body so the contexts the response was
+// read with are released once the body is closed.
+type releaseOnCloseBody struct {
+	io.ReadCloser
+
+	m      sync.Mutex
+	closed bool
+	fns    []func()
+}
+
+func (b *releaseOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+
+	b.m.Lock()
+	fns := b.fns
+	b.fns = nil
+	b.closed = true
+	b.m.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+
+	return err
+}
+
+// onClose registers fn to be called when the body is closed. If the body was
+// already closed fn will be called immediately.
+func (b *releaseOnCloseBody) onClose(fn func()) {
+	b.m.Lock()
+	if !b.closed {
+		b.fns = append(b.fns, fn)
+		b.m.Unlock()
+		return
+	}
+	b.m.Unlock()
+
+	fn()
+}
+
+// startOperationTimeout sets the request's context to one that is canceled
+// when the operation timeout elapses, if an operation timeout is configured.
+func (r *Request) startOperationTimeout() {
+	timeout := aws.DurationValue(r.Config.OperationTimeout)
+	if timeout <= 0 {
+		return
+	}
+
+	ctx, t := newTimeoutContext(r.Context(), timeout)
+	r.operationTimeout = t
+	setRequestContext(r, ctx)
+}
+
+// endTimeouts stops the request's timeouts. If the operation timeout elapsed
+// the request's error will be replaced with an ErrCodeOperationTimeout error.
+//
+// When the request succeeds the timeouts' contexts will not be released until
+// the response body is closed, allowing streaming response bodies to be read
+// by the caller.
+func (r *Request) endTimeouts() {
+	op, attempt := r.operationTimeout, r.attemptTimeout
+	if op == nil && attempt == nil {
+		return
+	}
+
+	if op != nil {
+		op.stop()
+		if r.Error != nil && op.Expired() {
+			r.Error = awserr.New(ErrCodeOperationTimeout,
+				"request exceeded the configured operation timeout", r.Error)
+			r.Retryable = aws.Bool(false)
+		}
+	}
+	if attempt != nil {
+		attempt.stop()
+	}
+
+	if r.Error == nil && r.responseBody != nil {
+		if op != nil {
+			r.responseBody.onClose(op.release)
+		}
+		return
+	}
+
+	if attempt != nil {
+		attempt.release()
+	}
+	if op != nil {
+		op.release()
+	}
+}
+
+// startAttemptTimeout sets the HTTP request's context to one that is
+// canceled when the attempt timeout elapses, if an attempt timeout is
+// configured. Resources of a previous attempt will be released.
+func (r *Request) startAttemptTimeout() {
+	if r.attemptTimeout != nil {
+		r.attemptTimeout.release()
+		r.attemptTimeout = nil
+	}
+
+	timeout := aws.DurationValue(r.Config.AttemptTimeout)
+	if timeout <= 0 {
+		return
+	}
+
+	ctx, t := newTimeoutContext(r.Context(), timeout)
+	r.attemptTimeout = t
+	setAttemptContext(r, ctx)
+}
+
+// wrapResponseBody wraps the HTTP response body so the attempt's timeout
+// resources are released once the body is closed.
+func (r *Request) wrapResponseBody() {
+	r.responseBody = nil
+	if r.attemptTimeout == nil && r.operationTimeout == nil {
+		return
+	}
+	if r.HTTPResponse == nil || r.HTTPResponse.Body == nil {
+		return
+	}
+
+	body := &releaseOnCloseBody{ReadCloser: r.HTTPResponse.Body}
+	if t := r.attemptTimeout; t != nil {
+		body.fns = append(body.fns, t.release)
+	}
+	r.responseBody = body
+	r.HTTPResponse.Body = body
+}
+
+// adaptAttemptTimeoutError replaces the request's error with an
+// ErrCodeAttemptTimeout error if the attempt's timeout elapsed.
+func (r *Request) adaptAttemptTimeoutError() {
+	t := r.attemptTimeout
+	if t == nil || r.Error == nil || !t.Expired() {
+		return
+	}
+	if r.operationTimeout != nil && r.operationTimeout.Expired() {
+		return
+	}
+
+	if aerr, ok := r.Error.(awserr.Error); ok && aerr.Code() == ErrCodeAttemptTimeout {
+		return
+	}
+	r.Error = awserr.New(ErrCodeAttemptTimeout,
+		"request attempt exceeded the configured attempt timeout", r.Error)
+}
@@ -0,0 +1,79 @@
+// +build !go1.7
+
+package request
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// errCanceled is the error of a cancelCtx which was canceled, the same as
+// the Go 1.7 context.Canceled error.
+var errCanceled = errors.New("context canceled")
+
+// cancelCtx is a copy of the Go 1.7 stdlib's cancelable context, canceled
+// when its cancel function is called or its parent is canceled.
+type cancelCtx struct {
+	parent aws.Context
+	done   chan struct{}
+
+	m   sync.Mutex
+	err error
+}
+
+func (c *cancelCtx) Deadline() (deadline time.Time, ok bool) {
+	return c.parent.Deadline()
+}
+
+func (c *cancelCtx) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *cancelCtx) Err() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return c.err
+}
+
+func (c *cancelCtx) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+func (c *cancelCtx) cancel(err error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	close(c.done)
+}
+
+// withCancel returns a copy of the context which is canceled when the
+// returned cancel function is called, or the parent context is canceled.
+func withCancel(parent aws.Context) (aws.Context, func()) {
+	c := &cancelCtx{parent: parent, done: make(chan struct{})}
+
+	if parentDone := parent.Done(); parentDone != nil {
+		go func() {
+			select {
+			case <-parentDone:
+				c.cancel(parent.Err())
+			case <-c.done:
+			}
+		}()
+	}
+
+	return c, func() { c.cancel(errCanceled) }
+}
+
+// setAttemptContext sets the context the HTTP request of the current attempt
+// is sent with, without changing the request's context.
+func setAttemptContext(r *Request, ctx aws.Context) {
+	r.HTTPRequest.Cancel = ctx.Done()
+}
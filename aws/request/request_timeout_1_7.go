@@ -0,0 +1,21 @@
+// +build go1.7
+
+package request
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// withCancel returns a copy of the context which is canceled when the
+// returned cancel function is called, or the parent context is canceled.
+func withCancel(parent aws.Context) (aws.Context, func()) {
+	return context.WithCancel(parent)
+}
+
+// setAttemptContext sets the context the HTTP request of the current attempt
+// is sent with, without changing the request's context.
+func setAttemptContext(r *Request, ctx aws.Context) {
+	r.HTTPRequest = r.HTTPRequest.WithContext(ctx)
+}
@@ -0,0 +1,151 @@
+// +build go1.7
+
+package request_test
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+)
+
+func newTimeoutTestRequest(cfg *aws.Config, url string, opts ...request.Option) *request.Request {
+	cfg.Region = unit.Session.Config.Region
+	cfg.DisableSSL = aws.Bool(true)
+	cfg.Endpoint = aws.String(url)
+	cfg.SleepDelay = func(time.Duration) {}
+
+	svc := awstesting.NewClient(cfg)
+	req := svc.NewRequest(&request.Operation{
+		Name: "name", HTTPMethod: "GET", HTTPPath: "/path",
+	}, &struct{}{}, &struct{}{})
+	req.Handlers.Unmarshal.PushBack(func(r *request.Request) {
+		defer r.HTTPResponse.Body.Close()
+		if _, err := io.Copy(ioutil.Discard, r.HTTPResponse.Body); err != nil {
+			r.Error = awserr.New(request.ErrCodeSerialization, "failed to read body", err)
+		}
+	})
+	req.ApplyOptions(opts...)
+
+	return req
+}
+
+func TestRequestAttemptTimeout_Retried(t *testing.T) {
+	var reqNum int32
+	done := make(chan struct{})
+	defer close(done)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&reqNum, 1) == 1 {
+			select {
+			case <-done:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	req := newTimeoutTestRequest(&aws.Config{
+		MaxRetries:     aws.Int(2),
+		AttemptTimeout: aws.Duration(50 * time.Millisecond),
+	}, server.URL)
+
+	var retryErr error
+	req.Handlers.Retry.PushFront(func(r *request.Request) {
+		retryErr = r.Error
+	})
+
+	if err := req.Send(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 1, req.RetryCount; e != a {
+		t.Errorf("expect %d retries, got %d", e, a)
+	}
+
+	aerr, ok := retryErr.(awserr.Error)
+	if !ok {
+		t.Fatalf("expect awserr.Error, got %T", retryErr)
+	}
+	if e, a := request.ErrCodeAttemptTimeout, aerr.Code(); e != a {
+		t.Errorf("expect %q error code, got %q", e, a)
+	}
+}
+
+func TestRequestOperationTimeout(t *testing.T) {
+	var reqNum int32
+	done := make(chan struct{})
+	defer close(done)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&reqNum, 1)
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	req := newTimeoutTestRequest(&aws.Config{
+		MaxRetries:     aws.Int(100),
+		AttemptTimeout: aws.Duration(20 * time.Millisecond),
+	}, server.URL, request.WithOperationTimeout(100*time.Millisecond))
+
+	start := time.Now()
+	err := req.Send()
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expect operation to be bounded by timeout, took %v", elapsed)
+	}
+
+	aerr := err.(awserr.Error)
+	if e, a := request.ErrCodeOperationTimeout, aerr.Code(); e != a {
+		t.Errorf("expect %q error code, got %q", e, a)
+	}
+	if aws.BoolValue(req.Retryable) {
+		t.Errorf("expect operation timeout not to be retryable")
+	}
+	if n := atomic.LoadInt32(&reqNum); n < 2 {
+		t.Errorf("expect attempts to be retried within the operation, got %d", n)
+	}
+}
+
+func TestRequestOperationTimeout_StreamingBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte("streamed"))
+	}))
+	defer server.Close()
+
+	req := newTimeoutTestRequest(&aws.Config{}, server.URL,
+		request.WithAttemptTimeout(20*time.Millisecond),
+		request.WithOperationTimeout(20*time.Millisecond))
+	req.Handlers.Unmarshal.Clear()
+
+	if err := req.Send(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer req.HTTPResponse.Body.Close()
+
+	b, err := ioutil.ReadAll(req.HTTPResponse.Body)
+	if err != nil {
+		t.Fatalf("expect body to be readable after Send, got %v", err)
+	}
+	if e, a := "streamed", string(b); e != a {
+		t.Errorf("expect %q body, got %q", e, a)
+	}
+}
@@ -1,6 +1,9 @@
 package request
 
 import (
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -16,6 +19,15 @@ type Retryer interface {
 	MaxRetries() int
 }
 
+// An OperationRetryer is a Retryer selecting the Retryer of each API
+// operation's requests, such as the client.ClassRetryer, so the retries of
+// operations can be configured differently on one client. Requests are
+// created with the Retryer returned for their operation.
+type OperationRetryer interface {
+	Retryer
+	RetryerForOperation(*Operation) Retryer
+}
+
 // WithRetryer sets a config Retryer value to the given Config returning it
 // for chaining.
 func WithRetryer(cfg *aws.Config, retryer Retryer) *aws.Config {
@@ -29,6 +41,7 @@ var retryableCodes = map[string]struct{}{
 	"RequestError":            {},
 	"RequestTimeout":          {},
 	ErrCodeResponseTimeout:    {},
+	ErrCodeAttemptTimeout:     {},
 	"RequestTimeoutException": {}, // Glacier's flavor of RequestTimeout
 }
 
@@ -159,3 +172,35 @@ func (r *Request) IsErrorThrottle() bool {
 func (r *Request) IsErrorExpired() bool {
 	return IsErrorExpiredCreds(r.Error)
 }
+
+// RetryAfter returns the delay of the Retry-After header of the request's
+// HTTP response, in either delay-seconds or HTTP-date form, and whether the
+// response has a valid Retry-After header. The delay of an HTTP-date in the
+// past is 0.
+func (r *Request) RetryAfter() (time.Duration, bool) {
+	if r.HTTPResponse == nil {
+		return 0, false
+	}
+
+	v := strings.TrimSpace(r.HTTPResponse.Header.Get("Retry-After"))
+	if len(v) == 0 {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		delay := t.Sub(time.Now())
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,101 @@
+// Package retry provides opt-in retry budgets, request hedging, and
+// endpoint re-resolution for the SDK's API requests.
+//
+// A Budget limits the retries of requests to a ratio of the requests made,
+// so that retries cannot multiply the load on a service during an outage. A
+// HedgePolicy issues a second attempt of idempotent read requests, GET and
+// HEAD, whose first attempt has not responded within a percentile of the
+// latencies observed, trimming the tail latency of reads. Hedged attempts
+// are taken from the Budget when both are set. An EndpointReResolver
+// re-resolves the endpoints of requests repeatedly failing with DNS or
+// connection errors before they are retried.
+//
+// They are enabled by setting the aws.Config RetryBudget, HedgePolicy, and
+// EndpointReResolver fields, and may be shared by the Configs of any number
+// of service clients.
+//
+//     sess := session.Must(session.NewSession(&aws.Config{
+//         RetryBudget: retry.NewBudget(0.1),
+//         HedgePolicy: retry.NewHedgePolicy(0.95),
+//     }))
+//     svc := s3.New(sess)
+package retry
+
+import (
+	"math"
+	"sync"
+)
+
+// DefaultBudgetBurst is the default number of retries a Budget allows at
+// once, before being limited to its Ratio of requests.
+const DefaultBudgetBurst = 10
+
+// A Budget limits the number of retries to a ratio of the number of
+// requests. Each request deposits Ratio retries to the budget, and each retry
+// withdraws one, up to Burst retries. It is safe to use concurrently, and a
+// single Budget may be shared by multiple service clients. The Budget's
+// fields must not be modified once it is in use.
+type Budget struct {
+	// Ratio is the number of retries allowed per request, such as 0.1
+	// allowing retries of one in ten requests.
+	Ratio float64
+
+	// Burst is the maximum number of retries which may be made at once. The
+	// budget starts with Burst retries available. If zero,
+	// DefaultBudgetBurst will be used.
+	Burst int
+
+	m       sync.Mutex
+	tokens  float64
+	started bool
+}
+
+// NewBudget returns a Budget allowing ratio retries per request. Pass in
+// additional functional options to customize the budget.
+func NewBudget(ratio float64, options ...func(*Budget)) *Budget {
+	b := &Budget{Ratio: ratio}
+	for _, option := range options {
+		option(b)
+	}
+
+	return b
+}
+
+// RecordRequest deposits the retries allowed for a request to the budget.
+func (b *Budget) RecordRequest() {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.start()
+	b.tokens = math.Min(b.burst(), b.tokens+b.Ratio)
+}
+
+// AllowRetry withdraws a retry from the budget, returning false if the
+// budget has no retries available.
+func (b *Budget) AllowRetry() bool {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.start()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// start fills the budget the first time it is used. Must be called with the
+// lock held.
+func (b *Budget) start() {
+	if !b.started {
+		b.tokens = b.burst()
+		b.started = true
+	}
+}
+
+func (b *Budget) burst() float64 {
+	if b.Burst <= 0 {
+		return DefaultBudgetBurst
+	}
+	return float64(b.Burst)
+}
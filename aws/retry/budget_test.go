@@ -0,0 +1,41 @@
+package retry
+
+import "testing"
+
+func TestBudget(t *testing.T) {
+	b := NewBudget(0.5, func(b *Budget) {
+		b.Burst = 2
+	})
+
+	// The budget starts with the burst of 2 retries.
+	for i := 0; i < 2; i++ {
+		if !b.AllowRetry() {
+			t.Fatalf("%d, expect retry allowed", i)
+		}
+	}
+	if b.AllowRetry() {
+		t.Fatalf("expect retry not allowed once budget exhausted")
+	}
+
+	// Each request deposits half a retry.
+	b.RecordRequest()
+	if b.AllowRetry() {
+		t.Fatalf("expect retry not allowed after one request")
+	}
+	b.RecordRequest()
+	if !b.AllowRetry() {
+		t.Fatalf("expect retry allowed after two requests")
+	}
+
+	// Deposits are capped at the burst.
+	for i := 0; i < 10; i++ {
+		b.RecordRequest()
+	}
+	var allowed int
+	for b.AllowRetry() {
+		allowed++
+	}
+	if e, a := 2, allowed; e != a {
+		t.Errorf("expect %v retries allowed, got %v", e, a)
+	}
+}
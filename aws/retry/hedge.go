@@ -0,0 +1,115 @@
+package retry
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultHedgeMinSamples is the default number of latencies a
+	// HedgePolicy must observe before hedging requests.
+	DefaultHedgeMinSamples = 20
+
+	// DefaultHedgeWindow is the default number of most recent latencies a
+	// HedgePolicy computes its percentile from.
+	DefaultHedgeWindow = 200
+)
+
+// A HedgePolicy determines the delay after which a second attempt of an
+// idempotent read request is made, if its first attempt has not responded.
+// The delay is the Percentile of the latencies observed of recent attempts,
+// so that only the slowest of requests are hedged. It is safe to use
+// concurrently, and a single HedgePolicy may be shared by multiple service
+// clients. The HedgePolicy's fields must not be modified once it is in use.
+type HedgePolicy struct {
+	// Percentile is the percentile, between 0 and 1, of the observed
+	// latencies after which requests are hedged, such as 0.95 hedging the
+	// five percent of requests slower than the rest.
+	Percentile float64
+
+	// MinDelay is the minimum delay before a request is hedged, regardless
+	// of the latencies observed.
+	MinDelay time.Duration
+
+	// MinSamples is the number of latencies which must be observed before
+	// requests are hedged. If zero, DefaultHedgeMinSamples will be used.
+	MinSamples int
+
+	// Window is the number of most recent latencies the percentile is
+	// computed from. If zero, DefaultHedgeWindow will be used.
+	Window int
+
+	m       sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+// NewHedgePolicy returns a HedgePolicy hedging requests slower than the
+// percentile of observed latencies. Pass in additional functional options to
+// customize the policy.
+func NewHedgePolicy(percentile float64, options ...func(*HedgePolicy)) *HedgePolicy {
+	p := &HedgePolicy{Percentile: percentile}
+	for _, option := range options {
+		option(p)
+	}
+
+	return p
+}
+
+// Observe records the latency of a request attempt.
+func (p *HedgePolicy) Observe(latency time.Duration) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	window := p.Window
+	if window <= 0 {
+		window = DefaultHedgeWindow
+	}
+
+	if len(p.samples) < window {
+		p.samples = append(p.samples, latency)
+		return
+	}
+	p.samples[p.next] = latency
+	p.next = (p.next + 1) % len(p.samples)
+}
+
+// Delay returns the delay after which a request should be hedged. False is
+// returned if not enough latencies have been observed yet to hedge requests.
+func (p *HedgePolicy) Delay() (time.Duration, bool) {
+	p.m.Lock()
+	samples := make([]time.Duration, len(p.samples))
+	copy(samples, p.samples)
+	p.m.Unlock()
+
+	minSamples := p.MinSamples
+	if minSamples <= 0 {
+		minSamples = DefaultHedgeMinSamples
+	}
+	if len(samples) == 0 || len(samples) < minSamples {
+		return 0, false
+	}
+
+	sort.Sort(durations(samples))
+
+	i := int(math.Ceil(p.Percentile*float64(len(samples)))) - 1
+	if i < 0 {
+		i = 0
+	} else if i >= len(samples) {
+		i = len(samples) - 1
+	}
+
+	delay := samples[i]
+	if delay < p.MinDelay {
+		delay = p.MinDelay
+	}
+	return delay, true
+}
+
+type durations []time.Duration
+
+func (d durations) Len() int           { return len(d) }
+func (d durations) Less(i, j int) bool { return d[i] < d[j] }
+func (d durations) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
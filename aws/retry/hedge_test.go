@@ -0,0 +1,56 @@
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHedgePolicyDelay(t *testing.T) {
+	p := NewHedgePolicy(0.9, func(p *HedgePolicy) {
+		p.MinSamples = 5
+		p.Window = 10
+	})
+
+	for i := 1; i <= 4; i++ {
+		p.Observe(time.Duration(i) * time.Millisecond)
+	}
+	if _, ok := p.Delay(); ok {
+		t.Fatalf("expect no delay before min samples observed")
+	}
+
+	for i := 5; i <= 10; i++ {
+		p.Observe(time.Duration(i) * time.Millisecond)
+	}
+	delay, ok := p.Delay()
+	if !ok {
+		t.Fatalf("expect delay once min samples observed")
+	}
+	if e, a := 9*time.Millisecond, delay; e != a {
+		t.Errorf("expect %v delay, got %v", e, a)
+	}
+
+	// Samples beyond the window replace the oldest samples.
+	for i := 0; i < 5; i++ {
+		p.Observe(100 * time.Millisecond)
+	}
+	delay, _ = p.Delay()
+	if e, a := 100*time.Millisecond, delay; e != a {
+		t.Errorf("expect %v delay, got %v", e, a)
+	}
+}
+
+func TestHedgePolicyMinDelay(t *testing.T) {
+	p := NewHedgePolicy(0.5, func(p *HedgePolicy) {
+		p.MinSamples = 1
+		p.MinDelay = 50 * time.Millisecond
+	})
+	p.Observe(time.Millisecond)
+
+	delay, ok := p.Delay()
+	if !ok {
+		t.Fatalf("expect delay")
+	}
+	if e, a := 50*time.Millisecond, delay; e != a {
+		t.Errorf("expect %v delay, got %v", e, a)
+	}
+}
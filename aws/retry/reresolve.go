@@ -0,0 +1,144 @@
+package retry
+
+import (
+	"strings"
+	"sync"
+)
+
+// DefaultReResolveAttempts is the default number of consecutive attempts of
+// a request failing with DNS or connection errors after which an
+// EndpointReResolver re-resolves the request's endpoint.
+const DefaultReResolveAttempts = 2
+
+// An EndpointReResolver re-resolves the endpoint of requests whose attempts
+// repeatedly fail with DNS or connection errors before they are retried
+// again, so that a stale DNS entry or an unreachable gateway address does not
+// fail the requests of a client until it is restarted.
+//
+// Re-resolving an endpoint evicts its host from the aws.Config DNSCache, if
+// set, closes the idle connections of the HTTPClient's transport, and routes
+// the requests to the endpoint to its next variant, if it has any. The
+// variant is used by the following requests to the endpoint until it is
+// re-resolved again.
+//
+// It is safe to use concurrently, and a single EndpointReResolver may be
+// shared by multiple service clients. The EndpointReResolver's fields must
+// not be modified once it is in use.
+//
+//     sess := session.Must(session.NewSession(&aws.Config{
+//         EndpointReResolver: retry.NewEndpointReResolver(func(e *retry.EndpointReResolver) {
+//             e.Variants = func(host string) []string {
+//                 return []string{host, "s3.direct.us-south.cloud-object-storage.appdomain.cloud"}
+//             }
+//         }),
+//     }))
+type EndpointReResolver struct {
+	// Attempts is the number of consecutive attempts of a request failing
+	// with DNS or connection errors after which its endpoint is re-resolved.
+	// If zero, DefaultReResolveAttempts will be used.
+	Attempts int
+
+	// Variants returns the hosts requests to the endpoint host may be sent
+	// to, in order of preference, such as the hosts of the endpoint's
+	// gateways. Re-resolving an endpoint routes its requests to the next
+	// variant, wrapping around after the last. If nil, or no variants are
+	// returned, requests are not routed to another host.
+	Variants func(host string) []string
+
+	// OnReResolve is called when the endpoint host is re-resolved, with the
+	// host its requests were sent to, and the host they are sent to next, if
+	// set. It is called from the goroutine of the failed request, and must
+	// not block.
+	OnReResolve func(endpoint, from, to string)
+
+	m      sync.Mutex
+	active map[string]int
+}
+
+// NewEndpointReResolver returns an EndpointReResolver. Pass in additional
+// functional options to customize the re-resolution behavior.
+func NewEndpointReResolver(options ...func(*EndpointReResolver)) *EndpointReResolver {
+	e := &EndpointReResolver{}
+	for _, option := range options {
+		option(e)
+	}
+
+	return e
+}
+
+// ReResolveAttempts returns the number of consecutive failed attempts after
+// which endpoints are re-resolved.
+func (e *EndpointReResolver) ReResolveAttempts() int {
+	if e.Attempts <= 0 {
+		return DefaultReResolveAttempts
+	}
+	return e.Attempts
+}
+
+// Route returns the host requests to the endpoint host are sent to. Hosts
+// prefixed with a label of the endpoint, such as the virtual hosted-style
+// bucket name of S3 requests, are returned with the prefix.
+func (e *EndpointReResolver) Route(endpoint, host string) string {
+	variants := e.variants(endpoint)
+	if len(variants) == 0 {
+		return host
+	}
+
+	e.m.Lock()
+	active := variants[e.active[endpoint]%len(variants)]
+	e.m.Unlock()
+
+	return replaceEndpointHost(host, append([]string{endpoint}, variants...), active)
+}
+
+// ReResolve routes the requests to the endpoint host to its next variant,
+// returning the host the requests are sent to next. The host returned is
+// the request's host if the endpoint has no variants.
+func (e *EndpointReResolver) ReResolve(endpoint, host string) string {
+	variants := e.variants(endpoint)
+	if len(variants) == 0 {
+		e.notify(endpoint, host, host)
+		return host
+	}
+
+	e.m.Lock()
+	if e.active == nil {
+		e.active = map[string]int{}
+	}
+	e.active[endpoint] = (e.active[endpoint] + 1) % len(variants)
+	next := variants[e.active[endpoint]]
+	e.m.Unlock()
+
+	to := replaceEndpointHost(host, append([]string{endpoint}, variants...), next)
+	e.notify(endpoint, host, to)
+	return to
+}
+
+func (e *EndpointReResolver) variants(endpoint string) []string {
+	if e.Variants == nil {
+		return nil
+	}
+	return e.Variants(endpoint)
+}
+
+func (e *EndpointReResolver) notify(endpoint, from, to string) {
+	if e.OnReResolve != nil {
+		e.OnReResolve(endpoint, from, to)
+	}
+}
+
+// replaceEndpointHost replaces the endpoint host the host is, or is prefixed
+// with, with the host provided. The longest endpoint matching is replaced,
+// as variants may be subdomains of the endpoint.
+func replaceEndpointHost(host string, endpoints []string, to string) string {
+	var match string
+	for _, ep := range endpoints {
+		if len(ep) > len(match) && (host == ep || strings.HasSuffix(host, "."+ep)) {
+			match = ep
+		}
+	}
+	if len(match) == 0 {
+		return host
+	}
+	return strings.TrimSuffix(host, match) + to
+}
@@ -0,0 +1,33 @@
+package retry
+
+import "testing"
+
+func TestEndpointReResolver(t *testing.T) {
+	e := NewEndpointReResolver(func(e *EndpointReResolver) {
+		e.Variants = func(host string) []string {
+			return []string{"a.example.com", "b.example.com"}
+		}
+	})
+
+	if e, a := DefaultReResolveAttempts, e.ReResolveAttempts(); e != a {
+		t.Errorf("expect %v attempts, got %v", e, a)
+	}
+
+	cases := []struct {
+		Host, Route, ReResolve string
+	}{
+		{Host: "example.com", Route: "a.example.com", ReResolve: "b.example.com"},
+		{Host: "bucket.b.example.com", Route: "bucket.b.example.com", ReResolve: "bucket.a.example.com"},
+		{Host: "bucket.a.example.com", Route: "bucket.a.example.com", ReResolve: "bucket.b.example.com"},
+		{Host: "other.com", Route: "other.com", ReResolve: "other.com"},
+	}
+
+	for i, c := range cases {
+		if e, a := c.Route, e.Route("example.com", c.Host); e != a {
+			t.Errorf("%d, expect %v route, got %v", i, e, a)
+		}
+		if e, a := c.ReResolve, e.ReResolve("example.com", c.Host); e != a {
+			t.Errorf("%d, expect %v re-resolved, got %v", i, e, a)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package session
+
+import (
+	"bytes"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/awstesting"
+)
+
+func newMutualTLSTestServer() *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	return server
+}
+
+func TestNewSession_WithClientTLSCert(t *testing.T) {
+	oldEnv := initSessionTestEnv()
+	defer awstesting.PopEnv(oldEnv)
+
+	server := newMutualTLSTestServer()
+	defer server.Close()
+
+	s, err := NewSessionWithOptions(Options{
+		Config:        aws.Config{HTTPClient: awstesting.NewTLSClient(server)},
+		ClientTLSCert: bytes.NewReader(awstesting.TLSBundleCert),
+		ClientTLSKey:  bytes.NewReader(awstesting.TLSBundleKey),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	resp, err := s.Config.HTTPClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	resp.Body.Close()
+	if e, a := http.StatusOK, resp.StatusCode; e != a {
+		t.Errorf("expect %d status code, got %d", e, a)
+	}
+}
+
+func TestNewSession_WithClientTLSCert_Env(t *testing.T) {
+	oldEnv := initSessionTestEnv()
+	defer awstesting.PopEnv(oldEnv)
+
+	os.Setenv("AWS_SDK_GO_CLIENT_TLS_CERT", TLSBundleCertFile)
+	os.Setenv("AWS_SDK_GO_CLIENT_TLS_KEY", TLSBundleKeyFile)
+
+	s, err := NewSessionWithOptions(Options{
+		Config: aws.Config{HTTPClient: &http.Client{}},
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	tr := s.Config.HTTPClient.Transport.(*http.Transport)
+	if e, a := 1, len(tr.TLSClientConfig.Certificates); e != a {
+		t.Errorf("expect %d certificates, got %d", e, a)
+	}
+}
+
+func TestNewSession_WithClientTLSCert_MissingKey(t *testing.T) {
+	oldEnv := initSessionTestEnv()
+	defer awstesting.PopEnv(oldEnv)
+
+	_, err := NewSessionWithOptions(Options{
+		ClientTLSCert: bytes.NewReader(awstesting.TLSBundleCert),
+	})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := ErrCodeLoadClientTLSCert, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %s error code, got %s", e, a)
+	}
+}
+
+func TestSessionClientConfig_WithTLSConfig(t *testing.T) {
+	oldEnv := initSessionTestEnv()
+	defer awstesting.PopEnv(oldEnv)
+
+	server := newMutualTLSTestServer()
+	defer server.Close()
+
+	s, err := NewSession(&aws.Config{
+		Region:     aws.String("us-south"),
+		HTTPClient: awstesting.NewTLSClient(server),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// Requests without the client certificate are rejected.
+	if resp, err := s.Config.HTTPClient.Get(server.URL); err == nil {
+		resp.Body.Close()
+		t.Fatalf("expect error without client certificate, got none")
+	}
+
+	cert, err := tls.X509KeyPair(awstesting.TLSBundleCert, awstesting.TLSBundleKey)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	cfg := s.ClientConfig("s3", &aws.Config{
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+	})
+
+	// The server's root CAs of the session's transport are kept.
+	resp, err := cfg.Config.HTTPClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	resp.Body.Close()
+	if e, a := http.StatusOK, resp.StatusCode; e != a {
+		t.Errorf("expect %d status code, got %d", e, a)
+	}
+}
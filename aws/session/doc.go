@@ -269,5 +269,18 @@ over the AWS_CA_BUNDLE environment variable, and will be used if both are set.
 Setting a custom HTTPClient in the aws.Config options will override this setting.
 To use this option and custom HTTP client, the HTTP client needs to be provided
 when creating the session. Not the service client.
+
+Paths to a client TLS certificate and key PEM file that the SDK will present
+to endpoints requiring mutual TLS, such as COS direct endpoints and private
+gateways. Both must be set.
+
+	AWS_SDK_GO_CLIENT_TLS_CERT=$HOME/client.crt
+	AWS_SDK_GO_CLIENT_TLS_KEY=$HOME/client.key
+
+Enabling this option will attempt to merge the Transport into the SDK's HTTP
+client in the same way as AWS_CA_BUNDLE. The Session options ClientTLSCert and
+ClientTLSKey have priority over the environment variables. To present client
+certificates for a single service client, set the aws.Config TLSConfig value
+of the service client instead.
 */
 package session
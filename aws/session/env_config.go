@@ -92,6 +92,17 @@ type envConfig struct {
 	//
 	//  AWS_CA_BUNDLE=$HOME/my_custom_ca_bundle
 	CustomCABundle string
+
+	// Paths of the client TLS certificate and key PEM files the SDK will
+	// present to endpoints requiring mutual TLS. Both must be set.
+	//
+	// Enabling this option will attempt to merge the Transport into the SDK's
+	// HTTP client, in the same way as AWS_CA_BUNDLE.
+	//
+	//  AWS_SDK_GO_CLIENT_TLS_CERT=$HOME/client.crt
+	//  AWS_SDK_GO_CLIENT_TLS_KEY=$HOME/client.key
+	ClientTLSCert string
+	ClientTLSKey  string
 }
 
 var (
@@ -174,6 +185,8 @@ func envConfigLoad(enableSharedConfig bool) envConfig {
 	setFromEnvVal(&cfg.SharedConfigFile, sharedConfigFileEnvKey)
 
 	cfg.CustomCABundle = os.Getenv("AWS_CA_BUNDLE")
+	cfg.ClientTLSCert = os.Getenv("AWS_SDK_GO_CLIENT_TLS_CERT")
+	cfg.ClientTLSKey = os.Getenv("AWS_SDK_GO_CLIENT_TLS_KEY")
 
 	return cfg
 }
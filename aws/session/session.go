@@ -193,6 +193,22 @@ type Options struct {
 	// to also enable this feature. CustomCABundle session option field has priority
 	// over the AWS_CA_BUNDLE environment variable, and will be used if both are set.
 	CustomCABundle io.Reader
+
+	// Readers for a client TLS certificate and its private key, in PEM
+	// format, that the SDK will present to endpoints requiring mutual TLS,
+	// such as COS direct endpoints and private gateways. Both must be set.
+	//
+	// Enabling this option will attempt to merge the Transport into the SDK's
+	// HTTP client. If the client's Transport is not a http.Transport an error
+	// will be returned. If the Transport's TLS config is set this option will
+	// cause the SDK to overwrite the Transport's TLS config's Certificates
+	// value.
+	//
+	// The session options have priority over the AWS_SDK_GO_CLIENT_TLS_CERT
+	// and AWS_SDK_GO_CLIENT_TLS_KEY environment variables, and will be used
+	// if both are set.
+	ClientTLSCert io.Reader
+	ClientTLSKey  io.Reader
 }
 
 // NewSessionWithOptions returns a new Session created from SDK defaults, config files,
@@ -261,6 +277,27 @@ func NewSessionWithOptions(opts Options) (*Session, error) {
 		opts.CustomCABundle = f
 	}
 
+	// Only use the client TLS certificate environment variables if the
+	// session options are not provided.
+	if len(envCfg.ClientTLSCert) != 0 && opts.ClientTLSCert == nil {
+		f, err := os.Open(envCfg.ClientTLSCert)
+		if err != nil {
+			return nil, awserr.New(ErrCodeLoadClientTLSCert,
+				"failed to open client TLS certificate PEM file", err)
+		}
+		defer f.Close()
+		opts.ClientTLSCert = f
+	}
+	if len(envCfg.ClientTLSKey) != 0 && opts.ClientTLSKey == nil {
+		f, err := os.Open(envCfg.ClientTLSKey)
+		if err != nil {
+			return nil, awserr.New(ErrCodeLoadClientTLSCert,
+				"failed to open client TLS key PEM file", err)
+		}
+		defer f.Close()
+		opts.ClientTLSKey = f
+	}
+
 	return newSession(opts, envCfg, &opts.Config)
 }
 
@@ -303,6 +340,17 @@ func deprecatedNewSession(cfgs ...*aws.Config) *Session {
 
 	initHandlers(s)
 
+	if transportConfigSet(cfg) {
+		if err := mergeHTTPTransportConfig(s.Config); err != nil {
+			// Old session.New expected all errors to be discovered when
+			// a request is made, and would report the errors then.
+			s.Config.Logger.Log("ERROR:", "failed to configure HTTP transport", "Error:", err)
+			s.Handlers.Validate.PushBack(func(r *request.Request) {
+				r.Error = err
+			})
+		}
+	}
+
 	return s
 }
 
@@ -346,6 +394,13 @@ func newSession(opts Options, envCfg envConfig, cfgs ...*aws.Config) (*Session,
 
 	initHandlers(s)
 
+	// Setup HTTP client's transport with the transport config values if set.
+	if transportConfigSet(userCfg) {
+		if err := mergeHTTPTransportConfig(s.Config); err != nil {
+			return nil, err
+		}
+	}
+
 	// Setup HTTP client with custom cert bundle if enabled
 	if opts.CustomCABundle != nil {
 		if err := loadCustomCABundle(s, opts.CustomCABundle); err != nil {
@@ -353,6 +408,13 @@ func newSession(opts Options, envCfg envConfig, cfgs ...*aws.Config) (*Session,
 		}
 	}
 
+	// Setup HTTP client with the client TLS certificate if enabled
+	if opts.ClientTLSCert != nil || opts.ClientTLSKey != nil {
+		if err := loadClientTLSCert(s, opts.ClientTLSCert, opts.ClientTLSKey); err != nil {
+			return nil, err
+		}
+	}
+
 	return s, nil
 }
 
@@ -385,6 +447,56 @@ func loadCustomCABundle(s *Session, bundle io.Reader) error {
 	return nil
 }
 
+// ErrCodeLoadClientTLSCert is the error code returned when the session is
+// unable to load the client TLS certificate.
+const ErrCodeLoadClientTLSCert = "LoadClientTLSCertError"
+
+func loadClientTLSCert(s *Session, certReader, keyReader io.Reader) error {
+	if certReader == nil || keyReader == nil {
+		return awserr.New(ErrCodeLoadClientTLSCert,
+			"client TLS certificate and key must both be provided", nil)
+	}
+
+	var t *http.Transport
+	switch v := s.Config.HTTPClient.Transport.(type) {
+	case *http.Transport:
+		t = v
+	default:
+		if s.Config.HTTPClient.Transport != nil {
+			return awserr.New(ErrCodeLoadClientTLSCert,
+				"unable to load client TLS certificate, HTTPClient's transport unsupported type", nil)
+		}
+	}
+	if t == nil {
+		t = &http.Transport{}
+	}
+
+	certPEM, err := ioutil.ReadAll(certReader)
+	if err != nil {
+		return awserr.New(ErrCodeLoadClientTLSCert,
+			"failed to read client TLS certificate PEM file", err)
+	}
+	keyPEM, err := ioutil.ReadAll(keyReader)
+	if err != nil {
+		return awserr.New(ErrCodeLoadClientTLSCert,
+			"failed to read client TLS key PEM file", err)
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return awserr.New(ErrCodeLoadClientTLSCert,
+			"failed to load client TLS certificate", err)
+	}
+
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	t.TLSClientConfig.Certificates = []tls.Certificate{cert}
+
+	s.Config.HTTPClient.Transport = t
+
+	return nil
+}
+
 func loadCertPool(r io.Reader) (*x509.CertPool, error) {
 	b, err := ioutil.ReadAll(r)
 	if err != nil {
@@ -523,6 +635,11 @@ func initHandlers(s *Session) {
 // and handlers. If any additional configs are provided they will be merged
 // on top of the Session's copied config.
 //
+// The copy keeps the IBM COS settings of the Session, its IBM IAM
+// credentials, and the S3COSEndpointType and S3ServiceInstanceID config
+// values, so that COS clients of the copy resolve the same endpoint type and
+// sign requests with the IBM IAM signer.
+//
 //     // Create a copy of the current Session, configured for the us-west-2 region.
 //     sess.Copy(&aws.Config{Region: aws.String("us-west-2")})
 func (s *Session) Copy(cfgs ...*aws.Config) *Session {
@@ -553,6 +670,17 @@ func (s *Session) clientConfigWithErr(serviceName string, cfgs ...*aws.Config) (
 	var resolved endpoints.ResolvedEndpoint
 	var err error
 
+	if transportConfigSet(cfgs...) {
+		if err = mergeHTTPTransportConfig(s.Config); err != nil {
+			// Prevent requests being made by the client with a transport
+			// that does not reflect the client's config.
+			s.Handlers.Validate.PushBack(func(r *request.Request) {
+				r.Error = err
+			})
+			return client.Config{Config: s.Config, Handlers: s.Handlers}, err
+		}
+	}
+
 	region := aws.StringValue(s.Config.Region)
 
 	if endpoint := aws.StringValue(s.Config.Endpoint); len(endpoint) != 0 {
@@ -564,12 +692,27 @@ func (s *Session) clientConfigWithErr(serviceName string, cfgs ...*aws.Config) (
 			func(opt *endpoints.Options) {
 				opt.DisableSSL = aws.BoolValue(s.Config.DisableSSL)
 				opt.UseDualStack = aws.BoolValue(s.Config.UseDualStack)
+				opt.UseFIPSEndpoint = aws.BoolValue(s.Config.UseFIPSEndpoint)
+				opt.EndpointType = aws.StringValue(s.Config.S3COSEndpointType)
 
 				// Support the condition where the service is modeled but its
 				// endpoint metadata is not available.
 				opt.ResolveUnknownService = true
 			},
 		)
+		if err != nil {
+			// Fail the client's requests with the resolver's error, such
+			// as a region without a FIPS endpoint, or not allowed by the
+			// resolver, instead of a missing endpoint.
+			resolveErr := err
+			s.Handlers.Validate.PushFront(func(r *request.Request) {
+				if len(r.ClientInfo.Endpoint) != 0 {
+					// The endpoint was set after the client was created.
+					return
+				}
+				r.Error = resolveErr
+			})
+		}
 	}
 
 	return client.Config{
@@ -0,0 +1,52 @@
+// +build go1.14
+
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/awstesting"
+)
+
+func TestNewSession_WithDisableHTTP2(t *testing.T) {
+	oldEnv := initSessionTestEnv()
+	defer awstesting.PopEnv(oldEnv)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	cases := []struct {
+		Disable       bool
+		ExpectProtoMj int
+	}{
+		{Disable: true, ExpectProtoMj: 1},
+		{Disable: false, ExpectProtoMj: 2},
+	}
+
+	for i, c := range cases {
+		s, err := NewSession(&aws.Config{
+			HTTPClient:   server.Client(),
+			DisableHTTP2: aws.Bool(c.Disable),
+		})
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+
+		resp, err := s.Config.HTTPClient.Get(server.URL)
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		resp.Body.Close()
+
+		if e, a := c.ExpectProtoMj, resp.ProtoMajor; e != a {
+			t.Errorf("%d, expect HTTP/%d, got HTTP/%d", i, e, a)
+		}
+	}
+}
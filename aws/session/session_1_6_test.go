@@ -0,0 +1,38 @@
+// +build go1.6
+
+package session
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/awstesting"
+)
+
+func TestSessionClientConfig_WithDisableHTTP2(t *testing.T) {
+	oldEnv := initSessionTestEnv()
+	defer awstesting.PopEnv(oldEnv)
+
+	s, err := NewSession(&aws.Config{Region: aws.String("us-south")})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	cfg := s.ClientConfig("s3", &aws.Config{DisableHTTP2: aws.Bool(true)})
+	if cfg.Config.HTTPClient == s.Config.HTTPClient {
+		t.Fatalf("expect client to have its own HTTP client")
+	}
+
+	tr, ok := cfg.Config.HTTPClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expect *http.Transport, got %T", cfg.Config.HTTPClient.Transport)
+	}
+	if tr.TLSNextProto == nil || len(tr.TLSNextProto) != 0 {
+		t.Errorf("expect HTTP/2 to be disabled, got %v", tr.TLSNextProto)
+	}
+
+	if e, a := http.DefaultClient, s.Config.HTTPClient; e != a {
+		t.Errorf("expect session HTTP client not to be modified")
+	}
+}
@@ -0,0 +1,54 @@
+// +build go1.8
+
+package session
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/dnscache"
+	"github.com/aws/aws-sdk-go/awstesting"
+)
+
+func TestNewSession_WithDNSCache(t *testing.T) {
+	oldEnv := initSessionTestEnv()
+	defer awstesting.PopEnv(oldEnv)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	var lookups []string
+	resolver := dnscache.New(time.Minute, func(r *dnscache.Resolver) {
+		r.LookupHost = func(ctx context.Context, host string) ([]string, error) {
+			lookups = append(lookups, host)
+			return []string{"127.0.0.1"}, nil
+		}
+	})
+
+	s, err := NewSession(&aws.Config{DNSCache: resolver})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if s.Config.HTTPClient == http.DefaultClient {
+		t.Fatalf("expect http.DefaultClient not to be modified")
+	}
+
+	u, _ := url.Parse(server.URL)
+	resp, err := s.Config.HTTPClient.Get("http://cos.example:" + u.Port())
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if e, a := []string{"cos.example"}, lookups; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v lookups, got %v", e, a)
+	}
+}
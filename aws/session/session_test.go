@@ -12,10 +12,16 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
 	"github.com/aws/aws-sdk-go/aws/defaults"
+	"github.com/aws/aws-sdk-go/aws/dnscache"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
 	"github.com/aws/aws-sdk-go/awstesting"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/cos"
 )
 
 func TestNewDefaultSession(t *testing.T) {
@@ -426,3 +432,91 @@ func initSessionTestEnv() (oldEnv []string) {
 
 	return oldEnv
 }
+
+func TestNewSession_WithDNSCacheUnsupportedTransport(t *testing.T) {
+	oldEnv := initSessionTestEnv()
+	defer awstesting.PopEnv(oldEnv)
+
+	_, err := NewSession(&aws.Config{
+		HTTPClient: &http.Client{Transport: http.NewFileTransport(http.Dir("."))},
+		DNSCache:   dnscache.New(time.Minute),
+	})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := ErrCodeHTTPTransportConfig, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
+
+func TestSessionCopy_IBMSettings(t *testing.T) {
+	oldEnv := initSessionTestEnv()
+	defer awstesting.PopEnv(oldEnv)
+
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+
+	sess, err := NewSession(&aws.Config{
+		Region:              aws.String("us-south"),
+		S3COSEndpointType:   aws.String("direct"),
+		S3ServiceInstanceID: aws.String("config-instance"),
+		Credentials:         ibmcreds.NewCredentialsClient("apikey", "creds-instance", server.URL),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	svc := cos.New(sess.Copy(&aws.Config{Region: aws.String("eu-de")}))
+	r, _ := svc.ListBucketsRequest(nil)
+	if err := r.Sign(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := "https://s3.direct.eu-de.cloud-object-storage.appdomain.cloud", r.ClientInfo.Endpoint; e != a {
+		t.Errorf("expect %v endpoint, got %v", e, a)
+	}
+	if e, a := "config-instance", r.HTTPRequest.Header.Get("ibm-service-instance-id"); e != a {
+		t.Errorf("expect %v service instance ID, got %v", e, a)
+	}
+	if e, a := "Bearer "+server.Tokens()[0].AccessToken, r.HTTPRequest.Header.Get("Authorization"); e != a {
+		t.Errorf("expect %v authorization, got %v", e, a)
+	}
+}
+
+func TestSessionCopy_IBMSignHandler(t *testing.T) {
+	oldEnv := initSessionTestEnv()
+	defer awstesting.PopEnv(oldEnv)
+
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+
+	sess, err := NewSession(&aws.Config{
+		Region:           aws.String("us-south"),
+		EndpointResolver: defaults.IBMCOSEndpointResolver("private"),
+		Credentials:      ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// Copies overriding other values keep the IBM IAM credentials, and
+	// clients of the copies sign requests with the IBM IAM signer.
+	svc := s3.New(sess.Copy(&aws.Config{Region: aws.String("eu-de")}, &aws.Config{MaxRetries: aws.Int(1)}))
+	r, _ := svc.ListBucketsRequest(nil)
+	if r.Handlers.Sign.SwapNamed(v4.SignRequestHandler) {
+		t.Errorf("expect no V4 sign handler")
+	}
+	if err := r.Sign(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := "https://s3.private.eu-de.cloud-object-storage.appdomain.cloud", r.ClientInfo.Endpoint; e != a {
+		t.Errorf("expect %v endpoint, got %v", e, a)
+	}
+	if e, a := "Bearer "+server.Tokens()[0].AccessToken, r.HTTPRequest.Header.Get("Authorization"); e != a {
+		t.Errorf("expect %v authorization, got %v", e, a)
+	}
+	if e, a := "instanceID", r.HTTPRequest.Header.Get("ibm-service-instance-id"); e != a {
+		t.Errorf("expect %v service instance ID, got %v", e, a)
+	}
+}
@@ -0,0 +1,26 @@
+// +build !go1.8
+
+package session
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/dnscache"
+)
+
+// setTransportDNSCache sets the transport to dial connections with the
+// addresses cached by the resolver. The transport's DialContext, set by the
+// default transport of Go 1.7, is cleared as it takes precedence over Dial.
+func setTransportDNSCache(t *http.Transport, r *dnscache.Resolver) {
+	setTransportField(t, "DialContext", nil)
+	t.Dial = r.Dial
+}
+
+// cloneTLSConfig copies the exported fields of the TLS config, as
+// tls.Config's Clone method is only available as of Go 1.8.
+func cloneTLSConfig(cfg *tls.Config) *tls.Config {
+	c := &tls.Config{}
+	copyExportedFields(c, cfg)
+	return c
+}
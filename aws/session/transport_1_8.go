@@ -0,0 +1,20 @@
+// +build go1.8
+
+package session
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/dnscache"
+)
+
+// setTransportDNSCache sets the transport to dial connections with the
+// addresses cached by the resolver.
+func setTransportDNSCache(t *http.Transport, r *dnscache.Resolver) {
+	t.DialContext = r.DialContext
+}
+
+func cloneTLSConfig(cfg *tls.Config) *tls.Config {
+	return cfg.Clone()
+}
@@ -0,0 +1,318 @@
+package session
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/defaults"
+	"github.com/aws/aws-sdk-go/awstesting"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestNewSession_WithHTTPProxy(t *testing.T) {
+	oldEnv := initSessionTestEnv()
+	defer awstesting.PopEnv(oldEnv)
+
+	var proxyAuth, requestURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxyAuth = r.Header.Get("Proxy-Authorization")
+		requestURL = r.URL.String()
+		w.WriteHeader(200)
+	}))
+	defer proxy.Close()
+
+	s, err := NewSession(&aws.Config{
+		ProxyURL: aws.String("http://user:password@" + proxy.Listener.Addr().String()),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	resp, err := s.Config.HTTPClient.Get("http://bucket.example.com/key")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if e, a := "http://bucket.example.com/key", requestURL; e != a {
+		t.Errorf("expect %v proxied, got %v", e, a)
+	}
+	expectAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:password"))
+	if e, a := expectAuth, proxyAuth; e != a {
+		t.Errorf("expect %v proxy authorization, got %v", e, a)
+	}
+}
+
+func TestNewHTTPClient_WithSOCKS5Proxy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer l.Close()
+
+	var user, password string
+	go serveSOCKS5(l, func(u, p string) {
+		user, password = u, p
+	})
+
+	client, err := NewHTTPClient(&aws.Config{
+		ProxyURL: aws.String("socks5://user:password@" + l.Addr().String()),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if e, a := http.StatusOK, resp.StatusCode; e != a {
+		t.Errorf("expect %d status code, got %d", e, a)
+	}
+	if e, a := "user", user; e != a {
+		t.Errorf("expect %v proxy user, got %v", e, a)
+	}
+	if e, a := "password", password; e != a {
+		t.Errorf("expect %v proxy password, got %v", e, a)
+	}
+}
+
+func TestNewHTTPClient_WithProxyURL(t *testing.T) {
+	cases := []struct {
+		ProxyURL  string
+		ExpectErr bool
+		ExpectNil bool
+	}{
+		{ProxyURL: "http://proxy.example.com:3128"},
+		{ProxyURL: "https://proxy.example.com"},
+		{ProxyURL: "socks5://proxy.example.com:1080"},
+		{ProxyURL: "", ExpectNil: true},
+		{ProxyURL: "ftp://proxy.example.com", ExpectErr: true},
+		{ProxyURL: "proxy.example.com:3128", ExpectErr: true},
+	}
+
+	for i, c := range cases {
+		client, err := NewHTTPClient(&aws.Config{ProxyURL: aws.String(c.ProxyURL)})
+		if c.ExpectErr {
+			if err == nil {
+				t.Fatalf("%d, expect error, got none", i)
+			}
+			if e, a := ErrCodeHTTPTransportConfig, err.(awserr.Error).Code(); e != a {
+				t.Errorf("%d, expect %v error code, got %v", i, e, a)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+
+		tr := client.Transport.(*http.Transport)
+		if e, a := c.ExpectNil, tr.Proxy == nil; e != a {
+			t.Errorf("%d, expect nil proxy %t, got %t", i, e, a)
+		}
+	}
+}
+
+// serveSOCKS5 serves a single SOCKS5 connection requiring username and
+// password authentication, reporting the credentials it was sent.
+func serveSOCKS5(l net.Listener, auth func(user, password string)) {
+	conn, err := l.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	// Greeting, selecting username and password authentication.
+	var hdr [2]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(r, make([]byte, hdr[1])); err != nil {
+		return
+	}
+	conn.Write([]byte{5, 2})
+
+	// Username and password authentication.
+	readString := func() string {
+		n, _ := r.ReadByte()
+		b := make([]byte, n)
+		io.ReadFull(r, b)
+		return string(b)
+	}
+	r.ReadByte()
+	user := readString()
+	password := readString()
+	auth(user, password)
+	conn.Write([]byte{1, 0})
+
+	// CONNECT request.
+	var req [4]byte
+	if _, err := io.ReadFull(r, req[:]); err != nil {
+		return
+	}
+	var host string
+	switch req[3] {
+	case 1:
+		ip := make([]byte, 4)
+		io.ReadFull(r, ip)
+		host = net.IP(ip).String()
+	case 3:
+		host = readString()
+	default:
+		return
+	}
+	var port uint16
+	binary.Read(r, binary.BigEndian, &port)
+
+	target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+	if err != nil {
+		conn.Write([]byte{5, 1, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer target.Close()
+	conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+
+	go io.Copy(target, r)
+	io.Copy(conn, target)
+}
+
+func TestNewSession_WithHostOverride(t *testing.T) {
+	oldEnv := initSessionTestEnv()
+	defer awstesting.PopEnv(oldEnv)
+
+	var host, serverName string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+		serverName = r.TLS.ServerName
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	// The test server's certificate is valid for example.com, not the
+	// address dialed.
+	s, err := NewSession(&aws.Config{
+		HTTPClient:       awstesting.NewTLSClient(server),
+		Endpoint:         aws.String(server.URL),
+		HostOverride:     aws.String("example.com:8443"),
+		Region:           aws.String("us-south"),
+		Credentials:      credentials.AnonymousCredentials,
+		S3ForcePathStyle: aws.Bool(true),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	svc := s3.New(s)
+	if _, err := svc.HeadBucket(&s3.HeadBucketInput{Bucket: aws.String("bucket")}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := "example.com:8443", host; e != a {
+		t.Errorf("expect %v host header, got %v", e, a)
+	}
+	if e, a := "example.com", serverName; e != a {
+		t.Errorf("expect %v server name, got %v", e, a)
+	}
+}
+
+func TestNewHTTPClient_WithFIPSEndpoint(t *testing.T) {
+	client, err := NewHTTPClient(&aws.Config{UseFIPSEndpoint: aws.Bool(true)})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	tr, ok := client.Transport.(*http.Transport)
+	if !ok || tr.TLSClientConfig == nil {
+		t.Fatalf("expect transport with TLS config, got %v", client.Transport)
+	}
+	cfg := tr.TLSClientConfig
+	if e, a := uint16(tls.VersionTLS12), cfg.MinVersion; e != a {
+		t.Errorf("expect %v min version, got %v", e, a)
+	}
+	if e, a := uint16(tls.VersionTLS12), cfg.MaxVersion; e != a {
+		t.Errorf("expect %v max version, got %v", e, a)
+	}
+	if e, a := len(fipsCipherSuites), len(cfg.CipherSuites); e != a {
+		t.Errorf("expect %v cipher suites, got %v", e, a)
+	}
+
+	// The default client's transport is not modified.
+	if dt, ok := http.DefaultTransport.(*http.Transport); ok && dt.TLSClientConfig != nil &&
+		len(dt.TLSClientConfig.CipherSuites) != 0 {
+		t.Errorf("expect default transport not to be modified")
+	}
+}
+
+func TestNewSession_WithFIPSEndpointNotAvailable(t *testing.T) {
+	oldEnv := initSessionTestEnv()
+	defer awstesting.PopEnv(oldEnv)
+
+	s, err := NewSession(&aws.Config{
+		EndpointResolver: defaults.IBMCOSEndpointResolver(""),
+		UseFIPSEndpoint:  aws.Bool(true),
+		Region:           aws.String("eu-de"),
+		Credentials:      credentials.AnonymousCredentials,
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	svc := s3.New(s)
+	_, err = svc.HeadBucket(&s3.HeadBucketInput{Bucket: aws.String("bucket")})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := defaults.ErrCodeIBMCOSFIPSEndpointNotAvailable, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
+
+func TestCopyTransport(t *testing.T) {
+	orig := &http.Transport{
+		MaxIdleConnsPerHost: 7,
+		DisableCompression:  true,
+		TLSClientConfig:     &tls.Config{ServerName: "cos.example"},
+	}
+
+	c := copyTransport(orig)
+	if e, a := 7, c.MaxIdleConnsPerHost; e != a {
+		t.Errorf("expect %v idle conns per host, got %v", e, a)
+	}
+	if !c.DisableCompression {
+		t.Errorf("expect compression disabled")
+	}
+	if c.TLSClientConfig == orig.TLSClientConfig {
+		t.Fatalf("expect TLS config to be cloned")
+	}
+	if e, a := "cos.example", c.TLSClientConfig.ServerName; e != a {
+		t.Errorf("expect %v server name, got %v", e, a)
+	}
+
+	setTransportField(c, "NotAField", true)
+	setTransportField(c, "TLSClientConfig", nil)
+	if c.TLSClientConfig != nil {
+		t.Errorf("expect TLS config cleared, got %v", c.TLSClientConfig)
+	}
+	if orig.TLSClientConfig == nil {
+		t.Errorf("expect original transport not modified")
+	}
+}
@@ -4,10 +4,17 @@ package ibm
 import (
 	"net/http"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
 	"github.com/aws/aws-sdk-go/aws/request"
 )
 
+// ErrCodePresignNotSupported is the error code returned when a request is
+// presigned with IBM IAM credentials. Bearer tokens cannot be carried by
+// presigned URLs, requests must be presigned with HMAC credentials.
+const ErrCodePresignNotSupported = "PresignNotSupported"
+
 // Signer applies IBM IAM signing to given request.
 type Signer struct {
 	// The authentication credentials the request will be signed against.
@@ -28,16 +35,36 @@ func NewSigner(credentials *credentials.Credentials) *Signer {
 
 // Sign signs IBM IAM requests.
 func (ibm Signer) Sign(r *http.Request, op *request.Operation) error {
-	creds, err := ibm.Credentials.Get()
+	return ibm.SignWithContext(aws.BackgroundContext(), r, op)
+}
+
+// SignWithContext signs IBM IAM requests the same as Sign, retrieving the
+// credentials with the context if they need to be refreshed. The IAM token
+// request is canceled with the context, and bounded by its deadline.
+//
+// The credentials are retrieved for the host of the request, so that IBM IAM
+// credentials without an IAM endpoint request tokens from the private IAM
+// endpoint for private and direct COS endpoints.
+func (ibm Signer) SignWithContext(ctx aws.Context, r *http.Request, op *request.Operation) error {
+	creds, err := ibm.Credentials.GetWithContext(ibmcreds.WithServiceEndpoint(ctx, r.URL.Host))
 	if err != nil {
 		return err
 	}
 
-	r.Header.Add("Authorization", "Bearer "+creds.SessionToken)
+	r.Header.Set("Authorization", "Bearer "+creds.SessionToken)
+	setServiceInstanceID(r.Header, op, creds.ServiceInstanceID)
+	return nil
+}
+
+// setServiceInstanceID sets the service instance ID header of ListBuckets and
+// CreateBucket requests. The service instance ID may have been set for the
+// request already, or by a previous attempt of the request.
+func setServiceInstanceID(h http.Header, op *request.Operation, id string) {
 	if op.Name == "ListBuckets" || op.Name == "CreateBucket" {
-		r.Header.Add("ibm-service-instance-id", creds.ServiceInstanceID)
+		if h.Get("ibm-service-instance-id") == "" {
+			h.Set("ibm-service-instance-id", id)
+		}
 	}
-	return nil
 }
 
 // SignRequestHandler is a named request handler the SDK will use to sign
@@ -46,13 +73,24 @@ var SignRequestHandler = request.NamedHandler{
 	Name: "ibm.SignRequestHandler", Fn: SignRequest,
 }
 
-// SignRequest signs IBM IAM requests.
+// SignRequest signs IBM IAM requests. Requests with credentials which are not
+// IBM IAM credentials, such as HMAC credentials set for the request with
+// request.WithCredentials, are signed with the V4 signature. Presigning a
+// request with IBM IAM credentials fails with a PresignNotSupported error.
+// All requests are signed with the V4 signature if the S3EmulatorMode config
+// option is enabled.
+//
+// The credentials are retrieved with the request's context, so the IAM token
+// request of expired credentials is canceled with the request, and counts
+// against its deadline. ListBuckets and CreateBucket requests are sent with
+// the S3ServiceInstanceID of the request's config, if set, instead of the
+// service instance ID of the credentials.
+//
+// SignRequest runs the Sign phase of the Stack of NewStack. Use a Stack to
+// compose other middleware with the IBM signing path.
 func SignRequest(req *request.Request) {
-	ibm := NewSigner(req.Config.Credentials)
-
-	err := ibm.Sign(req.HTTPRequest, req.Operation)
-	if err != nil {
-		req.Error = err
-		return
-	}
+	signRequest(req)
 }
+
+// signRequest runs the Sign phase of the default stack.
+var signRequest = phaseHandler(&defaultStack.Sign, SignPhase, nil)
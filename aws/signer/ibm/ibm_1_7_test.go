@@ -0,0 +1,48 @@
+// +build go1.7
+
+package ibm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+)
+
+func TestSignRequest_ContextDeadline(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+	server.SetLatency(500 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	creds := ibmcreds.NewCredentialsClient("apikey", "instanceID", server.URL)
+	r := newTestRequest(creds, "GetObject")
+	r.SetContext(ctx)
+
+	start := time.Now()
+	SignRequest(r)
+	if r.Error == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if elapsed := time.Since(start); elapsed >= 500*time.Millisecond {
+		t.Errorf("expect token request to be canceled at the deadline, took %v", elapsed)
+	}
+	if e, a := "", r.HTTPRequest.Header.Get("Authorization"); e != a {
+		t.Errorf("expect no authorization, got %v", a)
+	}
+
+	// The credentials are retrieved once the request's context allows it.
+	server.SetLatency(0)
+	r = newTestRequest(creds, "GetObject")
+	SignRequest(r)
+	if r.Error != nil {
+		t.Fatalf("expect no error, got %v", r.Error)
+	}
+	if e, a := 1, len(server.Tokens()); e != a {
+		t.Errorf("expect %v token issued, got %v", e, a)
+	}
+}
@@ -0,0 +1,131 @@
+package ibm
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/credentialstest"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds/ibmcredstest"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+)
+
+func newTestRequest(creds *credentials.Credentials, opName string) *request.Request {
+	cfg := unit.Session.Config.Copy(&aws.Config{Credentials: creds})
+	c := client.New(*cfg, metadata.ClientInfo{
+		Endpoint:      "https://s3.us-south.cloud-object-storage.appdomain.cloud",
+		SigningName:   "s3",
+		SigningRegion: "us-south",
+	}, request.Handlers{})
+	return c.NewRequest(&request.Operation{Name: opName, HTTPMethod: "GET", HTTPPath: "/"}, nil, nil)
+}
+
+func TestSignRequest(t *testing.T) {
+	creds := credentials.NewTypedCredentials(&credentialstest.MockProvider{Value: credentials.Value{
+		SessionToken:      "token",
+		ServiceInstanceID: "instanceID",
+	}}, "ibm-iam")
+
+	r := newTestRequest(creds, "ListBuckets")
+	SignRequest(r)
+	if r.Error != nil {
+		t.Fatalf("expect no error, got %v", r.Error)
+	}
+	if e, a := "Bearer token", r.HTTPRequest.Header.Get("Authorization"); e != a {
+		t.Errorf("expect %v authorization, got %v", e, a)
+	}
+	if e, a := "instanceID", r.HTTPRequest.Header.Get("ibm-service-instance-id"); e != a {
+		t.Errorf("expect %v service instance ID, got %v", e, a)
+	}
+}
+
+func TestSignRequest_Resign(t *testing.T) {
+	creds := credentials.NewTypedCredentials(&credentialstest.MockProvider{Value: credentials.Value{
+		SessionToken:      "token",
+		ServiceInstanceID: "instanceID",
+	}}, "ibm-iam")
+
+	r := newTestRequest(creds, "ListBuckets")
+	r.HTTPRequest.Header.Set("ibm-service-instance-id", "otherInstanceID")
+	SignRequest(r)
+	SignRequest(r)
+	if r.Error != nil {
+		t.Fatalf("expect no error, got %v", r.Error)
+	}
+	if e, a := []string{"Bearer token"}, r.HTTPRequest.Header["Authorization"]; len(a) != 1 || e[0] != a[0] {
+		t.Errorf("expect %v authorization, got %v", e, a)
+	}
+	if e, a := []string{"otherInstanceID"}, r.HTTPRequest.Header["Ibm-Service-Instance-Id"]; len(a) != 1 || e[0] != a[0] {
+		t.Errorf("expect %v service instance ID, got %v", e, a)
+	}
+}
+
+func TestSignRequest_HMACCredentials(t *testing.T) {
+	r := newTestRequest(credentials.NewStaticCredentials("AKID", "SECRET", ""), "GetObject")
+	r.HTTPRequest.Header = http.Header{}
+	SignRequest(r)
+	if r.Error != nil {
+		t.Fatalf("expect no error, got %v", r.Error)
+	}
+	if a := r.HTTPRequest.Header.Get("Authorization"); !strings.HasPrefix(a, "AWS4-HMAC-SHA256 Credential=AKID/") {
+		t.Errorf("expect V4 signature, got %v", a)
+	}
+}
+
+func TestSignRequest_Presign(t *testing.T) {
+	creds := credentials.NewTypedCredentials(&credentialstest.MockProvider{Value: credentials.Value{
+		SessionToken: "token",
+	}}, "ibm-iam")
+
+	r := newTestRequest(creds, "GetObject")
+	r.Handlers.Sign.PushBack(SignRequest)
+	_, err := r.Presign(15 * time.Minute)
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := ErrCodePresignNotSupported, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+
+	r = newTestRequest(credentials.NewStaticCredentials("AKID", "SECRET", ""), "GetObject")
+	r.Handlers.Sign.PushBack(SignRequest)
+	u, err := r.Presign(15 * time.Minute)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "X-Amz-Signature=", u; !strings.Contains(a, e) {
+		t.Errorf("expect %v in URL, got %v", e, a)
+	}
+}
+
+func TestSign_PrivateIAMEndpoint(t *testing.T) {
+	server := ibmcredstest.NewServer()
+	defer server.Close()
+
+	creds := ibmcreds.NewCredentialsClient("apikey", "instanceID", "", func(p *ibmcreds.Provider) {
+		p.PrivateIAMEndpoint = server.URL
+	})
+	r, err := http.NewRequest("GET", "https://s3.direct.us-south.cloud-object-storage.appdomain.cloud/bucket", nil)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	err = NewSigner(creds).Sign(r, &request.Operation{Name: "ListObjects"})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 1, len(server.Requests()); e != a {
+		t.Fatalf("expect %v private IAM token request, got %v", e, a)
+	}
+	if e, a := "Bearer "+server.Tokens()[0].AccessToken, r.Header.Get("Authorization"); e != a {
+		t.Errorf("expect %v authorization, got %v", e, a)
+	}
+}
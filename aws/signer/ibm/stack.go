@@ -0,0 +1,402 @@
+package ibm
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+const (
+	// ErrCodeMiddlewareExists is the error code returned when a middleware
+	// is added to a Step which has a middleware with the same ID.
+	ErrCodeMiddlewareExists = "MiddlewareExists"
+
+	// ErrCodeMiddlewareNotFound is the error code returned when a Step has
+	// no middleware with the ID provided.
+	ErrCodeMiddlewareNotFound = "MiddlewareNotFound"
+)
+
+// IDs of the middleware of the Sign phase of the Stack of NewStack.
+const (
+	PresignMiddlewareID           = "ibm.Presign"
+	CredentialsMiddlewareID       = "ibm.Credentials"
+	ServiceInstanceIDMiddlewareID = "ibm.ServiceInstanceID"
+	SignatureMiddlewareID         = "ibm.Signature"
+)
+
+// Names of the request handlers a Stack is applied to request.Handlers with.
+const (
+	stackBuildHandlerName     = "ibm.Stack.Build"
+	stackSendHandlerName      = "ibm.Stack.Send"
+	stackUnmarshalHandlerName = "ibm.Stack.Unmarshal"
+)
+
+// A Phase of a request's path through a Stack.
+type Phase int
+
+// Phases of a Stack, in the order they are run for a request.
+const (
+	// BuildPhase is run once per request, after the request is built.
+	BuildPhase Phase = iota
+
+	// SignPhase is run before every attempt of the request is sent.
+	SignPhase
+
+	// SendPhase wraps sending every attempt of the request.
+	SendPhase
+
+	// UnmarshalPhase wraps unmarshaling the successful response of the
+	// request.
+	UnmarshalPhase
+)
+
+// String returns the name of the phase.
+func (p Phase) String() string {
+	switch p {
+	case BuildPhase:
+		return "Build"
+	case SignPhase:
+		return "Sign"
+	case SendPhase:
+		return "Send"
+	case UnmarshalPhase:
+		return "Unmarshal"
+	default:
+		return fmt.Sprintf("Phase(%d)", int(p))
+	}
+}
+
+// A Context is the state of a request passed through the middleware of a
+// phase.
+type Context struct {
+	// Request being built, signed, sent, or unmarshaled.
+	Request *request.Request
+
+	// Phase the middleware is run in.
+	Phase Phase
+
+	// Credentials the request is signed with. Set in the Sign phase by the
+	// middleware with the CredentialsMiddlewareID, for requests signed with
+	// IBM IAM credentials.
+	Credentials credentials.Value
+}
+
+// Attempt returns the attempt of the request, starting at 1, so that
+// middleware can tell retried attempts apart.
+func (c *Context) Attempt() int {
+	return c.Request.RetryCount + 1
+}
+
+// A Handler handles a request in a phase.
+type Handler interface {
+	Handle(ctx *Context) error
+}
+
+// HandlerFunc is a function which satisfies the Handler interface.
+type HandlerFunc func(ctx *Context) error
+
+// Handle calls the function.
+func (fn HandlerFunc) Handle(ctx *Context) error {
+	return fn(ctx)
+}
+
+// A Middleware is a step of a phase identified by its ID. The middleware
+// continues the phase by calling the next handler, and may act on the
+// request before and after it.
+type Middleware struct {
+	ID string
+	Fn func(ctx *Context, next Handler) error
+}
+
+// RelativePosition is the position a middleware is added at, relative to the
+// middleware of a Step, or to one of them.
+type RelativePosition int
+
+// Relative positions of middleware.
+const (
+	Before RelativePosition = iota
+	After
+)
+
+// A Step is the ordered middleware of a phase of a Stack. The middleware are
+// run in order, the first wrapping all others.
+type Step struct {
+	list []Middleware
+}
+
+// Add adds the middleware to the front of the step if the position is
+// Before, or to the back if After.
+func (s *Step) Add(m Middleware, pos RelativePosition) error {
+	if _, ok := s.index(m.ID); ok {
+		return middlewareExistsError(m.ID)
+	}
+
+	if pos == Before {
+		s.insert(0, m)
+	} else {
+		s.insert(len(s.list), m)
+	}
+	return nil
+}
+
+// Insert inserts the middleware before or after the middleware with the ID
+// relativeTo.
+func (s *Step) Insert(m Middleware, relativeTo string, pos RelativePosition) error {
+	if _, ok := s.index(m.ID); ok {
+		return middlewareExistsError(m.ID)
+	}
+	i, ok := s.index(relativeTo)
+	if !ok {
+		return middlewareNotFoundError(relativeTo)
+	}
+
+	if pos == After {
+		i++
+	}
+	s.insert(i, m)
+	return nil
+}
+
+// Swap replaces the middleware with the ID with the middleware provided.
+func (s *Step) Swap(id string, m Middleware) error {
+	i, ok := s.index(id)
+	if !ok {
+		return middlewareNotFoundError(id)
+	}
+	if j, ok := s.index(m.ID); ok && j != i {
+		return middlewareExistsError(m.ID)
+	}
+
+	s.list[i] = m
+	return nil
+}
+
+// Remove removes the middleware with the ID.
+func (s *Step) Remove(id string) error {
+	i, ok := s.index(id)
+	if !ok {
+		return middlewareNotFoundError(id)
+	}
+
+	s.list = append(s.list[:i:i], s.list[i+1:]...)
+	return nil
+}
+
+// List returns the IDs of the step's middleware, in the order they are run.
+func (s *Step) List() []string {
+	ids := make([]string, 0, len(s.list))
+	for _, m := range s.list {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func (s *Step) index(id string) (int, bool) {
+	for i, m := range s.list {
+		if m.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Step) insert(i int, m Middleware) {
+	list := make([]Middleware, 0, len(s.list)+1)
+	list = append(list, s.list[:i]...)
+	list = append(list, m)
+	s.list = append(list, s.list[i:]...)
+}
+
+// handle runs the step's middleware with the context, ending with the
+// terminal handler.
+func (s *Step) handle(ctx *Context, terminal Handler) error {
+	h := terminal
+	for i := len(s.list) - 1; i >= 0; i-- {
+		m, next := s.list[i], h
+		h = HandlerFunc(func(ctx *Context) error {
+			return m.Fn(ctx, next)
+		})
+	}
+	return h.Handle(ctx)
+}
+
+func middlewareExistsError(id string) error {
+	return awserr.New(ErrCodeMiddlewareExists, "middleware "+id+" already exists", nil)
+}
+
+func middlewareNotFoundError(id string) error {
+	return awserr.New(ErrCodeMiddlewareNotFound, "middleware "+id+" not found", nil)
+}
+
+// A Stack composes the IBM signing path of requests from the middleware of
+// its phases, so that cross-cutting features, such as tracing, tenant
+// headers, or re-signing retried attempts, are ordered relative to the IBM
+// signing middleware by ID, instead of by the order of request handlers.
+//
+// The Stack of NewStack signs requests the same as SignRequest. Apply the
+// stack to the handlers of a client to use it.
+//
+//     stack := ibm.NewStack()
+//     stack.Build.Add(ibm.Middleware{
+//         ID: "TenantHeader",
+//         Fn: func(ctx *ibm.Context, next ibm.Handler) error {
+//             ctx.Request.HTTPRequest.Header.Set("X-Tenant", tenant)
+//             return next.Handle(ctx)
+//         },
+//     }, ibm.After)
+//
+//     svc := cos.New(sess)
+//     stack.Apply(&svc.Handlers)
+type Stack struct {
+	Build     Step
+	Sign      Step
+	Send      Step
+	Unmarshal Step
+}
+
+// NewStack returns a Stack of the IBM signing path of SignRequest. The Sign
+// phase's middleware, in order, are:
+//
+//   * PresignMiddlewareID, failing presigned requests with IBM IAM
+//     credentials,
+//   * CredentialsMiddlewareID, retrieving the IBM IAM credentials of the
+//     request into the Context,
+//   * ServiceInstanceIDMiddlewareID, setting the ibm-service-instance-id
+//     header of ListBuckets and CreateBucket requests,
+//   * SignatureMiddlewareID, setting the bearer token of the request, or
+//     signing it with the V4 signature.
+//
+// Requests with credentials which are not IBM IAM credentials, or with the
+// S3EmulatorMode config option enabled, are only signed with the V4
+// signature by the SignatureMiddlewareID middleware.
+func NewStack() *Stack {
+	s := &Stack{}
+	s.Sign.Add(Middleware{ID: PresignMiddlewareID, Fn: presignMiddleware}, After)
+	s.Sign.Add(Middleware{ID: CredentialsMiddlewareID, Fn: credentialsMiddleware}, After)
+	s.Sign.Add(Middleware{ID: ServiceInstanceIDMiddlewareID, Fn: serviceInstanceIDMiddleware}, After)
+	s.Sign.Add(Middleware{ID: SignatureMiddlewareID, Fn: signatureMiddleware}, After)
+	return s
+}
+
+// Clone returns a copy of the stack, which can be modified without
+// modifying the stack.
+func (s *Stack) Clone() *Stack {
+	return &Stack{
+		Build:     Step{list: append([]Middleware{}, s.Build.list...)},
+		Sign:      Step{list: append([]Middleware{}, s.Sign.list...)},
+		Send:      Step{list: append([]Middleware{}, s.Send.list...)},
+		Unmarshal: Step{list: append([]Middleware{}, s.Unmarshal.list...)},
+	}
+}
+
+// Apply applies a copy of the stack to the request handlers, replacing the
+// IBM and V4 sign handlers with the stack's Sign phase. The Send and
+// Unmarshal phases wrap the handlers of the Send and Unmarshal lists when
+// the stack is applied, handlers added to the lists later are run after
+// them. A stack should be applied to handlers once, and can be modified
+// after it is applied without modifying the handlers.
+func (s *Stack) Apply(h *request.Handlers) {
+	c := s.Clone()
+	saved := h.Copy()
+
+	h.Build.SetBackNamed(request.NamedHandler{
+		Name: stackBuildHandlerName, Fn: phaseHandler(&c.Build, BuildPhase, nil),
+	})
+
+	h.Sign.Remove(v4.SignRequestHandler)
+	h.Sign.SetBackNamed(request.NamedHandler{
+		Name: SignRequestHandler.Name, Fn: phaseHandler(&c.Sign, SignPhase, nil),
+	})
+
+	h.Send.Clear()
+	h.Send.PushBackNamed(request.NamedHandler{
+		Name: stackSendHandlerName, Fn: phaseHandler(&c.Send, SendPhase, saved.Send.Run),
+	})
+
+	h.Unmarshal.Clear()
+	h.Unmarshal.PushBackNamed(request.NamedHandler{
+		Name: stackUnmarshalHandlerName, Fn: phaseHandler(&c.Unmarshal, UnmarshalPhase, saved.Unmarshal.Run),
+	})
+}
+
+// phaseHandler returns a request handler running the step's middleware in
+// the phase, ending with the terminal request handler, if any. The error of
+// the middleware is set as the request's error.
+func phaseHandler(step *Step, phase Phase, terminal func(*request.Request)) func(*request.Request) {
+	return func(r *request.Request) {
+		ctx := &Context{Request: r, Phase: phase}
+		err := step.handle(ctx, HandlerFunc(func(ctx *Context) error {
+			if terminal != nil {
+				terminal(ctx.Request)
+			}
+			return ctx.Request.Error
+		}))
+		if err != nil {
+			r.Error = err
+		}
+	}
+}
+
+// defaultStack is the Stack SignRequest signs requests with.
+var defaultStack = NewStack()
+
+// signsWithV4 returns whether the request is signed with the V4 signature,
+// instead of with IBM IAM credentials.
+func signsWithV4(r *request.Request) bool {
+	if aws.BoolValue(r.Config.S3EmulatorMode) {
+		return true
+	}
+	creds := r.Config.Credentials
+	return creds != nil && creds.GetCredentialsType() != "ibm-iam"
+}
+
+func presignMiddleware(ctx *Context, next Handler) error {
+	if !signsWithV4(ctx.Request) && ctx.Request.ExpireTime != 0 {
+		return awserr.New(ErrCodePresignNotSupported,
+			"requests cannot be presigned with IBM IAM credentials, use HMAC credentials", nil)
+	}
+	return next.Handle(ctx)
+}
+
+func credentialsMiddleware(ctx *Context, next Handler) error {
+	if !signsWithV4(ctx.Request) {
+		r := ctx.Request
+		creds, err := r.Config.Credentials.GetWithContext(
+			ibmcreds.WithServiceEndpoint(r.Context(), r.HTTPRequest.URL.Host))
+		if err != nil {
+			return err
+		}
+		ctx.Credentials = creds
+	}
+	return next.Handle(ctx)
+}
+
+func serviceInstanceIDMiddleware(ctx *Context, next Handler) error {
+	if !signsWithV4(ctx.Request) {
+		r := ctx.Request
+		id := aws.StringValue(r.Config.S3ServiceInstanceID)
+		if len(id) == 0 {
+			id = ctx.Credentials.ServiceInstanceID
+		}
+		setServiceInstanceID(r.HTTPRequest.Header, r.Operation, id)
+	}
+	return next.Handle(ctx)
+}
+
+func signatureMiddleware(ctx *Context, next Handler) error {
+	r := ctx.Request
+	if signsWithV4(r) {
+		v4.SignSDKRequest(r)
+		if r.Error != nil {
+			return r.Error
+		}
+	} else {
+		r.HTTPRequest.Header.Set("Authorization", "Bearer "+ctx.Credentials.SessionToken)
+	}
+	return next.Handle(ctx)
+}
@@ -0,0 +1,147 @@
+package ibm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/credentialstest"
+	"github.com/aws/aws-sdk-go/aws/defaults"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+)
+
+func testMiddleware(id string, calls *[]string) Middleware {
+	return Middleware{ID: id, Fn: func(ctx *Context, next Handler) error {
+		*calls = append(*calls, id)
+		return next.Handle(ctx)
+	}}
+}
+
+func TestStep(t *testing.T) {
+	var calls []string
+	var s Step
+	for _, err := range []error{
+		s.Add(testMiddleware("b", &calls), After),
+		s.Add(testMiddleware("a", &calls), Before),
+		s.Add(testMiddleware("d", &calls), After),
+		s.Insert(testMiddleware("c", &calls), "d", Before),
+		s.Insert(testMiddleware("e", &calls), "d", After),
+		s.Remove("b"),
+		s.Swap("e", testMiddleware("f", &calls)),
+	} {
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+
+	expect := []string{"a", "c", "d", "f"}
+	if e, a := expect, s.List(); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v middleware, got %v", e, a)
+	}
+
+	err := s.handle(&Context{}, HandlerFunc(func(ctx *Context) error {
+		calls = append(calls, "terminal")
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := append(expect, "terminal"), calls; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v calls, got %v", e, a)
+	}
+
+	cases := map[string]struct {
+		Err  error
+		Code string
+	}{
+		"add existing":     {s.Add(testMiddleware("a", &calls), After), ErrCodeMiddlewareExists},
+		"insert existing":  {s.Insert(testMiddleware("a", &calls), "d", After), ErrCodeMiddlewareExists},
+		"insert not found": {s.Insert(testMiddleware("x", &calls), "b", After), ErrCodeMiddlewareNotFound},
+		"swap to existing": {s.Swap("a", testMiddleware("d", &calls)), ErrCodeMiddlewareExists},
+		"remove not found": {s.Remove("b"), ErrCodeMiddlewareNotFound},
+	}
+	for name, c := range cases {
+		if c.Err == nil {
+			t.Errorf("%s, expect error, got none", name)
+			continue
+		}
+		if e, a := c.Code, c.Err.(awserr.Error).Code(); e != a {
+			t.Errorf("%s, expect %v error code, got %v", name, e, a)
+		}
+	}
+}
+
+func TestStackApply(t *testing.T) {
+	var tenants []string
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenants = append(tenants, r.Header.Get("X-Tenant"))
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(500)
+			return
+		}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	var calls []string
+	stack := NewStack()
+	stack.Build.Add(Middleware{ID: "TenantHeader", Fn: func(ctx *Context, next Handler) error {
+		ctx.Request.HTTPRequest.Header.Set("X-Tenant", "tenant")
+		return next.Handle(ctx)
+	}}, After)
+	stack.Sign.Insert(Middleware{ID: "Attempt", Fn: func(ctx *Context, next Handler) error {
+		calls = append(calls, fmt.Sprintf("sign %d, %s", ctx.Attempt(), ctx.Credentials.SessionToken))
+		return next.Handle(ctx)
+	}}, CredentialsMiddlewareID, After)
+	stack.Send.Add(Middleware{ID: "Trace", Fn: func(ctx *Context, next Handler) error {
+		calls = append(calls, fmt.Sprintf("send %d", ctx.Attempt()))
+		err := next.Handle(ctx)
+		calls = append(calls, fmt.Sprintf("sent %d, %d", ctx.Attempt(), ctx.Request.HTTPResponse.StatusCode))
+		return err
+	}}, After)
+	stack.Unmarshal.Add(testMiddleware("Unmarshal", &calls), After)
+
+	creds := credentials.NewTypedCredentials(&credentialstest.MockProvider{Value: credentials.Value{
+		SessionToken: "token",
+	}}, "ibm-iam")
+	cfg := unit.Session.Config.Copy(&aws.Config{Credentials: creds, MaxRetries: aws.Int(1)})
+	c := client.New(*cfg, metadata.ClientInfo{
+		Endpoint:      server.URL,
+		SigningName:   "s3",
+		SigningRegion: "us-south",
+	}, defaults.Handlers())
+	stack.Apply(&c.Handlers)
+
+	// Modifying the stack after it is applied does not modify the client.
+	stack.Sign.Remove(SignatureMiddlewareID)
+
+	r := c.NewRequest(&request.Operation{Name: "HeadBucket", HTTPMethod: "HEAD", HTTPPath: "/"}, nil, nil)
+	if err := r.Send(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := []string{
+		"sign 1, token", "send 1", "sent 1, 500",
+		"sign 2, token", "send 2", "sent 2, 200",
+		"Unmarshal",
+	}
+	if e, a := expect, calls; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v calls, got %v", e, a)
+	}
+	if e, a := []string{"tenant", "tenant"}, tenants; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v tenants, got %v", e, a)
+	}
+	if e, a := "Bearer token", r.HTTPRequest.Header.Get("Authorization"); e != a {
+		t.Errorf("expect %v authorization, got %v", e, a)
+	}
+}
@@ -164,3 +164,29 @@ func TestStandaloneSign_CustomURIEscape(t *testing.T) {
 		t.Errorf("expect %v, got %v", e, a)
 	}
 }
+
+func TestWithRequestSigningDetails(t *testing.T) {
+	svc := s3.New(unit.Session)
+	req, _ := svc.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("my key"),
+	})
+
+	var details []v4.SigningDetails
+	req.ApplyOptions(v4.WithRequestSigningDetails(func(d v4.SigningDetails) {
+		details = append(details, d)
+	}))
+	if err := req.Sign(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := 1, len(details); e != a {
+		t.Fatalf("expect %v details, got %v", e, a)
+	}
+	if e, a := "GET\n/my%20key\n", details[0].CanonicalRequest; !strings.HasPrefix(a, e) {
+		t.Errorf("expect canonical request prefix %q, got %q", e, a)
+	}
+	if e, a := details[0].Signature, req.HTTPRequest.Header.Get("Authorization"); !strings.HasSuffix(a, e) {
+		t.Errorf("expect %v signature, got %v", e, a)
+	}
+}
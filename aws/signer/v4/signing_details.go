@@ -0,0 +1,83 @@
+package v4
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// SigningDetails are the components of a V4 signature computed by the
+// Signer. Comparing them with the canonical request and string to sign the
+// service computed, such as the ones included in the message of a
+// SignatureDoesNotMatch error, shows which part of a request was signed
+// differently.
+type SigningDetails struct {
+	// CanonicalRequest is the canonical form of the request which was
+	// signed, its method, path, query, headers, signed headers, and payload
+	// hash separated by newlines.
+	CanonicalRequest string
+
+	// StringToSign is the string the signature is computed for, the
+	// algorithm, time, credential scope, and the hash of the canonical
+	// request separated by newlines.
+	StringToSign string
+
+	// CredentialScope is the date, region, service, and terminator the
+	// signing key is derived for, such as
+	// "20060102/us-south/s3/aws4_request".
+	CredentialScope string
+
+	// SignedHeaders are the names of the headers which were signed, in
+	// lower case and separated by semicolons.
+	SignedHeaders string
+
+	// PayloadHash is the hex encoded SHA256 hash of the body which was
+	// signed, or UNSIGNED-PAYLOAD.
+	PayloadHash string
+
+	// Signature is the hex encoded signature of the request.
+	Signature string
+}
+
+// String returns the details in the format they are logged in by Signers
+// with the aws.LogDebugWithSigning log level.
+func (d SigningDetails) String() string {
+	return fmt.Sprintf(logSignInfoMsg, d.CanonicalRequest, d.StringToSign, "")
+}
+
+// WithSigningDetails will set the SigningDetailsHandler of the signer to the
+// function provided, called with the details of each signature the signer
+// computes.
+func WithSigningDetails(fn func(SigningDetails)) func(*Signer) {
+	return func(v4 *Signer) {
+		v4.SigningDetailsHandler = fn
+	}
+}
+
+// WithRequestSigningDetails is a request option which will call the
+// function provided with the details of the request's V4 signature each time
+// the request is signed, such as each time it is retried. The request must
+// be signed with SignRequestHandler, as requests of clients configured with
+// HMAC credentials are.
+//
+//     _, err := svc.GetObjectWithContext(ctx, params,
+//         v4.WithRequestSigningDetails(func(d v4.SigningDetails) {
+//             log.Println(d)
+//         }),
+//     )
+func WithRequestSigningDetails(fn func(SigningDetails)) request.Option {
+	return func(r *request.Request) {
+		r.Handlers.Sign.SwapNamed(BuildNamedHandler(SignRequestHandler.Name, WithSigningDetails(fn)))
+	}
+}
+
+func (ctx *signingCtx) signingDetails() SigningDetails {
+	return SigningDetails{
+		CanonicalRequest: ctx.canonicalString,
+		StringToSign:     ctx.stringToSign,
+		CredentialScope:  ctx.credentialString,
+		SignedHeaders:    ctx.signedHeaders,
+		PayloadHash:      ctx.bodyDigest,
+		Signature:        ctx.signature,
+	}
+}
@@ -0,0 +1,48 @@
+package v4
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSigningDetails(t *testing.T) {
+	req, body := buildRequest("dynamodb", "us-east-1", "{}")
+
+	var details []SigningDetails
+	signer := buildSigner()
+	WithSigningDetails(func(d SigningDetails) {
+		details = append(details, d)
+	})(&signer)
+	signer.Sign(req, body, "dynamodb", "us-east-1", time.Unix(0, 0))
+
+	if e, a := 1, len(details); e != a {
+		t.Fatalf("expect %v details, got %v", e, a)
+	}
+	d := details[0]
+
+	expectAuth := "AWS4-HMAC-SHA256 Credential=AKID/" + d.CredentialScope +
+		", SignedHeaders=" + d.SignedHeaders + ", Signature=" + d.Signature
+	if e, a := expectAuth, req.Header.Get("Authorization"); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := "19700101/us-east-1/dynamodb/aws4_request", d.CredentialScope; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if !strings.HasPrefix(d.CanonicalRequest, "POST\n") || !strings.HasSuffix(d.CanonicalRequest, "\n"+d.PayloadHash) {
+		t.Errorf("expect canonical request of POST with payload hash, got %v", d.CanonicalRequest)
+	}
+
+	sum := sha256.Sum256([]byte(d.CanonicalRequest))
+	expectStringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256", "19700101T000000Z", d.CredentialScope, hex.EncodeToString(sum[:]),
+	}, "\n")
+	if e, a := expectStringToSign, d.StringToSign; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if !strings.Contains(d.String(), d.StringToSign) {
+		t.Errorf("expect string to contain string to sign, got %v", d.String())
+	}
+}
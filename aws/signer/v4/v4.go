@@ -197,6 +197,11 @@ type Signer struct {
 	// UnsignedPayload will prevent signing of the payload. This will only
 	// work for services that have support for this.
 	UnsignedPayload bool
+
+	// SigningDetailsHandler, if set, is called with the canonical request,
+	// string to sign, and signature of each request the signer signs, to
+	// diagnose signatures which do not match the service's.
+	SigningDetailsHandler func(SigningDetails)
 }
 
 // NewSigner returns a Signer pointer configured with the credentials and optional
@@ -359,6 +364,9 @@ func (v4 Signer) signWithBody(r *http.Request, body io.ReadSeeker, service, regi
 	if v4.Debug.Matches(aws.LogDebugWithSigning) {
 		v4.logSigningInfo(ctx)
 	}
+	if v4.SigningDetailsHandler != nil {
+		v4.SigningDetailsHandler(ctx.signingDetails())
+	}
 
 	return ctx.SignedHeaderVals, nil
 }
@@ -1,9 +1,12 @@
 package awstesting
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"time"
@@ -49,6 +52,23 @@ func CreateTLSServer(cert, key string, mux *http.ServeMux) (string, error) {
 	return "https://" + addr, nil
 }
 
+// NewTLSClient returns an HTTP client trusting the certificate of the started
+// TLS test server, as httptest.Server's Client method does as of Go 1.9.
+func NewTLSClient(server *httptest.Server) *http.Client {
+	cert, err := x509.ParseCertificate(server.TLS.Certificates[0].Certificate[0])
+	if err != nil {
+		panic(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		},
+	}
+}
+
 // CreateTLSBundleFiles returns the temporary filenames for the certificate
 // key, and CA PEM content. These files should be deleted when no longer
 // needed. CleanupTLSBundleFiles can be used for this cleanup.
@@ -0,0 +1,288 @@
+// Package cosinteg provides a harness for integration tests of IBM Cloud
+// Object Storage (COS) behavior against a live COS instance.
+//
+// The live tests are built with the "integration" build tag, and skipped
+// unless the instance is configured with the environment variables:
+//
+//     IBM_COS_TEST_API_KEY               API key of the test identity
+//     IBM_COS_TEST_SERVICE_INSTANCE_ID   ID of the COS service instance
+//     IBM_COS_TEST_REGION                region of the test buckets, e.g. us-south
+//     IBM_COS_TEST_ENDPOINT              optional COS endpoint to use instead
+//     IBM_COS_TEST_IAM_ENDPOINT          optional IAM endpoint to use instead
+//
+// Test buckets are named with the BucketPrefix, and deleted with their
+// objects by the cleanup of NewBucket. Buckets left behind by interrupted
+// tests can be deleted with the awstesting/cmd/bucket_cleanup command.
+//
+//     func TestPutObject(t *testing.T) {
+//         svc := cosinteg.NewClient(t)
+//         bucket, cleanup := cosinteg.NewBucket(t, svc)
+//         defer cleanup()
+//
+//         rec := cosinteg.Record(svc.Client)
+//         ...
+//         cosinteg.AssertIBMSigned(t, rec.Last("PutObject"))
+//     }
+package cosinteg
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/cos"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// Environment variables configuring the live COS instance.
+const (
+	EnvAPIKey            = "IBM_COS_TEST_API_KEY"
+	EnvServiceInstanceID = "IBM_COS_TEST_SERVICE_INSTANCE_ID"
+	EnvRegion            = "IBM_COS_TEST_REGION"
+	EnvEndpoint          = "IBM_COS_TEST_ENDPOINT"
+	EnvIAMEndpoint       = "IBM_COS_TEST_IAM_ENDPOINT"
+)
+
+// BucketPrefix is the prefix of the names of the buckets created by
+// NewBucket.
+const BucketPrefix = "ibm-cos-sdk-go-integration"
+
+// Config is the configuration of the live COS instance tests run against.
+type Config struct {
+	APIKey            string
+	ServiceInstanceID string
+	Region            string
+	Endpoint          string
+	IAMEndpoint       string
+}
+
+// ConfigFromEnv returns the configuration of the environment variables, and
+// whether the live COS instance is configured by them. The API key, service
+// instance ID, and region must be set.
+func ConfigFromEnv() (Config, bool) {
+	cfg := Config{
+		APIKey:            os.Getenv(EnvAPIKey),
+		ServiceInstanceID: os.Getenv(EnvServiceInstanceID),
+		Region:            os.Getenv(EnvRegion),
+		Endpoint:          os.Getenv(EnvEndpoint),
+		IAMEndpoint:       os.Getenv(EnvIAMEndpoint),
+	}
+
+	ok := len(cfg.APIKey) != 0 && len(cfg.ServiceInstanceID) != 0 && len(cfg.Region) != 0
+	return cfg, ok
+}
+
+// SkipUnlessConfigured skips the test if the live COS instance is not
+// configured by the environment variables, returning the configuration
+// otherwise.
+func SkipUnlessConfigured(t *testing.T) Config {
+	cfg, ok := ConfigFromEnv()
+	if !ok {
+		t.Skipf("live COS tests require %s, %s, and %s to be set",
+			EnvAPIKey, EnvServiceInstanceID, EnvRegion)
+	}
+	return cfg
+}
+
+// NewSession returns a session for the live COS instance of the
+// configuration, with IBM IAM credentials of its API key. Debug logging is
+// enabled by the DEBUG environment variable, the same as the integration
+// package.
+func NewSession(cfg Config) (*session.Session, error) {
+	awsCfg := &aws.Config{
+		Region:      aws.String(cfg.Region),
+		Credentials: ibmcreds.NewCredentialsClient(cfg.APIKey, cfg.ServiceInstanceID, cfg.IAMEndpoint),
+	}
+	if len(cfg.Endpoint) != 0 {
+		awsCfg.Endpoint = aws.String(cfg.Endpoint)
+	}
+	if os.Getenv("DEBUG") != "" {
+		awsCfg.LogLevel = aws.LogLevel(aws.LogDebug)
+	}
+
+	return session.NewSession(awsCfg)
+}
+
+// NewClient returns a COS client of the live COS instance configured by the
+// environment variables, skipping the test if it is not configured.
+func NewClient(t *testing.T) *s3.S3 {
+	cfg := SkipUnlessConfigured(t)
+
+	sess, err := NewSession(cfg)
+	if err != nil {
+		t.Fatalf("failed to create session, %v", err)
+	}
+	return cos.New(sess)
+}
+
+// UniqueBucketName returns a unique bucket name with the BucketPrefix.
+func UniqueBucketName() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return fmt.Sprintf("%s-%d-%x", BucketPrefix, time.Now().Unix(), b)
+}
+
+// NewBucket creates a bucket with a unique name, waiting until it exists,
+// and returns its name with a cleanup function deleting the bucket and its
+// objects. The test fails if the bucket cannot be created.
+func NewBucket(t *testing.T, svc s3iface.S3API) (string, func()) {
+	bucket := UniqueBucketName()
+
+	_, err := svc.CreateBucket(&s3.CreateBucketInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		t.Fatalf("failed to create bucket %s, %v", bucket, err)
+	}
+	err = svc.WaitUntilBucketExists(&s3.HeadBucketInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		t.Fatalf("failed to wait for bucket %s, %v", bucket, err)
+	}
+
+	return bucket, func() {
+		if err := DeleteBucket(svc, bucket); err != nil {
+			t.Errorf("failed to delete bucket %s, %v", bucket, err)
+		}
+	}
+}
+
+// DeleteBucket deletes the bucket, after deleting its objects and aborting
+// its multipart uploads.
+func DeleteBucket(svc s3iface.S3API, bucket string) error {
+	var errs []string
+	err := svc.ListObjectsPages(&s3.ListObjectsInput{Bucket: aws.String(bucket)},
+		func(page *s3.ListObjectsOutput, lastPage bool) bool {
+			for _, o := range page.Contents {
+				_, err := svc.DeleteObject(&s3.DeleteObjectInput{Bucket: aws.String(bucket), Key: o.Key})
+				if err != nil {
+					errs = append(errs, fmt.Sprintf("delete object %s, %v", aws.StringValue(o.Key), err))
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return fmt.Errorf("failed to list objects, %v", err)
+	}
+
+	err = svc.ListMultipartUploadsPages(&s3.ListMultipartUploadsInput{Bucket: aws.String(bucket)},
+		func(page *s3.ListMultipartUploadsOutput, lastPage bool) bool {
+			for _, u := range page.Uploads {
+				_, err := svc.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+					Bucket:   aws.String(bucket),
+					Key:      u.Key,
+					UploadId: u.UploadId,
+				})
+				if err != nil {
+					errs = append(errs, fmt.Sprintf("abort upload %s, %v", aws.StringValue(u.UploadId), err))
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return fmt.Errorf("failed to list multipart uploads, %v", err)
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("failed to empty bucket, %s", strings.Join(errs, "; "))
+	}
+
+	_, err = svc.DeleteBucket(&s3.DeleteBucketInput{Bucket: aws.String(bucket)})
+	return err
+}
+
+// An Exchange is the headers of a request sent by a client, and of the
+// response received.
+type Exchange struct {
+	Operation      string
+	RequestHeader  http.Header
+	ResponseHeader http.Header
+}
+
+// A Recorder records the Exchanges of a client's requests.
+type Recorder struct {
+	mu        sync.Mutex
+	exchanges []Exchange
+}
+
+// Record returns a Recorder of the requests of the client, recorded when
+// they complete.
+func Record(c *client.Client) *Recorder {
+	rec := &Recorder{}
+	c.Handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "cosinteg.Recorder", Fn: rec.record,
+	})
+	return rec
+}
+
+func (rec *Recorder) record(r *request.Request) {
+	e := Exchange{Operation: r.Operation.Name}
+	if r.HTTPRequest != nil {
+		e.RequestHeader = r.HTTPRequest.Header
+	}
+	if r.HTTPResponse != nil {
+		e.ResponseHeader = r.HTTPResponse.Header
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.exchanges = append(rec.exchanges, e)
+}
+
+// Exchanges returns the recorded Exchanges, in the order the requests
+// completed.
+func (rec *Recorder) Exchanges() []Exchange {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]Exchange{}, rec.exchanges...)
+}
+
+// Last returns the last recorded Exchange of the operation, or an Exchange
+// without headers if the operation was not recorded.
+func (rec *Recorder) Last(operation string) Exchange {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	for i := len(rec.exchanges) - 1; i >= 0; i-- {
+		if rec.exchanges[i].Operation == operation {
+			return rec.exchanges[i]
+		}
+	}
+	return Exchange{Operation: operation}
+}
+
+// AssertHeader asserts the header has the expected value.
+func AssertHeader(t *testing.T, h http.Header, name, expect string) {
+	if a := h.Get(name); expect != a {
+		t.Errorf("expect %v %s header, got %v", expect, name, a)
+	}
+}
+
+// AssertHeaderSet asserts the header is set.
+func AssertHeaderSet(t *testing.T, h http.Header, name string) {
+	if len(h.Get(name)) == 0 {
+		t.Errorf("expect %s header to be set", name)
+	}
+}
+
+// AssertIBMSigned asserts the request of the Exchange was signed with an
+// IBM IAM bearer token, and that ListBuckets and CreateBucket requests were
+// sent with the ibm-service-instance-id header.
+func AssertIBMSigned(t *testing.T, e Exchange) {
+	if e.RequestHeader == nil {
+		t.Errorf("expect %s request to be recorded", e.Operation)
+		return
+	}
+	if a := e.RequestHeader.Get("Authorization"); !strings.HasPrefix(a, "Bearer ") {
+		t.Errorf("expect %s request signed with a bearer token, got %q", e.Operation, a)
+	}
+	if e.Operation == "ListBuckets" || e.Operation == "CreateBucket" {
+		AssertHeaderSet(t, e.RequestHeader, "ibm-service-instance-id")
+	}
+}
@@ -0,0 +1,79 @@
+package cosinteg
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	env := awstesting.StashEnv()
+	defer awstesting.PopEnv(env)
+
+	if _, ok := ConfigFromEnv(); ok {
+		t.Errorf("expect not configured without environment variables")
+	}
+
+	os.Setenv(EnvAPIKey, "apikey")
+	os.Setenv(EnvServiceInstanceID, "instanceID")
+	os.Setenv(EnvRegion, "us-south")
+	cfg, ok := ConfigFromEnv()
+	if !ok {
+		t.Fatalf("expect configured")
+	}
+	if e, a := "us-south", cfg.Region; e != a {
+		t.Errorf("expect %v region, got %v", e, a)
+	}
+}
+
+func TestDeleteBucket(t *testing.T) {
+	svc := s3.New(unit.Session)
+	rec := Record(svc.Client)
+
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		body := ""
+		switch r.Operation.Name {
+		case "ListObjects":
+			body = `<ListBucketResult><IsTruncated>false</IsTruncated><Contents><Key>a</Key></Contents><Contents><Key>b</Key></Contents></ListBucketResult>`
+		case "ListMultipartUploads":
+			body = `<ListMultipartUploadsResult><IsTruncated>false</IsTruncated><Upload><Key>c</Key><UploadId>upload</UploadId></Upload></ListMultipartUploadsResult>`
+		}
+		r.HTTPResponse = &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"X-Amz-Request-Id": []string{"request-id"}},
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		}
+	})
+
+	if err := DeleteBucket(svc, "bucket"); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	var ops []string
+	for _, e := range rec.Exchanges() {
+		ops = append(ops, e.Operation)
+	}
+	expect := []string{"ListObjects", "DeleteObject", "DeleteObject",
+		"ListMultipartUploads", "AbortMultipartUpload", "DeleteBucket"}
+	if e, a := len(expect), len(ops); e != a {
+		t.Fatalf("expect %v requests, got %v", expect, ops)
+	}
+	for i := range expect {
+		if e, a := expect[i], ops[i]; e != a {
+			t.Errorf("expect %v request %d, got %v", e, i, a)
+		}
+	}
+
+	AssertHeader(t, rec.Last("DeleteBucket").ResponseHeader, "X-Amz-Request-Id", "request-id")
+	if e := rec.Last("GetObject"); e.RequestHeader != nil {
+		t.Errorf("expect no GetObject exchange, got %v", e)
+	}
+}
@@ -0,0 +1,65 @@
+// +build integration
+
+// Package cos_test runs integration tests of IBM COS behavior against a live
+// COS instance, see the cosinteg package for its configuration.
+package cos_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/awstesting/integration/cosinteg"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestListBuckets(t *testing.T) {
+	svc := cosinteg.NewClient(t)
+	rec := cosinteg.Record(svc.Client)
+
+	if _, err := svc.ListBuckets(&s3.ListBucketsInput{}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	cosinteg.AssertIBMSigned(t, rec.Last("ListBuckets"))
+}
+
+func TestObjectRoundTrip(t *testing.T) {
+	svc := cosinteg.NewClient(t)
+	bucket, cleanup := cosinteg.NewBucket(t, svc)
+	defer cleanup()
+	rec := cosinteg.Record(svc.Client)
+
+	_, err := svc.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String("key name"),
+		Body:   bytes.NewReader([]byte("hello world")),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	resp, err := svc.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String("key name"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "hello world", string(b); e != a {
+		t.Errorf("expect %v body, got %v", e, a)
+	}
+
+	for _, op := range []string{"PutObject", "GetObject"} {
+		e := rec.Last(op)
+		cosinteg.AssertIBMSigned(t, e)
+		cosinteg.AssertHeaderSet(t, e.ResponseHeader, "X-Amz-Request-Id")
+	}
+}
@@ -0,0 +1,423 @@
+// Package vcr provides an HTTP transport which records the request and
+// response pairs of integration tests to a fixture file, and replays them
+// deterministically in later test runs. Tests using a replaying Recorder do
+// not need network access, or live credentials.
+//
+// Credentials are redacted from the recorded fixtures. This includes the
+// Authorization and security token headers, the signature and credential
+// query parameters of presigned URLs, the API keys, passcodes, and tokens of
+// IBM IAM token requests, and the access and refresh tokens of IAM token
+// responses.
+//
+//     rec, err := vcr.New("testdata/fixtures/put_object.json", vcr.ModeAuto)
+//     if err != nil {
+//         t.Fatalf("failed to create recorder, %v", err)
+//     }
+//     defer rec.Stop()
+//
+//     sess := session.Must(session.NewSession(&aws.Config{
+//         HTTPClient: rec.Client(),
+//     }))
+//
+// Use ModeRecord, or ModeAuto without an existing fixture, with live
+// credentials to record the fixture. Once recorded, the fixture is replayed.
+package vcr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode is the mode a Recorder operates in.
+type Mode int
+
+const (
+	// ModeReplay replays the interactions of an existing fixture. Requests
+	// without a matching recorded interaction fail.
+	ModeReplay Mode = iota
+
+	// ModeRecord sends requests with the Recorder's Transport, recording the
+	// interactions to the fixture when the Recorder is stopped. An existing
+	// fixture will be overwritten.
+	ModeRecord
+
+	// ModeAuto replays the fixture if it exists, otherwise records it.
+	ModeAuto
+)
+
+// RedactedValue replaces the credentials redacted from recorded interactions.
+const RedactedValue = "REDACTED"
+
+// DefaultRedactedHeaders are the request headers redacted from recorded
+// interactions by default.
+var DefaultRedactedHeaders = []string{
+	"Authorization",
+	"X-Amz-Security-Token",
+}
+
+// An Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// A Request is a recorded HTTP request.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body"`
+}
+
+// A Response is a recorded HTTP response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body"`
+}
+
+// Body is a recorded HTTP body. Bodies which are not valid UTF-8 text are
+// encoded as base64 in the fixture.
+type Body []byte
+
+// MarshalJSON encodes the body as a JSON object with either a text, or
+// base64 field.
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(map[string]string{"text": string(b)})
+	}
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON decodes the body from its JSON encoding.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var v map[string]string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if s, ok := v["base64"]; ok {
+		d, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return err
+		}
+		*b = d
+		return nil
+	}
+
+	*b = Body(v["text"])
+	return nil
+}
+
+// A Recorder is an http.RoundTripper which records, or replays, HTTP
+// interactions. A Recorder is safe to use concurrently, but concurrent
+// requests are recorded in the order they complete.
+type Recorder struct {
+	// Transport is used to send requests when recording. Defaults to
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// RedactHeaders are the request headers whose values are replaced with
+	// RedactedValue in recorded interactions. Defaults to
+	// DefaultRedactedHeaders.
+	RedactHeaders []string
+
+	// Matcher determines if a recorded interaction matches the request being
+	// replayed. Each recorded interaction is replayed at most once, in the
+	// order recorded. Defaults to matching the method, URL path, and query.
+	Matcher func(r *http.Request, recorded Request) bool
+
+	path string
+	mode Mode
+
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+}
+
+// New returns a Recorder for the fixture path in the mode provided. If
+// replaying, the fixture is loaded and an error is returned if it cannot be
+// read. Functional options can be provided to configure the Recorder.
+func New(path string, mode Mode, options ...func(*Recorder)) (*Recorder, error) {
+	if mode == ModeAuto {
+		mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			mode = ModeReplay
+		}
+	}
+
+	r := &Recorder{
+		path: path,
+		mode: mode,
+	}
+	for _, option := range options {
+		option(r)
+	}
+
+	if mode == ModeReplay {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("vcr: failed to read fixture, %v", err)
+		}
+		if err := json.Unmarshal(b, &r.interactions); err != nil {
+			return nil, fmt.Errorf("vcr: failed to decode fixture %s, %v", path, err)
+		}
+		r.replayed = make([]bool, len(r.interactions))
+	}
+
+	return r, nil
+}
+
+// Mode returns the mode the Recorder is operating in. A Recorder created with
+// ModeAuto returns the mode selected.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an http.Client which uses the Recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip records or replays the request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeReplay {
+		return r.replay(req)
+	}
+	return r.record(req)
+}
+
+// Stop writes the recorded interactions to the fixture, if recording.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	b, err := json.MarshalIndent(r.interactions, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("vcr: failed to encode fixture, %v", err)
+	}
+
+	if dir := filepath.Dir(r.path); len(dir) != 0 {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("vcr: failed to create fixture directory, %v", err)
+		}
+	}
+	if err := ioutil.WriteFile(r.path, append(b, '\n'), 0644); err != nil {
+		return fmt.Errorf("vcr: failed to write fixture, %v", err)
+	}
+
+	return nil
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    redactURL(req.URL),
+			Header: r.redactHeader(req.Header),
+			Body:   redactBody(req.Header.Get("Content-Type"), reqBody),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       redactBody(resp.Header.Get("Content-Type"), respBody),
+		},
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	matcher := r.Matcher
+	if matcher == nil {
+		matcher = DefaultMatcher
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.replayed[i] || !matcher(req, interaction.Request) {
+			continue
+		}
+		r.replayed[i] = true
+
+		if req.Body != nil {
+			req.Body.Close()
+		}
+
+		resp := interaction.Response
+		header := http.Header{}
+		for k, v := range resp.Header {
+			header[k] = append([]string{}, v...)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+			StatusCode:    resp.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader(resp.Body)),
+			ContentLength: int64(len(resp.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("vcr: no recorded interaction for %s %s", req.Method, req.URL)
+}
+
+// DefaultMatcher matches requests to recorded requests by their method, URL
+// path, and URL query.
+func DefaultMatcher(r *http.Request, recorded Request) bool {
+	if r.Method != recorded.Method {
+		return false
+	}
+
+	u, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+
+	return r.URL.EscapedPath() == u.EscapedPath() &&
+		redactQuery(r.URL.Query()).Encode() == u.Query().Encode()
+}
+
+func (r *Recorder) redactHeader(h http.Header) http.Header {
+	names := r.RedactHeaders
+	if names == nil {
+		names = DefaultRedactedHeaders
+	}
+
+	redacted := http.Header{}
+	for k, v := range h {
+		redacted[k] = append([]string{}, v...)
+	}
+	for _, name := range names {
+		if _, ok := redacted[http.CanonicalHeaderKey(name)]; ok {
+			redacted.Set(name, RedactedValue)
+		}
+	}
+
+	return redacted
+}
+
+// redactedFormFields are the form fields of IAM token requests which are
+// redacted.
+var redactedFormFields = []string{
+	"apikey", "refresh_token", "password", "passcode",
+	"access_token", "cr_token", "assertion",
+}
+
+// redactedQueryParams are the query parameters of presigned URLs which are
+// redacted.
+var redactedQueryParams = []string{
+	"X-Amz-Signature", "X-Amz-Credential", "X-Amz-Security-Token",
+}
+
+// redactURL returns the URL with the values of its redactedQueryParams
+// replaced with RedactedValue.
+func redactURL(u *url.URL) string {
+	cpy := *u
+	if len(cpy.RawQuery) != 0 {
+		cpy.RawQuery = redactQuery(cpy.Query()).Encode()
+	}
+	return cpy.String()
+}
+
+// redactQuery replaces the values of the redactedQueryParams of the query
+// with RedactedValue.
+func redactQuery(query url.Values) url.Values {
+	for _, param := range redactedQueryParams {
+		if _, ok := query[param]; ok {
+			query.Set(param, RedactedValue)
+		}
+	}
+	return query
+}
+
+// redactedJSONFields are the fields of IAM token responses which are
+// redacted.
+var redactedJSONFields = []string{"access_token", "refresh_token", "delegated_refresh_token"}
+
+func redactBody(contentType string, body []byte) Body {
+	switch {
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return body
+		}
+		for _, field := range redactedFormFields {
+			if _, ok := values[field]; ok {
+				values.Set(field, RedactedValue)
+			}
+		}
+		return Body(values.Encode())
+
+	case strings.HasPrefix(contentType, "application/json"):
+		var v map[string]interface{}
+		if err := json.Unmarshal(body, &v); err != nil {
+			return body
+		}
+		redacted := false
+		for _, field := range redactedJSONFields {
+			if _, ok := v[field]; ok {
+				v[field] = RedactedValue
+				redacted = true
+			}
+		}
+		if !redacted {
+			return body
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return body
+		}
+		return b
+	}
+
+	return body
+}
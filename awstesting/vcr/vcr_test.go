@@ -0,0 +1,216 @@
+package vcr_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/awstesting/vcr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func newTestS3(client *http.Client, endpoint string) *s3.S3 {
+	return s3.New(unit.Session, &aws.Config{
+		Endpoint:         aws.String(endpoint),
+		S3ForcePathStyle: aws.Bool(true),
+		HTTPClient:       client,
+	})
+}
+
+func TestRecordReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vcr")
+	if err != nil {
+		t.Fatalf("failed to create temp dir, %v", err)
+	}
+	defer os.RemoveAll(dir)
+	fixture := filepath.Join(dir, "fixtures", "get_object.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"abc"`)
+		w.Write([]byte{0xff, 0x00, 'o', 'k'})
+	}))
+
+	rec, err := vcr.New(fixture, vcr.ModeAuto)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := vcr.ModeRecord, rec.Mode(); e != a {
+		t.Fatalf("expect %v mode, got %v", e, a)
+	}
+
+	svc := newTestS3(rec.Client(), server.URL)
+	out, err := svc.GetObject(&s3.GetObjectInput{Bucket: aws.String("bucket"), Key: aws.String("key")})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	recorded, _ := ioutil.ReadAll(out.Body)
+	out.Body.Close()
+
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	server.Close()
+
+	b, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("expect fixture to be written, got %v", err)
+	}
+	if strings.Contains(string(b), "AKID") || strings.Contains(string(b), "SESSION") {
+		t.Errorf("expect credentials to be redacted from fixture, got %s", b)
+	}
+
+	rec, err = vcr.New(fixture, vcr.ModeAuto)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := vcr.ModeReplay, rec.Mode(); e != a {
+		t.Fatalf("expect %v mode, got %v", e, a)
+	}
+
+	svc = newTestS3(rec.Client(), server.URL)
+	out, err = svc.GetObject(&s3.GetObjectInput{Bucket: aws.String("bucket"), Key: aws.String("key")})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	replayed, _ := ioutil.ReadAll(out.Body)
+	out.Body.Close()
+
+	if !bytes.Equal(recorded, replayed) {
+		t.Errorf("expect %v body, got %v", recorded, replayed)
+	}
+	if e, a := `"abc"`, aws.StringValue(out.ETag); e != a {
+		t.Errorf("expect %v ETag, got %v", e, a)
+	}
+
+	// Each interaction is only replayed once.
+	_, err = svc.GetObject(&s3.GetObjectInput{Bucket: aws.String("bucket"), Key: aws.String("key")})
+	if err == nil {
+		t.Errorf("expect error for unrecorded request, got none")
+	}
+}
+
+func TestRecordRedactsIAMTokens(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vcr")
+	if err != nil {
+		t.Fatalf("failed to create temp dir, %v", err)
+	}
+	defer os.RemoveAll(dir)
+	fixture := filepath.Join(dir, "token.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"secret-token","expiration":1500000000}`))
+	}))
+	defer server.Close()
+
+	rec, err := vcr.New(fixture, vcr.ModeRecord)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	resp, err := rec.Client().Post(server.URL+"/oidc/token", "application/x-www-form-urlencoded",
+		strings.NewReader("grant_type=urn%3Aibm%3Aparams%3Aoauth%3Agrant-type%3Aapikey&apikey=secret-key"))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	b, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(b), "secret-token") {
+		t.Errorf("expect caller to receive unredacted response, got %s", b)
+	}
+
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	b, _ = ioutil.ReadFile(fixture)
+	for _, secret := range []string{"secret-token", "secret-key"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("expect %v to be redacted from fixture, got %s", secret, b)
+		}
+	}
+	if !strings.Contains(string(b), "expiration") {
+		t.Errorf("expect non-credential fields to be recorded, got %s", b)
+	}
+}
+
+func TestRecordRedactsGrantsAndPresignedURLs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vcr")
+	if err != nil {
+		t.Fatalf("failed to create temp dir, %v", err)
+	}
+	defer os.RemoveAll(dir)
+	fixture := filepath.Join(dir, "grants.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	forms := []string{
+		"grant_type=urn%3Aibm%3Aparams%3Aoauth%3Agrant-type%3Apasscode&passcode=secret-passcode",
+		"grant_type=urn%3Aibm%3Aparams%3Aoauth%3Agrant-type%3Acr-token&cr_token=secret-cr-token",
+		"grant_type=urn%3Aibm%3Aparams%3Aoauth%3Agrant-type%3Aassume&access_token=secret-access-token",
+		"grant_type=urn%3Aietf%3Aparams%3Aoauth%3Agrant-type%3Ajwt-bearer&assertion=secret-assertion",
+	}
+	presigned := server.URL + "/bucket/key?X-Amz-Algorithm=AWS4-HMAC-SHA256" +
+		"&X-Amz-Credential=secret-credential&X-Amz-Signature=secret-signature&X-Amz-Expires=900"
+
+	rec, err := vcr.New(fixture, vcr.ModeRecord)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	for _, form := range forms {
+		resp, err := rec.Client().Post(server.URL+"/identity/token",
+			"application/x-www-form-urlencoded", strings.NewReader(form))
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		resp.Body.Close()
+	}
+	resp, err := rec.Client().Get(presigned)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	resp.Body.Close()
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	b, _ := ioutil.ReadFile(fixture)
+	for _, secret := range []string{
+		"secret-passcode", "secret-cr-token", "secret-access-token", "secret-assertion",
+		"secret-credential", "secret-signature",
+	} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("expect %v to be redacted from fixture, got %s", secret, b)
+		}
+	}
+	if !strings.Contains(string(b), "X-Amz-Expires=900") {
+		t.Errorf("expect non-credential query parameters to be recorded, got %s", b)
+	}
+
+	// Presigned requests match their redacted recording.
+	rec, err = vcr.New(fixture, vcr.ModeReplay)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	resp, err = rec.Client().Get(presigned)
+	if err != nil {
+		t.Fatalf("expect presigned request to be replayed, got %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestReplayMissingFixture(t *testing.T) {
+	if _, err := vcr.New(filepath.Join("testdata", "missing.json"), vcr.ModeReplay); err == nil {
+		t.Errorf("expect error, got none")
+	}
+}
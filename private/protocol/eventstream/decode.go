@@ -0,0 +1,72 @@
+package eventstream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+)
+
+// Decoder provides decoding of an Event Stream messages.
+type Decoder struct {
+	r io.Reader
+}
+
+// NewDecoder initializes and returns a Decoder for decoding event
+// stream messages from the reader provided.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		r: r,
+	}
+}
+
+// Decode attempts to decode a single message from the event stream reader.
+// Will return the event stream message, or error if Decode fails to read
+// the message from the stream. The payload of the message is decoded into
+// payloadBuf, if it is large enough, to reuse the buffer between messages.
+//
+// io.EOF is returned if the stream ends before a message is started, and
+// io.ErrUnexpectedEOF if it ends within a message.
+func (d *Decoder) Decode(payloadBuf []byte) (m Message, err error) {
+	var preludeBuf [preludeLen + preludeCRCLen]byte
+	if n, err := io.ReadFull(d.r, preludeBuf[:]); err != nil {
+		if err == io.ErrUnexpectedEOF || (err == io.EOF && n > 0) {
+			return Message{}, io.ErrUnexpectedEOF
+		}
+		return Message{}, err
+	}
+
+	prelude := messagePrelude{
+		Length:     binary.BigEndian.Uint32(preludeBuf[0:]),
+		HeadersLen: binary.BigEndian.Uint32(preludeBuf[4:]),
+		PreludeCRC: binary.BigEndian.Uint32(preludeBuf[8:]),
+	}
+	if crc32.Checksum(preludeBuf[:preludeLen], crc32IEEETable) != prelude.PreludeCRC {
+		return Message{}, ChecksumError{Part: "prelude"}
+	}
+	if err := prelude.ValidateLens(); err != nil {
+		return Message{}, err
+	}
+
+	rest := make([]byte, prelude.Length-preludeLen-preludeCRCLen)
+	if _, err := io.ReadFull(d.r, rest); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return Message{}, err
+	}
+
+	crc := crc32.Update(crc32.Checksum(preludeBuf[:], crc32IEEETable),
+		crc32IEEETable, rest[:len(rest)-msgCRCLen])
+	if crc != binary.BigEndian.Uint32(rest[len(rest)-msgCRCLen:]) {
+		return Message{}, ChecksumError{Part: "message"}
+	}
+
+	m.Headers, err = decodeHeaders(bytes.NewReader(rest[:prelude.HeadersLen]))
+	if err != nil {
+		return Message{}, err
+	}
+	m.Payload = append(payloadBuf[:0], rest[prelude.HeadersLen:len(rest)-msgCRCLen]...)
+
+	return m, nil
+}
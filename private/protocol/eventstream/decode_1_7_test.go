@@ -0,0 +1,49 @@
+// +build go1.7
+
+package eventstream
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"testing"
+)
+
+func TestDecodeErrors(t *testing.T) {
+	b, _ := hex.DecodeString(recordsMessage)
+
+	cases := map[string]struct {
+		Message []byte
+		Err     error
+	}{
+		"empty": {
+			Message: nil,
+			Err:     io.EOF,
+		},
+		"truncated prelude": {
+			Message: b[:6],
+			Err:     io.ErrUnexpectedEOF,
+		},
+		"truncated message": {
+			Message: b[:len(b)-2],
+			Err:     io.ErrUnexpectedEOF,
+		},
+		"prelude checksum": {
+			Message: append([]byte{0, 0, 0, 0x41}, b[4:]...),
+			Err:     ChecksumError{Part: "prelude"},
+		},
+		"message checksum": {
+			Message: append(append([]byte{}, b[:len(b)-6]...), 'x', '\n', 0xf4, 0xf6, 0x9f, 0x81),
+			Err:     ChecksumError{Part: "message"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewDecoder(bytes.NewReader(c.Message)).Decode(nil)
+			if e, a := c.Err, err; e != a {
+				t.Errorf("expect %v error, got %v", e, a)
+			}
+		})
+	}
+}
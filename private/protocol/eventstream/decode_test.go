@@ -0,0 +1,83 @@
+package eventstream
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// recordsMessage is a Records event message, encoded independently of the
+// Encoder.
+const recordsMessage = "000000400000002c0f09ebc30d3a6d6573736167652d747970650700056576656e74" +
+	"0b3a6576656e742d747970650700075265636f726473612c620af4f69f81"
+
+func TestDecode(t *testing.T) {
+	b, _ := hex.DecodeString(recordsMessage)
+
+	m, err := NewDecoder(bytes.NewReader(b)).Decode(nil)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := Message{
+		Headers: Headers{
+			{Name: ":message-type", Value: StringValue("event")},
+			{Name: ":event-type", Value: StringValue("Records")},
+		},
+		Payload: []byte("a,b\n"),
+	}
+	if e, a := expect, m; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(m); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := recordsMessage, hex.EncodeToString(buf.Bytes()); e != a {
+		t.Errorf("expect %v encoded message, got %v", e, a)
+	}
+}
+
+func TestEncodeDecodeHeaderValues(t *testing.T) {
+	msg := Message{
+		Headers: Headers{
+			{Name: "true", Value: BoolValue(true)},
+			{Name: "false", Value: BoolValue(false)},
+			{Name: "byte", Value: Int8Value(-8)},
+			{Name: "short", Value: Int16Value(-16)},
+			{Name: "integer", Value: Int32Value(-32)},
+			{Name: "long", Value: Int64Value(-64)},
+			{Name: "bytes", Value: BytesValue([]byte{1, 2, 3})},
+			{Name: "string", Value: StringValue("string")},
+			{Name: "timestamp", Value: TimestampValue(time.Unix(1600000000, 123000000).UTC())},
+			{Name: "uuid", Value: UUIDValue{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}},
+		},
+		Payload: []byte("payload"),
+	}
+
+	var buf bytes.Buffer
+	encoder := NewEncoder(&buf)
+	for i := 0; i < 2; i++ {
+		if err := encoder.Encode(msg); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+
+	decoder := NewDecoder(&buf)
+	for i := 0; i < 2; i++ {
+		m, err := decoder.Decode(nil)
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		if e, a := msg, m; !reflect.DeepEqual(e, a) {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+	if _, err := decoder.Decode(nil); err != io.EOF {
+		t.Errorf("expect EOF, got %v", err)
+	}
+}
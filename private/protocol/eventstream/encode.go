@@ -0,0 +1,50 @@
+package eventstream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+)
+
+// Encoder provides EventStream message encoding.
+type Encoder struct {
+	w io.Writer
+}
+
+// NewEncoder initializes and returns an Encoder to encode Event Stream
+// messages to an io.Writer.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w: w,
+	}
+}
+
+// Encode encodes a single EventStream message to the io.Writer the Encoder
+// was created with. An error is returned if writing the message fails.
+func (e *Encoder) Encode(msg Message) error {
+	var headers bytes.Buffer
+	if err := encodeHeaders(&headers, msg.Headers); err != nil {
+		return err
+	}
+
+	prelude := messagePrelude{
+		Length:     uint32(minMsgLen + headers.Len() + len(msg.Payload)),
+		HeadersLen: uint32(headers.Len()),
+	}
+	if err := prelude.ValidateLens(); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(prelude.encode())
+	buf.Write(headers.Bytes())
+	buf.Write(msg.Payload)
+
+	var crc [msgCRCLen]byte
+	binary.BigEndian.PutUint32(crc[:], crc32.Checksum(buf.Bytes(), crc32IEEETable))
+	buf.Write(crc[:])
+
+	_, err := e.w.Write(buf.Bytes())
+	return err
+}
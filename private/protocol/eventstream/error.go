@@ -0,0 +1,28 @@
+package eventstream
+
+import "fmt"
+
+// LengthError is returned when a length of a message is invalid, such as a
+// message longer than the largest message, or a header name longer than
+// 255 bytes.
+type LengthError struct {
+	Part  string
+	Want  int
+	Have  int
+	Value interface{}
+}
+
+func (e LengthError) Error() string {
+	return fmt.Sprintf("%s length invalid, %d/%d, %v",
+		e.Part, e.Want, e.Have, e.Value)
+}
+
+// ChecksumError is returned when the prelude or message checksum of a
+// message does not match the checksum of its bytes.
+type ChecksumError struct {
+	Part string
+}
+
+func (e ChecksumError) Error() string {
+	return fmt.Sprintf("%s checksum mismatch", e.Part)
+}
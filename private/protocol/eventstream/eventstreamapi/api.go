@@ -0,0 +1,156 @@
+// Package eventstreamapi provides the reading of the events of API
+// operations' event streams, such as the S3 SelectObjectContent operation's.
+package eventstreamapi
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/private/protocol/eventstream"
+)
+
+// Headers of event stream messages.
+const (
+	MessageTypeHeader   = `:message-type`   // Identifies type of message.
+	EventTypeHeader     = `:event-type`     // Identifies message event type.
+	ContentTypeHeader   = `:content-type`   // Identifies message payload content type.
+	ExceptionTypeHeader = `:exception-type` // Identifies message exception type.
+	ErrorCodeHeader     = `:error-code`     // Message Error Code.
+	ErrorMessageHeader  = `:error-message`  // Message Error Message.
+)
+
+// Types of event stream messages.
+const (
+	EventMessageType     = `event`
+	ErrorMessageType     = `error`
+	ExceptionMessageType = `exception`
+)
+
+// Unmarshaler provides the interface for unmarshaling a EventStream
+// message into a SDK type. The message's payload is only valid until the
+// next message is read, and must be copied if retained.
+type Unmarshaler interface {
+	UnmarshalEvent(eventstream.Message) error
+}
+
+// UnmarshalerForEventType returns the Unmarshaler of the events of the event
+// type, or nil if the event type is not known.
+type UnmarshalerForEventType func(eventType string) (Unmarshaler, error)
+
+// EventReader provides reading from the EventStream of an reader.
+type EventReader struct {
+	decoder                 *eventstream.Decoder
+	unmarshalerForEventType UnmarshalerForEventType
+
+	payloadBuf []byte
+}
+
+// NewEventReader returns a EventReader built from the reader and unmarshaler
+// provided. Use ReadEvent method to read from the EventStream.
+func NewEventReader(r io.Reader, unmarshalerForEventType UnmarshalerForEventType) *EventReader {
+	return &EventReader{
+		decoder:                 eventstream.NewDecoder(r),
+		unmarshalerForEventType: unmarshalerForEventType,
+		payloadBuf:              make([]byte, 10*1024),
+	}
+}
+
+// ReadEvent attempts to read a message from the EventStream and return the
+// unmarshaled event value that the message is for. Events of event types
+// which are not known are skipped.
+//
+// Error and exception messages of the stream are returned as errors, which
+// satisfy the awserr.Error interface. io.EOF is returned when the stream
+// ends.
+func (r *EventReader) ReadEvent() (event interface{}, err error) {
+	for {
+		msg, err := r.decoder.Decode(r.payloadBuf)
+		if err != nil {
+			return nil, err
+		}
+		// Reclaim payload buffer for next message read. Unmarshalers must
+		// copy the payload of the messages.
+		r.payloadBuf = msg.Payload[0:0]
+
+		typ, err := headerString(msg, MessageTypeHeader)
+		if err != nil {
+			return nil, err
+		}
+
+		switch typ {
+		case EventMessageType:
+			event, err := r.unmarshalEventMessage(msg)
+			if err == nil && event == nil {
+				// Skip events of event types which are not known.
+				continue
+			}
+			return event, err
+		case ExceptionMessageType:
+			code, err := headerString(msg, ExceptionTypeHeader)
+			if err != nil {
+				return nil, err
+			}
+			return nil, messageError{code: code, msg: string(msg.Payload)}
+		case ErrorMessageType:
+			code, err := headerString(msg, ErrorCodeHeader)
+			if err != nil {
+				return nil, err
+			}
+			var message string
+			if v := msg.Headers.Get(ErrorMessageHeader); v != nil {
+				message = v.String()
+			}
+			return nil, messageError{code: code, msg: message}
+		default:
+			return nil, fmt.Errorf("unknown eventstream message type, %v", typ)
+		}
+	}
+}
+
+func (r *EventReader) unmarshalEventMessage(msg eventstream.Message) (event interface{}, err error) {
+	eventType, err := headerString(msg, EventTypeHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	ev, err := r.unmarshalerForEventType(eventType)
+	if err != nil || ev == nil {
+		return nil, err
+	}
+
+	if err := ev.UnmarshalEvent(msg); err != nil {
+		return nil, err
+	}
+
+	return ev, nil
+}
+
+func headerString(msg eventstream.Message, name string) (string, error) {
+	v := msg.Headers.Get(name)
+	if v == nil {
+		return "", fmt.Errorf("eventstream message missing %s header", name)
+	}
+	return v.String(), nil
+}
+
+// messageError is the error of an error or exception message of a stream.
+type messageError struct {
+	code string
+	msg  string
+}
+
+func (e messageError) Code() string {
+	return e.code
+}
+
+func (e messageError) Message() string {
+	return e.msg
+}
+
+func (e messageError) Error() string {
+	return fmt.Sprintf("%s: %s", e.code, e.msg)
+}
+
+func (e messageError) OrigErr() error {
+	return nil
+}
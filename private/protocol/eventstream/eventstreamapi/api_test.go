@@ -0,0 +1,110 @@
+package eventstreamapi
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/private/protocol/eventstream"
+)
+
+type testEvent struct {
+	Payload []byte
+}
+
+func (e *testEvent) UnmarshalEvent(msg eventstream.Message) error {
+	e.Payload = append([]byte{}, msg.Payload...)
+	return nil
+}
+
+func encodeMessages(t *testing.T, msgs ...eventstream.Message) *bytes.Buffer {
+	var buf bytes.Buffer
+	enc := eventstream.NewEncoder(&buf)
+	for _, msg := range msgs {
+		if err := enc.Encode(msg); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+	return &buf
+}
+
+func newMessage(headers map[string]string, payload []byte) eventstream.Message {
+	var msg eventstream.Message
+	for k, v := range headers {
+		msg.Headers.Set(k, eventstream.StringValue(v))
+	}
+	msg.Payload = payload
+	return msg
+}
+
+func unmarshalerForEventType(eventType string) (Unmarshaler, error) {
+	if eventType == "Test" {
+		return &testEvent{}, nil
+	}
+	return nil, nil
+}
+
+func TestEventReader(t *testing.T) {
+	buf := encodeMessages(t,
+		newMessage(map[string]string{MessageTypeHeader: EventMessageType, EventTypeHeader: "Unknown"}, []byte("skipped")),
+		newMessage(map[string]string{MessageTypeHeader: EventMessageType, EventTypeHeader: "Test"}, []byte("first")),
+		newMessage(map[string]string{MessageTypeHeader: EventMessageType, EventTypeHeader: "Test"}, []byte("second")),
+	)
+
+	r := NewEventReader(buf, unmarshalerForEventType)
+	for _, expect := range []string{"first", "second"} {
+		event, err := r.ReadEvent()
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if e, a := expect, string(event.(*testEvent).Payload); e != a {
+			t.Errorf("expect %v payload, got %v", e, a)
+		}
+	}
+
+	if _, err := r.ReadEvent(); err != io.EOF {
+		t.Errorf("expect %v, got %v", io.EOF, err)
+	}
+}
+
+func TestEventReader_Errors(t *testing.T) {
+	cases := map[string]struct {
+		Message eventstream.Message
+		Code    string
+		Msg     string
+	}{
+		"error": {
+			Message: newMessage(map[string]string{
+				MessageTypeHeader:  ErrorMessageType,
+				ErrorCodeHeader:    "InternalError",
+				ErrorMessageHeader: "failed",
+			}, nil),
+			Code: "InternalError",
+			Msg:  "failed",
+		},
+		"exception": {
+			Message: newMessage(map[string]string{
+				MessageTypeHeader:   ExceptionMessageType,
+				ExceptionTypeHeader: "SomeException",
+			}, []byte("exception message")),
+			Code: "SomeException",
+			Msg:  "exception message",
+		},
+	}
+
+	for name, c := range cases {
+		r := NewEventReader(encodeMessages(t, c.Message), unmarshalerForEventType)
+		_, err := r.ReadEvent()
+		aerr, ok := err.(awserr.Error)
+		if !ok {
+			t.Fatalf("%s: expect awserr.Error, got %v", name, err)
+		}
+		if e, a := c.Code, aerr.Code(); e != a {
+			t.Errorf("%s: expect %v code, got %v", name, e, a)
+		}
+		if e, a := c.Msg, aerr.Message(); e != a {
+			t.Errorf("%s: expect %v message, got %v", name, e, a)
+		}
+	}
+}
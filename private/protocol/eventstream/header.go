@@ -0,0 +1,317 @@
+package eventstream
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Headers are the headers of a message.
+type Headers []Header
+
+// Header is a header of a message, its name and value.
+type Header struct {
+	Name  string
+	Value Value
+}
+
+// Set sets the value of the header with the name, adding the header if the
+// headers do not have it.
+func (hs *Headers) Set(name string, value Value) {
+	for i := 0; i < len(*hs); i++ {
+		if (*hs)[i].Name == name {
+			(*hs)[i].Value = value
+			return
+		}
+	}
+
+	*hs = append(*hs, Header{
+		Name: name, Value: value,
+	})
+}
+
+// Get returns the value of the header with the name, or nil if the headers
+// do not have it.
+func (hs Headers) Get(name string) Value {
+	for i := 0; i < len(hs); i++ {
+		if h := hs[i]; h.Name == name {
+			return h.Value
+		}
+	}
+	return nil
+}
+
+// Del deletes the header with the name.
+func (hs *Headers) Del(name string) {
+	for i := 0; i < len(*hs); i++ {
+		if (*hs)[i].Name == name {
+			copy((*hs)[i:], (*hs)[i+1:])
+			(*hs) = (*hs)[:len(*hs)-1]
+		}
+	}
+}
+
+func decodeHeaders(r io.Reader) (Headers, error) {
+	hs := Headers{}
+
+	for {
+		name, err := decodeHeaderName(r)
+		if err != nil {
+			if err == io.EOF {
+				// EOF while getting header name means no more headers
+				break
+			}
+			return nil, err
+		}
+
+		value, err := decodeHeaderValue(r)
+		if err != nil {
+			return nil, err
+		}
+
+		hs.Set(name, value)
+	}
+
+	return hs, nil
+}
+
+func decodeHeaderName(r io.Reader) (string, error) {
+	var n [1]byte
+	if _, err := io.ReadFull(r, n[:]); err != nil {
+		return "", err
+	}
+
+	name := make([]byte, n[0])
+	if _, err := io.ReadFull(r, name); err != nil {
+		return "", err
+	}
+
+	return string(name), nil
+}
+
+func encodeHeaders(w io.Writer, hs Headers) error {
+	for _, h := range hs {
+		if len(h.Name) > 255 {
+			return LengthError{Part: "header name", Want: 255, Have: len(h.Name), Value: h.Name}
+		}
+		if _, err := w.Write(append([]byte{byte(len(h.Name))}, h.Name...)); err != nil {
+			return err
+		}
+		if err := h.Value.encode(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// valueType is the type of a header value.
+type valueType uint8
+
+const (
+	trueValueType valueType = iota
+	falseValueType
+	int8ValueType  // Byte
+	int16ValueType // Short
+	int32ValueType // Integer
+	int64ValueType // Long
+	bytesValueType
+	stringValueType
+	timestampValueType
+	uuidValueType
+)
+
+// Value is the value of a header.
+type Value interface {
+	// Get returns the Go value of the header value.
+	Get() interface{}
+
+	String() string
+
+	encode(io.Writer) error
+}
+
+func decodeHeaderValue(r io.Reader) (Value, error) {
+	var t [1]byte
+	if _, err := io.ReadFull(r, t[:]); err != nil {
+		return nil, err
+	}
+
+	switch valueType(t[0]) {
+	case trueValueType:
+		return BoolValue(true), nil
+	case falseValueType:
+		return BoolValue(false), nil
+	case int8ValueType:
+		var v int8
+		err := binary.Read(r, binary.BigEndian, &v)
+		return Int8Value(v), err
+	case int16ValueType:
+		var v int16
+		err := binary.Read(r, binary.BigEndian, &v)
+		return Int16Value(v), err
+	case int32ValueType:
+		var v int32
+		err := binary.Read(r, binary.BigEndian, &v)
+		return Int32Value(v), err
+	case int64ValueType:
+		var v int64
+		err := binary.Read(r, binary.BigEndian, &v)
+		return Int64Value(v), err
+	case bytesValueType:
+		b, err := decodeBytes(r)
+		return BytesValue(b), err
+	case stringValueType:
+		b, err := decodeBytes(r)
+		return StringValue(b), err
+	case timestampValueType:
+		var v int64
+		err := binary.Read(r, binary.BigEndian, &v)
+		return TimestampValue(time.Unix(0, v*int64(time.Millisecond)).UTC()), err
+	case uuidValueType:
+		var v UUIDValue
+		_, err := io.ReadFull(r, v[:])
+		return v, err
+	default:
+		return nil, fmt.Errorf("unknown header value type %d", t[0])
+	}
+}
+
+func decodeBytes(r io.Reader) ([]byte, error) {
+	var n uint16
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, n)
+	_, err := io.ReadFull(r, b)
+	return b, err
+}
+
+func encodeValue(w io.Writer, t valueType, v interface{}) error {
+	if _, err := w.Write([]byte{byte(t)}); err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	return binary.Write(w, binary.BigEndian, v)
+}
+
+func encodeBytes(w io.Writer, t valueType, b []byte) error {
+	if len(b) > 0xffff {
+		return LengthError{Part: "header value", Want: 0xffff, Have: len(b)}
+	}
+	if err := encodeValue(w, t, uint16(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// BoolValue is a bool header value.
+type BoolValue bool
+
+// Get returns the bool value.
+func (v BoolValue) Get() interface{} { return bool(v) }
+
+func (v BoolValue) String() string { return fmt.Sprintf("%v", bool(v)) }
+
+func (v BoolValue) encode(w io.Writer) error {
+	if v {
+		return encodeValue(w, trueValueType, nil)
+	}
+	return encodeValue(w, falseValueType, nil)
+}
+
+// Int8Value is a byte header value.
+type Int8Value int8
+
+// Get returns the int8 value.
+func (v Int8Value) Get() interface{} { return int8(v) }
+
+func (v Int8Value) String() string { return fmt.Sprintf("0x%02x", int8(v)) }
+
+func (v Int8Value) encode(w io.Writer) error { return encodeValue(w, int8ValueType, int8(v)) }
+
+// Int16Value is a short header value.
+type Int16Value int16
+
+// Get returns the int16 value.
+func (v Int16Value) Get() interface{} { return int16(v) }
+
+func (v Int16Value) String() string { return fmt.Sprintf("%d", int16(v)) }
+
+func (v Int16Value) encode(w io.Writer) error { return encodeValue(w, int16ValueType, int16(v)) }
+
+// Int32Value is an integer header value.
+type Int32Value int32
+
+// Get returns the int32 value.
+func (v Int32Value) Get() interface{} { return int32(v) }
+
+func (v Int32Value) String() string { return fmt.Sprintf("%d", int32(v)) }
+
+func (v Int32Value) encode(w io.Writer) error { return encodeValue(w, int32ValueType, int32(v)) }
+
+// Int64Value is a long header value.
+type Int64Value int64
+
+// Get returns the int64 value.
+func (v Int64Value) Get() interface{} { return int64(v) }
+
+func (v Int64Value) String() string { return fmt.Sprintf("%d", int64(v)) }
+
+func (v Int64Value) encode(w io.Writer) error { return encodeValue(w, int64ValueType, int64(v)) }
+
+// BytesValue is a byte array header value.
+type BytesValue []byte
+
+// Get returns the []byte value.
+func (v BytesValue) Get() interface{} { return []byte(v) }
+
+func (v BytesValue) String() string { return hex.EncodeToString(v) }
+
+func (v BytesValue) encode(w io.Writer) error { return encodeBytes(w, bytesValueType, v) }
+
+// StringValue is a string header value.
+type StringValue string
+
+// Get returns the string value.
+func (v StringValue) Get() interface{} { return string(v) }
+
+func (v StringValue) String() string { return string(v) }
+
+func (v StringValue) encode(w io.Writer) error { return encodeBytes(w, stringValueType, []byte(v)) }
+
+// TimestampValue is a timestamp header value, with millisecond precision.
+type TimestampValue time.Time
+
+// Get returns the time.Time value.
+func (v TimestampValue) Get() interface{} { return time.Time(v) }
+
+func (v TimestampValue) String() string { return time.Time(v).Format(time.RFC3339Nano) }
+
+func (v TimestampValue) encode(w io.Writer) error {
+	ms := time.Time(v).UnixNano() / int64(time.Millisecond)
+	return encodeValue(w, timestampValueType, ms)
+}
+
+// UUIDValue is a UUID header value.
+type UUIDValue [16]byte
+
+// Get returns the [16]byte value.
+func (v UUIDValue) Get() interface{} { return [16]byte(v) }
+
+func (v UUIDValue) String() string {
+	return fmt.Sprintf("%X-%X-%X-%X-%X", v[0:4], v[4:6], v[6:8], v[8:10], v[10:])
+}
+
+func (v UUIDValue) encode(w io.Writer) error {
+	if err := encodeValue(w, uuidValueType, nil); err != nil {
+		return err
+	}
+	_, err := w.Write(v[:])
+	return err
+}
@@ -0,0 +1,69 @@
+// Package eventstream provides the encoding and decoding of the messages of
+// the binary event stream protocol, such as the event stream of the
+// S3 SelectObjectContent operation's response.
+//
+// Each message is framed by a prelude, the total length of the message and
+// of its headers, and checksums of the prelude and of the whole message.
+//
+//     [total length (4)][headers length (4)][prelude CRC (4)]
+//     [headers (headers length)]
+//     [payload (total length - headers length - 16)]
+//     [message CRC (4)]
+package eventstream
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+)
+
+const preludeLen = 8
+const preludeCRCLen = 4
+const msgCRCLen = 4
+const minMsgLen = preludeLen + preludeCRCLen + msgCRCLen
+
+// maxPayloadLen is the largest payload of a message, 16 MB.
+const maxPayloadLen = 1024 * 1024 * 16
+
+// maxHeadersLen is the largest headers section of a message, 128 KB.
+const maxHeadersLen = 1024 * 128
+
+// A Message is an event stream message, its headers and payload.
+type Message struct {
+	Headers Headers
+	Payload []byte
+}
+
+// messagePrelude is the prelude of a message.
+type messagePrelude struct {
+	Length     uint32
+	HeadersLen uint32
+	PreludeCRC uint32
+}
+
+func (p messagePrelude) PayloadLen() uint32 {
+	return p.Length - p.HeadersLen - minMsgLen
+}
+
+func (p messagePrelude) ValidateLens() error {
+	if p.Length == 0 || p.Length < minMsgLen+p.HeadersLen {
+		return LengthError{Part: "message prelude", Want: minMsgLen + int(p.HeadersLen), Have: int(p.Length)}
+	}
+	if p.HeadersLen > maxHeadersLen {
+		return LengthError{Part: "message headers", Want: maxHeadersLen, Have: int(p.HeadersLen)}
+	}
+	if n := p.PayloadLen(); n > maxPayloadLen {
+		return LengthError{Part: "message payload", Want: maxPayloadLen, Have: int(n)}
+	}
+	return nil
+}
+
+// crc32IEEETable is the table of the CRC-32 checksums of messages.
+var crc32IEEETable = crc32.MakeTable(crc32.IEEE)
+
+func (p messagePrelude) encode() []byte {
+	b := make([]byte, preludeLen+preludeCRCLen)
+	binary.BigEndian.PutUint32(b[0:], p.Length)
+	binary.BigEndian.PutUint32(b[4:], p.HeadersLen)
+	binary.BigEndian.PutUint32(b[8:], crc32.Checksum(b[:preludeLen], crc32IEEETable))
+	return b
+}
@@ -183,6 +183,18 @@ func unmarshalScalar(value reflect.Value, data interface{}, tag reflect.StructTa
 				return err
 			}
 			value.Set(reflect.ValueOf(b))
+		case *time.Time:
+			// Timestamps are unix timestamps unless the member is an ISO 8601
+			// timestamp, as returned by IBM Cloud APIs.
+			if tag.Get("timestampFormat") != "iso8601" {
+				return errf()
+			}
+			t, err := time.Parse(time.RFC3339, d)
+			if err != nil {
+				return err
+			}
+			t = t.UTC()
+			value.Set(reflect.ValueOf(&t))
 		default:
 			return errf()
 		}
@@ -18,6 +18,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/private/protocol"
 )
 
 // RFC822 returns an RFC822 formatted timestamp for AWS protocols
@@ -83,6 +84,13 @@ func buildLocationElements(r *request.Request, v reflect.Value, buildGETQuery bo
 			if name == "" {
 				name = field.Name
 			}
+			// Idempotency tokens are generated once, when the request is
+			// built, so the same token is sent with every retry.
+			if loc := field.Tag.Get("location"); loc == "header" || loc == "querystring" {
+				if protocol.CanSetIdempotencyToken(m, field) {
+					m = reflect.ValueOf(protocol.GetIdempotencyToken())
+				}
+			}
 			if kind := m.Kind(); kind == reflect.Ptr {
 				m = m.Elem()
 			} else if kind == reflect.Interface {
@@ -61,3 +61,39 @@ func TestMarshalPath(t *testing.T) {
 	}
 
 }
+
+func TestBuildIdempotencyToken(t *testing.T) {
+	in := struct {
+		ClientToken *string `location:"header" locationName:"x-client-token" idempotencyToken:"true"`
+		QueryToken  *string `location:"querystring" locationName:"token" idempotencyToken:"true"`
+		SetToken    *string `location:"header" locationName:"x-set-token" idempotencyToken:"true"`
+	}{
+		SetToken: aws.String("set"),
+	}
+
+	req := &request.Request{
+		HTTPRequest: &http.Request{
+			URL:    &url.URL{Scheme: "https", Host: "example.com", Path: "/"},
+			Header: http.Header{},
+		},
+		Params: &in,
+	}
+
+	Build(req)
+
+	if req.Error != nil {
+		t.Fatalf("unexpected error, %v", req.Error)
+	}
+	if a := req.HTTPRequest.Header.Get("x-client-token"); len(a) != 36 {
+		t.Errorf("expect generated header token, got %q", a)
+	}
+	if a := req.HTTPRequest.URL.Query().Get("token"); len(a) != 36 {
+		t.Errorf("expect generated querystring token, got %q", a)
+	}
+	if e, a := "set", req.HTTPRequest.Header.Get("x-set-token"); e != a {
+		t.Errorf("expect %v token, got %v", e, a)
+	}
+	if in.ClientToken != nil {
+		t.Errorf("expect input not to be modified")
+	}
+}
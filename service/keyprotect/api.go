@@ -0,0 +1,424 @@
+package keyprotect
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const opUnwrapKey = "UnwrapKey"
+
+// UnwrapKeyRequest generates a "aws/request.Request" representing the
+// client's request for the UnwrapKey operation. The "output" return
+// value will be populated with the request's response once the request completes
+// successfully.
+//
+// Use "Send" method on the returned Request to send the API call to the service.
+// the "output" return value is not valid until after Send returns without error.
+//
+// See UnwrapKey for more information on using the UnwrapKey
+// API call, and error handling.
+//
+// This method is useful when you want to inject custom logic or configuration
+// into the SDK's request lifecycle. Such as custom headers, or retry logic.
+//
+//
+//    // Example sending a request using the UnwrapKeyRequest method.
+//    req, resp := client.UnwrapKeyRequest(params)
+//
+//    err := req.Send()
+//    if err == nil { // resp is now filled
+//        fmt.Println(resp)
+//    }
+func (c *KeyProtect) UnwrapKeyRequest(input *UnwrapKeyInput) (req *request.Request, output *UnwrapKeyOutput) {
+	op := &request.Operation{
+		Name:       opUnwrapKey,
+		HTTPMethod: "POST",
+		HTTPPath:   "/api/v2/keys/{id}?action=unwrap",
+	}
+
+	if input == nil {
+		input = &UnwrapKeyInput{}
+	}
+
+	output = &UnwrapKeyOutput{}
+	req = c.newRequest(op, input, output)
+	return
+}
+
+// UnwrapKey API operation for IBM Key Protect.
+//
+// Unwraps (decrypts) a data encryption key wrapped with the root key, with
+// the additional authentication data the key was wrapped with.
+//
+// Returns awserr.Error for service API and SDK errors. Use runtime type assertions
+// with awserr.Error's Code and Message methods to get detailed information about
+// the error.
+func (c *KeyProtect) UnwrapKey(input *UnwrapKeyInput) (*UnwrapKeyOutput, error) {
+	req, out := c.UnwrapKeyRequest(input)
+	return out, req.Send()
+}
+
+// UnwrapKeyWithContext is the same as UnwrapKey with the addition of
+// the ability to pass a context and additional request options.
+//
+// See UnwrapKey for details on how to use this API operation.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (c *KeyProtect) UnwrapKeyWithContext(ctx aws.Context, input *UnwrapKeyInput, opts ...request.Option) (*UnwrapKeyOutput, error) {
+	req, out := c.UnwrapKeyRequest(input)
+	req.SetContext(ctx)
+	req.ApplyOptions(opts...)
+	return out, req.Send()
+}
+
+const opWrapKey = "WrapKey"
+
+// WrapKeyRequest generates a "aws/request.Request" representing the
+// client's request for the WrapKey operation. The "output" return
+// value will be populated with the request's response once the request completes
+// successfully.
+//
+// Use "Send" method on the returned Request to send the API call to the service.
+// the "output" return value is not valid until after Send returns without error.
+//
+// See WrapKey for more information on using the WrapKey
+// API call, and error handling.
+//
+// This method is useful when you want to inject custom logic or configuration
+// into the SDK's request lifecycle. Such as custom headers, or retry logic.
+//
+//
+//    // Example sending a request using the WrapKeyRequest method.
+//    req, resp := client.WrapKeyRequest(params)
+//
+//    err := req.Send()
+//    if err == nil { // resp is now filled
+//        fmt.Println(resp)
+//    }
+func (c *KeyProtect) WrapKeyRequest(input *WrapKeyInput) (req *request.Request, output *WrapKeyOutput) {
+	op := &request.Operation{
+		Name:       opWrapKey,
+		HTTPMethod: "POST",
+		HTTPPath:   "/api/v2/keys/{id}?action=wrap",
+	}
+
+	if input == nil {
+		input = &WrapKeyInput{}
+	}
+
+	output = &WrapKeyOutput{}
+	req = c.newRequest(op, input, output)
+	return
+}
+
+// WrapKey API operation for IBM Key Protect.
+//
+// Wraps (encrypts) a data encryption key with the root key. If no plaintext
+// key is provided, a new 256 bit data encryption key is generated, and
+// returned with its wrapped key.
+//
+// Returns awserr.Error for service API and SDK errors. Use runtime type assertions
+// with awserr.Error's Code and Message methods to get detailed information about
+// the error.
+func (c *KeyProtect) WrapKey(input *WrapKeyInput) (*WrapKeyOutput, error) {
+	req, out := c.WrapKeyRequest(input)
+	return out, req.Send()
+}
+
+// WrapKeyWithContext is the same as WrapKey with the addition of
+// the ability to pass a context and additional request options.
+//
+// See WrapKey for details on how to use this API operation.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (c *KeyProtect) WrapKeyWithContext(ctx aws.Context, input *WrapKeyInput, opts ...request.Option) (*WrapKeyOutput, error) {
+	req, out := c.WrapKeyRequest(input)
+	req.SetContext(ctx)
+	req.ApplyOptions(opts...)
+	return out, req.Send()
+}
+
+type KeyVersion struct {
+	_ struct{} `type:"structure"`
+
+	// The time the version of the root key was created.
+	CreationDate *time.Time `locationName:"creationDate" type:"timestamp" timestampFormat:"iso8601"`
+
+	// The ID of the version of the root key.
+	ID *string `locationName:"id" type:"string"`
+}
+
+// String returns the string representation
+func (s KeyVersion) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s KeyVersion) GoString() string {
+	return s.String()
+}
+
+// SetCreationDate sets the CreationDate field's value.
+func (s *KeyVersion) SetCreationDate(v time.Time) *KeyVersion {
+	s.CreationDate = &v
+	return s
+}
+
+// SetID sets the ID field's value.
+func (s *KeyVersion) SetID(v string) *KeyVersion {
+	s.ID = &v
+	return s
+}
+
+type UnwrapKeyInput struct {
+	_ struct{} `type:"structure"`
+
+	// The additional authentication data (AAD) of the key. The same AAD must be
+	// provided to unwrap the key.
+	AAD []*string `locationName:"aad" type:"list"`
+
+	// The wrapped data encryption key.
+	//
+	// Ciphertext is automatically base64 encoded/decoded by the SDK.
+	//
+	// Ciphertext is a required field
+	Ciphertext []byte `locationName:"ciphertext" type:"blob" required:"true"`
+
+	// The GUID of the Key Protect or Hyper Protect Crypto Services instance of
+	// the root key.
+	//
+	// InstanceID is a required field
+	InstanceID *string `location:"header" locationName:"bluemix-instance" type:"string" required:"true"`
+
+	// The ID of the root key.
+	//
+	// KeyID is a required field
+	KeyID *string `location:"uri" locationName:"id" type:"string" required:"true"`
+}
+
+// String returns the string representation
+func (s UnwrapKeyInput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s UnwrapKeyInput) GoString() string {
+	return s.String()
+}
+
+// Validate inspects the fields of the type to determine if they are valid.
+func (s *UnwrapKeyInput) Validate() error {
+	invalidParams := request.ErrInvalidParams{Context: "UnwrapKeyInput"}
+	if s.Ciphertext == nil {
+		invalidParams.Add(request.NewErrParamRequired("Ciphertext"))
+	}
+	if s.InstanceID == nil {
+		invalidParams.Add(request.NewErrParamRequired("InstanceID"))
+	}
+	if s.InstanceID != nil && len(*s.InstanceID) < 1 {
+		invalidParams.Add(request.NewErrParamMinLen("InstanceID", 1))
+	}
+	if s.KeyID == nil {
+		invalidParams.Add(request.NewErrParamRequired("KeyID"))
+	}
+	if s.KeyID != nil && len(*s.KeyID) < 1 {
+		invalidParams.Add(request.NewErrParamMinLen("KeyID", 1))
+	}
+
+	if invalidParams.Len() > 0 {
+		return invalidParams
+	}
+	return nil
+}
+
+// SetAAD sets the AAD field's value.
+func (s *UnwrapKeyInput) SetAAD(v []*string) *UnwrapKeyInput {
+	s.AAD = v
+	return s
+}
+
+// SetCiphertext sets the Ciphertext field's value.
+func (s *UnwrapKeyInput) SetCiphertext(v []byte) *UnwrapKeyInput {
+	s.Ciphertext = v
+	return s
+}
+
+// SetInstanceID sets the InstanceID field's value.
+func (s *UnwrapKeyInput) SetInstanceID(v string) *UnwrapKeyInput {
+	s.InstanceID = &v
+	return s
+}
+
+// SetKeyID sets the KeyID field's value.
+func (s *UnwrapKeyInput) SetKeyID(v string) *UnwrapKeyInput {
+	s.KeyID = &v
+	return s
+}
+
+type UnwrapKeyOutput struct {
+	_ struct{} `type:"structure"`
+
+	// The version of the root key the key is wrapped with.
+	KeyVersion *KeyVersion `locationName:"keyVersion" type:"structure"`
+
+	// The unwrapped data encryption key.
+	//
+	// Plaintext is automatically base64 encoded/decoded by the SDK.
+	Plaintext []byte `locationName:"plaintext" type:"blob"`
+}
+
+// String returns the string representation
+func (s UnwrapKeyOutput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s UnwrapKeyOutput) GoString() string {
+	return s.String()
+}
+
+// SetKeyVersion sets the KeyVersion field's value.
+func (s *UnwrapKeyOutput) SetKeyVersion(v *KeyVersion) *UnwrapKeyOutput {
+	s.KeyVersion = v
+	return s
+}
+
+// SetPlaintext sets the Plaintext field's value.
+func (s *UnwrapKeyOutput) SetPlaintext(v []byte) *UnwrapKeyOutput {
+	s.Plaintext = v
+	return s
+}
+
+type WrapKeyInput struct {
+	_ struct{} `type:"structure"`
+
+	// The additional authentication data (AAD) of the key. The same AAD must be
+	// provided to unwrap the key.
+	AAD []*string `locationName:"aad" type:"list"`
+
+	// The GUID of the Key Protect or Hyper Protect Crypto Services instance of
+	// the root key.
+	//
+	// InstanceID is a required field
+	InstanceID *string `location:"header" locationName:"bluemix-instance" type:"string" required:"true"`
+
+	// The ID of the root key.
+	//
+	// KeyID is a required field
+	KeyID *string `location:"uri" locationName:"id" type:"string" required:"true"`
+
+	// The data encryption key to wrap. A new key is generated if not set.
+	//
+	// Plaintext is automatically base64 encoded/decoded by the SDK.
+	Plaintext []byte `locationName:"plaintext" type:"blob"`
+}
+
+// String returns the string representation
+func (s WrapKeyInput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s WrapKeyInput) GoString() string {
+	return s.String()
+}
+
+// Validate inspects the fields of the type to determine if they are valid.
+func (s *WrapKeyInput) Validate() error {
+	invalidParams := request.ErrInvalidParams{Context: "WrapKeyInput"}
+	if s.InstanceID == nil {
+		invalidParams.Add(request.NewErrParamRequired("InstanceID"))
+	}
+	if s.InstanceID != nil && len(*s.InstanceID) < 1 {
+		invalidParams.Add(request.NewErrParamMinLen("InstanceID", 1))
+	}
+	if s.KeyID == nil {
+		invalidParams.Add(request.NewErrParamRequired("KeyID"))
+	}
+	if s.KeyID != nil && len(*s.KeyID) < 1 {
+		invalidParams.Add(request.NewErrParamMinLen("KeyID", 1))
+	}
+
+	if invalidParams.Len() > 0 {
+		return invalidParams
+	}
+	return nil
+}
+
+// SetAAD sets the AAD field's value.
+func (s *WrapKeyInput) SetAAD(v []*string) *WrapKeyInput {
+	s.AAD = v
+	return s
+}
+
+// SetInstanceID sets the InstanceID field's value.
+func (s *WrapKeyInput) SetInstanceID(v string) *WrapKeyInput {
+	s.InstanceID = &v
+	return s
+}
+
+// SetKeyID sets the KeyID field's value.
+func (s *WrapKeyInput) SetKeyID(v string) *WrapKeyInput {
+	s.KeyID = &v
+	return s
+}
+
+// SetPlaintext sets the Plaintext field's value.
+func (s *WrapKeyInput) SetPlaintext(v []byte) *WrapKeyInput {
+	s.Plaintext = v
+	return s
+}
+
+type WrapKeyOutput struct {
+	_ struct{} `type:"structure"`
+
+	// The wrapped data encryption key.
+	//
+	// Ciphertext is automatically base64 encoded/decoded by the SDK.
+	Ciphertext []byte `locationName:"ciphertext" type:"blob"`
+
+	// The version of the root key the key is wrapped with.
+	KeyVersion *KeyVersion `locationName:"keyVersion" type:"structure"`
+
+	// The data encryption key generated, if no plaintext key was provided.
+	//
+	// Plaintext is automatically base64 encoded/decoded by the SDK.
+	Plaintext []byte `locationName:"plaintext" type:"blob"`
+}
+
+// String returns the string representation
+func (s WrapKeyOutput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s WrapKeyOutput) GoString() string {
+	return s.String()
+}
+
+// SetCiphertext sets the Ciphertext field's value.
+func (s *WrapKeyOutput) SetCiphertext(v []byte) *WrapKeyOutput {
+	s.Ciphertext = v
+	return s
+}
+
+// SetKeyVersion sets the KeyVersion field's value.
+func (s *WrapKeyOutput) SetKeyVersion(v *KeyVersion) *WrapKeyOutput {
+	s.KeyVersion = v
+	return s
+}
+
+// SetPlaintext sets the Plaintext field's value.
+func (s *WrapKeyOutput) SetPlaintext(v []byte) *WrapKeyOutput {
+	s.Plaintext = v
+	return s
+}
@@ -0,0 +1,49 @@
+package keyprotect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+// ErrCodeInvalidKeyCRN is the error code returned when a key CRN cannot be
+// parsed.
+const ErrCodeInvalidKeyCRN = "InvalidKeyCRN"
+
+// A KeyCRN is a parsed CRN of a Key Protect or Hyper Protect Crypto Services
+// key, such as
+// "crn:v1:bluemix:public:kms:us-south:a/account:instance-guid:key:key-id".
+type KeyCRN struct {
+	// The service of the key, "kms" for Key Protect keys and "hs-crypto" for
+	// Hyper Protect Crypto Services keys.
+	Service string
+
+	// The region of the key's instance.
+	Region string
+
+	// The GUID of the key's instance, sent as the instance ID of requests.
+	InstanceID string
+
+	// The ID of the key.
+	KeyID string
+}
+
+// ParseKeyCRN parses the CRN of a Key Protect or Hyper Protect Crypto
+// Services key.
+func ParseKeyCRN(crn string) (KeyCRN, error) {
+	// crn:version:cname:ctype:service-name:location:scope:service-instance:resource-type:resource
+	parts := strings.Split(crn, ":")
+	if len(parts) != 10 || parts[0] != "crn" || parts[8] != "key" ||
+		len(parts[4]) == 0 || len(parts[7]) == 0 || len(parts[9]) == 0 {
+		return KeyCRN{}, awserr.New(ErrCodeInvalidKeyCRN,
+			fmt.Sprintf("invalid key CRN %q", crn), nil)
+	}
+
+	return KeyCRN{
+		Service:    parts[4],
+		Region:     parts[5],
+		InstanceID: parts[7],
+		KeyID:      parts[9],
+	}, nil
+}
@@ -0,0 +1,76 @@
+package keyprotect
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// keyActionContentType is the content type of key action requests.
+const keyActionContentType = "application/vnd.ibm.kms.key_action+json"
+
+func setKeyActionContentType(r *request.Request) {
+	r.HTTPRequest.Header.Set("Content-Type", keyActionContentType)
+}
+
+// jsonErrorResponse is the error response of the Key Protect API.
+type jsonErrorResponse struct {
+	Resources []struct {
+		ErrorMsg string `json:"errorMsg"`
+		Reasons  []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"reasons"`
+	} `json:"resources"`
+}
+
+func unmarshalError(r *request.Request) {
+	defer r.HTTPResponse.Body.Close()
+	defer io.Copy(ioutil.Discard, r.HTTPResponse.Body)
+
+	var errCode, errMsg string
+
+	resp := &jsonErrorResponse{}
+	err := json.NewDecoder(r.HTTPResponse.Body).Decode(resp)
+	if err != nil && err != io.EOF {
+		errCode = "SerializationError"
+		errMsg = "failed to decode Key Protect JSON error response"
+	} else {
+		if len(resp.Resources) > 0 {
+			res := resp.Resources[0]
+			errMsg = res.ErrorMsg
+			if len(res.Reasons) > 0 {
+				errCode = res.Reasons[0].Code
+				if len(errMsg) == 0 {
+					errMsg = res.Reasons[0].Message
+				}
+			}
+		}
+		err = nil
+	}
+
+	// Fallback to status code converted to message if still no error code
+	if len(errCode) == 0 {
+		statusText := http.StatusText(r.HTTPResponse.StatusCode)
+		errCode = strings.Replace(statusText, " ", "", -1)
+		if len(errMsg) == 0 {
+			errMsg = statusText
+		}
+	}
+
+	requestID := r.RequestID
+	if requestID == "" {
+		requestID = r.HTTPResponse.Header.Get("Correlation-Id")
+	}
+
+	r.Error = awserr.NewRequestFailure(
+		awserr.New(errCode, errMsg, err),
+		r.HTTPResponse.StatusCode,
+		requestID,
+	)
+}
@@ -0,0 +1,187 @@
+package keyprotect_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/credentialstest"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/keyprotect"
+)
+
+func newTestClient(handler http.HandlerFunc) (*keyprotect.KeyProtect, func()) {
+	server := httptest.NewServer(handler)
+
+	svc := keyprotect.New(unit.Session, &aws.Config{
+		Endpoint:   aws.String(server.URL),
+		MaxRetries: aws.Int(0),
+		Credentials: credentials.NewTypedCredentials(&credentialstest.MockProvider{
+			Value: credentials.Value{SessionToken: "token"},
+		}, "ibm-iam"),
+	})
+
+	return svc, server.Close
+}
+
+func TestWrapUnwrapKey(t *testing.T) {
+	svc, closeFn := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if e, a := "/api/v2/keys/key-id", r.URL.Path; e != a {
+			t.Errorf("expect %v path, got %v", e, a)
+		}
+		if e, a := "instance-guid", r.Header.Get("bluemix-instance"); e != a {
+			t.Errorf("expect %v instance, got %v", e, a)
+		}
+		if e, a := "Bearer token", r.Header.Get("Authorization"); e != a {
+			t.Errorf("expect %v authorization, got %v", e, a)
+		}
+		if e, a := "application/vnd.ibm.kms.key_action+json", r.Header.Get("Content-Type"); e != a {
+			t.Errorf("expect %v content type, got %v", e, a)
+		}
+
+		var body struct {
+			Plaintext  []byte   `json:"plaintext"`
+			Ciphertext []byte   `json:"ciphertext"`
+			AAD        []string `json:"aad"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("expect no error, got %v", err)
+		}
+		if e, a := []string{"aad"}, body.AAD; len(a) != 1 || e[0] != a[0] {
+			t.Errorf("expect %v AAD, got %v", e, a)
+		}
+
+		// The test service "wraps" keys by reversing them.
+		switch action := r.URL.Query().Get("action"); action {
+		case "wrap":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"ciphertext": reverse(body.Plaintext),
+				"keyVersion": map[string]string{"id": "version-id"},
+			})
+		case "unwrap":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"plaintext": reverse(body.Ciphertext),
+			})
+		default:
+			t.Errorf("unexpected action %v", action)
+		}
+	})
+	defer closeFn()
+
+	key := []byte("0123456789abcdef")
+	wrapped, err := svc.WrapKey(&keyprotect.WrapKeyInput{
+		AAD:        aws.StringSlice([]string{"aad"}),
+		InstanceID: aws.String("instance-guid"),
+		KeyID:      aws.String("key-id"),
+		Plaintext:  key,
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := reverse(key), wrapped.Ciphertext; !bytes.Equal(e, a) {
+		t.Errorf("expect %v ciphertext, got %v", e, a)
+	}
+	if e, a := "version-id", aws.StringValue(wrapped.KeyVersion.ID); e != a {
+		t.Errorf("expect %v key version, got %v", e, a)
+	}
+
+	unwrapped, err := svc.UnwrapKey(&keyprotect.UnwrapKeyInput{
+		AAD:        aws.StringSlice([]string{"aad"}),
+		Ciphertext: wrapped.Ciphertext,
+		InstanceID: aws.String("instance-guid"),
+		KeyID:      aws.String("key-id"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := key, unwrapped.Plaintext; !bytes.Equal(e, a) {
+		t.Errorf("expect %v plaintext, got %v", e, a)
+	}
+}
+
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
+
+func TestUnmarshalError(t *testing.T) {
+	svc, closeFn := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Correlation-Id", "correlation-id")
+		w.WriteHeader(404)
+		w.Write([]byte(`{"metadata":{"collectionType":"application/vnd.ibm.kms.error+json","collectionTotal":1},
+			"resources":[{"errorMsg":"Not Found: Key could not be found",
+			"reasons":[{"code":"KEY_NOT_FOUND_ERR","message":"Key does not exist","status":404}]}]}`))
+	})
+	defer closeFn()
+
+	_, err := svc.WrapKey(&keyprotect.WrapKeyInput{
+		InstanceID: aws.String("instance-guid"),
+		KeyID:      aws.String("key-id"),
+	})
+	aerr, ok := err.(awserr.RequestFailure)
+	if !ok {
+		t.Fatalf("expect request failure, got %v", err)
+	}
+	if e, a := "KEY_NOT_FOUND_ERR", aerr.Code(); e != a {
+		t.Errorf("expect %v code, got %v", e, a)
+	}
+	if e, a := 404, aerr.StatusCode(); e != a {
+		t.Errorf("expect %v status code, got %v", e, a)
+	}
+	if e, a := "correlation-id", aerr.RequestID(); e != a {
+		t.Errorf("expect %v request ID, got %v", e, a)
+	}
+}
+
+func TestParseKeyCRN(t *testing.T) {
+	cases := []struct {
+		CRN    string
+		Expect keyprotect.KeyCRN
+		Err    bool
+	}{
+		{
+			CRN:    "crn:v1:bluemix:public:kms:us-south:a/account:instance-guid:key:key-id",
+			Expect: keyprotect.KeyCRN{Service: "kms", Region: "us-south", InstanceID: "instance-guid", KeyID: "key-id"},
+		},
+		{
+			CRN:    "crn:v1:bluemix:public:hs-crypto:eu-de:a/account:instance-guid:key:key-id",
+			Expect: keyprotect.KeyCRN{Service: "hs-crypto", Region: "eu-de", InstanceID: "instance-guid", KeyID: "key-id"},
+		},
+		{CRN: "crn:v1:bluemix:public:kms:us-south:a/account:instance-guid::", Err: true},
+		{CRN: "key-id", Err: true},
+	}
+
+	for i, c := range cases {
+		crn, err := keyprotect.ParseKeyCRN(c.CRN)
+		if c.Err {
+			if err == nil {
+				t.Errorf("%d, expect error, got none", i)
+			} else if e, a := keyprotect.ErrCodeInvalidKeyCRN, err.(awserr.Error).Code(); e != a {
+				t.Errorf("%d, expect %v error code, got %v", i, e, a)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d, expect no error, got %v", i, err)
+		}
+		if e, a := c.Expect, crn; e != a {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+}
+
+func TestNewEndpoint(t *testing.T) {
+	svc := keyprotect.New(unit.Session, &aws.Config{Region: aws.String("eu-de")})
+
+	if e, a := "https://eu-de.kms.cloud.ibm.com", svc.Endpoint; e != a {
+		t.Errorf("expect %v endpoint, got %v", e, a)
+	}
+}
@@ -0,0 +1,30 @@
+// Package keyprotect provides the client and types for making API requests
+// to the key wrapping API of IBM Key Protect and IBM Hyper Protect Crypto
+// Services (HPCS).
+//
+// Root keys stored in a Key Protect or HPCS instance wrap (encrypt) and
+// unwrap (decrypt) data encryption keys, such as the data keys of objects
+// encrypted client-side by the s3crypto package. The root keys never leave
+// the service.
+//
+// Using the Client
+//
+// Create a client with the New function, and a session configured with IBM
+// IAM credentials. The client sends requests to the Key Protect endpoint of
+// the session's region, an Endpoint must be provided for HPCS instances.
+//
+//     svc := keyprotect.New(sess)
+//
+//     key, err := keyprotect.ParseKeyCRN(rootKeyCRN)
+//     if err != nil {
+//         return err
+//     }
+//     out, err := svc.WrapKey(&keyprotect.WrapKeyInput{
+//         InstanceID: aws.String(key.InstanceID),
+//         KeyID:      aws.String(key.KeyID),
+//         Plaintext:  dataKey,
+//     })
+//
+// Errors returned by the key wrapping API are awserr.RequestFailure values
+// with the error code of the response, such as "KEY_NOT_FOUND_ERR".
+package keyprotect
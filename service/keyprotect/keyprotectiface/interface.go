@@ -0,0 +1,66 @@
+// Package keyprotectiface provides an interface to enable mocking the
+// IBM Key Protect client for testing your code.
+package keyprotectiface
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/keyprotect"
+)
+
+// KeyProtectAPI provides an interface to enable mocking the
+// keyprotect.KeyProtect service client's API operation,
+// and paginators. This make unit testing your code that calls out
+// to the SDK's service client's calls easier.
+//
+// The best way to use this interface is so the SDK's service client's calls
+// can be stubbed out for unit testing your code with the SDK without needing
+// to inject custom request handlers into the SDK's request pipeline.
+//
+//    // myFunc uses an SDK service client to make a request to
+//    // the IBM Key Protect API.
+//    func myFunc(svc keyprotectiface.KeyProtectAPI) bool {
+//        // Make svc.WrapKey request
+//    }
+//
+//    func main() {
+//        sess := session.New()
+//        svc := keyprotect.New(sess)
+//
+//        myFunc(svc)
+//    }
+//
+// In your _test.go file:
+//
+//    // Define a mock struct to be used in your unit tests of myFunc.
+//    type mockKeyProtectClient struct {
+//        keyprotectiface.KeyProtectAPI
+//    }
+//    func (m *mockKeyProtectClient) WrapKey(input *keyprotect.WrapKeyInput) (*keyprotect.WrapKeyOutput, error) {
+//        // mock response/functionality
+//    }
+//
+//    func TestMyFunc(t *testing.T) {
+//        // Setup Test
+//        mockSvc := &mockKeyProtectClient{}
+//
+//        myfunc(mockSvc)
+//
+//        // Verify myFunc's functionality
+//    }
+//
+// It is important to note that this interface will have breaking changes
+// when API operations are added to the client. Its suggested to use the
+// pattern above for testing, or using tooling to generate mocks to satisfy
+// the interfaces.
+type KeyProtectAPI interface {
+	UnwrapKey(*keyprotect.UnwrapKeyInput) (*keyprotect.UnwrapKeyOutput, error)
+	UnwrapKeyWithContext(aws.Context, *keyprotect.UnwrapKeyInput, ...request.Option) (*keyprotect.UnwrapKeyOutput, error)
+	UnwrapKeyRequest(*keyprotect.UnwrapKeyInput) (*request.Request, *keyprotect.UnwrapKeyOutput)
+
+	WrapKey(*keyprotect.WrapKeyInput) (*keyprotect.WrapKeyOutput, error)
+	WrapKeyWithContext(aws.Context, *keyprotect.WrapKeyInput, ...request.Option) (*keyprotect.WrapKeyOutput, error)
+	WrapKeyRequest(*keyprotect.WrapKeyInput) (*request.Request, *keyprotect.WrapKeyOutput)
+}
+
+var _ KeyProtectAPI = (*keyprotect.KeyProtect)(nil)
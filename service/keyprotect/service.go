@@ -0,0 +1,98 @@
+package keyprotect
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/signer/ibm"
+	"github.com/aws/aws-sdk-go/private/protocol/restjson"
+)
+
+// KeyProtect provides the API operation methods for making requests to the
+// key wrapping API of IBM Key Protect and Hyper Protect Crypto Services. See
+// this package's package overview docs for details on the service.
+//
+// KeyProtect methods are safe to use concurrently. It is not safe to
+// modify mutate any of the struct's properties though.
+type KeyProtect struct {
+	*client.Client
+}
+
+// Service information constants
+const (
+	ServiceName = "keyprotect" // Service endpoint prefix API calls made to.
+	EndpointsID = ServiceName  // Service ID for Regions and Endpoints metadata.
+)
+
+// endpointDomain is the domain of the regional Key Protect endpoints.
+const endpointDomain = "kms.cloud.ibm.com"
+
+// New creates a new instance of the KeyProtect client with a session.
+// Requests are sent to the Key Protect endpoint of the configured region,
+// such as "https://us-south.kms.cloud.ibm.com", unless an Endpoint is
+// provided with the additional configuration, and are signed with the
+// session's IBM IAM credentials.
+//
+// Example:
+//     sess := session.Must(session.NewSession(&aws.Config{
+//         Region:      aws.String("us-south"),
+//         Credentials: ibmcreds.NewCredentialsClient(apiKey, "", ""),
+//     }))
+//
+//     // Create a KeyProtect client from just a session.
+//     svc := keyprotect.New(sess)
+//
+//     // Create a KeyProtect client for a Hyper Protect Crypto Services instance.
+//     svc := keyprotect.New(sess, &aws.Config{Endpoint: aws.String(hpcsEndpoint)})
+func New(p client.ConfigProvider, cfgs ...*aws.Config) *KeyProtect {
+	cfg := &aws.Config{EndpointResolver: endpointResolver}
+
+	c := p.ClientConfig(EndpointsID, append([]*aws.Config{cfg}, cfgs...)...)
+	return newClient(*c.Config, c.Handlers, c.Endpoint, c.SigningRegion, c.SigningName)
+}
+
+// endpointResolver resolves the regional Key Protect endpoints. Key Protect
+// endpoints are not part of the SDK's endpoints model.
+var endpointResolver = endpoints.ResolverFunc(func(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+	var o endpoints.Options
+	o.Set(opts...)
+
+	return endpoints.ResolvedEndpoint{
+		URL:           endpoints.AddScheme(region+"."+endpointDomain, o.DisableSSL),
+		SigningRegion: region,
+	}, nil
+})
+
+// newClient creates, initializes and returns a new service client instance.
+func newClient(cfg aws.Config, handlers request.Handlers, endpoint, signingRegion, signingName string) *KeyProtect {
+	svc := &KeyProtect{
+		Client: client.New(
+			cfg,
+			metadata.ClientInfo{
+				ServiceName:   ServiceName,
+				SigningName:   signingName,
+				SigningRegion: signingRegion,
+				Endpoint:      endpoint,
+				APIVersion:    "v2",
+			},
+			handlers,
+		),
+	}
+
+	// Handlers
+	svc.Handlers.Sign.PushBackNamed(ibm.SignRequestHandler)
+	svc.Handlers.Build.PushBackNamed(restjson.BuildHandler)
+	svc.Handlers.Build.PushBack(setKeyActionContentType)
+	svc.Handlers.Unmarshal.PushBackNamed(restjson.UnmarshalHandler)
+	svc.Handlers.UnmarshalMeta.PushBackNamed(restjson.UnmarshalMetaHandler)
+	svc.Handlers.UnmarshalError.PushBack(unmarshalError)
+
+	return svc
+}
+
+// newRequest creates a new request for a KeyProtect operation.
+func (c *KeyProtect) newRequest(op *request.Operation, params, data interface{}) *request.Request {
+	return c.NewRequest(op, params, data)
+}
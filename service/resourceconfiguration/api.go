@@ -0,0 +1,258 @@
+package resourceconfiguration
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const opGetBucketConfig = "GetBucketConfig"
+
+// GetBucketConfigRequest generates a "aws/request.Request" representing the
+// client's request for the GetBucketConfig operation. The "output" return
+// value will be populated with the request's response once the request completes
+// successfully.
+//
+// Use "Send" method on the returned Request to send the API call to the service.
+// the "output" return value is not valid until after Send returns without error.
+//
+// See GetBucketConfig for more information on using the GetBucketConfig
+// API call, and error handling.
+//
+// This method is useful when you want to inject custom logic or configuration
+// into the SDK's request lifecycle. Such as custom headers, or retry logic.
+//
+//
+//    // Example sending a request using the GetBucketConfigRequest method.
+//    req, resp := client.GetBucketConfigRequest(params)
+//
+//    err := req.Send()
+//    if err == nil { // resp is now filled
+//        fmt.Println(resp)
+//    }
+func (c *ResourceConfiguration) GetBucketConfigRequest(input *GetBucketConfigInput) (req *request.Request, output *GetBucketConfigOutput) {
+	op := &request.Operation{
+		Name:       opGetBucketConfig,
+		HTTPMethod: "GET",
+		HTTPPath:   "/v1/b/{bucket}",
+	}
+
+	if input == nil {
+		input = &GetBucketConfigInput{}
+	}
+
+	output = &GetBucketConfigOutput{}
+	req = c.newRequest(op, input, output)
+	return
+}
+
+// GetBucketConfig API operation for IBM COS Resource Configuration.
+//
+// Retrieves the configuration and usage of a bucket, such as its hard quota
+// and the number of bytes it stores. The usage is updated periodically, and
+// may not reflect the most recent writes to the bucket.
+//
+// Returns awserr.Error for service API and SDK errors. Use runtime type assertions
+// with awserr.Error's Code and Message methods to get detailed information about
+// the error.
+func (c *ResourceConfiguration) GetBucketConfig(input *GetBucketConfigInput) (*GetBucketConfigOutput, error) {
+	req, out := c.GetBucketConfigRequest(input)
+	return out, req.Send()
+}
+
+// GetBucketConfigWithContext is the same as GetBucketConfig with the addition of
+// the ability to pass a context and additional request options.
+//
+// See GetBucketConfig for details on how to use this API operation.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (c *ResourceConfiguration) GetBucketConfigWithContext(ctx aws.Context, input *GetBucketConfigInput, opts ...request.Option) (*GetBucketConfigOutput, error) {
+	req, out := c.GetBucketConfigRequest(input)
+	req.SetContext(ctx)
+	req.ApplyOptions(opts...)
+	return out, req.Send()
+}
+
+type BucketConfig struct {
+	_ struct{} `type:"structure"`
+
+	// The number of bytes stored in the bucket, including noncurrent object
+	// versions.
+	BytesUsed *int64 `locationName:"bytes_used" type:"long"`
+
+	// The CRN of the bucket.
+	CRN *string `locationName:"crn" type:"string"`
+
+	// The maximum number of bytes the bucket may store, 0 if the bucket has no
+	// quota. Writes which would exceed the quota are rejected.
+	HardQuota *int64 `locationName:"hard_quota" type:"long"`
+
+	// The name of the bucket.
+	Name *string `locationName:"name" type:"string"`
+
+	// The number of bytes stored in noncurrent object versions of the bucket.
+	NoncurrentBytesUsed *int64 `locationName:"noncurrent_bytes_used" type:"long"`
+
+	// The number of noncurrent object versions in the bucket.
+	NoncurrentObjectCount *int64 `locationName:"noncurrent_object_count" type:"long"`
+
+	// The number of objects in the bucket.
+	ObjectCount *int64 `locationName:"object_count" type:"long"`
+
+	// The CRN of the COS service instance of the bucket.
+	ServiceInstanceCRN *string `locationName:"service_instance_crn" type:"string"`
+
+	// The GUID of the COS service instance of the bucket.
+	ServiceInstanceID *string `locationName:"service_instance_id" type:"string"`
+
+	// The time the bucket was created.
+	TimeCreated *time.Time `locationName:"time_created" type:"timestamp" timestampFormat:"iso8601"`
+
+	// The time the bucket's configuration was last updated.
+	TimeUpdated *time.Time `locationName:"time_updated" type:"timestamp" timestampFormat:"iso8601"`
+}
+
+// String returns the string representation
+func (s BucketConfig) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s BucketConfig) GoString() string {
+	return s.String()
+}
+
+// SetBytesUsed sets the BytesUsed field's value.
+func (s *BucketConfig) SetBytesUsed(v int64) *BucketConfig {
+	s.BytesUsed = &v
+	return s
+}
+
+// SetCRN sets the CRN field's value.
+func (s *BucketConfig) SetCRN(v string) *BucketConfig {
+	s.CRN = &v
+	return s
+}
+
+// SetHardQuota sets the HardQuota field's value.
+func (s *BucketConfig) SetHardQuota(v int64) *BucketConfig {
+	s.HardQuota = &v
+	return s
+}
+
+// SetName sets the Name field's value.
+func (s *BucketConfig) SetName(v string) *BucketConfig {
+	s.Name = &v
+	return s
+}
+
+// SetNoncurrentBytesUsed sets the NoncurrentBytesUsed field's value.
+func (s *BucketConfig) SetNoncurrentBytesUsed(v int64) *BucketConfig {
+	s.NoncurrentBytesUsed = &v
+	return s
+}
+
+// SetNoncurrentObjectCount sets the NoncurrentObjectCount field's value.
+func (s *BucketConfig) SetNoncurrentObjectCount(v int64) *BucketConfig {
+	s.NoncurrentObjectCount = &v
+	return s
+}
+
+// SetObjectCount sets the ObjectCount field's value.
+func (s *BucketConfig) SetObjectCount(v int64) *BucketConfig {
+	s.ObjectCount = &v
+	return s
+}
+
+// SetServiceInstanceCRN sets the ServiceInstanceCRN field's value.
+func (s *BucketConfig) SetServiceInstanceCRN(v string) *BucketConfig {
+	s.ServiceInstanceCRN = &v
+	return s
+}
+
+// SetServiceInstanceID sets the ServiceInstanceID field's value.
+func (s *BucketConfig) SetServiceInstanceID(v string) *BucketConfig {
+	s.ServiceInstanceID = &v
+	return s
+}
+
+// SetTimeCreated sets the TimeCreated field's value.
+func (s *BucketConfig) SetTimeCreated(v time.Time) *BucketConfig {
+	s.TimeCreated = &v
+	return s
+}
+
+// SetTimeUpdated sets the TimeUpdated field's value.
+func (s *BucketConfig) SetTimeUpdated(v time.Time) *BucketConfig {
+	s.TimeUpdated = &v
+	return s
+}
+
+type GetBucketConfigInput struct {
+	_ struct{} `type:"structure"`
+
+	// The name of the bucket.
+	//
+	// Bucket is a required field
+	Bucket *string `location:"uri" locationName:"bucket" type:"string" required:"true"`
+}
+
+// String returns the string representation
+func (s GetBucketConfigInput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s GetBucketConfigInput) GoString() string {
+	return s.String()
+}
+
+// Validate inspects the fields of the type to determine if they are valid.
+func (s *GetBucketConfigInput) Validate() error {
+	invalidParams := request.ErrInvalidParams{Context: "GetBucketConfigInput"}
+	if s.Bucket == nil {
+		invalidParams.Add(request.NewErrParamRequired("Bucket"))
+	}
+	if s.Bucket != nil && len(*s.Bucket) < 1 {
+		invalidParams.Add(request.NewErrParamMinLen("Bucket", 1))
+	}
+
+	if invalidParams.Len() > 0 {
+		return invalidParams
+	}
+	return nil
+}
+
+// SetBucket sets the Bucket field's value.
+func (s *GetBucketConfigInput) SetBucket(v string) *GetBucketConfigInput {
+	s.Bucket = &v
+	return s
+}
+
+type GetBucketConfigOutput struct {
+	_ struct{} `type:"structure" payload:"BucketConfig"`
+
+	// The configuration of the bucket.
+	BucketConfig *BucketConfig `type:"structure"`
+}
+
+// String returns the string representation
+func (s GetBucketConfigOutput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s GetBucketConfigOutput) GoString() string {
+	return s.String()
+}
+
+// SetBucketConfig sets the BucketConfig field's value.
+func (s *GetBucketConfigOutput) SetBucketConfig(v *BucketConfig) *GetBucketConfigOutput {
+	s.BucketConfig = v
+	return s
+}
@@ -0,0 +1,87 @@
+package resourceconfiguration_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/credentialstest"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/resourceconfiguration"
+)
+
+func newTestClient(handler http.HandlerFunc) (*resourceconfiguration.ResourceConfiguration, func()) {
+	server := httptest.NewServer(handler)
+
+	svc := resourceconfiguration.New(unit.Session, &aws.Config{
+		Endpoint:   aws.String(server.URL),
+		MaxRetries: aws.Int(0),
+		Credentials: credentials.NewTypedCredentials(&credentialstest.MockProvider{
+			Value: credentials.Value{SessionToken: "token"},
+		}, "ibm-iam"),
+	})
+
+	return svc, server.Close
+}
+
+func TestGetBucketConfig(t *testing.T) {
+	svc, closeFn := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if e, a := "/v1/b/bucket", r.URL.Path; e != a {
+			t.Errorf("expect %v path, got %v", e, a)
+		}
+		if e, a := "Bearer token", r.Header.Get("Authorization"); e != a {
+			t.Errorf("expect %v authorization, got %v", e, a)
+		}
+		w.Write([]byte(`{"name":"bucket","service_instance_id":"guid","object_count":3,
+			"bytes_used":1024,"hard_quota":4096,"time_created":"2020-01-02T03:04:05.678Z"}`))
+	})
+	defer closeFn()
+
+	out, err := svc.GetBucketConfig(&resourceconfiguration.GetBucketConfigInput{
+		Bucket: aws.String("bucket"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	config := out.BucketConfig
+	if e, a := "guid", aws.StringValue(config.ServiceInstanceID); e != a {
+		t.Errorf("expect %v service instance ID, got %v", e, a)
+	}
+	if e, a := int64(1024), aws.Int64Value(config.BytesUsed); e != a {
+		t.Errorf("expect %v bytes used, got %v", e, a)
+	}
+	if e, a := int64(4096), aws.Int64Value(config.HardQuota); e != a {
+		t.Errorf("expect %v hard quota, got %v", e, a)
+	}
+	if e, a := int64(1577934245), aws.TimeValue(config.TimeCreated).Unix(); e != a {
+		t.Errorf("expect %v time created, got %v", e, a)
+	}
+}
+
+func TestUnmarshalError(t *testing.T) {
+	svc, closeFn := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+		w.Write([]byte(`{"errors":[{"code":"NoSuchBucket","message":"The specified bucket does not exist."}],"trace":"trace-id"}`))
+	})
+	defer closeFn()
+
+	_, err := svc.GetBucketConfig(&resourceconfiguration.GetBucketConfigInput{
+		Bucket: aws.String("bucket"),
+	})
+	aerr, ok := err.(awserr.RequestFailure)
+	if !ok {
+		t.Fatalf("expect request failure, got %v", err)
+	}
+	if e, a := "NoSuchBucket", aerr.Code(); e != a {
+		t.Errorf("expect %v code, got %v", e, a)
+	}
+	if e, a := 404, aerr.StatusCode(); e != a {
+		t.Errorf("expect %v status code, got %v", e, a)
+	}
+	if e, a := "trace-id", aerr.RequestID(); e != a {
+		t.Errorf("expect %v request ID, got %v", e, a)
+	}
+}
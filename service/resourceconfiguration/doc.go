@@ -0,0 +1,25 @@
+// Package resourceconfiguration provides the client and types for making API
+// requests to the IBM Cloud Object Storage Resource Configuration API.
+//
+// The Resource Configuration API reports the configuration and usage of COS
+// buckets, such as their hard quota, and the number of objects and bytes
+// they store, with the same IBM IAM credentials as the COS clients.
+//
+// Using the Client
+//
+// Create a client with the New function, and a session configured with IBM
+// IAM credentials.
+//
+//     svc := resourceconfiguration.New(sess)
+//
+//     out, err := svc.GetBucketConfig(&resourceconfiguration.GetBucketConfigInput{
+//         Bucket: aws.String("bucket"),
+//     })
+//     if err != nil {
+//         return err
+//     }
+//     fmt.Println(aws.Int64Value(out.BucketConfig.BytesUsed), aws.Int64Value(out.BucketConfig.HardQuota))
+//
+// Errors returned by the Resource Configuration API are awserr.RequestFailure
+// values with the error code of the response, such as "NoSuchBucket".
+package resourceconfiguration
@@ -0,0 +1,62 @@
+// Package resourceconfigurationiface provides an interface to enable mocking the
+// IBM COS Resource Configuration client for testing your code.
+package resourceconfigurationiface
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/resourceconfiguration"
+)
+
+// ResourceConfigurationAPI provides an interface to enable mocking the
+// resourceconfiguration.ResourceConfiguration service client's API operation,
+// and paginators. This make unit testing your code that calls out
+// to the SDK's service client's calls easier.
+//
+// The best way to use this interface is so the SDK's service client's calls
+// can be stubbed out for unit testing your code with the SDK without needing
+// to inject custom request handlers into the SDK's request pipeline.
+//
+//    // myFunc uses an SDK service client to make a request to
+//    // the IBM COS Resource Configuration API.
+//    func myFunc(svc resourceconfigurationiface.ResourceConfigurationAPI) bool {
+//        // Make svc.GetBucketConfig request
+//    }
+//
+//    func main() {
+//        sess := session.New()
+//        svc := resourceconfiguration.New(sess)
+//
+//        myFunc(svc)
+//    }
+//
+// In your _test.go file:
+//
+//    // Define a mock struct to be used in your unit tests of myFunc.
+//    type mockResourceConfigurationClient struct {
+//        resourceconfigurationiface.ResourceConfigurationAPI
+//    }
+//    func (m *mockResourceConfigurationClient) GetBucketConfig(input *resourceconfiguration.GetBucketConfigInput) (*resourceconfiguration.GetBucketConfigOutput, error) {
+//        // mock response/functionality
+//    }
+//
+//    func TestMyFunc(t *testing.T) {
+//        // Setup Test
+//        mockSvc := &mockResourceConfigurationClient{}
+//
+//        myfunc(mockSvc)
+//
+//        // Verify myFunc's functionality
+//    }
+//
+// It is important to note that this interface will have breaking changes
+// when API operations are added to the client. Its suggested to use the
+// pattern above for testing, or using tooling to generate mocks to satisfy
+// the interfaces.
+type ResourceConfigurationAPI interface {
+	GetBucketConfig(*resourceconfiguration.GetBucketConfigInput) (*resourceconfiguration.GetBucketConfigOutput, error)
+	GetBucketConfigWithContext(aws.Context, *resourceconfiguration.GetBucketConfigInput, ...request.Option) (*resourceconfiguration.GetBucketConfigOutput, error)
+	GetBucketConfigRequest(*resourceconfiguration.GetBucketConfigInput) (*request.Request, *resourceconfiguration.GetBucketConfigOutput)
+}
+
+var _ ResourceConfigurationAPI = (*resourceconfiguration.ResourceConfiguration)(nil)
@@ -0,0 +1,81 @@
+package resourceconfiguration
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/signer/ibm"
+	"github.com/aws/aws-sdk-go/private/protocol/restjson"
+)
+
+// ResourceConfiguration provides the API operation methods for making requests
+// to the IBM Cloud Object Storage Resource Configuration API. See this
+// package's package overview docs for details on the service.
+//
+// ResourceConfiguration methods are safe to use concurrently. It is not safe
+// to modify mutate any of the struct's properties though.
+type ResourceConfiguration struct {
+	*client.Client
+}
+
+// Service information constants
+const (
+	ServiceName = "resource-configuration" // Service endpoint prefix API calls made to.
+	EndpointsID = ServiceName              // Service ID for Regions and Endpoints metadata.
+)
+
+// DefaultEndpoint is the endpoint of the COS Resource Configuration API. The
+// API is a global service, the same endpoint is used for buckets of every
+// region.
+const DefaultEndpoint = "https://config.cloud-object-storage.cloud.ibm.com"
+
+// New creates a new instance of the ResourceConfiguration client with a
+// session. Requests are sent to the DefaultEndpoint, unless an Endpoint is
+// provided with the additional configuration, and are signed with the
+// session's IBM IAM credentials.
+//
+// Example:
+//     sess := session.Must(session.NewSession(&aws.Config{
+//         Credentials: ibmcreds.NewCredentialsClient(apiKey, "", ""),
+//     }))
+//
+//     // Create a ResourceConfiguration client from just a session.
+//     svc := resourceconfiguration.New(sess)
+func New(p client.ConfigProvider, cfgs ...*aws.Config) *ResourceConfiguration {
+	cfg := &aws.Config{Endpoint: aws.String(DefaultEndpoint)}
+
+	c := p.ClientConfig(EndpointsID, append([]*aws.Config{cfg}, cfgs...)...)
+	return newClient(*c.Config, c.Handlers, c.Endpoint, c.SigningRegion, c.SigningName)
+}
+
+// newClient creates, initializes and returns a new service client instance.
+func newClient(cfg aws.Config, handlers request.Handlers, endpoint, signingRegion, signingName string) *ResourceConfiguration {
+	svc := &ResourceConfiguration{
+		Client: client.New(
+			cfg,
+			metadata.ClientInfo{
+				ServiceName:   ServiceName,
+				SigningName:   signingName,
+				SigningRegion: signingRegion,
+				Endpoint:      endpoint,
+				APIVersion:    "v1",
+			},
+			handlers,
+		),
+	}
+
+	// Handlers
+	svc.Handlers.Sign.PushBackNamed(ibm.SignRequestHandler)
+	svc.Handlers.Build.PushBackNamed(restjson.BuildHandler)
+	svc.Handlers.Unmarshal.PushBackNamed(restjson.UnmarshalHandler)
+	svc.Handlers.UnmarshalMeta.PushBackNamed(restjson.UnmarshalMetaHandler)
+	svc.Handlers.UnmarshalError.PushBack(unmarshalError)
+
+	return svc
+}
+
+// newRequest creates a new request for a ResourceConfiguration operation.
+func (c *ResourceConfiguration) newRequest(op *request.Operation, params, data interface{}) *request.Request {
+	return c.NewRequest(op, params, data)
+}
@@ -0,0 +1,1466 @@
+package resourcecontroller
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const opCreateResourceKey = "CreateResourceKey"
+
+// CreateResourceKeyRequest generates a "aws/request.Request" representing the
+// client's request for the CreateResourceKey operation. The "output" return
+// value will be populated with the request's response once the request completes
+// successfully.
+//
+// Use "Send" method on the returned Request to send the API call to the service.
+// the "output" return value is not valid until after Send returns without error.
+//
+// See CreateResourceKey for more information on using the CreateResourceKey
+// API call, and error handling.
+//
+// This method is useful when you want to inject custom logic or configuration
+// into the SDK's request lifecycle. Such as custom headers, or retry logic.
+//
+//
+//    // Example sending a request using the CreateResourceKeyRequest method.
+//    req, resp := client.CreateResourceKeyRequest(params)
+//
+//    err := req.Send()
+//    if err == nil { // resp is now filled
+//        fmt.Println(resp)
+//    }
+func (c *ResourceController) CreateResourceKeyRequest(input *CreateResourceKeyInput) (req *request.Request, output *CreateResourceKeyOutput) {
+	op := &request.Operation{
+		Name:       opCreateResourceKey,
+		HTTPMethod: "POST",
+		HTTPPath:   "/v2/resource_keys",
+	}
+
+	if input == nil {
+		input = &CreateResourceKeyInput{}
+	}
+
+	output = &CreateResourceKeyOutput{}
+	req = c.newRequest(op, input, output)
+	return
+}
+
+// CreateResourceKey API operation for IBM Cloud Resource Controller.
+//
+// Creates a resource key, a service credential, for a resource instance. Set
+// the HMAC parameter to create HMAC credentials for COS service instances.
+//
+// The credentials of the key are only returned by CreateResourceKey and
+// GetResourceKey, and should be stored securely.
+//
+// Returns awserr.Error for service API and SDK errors. Use runtime type assertions
+// with awserr.Error's Code and Message methods to get detailed information about
+// the error.
+func (c *ResourceController) CreateResourceKey(input *CreateResourceKeyInput) (*CreateResourceKeyOutput, error) {
+	req, out := c.CreateResourceKeyRequest(input)
+	return out, req.Send()
+}
+
+// CreateResourceKeyWithContext is the same as CreateResourceKey with the addition of
+// the ability to pass a context and additional request options.
+//
+// See CreateResourceKey for details on how to use this API operation.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (c *ResourceController) CreateResourceKeyWithContext(ctx aws.Context, input *CreateResourceKeyInput, opts ...request.Option) (*CreateResourceKeyOutput, error) {
+	req, out := c.CreateResourceKeyRequest(input)
+	req.SetContext(ctx)
+	req.ApplyOptions(opts...)
+	return out, req.Send()
+}
+
+const opDeleteResourceKey = "DeleteResourceKey"
+
+// DeleteResourceKeyRequest generates a "aws/request.Request" representing the
+// client's request for the DeleteResourceKey operation. The "output" return
+// value will be populated with the request's response once the request completes
+// successfully.
+//
+// Use "Send" method on the returned Request to send the API call to the service.
+// the "output" return value is not valid until after Send returns without error.
+//
+// See DeleteResourceKey for more information on using the DeleteResourceKey
+// API call, and error handling.
+//
+// This method is useful when you want to inject custom logic or configuration
+// into the SDK's request lifecycle. Such as custom headers, or retry logic.
+//
+//
+//    // Example sending a request using the DeleteResourceKeyRequest method.
+//    req, resp := client.DeleteResourceKeyRequest(params)
+//
+//    err := req.Send()
+//    if err == nil { // resp is now filled
+//        fmt.Println(resp)
+//    }
+func (c *ResourceController) DeleteResourceKeyRequest(input *DeleteResourceKeyInput) (req *request.Request, output *DeleteResourceKeyOutput) {
+	op := &request.Operation{
+		Name:       opDeleteResourceKey,
+		HTTPMethod: "DELETE",
+		HTTPPath:   "/v2/resource_keys/{id}",
+	}
+
+	if input == nil {
+		input = &DeleteResourceKeyInput{}
+	}
+
+	output = &DeleteResourceKeyOutput{}
+	req = c.newRequest(op, input, output)
+	return
+}
+
+// DeleteResourceKey API operation for IBM Cloud Resource Controller.
+//
+// Deletes a resource key, revoking its credentials.
+//
+// Returns awserr.Error for service API and SDK errors. Use runtime type assertions
+// with awserr.Error's Code and Message methods to get detailed information about
+// the error.
+func (c *ResourceController) DeleteResourceKey(input *DeleteResourceKeyInput) (*DeleteResourceKeyOutput, error) {
+	req, out := c.DeleteResourceKeyRequest(input)
+	return out, req.Send()
+}
+
+// DeleteResourceKeyWithContext is the same as DeleteResourceKey with the addition of
+// the ability to pass a context and additional request options.
+//
+// See DeleteResourceKey for details on how to use this API operation.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (c *ResourceController) DeleteResourceKeyWithContext(ctx aws.Context, input *DeleteResourceKeyInput, opts ...request.Option) (*DeleteResourceKeyOutput, error) {
+	req, out := c.DeleteResourceKeyRequest(input)
+	req.SetContext(ctx)
+	req.ApplyOptions(opts...)
+	return out, req.Send()
+}
+
+const opGetResourceInstance = "GetResourceInstance"
+
+// GetResourceInstanceRequest generates a "aws/request.Request" representing the
+// client's request for the GetResourceInstance operation. The "output" return
+// value will be populated with the request's response once the request completes
+// successfully.
+//
+// Use "Send" method on the returned Request to send the API call to the service.
+// the "output" return value is not valid until after Send returns without error.
+//
+// See GetResourceInstance for more information on using the GetResourceInstance
+// API call, and error handling.
+//
+// This method is useful when you want to inject custom logic or configuration
+// into the SDK's request lifecycle. Such as custom headers, or retry logic.
+//
+//
+//    // Example sending a request using the GetResourceInstanceRequest method.
+//    req, resp := client.GetResourceInstanceRequest(params)
+//
+//    err := req.Send()
+//    if err == nil { // resp is now filled
+//        fmt.Println(resp)
+//    }
+func (c *ResourceController) GetResourceInstanceRequest(input *GetResourceInstanceInput) (req *request.Request, output *GetResourceInstanceOutput) {
+	op := &request.Operation{
+		Name:       opGetResourceInstance,
+		HTTPMethod: "GET",
+		HTTPPath:   "/v2/resource_instances/{id}",
+	}
+
+	if input == nil {
+		input = &GetResourceInstanceInput{}
+	}
+
+	output = &GetResourceInstanceOutput{}
+	req = c.newRequest(op, input, output)
+	return
+}
+
+// GetResourceInstance API operation for IBM Cloud Resource Controller.
+//
+// Retrieves a resource instance by its GUID or CRN.
+//
+// Returns awserr.Error for service API and SDK errors. Use runtime type assertions
+// with awserr.Error's Code and Message methods to get detailed information about
+// the error.
+func (c *ResourceController) GetResourceInstance(input *GetResourceInstanceInput) (*GetResourceInstanceOutput, error) {
+	req, out := c.GetResourceInstanceRequest(input)
+	return out, req.Send()
+}
+
+// GetResourceInstanceWithContext is the same as GetResourceInstance with the addition of
+// the ability to pass a context and additional request options.
+//
+// See GetResourceInstance for details on how to use this API operation.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (c *ResourceController) GetResourceInstanceWithContext(ctx aws.Context, input *GetResourceInstanceInput, opts ...request.Option) (*GetResourceInstanceOutput, error) {
+	req, out := c.GetResourceInstanceRequest(input)
+	req.SetContext(ctx)
+	req.ApplyOptions(opts...)
+	return out, req.Send()
+}
+
+const opGetResourceKey = "GetResourceKey"
+
+// GetResourceKeyRequest generates a "aws/request.Request" representing the
+// client's request for the GetResourceKey operation. The "output" return
+// value will be populated with the request's response once the request completes
+// successfully.
+//
+// Use "Send" method on the returned Request to send the API call to the service.
+// the "output" return value is not valid until after Send returns without error.
+//
+// See GetResourceKey for more information on using the GetResourceKey
+// API call, and error handling.
+//
+// This method is useful when you want to inject custom logic or configuration
+// into the SDK's request lifecycle. Such as custom headers, or retry logic.
+//
+//
+//    // Example sending a request using the GetResourceKeyRequest method.
+//    req, resp := client.GetResourceKeyRequest(params)
+//
+//    err := req.Send()
+//    if err == nil { // resp is now filled
+//        fmt.Println(resp)
+//    }
+func (c *ResourceController) GetResourceKeyRequest(input *GetResourceKeyInput) (req *request.Request, output *GetResourceKeyOutput) {
+	op := &request.Operation{
+		Name:       opGetResourceKey,
+		HTTPMethod: "GET",
+		HTTPPath:   "/v2/resource_keys/{id}",
+	}
+
+	if input == nil {
+		input = &GetResourceKeyInput{}
+	}
+
+	output = &GetResourceKeyOutput{}
+	req = c.newRequest(op, input, output)
+	return
+}
+
+// GetResourceKey API operation for IBM Cloud Resource Controller.
+//
+// Retrieves a resource key, including its credentials, by its GUID or CRN.
+//
+// Returns awserr.Error for service API and SDK errors. Use runtime type assertions
+// with awserr.Error's Code and Message methods to get detailed information about
+// the error.
+func (c *ResourceController) GetResourceKey(input *GetResourceKeyInput) (*GetResourceKeyOutput, error) {
+	req, out := c.GetResourceKeyRequest(input)
+	return out, req.Send()
+}
+
+// GetResourceKeyWithContext is the same as GetResourceKey with the addition of
+// the ability to pass a context and additional request options.
+//
+// See GetResourceKey for details on how to use this API operation.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (c *ResourceController) GetResourceKeyWithContext(ctx aws.Context, input *GetResourceKeyInput, opts ...request.Option) (*GetResourceKeyOutput, error) {
+	req, out := c.GetResourceKeyRequest(input)
+	req.SetContext(ctx)
+	req.ApplyOptions(opts...)
+	return out, req.Send()
+}
+
+const opListResourceInstances = "ListResourceInstances"
+
+// ListResourceInstancesRequest generates a "aws/request.Request" representing the
+// client's request for the ListResourceInstances operation. The "output" return
+// value will be populated with the request's response once the request completes
+// successfully.
+//
+// Use "Send" method on the returned Request to send the API call to the service.
+// the "output" return value is not valid until after Send returns without error.
+//
+// See ListResourceInstances for more information on using the ListResourceInstances
+// API call, and error handling.
+//
+// This method is useful when you want to inject custom logic or configuration
+// into the SDK's request lifecycle. Such as custom headers, or retry logic.
+//
+//
+//    // Example sending a request using the ListResourceInstancesRequest method.
+//    req, resp := client.ListResourceInstancesRequest(params)
+//
+//    err := req.Send()
+//    if err == nil { // resp is now filled
+//        fmt.Println(resp)
+//    }
+func (c *ResourceController) ListResourceInstancesRequest(input *ListResourceInstancesInput) (req *request.Request, output *ListResourceInstancesOutput) {
+	op := &request.Operation{
+		Name:       opListResourceInstances,
+		HTTPMethod: "GET",
+		HTTPPath:   "/v2/resource_instances",
+		Paginator: &request.Paginator{
+			InputTokens:     []string{"Start"},
+			OutputTokens:    []string{"NextStart"},
+			LimitToken:      "Limit",
+			TruncationToken: "",
+		},
+	}
+
+	if input == nil {
+		input = &ListResourceInstancesInput{}
+	}
+
+	output = &ListResourceInstancesOutput{}
+	req = c.newRequest(op, input, output)
+	return
+}
+
+// ListResourceInstances API operation for IBM Cloud Resource Controller.
+//
+// Lists the resource instances of the account, such as the COS service
+// instances when the ResourceID is COSResourceID.
+//
+// Returns awserr.Error for service API and SDK errors. Use runtime type assertions
+// with awserr.Error's Code and Message methods to get detailed information about
+// the error.
+func (c *ResourceController) ListResourceInstances(input *ListResourceInstancesInput) (*ListResourceInstancesOutput, error) {
+	req, out := c.ListResourceInstancesRequest(input)
+	return out, req.Send()
+}
+
+// ListResourceInstancesWithContext is the same as ListResourceInstances with the addition of
+// the ability to pass a context and additional request options.
+//
+// See ListResourceInstances for details on how to use this API operation.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (c *ResourceController) ListResourceInstancesWithContext(ctx aws.Context, input *ListResourceInstancesInput, opts ...request.Option) (*ListResourceInstancesOutput, error) {
+	req, out := c.ListResourceInstancesRequest(input)
+	req.SetContext(ctx)
+	req.ApplyOptions(opts...)
+	return out, req.Send()
+}
+
+// ListResourceInstancesPages iterates over the pages of a ListResourceInstances operation,
+// calling the "fn" function with the response data for each page. To stop
+// iterating, return false from the fn function.
+//
+// See ListResourceInstances method for more information on how to use this operation.
+//
+// Note: This operation can generate multiple requests to a service.
+//
+//    // Example iterating over at most 3 pages of a ListResourceInstances operation.
+//    pageNum := 0
+//    err := client.ListResourceInstancesPages(params,
+//        func(page *ListResourceInstancesOutput, lastPage bool) bool {
+//            pageNum++
+//            fmt.Println(page)
+//            return pageNum <= 3
+//        })
+//
+func (c *ResourceController) ListResourceInstancesPages(input *ListResourceInstancesInput, fn func(*ListResourceInstancesOutput, bool) bool) error {
+	return c.ListResourceInstancesPagesWithContext(aws.BackgroundContext(), input, fn)
+}
+
+// ListResourceInstancesPagesWithContext same as ListResourceInstancesPages except
+// it takes a Context and allows setting request options on the pages.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (c *ResourceController) ListResourceInstancesPagesWithContext(ctx aws.Context, input *ListResourceInstancesInput, fn func(*ListResourceInstancesOutput, bool) bool, opts ...request.Option) error {
+	p := request.Pagination{
+		NewRequest: func() (*request.Request, error) {
+			var inCpy *ListResourceInstancesInput
+			if input != nil {
+				tmp := *input
+				inCpy = &tmp
+			}
+			req, _ := c.ListResourceInstancesRequest(inCpy)
+			req.SetContext(ctx)
+			req.ApplyOptions(opts...)
+			return req, nil
+		},
+	}
+
+	cont := true
+	for p.Next() && cont {
+		cont = fn(p.Page().(*ListResourceInstancesOutput), !p.HasNextPage())
+	}
+	return p.Err()
+}
+
+const opListResourceKeys = "ListResourceKeys"
+
+// ListResourceKeysRequest generates a "aws/request.Request" representing the
+// client's request for the ListResourceKeys operation. The "output" return
+// value will be populated with the request's response once the request completes
+// successfully.
+//
+// Use "Send" method on the returned Request to send the API call to the service.
+// the "output" return value is not valid until after Send returns without error.
+//
+// See ListResourceKeys for more information on using the ListResourceKeys
+// API call, and error handling.
+//
+// This method is useful when you want to inject custom logic or configuration
+// into the SDK's request lifecycle. Such as custom headers, or retry logic.
+//
+//
+//    // Example sending a request using the ListResourceKeysRequest method.
+//    req, resp := client.ListResourceKeysRequest(params)
+//
+//    err := req.Send()
+//    if err == nil { // resp is now filled
+//        fmt.Println(resp)
+//    }
+func (c *ResourceController) ListResourceKeysRequest(input *ListResourceKeysInput) (req *request.Request, output *ListResourceKeysOutput) {
+	op := &request.Operation{
+		Name:       opListResourceKeys,
+		HTTPMethod: "GET",
+		HTTPPath:   "/v2/resource_keys",
+		Paginator: &request.Paginator{
+			InputTokens:     []string{"Start"},
+			OutputTokens:    []string{"NextStart"},
+			LimitToken:      "Limit",
+			TruncationToken: "",
+		},
+	}
+
+	if input == nil {
+		input = &ListResourceKeysInput{}
+	}
+
+	output = &ListResourceKeysOutput{}
+	req = c.newRequest(op, input, output)
+	return
+}
+
+// ListResourceKeys API operation for IBM Cloud Resource Controller.
+//
+// Lists the resource keys of the account. The credentials of the keys are not
+// listed, use GetResourceKey to retrieve them.
+//
+// Returns awserr.Error for service API and SDK errors. Use runtime type assertions
+// with awserr.Error's Code and Message methods to get detailed information about
+// the error.
+func (c *ResourceController) ListResourceKeys(input *ListResourceKeysInput) (*ListResourceKeysOutput, error) {
+	req, out := c.ListResourceKeysRequest(input)
+	return out, req.Send()
+}
+
+// ListResourceKeysWithContext is the same as ListResourceKeys with the addition of
+// the ability to pass a context and additional request options.
+//
+// See ListResourceKeys for details on how to use this API operation.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (c *ResourceController) ListResourceKeysWithContext(ctx aws.Context, input *ListResourceKeysInput, opts ...request.Option) (*ListResourceKeysOutput, error) {
+	req, out := c.ListResourceKeysRequest(input)
+	req.SetContext(ctx)
+	req.ApplyOptions(opts...)
+	return out, req.Send()
+}
+
+// ListResourceKeysPages iterates over the pages of a ListResourceKeys operation,
+// calling the "fn" function with the response data for each page. To stop
+// iterating, return false from the fn function.
+//
+// See ListResourceKeys method for more information on how to use this operation.
+//
+// Note: This operation can generate multiple requests to a service.
+//
+//    // Example iterating over at most 3 pages of a ListResourceKeys operation.
+//    pageNum := 0
+//    err := client.ListResourceKeysPages(params,
+//        func(page *ListResourceKeysOutput, lastPage bool) bool {
+//            pageNum++
+//            fmt.Println(page)
+//            return pageNum <= 3
+//        })
+//
+func (c *ResourceController) ListResourceKeysPages(input *ListResourceKeysInput, fn func(*ListResourceKeysOutput, bool) bool) error {
+	return c.ListResourceKeysPagesWithContext(aws.BackgroundContext(), input, fn)
+}
+
+// ListResourceKeysPagesWithContext same as ListResourceKeysPages except
+// it takes a Context and allows setting request options on the pages.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (c *ResourceController) ListResourceKeysPagesWithContext(ctx aws.Context, input *ListResourceKeysInput, fn func(*ListResourceKeysOutput, bool) bool, opts ...request.Option) error {
+	p := request.Pagination{
+		NewRequest: func() (*request.Request, error) {
+			var inCpy *ListResourceKeysInput
+			if input != nil {
+				tmp := *input
+				inCpy = &tmp
+			}
+			req, _ := c.ListResourceKeysRequest(inCpy)
+			req.SetContext(ctx)
+			req.ApplyOptions(opts...)
+			return req, nil
+		},
+	}
+
+	cont := true
+	for p.Next() && cont {
+		cont = fn(p.Page().(*ListResourceKeysOutput), !p.HasNextPage())
+	}
+	return p.Err()
+}
+
+type CreateResourceKeyInput struct {
+	_ struct{} `type:"structure"`
+
+	// The name of the resource key.
+	//
+	// Name is a required field
+	Name *string `locationName:"name" type:"string" required:"true"`
+
+	// The parameters of the resource key, such as whether HMAC credentials
+	// are created.
+	Parameters *ResourceKeyParameters `locationName:"parameters" type:"structure"`
+
+	// The CRN or name of the role the credentials of the key are granted
+	// on the resource instance, such as "Writer". Defaults to the Writer
+	// role if not set.
+	Role *string `locationName:"role" type:"string"`
+
+	// The GUID or CRN of the resource instance the key is created for.
+	//
+	// Source is a required field
+	Source *string `locationName:"source" type:"string" required:"true"`
+}
+
+// String returns the string representation
+func (s CreateResourceKeyInput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s CreateResourceKeyInput) GoString() string {
+	return s.String()
+}
+
+// Validate inspects the fields of the type to determine if they are valid.
+func (s *CreateResourceKeyInput) Validate() error {
+	invalidParams := request.ErrInvalidParams{Context: "CreateResourceKeyInput"}
+	if s.Name == nil {
+		invalidParams.Add(request.NewErrParamRequired("Name"))
+	}
+	if s.Source == nil {
+		invalidParams.Add(request.NewErrParamRequired("Source"))
+	}
+
+	if invalidParams.Len() > 0 {
+		return invalidParams
+	}
+	return nil
+}
+
+// SetName sets the Name field's value.
+func (s *CreateResourceKeyInput) SetName(v string) *CreateResourceKeyInput {
+	s.Name = &v
+	return s
+}
+
+// SetParameters sets the Parameters field's value.
+func (s *CreateResourceKeyInput) SetParameters(v *ResourceKeyParameters) *CreateResourceKeyInput {
+	s.Parameters = v
+	return s
+}
+
+// SetRole sets the Role field's value.
+func (s *CreateResourceKeyInput) SetRole(v string) *CreateResourceKeyInput {
+	s.Role = &v
+	return s
+}
+
+// SetSource sets the Source field's value.
+func (s *CreateResourceKeyInput) SetSource(v string) *CreateResourceKeyInput {
+	s.Source = &v
+	return s
+}
+
+type CreateResourceKeyOutput struct {
+	_ struct{} `type:"structure" payload:"ResourceKey"`
+
+	// The resource key created, including its credentials.
+	ResourceKey *ResourceKey `type:"structure"`
+}
+
+// String returns the string representation
+func (s CreateResourceKeyOutput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s CreateResourceKeyOutput) GoString() string {
+	return s.String()
+}
+
+// SetResourceKey sets the ResourceKey field's value.
+func (s *CreateResourceKeyOutput) SetResourceKey(v *ResourceKey) *CreateResourceKeyOutput {
+	s.ResourceKey = v
+	return s
+}
+
+type DeleteResourceKeyInput struct {
+	_ struct{} `type:"structure"`
+
+	// The GUID or CRN of the resource key.
+	//
+	// ID is a required field
+	ID *string `location:"uri" locationName:"id" type:"string" required:"true"`
+}
+
+// String returns the string representation
+func (s DeleteResourceKeyInput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s DeleteResourceKeyInput) GoString() string {
+	return s.String()
+}
+
+// Validate inspects the fields of the type to determine if they are valid.
+func (s *DeleteResourceKeyInput) Validate() error {
+	invalidParams := request.ErrInvalidParams{Context: "DeleteResourceKeyInput"}
+	if s.ID == nil {
+		invalidParams.Add(request.NewErrParamRequired("ID"))
+	}
+
+	if invalidParams.Len() > 0 {
+		return invalidParams
+	}
+	return nil
+}
+
+// SetID sets the ID field's value.
+func (s *DeleteResourceKeyInput) SetID(v string) *DeleteResourceKeyInput {
+	s.ID = &v
+	return s
+}
+
+type DeleteResourceKeyOutput struct {
+	_ struct{} `type:"structure"`
+}
+
+// String returns the string representation
+func (s DeleteResourceKeyOutput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s DeleteResourceKeyOutput) GoString() string {
+	return s.String()
+}
+
+type GetResourceInstanceInput struct {
+	_ struct{} `type:"structure"`
+
+	// The GUID or CRN of the resource instance.
+	//
+	// ID is a required field
+	ID *string `location:"uri" locationName:"id" type:"string" required:"true"`
+}
+
+// String returns the string representation
+func (s GetResourceInstanceInput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s GetResourceInstanceInput) GoString() string {
+	return s.String()
+}
+
+// Validate inspects the fields of the type to determine if they are valid.
+func (s *GetResourceInstanceInput) Validate() error {
+	invalidParams := request.ErrInvalidParams{Context: "GetResourceInstanceInput"}
+	if s.ID == nil {
+		invalidParams.Add(request.NewErrParamRequired("ID"))
+	}
+
+	if invalidParams.Len() > 0 {
+		return invalidParams
+	}
+	return nil
+}
+
+// SetID sets the ID field's value.
+func (s *GetResourceInstanceInput) SetID(v string) *GetResourceInstanceInput {
+	s.ID = &v
+	return s
+}
+
+type GetResourceInstanceOutput struct {
+	_ struct{} `type:"structure" payload:"ResourceInstance"`
+
+	// The resource instance.
+	ResourceInstance *ResourceInstance `type:"structure"`
+}
+
+// String returns the string representation
+func (s GetResourceInstanceOutput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s GetResourceInstanceOutput) GoString() string {
+	return s.String()
+}
+
+// SetResourceInstance sets the ResourceInstance field's value.
+func (s *GetResourceInstanceOutput) SetResourceInstance(v *ResourceInstance) *GetResourceInstanceOutput {
+	s.ResourceInstance = v
+	return s
+}
+
+type GetResourceKeyInput struct {
+	_ struct{} `type:"structure"`
+
+	// The GUID or CRN of the resource key.
+	//
+	// ID is a required field
+	ID *string `location:"uri" locationName:"id" type:"string" required:"true"`
+}
+
+// String returns the string representation
+func (s GetResourceKeyInput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s GetResourceKeyInput) GoString() string {
+	return s.String()
+}
+
+// Validate inspects the fields of the type to determine if they are valid.
+func (s *GetResourceKeyInput) Validate() error {
+	invalidParams := request.ErrInvalidParams{Context: "GetResourceKeyInput"}
+	if s.ID == nil {
+		invalidParams.Add(request.NewErrParamRequired("ID"))
+	}
+
+	if invalidParams.Len() > 0 {
+		return invalidParams
+	}
+	return nil
+}
+
+// SetID sets the ID field's value.
+func (s *GetResourceKeyInput) SetID(v string) *GetResourceKeyInput {
+	s.ID = &v
+	return s
+}
+
+type GetResourceKeyOutput struct {
+	_ struct{} `type:"structure" payload:"ResourceKey"`
+
+	// The resource key, including its credentials.
+	ResourceKey *ResourceKey `type:"structure"`
+}
+
+// String returns the string representation
+func (s GetResourceKeyOutput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s GetResourceKeyOutput) GoString() string {
+	return s.String()
+}
+
+// SetResourceKey sets the ResourceKey field's value.
+func (s *GetResourceKeyOutput) SetResourceKey(v *ResourceKey) *GetResourceKeyOutput {
+	s.ResourceKey = v
+	return s
+}
+
+// HMACKeys are the HMAC credentials of a resource key created with the
+// HMAC parameter, which sign COS requests with the V4 signature.
+type HMACKeys struct {
+	_ struct{} `type:"structure"`
+
+	// The access key ID of the HMAC credentials.
+	AccessKeyID *string `locationName:"access_key_id" type:"string"`
+
+	// The secret access key of the HMAC credentials.
+	SecretAccessKey *string `locationName:"secret_access_key" type:"string"`
+}
+
+// String returns the string representation
+func (s HMACKeys) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s HMACKeys) GoString() string {
+	return s.String()
+}
+
+// SetAccessKeyID sets the AccessKeyID field's value.
+func (s *HMACKeys) SetAccessKeyID(v string) *HMACKeys {
+	s.AccessKeyID = &v
+	return s
+}
+
+// SetSecretAccessKey sets the SecretAccessKey field's value.
+func (s *HMACKeys) SetSecretAccessKey(v string) *HMACKeys {
+	s.SecretAccessKey = &v
+	return s
+}
+
+type ListResourceInstancesInput struct {
+	_ struct{} `type:"structure"`
+
+	// Only lists the resource instance with the GUID.
+	GUID *string `location:"querystring" locationName:"guid" type:"string"`
+
+	// The maximum number of resource instances listed per page.
+	Limit *int64 `location:"querystring" locationName:"limit" type:"integer"`
+
+	// Only lists the resource instances with the name.
+	Name *string `location:"querystring" locationName:"name" type:"string"`
+
+	// Only lists the resource instances of the resource group.
+	ResourceGroupID *string `location:"querystring" locationName:"resource_group_id" type:"string"`
+
+	// Only lists the resource instances of the service, such as COSResourceID.
+	ResourceID *string `location:"querystring" locationName:"resource_id" type:"string"`
+
+	// Only lists the resource instances of the service plan.
+	ResourcePlanID *string `location:"querystring" locationName:"resource_plan_id" type:"string"`
+
+	// The token of the page listed, returned as the NextStart of the previous
+	// page.
+	Start *string `location:"querystring" locationName:"start" type:"string"`
+
+	// Only lists the resource instances of the type, such as "service_instance".
+	Type *string `location:"querystring" locationName:"type" type:"string"`
+}
+
+// String returns the string representation
+func (s ListResourceInstancesInput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s ListResourceInstancesInput) GoString() string {
+	return s.String()
+}
+
+// SetGUID sets the GUID field's value.
+func (s *ListResourceInstancesInput) SetGUID(v string) *ListResourceInstancesInput {
+	s.GUID = &v
+	return s
+}
+
+// SetLimit sets the Limit field's value.
+func (s *ListResourceInstancesInput) SetLimit(v int64) *ListResourceInstancesInput {
+	s.Limit = &v
+	return s
+}
+
+// SetName sets the Name field's value.
+func (s *ListResourceInstancesInput) SetName(v string) *ListResourceInstancesInput {
+	s.Name = &v
+	return s
+}
+
+// SetResourceGroupID sets the ResourceGroupID field's value.
+func (s *ListResourceInstancesInput) SetResourceGroupID(v string) *ListResourceInstancesInput {
+	s.ResourceGroupID = &v
+	return s
+}
+
+// SetResourceID sets the ResourceID field's value.
+func (s *ListResourceInstancesInput) SetResourceID(v string) *ListResourceInstancesInput {
+	s.ResourceID = &v
+	return s
+}
+
+// SetResourcePlanID sets the ResourcePlanID field's value.
+func (s *ListResourceInstancesInput) SetResourcePlanID(v string) *ListResourceInstancesInput {
+	s.ResourcePlanID = &v
+	return s
+}
+
+// SetStart sets the Start field's value.
+func (s *ListResourceInstancesInput) SetStart(v string) *ListResourceInstancesInput {
+	s.Start = &v
+	return s
+}
+
+// SetType sets the Type field's value.
+func (s *ListResourceInstancesInput) SetType(v string) *ListResourceInstancesInput {
+	s.Type = &v
+	return s
+}
+
+type ListResourceInstancesOutput struct {
+	_ struct{} `type:"structure"`
+
+	// The token of the next page, parsed from NextURL. Not set on the last
+	// page.
+	NextStart *string `type:"string"`
+
+	// The URL of the next page. Not set on the last page.
+	NextURL *string `locationName:"next_url" type:"string"`
+
+	// The resource instances of the page.
+	Resources []*ResourceInstance `locationName:"resources" type:"list"`
+
+	// The number of resource instances of the page.
+	RowsCount *int64 `locationName:"rows_count" type:"integer"`
+}
+
+// String returns the string representation
+func (s ListResourceInstancesOutput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s ListResourceInstancesOutput) GoString() string {
+	return s.String()
+}
+
+// SetNextStart sets the NextStart field's value.
+func (s *ListResourceInstancesOutput) SetNextStart(v string) *ListResourceInstancesOutput {
+	s.NextStart = &v
+	return s
+}
+
+// SetNextURL sets the NextURL field's value.
+func (s *ListResourceInstancesOutput) SetNextURL(v string) *ListResourceInstancesOutput {
+	s.NextURL = &v
+	return s
+}
+
+// SetResources sets the Resources field's value.
+func (s *ListResourceInstancesOutput) SetResources(v []*ResourceInstance) *ListResourceInstancesOutput {
+	s.Resources = v
+	return s
+}
+
+// SetRowsCount sets the RowsCount field's value.
+func (s *ListResourceInstancesOutput) SetRowsCount(v int64) *ListResourceInstancesOutput {
+	s.RowsCount = &v
+	return s
+}
+
+type ListResourceKeysInput struct {
+	_ struct{} `type:"structure"`
+
+	// Only lists the resource key with the GUID.
+	GUID *string `location:"querystring" locationName:"guid" type:"string"`
+
+	// The maximum number of resource keys listed per page.
+	Limit *int64 `location:"querystring" locationName:"limit" type:"integer"`
+
+	// Only lists the resource keys with the name.
+	Name *string `location:"querystring" locationName:"name" type:"string"`
+
+	// Only lists the resource keys of the resource group.
+	ResourceGroupID *string `location:"querystring" locationName:"resource_group_id" type:"string"`
+
+	// Only lists the resource keys of the service, such as COSResourceID.
+	ResourceID *string `location:"querystring" locationName:"resource_id" type:"string"`
+
+	// The token of the page listed, returned as the NextStart of the previous
+	// page.
+	Start *string `location:"querystring" locationName:"start" type:"string"`
+}
+
+// String returns the string representation
+func (s ListResourceKeysInput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s ListResourceKeysInput) GoString() string {
+	return s.String()
+}
+
+// SetGUID sets the GUID field's value.
+func (s *ListResourceKeysInput) SetGUID(v string) *ListResourceKeysInput {
+	s.GUID = &v
+	return s
+}
+
+// SetLimit sets the Limit field's value.
+func (s *ListResourceKeysInput) SetLimit(v int64) *ListResourceKeysInput {
+	s.Limit = &v
+	return s
+}
+
+// SetName sets the Name field's value.
+func (s *ListResourceKeysInput) SetName(v string) *ListResourceKeysInput {
+	s.Name = &v
+	return s
+}
+
+// SetResourceGroupID sets the ResourceGroupID field's value.
+func (s *ListResourceKeysInput) SetResourceGroupID(v string) *ListResourceKeysInput {
+	s.ResourceGroupID = &v
+	return s
+}
+
+// SetResourceID sets the ResourceID field's value.
+func (s *ListResourceKeysInput) SetResourceID(v string) *ListResourceKeysInput {
+	s.ResourceID = &v
+	return s
+}
+
+// SetStart sets the Start field's value.
+func (s *ListResourceKeysInput) SetStart(v string) *ListResourceKeysInput {
+	s.Start = &v
+	return s
+}
+
+type ListResourceKeysOutput struct {
+	_ struct{} `type:"structure"`
+
+	// The token of the next page, parsed from NextURL. Not set on the last
+	// page.
+	NextStart *string `type:"string"`
+
+	// The URL of the next page. Not set on the last page.
+	NextURL *string `locationName:"next_url" type:"string"`
+
+	// The resource keys of the page.
+	Resources []*ResourceKey `locationName:"resources" type:"list"`
+
+	// The number of resource keys of the page.
+	RowsCount *int64 `locationName:"rows_count" type:"integer"`
+}
+
+// String returns the string representation
+func (s ListResourceKeysOutput) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s ListResourceKeysOutput) GoString() string {
+	return s.String()
+}
+
+// SetNextStart sets the NextStart field's value.
+func (s *ListResourceKeysOutput) SetNextStart(v string) *ListResourceKeysOutput {
+	s.NextStart = &v
+	return s
+}
+
+// SetNextURL sets the NextURL field's value.
+func (s *ListResourceKeysOutput) SetNextURL(v string) *ListResourceKeysOutput {
+	s.NextURL = &v
+	return s
+}
+
+// SetResources sets the Resources field's value.
+func (s *ListResourceKeysOutput) SetResources(v []*ResourceKey) *ListResourceKeysOutput {
+	s.Resources = v
+	return s
+}
+
+// SetRowsCount sets the RowsCount field's value.
+func (s *ListResourceKeysOutput) SetRowsCount(v int64) *ListResourceKeysOutput {
+	s.RowsCount = &v
+	return s
+}
+
+// ResourceInstance is an instance of an IBM Cloud service, such as a COS
+// service instance.
+type ResourceInstance struct {
+	_ struct{} `type:"structure"`
+
+	// The ID of the account of the resource instance.
+	AccountID *string `locationName:"account_id" type:"string"`
+
+	// The CRN of the resource instance.
+	CRN *string `locationName:"crn" type:"string"`
+
+	// The time the resource instance was created.
+	CreatedAt *time.Time `locationName:"created_at" type:"timestamp" timestampFormat:"iso8601"`
+
+	// The GUID of the resource instance. The GUID is the service instance ID
+	// of COS service instances, sent with ListBuckets and CreateBucket requests.
+	GUID *string `locationName:"guid" type:"string"`
+
+	// The ID of the resource instance, which is its CRN.
+	ID *string `locationName:"id" type:"string"`
+
+	// The name of the resource instance.
+	Name *string `locationName:"name" type:"string"`
+
+	// The region of the resource instance, "global" for COS service instances.
+	RegionID *string `locationName:"region_id" type:"string"`
+
+	// The ID of the resource group of the resource instance.
+	ResourceGroupID *string `locationName:"resource_group_id" type:"string"`
+
+	// The ID of the service of the resource instance.
+	ResourceID *string `locationName:"resource_id" type:"string"`
+
+	// The ID of the service plan of the resource instance.
+	ResourcePlanID *string `locationName:"resource_plan_id" type:"string"`
+
+	// The state of the resource instance, such as "active".
+	State *string `locationName:"state" type:"string"`
+
+	// The type of the resource instance, such as "service_instance".
+	Type *string `locationName:"type" type:"string"`
+
+	// The time the resource instance was last updated.
+	UpdatedAt *time.Time `locationName:"updated_at" type:"timestamp" timestampFormat:"iso8601"`
+}
+
+// String returns the string representation
+func (s ResourceInstance) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s ResourceInstance) GoString() string {
+	return s.String()
+}
+
+// SetAccountID sets the AccountID field's value.
+func (s *ResourceInstance) SetAccountID(v string) *ResourceInstance {
+	s.AccountID = &v
+	return s
+}
+
+// SetCRN sets the CRN field's value.
+func (s *ResourceInstance) SetCRN(v string) *ResourceInstance {
+	s.CRN = &v
+	return s
+}
+
+// SetCreatedAt sets the CreatedAt field's value.
+func (s *ResourceInstance) SetCreatedAt(v time.Time) *ResourceInstance {
+	s.CreatedAt = &v
+	return s
+}
+
+// SetGUID sets the GUID field's value.
+func (s *ResourceInstance) SetGUID(v string) *ResourceInstance {
+	s.GUID = &v
+	return s
+}
+
+// SetID sets the ID field's value.
+func (s *ResourceInstance) SetID(v string) *ResourceInstance {
+	s.ID = &v
+	return s
+}
+
+// SetName sets the Name field's value.
+func (s *ResourceInstance) SetName(v string) *ResourceInstance {
+	s.Name = &v
+	return s
+}
+
+// SetRegionID sets the RegionID field's value.
+func (s *ResourceInstance) SetRegionID(v string) *ResourceInstance {
+	s.RegionID = &v
+	return s
+}
+
+// SetResourceGroupID sets the ResourceGroupID field's value.
+func (s *ResourceInstance) SetResourceGroupID(v string) *ResourceInstance {
+	s.ResourceGroupID = &v
+	return s
+}
+
+// SetResourceID sets the ResourceID field's value.
+func (s *ResourceInstance) SetResourceID(v string) *ResourceInstance {
+	s.ResourceID = &v
+	return s
+}
+
+// SetResourcePlanID sets the ResourcePlanID field's value.
+func (s *ResourceInstance) SetResourcePlanID(v string) *ResourceInstance {
+	s.ResourcePlanID = &v
+	return s
+}
+
+// SetState sets the State field's value.
+func (s *ResourceInstance) SetState(v string) *ResourceInstance {
+	s.State = &v
+	return s
+}
+
+// SetType sets the Type field's value.
+func (s *ResourceInstance) SetType(v string) *ResourceInstance {
+	s.Type = &v
+	return s
+}
+
+// SetUpdatedAt sets the UpdatedAt field's value.
+func (s *ResourceInstance) SetUpdatedAt(v time.Time) *ResourceInstance {
+	s.UpdatedAt = &v
+	return s
+}
+
+// ResourceKey is a service credential of a resource instance.
+type ResourceKey struct {
+	_ struct{} `type:"structure"`
+
+	// The ID of the account of the resource key.
+	AccountID *string `locationName:"account_id" type:"string"`
+
+	// The CRN of the resource key.
+	CRN *string `locationName:"crn" type:"string"`
+
+	// The time the resource key was created.
+	CreatedAt *time.Time `locationName:"created_at" type:"timestamp" timestampFormat:"iso8601"`
+
+	// The credentials of the resource key.
+	Credentials *ResourceKeyCredentials `locationName:"credentials" type:"structure"`
+
+	// The GUID of the resource key.
+	GUID *string `locationName:"guid" type:"string"`
+
+	// The ID of the resource key, which is its CRN.
+	ID *string `locationName:"id" type:"string"`
+
+	// The name of the resource key.
+	Name *string `locationName:"name" type:"string"`
+
+	// The ID of the resource group of the resource key.
+	ResourceGroupID *string `locationName:"resource_group_id" type:"string"`
+
+	// The CRN of the role of the resource key's credentials.
+	Role *string `locationName:"role" type:"string"`
+
+	// The CRN of the resource instance of the resource key.
+	SourceCRN *string `locationName:"source_crn" type:"string"`
+
+	// The state of the resource key, such as "active".
+	State *string `locationName:"state" type:"string"`
+}
+
+// String returns the string representation
+func (s ResourceKey) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s ResourceKey) GoString() string {
+	return s.String()
+}
+
+// SetAccountID sets the AccountID field's value.
+func (s *ResourceKey) SetAccountID(v string) *ResourceKey {
+	s.AccountID = &v
+	return s
+}
+
+// SetCRN sets the CRN field's value.
+func (s *ResourceKey) SetCRN(v string) *ResourceKey {
+	s.CRN = &v
+	return s
+}
+
+// SetCreatedAt sets the CreatedAt field's value.
+func (s *ResourceKey) SetCreatedAt(v time.Time) *ResourceKey {
+	s.CreatedAt = &v
+	return s
+}
+
+// SetCredentials sets the Credentials field's value.
+func (s *ResourceKey) SetCredentials(v *ResourceKeyCredentials) *ResourceKey {
+	s.Credentials = v
+	return s
+}
+
+// SetGUID sets the GUID field's value.
+func (s *ResourceKey) SetGUID(v string) *ResourceKey {
+	s.GUID = &v
+	return s
+}
+
+// SetID sets the ID field's value.
+func (s *ResourceKey) SetID(v string) *ResourceKey {
+	s.ID = &v
+	return s
+}
+
+// SetName sets the Name field's value.
+func (s *ResourceKey) SetName(v string) *ResourceKey {
+	s.Name = &v
+	return s
+}
+
+// SetResourceGroupID sets the ResourceGroupID field's value.
+func (s *ResourceKey) SetResourceGroupID(v string) *ResourceKey {
+	s.ResourceGroupID = &v
+	return s
+}
+
+// SetRole sets the Role field's value.
+func (s *ResourceKey) SetRole(v string) *ResourceKey {
+	s.Role = &v
+	return s
+}
+
+// SetSourceCRN sets the SourceCRN field's value.
+func (s *ResourceKey) SetSourceCRN(v string) *ResourceKey {
+	s.SourceCRN = &v
+	return s
+}
+
+// SetState sets the State field's value.
+func (s *ResourceKey) SetState(v string) *ResourceKey {
+	s.State = &v
+	return s
+}
+
+// ResourceKeyCredentials are the credentials of a resource key.
+type ResourceKeyCredentials struct {
+	_ struct{} `type:"structure"`
+
+	// The API key of the service ID of the credentials, which may be used
+	// with the ibmcreds credentials provider.
+	APIKey *string `locationName:"apikey" type:"string"`
+
+	// The HMAC credentials, if the key was created with the HMAC parameter.
+	COSHMACKeys *HMACKeys `locationName:"cos_hmac_keys" type:"structure"`
+
+	// The URL of the endpoints of the service.
+	Endpoints *string `locationName:"endpoints" type:"string"`
+
+	// The description of the API key.
+	IAMAPIKeyDescription *string `locationName:"iam_apikey_description" type:"string"`
+
+	// The ID of the API key.
+	IAMAPIKeyID *string `locationName:"iam_apikey_id" type:"string"`
+
+	// The name of the API key.
+	IAMAPIKeyName *string `locationName:"iam_apikey_name" type:"string"`
+
+	// The CRN of the role of the credentials.
+	IAMRoleCRN *string `locationName:"iam_role_crn" type:"string"`
+
+	// The CRN of the service ID of the credentials.
+	IAMServiceIDCRN *string `locationName:"iam_serviceid_crn" type:"string"`
+
+	// The CRN of the resource instance of the credentials.
+	ResourceInstanceID *string `locationName:"resource_instance_id" type:"string"`
+}
+
+// String returns the string representation
+func (s ResourceKeyCredentials) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s ResourceKeyCredentials) GoString() string {
+	return s.String()
+}
+
+// SetAPIKey sets the APIKey field's value.
+func (s *ResourceKeyCredentials) SetAPIKey(v string) *ResourceKeyCredentials {
+	s.APIKey = &v
+	return s
+}
+
+// SetCOSHMACKeys sets the COSHMACKeys field's value.
+func (s *ResourceKeyCredentials) SetCOSHMACKeys(v *HMACKeys) *ResourceKeyCredentials {
+	s.COSHMACKeys = v
+	return s
+}
+
+// SetEndpoints sets the Endpoints field's value.
+func (s *ResourceKeyCredentials) SetEndpoints(v string) *ResourceKeyCredentials {
+	s.Endpoints = &v
+	return s
+}
+
+// SetIAMAPIKeyDescription sets the IAMAPIKeyDescription field's value.
+func (s *ResourceKeyCredentials) SetIAMAPIKeyDescription(v string) *ResourceKeyCredentials {
+	s.IAMAPIKeyDescription = &v
+	return s
+}
+
+// SetIAMAPIKeyID sets the IAMAPIKeyID field's value.
+func (s *ResourceKeyCredentials) SetIAMAPIKeyID(v string) *ResourceKeyCredentials {
+	s.IAMAPIKeyID = &v
+	return s
+}
+
+// SetIAMAPIKeyName sets the IAMAPIKeyName field's value.
+func (s *ResourceKeyCredentials) SetIAMAPIKeyName(v string) *ResourceKeyCredentials {
+	s.IAMAPIKeyName = &v
+	return s
+}
+
+// SetIAMRoleCRN sets the IAMRoleCRN field's value.
+func (s *ResourceKeyCredentials) SetIAMRoleCRN(v string) *ResourceKeyCredentials {
+	s.IAMRoleCRN = &v
+	return s
+}
+
+// SetIAMServiceIDCRN sets the IAMServiceIDCRN field's value.
+func (s *ResourceKeyCredentials) SetIAMServiceIDCRN(v string) *ResourceKeyCredentials {
+	s.IAMServiceIDCRN = &v
+	return s
+}
+
+// SetResourceInstanceID sets the ResourceInstanceID field's value.
+func (s *ResourceKeyCredentials) SetResourceInstanceID(v string) *ResourceKeyCredentials {
+	s.ResourceInstanceID = &v
+	return s
+}
+
+// ResourceKeyParameters are the parameters of a resource key.
+type ResourceKeyParameters struct {
+	_ struct{} `type:"structure"`
+
+	// Creates HMAC credentials for the key, in addition to its API key.
+	HMAC *bool `locationName:"HMAC" type:"boolean"`
+}
+
+// String returns the string representation
+func (s ResourceKeyParameters) String() string {
+	return awsutil.Prettify(s)
+}
+
+// GoString returns the string representation
+func (s ResourceKeyParameters) GoString() string {
+	return s.String()
+}
+
+// SetHMAC sets the HMAC field's value.
+func (s *ResourceKeyParameters) SetHMAC(v bool) *ResourceKeyParameters {
+	s.HMAC = &v
+	return s
+}
@@ -0,0 +1,120 @@
+package resourcecontroller
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// COSResourceID is the ResourceID of IBM Cloud Object Storage service
+// instances.
+const COSResourceID = "dff97f5c-bc5e-4455-b470-411c3edbe49c"
+
+// Credentials returns static credentials of the HMAC keys, which may be used
+// to sign COS requests with the V4 signature.
+func (k *HMACKeys) Credentials() *credentials.Credentials {
+	return credentials.NewStaticCredentials(
+		aws.StringValue(k.AccessKeyID), aws.StringValue(k.SecretAccessKey), "")
+}
+
+// setContentType sets the Content-Type of requests with a JSON body.
+func setContentType(r *request.Request) {
+	if r.Body != nil && r.HTTPRequest.Header.Get("Content-Type") == "" {
+		r.HTTPRequest.Header.Set("Content-Type", "application/json")
+	}
+}
+
+// populateNextStart sets the NextStart token of list outputs to the start
+// parameter of their next_url, so the lists can be paginated.
+func populateNextStart(r *request.Request) {
+	var nextURL *string
+	var nextStart **string
+
+	switch out := r.Data.(type) {
+	case *ListResourceInstancesOutput:
+		nextURL, nextStart = out.NextURL, &out.NextStart
+	case *ListResourceKeysOutput:
+		nextURL, nextStart = out.NextURL, &out.NextStart
+	default:
+		return
+	}
+
+	if aws.StringValue(nextURL) == "" {
+		return
+	}
+	u, err := url.Parse(*nextURL)
+	if err != nil {
+		r.Error = awserr.New("SerializationError", "failed to parse next_url", err)
+		return
+	}
+	if start := u.Query().Get("start"); start != "" {
+		*nextStart = aws.String(start)
+	}
+}
+
+// jsonErrorResponse is the error response of the Resource Controller, or of
+// the IBM Cloud platform services in front of it.
+type jsonErrorResponse struct {
+	Code          string `json:"error_code"`
+	Message       string `json:"message"`
+	TransactionID string `json:"transaction_id"`
+	Errors        []struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+	Trace string `json:"trace"`
+}
+
+func unmarshalError(r *request.Request) {
+	defer r.HTTPResponse.Body.Close()
+	defer io.Copy(ioutil.Discard, r.HTTPResponse.Body)
+
+	var errCode, errMsg string
+
+	resp := &jsonErrorResponse{}
+	err := json.NewDecoder(r.HTTPResponse.Body).Decode(resp)
+	if err != nil && err != io.EOF {
+		errCode = "SerializationError"
+		errMsg = "failed to decode Resource Controller JSON error response"
+	} else {
+		errCode, errMsg = resp.Code, resp.Message
+		if len(resp.Errors) > 0 {
+			if errCode == "" {
+				errCode = resp.Errors[0].Code
+			}
+			if errMsg == "" {
+				errMsg = resp.Errors[0].Message
+			}
+		}
+		err = nil
+	}
+
+	// Fallback to status code converted to message if still no error code
+	if len(errCode) == 0 {
+		statusText := http.StatusText(r.HTTPResponse.StatusCode)
+		errCode = strings.Replace(statusText, " ", "", -1)
+		errMsg = statusText
+	}
+
+	requestID := r.RequestID
+	if requestID == "" {
+		requestID = resp.TransactionID
+	}
+	if requestID == "" {
+		requestID = resp.Trace
+	}
+
+	r.Error = awserr.NewRequestFailure(
+		awserr.New(errCode, errMsg, err),
+		r.HTTPResponse.StatusCode,
+		requestID,
+	)
+}
@@ -0,0 +1,72 @@
+// +build go1.7
+
+package resourcecontroller_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/resourcecontroller"
+)
+
+func TestUnmarshalError(t *testing.T) {
+	cases := map[string]struct {
+		Status    int
+		Body      string
+		Code      string
+		Message   string
+		RequestID string
+	}{
+		"resource controller": {
+			Status:    404,
+			Body:      `{"message":"Instance not found","status_code":404,"error_code":"RC-ResourceNotFound","transaction_id":"txn-1"}`,
+			Code:      "RC-ResourceNotFound",
+			Message:   "Instance not found",
+			RequestID: "txn-1",
+		},
+		"platform": {
+			Status:    401,
+			Body:      `{"errors":[{"code":"not_authorized","message":"Unauthorized"}],"trace":"trace-1"}`,
+			Code:      "not_authorized",
+			Message:   "Unauthorized",
+			RequestID: "trace-1",
+		},
+		"no body": {
+			Status:  503,
+			Code:    "ServiceUnavailable",
+			Message: "Service Unavailable",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			svc, closeFn := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(c.Status)
+				w.Write([]byte(c.Body))
+			})
+			defer closeFn()
+
+			_, err := svc.GetResourceInstance(&resourcecontroller.GetResourceInstanceInput{
+				ID: aws.String("guid-1"),
+			})
+			aerr, ok := err.(awserr.RequestFailure)
+			if !ok {
+				t.Fatalf("expect awserr.RequestFailure, got %T %v", err, err)
+			}
+			if e, a := c.Status, aerr.StatusCode(); e != a {
+				t.Errorf("expect %v status code, got %v", e, a)
+			}
+			if e, a := c.Code, aerr.Code(); e != a {
+				t.Errorf("expect %v code, got %v", e, a)
+			}
+			if e, a := c.Message, aerr.Message(); e != a {
+				t.Errorf("expect %v message, got %v", e, a)
+			}
+			if e, a := c.RequestID, aerr.RequestID(); e != a {
+				t.Errorf("expect %v request ID, got %v", e, a)
+			}
+		})
+	}
+}
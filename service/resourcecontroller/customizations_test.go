@@ -0,0 +1,171 @@
+package resourcecontroller_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/credentialstest"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/resourcecontroller"
+)
+
+func newTestClient(handler http.HandlerFunc) (*resourcecontroller.ResourceController, func()) {
+	server := httptest.NewServer(handler)
+
+	svc := resourcecontroller.New(unit.Session, &aws.Config{
+		Endpoint:   aws.String(server.URL),
+		MaxRetries: aws.Int(0),
+		Credentials: credentials.NewTypedCredentials(&credentialstest.MockProvider{
+			Value: credentials.Value{SessionToken: "token"},
+		}, "ibm-iam"),
+	})
+
+	return svc, server.Close
+}
+
+func TestNewEndpoint(t *testing.T) {
+	svc := resourcecontroller.New(unit.Session)
+	if e, a := resourcecontroller.DefaultEndpoint, svc.Endpoint; e != a {
+		t.Errorf("expect %v endpoint, got %v", e, a)
+	}
+
+	svc = resourcecontroller.New(unit.Session, &aws.Config{Endpoint: aws.String("https://example.com")})
+	if e, a := "https://example.com", svc.Endpoint; e != a {
+		t.Errorf("expect %v endpoint, got %v", e, a)
+	}
+}
+
+func TestListResourceInstancesPages(t *testing.T) {
+	var starts []string
+	svc, closeFn := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if e, a := "/v2/resource_instances", r.URL.Path; e != a {
+			t.Errorf("expect %v path, got %v", e, a)
+		}
+		if e, a := resourcecontroller.COSResourceID, r.URL.Query().Get("resource_id"); e != a {
+			t.Errorf("expect %v resource_id, got %v", e, a)
+		}
+		if e, a := "Bearer token", r.Header.Get("Authorization"); e != a {
+			t.Errorf("expect %v authorization, got %v", e, a)
+		}
+
+		start := r.URL.Query().Get("start")
+		starts = append(starts, start)
+		switch start {
+		case "":
+			w.Write([]byte(`{"rows_count":1,"next_url":"/v2/resource_instances?resource_id=x&start=page2",
+				"resources":[{"guid":"guid-1","name":"one","created_at":"2020-01-02T03:04:05.678Z"}]}`))
+		case "page2":
+			w.Write([]byte(`{"rows_count":1,"next_url":null,"resources":[{"guid":"guid-2","name":"two"}]}`))
+		}
+	})
+	defer closeFn()
+
+	var guids []string
+	var created *time.Time
+	err := svc.ListResourceInstancesPages(&resourcecontroller.ListResourceInstancesInput{
+		ResourceID: aws.String(resourcecontroller.COSResourceID),
+	}, func(page *resourcecontroller.ListResourceInstancesOutput, lastPage bool) bool {
+		for _, instance := range page.Resources {
+			guids = append(guids, aws.StringValue(instance.GUID))
+			if created == nil {
+				created = instance.CreatedAt
+			}
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := []string{"guid-1", "guid-2"}, guids; len(e) != len(a) || e[0] != a[0] || e[1] != a[1] {
+		t.Errorf("expect %v instances, got %v", e, a)
+	}
+	if e, a := []string{"", "page2"}, starts; len(e) != len(a) || e[1] != a[1] {
+		t.Errorf("expect %v starts, got %v", e, a)
+	}
+	if e, a := time.Date(2020, 1, 2, 3, 4, 5, 678000000, time.UTC), aws.TimeValue(created); !e.Equal(a) {
+		t.Errorf("expect %v created at, got %v", e, a)
+	}
+}
+
+func TestCreateResourceKey(t *testing.T) {
+	svc, closeFn := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if e, a := "POST", r.Method; e != a {
+			t.Errorf("expect %v method, got %v", e, a)
+		}
+		if e, a := "application/json", r.Header.Get("Content-Type"); e != a {
+			t.Errorf("expect %v content type, got %v", e, a)
+		}
+
+		var body map[string]interface{}
+		b, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(b, &body); err != nil {
+			t.Fatalf("expect JSON body, got %v, %s", err, b)
+		}
+		if e, a := "guid-1", body["source"]; e != a {
+			t.Errorf("expect %v source, got %v", e, a)
+		}
+		if e, a := true, body["parameters"].(map[string]interface{})["HMAC"]; e != a {
+			t.Errorf("expect %v HMAC, got %v", e, a)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"guid":"key-1","name":"uploader","credentials":{"apikey":"secret",
+			"cos_hmac_keys":{"access_key_id":"AKID","secret_access_key":"SECRET"}}}`))
+	})
+	defer closeFn()
+
+	out, err := svc.CreateResourceKey(&resourcecontroller.CreateResourceKeyInput{
+		Name:       aws.String("uploader"),
+		Source:     aws.String("guid-1"),
+		Parameters: &resourcecontroller.ResourceKeyParameters{HMAC: aws.Bool(true)},
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "key-1", aws.StringValue(out.ResourceKey.GUID); e != a {
+		t.Errorf("expect %v key, got %v", e, a)
+	}
+
+	v, err := out.ResourceKey.Credentials.COSHMACKeys.Credentials().Get()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "AKID", v.AccessKeyID; e != a {
+		t.Errorf("expect %v access key ID, got %v", e, a)
+	}
+	if e, a := "SECRET", v.SecretAccessKey; e != a {
+		t.Errorf("expect %v secret access key, got %v", e, a)
+	}
+}
+
+func TestDeleteResourceKey(t *testing.T) {
+	svc, closeFn := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if e, a := "DELETE", r.Method; e != a {
+			t.Errorf("expect %v method, got %v", e, a)
+		}
+		if e, a := "/v2/resource_keys/crn%3Av1%3Abluemix%3Apublic%3Acloud-object-storage%3Aglobal%3Aa%2Facct%3Akey%3A%3A", r.URL.EscapedPath(); e != a {
+			t.Errorf("expect %v path, got %v", e, a)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer closeFn()
+
+	_, err := svc.DeleteResourceKey(&resourcecontroller.DeleteResourceKeyInput{
+		ID: aws.String("crn:v1:bluemix:public:cloud-object-storage:global:a/acct:key::"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	_, err = svc.DeleteResourceKey(&resourcecontroller.DeleteResourceKeyInput{})
+	if err == nil {
+		t.Fatalf("expect error for missing ID, got none")
+	}
+}
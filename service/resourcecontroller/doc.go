@@ -0,0 +1,37 @@
+// Package resourcecontroller provides the client and types for making API
+// requests to the IBM Cloud Resource Controller.
+//
+// The Resource Controller manages the instances of IBM Cloud services, and
+// their service credentials, called resource keys. The client allows COS
+// service instances to be listed, and resource keys, including HMAC
+// credentials, to be created and deleted with the same IBM IAM credentials as
+// the COS clients.
+//
+// Using the Client
+//
+// Create a client with the New function, and a session configured with IBM
+// IAM credentials.
+//
+//     svc := resourcecontroller.New(sess)
+//
+//     // List the COS service instances of the account.
+//     err := svc.ListResourceInstancesPages(&resourcecontroller.ListResourceInstancesInput{
+//         ResourceID: aws.String(resourcecontroller.COSResourceID),
+//     }, func(page *resourcecontroller.ListResourceInstancesOutput, lastPage bool) bool {
+//         for _, instance := range page.Resources {
+//             fmt.Println(aws.StringValue(instance.Name), aws.StringValue(instance.GUID))
+//         }
+//         return true
+//     })
+//
+//     // Create HMAC credentials for a COS service instance.
+//     out, err := svc.CreateResourceKey(&resourcecontroller.CreateResourceKeyInput{
+//         Name:       aws.String("uploader"),
+//         Source:     instance.GUID,
+//         Role:       aws.String("Writer"),
+//         Parameters: &resourcecontroller.ResourceKeyParameters{HMAC: aws.Bool(true)},
+//     })
+//
+// Errors returned by the Resource Controller are awserr.RequestFailure values
+// with the error code of the response, such as "RC-ResourceNotFound".
+package resourcecontroller
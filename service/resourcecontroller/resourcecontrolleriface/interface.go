@@ -0,0 +1,88 @@
+// Package resourcecontrolleriface provides an interface to enable mocking the
+// IBM Cloud Resource Controller client for testing your code.
+package resourcecontrolleriface
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/resourcecontroller"
+)
+
+// ResourceControllerAPI provides an interface to enable mocking the
+// resourcecontroller.ResourceController service client's API operation,
+// and paginators. This make unit testing your code that calls out
+// to the SDK's service client's calls easier.
+//
+// The best way to use this interface is so the SDK's service client's calls
+// can be stubbed out for unit testing your code with the SDK without needing
+// to inject custom request handlers into the SDK's request pipeline.
+//
+//    // myFunc uses an SDK service client to make a request to
+//    // the IBM Cloud Resource Controller.
+//    func myFunc(svc resourcecontrolleriface.ResourceControllerAPI) bool {
+//        // Make svc.ListResourceInstances request
+//    }
+//
+//    func main() {
+//        sess := session.New()
+//        svc := resourcecontroller.New(sess)
+//
+//        myFunc(svc)
+//    }
+//
+// In your _test.go file:
+//
+//    // Define a mock struct to be used in your unit tests of myFunc.
+//    type mockResourceControllerClient struct {
+//        resourcecontrolleriface.ResourceControllerAPI
+//    }
+//    func (m *mockResourceControllerClient) ListResourceInstances(input *resourcecontroller.ListResourceInstancesInput) (*resourcecontroller.ListResourceInstancesOutput, error) {
+//        // mock response/functionality
+//    }
+//
+//    func TestMyFunc(t *testing.T) {
+//        // Setup Test
+//        mockSvc := &mockResourceControllerClient{}
+//
+//        myfunc(mockSvc)
+//
+//        // Verify myFunc's functionality
+//    }
+//
+// It is important to note that this interface will have breaking changes
+// when API operations are added to the client. Its suggested to use the
+// pattern above for testing, or using tooling to generate mocks to satisfy
+// the interfaces.
+type ResourceControllerAPI interface {
+	CreateResourceKey(*resourcecontroller.CreateResourceKeyInput) (*resourcecontroller.CreateResourceKeyOutput, error)
+	CreateResourceKeyWithContext(aws.Context, *resourcecontroller.CreateResourceKeyInput, ...request.Option) (*resourcecontroller.CreateResourceKeyOutput, error)
+	CreateResourceKeyRequest(*resourcecontroller.CreateResourceKeyInput) (*request.Request, *resourcecontroller.CreateResourceKeyOutput)
+
+	DeleteResourceKey(*resourcecontroller.DeleteResourceKeyInput) (*resourcecontroller.DeleteResourceKeyOutput, error)
+	DeleteResourceKeyWithContext(aws.Context, *resourcecontroller.DeleteResourceKeyInput, ...request.Option) (*resourcecontroller.DeleteResourceKeyOutput, error)
+	DeleteResourceKeyRequest(*resourcecontroller.DeleteResourceKeyInput) (*request.Request, *resourcecontroller.DeleteResourceKeyOutput)
+
+	GetResourceInstance(*resourcecontroller.GetResourceInstanceInput) (*resourcecontroller.GetResourceInstanceOutput, error)
+	GetResourceInstanceWithContext(aws.Context, *resourcecontroller.GetResourceInstanceInput, ...request.Option) (*resourcecontroller.GetResourceInstanceOutput, error)
+	GetResourceInstanceRequest(*resourcecontroller.GetResourceInstanceInput) (*request.Request, *resourcecontroller.GetResourceInstanceOutput)
+
+	GetResourceKey(*resourcecontroller.GetResourceKeyInput) (*resourcecontroller.GetResourceKeyOutput, error)
+	GetResourceKeyWithContext(aws.Context, *resourcecontroller.GetResourceKeyInput, ...request.Option) (*resourcecontroller.GetResourceKeyOutput, error)
+	GetResourceKeyRequest(*resourcecontroller.GetResourceKeyInput) (*request.Request, *resourcecontroller.GetResourceKeyOutput)
+
+	ListResourceInstances(*resourcecontroller.ListResourceInstancesInput) (*resourcecontroller.ListResourceInstancesOutput, error)
+	ListResourceInstancesWithContext(aws.Context, *resourcecontroller.ListResourceInstancesInput, ...request.Option) (*resourcecontroller.ListResourceInstancesOutput, error)
+	ListResourceInstancesRequest(*resourcecontroller.ListResourceInstancesInput) (*request.Request, *resourcecontroller.ListResourceInstancesOutput)
+
+	ListResourceInstancesPages(*resourcecontroller.ListResourceInstancesInput, func(*resourcecontroller.ListResourceInstancesOutput, bool) bool) error
+	ListResourceInstancesPagesWithContext(aws.Context, *resourcecontroller.ListResourceInstancesInput, func(*resourcecontroller.ListResourceInstancesOutput, bool) bool, ...request.Option) error
+
+	ListResourceKeys(*resourcecontroller.ListResourceKeysInput) (*resourcecontroller.ListResourceKeysOutput, error)
+	ListResourceKeysWithContext(aws.Context, *resourcecontroller.ListResourceKeysInput, ...request.Option) (*resourcecontroller.ListResourceKeysOutput, error)
+	ListResourceKeysRequest(*resourcecontroller.ListResourceKeysInput) (*request.Request, *resourcecontroller.ListResourceKeysOutput)
+
+	ListResourceKeysPages(*resourcecontroller.ListResourceKeysInput, func(*resourcecontroller.ListResourceKeysOutput, bool) bool) error
+	ListResourceKeysPagesWithContext(aws.Context, *resourcecontroller.ListResourceKeysInput, func(*resourcecontroller.ListResourceKeysOutput, bool) bool, ...request.Option) error
+}
+
+var _ ResourceControllerAPI = (*resourcecontroller.ResourceController)(nil)
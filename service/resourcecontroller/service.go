@@ -0,0 +1,83 @@
+package resourcecontroller
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/signer/ibm"
+	"github.com/aws/aws-sdk-go/private/protocol/restjson"
+)
+
+// ResourceController provides the API operation methods for making requests
+// to the IBM Cloud Resource Controller. See this package's package overview
+// docs for details on the service.
+//
+// ResourceController methods are safe to use concurrently. It is not safe to
+// modify mutate any of the struct's properties though.
+type ResourceController struct {
+	*client.Client
+}
+
+// Service information constants
+const (
+	ServiceName = "resource-controller" // Service endpoint prefix API calls made to.
+	EndpointsID = ServiceName           // Service ID for Regions and Endpoints metadata.
+)
+
+// DefaultEndpoint is the endpoint of the IBM Cloud Resource Controller API.
+// The Resource Controller is a global service, the same endpoint is used for
+// every region.
+const DefaultEndpoint = "https://resource-controller.cloud.ibm.com"
+
+// New creates a new instance of the ResourceController client with a session.
+// Requests are sent to the DefaultEndpoint, unless an Endpoint is provided
+// with the additional configuration, and are signed with the session's IBM
+// IAM credentials.
+//
+// Example:
+//     sess := session.Must(session.NewSession(&aws.Config{
+//         Credentials: ibmcreds.NewCredentialsClient(apiKey, "", ""),
+//     }))
+//
+//     // Create a ResourceController client from just a session.
+//     svc := resourcecontroller.New(sess)
+func New(p client.ConfigProvider, cfgs ...*aws.Config) *ResourceController {
+	cfg := &aws.Config{Endpoint: aws.String(DefaultEndpoint)}
+
+	c := p.ClientConfig(EndpointsID, append([]*aws.Config{cfg}, cfgs...)...)
+	return newClient(*c.Config, c.Handlers, c.Endpoint, c.SigningRegion, c.SigningName)
+}
+
+// newClient creates, initializes and returns a new service client instance.
+func newClient(cfg aws.Config, handlers request.Handlers, endpoint, signingRegion, signingName string) *ResourceController {
+	svc := &ResourceController{
+		Client: client.New(
+			cfg,
+			metadata.ClientInfo{
+				ServiceName:   ServiceName,
+				SigningName:   signingName,
+				SigningRegion: signingRegion,
+				Endpoint:      endpoint,
+				APIVersion:    "v2",
+			},
+			handlers,
+		),
+	}
+
+	// Handlers
+	svc.Handlers.Sign.PushBackNamed(ibm.SignRequestHandler)
+	svc.Handlers.Build.PushBackNamed(restjson.BuildHandler)
+	svc.Handlers.Build.PushBack(setContentType)
+	svc.Handlers.Unmarshal.PushBackNamed(restjson.UnmarshalHandler)
+	svc.Handlers.Unmarshal.PushBack(populateNextStart)
+	svc.Handlers.UnmarshalMeta.PushBackNamed(restjson.UnmarshalMetaHandler)
+	svc.Handlers.UnmarshalError.PushBack(unmarshalError)
+
+	return svc
+}
+
+// newRequest creates a new request for a ResourceController operation.
+func (c *ResourceController) newRequest(op *request.Operation, params, data interface{}) *request.Request {
+	return c.NewRequest(op, params, data)
+}
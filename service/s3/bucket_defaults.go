@@ -0,0 +1,215 @@
+package s3
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const bucketDefaultsHandlerName = "awssdk.s3.BucketDefaults"
+
+// BucketDefaultParams are the default parameters of the calls for a bucket.
+// A default is only applied to the parameter of a call if the call does not
+// set the parameter.
+type BucketDefaultParams struct {
+	// ACL is the canned ACL of the objects written by PutObject, CopyObject,
+	// and CreateMultipartUpload calls. It is not applied to calls which set
+	// one of the Grant parameters.
+	ACL *string
+
+	// StorageClass of the objects written by PutObject, CopyObject, and
+	// CreateMultipartUpload calls.
+	StorageClass *string
+
+	// ServerSideEncryption and SSEKMSKeyId of the objects written by
+	// PutObject, CopyObject, and CreateMultipartUpload calls.
+	ServerSideEncryption *string
+	SSEKMSKeyId          *string
+
+	// Metadata entries added to the metadata of the objects written by
+	// PutObject and CreateMultipartUpload calls, and CopyObject calls
+	// replacing the metadata of the object copied. Entries of the call's
+	// metadata take precedence over the defaults.
+	Metadata map[string]*string
+
+	// IBMSSEKPCustomerRootKeyCrn and IBMSSEKPEncryptionAlgorithm of the
+	// bucket created by a CreateBucket call.
+	IBMSSEKPCustomerRootKeyCrn  *string
+	IBMSSEKPEncryptionAlgorithm *string
+}
+
+// BucketDefaults applies default parameters to the calls for buckets, before
+// the calls are validated, so that policies such as a bucket's storage class
+// or encryption are set in one place instead of at every call site.
+//
+// BucketDefaults are opt-in. Add them to a client's handlers with
+// AddHandlers, or to a single call with the WithBucketDefaults request
+// option.
+//
+//     defaults := s3.NewBucketDefaults()
+//     defaults.Set("logs", s3.BucketDefaultParams{
+//         StorageClass: aws.String(s3.StorageClassStandardIa),
+//         Metadata:     map[string]*string{"team": aws.String("storage")},
+//     })
+//     defaults.AddHandlers(&svc.Handlers)
+//
+// The input parameters of the calls are not modified; defaults are applied
+// to a copy of the input.
+//
+// BucketDefaults are safe to use concurrently, and may be shared by
+// multiple clients.
+type BucketDefaults struct {
+	mu      sync.RWMutex
+	buckets map[string]BucketDefaultParams
+}
+
+// NewBucketDefaults returns BucketDefaults without defaults for any bucket.
+func NewBucketDefaults() *BucketDefaults {
+	return &BucketDefaults{}
+}
+
+// Set sets the default parameters of the calls for the bucket, replacing its
+// previous defaults.
+func (d *BucketDefaults) Set(bucket string, params BucketDefaultParams) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.buckets == nil {
+		d.buckets = map[string]BucketDefaultParams{}
+	}
+	d.buckets[bucket] = params
+}
+
+// Get returns the default parameters of the calls for the bucket, and
+// whether the bucket has defaults.
+func (d *BucketDefaults) Get(bucket string) (BucketDefaultParams, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	params, ok := d.buckets[bucket]
+	return params, ok
+}
+
+// Remove removes the default parameters of the calls for the bucket.
+func (d *BucketDefaults) Remove(bucket string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.buckets, bucket)
+}
+
+// AddHandlers adds the handler applying the defaults to the handlers
+// provided, such as a client's handlers. The handler runs before the
+// parameters are validated. Adding the handlers multiple times has no
+// additional effect.
+func (d *BucketDefaults) AddHandlers(handlers *request.Handlers) {
+	handlers.Validate.Remove(request.NamedHandler{Name: bucketDefaultsHandlerName})
+	handlers.Validate.PushFrontNamed(request.NamedHandler{
+		Name: bucketDefaultsHandlerName, Fn: d.apply,
+	})
+}
+
+// WithBucketDefaults is a request option which will apply the defaults of the
+// call's bucket to its parameters.
+//
+//     _, err := svc.PutObjectWithContext(ctx, &s3.PutObjectInput{
+//         Bucket: aws.String("logs"),
+//         Key:    aws.String(key),
+//         Body:   body,
+//     }, s3.WithBucketDefaults(defaults))
+func WithBucketDefaults(defaults *BucketDefaults) request.Option {
+	return func(r *request.Request) {
+		defaults.AddHandlers(&r.Handlers)
+	}
+}
+
+// apply replaces the parameters of the request with a copy with the
+// defaults of its bucket applied.
+func (d *BucketDefaults) apply(r *request.Request) {
+	if !r.ParamsFilled() {
+		return
+	}
+	in, ok := r.Params.(bucketGetter)
+	if !ok {
+		return
+	}
+	params, ok := d.Get(in.getBucket())
+	if !ok {
+		return
+	}
+
+	switch in := r.Params.(type) {
+	case *PutObjectInput:
+		cp := *in
+		if !hasGrants(cp.GrantFullControl, cp.GrantRead, cp.GrantReadACP, cp.GrantWriteACP) {
+			setDefault(&cp.ACL, params.ACL)
+		}
+		setDefault(&cp.StorageClass, params.StorageClass)
+		setDefault(&cp.ServerSideEncryption, params.ServerSideEncryption)
+		setDefault(&cp.SSEKMSKeyId, params.SSEKMSKeyId)
+		cp.Metadata = mergeDefaultMetadata(cp.Metadata, params.Metadata)
+		r.Params = &cp
+	case *CopyObjectInput:
+		cp := *in
+		if !hasGrants(cp.GrantFullControl, cp.GrantRead, cp.GrantReadACP, cp.GrantWriteACP) {
+			setDefault(&cp.ACL, params.ACL)
+		}
+		setDefault(&cp.StorageClass, params.StorageClass)
+		setDefault(&cp.ServerSideEncryption, params.ServerSideEncryption)
+		setDefault(&cp.SSEKMSKeyId, params.SSEKMSKeyId)
+		if aws.StringValue(cp.MetadataDirective) == MetadataDirectiveReplace {
+			cp.Metadata = mergeDefaultMetadata(cp.Metadata, params.Metadata)
+		}
+		r.Params = &cp
+	case *CreateMultipartUploadInput:
+		cp := *in
+		if !hasGrants(cp.GrantFullControl, cp.GrantRead, cp.GrantReadACP, cp.GrantWriteACP) {
+			setDefault(&cp.ACL, params.ACL)
+		}
+		setDefault(&cp.StorageClass, params.StorageClass)
+		setDefault(&cp.ServerSideEncryption, params.ServerSideEncryption)
+		setDefault(&cp.SSEKMSKeyId, params.SSEKMSKeyId)
+		cp.Metadata = mergeDefaultMetadata(cp.Metadata, params.Metadata)
+		r.Params = &cp
+	case *CreateBucketInput:
+		cp := *in
+		setDefault(&cp.IBMSSEKPCustomerRootKeyCrn, params.IBMSSEKPCustomerRootKeyCrn)
+		setDefault(&cp.IBMSSEKPEncryptionAlgorithm, params.IBMSSEKPEncryptionAlgorithm)
+		r.Params = &cp
+	}
+}
+
+// setDefault sets the parameter to the default, if the parameter is not set.
+func setDefault(param **string, v *string) {
+	if *param == nil && v != nil {
+		*param = aws.String(*v)
+	}
+}
+
+// hasGrants returns true if one of the grant parameters is set.
+func hasGrants(grants ...*string) bool {
+	for _, g := range grants {
+		if g != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeDefaultMetadata returns a copy of the metadata with the default
+// entries it does not have added.
+func mergeDefaultMetadata(metadata, defaults map[string]*string) map[string]*string {
+	if len(defaults) == 0 {
+		return metadata
+	}
+
+	merged := make(map[string]*string, len(metadata)+len(defaults))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range metadata {
+		merged[k] = v
+	}
+	return merged
+}
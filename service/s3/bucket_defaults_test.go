@@ -0,0 +1,132 @@
+package s3_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func newBucketDefaultsTestSvc(headers *[]http.Header) *s3.S3 {
+	svc := s3.New(unit.Session, &aws.Config{MaxRetries: aws.Int(0)})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		*headers = append(*headers, r.HTTPRequest.Header)
+		r.HTTPResponse = &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+		}
+	})
+	return svc
+}
+
+func TestBucketDefaults(t *testing.T) {
+	var headers []http.Header
+	svc := newBucketDefaultsTestSvc(&headers)
+
+	defaults := s3.NewBucketDefaults()
+	defaults.Set("bucket", s3.BucketDefaultParams{
+		ACL:          aws.String(s3.ObjectCannedACLPublicRead),
+		StorageClass: aws.String(s3.StorageClassStandardIa),
+		Metadata:     map[string]*string{"team": aws.String("storage"), "env": aws.String("prod")},
+	})
+	defaults.AddHandlers(&svc.Handlers)
+	defaults.AddHandlers(&svc.Handlers)
+
+	in := &s3.PutObjectInput{
+		Bucket:   aws.String("bucket"),
+		Key:      aws.String("key"),
+		Metadata: map[string]*string{"env": aws.String("dev")},
+	}
+	if _, err := svc.PutObject(in); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	h := headers[0]
+	if e, a := "public-read", h.Get("X-Amz-Acl"); e != a {
+		t.Errorf("expect %v ACL, got %v", e, a)
+	}
+	if e, a := "STANDARD_IA", h.Get("X-Amz-Storage-Class"); e != a {
+		t.Errorf("expect %v storage class, got %v", e, a)
+	}
+	if e, a := "storage", h.Get("X-Amz-Meta-Team"); e != a {
+		t.Errorf("expect %v team metadata, got %v", e, a)
+	}
+	if e, a := "dev", h.Get("X-Amz-Meta-Env"); e != a {
+		t.Errorf("expect %v env metadata, got %v", e, a)
+	}
+	if in.ACL != nil || in.StorageClass != nil || len(in.Metadata) != 1 {
+		t.Errorf("expect input not modified, got %v", in)
+	}
+
+	_, err := svc.PutObject(&s3.PutObjectInput{
+		Bucket:       aws.String("bucket"),
+		Key:          aws.String("key"),
+		StorageClass: aws.String(s3.StorageClassStandard),
+		GrantRead:    aws.String("id=reader"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	h = headers[1]
+	if e, a := "", h.Get("X-Amz-Acl"); e != a {
+		t.Errorf("expect no ACL with grants, got %v", a)
+	}
+	if e, a := "STANDARD", h.Get("X-Amz-Storage-Class"); e != a {
+		t.Errorf("expect %v storage class, got %v", e, a)
+	}
+
+	_, err = svc.CopyObject(&s3.CopyObjectInput{
+		Bucket:     aws.String("bucket"),
+		Key:        aws.String("key"),
+		CopySource: aws.String("bucket/src"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	h = headers[2]
+	if e, a := "STANDARD_IA", h.Get("X-Amz-Storage-Class"); e != a {
+		t.Errorf("expect %v storage class, got %v", e, a)
+	}
+	if e, a := "", h.Get("X-Amz-Meta-Team"); e != a {
+		t.Errorf("expect no metadata without replace directive, got %v", a)
+	}
+
+	if _, err := svc.PutObject(&s3.PutObjectInput{Bucket: aws.String("other"), Key: aws.String("key")}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "", headers[3].Get("X-Amz-Storage-Class"); e != a {
+		t.Errorf("expect no storage class for other bucket, got %v", a)
+	}
+}
+
+func TestWithBucketDefaults_CreateBucket(t *testing.T) {
+	var headers []http.Header
+	svc := newBucketDefaultsTestSvc(&headers)
+
+	defaults := s3.NewBucketDefaults()
+	defaults.Set("bucket", s3.BucketDefaultParams{
+		IBMSSEKPCustomerRootKeyCrn:  aws.String("crn:v1:key"),
+		IBMSSEKPEncryptionAlgorithm: aws.String(s3.IBMSSEKPEncryptionAlgorithmAes256),
+	})
+
+	_, err := svc.CreateBucketWithContext(aws.BackgroundContext(),
+		&s3.CreateBucketInput{Bucket: aws.String("bucket")},
+		s3.WithBucketDefaults(defaults))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "crn:v1:key", headers[0].Get("Ibm-Sse-Kp-Customer-Root-Key-Crn"); e != a {
+		t.Errorf("expect %v root key CRN, got %v", e, a)
+	}
+
+	defaults.Remove("bucket")
+	if _, ok := defaults.Get("bucket"); ok {
+		t.Errorf("expect bucket defaults removed")
+	}
+}
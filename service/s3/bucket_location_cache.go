@@ -0,0 +1,198 @@
+package s3
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const (
+	bucketLocationCacheLookupName     = "awssdk.s3.BucketLocationCache.Lookup"
+	bucketLocationCacheStoreName      = "awssdk.s3.BucketLocationCache.Store"
+	bucketLocationCacheInvalidateName = "awssdk.s3.BucketLocationCache.Invalidate"
+)
+
+// A BucketLocationCache caches the results of GetBucketLocation calls per
+// bucket, so that region routing code can look up a bucket's location
+// without a call to the service for each lookup. A bucket's cached location
+// is invalidated when its TTL elapses, or when a request for the bucket is
+// redirected with a 301 status code, as requests are when the bucket is not
+// in the region of the client.
+//
+// The cache is opt-in. Add it to a client's handlers with AddHandlers, or to
+// a single call with the WithBucketLocationCache request option.
+//
+//     cache := s3.NewBucketLocationCache(time.Hour)
+//     cache.AddHandlers(&svc.Handlers)
+//
+//     // Only the first call is made to the service.
+//     for i := 0; i < 10; i++ {
+//         result, err := svc.GetBucketLocation(&s3.GetBucketLocationInput{
+//             Bucket: aws.String(bucket),
+//         })
+//     }
+//
+// A BucketLocationCache is safe to use concurrently, and may be shared by
+// multiple clients.
+type BucketLocationCache struct {
+	// TTL is the duration a bucket's location is cached for. If TTL is 0 or
+	// less locations are cached until invalidated.
+	TTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]bucketLocationCacheEntry
+}
+
+type bucketLocationCacheEntry struct {
+	location *string
+	expires  time.Time
+}
+
+// NewBucketLocationCache returns a BucketLocationCache caching locations for
+// the TTL provided.
+func NewBucketLocationCache(ttl time.Duration) *BucketLocationCache {
+	return &BucketLocationCache{TTL: ttl}
+}
+
+// Get returns the cached LocationConstraint of the bucket, and whether the
+// bucket's location is cached. The LocationConstraint is nil for buckets
+// without one.
+func (c *BucketLocationCache) Get(bucket string) (*string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[bucket]
+	if !ok {
+		return nil, false
+	}
+	if !e.expires.IsZero() && !time.Now().Before(e.expires) {
+		delete(c.entries, bucket)
+		return nil, false
+	}
+
+	return e.location, true
+}
+
+// Set caches the LocationConstraint of the bucket.
+func (c *BucketLocationCache) Set(bucket string, location *string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = map[string]bucketLocationCacheEntry{}
+	}
+
+	var e bucketLocationCacheEntry
+	if location != nil {
+		e.location = aws.String(*location)
+	}
+	if c.TTL > 0 {
+		e.expires = time.Now().Add(c.TTL)
+	}
+	c.entries[bucket] = e
+}
+
+// Invalidate removes the cached location of the bucket.
+func (c *BucketLocationCache) Invalidate(bucket string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, bucket)
+}
+
+// AddHandlers adds the cache's handlers to the handlers provided, such as a
+// client's handlers, caching the results of the GetBucketLocation calls made
+// with them. Adding the handlers multiple times has no additional effect.
+func (c *BucketLocationCache) AddHandlers(handlers *request.Handlers) {
+	handlers.Validate.Remove(request.NamedHandler{Name: bucketLocationCacheLookupName})
+	handlers.Validate.PushFrontNamed(request.NamedHandler{
+		Name: bucketLocationCacheLookupName, Fn: c.lookup,
+	})
+	handlers.Unmarshal.SetBackNamed(request.NamedHandler{
+		Name: bucketLocationCacheStoreName, Fn: c.store,
+	})
+	handlers.Complete.SetBackNamed(request.NamedHandler{
+		Name: bucketLocationCacheInvalidateName, Fn: c.invalidate,
+	})
+}
+
+// WithBucketLocationCache is a request option which will cache the result of
+// a GetBucketLocation call in the cache provided, or return the bucket's
+// cached location without calling the service.
+//
+//     result, err := svc.GetBucketLocationWithContext(ctx,
+//         &s3.GetBucketLocationInput{
+//             Bucket: aws.String(bucket),
+//         },
+//         s3.WithBucketLocationCache(cache),
+//     )
+func WithBucketLocationCache(cache *BucketLocationCache) request.Option {
+	return func(r *request.Request) {
+		cache.AddHandlers(&r.Handlers)
+	}
+}
+
+// lookup serves GetBucketLocation calls for cached buckets with a response
+// built from the cached location, instead of calling the service. The
+// response is unmarshaled as usual, so that other handlers, such as
+// NormalizeBucketLocationHandler, are applied to the cached location.
+func (c *BucketLocationCache) lookup(r *request.Request) {
+	if r.Operation.Name != opGetBucketLocation || !r.ParamsFilled() {
+		return
+	}
+
+	in := r.Params.(*GetBucketLocationInput)
+	loc, ok := c.Get(aws.StringValue(in.Bucket))
+	if !ok {
+		return
+	}
+
+	var body bytes.Buffer
+	body.WriteString(xml.Header)
+	body.WriteString(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">`)
+	xml.EscapeText(&body, []byte(aws.StringValue(loc)))
+	body.WriteString(`</LocationConstraint>`)
+
+	r.Handlers.Sign.Clear()
+	r.Handlers.Send.Clear()
+	r.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     http.StatusText(http.StatusOK),
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(bytes.NewReader(body.Bytes())),
+		}
+	})
+	r.Handlers.Unmarshal.Remove(request.NamedHandler{Name: bucketLocationCacheStoreName})
+}
+
+// store caches the location of successful GetBucketLocation calls.
+func (c *BucketLocationCache) store(r *request.Request) {
+	if r.Operation.Name != opGetBucketLocation || r.Error != nil ||
+		!r.ParamsFilled() || !r.DataFilled() {
+		return
+	}
+
+	in := r.Params.(*GetBucketLocationInput)
+	out := r.Data.(*GetBucketLocationOutput)
+	c.Set(aws.StringValue(in.Bucket), out.LocationConstraint)
+}
+
+// invalidate removes the cached location of buckets whose requests were
+// redirected.
+func (c *BucketLocationCache) invalidate(r *request.Request) {
+	if r.HTTPResponse == nil || r.HTTPResponse.StatusCode != http.StatusMovedPermanently ||
+		!r.ParamsFilled() {
+		return
+	}
+
+	if in, ok := r.Params.(bucketGetter); ok {
+		c.Invalidate(in.getBucket())
+	}
+}
@@ -0,0 +1,133 @@
+package s3_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func newBucketLocationCacheClient(calls *int, status *int) *s3.S3 {
+	svc := s3.New(unit.Session, &aws.Config{MaxRetries: aws.Int(0)})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		*calls++
+		body := `<?xml version="1.0" encoding="UTF-8"?><LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">EU</LocationConstraint>`
+		if *status != 200 {
+			body = `<Error><Code>PermanentRedirect</Code></Error>`
+		}
+		r.HTTPResponse = &http.Response{
+			StatusCode: *status,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		}
+	})
+	return svc
+}
+
+func TestBucketLocationCache(t *testing.T) {
+	calls, status := 0, 200
+	svc := newBucketLocationCacheClient(&calls, &status)
+
+	cache := s3.NewBucketLocationCache(time.Hour)
+	cache.AddHandlers(&svc.Handlers)
+	cache.AddHandlers(&svc.Handlers)
+
+	for i := 0; i < 3; i++ {
+		resp, err := svc.GetBucketLocation(&s3.GetBucketLocationInput{Bucket: aws.String("bucket")})
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		if e, a := "EU", aws.StringValue(resp.LocationConstraint); e != a {
+			t.Errorf("%d, expect %v location, got %v", i, e, a)
+		}
+	}
+	if e, a := 1, calls; e != a {
+		t.Errorf("expect %v calls, got %v", e, a)
+	}
+
+	resp, err := svc.GetBucketLocationWithContext(aws.BackgroundContext(),
+		&s3.GetBucketLocationInput{Bucket: aws.String("bucket")},
+		s3.WithNormalizeBucketLocation)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "eu-west-1", aws.StringValue(resp.LocationConstraint); e != a {
+		t.Errorf("expect normalized %v location, got %v", e, a)
+	}
+	if e, a := 1, calls; e != a {
+		t.Errorf("expect %v calls, got %v", e, a)
+	}
+
+	if _, err := svc.GetBucketLocation(&s3.GetBucketLocationInput{Bucket: aws.String("other")}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 2, calls; e != a {
+		t.Errorf("expect %v calls, got %v", e, a)
+	}
+}
+
+func TestBucketLocationCache_InvalidateOnRedirect(t *testing.T) {
+	calls, status := 0, 200
+	svc := newBucketLocationCacheClient(&calls, &status)
+
+	cache := s3.NewBucketLocationCache(0)
+	cache.AddHandlers(&svc.Handlers)
+
+	if _, err := svc.GetBucketLocation(&s3.GetBucketLocationInput{Bucket: aws.String("bucket")}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if _, ok := cache.Get("bucket"); !ok {
+		t.Fatalf("expect bucket location to be cached")
+	}
+
+	status = 301
+	if _, err := svc.HeadObject(&s3.HeadObjectInput{Bucket: aws.String("bucket"), Key: aws.String("key")}); err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if _, ok := cache.Get("bucket"); ok {
+		t.Errorf("expect bucket location to be invalidated")
+	}
+}
+
+func TestBucketLocationCache_TTL(t *testing.T) {
+	cache := s3.NewBucketLocationCache(10 * time.Millisecond)
+	cache.Set("bucket", nil)
+
+	loc, ok := cache.Get("bucket")
+	if !ok {
+		t.Fatalf("expect bucket location to be cached")
+	}
+	if loc != nil {
+		t.Errorf("expect nil location, got %v", *loc)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := cache.Get("bucket"); ok {
+		t.Errorf("expect bucket location to expire")
+	}
+}
+
+func TestWithBucketLocationCache(t *testing.T) {
+	calls, status := 0, 200
+	svc := newBucketLocationCacheClient(&calls, &status)
+	cache := s3.NewBucketLocationCache(time.Hour)
+
+	for i := 0; i < 2; i++ {
+		_, err := svc.GetBucketLocationWithContext(aws.BackgroundContext(),
+			&s3.GetBucketLocationInput{Bucket: aws.String("bucket")},
+			s3.WithBucketLocationCache(cache))
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+	}
+	if e, a := 1, calls; e != a {
+		t.Errorf("expect %v calls, got %v", e, a)
+	}
+}
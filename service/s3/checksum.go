@@ -0,0 +1,308 @@
+package s3
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// Checksum algorithms supported by the aws.Config S3ChecksumAlgorithm value.
+const (
+	// ChecksumAlgorithmCRC32C is the CRC-32 checksum using the Castagnoli
+	// polynomial.
+	ChecksumAlgorithmCRC32C = "CRC32C"
+
+	// ChecksumAlgorithmCRC32 is the CRC-32 checksum using the IEEE polynomial.
+	ChecksumAlgorithmCRC32 = "CRC32"
+
+	// ChecksumAlgorithmCRC64NVME is the CRC-64 checksum using the NVMe
+	// polynomial.
+	ChecksumAlgorithmCRC64NVME = "CRC64NVME"
+)
+
+// checksumAlgorithmETag is the algorithm of ChecksumMismatchErrors of bodies
+// validated against the object's ETag.
+const checksumAlgorithmETag = "ETag"
+
+const (
+	// ErrCodeChecksumMismatch is the error code returned when a checksum
+	// computed by the SDK does not match the checksum returned by the service.
+	ErrCodeChecksumMismatch = "ChecksumMismatch"
+
+	// ErrCodeInvalidChecksumAlgorithm is the error code returned when the
+	// aws.Config S3ChecksumAlgorithm value is not a supported algorithm.
+	ErrCodeInvalidChecksumAlgorithm = "InvalidChecksumAlgorithm"
+)
+
+const (
+	checksumHeaderPrefix       = "X-Amz-Checksum-"
+	checksumAlgorithmHeader    = "X-Amz-Sdk-Checksum-Algorithm"
+	checksumModeHeader         = "X-Amz-Checksum-Mode"
+	checksumModeEnabled        = "ENABLED"
+	checksumCompositeSeparator = "-"
+)
+
+// responseChecksumAlgorithms are the algorithms GetObject responses are
+// validated with, in order of preference.
+var responseChecksumAlgorithms = []string{
+	ChecksumAlgorithmCRC64NVME,
+	ChecksumAlgorithmCRC32C,
+	ChecksumAlgorithmCRC32,
+}
+
+var (
+	crc32cTable    = crc32.MakeTable(crc32.Castagnoli)
+	crc64NVMETable = crc64.MakeTable(0x9a6c9329ac4bc9b5)
+)
+
+// newChecksumHash returns a hash for the checksum algorithm, or false if the
+// algorithm is not supported.
+func newChecksumHash(algorithm string) (hash.Hash, bool) {
+	switch strings.ToUpper(algorithm) {
+	case ChecksumAlgorithmCRC32C:
+		return crc32.New(crc32cTable), true
+	case ChecksumAlgorithmCRC32:
+		return crc32.NewIEEE(), true
+	case ChecksumAlgorithmCRC64NVME:
+		return crc64.New(crc64NVMETable), true
+	default:
+		return nil, false
+	}
+}
+
+func checksumHeader(algorithm string) string {
+	return checksumHeaderPrefix + strings.ToLower(algorithm)
+}
+
+// computeBodyChecksum computes the checksum of the request body using the
+// aws.Config S3ChecksumAlgorithm, and sets the checksum request headers.
+func computeBodyChecksum(r *request.Request) {
+	algorithm := strings.ToUpper(aws.StringValue(r.Config.S3ChecksumAlgorithm))
+	if len(algorithm) == 0 {
+		return
+	}
+	// The body of presigned requests is sent by the holder of the URL.
+	if r.ExpireTime != 0 {
+		return
+	}
+
+	h, ok := newChecksumHash(algorithm)
+	if !ok {
+		r.Error = awserr.New(ErrCodeInvalidChecksumAlgorithm,
+			"unsupported checksum algorithm, "+algorithm, nil)
+		return
+	}
+
+	if r.Body != nil {
+		// hash the body, seeking back to the start of the body after reading
+		// to reset it for transmission.
+		if _, err := r.Body.Seek(r.BodyStart, 0); err != nil { // io.SeekStart
+			r.Error = awserr.New("ComputeChecksum", "failed to seek body", err)
+			return
+		}
+		if _, err := io.Copy(h, r.Body); err != nil {
+			r.Error = awserr.New("ComputeChecksum", "failed to read body", err)
+			return
+		}
+		if _, err := r.Body.Seek(r.BodyStart, 0); err != nil { // io.SeekStart
+			r.Error = awserr.New("ComputeChecksum", "failed to seek body", err)
+			return
+		}
+	}
+
+	r.HTTPRequest.Header.Set(checksumAlgorithmHeader, algorithm)
+	r.HTTPRequest.Header.Set(checksumHeader(algorithm),
+		base64.StdEncoding.EncodeToString(h.Sum(nil)))
+}
+
+// verifyBodyChecksum verifies the checksum returned by the service matches
+// the checksum computed for the request body. Responses that do not include
+// the checksum are not verified.
+func verifyBodyChecksum(r *request.Request) {
+	algorithm := r.HTTPRequest.Header.Get(checksumAlgorithmHeader)
+	if len(algorithm) == 0 {
+		return
+	}
+
+	header := checksumHeader(algorithm)
+	expect := r.HTTPRequest.Header.Get(header)
+	actual := r.HTTPResponse.Header.Get(header)
+	if len(actual) == 0 || actual == expect {
+		return
+	}
+
+	r.Error = awserr.New(ErrCodeChecksumMismatch,
+		"service returned "+algorithm+" checksum "+actual+
+			", does not match computed checksum "+expect, nil)
+}
+
+// A ChecksumMismatchError is returned from the Read method of a GetObject
+// response body, once the body has been read to the end, if the checksum
+// computed of the body does not match the object's checksum, or ETag. The
+// body read should be discarded, as it is not the object stored.
+//
+//     _, err := io.Copy(f, out.Body)
+//     if _, ok := err.(*s3.ChecksumMismatchError); ok {
+//         // The object was corrupted in transit, retry the download.
+//     }
+type ChecksumMismatchError struct {
+	// The algorithm of the checksum, such as "CRC32C", or "ETag" if the
+	// body was validated against the object's ETag.
+	Algorithm string
+
+	// The object's checksum, or ETag.
+	Expected string
+
+	// The checksum computed of the body.
+	Actual string
+}
+
+// Code returns the ErrCodeChecksumMismatch error code.
+func (e *ChecksumMismatchError) Code() string {
+	return ErrCodeChecksumMismatch
+}
+
+// Message returns the error's message.
+func (e *ChecksumMismatchError) Message() string {
+	return fmt.Sprintf("computed %s checksum %s of response body, does not match expected checksum %s",
+		e.Algorithm, e.Actual, e.Expected)
+}
+
+// OrigErr always returns nil.
+func (e *ChecksumMismatchError) OrigErr() error {
+	return nil
+}
+
+// Error returns the string representation of the error.
+func (e *ChecksumMismatchError) Error() string {
+	return awserr.SprintError(e.Code(), e.Message(), "", nil)
+}
+
+// enableResponseChecksum requests the object's checksum be returned with the
+// GetObject response if response checksum validation is enabled.
+func enableResponseChecksum(r *request.Request) {
+	if !aws.BoolValue(r.Config.S3ValidateResponseChecksum) {
+		return
+	}
+
+	r.HTTPRequest.Header.Set(checksumModeHeader, checksumModeEnabled)
+}
+
+// validateResponseChecksum wraps the GetObject response body with a reader
+// that validates the body against the checksum returned by the service.
+func validateResponseChecksum(r *request.Request) {
+	validateChecksum := aws.BoolValue(r.Config.S3ValidateResponseChecksum)
+	validateETag := aws.BoolValue(r.Config.S3ValidateResponseETag)
+	if !validateChecksum && !validateETag {
+		return
+	}
+
+	out, ok := r.Data.(*GetObjectOutput)
+	if !ok || out.Body == nil {
+		return
+	}
+
+	// The checksum of range requests is the checksum of the whole object, and
+	// bodies decompressed by the HTTP transport are not the object stored.
+	resp := r.HTTPResponse
+	if resp.StatusCode == 206 || len(resp.Header.Get("Content-Range")) != 0 || isUncompressed(resp) {
+		return
+	}
+
+	if validateChecksum {
+		for _, algorithm := range responseChecksumAlgorithms {
+			expect := resp.Header.Get(checksumHeader(algorithm))
+			if len(expect) == 0 {
+				continue
+			}
+			if strings.Contains(expect, checksumCompositeSeparator) {
+				// Composite checksums of multipart objects cannot be
+				// validated against the object's body.
+				return
+			}
+
+			h, _ := newChecksumHash(algorithm)
+			out.Body = &checksumValidatingReader{
+				ReadCloser: out.Body,
+				hash:       h,
+				algorithm:  algorithm,
+				expect:     expect,
+				encode:     base64.StdEncoding.EncodeToString,
+			}
+			return
+		}
+	}
+
+	if validateETag {
+		if etag, ok := md5ETag(out); ok {
+			out.Body = &checksumValidatingReader{
+				ReadCloser: out.Body,
+				hash:       md5.New(),
+				algorithm:  checksumAlgorithmETag,
+				expect:     etag,
+				encode:     hex.EncodeToString,
+			}
+		}
+	}
+}
+
+// md5ETag returns the object's ETag, without quotes, if it is the MD5 digest
+// of the object. The ETags of multipart objects, and of objects encrypted
+// with SSE-C or SSE-KMS, are not the digest of the object.
+func md5ETag(out *GetObjectOutput) (string, bool) {
+	if out.SSECustomerAlgorithm != nil ||
+		aws.StringValue(out.ServerSideEncryption) == ServerSideEncryptionAwsKms {
+		return "", false
+	}
+
+	etag := strings.ToLower(strings.Trim(aws.StringValue(out.ETag), `"`))
+	if len(etag) != hex.EncodedLen(md5.Size) {
+		return "", false
+	}
+	if _, err := hex.DecodeString(etag); err != nil {
+		return "", false
+	}
+	return etag, true
+}
+
+// checksumValidatingReader computes the checksum of the body as it is read,
+// returning an error when the end of the body is reached if the checksum does
+// not match the expected value.
+type checksumValidatingReader struct {
+	io.ReadCloser
+
+	hash      hash.Hash
+	algorithm string
+	expect    string
+	encode    func([]byte) string
+}
+
+func (r *checksumValidatingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.hash.Write(p[:n])
+	}
+
+	if err == io.EOF {
+		actual := r.encode(r.hash.Sum(nil))
+		if actual != r.expect {
+			return n, &ChecksumMismatchError{
+				Algorithm: r.algorithm,
+				Expected:  r.expect,
+				Actual:    actual,
+			}
+		}
+	}
+
+	return n, err
+}
@@ -0,0 +1,13 @@
+// +build !go1.7
+
+package s3
+
+import "net/http"
+
+// isUncompressed returns whether the response's body may have been
+// decompressed. Decompressed responses cannot be told apart before Go 1.7,
+// but their Content-Length is removed, so responses without a length are
+// assumed to be decompressed.
+func isUncompressed(resp *http.Response) bool {
+	return resp.ContentLength < 0
+}
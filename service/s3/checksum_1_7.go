@@ -0,0 +1,11 @@
+// +build go1.7
+
+package s3
+
+import "net/http"
+
+// isUncompressed returns whether the response's body was decompressed by
+// the HTTP transport, or the SDK.
+func isUncompressed(resp *http.Response) bool {
+	return resp.Uncompressed
+}
@@ -0,0 +1,261 @@
+package s3_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func checksumBase64(b ...byte) string {
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func TestPutObjectChecksum(t *testing.T) {
+	cases := map[string]struct {
+		Algorithm string
+		Expect    string
+		Header    string
+	}{
+		"CRC32C": {
+			Algorithm: s3.ChecksumAlgorithmCRC32C,
+			Expect:    checksumBase64(0xe3, 0x06, 0x92, 0x83),
+			Header:    "X-Amz-Checksum-Crc32c",
+		},
+		"CRC32": {
+			Algorithm: s3.ChecksumAlgorithmCRC32,
+			Expect:    checksumBase64(0xcb, 0xf4, 0x39, 0x26),
+			Header:    "X-Amz-Checksum-Crc32",
+		},
+		"CRC64NVME": {
+			Algorithm: "crc64nvme",
+			Expect:    checksumBase64(0xae, 0x8b, 0x14, 0x86, 0x0a, 0x79, 0x98, 0x88),
+			Header:    "X-Amz-Checksum-Crc64nvme",
+		},
+	}
+
+	for name, c := range cases {
+		svc := s3.New(unit.Session, aws.NewConfig().WithS3ChecksumAlgorithm(c.Algorithm))
+		req, _ := svc.PutObjectRequest(&s3.PutObjectInput{
+			Bucket: aws.String("bucket"),
+			Key:    aws.String("key"),
+			Body:   bytes.NewReader([]byte("123456789")),
+		})
+		if err := req.Build(); err != nil {
+			t.Fatalf("%s: expect no error, got %v", name, err)
+		}
+
+		if e, a := c.Expect, req.HTTPRequest.Header.Get(c.Header); e != a {
+			t.Errorf("%s: expect %v checksum, got %v", name, e, a)
+		}
+		if e, a := strings.ToUpper(c.Algorithm), req.HTTPRequest.Header.Get("X-Amz-Sdk-Checksum-Algorithm"); e != a {
+			t.Errorf("%s: expect %v algorithm, got %v", name, e, a)
+		}
+
+		b, _ := ioutil.ReadAll(req.HTTPRequest.Body)
+		if e, a := "123456789", string(b); e != a {
+			t.Errorf("%s: expect body to be reset, got %q", name, a)
+		}
+	}
+}
+
+func TestPutObjectChecksum_InvalidAlgorithm(t *testing.T) {
+	svc := s3.New(unit.Session, aws.NewConfig().WithS3ChecksumAlgorithm("SHA0"))
+	req, _ := svc.PutObjectRequest(&s3.PutObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("key"),
+		Body:   bytes.NewReader([]byte("123456789")),
+	})
+
+	err := req.Build()
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := s3.ErrCodeInvalidChecksumAlgorithm, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
+
+func TestUploadPartChecksum_Mismatch(t *testing.T) {
+	cases := map[string]struct {
+		Returned  string
+		ExpectErr bool
+	}{
+		"match":    {Returned: checksumBase64(0xe3, 0x06, 0x92, 0x83)},
+		"mismatch": {Returned: checksumBase64(0, 0, 0, 0), ExpectErr: true},
+		"absent":   {},
+	}
+
+	for name, c := range cases {
+		svc := s3.New(unit.Session, aws.NewConfig().
+			WithS3ChecksumAlgorithm(s3.ChecksumAlgorithmCRC32C))
+		req, _ := svc.UploadPartRequest(&s3.UploadPartInput{
+			Bucket:     aws.String("bucket"),
+			Key:        aws.String("key"),
+			UploadId:   aws.String("upload"),
+			PartNumber: aws.Int64(1),
+			Body:       bytes.NewReader([]byte("123456789")),
+		})
+		req.Handlers.Send.Clear()
+		req.Handlers.Send.PushBack(func(r *request.Request) {
+			r.HTTPResponse = &http.Response{
+				StatusCode: 200,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+			}
+			if len(c.Returned) != 0 {
+				r.HTTPResponse.Header.Set("X-Amz-Checksum-Crc32c", c.Returned)
+			}
+		})
+
+		err := req.Send()
+		if c.ExpectErr {
+			if err == nil {
+				t.Fatalf("%s: expect error, got none", name)
+			}
+			if e, a := s3.ErrCodeChecksumMismatch, err.(awserr.Error).Code(); e != a {
+				t.Errorf("%s: expect %v error code, got %v", name, e, a)
+			}
+		} else if err != nil {
+			t.Errorf("%s: expect no error, got %v", name, err)
+		}
+	}
+}
+
+func TestGetObjectValidateChecksum(t *testing.T) {
+	cases := map[string]struct {
+		StatusCode int
+		Checksum   string
+		ExpectErr  bool
+	}{
+		"valid":     {StatusCode: 200, Checksum: checksumBase64(0xe3, 0x06, 0x92, 0x83)},
+		"invalid":   {StatusCode: 200, Checksum: checksumBase64(0, 0, 0, 0), ExpectErr: true},
+		"composite": {StatusCode: 200, Checksum: checksumBase64(0, 0, 0, 0) + "-2"},
+		"range":     {StatusCode: 206, Checksum: checksumBase64(0, 0, 0, 0)},
+		"absent":    {StatusCode: 200},
+	}
+
+	for name, c := range cases {
+		svc := s3.New(unit.Session, aws.NewConfig().WithS3ValidateResponseChecksum(true))
+		req, out := svc.GetObjectRequest(&s3.GetObjectInput{
+			Bucket: aws.String("bucket"),
+			Key:    aws.String("key"),
+		})
+		req.Handlers.Send.Clear()
+		req.Handlers.Send.PushBack(func(r *request.Request) {
+			if e, a := "ENABLED", r.HTTPRequest.Header.Get("X-Amz-Checksum-Mode"); e != a {
+				t.Errorf("%s: expect %v checksum mode, got %v", name, e, a)
+			}
+			r.HTTPResponse = &http.Response{
+				StatusCode: c.StatusCode,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(strings.NewReader("123456789")),
+			}
+			if len(c.Checksum) != 0 {
+				r.HTTPResponse.Header.Set("X-Amz-Checksum-Crc32c", c.Checksum)
+			}
+		})
+
+		if err := req.Send(); err != nil {
+			t.Fatalf("%s: expect no error, got %v", name, err)
+		}
+
+		b, err := ioutil.ReadAll(out.Body)
+		out.Body.Close()
+		if e, a := "123456789", string(b); e != a {
+			t.Errorf("%s: expect %q body, got %q", name, e, a)
+		}
+		if c.ExpectErr {
+			if err == nil {
+				t.Fatalf("%s: expect error, got none", name)
+			}
+			if e, a := s3.ErrCodeChecksumMismatch, err.(awserr.Error).Code(); e != a {
+				t.Errorf("%s: expect %v error code, got %v", name, e, a)
+			}
+		} else if err != nil {
+			t.Errorf("%s: expect no error, got %v", name, err)
+		}
+	}
+}
+
+func TestGetObjectValidateETag(t *testing.T) {
+	const bodyMD5 = "25f9e794323b453885f5181f1b624d0b"
+
+	cases := map[string]struct {
+		ETag       string
+		Encryption string
+		Checksum   string
+		ExpectErr  bool
+	}{
+		"valid":     {ETag: `"` + bodyMD5 + `"`},
+		"invalid":   {ETag: `"00000000000000000000000000000000"`, ExpectErr: true},
+		"multipart": {ETag: `"00000000000000000000000000000000-2"`},
+		"kms":       {ETag: `"00000000000000000000000000000000"`, Encryption: "aws:kms"},
+		"absent":    {},
+		// The object's checksum, when validated, takes precedence.
+		"checksum": {ETag: `"00000000000000000000000000000000"`, Checksum: checksumBase64(0xe3, 0x06, 0x92, 0x83)},
+	}
+
+	for name, c := range cases {
+		svc := s3.New(unit.Session, &aws.Config{
+			S3ValidateResponseChecksum: aws.Bool(true),
+			S3ValidateResponseETag:     aws.Bool(true),
+		})
+		req, out := svc.GetObjectRequest(&s3.GetObjectInput{
+			Bucket: aws.String("bucket"),
+			Key:    aws.String("key"),
+		})
+		req.Handlers.Send.Clear()
+		req.Handlers.Send.PushBack(func(r *request.Request) {
+			r.HTTPResponse = &http.Response{
+				StatusCode: 200,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(strings.NewReader("123456789")),
+			}
+			if len(c.ETag) != 0 {
+				r.HTTPResponse.Header.Set("ETag", c.ETag)
+			}
+			if len(c.Encryption) != 0 {
+				r.HTTPResponse.Header.Set("X-Amz-Server-Side-Encryption", c.Encryption)
+			}
+			if len(c.Checksum) != 0 {
+				r.HTTPResponse.Header.Set("X-Amz-Checksum-Crc32c", c.Checksum)
+			}
+		})
+
+		if err := req.Send(); err != nil {
+			t.Fatalf("%s: expect no error, got %v", name, err)
+		}
+
+		_, err := ioutil.ReadAll(out.Body)
+		out.Body.Close()
+		if !c.ExpectErr {
+			if err != nil {
+				t.Errorf("%s: expect no error, got %v", name, err)
+			}
+			continue
+		}
+
+		mismatch, ok := err.(*s3.ChecksumMismatchError)
+		if !ok {
+			t.Fatalf("%s: expect ChecksumMismatchError, got %T %v", name, err, err)
+		}
+		if e, a := s3.ErrCodeChecksumMismatch, mismatch.Code(); e != a {
+			t.Errorf("%s: expect %v error code, got %v", name, e, a)
+		}
+		if e, a := "ETag", mismatch.Algorithm; e != a {
+			t.Errorf("%s: expect %v algorithm, got %v", name, e, a)
+		}
+		if e, a := bodyMD5, mismatch.Actual; e != a {
+			t.Errorf("%s: expect %v actual checksum, got %v", name, e, a)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package s3
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const (
+	// ErrCodePreconditionFailed is the error code returned when the object
+	// of a conditional request does not satisfy the request's precondition,
+	// such as an If-Match ETag.
+	ErrCodePreconditionFailed = "PreconditionFailed"
+
+	// ErrCodeNotModified is the error code returned when the object of a
+	// GetObject or HeadObject request with an If-None-Match or
+	// If-Modified-Since precondition has not been modified.
+	ErrCodeNotModified = "NotModified"
+)
+
+// A PreconditionFailedError is returned by conditional requests when the
+// object does not satisfy the request's precondition, such as when the
+// object's ETag does not match the If-Match ETag, or when the object already
+// exists for a PutObjectIfAbsent request.
+type PreconditionFailedError struct {
+	RequestFailure
+}
+
+// A NotModifiedError is returned by conditional GetObject and HeadObject
+// requests when the object has not been modified, such as when the object's
+// ETag matches the If-None-Match ETag.
+type NotModifiedError struct {
+	RequestFailure
+}
+
+// newConditionalError returns the error of a request failing one of its
+// preconditions as a PreconditionFailedError or NotModifiedError, or the
+// error unchanged otherwise.
+func newConditionalError(err RequestFailure) error {
+	switch err.StatusCode() {
+	case http.StatusPreconditionFailed:
+		return PreconditionFailedError{RequestFailure: err}
+	case http.StatusNotModified:
+		return NotModifiedError{RequestFailure: err}
+	default:
+		return err
+	}
+}
+
+// WithIfMatch is a request option making the request conditional on the
+// object's ETag matching the ETag provided. Requests whose object does not
+// match fail with a PreconditionFailedError.
+//
+// The option allows PutObject requests to only replace an object if it has
+// not been replaced since it was read, which PutObjectInput has no field for.
+//
+//     _, err := svc.PutObjectWithContext(ctx, params, s3.WithIfMatch(etag))
+func WithIfMatch(etag string) request.Option {
+	return withHeader("If-Match", etag)
+}
+
+// WithIfNoneMatch is a request option making the request conditional on the
+// object's ETag not matching the ETag provided. An ETag of "*" matches any
+// object, making PutObject requests conditional on the object not existing.
+//
+//     _, err := svc.PutObjectWithContext(ctx, params, s3.WithIfNoneMatch("*"))
+func WithIfNoneMatch(etag string) request.Option {
+	return withHeader("If-None-Match", etag)
+}
+
+// WithIfModifiedSince is a request option making the request conditional on
+// the object having been modified since the time provided.
+func WithIfModifiedSince(t time.Time) request.Option {
+	return withHeader("If-Modified-Since", t.UTC().Format(http.TimeFormat))
+}
+
+// WithIfUnmodifiedSince is a request option making the request conditional
+// on the object not having been modified since the time provided.
+func WithIfUnmodifiedSince(t time.Time) request.Option {
+	return withHeader("If-Unmodified-Since", t.UTC().Format(http.TimeFormat))
+}
+
+// withHeader returns a request option setting the HTTP header of the request.
+func withHeader(name, value string) request.Option {
+	return func(r *request.Request) {
+		r.HTTPRequest.Header.Set(name, value)
+	}
+}
+
+// PutObjectIfAbsent adds an object to a bucket, unless an object already
+// exists with the same key. If the object exists the request fails with a
+// PreconditionFailedError.
+func (c *S3) PutObjectIfAbsent(input *PutObjectInput) (*PutObjectOutput, error) {
+	return c.PutObjectIfAbsentWithContext(aws.BackgroundContext(), input)
+}
+
+// PutObjectIfAbsentWithContext is the same as PutObjectIfAbsent with the
+// addition of the ability to pass a context and additional request options.
+//
+// See PutObjectIfAbsent for details on how to use this API operation.
+func (c *S3) PutObjectIfAbsentWithContext(ctx aws.Context, input *PutObjectInput, opts ...request.Option) (*PutObjectOutput, error) {
+	opts = append(opts[:len(opts):len(opts)], WithIfNoneMatch("*"))
+	return c.PutObjectWithContext(ctx, input, opts...)
+}
+
+// PutObjectIfMatch replaces an object, unless the object's ETag no longer
+// matches the ETag provided, such as the ETag of the object when it was
+// read. If the object has been replaced, or deleted, since, the request fails
+// with a PreconditionFailedError. This allows read-modify-write updates of an
+// object with optimistic concurrency.
+//
+// Example:
+//     obj, err := svc.GetObject(getParams)
+//     ...
+//     putParams.Body = update(obj.Body)
+//     _, err = svc.PutObjectIfMatch(putParams, aws.StringValue(obj.ETag))
+//     if _, ok := err.(s3.PreconditionFailedError); ok {
+//         // The object was modified concurrently, retry the update.
+//     }
+func (c *S3) PutObjectIfMatch(input *PutObjectInput, etag string) (*PutObjectOutput, error) {
+	return c.PutObjectIfMatchWithContext(aws.BackgroundContext(), input, etag)
+}
+
+// PutObjectIfMatchWithContext is the same as PutObjectIfMatch with the
+// addition of the ability to pass a context and additional request options.
+//
+// See PutObjectIfMatch for details on how to use this API operation.
+func (c *S3) PutObjectIfMatchWithContext(ctx aws.Context, input *PutObjectInput, etag string, opts ...request.Option) (*PutObjectOutput, error) {
+	opts = append(opts[:len(opts):len(opts)], WithIfMatch(etag))
+	return c.PutObjectWithContext(ctx, input, opts...)
+}
+
+// GetObjectIfChanged retrieves an object, unless the object's ETag still
+// matches the ETag provided, such as the ETag of a cached copy of the object.
+// The changed value returned is false, with a nil output, if the object has
+// not changed.
+func (c *S3) GetObjectIfChanged(input *GetObjectInput, etag string) (output *GetObjectOutput, changed bool, err error) {
+	return c.GetObjectIfChangedWithContext(aws.BackgroundContext(), input, etag)
+}
+
+// GetObjectIfChangedWithContext is the same as GetObjectIfChanged with the
+// addition of the ability to pass a context and additional request options.
+//
+// See GetObjectIfChanged for details on how to use this API operation.
+func (c *S3) GetObjectIfChangedWithContext(ctx aws.Context, input *GetObjectInput, etag string, opts ...request.Option) (output *GetObjectOutput, changed bool, err error) {
+	in := *input
+	in.IfNoneMatch = aws.String(etag)
+
+	output, err = c.GetObjectWithContext(ctx, &in, opts...)
+	if _, ok := err.(NotModifiedError); ok {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return output, true, nil
+}
+
+// CopyObjectIfSourceMatch copies an object, unless the source object's ETag
+// no longer matches the ETag provided. If the source object has been
+// replaced the request fails with a PreconditionFailedError.
+func (c *S3) CopyObjectIfSourceMatch(input *CopyObjectInput, etag string) (*CopyObjectOutput, error) {
+	return c.CopyObjectIfSourceMatchWithContext(aws.BackgroundContext(), input, etag)
+}
+
+// CopyObjectIfSourceMatchWithContext is the same as CopyObjectIfSourceMatch
+// with the addition of the ability to pass a context and additional request
+// options.
+//
+// See CopyObjectIfSourceMatch for details on how to use this API operation.
+func (c *S3) CopyObjectIfSourceMatchWithContext(ctx aws.Context, input *CopyObjectInput, etag string, opts ...request.Option) (*CopyObjectOutput, error) {
+	in := *input
+	in.CopySourceIfMatch = aws.String(etag)
+
+	return c.CopyObjectWithContext(ctx, &in, opts...)
+}
@@ -0,0 +1,167 @@
+package s3_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const preconditionFailedBody = `<?xml version="1.0" encoding="UTF-8"?>
+<Error><Code>PreconditionFailed</Code><Message>At least one of the pre-conditions you specified did not hold</Message></Error>`
+
+func newConditionalTestSvc(status int, body string) (*s3.S3, *http.Header) {
+	var reqHeader http.Header
+
+	svc := s3.New(unit.Session)
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		reqHeader = r.HTTPRequest.Header
+		r.HTTPResponse = &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Etag": []string{`"abc"`}},
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}
+	})
+
+	return svc, &reqHeader
+}
+
+func TestPutObjectIfAbsent(t *testing.T) {
+	svc, header := newConditionalTestSvc(200, "")
+	input := &s3.PutObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("key"),
+		Body:   bytes.NewReader([]byte("data")),
+	}
+
+	if _, err := svc.PutObjectIfAbsent(input); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "*", header.Get("If-None-Match"); e != a {
+		t.Errorf("expect %q If-None-Match, got %q", e, a)
+	}
+
+	svc, _ = newConditionalTestSvc(412, preconditionFailedBody)
+	_, err := svc.PutObjectIfAbsent(input)
+	aerr, ok := err.(s3.PreconditionFailedError)
+	if !ok {
+		t.Fatalf("expect PreconditionFailedError, got %T %v", err, err)
+	}
+	if e, a := s3.ErrCodePreconditionFailed, aerr.Code(); e != a {
+		t.Errorf("expect %v code, got %v", e, a)
+	}
+	if e, a := 412, aerr.StatusCode(); e != a {
+		t.Errorf("expect %v status code, got %v", e, a)
+	}
+}
+
+func TestPutObjectIfMatch(t *testing.T) {
+	svc, header := newConditionalTestSvc(412, preconditionFailedBody)
+
+	_, err := svc.PutObjectIfMatch(&s3.PutObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("key"),
+		Body:   bytes.NewReader([]byte("data")),
+	}, `"abc"`)
+	if _, ok := err.(s3.PreconditionFailedError); !ok {
+		t.Fatalf("expect PreconditionFailedError, got %T %v", err, err)
+	}
+	if e, a := `"abc"`, header.Get("If-Match"); e != a {
+		t.Errorf("expect %q If-Match, got %q", e, a)
+	}
+}
+
+func TestGetObjectIfChanged(t *testing.T) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("key"),
+	}
+
+	svc, header := newConditionalTestSvc(304, "")
+	out, changed, err := svc.GetObjectIfChanged(input, `"abc"`)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if changed || out != nil {
+		t.Errorf("expect object not changed, got %v, %v", changed, out)
+	}
+	if e, a := `"abc"`, header.Get("If-None-Match"); e != a {
+		t.Errorf("expect %q If-None-Match, got %q", e, a)
+	}
+	if input.IfNoneMatch != nil {
+		t.Errorf("expect input not to be modified")
+	}
+
+	svc, _ = newConditionalTestSvc(200, "data")
+	out, changed, err = svc.GetObjectIfChanged(input, `"def"`)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !changed {
+		t.Errorf("expect object changed")
+	}
+	b, _ := ioutil.ReadAll(out.Body)
+	if e, a := "data", string(b); e != a {
+		t.Errorf("expect %q body, got %q", e, a)
+	}
+}
+
+func TestGetObjectNotModifiedError(t *testing.T) {
+	svc, _ := newConditionalTestSvc(304, "")
+
+	_, err := svc.GetObject(&s3.GetObjectInput{
+		Bucket:      aws.String("bucket"),
+		Key:         aws.String("key"),
+		IfNoneMatch: aws.String(`"abc"`),
+	})
+	aerr, ok := err.(s3.NotModifiedError)
+	if !ok {
+		t.Fatalf("expect NotModifiedError, got %T %v", err, err)
+	}
+	if e, a := s3.ErrCodeNotModified, aerr.Code(); e != a {
+		t.Errorf("expect %v code, got %v", e, a)
+	}
+}
+
+func TestConditionalRequestOptions(t *testing.T) {
+	svc, header := newConditionalTestSvc(200, "")
+	since := time.Date(2017, 10, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	_, err := svc.HeadObjectWithContext(aws.BackgroundContext(), &s3.HeadObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("key"),
+	}, s3.WithIfModifiedSince(since), s3.WithIfUnmodifiedSince(since))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	for _, name := range []string{"If-Modified-Since", "If-Unmodified-Since"} {
+		if e, a := "Sun, 01 Oct 2017 11:00:00 GMT", header.Get(name); e != a {
+			t.Errorf("expect %q %s, got %q", e, name, a)
+		}
+	}
+}
+
+func TestCopyObjectIfSourceMatch(t *testing.T) {
+	svc, header := newConditionalTestSvc(412, preconditionFailedBody)
+
+	_, err := svc.CopyObjectIfSourceMatch(&s3.CopyObjectInput{
+		Bucket:     aws.String("bucket"),
+		Key:        aws.String("key"),
+		CopySource: aws.String("bucket/source"),
+	}, `"abc"`)
+	if _, ok := err.(s3.PreconditionFailedError); !ok {
+		t.Fatalf("expect PreconditionFailedError, got %T %v", err, err)
+	}
+	if e, a := `"abc"`, header.Get("X-Amz-Copy-Source-If-Match"); e != a {
+		t.Errorf("expect %q copy source If-Match, got %q", e, a)
+	}
+}
@@ -0,0 +1,39 @@
+package s3_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// TestWithContextVariants ensures every operation of the client, including
+// the hand written IBM Cloud Object Storage extensions, has a WithContext
+// variant taking the context as its first parameter.
+func TestWithContextVariants(t *testing.T) {
+	typ := reflect.TypeOf(&s3.S3{})
+	errType := reflect.TypeOf((*error)(nil)).Elem()
+	ctxType := reflect.TypeOf((*aws.Context)(nil)).Elem()
+
+	for i := 0; i < typ.NumMethod(); i++ {
+		m := typ.Method(i)
+		if strings.HasSuffix(m.Name, "WithContext") {
+			continue
+		}
+		if n := m.Type.NumOut(); n == 0 || m.Type.Out(n-1) != errType {
+			continue
+		}
+
+		ctxMethod, ok := typ.MethodByName(m.Name + "WithContext")
+		if !ok {
+			t.Errorf("expect %s to have a WithContext variant", m.Name)
+			continue
+		}
+		// The first parameter of a method's type is its receiver.
+		if a := ctxMethod.Type; a.NumIn() < 2 || a.In(1) != ctxType {
+			t.Errorf("expect %s to take an aws.Context first, got %v", ctxMethod.Name, a)
+		}
+	}
+}
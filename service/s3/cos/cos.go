@@ -0,0 +1,111 @@
+// Package cos provides a constructor of S3 clients configured for IBM Cloud
+// Object Storage (COS).
+//
+// The clients returned by New resolve the COS endpoints of regions, sign
+// requests with IBM IAM credentials, sending the service instance ID header
+// of the credentials with the requests that require it, and retry requests
+// with the s3.COSRetryer.
+//
+//     sess := session.Must(session.NewSession(&aws.Config{
+//         Region:      aws.String("us-south"),
+//         Credentials: ibmcreds.NewCredentialsClient(apiKey, serviceInstanceID, iamEndpoint),
+//     }))
+//
+//     svc := cos.New(sess)
+package cos
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/defaults"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/signer/ibm"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/service/resourcecontroller"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// Endpoint types of COS regions.
+const (
+	// PublicEndpoint is the type of the endpoints reachable from the
+	// internet.
+	PublicEndpoint = "public"
+
+	// PrivateEndpoint is the type of the endpoints reachable only from the
+	// IBM Cloud private network.
+	PrivateEndpoint = "private"
+
+	// DirectEndpoint is the type of the endpoints reachable from VPCs.
+	DirectEndpoint = "direct"
+)
+
+// New creates a new S3 client for IBM Cloud Object Storage with a session.
+//
+// The client resolves the public COS endpoint of the configured region, or
+// the endpoint of the S3COSEndpointType config value's type, such as
+// "private", retries requests with the s3.COSRetryer, and validates the
+// bucket name and LocationConstraint of CreateBucket requests before sending
+// them. Requests are signed with the IBM IAM signer, which signs requests
+// with HMAC credentials with the V4 signature instead.
+//
+// If the S3DiscoverServiceInstanceID config option is enabled, the service
+// instance ID of ListBuckets and CreateBucket requests signed with IBM IAM
+// credentials without one is discovered with the IBM Cloud Resource
+// Controller, see DiscoverServiceInstanceID.
+//
+// These defaults are the client configuration of
+// defaults.IBMCOSClientConfig. The configuration provided is merged over
+// them, so that an EndpointResolver, Endpoint, MaxRetries, Retryer, or
+// S3ValidateCOSBucket value provided is used instead.
+//
+//     // Create a COS client with a private endpoint.
+//     svc := cos.New(sess, &aws.Config{
+//         EndpointResolver: cos.EndpointResolver(cos.PrivateEndpoint),
+//     })
+func New(p client.ConfigProvider, cfgs ...*aws.Config) *s3.S3 {
+	cfg := defaults.IBMCOSClientConfig()
+	request.WithRetryer(cfg, newRetryer(append([]*aws.Config{cfg}, cfgs...)...))
+
+	svc := s3.New(p, append([]*aws.Config{cfg}, cfgs...)...)
+	svc.Handlers.Sign.Remove(v4.SignRequestHandler)
+	svc.Handlers.Sign.SetBackNamed(ibm.SignRequestHandler)
+
+	// The Resource Controller is not a COS endpoint, the endpoint of the
+	// configuration is not used.
+	discovery := &serviceInstanceIDDiscovery{
+		rc: resourcecontroller.New(p, append(cfgs[:len(cfgs):len(cfgs)],
+			&aws.Config{Endpoint: aws.String(resourcecontroller.DefaultEndpoint)})...),
+	}
+	svc.Handlers.Sign.PushBack(discovery.setServiceInstanceID)
+
+	return svc
+}
+
+// newRetryer returns the COSRetryer of clients, retrying requests up to the
+// MaxRetries configured, if any.
+func newRetryer(cfgs ...*aws.Config) *s3.COSRetryer {
+	merged := aws.NewConfig()
+	merged.MergeIn(cfgs...)
+
+	return s3.NewCOSRetryer(func(r *s3.COSRetryer) {
+		if n := aws.IntValue(merged.MaxRetries); merged.MaxRetries != nil && n != aws.UseServiceDefaultRetries {
+			r.NumMaxRetries = n
+		}
+	})
+}
+
+// EndpointFor returns the COS endpoint host of the region and endpoint type,
+// such as "s3.us-south.cloud-object-storage.appdomain.cloud" for the public
+// endpoint of the us-south region. An empty endpoint type is the public
+// endpoint type.
+func EndpointFor(region, endpointType string) string {
+	return defaults.IBMCOSEndpoint(region, endpointType)
+}
+
+// EndpointResolver returns an endpoints.Resolver resolving the S3 endpoints
+// of regions to the COS endpoints of the endpoint type provided. Endpoints of
+// other services are resolved by the default resolver.
+func EndpointResolver(endpointType string) endpoints.Resolver {
+	return defaults.IBMCOSEndpointResolver(endpointType)
+}
@@ -0,0 +1,185 @@
+package cos_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/credentialstest"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/cos"
+)
+
+func TestEndpointFor(t *testing.T) {
+	cases := map[string]string{
+		"":                  "s3.us-south.cloud-object-storage.appdomain.cloud",
+		cos.PublicEndpoint:  "s3.us-south.cloud-object-storage.appdomain.cloud",
+		cos.PrivateEndpoint: "s3.private.us-south.cloud-object-storage.appdomain.cloud",
+		cos.DirectEndpoint:  "s3.direct.us-south.cloud-object-storage.appdomain.cloud",
+	}
+
+	for endpointType, e := range cases {
+		if a := cos.EndpointFor("us-south", endpointType); e != a {
+			t.Errorf("%q, expect %v, got %v", endpointType, e, a)
+		}
+	}
+}
+
+func TestNewEndpoint(t *testing.T) {
+	cases := []struct {
+		Config   *aws.Config
+		Endpoint string
+	}{
+		{
+			Config:   &aws.Config{Region: aws.String("eu-de")},
+			Endpoint: "https://s3.eu-de.cloud-object-storage.appdomain.cloud",
+		},
+		{
+			Config: &aws.Config{
+				Region:           aws.String("eu-de"),
+				EndpointResolver: cos.EndpointResolver(cos.DirectEndpoint),
+				DisableSSL:       aws.Bool(true),
+			},
+			Endpoint: "http://s3.direct.eu-de.cloud-object-storage.appdomain.cloud",
+		},
+		{
+			Config: &aws.Config{
+				Region:   aws.String("eu-de"),
+				Endpoint: aws.String("https://example.com"),
+			},
+			Endpoint: "https://example.com",
+		},
+	}
+
+	for i, c := range cases {
+		svc := cos.New(unit.Session, c.Config)
+		if e, a := c.Endpoint, svc.Endpoint; e != a {
+			t.Errorf("%d, expect %v endpoint, got %v", i, e, a)
+		}
+		if e, a := "eu-de", svc.SigningRegion; e != a {
+			t.Errorf("%d, expect %v signing region, got %v", i, e, a)
+		}
+	}
+}
+
+func TestNewRetryer(t *testing.T) {
+	cases := []struct {
+		Config     *aws.Config
+		MaxRetries int
+	}{
+		{&aws.Config{}, s3.DefaultCOSMaxRetries},
+		{&aws.Config{MaxRetries: aws.Int(aws.UseServiceDefaultRetries)}, s3.DefaultCOSMaxRetries},
+		{&aws.Config{MaxRetries: aws.Int(2)}, 2},
+	}
+
+	for i, c := range cases {
+		svc := cos.New(unit.Session, c.Config)
+		retryer, ok := svc.Retryer.(*s3.COSRetryer)
+		if !ok {
+			t.Fatalf("%d, expect *s3.COSRetryer, got %T", i, svc.Retryer)
+		}
+		if e, a := c.MaxRetries, retryer.MaxRetries(); e != a {
+			t.Errorf("%d, expect %v max retries, got %v", i, e, a)
+		}
+	}
+}
+
+func TestNewSigner(t *testing.T) {
+	cases := map[string]struct {
+		Credentials   *credentials.Credentials
+		Authorization string
+		InstanceID    string
+	}{
+		"ibm iam": {
+			Credentials: credentials.NewTypedCredentials(&credentialstest.MockProvider{
+				Value: credentials.Value{SessionToken: "token", ServiceInstanceID: "instance"},
+			}, "ibm-iam"),
+			Authorization: "Bearer token",
+			InstanceID:    "instance",
+		},
+		"hmac": {
+			Credentials:   credentials.NewStaticCredentials("AKID", "SECRET", ""),
+			Authorization: "AWS4-HMAC-SHA256",
+		},
+	}
+
+	for name, c := range cases {
+		svc := cos.New(unit.Session, &aws.Config{Credentials: c.Credentials})
+		req, _ := svc.ListBucketsRequest(&s3.ListBucketsInput{})
+		if err := req.Sign(); err != nil {
+			t.Fatalf("%s, expect no error, got %v", name, err)
+		}
+
+		if e, a := c.Authorization, req.HTTPRequest.Header.Get("Authorization"); !strings.HasPrefix(a, e) {
+			t.Errorf("%s, expect %v authorization, got %v", name, e, a)
+		}
+		if e, a := c.InstanceID, req.HTTPRequest.Header.Get("ibm-service-instance-id"); e != a {
+			t.Errorf("%s, expect %v instance ID, got %v", name, e, a)
+		}
+	}
+}
+
+func TestNewValidatesBucket(t *testing.T) {
+	svc := cos.New(unit.Session)
+	req, _ := svc.CreateBucketRequest(&s3.CreateBucketInput{Bucket: aws.String("My_Bucket")})
+	err := req.Build()
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := s3.ErrCodeInvalidBucketName, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
+
+func TestNewEmulatorMode(t *testing.T) {
+	var reqs []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqs = append(reqs, r)
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	// The same client configuration as COS, with the emulator's endpoint,
+	// HMAC credentials, and the S3EmulatorMode enabled.
+	svc := cos.New(unit.Session, &aws.Config{
+		Endpoint: aws.String(server.URL),
+		Region:   aws.String("us-east-1"),
+		Credentials: credentials.NewTypedCredentials(&credentialstest.MockProvider{
+			Value: credentials.Value{
+				AccessKeyID: "AKID", SecretAccessKey: "SECRET", ServiceInstanceID: "instance",
+			},
+		}, "ibm-iam"),
+		S3ServiceInstanceID:         aws.String("instance"),
+		S3DiscoverServiceInstanceID: aws.Bool(true),
+		S3EmulatorMode:              aws.Bool(true),
+	})
+
+	_, err := svc.CreateBucket(&s3.CreateBucketInput{
+		Bucket: aws.String("bucket"),
+		CreateBucketConfiguration: &s3.CreateBucketConfiguration{
+			LocationConstraint: aws.String("us-east-1"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := 1, len(reqs); e != a {
+		t.Fatalf("expect %v requests, got %v", e, a)
+	}
+	r := reqs[0]
+	if e, a := "/bucket", r.URL.Path; e != a {
+		t.Errorf("expect %v path, got %v", e, a)
+	}
+	if e, a := "AWS4-HMAC-SHA256 Credential=AKID/", r.Header.Get("Authorization"); !strings.HasPrefix(a, e) {
+		t.Errorf("expect %v authorization, got %v", e, a)
+	}
+	if a := r.Header.Get("ibm-service-instance-id"); len(a) != 0 {
+		t.Errorf("expect no instance ID, got %v", a)
+	}
+}
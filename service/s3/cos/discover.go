@@ -0,0 +1,116 @@
+package cos
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/resourcecontroller"
+	"github.com/aws/aws-sdk-go/service/resourcecontroller/resourcecontrolleriface"
+)
+
+const (
+	// ErrCodeServiceInstanceNotFound is the error code returned when the
+	// service instance ID cannot be discovered because the account has no
+	// active COS service instance.
+	ErrCodeServiceInstanceNotFound = "ServiceInstanceNotFound"
+
+	// ErrCodeAmbiguousServiceInstance is the error code returned when the
+	// service instance ID cannot be discovered because the account has
+	// several active COS service instances.
+	ErrCodeAmbiguousServiceInstance = "AmbiguousServiceInstance"
+)
+
+// DiscoverServiceInstanceID returns the GUID of the only active COS service
+// instance of the account, listed with the Resource Controller client. An
+// error is returned if the account has no, or several, active COS service
+// instances, in which case the service instance ID must be configured.
+func DiscoverServiceInstanceID(ctx aws.Context, rc resourcecontrolleriface.ResourceControllerAPI) (string, error) {
+	var instances []*resourcecontroller.ResourceInstance
+
+	err := rc.ListResourceInstancesPagesWithContext(ctx, &resourcecontroller.ListResourceInstancesInput{
+		ResourceID: aws.String(resourcecontroller.COSResourceID),
+	}, func(page *resourcecontroller.ListResourceInstancesOutput, lastPage bool) bool {
+		for _, instance := range page.Resources {
+			if state := aws.StringValue(instance.State); state == "" || state == "active" {
+				instances = append(instances, instance)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return "", err
+	}
+
+	switch len(instances) {
+	case 0:
+		return "", awserr.New(ErrCodeServiceInstanceNotFound,
+			"no active COS service instance found, configure the service instance ID of the credentials", nil)
+	case 1:
+		return aws.StringValue(instances[0].GUID), nil
+	default:
+		names := make([]string, len(instances))
+		for i, instance := range instances {
+			names[i] = fmt.Sprintf("%s (%s)", aws.StringValue(instance.Name), aws.StringValue(instance.GUID))
+		}
+		return "", awserr.New(ErrCodeAmbiguousServiceInstance,
+			fmt.Sprintf("found %d COS service instances, configure the service instance ID of the credentials to one of %s",
+				len(instances), strings.Join(names, ", ")), nil)
+	}
+}
+
+// serviceInstanceIDDiscovery discovers the service instance ID of a client's
+// ListBuckets and CreateBucket requests, caching the ID once discovered.
+type serviceInstanceIDDiscovery struct {
+	rc resourcecontrolleriface.ResourceControllerAPI
+
+	mu sync.Mutex
+	id string
+}
+
+// setServiceInstanceID sets the service instance ID of ListBuckets and
+// CreateBucket requests signed with IBM IAM credentials without one, if the
+// S3DiscoverServiceInstanceID config option is enabled.
+func (d *serviceInstanceIDDiscovery) setServiceInstanceID(r *request.Request) {
+	if !aws.BoolValue(r.Config.S3DiscoverServiceInstanceID) || aws.BoolValue(r.Config.S3EmulatorMode) {
+		return
+	}
+	if name := r.Operation.Name; name != "ListBuckets" && name != "CreateBucket" {
+		return
+	}
+	if creds := r.Config.Credentials; creds == nil || creds.GetCredentialsType() != "ibm-iam" {
+		return
+	}
+	if r.HTTPRequest.Header.Get(serviceInstanceIDHeader) != "" {
+		return
+	}
+
+	id, err := d.get(r.Context())
+	if err != nil {
+		r.Error = err
+		return
+	}
+	r.HTTPRequest.Header.Set(serviceInstanceIDHeader, id)
+}
+
+// get returns the cached service instance ID, discovering it if not yet
+// discovered. Failed discoveries are not cached.
+func (d *serviceInstanceIDDiscovery) get(ctx aws.Context) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.id != "" {
+		return d.id, nil
+	}
+
+	id, err := DiscoverServiceInstanceID(ctx, d.rc)
+	if err != nil {
+		return "", err
+	}
+	d.id = id
+
+	return id, nil
+}
@@ -0,0 +1,61 @@
+// +build go1.7
+
+package cos_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/resourcecontroller"
+	"github.com/aws/aws-sdk-go/service/s3/cos"
+)
+
+func TestDiscoverServiceInstanceID(t *testing.T) {
+	cases := map[string]struct {
+		Instances []*resourcecontroller.ResourceInstance
+		ID        string
+		Code      string
+	}{
+		"one": {
+			Instances: []*resourcecontroller.ResourceInstance{
+				{GUID: aws.String("instance-1"), State: aws.String("active")},
+				{GUID: aws.String("instance-2"), State: aws.String("removed")},
+			},
+			ID: "instance-1",
+		},
+		"none": {
+			Code: cos.ErrCodeServiceInstanceNotFound,
+		},
+		"several": {
+			Instances: []*resourcecontroller.ResourceInstance{
+				{GUID: aws.String("instance-1")},
+				{GUID: aws.String("instance-2")},
+			},
+			Code: cos.ErrCodeAmbiguousServiceInstance,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			rc := &mockResourceController{pages: [][]*resourcecontroller.ResourceInstance{c.Instances}}
+
+			id, err := cos.DiscoverServiceInstanceID(aws.BackgroundContext(), rc)
+			if c.Code != "" {
+				if err == nil {
+					t.Fatalf("expect error, got none")
+				}
+				if e, a := c.Code, err.(awserr.Error).Code(); e != a {
+					t.Errorf("expect %v error code, got %v", e, a)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.ID, id; e != a {
+				t.Errorf("expect %v ID, got %v", e, a)
+			}
+		})
+	}
+}
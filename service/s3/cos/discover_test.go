@@ -0,0 +1,69 @@
+package cos_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/credentialstest"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/resourcecontroller"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/cos"
+)
+
+func TestNewDiscoverServiceInstanceID(t *testing.T) {
+	var rcRequests int
+	var instanceIDs []string
+
+	sess := unit.Session.Copy()
+	sess.Handlers.Send.Clear()
+	sess.Handlers.Send.PushBack(func(r *request.Request) {
+		var body string
+		switch r.ClientInfo.ServiceName {
+		case resourcecontroller.ServiceName:
+			rcRequests++
+			if e, a := "resource-controller.cloud.ibm.com", r.HTTPRequest.URL.Host; e != a {
+				t.Errorf("expect %v host, got %v", e, a)
+			}
+			body = `{"rows_count":1,"resources":[{"guid":"discovered","state":"active"}]}`
+		default:
+			instanceIDs = append(instanceIDs, r.HTTPRequest.Header.Get("ibm-service-instance-id"))
+			body = `<ListAllMyBucketsResult></ListAllMyBucketsResult>`
+		}
+		r.HTTPResponse = &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}
+	})
+
+	svc := cos.New(sess, &aws.Config{
+		Region:                      aws.String("us-south"),
+		S3DiscoverServiceInstanceID: aws.Bool(true),
+		Credentials: credentials.NewTypedCredentials(&credentialstest.MockProvider{
+			Value: credentials.Value{SessionToken: "token"},
+		}, "ibm-iam"),
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.ListBuckets(&s3.ListBucketsInput{}); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+	if _, err := svc.ListBucketsWithContext(aws.BackgroundContext(), &s3.ListBucketsInput{},
+		cos.WithServiceInstanceID("configured")); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := 1, rcRequests; e != a {
+		t.Errorf("expect %v Resource Controller requests, got %v", e, a)
+	}
+	if e, a := []string{"discovered", "discovered", "configured"}, instanceIDs; len(e) != len(a) || e[0] != a[0] || e[1] != a[1] || e[2] != a[2] {
+		t.Errorf("expect %v service instance IDs, got %v", e, a)
+	}
+}
@@ -0,0 +1,449 @@
+package cos
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// DefaultHealthCheckInterval is the default interval the endpoints of a
+// Failover are health checked at while unhealthy.
+const DefaultHealthCheckInterval = 30 * time.Second
+
+// ErrCodeUnknownLocation is the error code returned when a cross region
+// location is not known.
+const ErrCodeUnknownLocation = "UnknownLocation"
+
+// failoverHandlerName is the name of the handlers of failover clients.
+const failoverHandlerName = "cos.FailoverHandler"
+
+// crossRegionSites are the sites of the tethered endpoints of cross region
+// locations.
+var crossRegionSites = map[string][]string{
+	"us": {"dal", "wdc", "sjc"},
+	"eu": {"ams", "fra", "mil"},
+	"ap": {"tok", "seo", "hkg"},
+}
+
+// CrossRegionEndpoints returns the tethered endpoint hosts of the cross
+// region location and endpoint type, such as
+// "s3.dal.us.cloud-object-storage.appdomain.cloud" for the public endpoint of
+// the Dallas site of the "us" location. The location may be given with or
+// without its "-geo" suffix.
+func CrossRegionEndpoints(location, endpointType string) ([]string, error) {
+	location = strings.TrimSuffix(location, "-geo")
+
+	sites, ok := crossRegionSites[location]
+	if !ok {
+		return nil, awserr.New(ErrCodeUnknownLocation,
+			fmt.Sprintf("unknown cross region location %q", location), nil)
+	}
+
+	hosts := make([]string, 0, len(sites))
+	for _, site := range sites {
+		hosts = append(hosts, EndpointFor(site+"."+location, endpointType))
+	}
+	return hosts, nil
+}
+
+// A Failover routes the requests of a COS client to one of the endpoints of
+// a cross region bucket, failing over to the next healthy endpoint when a
+// read request fails due to an endpoint outage, such as a connection error
+// or a 5xx response other than throttling. Requests stick to the active
+// endpoint until it fails, and do not fail back once the failed endpoint
+// recovers.
+//
+// Failed endpoints are health checked in the background until healthy
+// again. A Failover is safe to use concurrently, and may be shared by
+// multiple clients. The Failover's fields must not be modified once it is in
+// use.
+type Failover struct {
+	// The endpoint hosts to route requests to, in order of preference.
+	Endpoints []string
+
+	// The interval failed endpoints are health checked at. If this is set
+	// to zero, the DefaultHealthCheckInterval value will be used.
+	HealthCheckInterval time.Duration
+
+	// HealthCheck returns nil if the endpoint host is healthy. Defaults to an
+	// HTTPS HEAD request of the endpoint, healthy unless the request fails or
+	// the response has a 5xx status code.
+	HealthCheck func(endpoint string) error
+
+	// Setting this value to true will cause the failover to probe the latency
+	// of its endpoints when its first client is created, routing requests to
+	// the endpoint with the lowest latency instead of the first endpoint.
+	// Endpoints whose probe fails are marked unhealthy.
+	SelectNearest bool
+
+	// Probe returns the latency of an endpoint host when SelectNearest is
+	// set. Defaults to timing an HTTPS HEAD request of the endpoint.
+	Probe func(endpoint string) (time.Duration, error)
+
+	// OnFailover is called when requests fail over from one endpoint to
+	// another, if set. It is called from the goroutine of the failed
+	// request, and must not block.
+	OnFailover func(from, to string)
+
+	m           sync.Mutex
+	active      int
+	failovers   int64
+	endpoints   []*endpointState
+	checkActive bool
+}
+
+// endpointState is the health and metrics of a Failover's endpoint.
+type endpointState struct {
+	healthy  bool
+	requests int64
+	failures int64
+}
+
+// FailoverStats are the metrics of a Failover.
+type FailoverStats struct {
+	// The endpoint requests are currently routed to.
+	Active string
+
+	// The number of times requests failed over to another endpoint.
+	Failovers int64
+
+	// The metrics of each endpoint, in order of preference.
+	Endpoints []EndpointStats
+}
+
+// EndpointStats are the metrics of an endpoint of a Failover.
+type EndpointStats struct {
+	// The endpoint host.
+	Endpoint string
+
+	// Whether the endpoint is healthy, or failed and being health checked.
+	Healthy bool
+
+	// The number of request attempts sent to the endpoint.
+	Requests int64
+
+	// The number of request attempts failing due to an endpoint outage.
+	Failures int64
+}
+
+// NewFailover returns a Failover of the tethered endpoints of the cross
+// region location and endpoint type, such as "us-geo". Pass in additional
+// functional options to customize the failover behavior.
+func NewFailover(location, endpointType string, options ...func(*Failover)) (*Failover, error) {
+	hosts, err := CrossRegionEndpoints(location, endpointType)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Failover{Endpoints: hosts}
+	for _, option := range options {
+		option(f)
+	}
+
+	return f, nil
+}
+
+// NewFailoverClient creates a new S3 client for IBM Cloud Object Storage the
+// same as New, whose requests are routed to the Failover's endpoints.
+//
+//     f, err := cos.NewFailover("us-geo", cos.PublicEndpoint)
+//     if err != nil {
+//         return err
+//     }
+//     svc := cos.NewFailoverClient(sess, f)
+//
+//     out, err := svc.GetObject(params)
+//     fmt.Println(f.Stats().Active)
+func NewFailoverClient(p client.ConfigProvider, f *Failover, cfgs ...*aws.Config) *s3.S3 {
+	f.init()
+
+	cfg := &aws.Config{Endpoint: aws.String(f.Endpoints[0])}
+	svc := New(p, append([]*aws.Config{cfg}, cfgs...)...)
+
+	base := f.Endpoints[0]
+	if u, err := url.Parse(svc.Endpoint); err == nil && len(u.Host) != 0 {
+		base = u.Host
+	}
+
+	// Route each attempt, including retries, to the active endpoint before
+	// the attempt is signed.
+	svc.Handlers.Sign.PushFrontNamed(request.NamedHandler{
+		Name: failoverHandlerName,
+		Fn: func(r *request.Request) {
+			f.route(r, base)
+		},
+	})
+	svc.Handlers.Retry.PushFrontNamed(request.NamedHandler{
+		Name: failoverHandlerName,
+		Fn:   f.recordFailure,
+	})
+
+	return svc
+}
+
+// init initializes the state of the Failover's endpoints once.
+func (f *Failover) init() {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.endpoints != nil {
+		return
+	}
+	if len(f.Endpoints) == 0 {
+		panic("cos: Failover requires at least one endpoint")
+	}
+
+	f.endpoints = make([]*endpointState, len(f.Endpoints))
+	for i := range f.endpoints {
+		f.endpoints[i] = &endpointState{healthy: true}
+	}
+
+	if f.SelectNearest {
+		f.selectNearest()
+	}
+}
+
+// selectNearest routes requests to the endpoint with the lowest probe
+// latency, marking the endpoints whose probe fails unhealthy. The first
+// endpoint stays active if all probes fail. Must be called with the lock
+// held.
+func (f *Failover) selectNearest() {
+	latencies, errs := probeEndpoints(f.Endpoints, f.Probe)
+
+	nearest := -1
+	for i := range f.Endpoints {
+		if errs[i] != nil {
+			f.endpoints[i].healthy = false
+			f.startHealthChecks()
+			continue
+		}
+		if nearest < 0 || latencies[i] < latencies[nearest] {
+			nearest = i
+		}
+	}
+
+	if nearest >= 0 {
+		f.active = nearest
+	}
+}
+
+// route replaces the base endpoint host of the request's URL with the active
+// endpoint. Virtual hosted-style bucket names prefixing the host are kept.
+func (f *Failover) route(r *request.Request, base string) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	host := r.HTTPRequest.URL.Host
+	for _, ep := range append([]string{base}, f.Endpoints...) {
+		if host == ep || strings.HasSuffix(host, "."+ep) {
+			host = strings.TrimSuffix(host, ep) + f.Endpoints[f.active]
+			break
+		}
+	}
+
+	r.HTTPRequest.URL.Host = host
+	f.endpoints[f.active].requests++
+}
+
+// recordFailure marks the endpoint of a read request's failed attempt as
+// unhealthy if the attempt failed due to an endpoint outage, failing over to
+// the next healthy endpoint.
+func (f *Failover) recordFailure(r *request.Request) {
+	if !isReadRequest(r) || !isEndpointFailure(r) {
+		return
+	}
+
+	from, to, ok := f.fail(r.HTTPRequest.URL.Host)
+	if ok && f.OnFailover != nil {
+		f.OnFailover(from, to)
+	}
+}
+
+// fail marks the endpoint of the host as unhealthy, and fails over to the
+// next healthy endpoint if the endpoint is the active one. Returns the
+// endpoints failed over from and to, if any.
+func (f *Failover) fail(host string) (from, to string, ok bool) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	failed := -1
+	for i, ep := range f.Endpoints {
+		if host == ep || strings.HasSuffix(host, "."+ep) {
+			failed = i
+			break
+		}
+	}
+	if failed < 0 {
+		return "", "", false
+	}
+
+	f.endpoints[failed].failures++
+	f.endpoints[failed].healthy = false
+	f.startHealthChecks()
+
+	if failed != f.active {
+		return "", "", false
+	}
+	for i := 1; i < len(f.Endpoints); i++ {
+		next := (f.active + i) % len(f.Endpoints)
+		if !f.endpoints[next].healthy {
+			continue
+		}
+
+		from = f.Endpoints[f.active]
+		f.active = next
+		f.failovers++
+		return from, f.Endpoints[next], true
+	}
+
+	// All endpoints failed, keep routing to the active endpoint.
+	return "", "", false
+}
+
+// startHealthChecks starts health checking the unhealthy endpoints in the
+// background, if not already. Must be called with the lock held.
+func (f *Failover) startHealthChecks() {
+	if f.checkActive {
+		return
+	}
+	f.checkActive = true
+
+	interval := f.HealthCheckInterval
+	if interval <= 0 {
+		interval = DefaultHealthCheckInterval
+	}
+	check := f.HealthCheck
+	if check == nil {
+		check = defaultHealthCheck
+	}
+
+	go func() {
+		for {
+			time.Sleep(interval)
+			if !f.checkUnhealthy(check) {
+				return
+			}
+		}
+	}()
+}
+
+// checkUnhealthy health checks the unhealthy endpoints, marking those which
+// are healthy again. Returns whether any endpoint is still unhealthy.
+func (f *Failover) checkUnhealthy(check func(string) error) bool {
+	f.m.Lock()
+	var unhealthy []int
+	for i, ep := range f.endpoints {
+		if !ep.healthy {
+			unhealthy = append(unhealthy, i)
+		}
+	}
+	f.m.Unlock()
+
+	healthy := map[int]bool{}
+	for _, i := range unhealthy {
+		healthy[i] = check(f.Endpoints[i]) == nil
+	}
+
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	remaining := false
+	for _, i := range unhealthy {
+		if healthy[i] {
+			f.endpoints[i].healthy = true
+		} else {
+			remaining = true
+		}
+	}
+	f.checkActive = remaining
+
+	return remaining
+}
+
+// Stats returns the current metrics of the Failover.
+func (f *Failover) Stats() FailoverStats {
+	f.init()
+
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	stats := FailoverStats{
+		Active:    f.Endpoints[f.active],
+		Failovers: f.failovers,
+		Endpoints: make([]EndpointStats, len(f.Endpoints)),
+	}
+	for i, ep := range f.endpoints {
+		stats.Endpoints[i] = EndpointStats{
+			Endpoint: f.Endpoints[i],
+			Healthy:  ep.healthy,
+			Requests: ep.requests,
+			Failures: ep.failures,
+		}
+	}
+
+	return stats
+}
+
+// healthCheckClient is the HTTP client of the default health check.
+var healthCheckClient = &http.Client{Timeout: 10 * time.Second}
+
+// defaultHealthCheck sends a HEAD request to the endpoint, returning an
+// error if the request fails or the response has a 5xx status code.
+func defaultHealthCheck(endpoint string) error {
+	resp, err := healthCheckClient.Head("https://" + endpoint + "/")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("endpoint %s unhealthy, status code %d", endpoint, resp.StatusCode)
+	}
+	return nil
+}
+
+// isReadRequest returns whether the request only reads data, and may be
+// retried on another endpoint.
+func isReadRequest(r *request.Request) bool {
+	switch r.Operation.HTTPMethod {
+	case "GET", "HEAD":
+		return true
+	default:
+		return false
+	}
+}
+
+// isEndpointFailure returns whether the request failed due to an outage of
+// its endpoint, such as a connection error or a 5xx response. Throttled
+// requests do not indicate an outage.
+func isEndpointFailure(r *request.Request) bool {
+	if r.Error == nil {
+		return false
+	}
+	if aerr, ok := r.Error.(awserr.Error); ok && aerr.Code() == "SlowDown" {
+		return false
+	}
+	if request.IsErrorThrottle(r.Error) {
+		return false
+	}
+
+	if r.HTTPResponse == nil || r.HTTPResponse.StatusCode == 0 {
+		return true
+	}
+	switch r.HTTPResponse.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
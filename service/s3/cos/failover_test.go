@@ -0,0 +1,150 @@
+package cos_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/cos"
+)
+
+func TestCrossRegionEndpoints(t *testing.T) {
+	hosts, err := cos.CrossRegionEndpoints("us-geo", cos.PrivateEndpoint)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := []string{
+		"s3.private.dal.us.cloud-object-storage.appdomain.cloud",
+		"s3.private.wdc.us.cloud-object-storage.appdomain.cloud",
+		"s3.private.sjc.us.cloud-object-storage.appdomain.cloud",
+	}
+	if e, a := expect, hosts; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if _, err := cos.CrossRegionEndpoints("mars-geo", cos.PublicEndpoint); err == nil {
+		t.Errorf("expect error for unknown location, got none")
+	}
+}
+
+func newFailoverTestServer(status *int32) (*httptest.Server, string) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(atomic.LoadInt32(status)))
+	}))
+	u, _ := url.Parse(server.URL)
+	return server, u.Host
+}
+
+func TestFailoverClient(t *testing.T) {
+	primaryStatus, secondaryStatus := int32(500), int32(200)
+	primary, primaryHost := newFailoverTestServer(&primaryStatus)
+	defer primary.Close()
+	secondary, secondaryHost := newFailoverTestServer(&secondaryStatus)
+	defer secondary.Close()
+
+	var failovers []string
+	healthy := make(chan struct{}, 1)
+	f := &cos.Failover{
+		Endpoints:           []string{primaryHost, secondaryHost},
+		HealthCheckInterval: 10 * time.Millisecond,
+		HealthCheck: func(endpoint string) error {
+			select {
+			case healthy <- struct{}{}:
+			default:
+			}
+			return nil
+		},
+		OnFailover: func(from, to string) {
+			failovers = append(failovers, from+" -> "+to)
+		},
+	}
+
+	svc := cos.NewFailoverClient(unit.Session, f, &aws.Config{
+		DisableSSL:       aws.Bool(true),
+		S3ForcePathStyle: aws.Bool(true),
+		MaxRetries:       aws.Int(2),
+		SleepDelay:       func(time.Duration) {},
+	})
+
+	// The read fails over to the secondary endpoint.
+	if _, err := svc.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("key"),
+	}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := []string{primaryHost + " -> " + secondaryHost}, failovers; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v failovers, got %v", e, a)
+	}
+
+	// The failed endpoint is health checked until healthy, but requests
+	// stick to the secondary endpoint.
+	select {
+	case <-healthy:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expect primary endpoint to be health checked")
+	}
+	atomic.StoreInt32(&primaryStatus, 200)
+	if _, err := svc.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("key"),
+		Body:   bytes.NewReader([]byte("data")),
+	}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	stats := f.Stats()
+	if e, a := secondaryHost, stats.Active; e != a {
+		t.Errorf("expect %v active, got %v", e, a)
+	}
+	if e, a := int64(1), stats.Failovers; e != a {
+		t.Errorf("expect %v failovers, got %v", e, a)
+	}
+	expect := []cos.EndpointStats{
+		{Endpoint: primaryHost, Healthy: true, Requests: 1, Failures: 1},
+		{Endpoint: secondaryHost, Healthy: true, Requests: 2},
+	}
+	if e, a := expect, stats.Endpoints; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %+v endpoint stats, got %+v", e, a)
+	}
+}
+
+func TestFailoverClientWritesDoNotFailOver(t *testing.T) {
+	status := int32(503)
+	server, host := newFailoverTestServer(&status)
+	defer server.Close()
+
+	f := &cos.Failover{Endpoints: []string{host, "127.0.0.1:1"}}
+	svc := cos.NewFailoverClient(unit.Session, f, &aws.Config{
+		DisableSSL:       aws.Bool(true),
+		S3ForcePathStyle: aws.Bool(true),
+		MaxRetries:       aws.Int(1),
+		SleepDelay:       func(time.Duration) {},
+	})
+
+	_, err := svc.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("key"),
+		Body:   bytes.NewReader([]byte("data")),
+	})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+
+	stats := f.Stats()
+	if e, a := host, stats.Active; e != a {
+		t.Errorf("expect %v active, got %v", e, a)
+	}
+	if e, a := int64(2), stats.Endpoints[0].Requests; e != a {
+		t.Errorf("expect %v requests, got %v", e, a)
+	}
+}
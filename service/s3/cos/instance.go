@@ -0,0 +1,95 @@
+package cos
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/resourcecontroller"
+	"github.com/aws/aws-sdk-go/service/resourcecontroller/resourcecontrolleriface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// ErrCodeBucketInstanceNotFound is the error code returned when none of the
+// COS service instances of the account contain a bucket.
+const ErrCodeBucketInstanceNotFound = "BucketInstanceNotFound"
+
+// serviceInstanceIDHeader is the header of the COS service instance ID sent
+// with ListBuckets and CreateBucket requests.
+const serviceInstanceIDHeader = "ibm-service-instance-id"
+
+// WithServiceInstanceID is a request option sending the COS service instance
+// ID provided with ListBuckets and CreateBucket requests, instead of the
+// service instance ID of the client's credentials. This allows the buckets of
+// every service instance of an account to be managed with the same client.
+//
+//     out, err := svc.ListBucketsWithContext(ctx, &s3.ListBucketsInput{},
+//         cos.WithServiceInstanceID(aws.StringValue(instance.GUID)))
+func WithServiceInstanceID(id string) request.Option {
+	return func(r *request.Request) {
+		// The IBM IAM signer does not replace the header once set.
+		r.Handlers.Build.PushBack(func(r *request.Request) {
+			r.HTTPRequest.Header.Set(serviceInstanceIDHeader, id)
+		})
+	}
+}
+
+// FindBucketInstance returns the COS service instance containing the bucket,
+// resolving the instance's GUID and CRN. The COS service instances of the
+// account are listed with the Resource Controller client, and the buckets of
+// each instance are listed with the S3 client, until the bucket is found.
+//
+//     instance, err := cos.FindBucketInstance(ctx, resourcecontroller.New(sess), cos.New(sess), "bucket")
+//     if err != nil {
+//         return err
+//     }
+//     fmt.Println(aws.StringValue(instance.GUID), aws.StringValue(instance.CRN))
+func FindBucketInstance(ctx aws.Context, rc resourcecontrolleriface.ResourceControllerAPI, svc s3iface.S3API, bucket string) (*resourcecontroller.ResourceInstance, error) {
+	var found *resourcecontroller.ResourceInstance
+	var listErr error
+
+	err := rc.ListResourceInstancesPagesWithContext(ctx, &resourcecontroller.ListResourceInstancesInput{
+		ResourceID: aws.String(resourcecontroller.COSResourceID),
+	}, func(page *resourcecontroller.ListResourceInstancesOutput, lastPage bool) bool {
+		for _, instance := range page.Resources {
+			out, err := svc.ListBucketsWithContext(ctx, &s3.ListBucketsInput{},
+				WithServiceInstanceID(aws.StringValue(instance.GUID)))
+			if err != nil {
+				listErr = err
+				return false
+			}
+			for _, b := range out.Buckets {
+				if aws.StringValue(b.Name) == bucket {
+					found = instance
+					return false
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if listErr != nil {
+		return nil, listErr
+	}
+	if found == nil {
+		return nil, awserr.New(ErrCodeBucketInstanceNotFound,
+			fmt.Sprintf("no COS service instance contains bucket %q", bucket), nil)
+	}
+
+	return found, nil
+}
+
+// BucketCRN returns the CRN of a bucket of the COS service instance with the
+// CRN provided, such as the CRN of a ResourceInstance. Bucket CRNs identify
+// buckets in IAM policies and authorization checks.
+//
+//     crn := cos.BucketCRN(aws.StringValue(instance.CRN), "bucket")
+//     err := ibmcreds.NewAuthorizer(creds).CheckAuthorized(ctx, crn, "cloud-object-storage.object.put")
+func BucketCRN(instanceCRN, bucket string) string {
+	return strings.TrimSuffix(instanceCRN, "::") + ":bucket:" + bucket
+}
@@ -0,0 +1,86 @@
+package cos_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/resourcecontroller"
+	"github.com/aws/aws-sdk-go/service/resourcecontroller/resourcecontrolleriface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/cos"
+)
+
+type mockResourceController struct {
+	resourcecontrolleriface.ResourceControllerAPI
+	pages [][]*resourcecontroller.ResourceInstance
+}
+
+func (m *mockResourceController) ListResourceInstancesPagesWithContext(ctx aws.Context, input *resourcecontroller.ListResourceInstancesInput, fn func(*resourcecontroller.ListResourceInstancesOutput, bool) bool, opts ...request.Option) error {
+	for i, page := range m.pages {
+		if !fn(&resourcecontroller.ListResourceInstancesOutput{Resources: page}, i == len(m.pages)-1) {
+			break
+		}
+	}
+	return nil
+}
+
+func TestFindBucketInstance(t *testing.T) {
+	buckets := map[string]string{
+		"instance-1": "bucket-a",
+		"instance-2": "bucket-b",
+		"instance-3": "bucket-c",
+	}
+
+	var listed []string
+	svc := s3.New(unit.Session)
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		id := r.HTTPRequest.Header.Get("ibm-service-instance-id")
+		listed = append(listed, id)
+		r.HTTPResponse = &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{},
+			Body: ioutil.NopCloser(strings.NewReader(
+				`<ListAllMyBucketsResult><Buckets><Bucket><Name>` + buckets[id] + `</Name></Bucket></Buckets></ListAllMyBucketsResult>`)),
+		}
+	})
+
+	rc := &mockResourceController{pages: [][]*resourcecontroller.ResourceInstance{
+		{{GUID: aws.String("instance-1")}},
+		{{GUID: aws.String("instance-2")}, {GUID: aws.String("instance-3")}},
+	}}
+
+	instance, err := cos.FindBucketInstance(aws.BackgroundContext(), rc, svc, "bucket-b")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "instance-2", aws.StringValue(instance.GUID); e != a {
+		t.Errorf("expect %v instance, got %v", e, a)
+	}
+	if e, a := []string{"instance-1", "instance-2"}, listed; len(e) != len(a) || e[0] != a[0] || e[1] != a[1] {
+		t.Errorf("expect %v instances listed, got %v", e, a)
+	}
+
+	_, err = cos.FindBucketInstance(aws.BackgroundContext(), rc, svc, "bucket-z")
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := cos.ErrCodeBucketInstanceNotFound, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
+
+func TestBucketCRN(t *testing.T) {
+	instanceCRN := "crn:v1:bluemix:public:cloud-object-storage:global:a/account:instance-guid::"
+
+	e := "crn:v1:bluemix:public:cloud-object-storage:global:a/account:instance-guid:bucket:bucket"
+	if a := cos.BucketCRN(instanceCRN, "bucket"); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
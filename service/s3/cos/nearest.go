@@ -0,0 +1,76 @@
+package cos
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+// ErrCodeNoReachableEndpoint is the error code returned when none of the
+// endpoints probed for the nearest endpoint could be reached.
+const ErrCodeNoReachableEndpoint = "NoReachableEndpoint"
+
+// NearestEndpoint probes the latency of the endpoint hosts concurrently, and
+// returns the endpoint with the lowest latency, such as the nearest of the
+// tethered endpoints of a cross region location. The probe defaults to timing
+// an HTTPS HEAD request of the endpoint if nil. Endpoints whose probe fails
+// are skipped.
+//
+//     hosts, err := cos.CrossRegionEndpoints("eu-geo", cos.PublicEndpoint)
+//     ...
+//     host, err := cos.NearestEndpoint(hosts, nil)
+//     ...
+//     svc := cos.New(sess, &aws.Config{Endpoint: aws.String(host)})
+func NearestEndpoint(endpoints []string, probe func(endpoint string) (time.Duration, error)) (string, error) {
+	latencies, errs := probeEndpoints(endpoints, probe)
+
+	nearest := -1
+	for i := range endpoints {
+		if errs[i] != nil {
+			continue
+		}
+		if nearest < 0 || latencies[i] < latencies[nearest] {
+			nearest = i
+		}
+	}
+
+	if nearest < 0 {
+		var origErr error
+		if len(errs) > 0 {
+			origErr = errs[0]
+		}
+		return "", awserr.New(ErrCodeNoReachableEndpoint, "none of the endpoints could be reached", origErr)
+	}
+	return endpoints[nearest], nil
+}
+
+// probeEndpoints probes the latency of each endpoint concurrently, returning
+// the latency and probe error of each.
+func probeEndpoints(endpoints []string, probe func(string) (time.Duration, error)) ([]time.Duration, []error) {
+	if probe == nil {
+		probe = defaultProbe
+	}
+
+	latencies := make([]time.Duration, len(endpoints))
+	errs := make([]error, len(endpoints))
+
+	var wg sync.WaitGroup
+	for i, ep := range endpoints {
+		wg.Add(1)
+		go func(i int, ep string) {
+			defer wg.Done()
+			latencies[i], errs[i] = probe(ep)
+		}(i, ep)
+	}
+	wg.Wait()
+
+	return latencies, errs
+}
+
+// defaultProbe returns the duration of a health check of the endpoint.
+func defaultProbe(endpoint string) (time.Duration, error) {
+	start := time.Now()
+	err := defaultHealthCheck(endpoint)
+	return time.Since(start), err
+}
@@ -0,0 +1,85 @@
+package cos_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3/cos"
+)
+
+func stubProbe(latencies map[string]time.Duration) func(string) (time.Duration, error) {
+	return func(endpoint string) (time.Duration, error) {
+		latency, ok := latencies[endpoint]
+		if !ok {
+			return 0, fmt.Errorf("unreachable endpoint %s", endpoint)
+		}
+		return latency, nil
+	}
+}
+
+func TestNearestEndpoint(t *testing.T) {
+	probe := stubProbe(map[string]time.Duration{
+		"dal": 80 * time.Millisecond,
+		"wdc": 20 * time.Millisecond,
+	})
+
+	host, err := cos.NearestEndpoint([]string{"dal", "sjc", "wdc"}, probe)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "wdc", host; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	_, err = cos.NearestEndpoint([]string{"sjc"}, probe)
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := cos.ErrCodeNoReachableEndpoint, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
+
+func TestFailoverSelectNearest(t *testing.T) {
+	f := &cos.Failover{
+		Endpoints:           []string{"dal", "wdc", "sjc"},
+		SelectNearest:       true,
+		HealthCheckInterval: time.Hour,
+		Probe: stubProbe(map[string]time.Duration{
+			"wdc": 50 * time.Millisecond,
+			"sjc": 10 * time.Millisecond,
+		}),
+	}
+	cos.NewFailoverClient(unit.Session, f)
+
+	stats := f.Stats()
+	if e, a := "sjc", stats.Active; e != a {
+		t.Errorf("expect %v active, got %v", e, a)
+	}
+	expect := []cos.EndpointStats{
+		{Endpoint: "dal", Healthy: false},
+		{Endpoint: "wdc", Healthy: true},
+		{Endpoint: "sjc", Healthy: true},
+	}
+	if e, a := expect, stats.Endpoints; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %+v endpoint stats, got %+v", e, a)
+	}
+}
+
+func TestFailoverSelectNearestUnreachable(t *testing.T) {
+	f := &cos.Failover{
+		Endpoints:           []string{"dal", "wdc"},
+		SelectNearest:       true,
+		HealthCheckInterval: time.Hour,
+		Probe:               stubProbe(nil),
+	}
+	cos.NewFailoverClient(unit.Session, f)
+
+	if e, a := "dal", f.Stats().Active; e != a {
+		t.Errorf("expect %v active, got %v", e, a)
+	}
+}
@@ -0,0 +1,324 @@
+package cos
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// ErrCodeOutsideNamespace is the error code returned when a call of a
+// PrefixClient names a bucket other than the client's bucket.
+const ErrCodeOutsideNamespace = "OutsideNamespace"
+
+// A PrefixClient scopes the object operations of a COS client to the keys
+// with a prefix in a bucket, so that multiple tenants can share a bucket
+// without access to each other's objects. The prefix is prepended to the
+// keys of the calls, and stripped from the keys of their results, so that
+// tenants use keys relative to their namespace.
+//
+// A PrefixClient only has methods of operations on the objects of its
+// namespace, so that code given a PrefixClient cannot call operations
+// outside of it. The Bucket parameter of calls may be omitted, and calls
+// naming another bucket fail with an error with the code
+// ErrCodeOutsideNamespace.
+//
+//     tenant := cos.NewPrefixClient(svc, "shared", "tenants/"+tenantID+"/")
+//
+//     // Writes the object "tenants/<tenantID>/report.csv".
+//     _, err := tenant.PutObjectWithContext(ctx, &s3.PutObjectInput{
+//         Key:  aws.String("report.csv"),
+//         Body: body,
+//     })
+//
+// The prefix is prepended as is, and should end with a delimiter such as "/"
+// so that one tenant's prefix is not the prefix of another's.
+type PrefixClient struct {
+	svc    s3iface.S3API
+	bucket string
+	prefix string
+}
+
+// NewPrefixClient returns a PrefixClient scoping the object operations of
+// the client to the keys with the prefix in the bucket.
+func NewPrefixClient(svc s3iface.S3API, bucket, prefix string) *PrefixClient {
+	return &PrefixClient{svc: svc, bucket: bucket, prefix: prefix}
+}
+
+// Bucket returns the bucket of the client's namespace.
+func (c *PrefixClient) Bucket() string {
+	return c.bucket
+}
+
+// Prefix returns the key prefix of the client's namespace.
+func (c *PrefixClient) Prefix() string {
+	return c.prefix
+}
+
+// PutObjectWithContext writes the object of the key in the namespace, see
+// s3.S3.PutObjectWithContext.
+func (c *PrefixClient) PutObjectWithContext(ctx aws.Context, input *s3.PutObjectInput, opts ...request.Option) (*s3.PutObjectOutput, error) {
+	in := *input
+	if err := c.scope(&in.Bucket, &in.Key); err != nil {
+		return nil, err
+	}
+	return c.svc.PutObjectWithContext(ctx, &in, opts...)
+}
+
+// GetObjectWithContext reads the object of the key in the namespace, see
+// s3.S3.GetObjectWithContext.
+func (c *PrefixClient) GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	in := *input
+	if err := c.scope(&in.Bucket, &in.Key); err != nil {
+		return nil, err
+	}
+	return c.svc.GetObjectWithContext(ctx, &in, opts...)
+}
+
+// HeadObjectWithContext reads the metadata of the object of the key in the
+// namespace, see s3.S3.HeadObjectWithContext.
+func (c *PrefixClient) HeadObjectWithContext(ctx aws.Context, input *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	in := *input
+	if err := c.scope(&in.Bucket, &in.Key); err != nil {
+		return nil, err
+	}
+	return c.svc.HeadObjectWithContext(ctx, &in, opts...)
+}
+
+// DeleteObjectWithContext deletes the object of the key in the namespace,
+// see s3.S3.DeleteObjectWithContext.
+func (c *PrefixClient) DeleteObjectWithContext(ctx aws.Context, input *s3.DeleteObjectInput, opts ...request.Option) (*s3.DeleteObjectOutput, error) {
+	in := *input
+	if err := c.scope(&in.Bucket, &in.Key); err != nil {
+		return nil, err
+	}
+	return c.svc.DeleteObjectWithContext(ctx, &in, opts...)
+}
+
+// DeleteObjectsWithContext deletes the objects of the keys in the
+// namespace, see s3.S3.DeleteObjectsWithContext. The keys of the result are
+// relative to the namespace.
+func (c *PrefixClient) DeleteObjectsWithContext(ctx aws.Context, input *s3.DeleteObjectsInput, opts ...request.Option) (*s3.DeleteObjectsOutput, error) {
+	in := *input
+	if err := c.scope(&in.Bucket, nil); err != nil {
+		return nil, err
+	}
+	if in.Delete != nil {
+		del := *in.Delete
+		del.Objects = make([]*s3.ObjectIdentifier, len(in.Delete.Objects))
+		for i, o := range in.Delete.Objects {
+			id := *o
+			id.Key = c.prependPrefix(id.Key)
+			del.Objects[i] = &id
+		}
+		in.Delete = &del
+	}
+
+	out, err := c.svc.DeleteObjectsWithContext(ctx, &in, opts...)
+	if out != nil {
+		for _, d := range out.Deleted {
+			d.Key = c.stripPrefix(d.Key)
+		}
+		for _, e := range out.Errors {
+			e.Key = c.stripPrefix(e.Key)
+		}
+	}
+	return out, err
+}
+
+// CopyObjectWithContext copies an object of the namespace to the key in the
+// namespace, see s3.S3.CopyObjectWithContext. The CopySource is the bucket
+// and key of the object copied, separated by a slash, the same as for
+// s3.S3.CopyObject, with a key relative to the namespace. The bucket must be
+// the client's bucket.
+//
+//     _, err := tenant.CopyObjectWithContext(ctx, &s3.CopyObjectInput{
+//         Key:        aws.String("copy.csv"),
+//         CopySource: aws.String("shared/report.csv"),
+//     })
+func (c *PrefixClient) CopyObjectWithContext(ctx aws.Context, input *s3.CopyObjectInput, opts ...request.Option) (*s3.CopyObjectOutput, error) {
+	in := *input
+	if err := c.scope(&in.Bucket, &in.Key); err != nil {
+		return nil, err
+	}
+	source, err := c.scopeCopySource(aws.StringValue(in.CopySource))
+	if err != nil {
+		return nil, err
+	}
+	in.CopySource = aws.String(source)
+	return c.svc.CopyObjectWithContext(ctx, &in, opts...)
+}
+
+// ListObjectsV2WithContext lists the objects of the namespace, see
+// s3.S3.ListObjectsV2WithContext. The Prefix and StartAfter parameters, and
+// the keys and prefixes of the result, are relative to the namespace.
+func (c *PrefixClient) ListObjectsV2WithContext(ctx aws.Context, input *s3.ListObjectsV2Input, opts ...request.Option) (*s3.ListObjectsV2Output, error) {
+	in, err := c.scopeListObjectsV2(input)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := c.svc.ListObjectsV2WithContext(ctx, in, opts...)
+	if out != nil {
+		c.stripListObjectsV2(out)
+	}
+	return out, err
+}
+
+// ListObjectsV2PagesWithContext iterates over the pages of the objects of
+// the namespace, see s3.S3.ListObjectsV2PagesWithContext and
+// ListObjectsV2WithContext.
+func (c *PrefixClient) ListObjectsV2PagesWithContext(ctx aws.Context, input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool, opts ...request.Option) error {
+	in, err := c.scopeListObjectsV2(input)
+	if err != nil {
+		return err
+	}
+
+	return c.svc.ListObjectsV2PagesWithContext(ctx, in, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		c.stripListObjectsV2(page)
+		return fn(page, lastPage)
+	}, opts...)
+}
+
+// CreateMultipartUploadWithContext starts a multipart upload of the object
+// of the key in the namespace, see s3.S3.CreateMultipartUploadWithContext.
+func (c *PrefixClient) CreateMultipartUploadWithContext(ctx aws.Context, input *s3.CreateMultipartUploadInput, opts ...request.Option) (*s3.CreateMultipartUploadOutput, error) {
+	in := *input
+	if err := c.scope(&in.Bucket, &in.Key); err != nil {
+		return nil, err
+	}
+
+	out, err := c.svc.CreateMultipartUploadWithContext(ctx, &in, opts...)
+	if out != nil {
+		out.Key = c.stripPrefix(out.Key)
+	}
+	return out, err
+}
+
+// UploadPartWithContext uploads a part of a multipart upload of the object
+// of the key in the namespace, see s3.S3.UploadPartWithContext.
+func (c *PrefixClient) UploadPartWithContext(ctx aws.Context, input *s3.UploadPartInput, opts ...request.Option) (*s3.UploadPartOutput, error) {
+	in := *input
+	if err := c.scope(&in.Bucket, &in.Key); err != nil {
+		return nil, err
+	}
+	return c.svc.UploadPartWithContext(ctx, &in, opts...)
+}
+
+// CompleteMultipartUploadWithContext completes a multipart upload of the
+// object of the key in the namespace, see
+// s3.S3.CompleteMultipartUploadWithContext.
+func (c *PrefixClient) CompleteMultipartUploadWithContext(ctx aws.Context, input *s3.CompleteMultipartUploadInput, opts ...request.Option) (*s3.CompleteMultipartUploadOutput, error) {
+	in := *input
+	if err := c.scope(&in.Bucket, &in.Key); err != nil {
+		return nil, err
+	}
+
+	out, err := c.svc.CompleteMultipartUploadWithContext(ctx, &in, opts...)
+	if out != nil {
+		out.Key = c.stripPrefix(out.Key)
+	}
+	return out, err
+}
+
+// AbortMultipartUploadWithContext aborts a multipart upload of the object
+// of the key in the namespace, see s3.S3.AbortMultipartUploadWithContext.
+func (c *PrefixClient) AbortMultipartUploadWithContext(ctx aws.Context, input *s3.AbortMultipartUploadInput, opts ...request.Option) (*s3.AbortMultipartUploadOutput, error) {
+	in := *input
+	if err := c.scope(&in.Bucket, &in.Key); err != nil {
+		return nil, err
+	}
+	return c.svc.AbortMultipartUploadWithContext(ctx, &in, opts...)
+}
+
+// scope sets the bucket to the client's bucket if it is not set, and
+// prepends the prefix to the key, if not nil. An error is returned if the
+// bucket is another bucket.
+func (c *PrefixClient) scope(bucket **string, key **string) error {
+	if *bucket != nil && **bucket != c.bucket {
+		return c.outsideNamespaceError(**bucket)
+	}
+	*bucket = aws.String(c.bucket)
+
+	if key != nil {
+		*key = c.prependPrefix(*key)
+	}
+	return nil
+}
+
+// scopeCopySource returns the copy source of the key in the namespace of
+// the copy source relative to the namespace.
+func (c *PrefixClient) scopeCopySource(source string) (string, error) {
+	var query string
+	if i := strings.IndexByte(source, '?'); i >= 0 {
+		source, query = source[:i], source[i:]
+	}
+	source = strings.TrimPrefix(source, "/")
+
+	bucket, key := source, ""
+	if i := strings.IndexByte(source, '/'); i >= 0 {
+		bucket, key = source[:i], source[i+1:]
+	}
+	if bucket != c.bucket {
+		return "", c.outsideNamespaceError(bucket)
+	}
+	if unescaped, err := pathUnescape(key); err == nil {
+		key = unescaped
+	}
+
+	escaped := (&url.URL{Path: c.prefix + key}).EscapedPath()
+	return c.bucket + "/" + escaped + query, nil
+}
+
+// scopeListObjectsV2 returns a copy of the input listing the objects of the
+// namespace.
+func (c *PrefixClient) scopeListObjectsV2(input *s3.ListObjectsV2Input) (*s3.ListObjectsV2Input, error) {
+	in := *input
+	if err := c.scope(&in.Bucket, &in.Prefix); err != nil {
+		return nil, err
+	}
+	if in.StartAfter != nil {
+		in.StartAfter = c.prependPrefix(in.StartAfter)
+	}
+	return &in, nil
+}
+
+// stripListObjectsV2 strips the prefix from the keys and prefixes of the
+// output.
+func (c *PrefixClient) stripListObjectsV2(out *s3.ListObjectsV2Output) {
+	out.Prefix = c.stripPrefix(out.Prefix)
+	out.StartAfter = c.stripPrefix(out.StartAfter)
+	for _, o := range out.Contents {
+		o.Key = c.stripPrefix(o.Key)
+	}
+	for _, p := range out.CommonPrefixes {
+		p.Prefix = c.stripPrefix(p.Prefix)
+	}
+}
+
+func (c *PrefixClient) prependPrefix(key *string) *string {
+	return aws.String(c.prefix + aws.StringValue(key))
+}
+
+func (c *PrefixClient) stripPrefix(key *string) *string {
+	if key == nil {
+		return nil
+	}
+	return aws.String(strings.TrimPrefix(*key, c.prefix))
+}
+
+func (c *PrefixClient) outsideNamespaceError(bucket string) error {
+	return awserr.New(ErrCodeOutsideNamespace,
+		fmt.Sprintf("bucket %q is outside of the namespace of bucket %q", bucket, c.bucket), nil)
+}
+
+// pathUnescape unescapes the URL path segment, as url.PathUnescape of Go 1.8
+// does. Unlike query components, "+" is not unescaped to a space.
+func pathUnescape(s string) (string, error) {
+	return url.QueryUnescape(strings.Replace(s, "+", "%2B", -1))
+}
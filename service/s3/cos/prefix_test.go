@@ -0,0 +1,140 @@
+package cos_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/cos"
+)
+
+func newPrefixTestSvc(reqs *[]*http.Request, body string) *s3.S3 {
+	svc := s3.New(unit.Session, &aws.Config{MaxRetries: aws.Int(0), S3ForcePathStyle: aws.Bool(true)})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		*reqs = append(*reqs, r.HTTPRequest)
+		r.HTTPResponse = &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}
+	})
+	return svc
+}
+
+func TestPrefixClient_Object(t *testing.T) {
+	var reqs []*http.Request
+	c := cos.NewPrefixClient(newPrefixTestSvc(&reqs, ""), "shared", "tenants/a/")
+
+	in := &s3.PutObjectInput{Key: aws.String("dir/report.csv"), Body: bytes.NewReader(nil)}
+	if _, err := c.PutObjectWithContext(aws.BackgroundContext(), in); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "/shared/tenants/a/dir/report.csv", reqs[0].URL.Path; e != a {
+		t.Errorf("expect %v path, got %v", e, a)
+	}
+	if e, a := "dir/report.csv", aws.StringValue(in.Key); e != a {
+		t.Errorf("expect input %v key not modified, got %v", e, a)
+	}
+
+	_, err := c.CopyObjectWithContext(aws.BackgroundContext(), &s3.CopyObjectInput{
+		Key:        aws.String("copy.csv"),
+		CopySource: aws.String("shared/dir/report%20v1.csv?versionId=1"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "/shared/tenants/a/copy.csv", reqs[1].URL.Path; e != a {
+		t.Errorf("expect %v path, got %v", e, a)
+	}
+	if e, a := "shared/tenants/a/dir/report%20v1.csv?versionId=1", reqs[1].Header.Get("X-Amz-Copy-Source"); e != a {
+		t.Errorf("expect %v copy source, got %v", e, a)
+	}
+
+	_, err = c.GetObjectWithContext(aws.BackgroundContext(), &s3.GetObjectInput{
+		Bucket: aws.String("other"), Key: aws.String("key"),
+	})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := cos.ErrCodeOutsideNamespace, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+
+	_, err = c.CopyObjectWithContext(aws.BackgroundContext(), &s3.CopyObjectInput{
+		Key: aws.String("copy.csv"), CopySource: aws.String("other/key"),
+	})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := 2, len(reqs); e != a {
+		t.Errorf("expect %v requests, got %v", e, a)
+	}
+}
+
+func TestPrefixClient_ListObjectsV2(t *testing.T) {
+	var reqs []*http.Request
+	body := `<ListBucketResult><Prefix>tenants/a/dir/</Prefix>` +
+		`<Contents><Key>tenants/a/dir/x</Key></Contents>` +
+		`<CommonPrefixes><Prefix>tenants/a/dir/sub/</Prefix></CommonPrefixes>` +
+		`<IsTruncated>false</IsTruncated></ListBucketResult>`
+	c := cos.NewPrefixClient(newPrefixTestSvc(&reqs, body), "shared", "tenants/a/")
+
+	var keys []string
+	err := c.ListObjectsV2PagesWithContext(aws.BackgroundContext(), &s3.ListObjectsV2Input{
+		Prefix:    aws.String("dir/"),
+		Delimiter: aws.String("/"),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		if e, a := "dir/", aws.StringValue(page.Prefix); e != a {
+			t.Errorf("expect %v prefix, got %v", e, a)
+		}
+		for _, o := range page.Contents {
+			keys = append(keys, aws.StringValue(o.Key))
+		}
+		for _, p := range page.CommonPrefixes {
+			keys = append(keys, aws.StringValue(p.Prefix))
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "tenants/a/dir/", reqs[0].URL.Query().Get("prefix"); e != a {
+		t.Errorf("expect %v prefix query, got %v", e, a)
+	}
+	if e, a := "dir/x,dir/sub/", strings.Join(keys, ","); e != a {
+		t.Errorf("expect %v keys, got %v", e, a)
+	}
+}
+
+func TestPrefixClient_DeleteObjects(t *testing.T) {
+	var reqs []*http.Request
+	body := `<DeleteResult><Deleted><Key>tenants/a/x</Key></Deleted>` +
+		`<Error><Key>tenants/a/y</Key><Code>AccessDenied</Code></Error></DeleteResult>`
+	c := cos.NewPrefixClient(newPrefixTestSvc(&reqs, body), "shared", "tenants/a/")
+
+	out, err := c.DeleteObjectsWithContext(aws.BackgroundContext(), &s3.DeleteObjectsInput{
+		Delete: &s3.Delete{Objects: []*s3.ObjectIdentifier{{Key: aws.String("x")}, {Key: aws.String("y")}}},
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	b, _ := ioutil.ReadAll(reqs[0].Body)
+	if !bytes.Contains(b, []byte("<Key>tenants/a/x</Key>")) || !bytes.Contains(b, []byte("<Key>tenants/a/y</Key>")) {
+		t.Errorf("expect prefixed keys in request body, got %s", b)
+	}
+	if e, a := "x", aws.StringValue(out.Deleted[0].Key); e != a {
+		t.Errorf("expect %v deleted key, got %v", e, a)
+	}
+	if e, a := "y", aws.StringValue(out.Errors[0].Key); e != a {
+		t.Errorf("expect %v error key, got %v", e, a)
+	}
+}
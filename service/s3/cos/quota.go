@@ -0,0 +1,38 @@
+package cos
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/resourceconfiguration"
+	"github.com/aws/aws-sdk-go/service/resourceconfiguration/resourceconfigurationiface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// BucketQuotaFunc returns a function returning the hard quota and usage of
+// buckets, retrieved with the Resource Configuration client. Set it as the
+// QuotaCheck of an Uploader, so large uploads fail early with a
+// QuotaExceededError if they would exceed the hard quota of their bucket.
+//
+//     uploader := s3manager.NewUploader(sess, func(u *s3manager.Uploader) {
+//         u.QuotaCheck = cos.BucketQuotaFunc(resourceconfiguration.New(sess))
+//     })
+//
+// The usage reported by the Resource Configuration API is updated
+// periodically, and may not include the most recent writes to the bucket.
+func BucketQuotaFunc(rc resourceconfigurationiface.ResourceConfigurationAPI) s3manager.QuotaFunc {
+	return func(ctx aws.Context, bucket string) (s3manager.BucketQuota, error) {
+		out, err := rc.GetBucketConfigWithContext(ctx, &resourceconfiguration.GetBucketConfigInput{
+			Bucket: aws.String(bucket),
+		})
+		if err != nil {
+			return s3manager.BucketQuota{}, err
+		}
+		if out.BucketConfig == nil {
+			return s3manager.BucketQuota{}, nil
+		}
+
+		return s3manager.BucketQuota{
+			HardQuota: aws.Int64Value(out.BucketConfig.HardQuota),
+			BytesUsed: aws.Int64Value(out.BucketConfig.BytesUsed),
+		}, nil
+	}
+}
@@ -0,0 +1,37 @@
+package cos_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/resourceconfiguration"
+	"github.com/aws/aws-sdk-go/service/resourceconfiguration/resourceconfigurationiface"
+	"github.com/aws/aws-sdk-go/service/s3/cos"
+)
+
+type mockResourceConfiguration struct {
+	resourceconfigurationiface.ResourceConfigurationAPI
+	configs map[string]*resourceconfiguration.BucketConfig
+}
+
+func (m *mockResourceConfiguration) GetBucketConfigWithContext(ctx aws.Context, input *resourceconfiguration.GetBucketConfigInput, opts ...request.Option) (*resourceconfiguration.GetBucketConfigOutput, error) {
+	return &resourceconfiguration.GetBucketConfigOutput{BucketConfig: m.configs[aws.StringValue(input.Bucket)]}, nil
+}
+
+func TestBucketQuotaFunc(t *testing.T) {
+	rc := &mockResourceConfiguration{configs: map[string]*resourceconfiguration.BucketConfig{
+		"bucket": {HardQuota: aws.Int64(4096), BytesUsed: aws.Int64(1024)},
+	}}
+
+	quota, err := cos.BucketQuotaFunc(rc)(aws.BackgroundContext(), "bucket")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := int64(4096), quota.HardQuota; e != a {
+		t.Errorf("expect %v hard quota, got %v", e, a)
+	}
+	if e, a := int64(1024), quota.BytesUsed; e != a {
+		t.Errorf("expect %v bytes used, got %v", e, a)
+	}
+}
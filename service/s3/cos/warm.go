@@ -0,0 +1,163 @@
+package cos
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ibmcreds"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// ErrCodeWarmUp is the error code returned when a client could not be fully
+// warmed up by WarmUp.
+const ErrCodeWarmUp = "WarmUpError"
+
+// DefaultWarmUpConnections is the number of connections WarmUp establishes
+// to each host if the Connections of its options is not set.
+const DefaultWarmUpConnections = 2
+
+// WarmUpOptions configures the warm up of a client by WarmUp.
+type WarmUpOptions struct {
+	// Connections is the number of connections established to each host.
+	// Defaults to DefaultWarmUpConnections if 0. The client's transport
+	// keeps at most its MaxIdleConnsPerHost idle connections to a host, 2
+	// for an http.Transport without MaxIdleConnsPerHost, so connections
+	// beyond that are closed once established.
+	Connections int
+
+	// Buckets whose virtual-hosted endpoint hosts, such as
+	// "bucket.s3.us-south.cloud-object-storage.appdomain.cloud", are warmed
+	// up in addition to the client's endpoint host. Requests of the client
+	// for buckets use a connection to the bucket's host, unless the client
+	// uses path-style addressing.
+	Buckets []string
+
+	// SkipCredentials skips retrieving the client's credentials.
+	SkipCredentials bool
+}
+
+// WarmUp prepares the client for its first requests, so that they do not pay
+// the latency of cold connections and credentials. WarmUp resolves the DNS
+// names of the client's endpoint hosts, establishes the Connections to them,
+// which are kept idle by the client's transport for its requests, and
+// retrieves the client's credentials, such as the IBM IAM token of ibmcreds
+// credentials. The hosts are sent an unsigned HEAD request over each
+// connection, whose response is ignored.
+//
+//     svc := cos.New(sess)
+//     if err := cos.WarmUp(ctx, svc, func(o *cos.WarmUpOptions) {
+//         o.Connections = 4
+//         o.Buckets = []string{"bucket"}
+//     }); err != nil {
+//         log.Printf("failed to warm up COS client, %v", err)
+//     }
+//
+// The client can be used while it is warmed up, and failing to warm it up
+// does not prevent its use. An error with the code ErrCodeWarmUp is returned
+// with the errors of the connections, or of the credentials, which failed.
+func WarmUp(ctx aws.Context, svc *s3.S3, options ...func(*WarmUpOptions)) error {
+	opts := WarmUpOptions{}
+	for _, option := range options {
+		option(&opts)
+	}
+	if opts.Connections <= 0 {
+		opts.Connections = DefaultWarmUpConnections
+	}
+
+	urls, err := warmUpURLs(svc, opts.Buckets)
+	if err != nil {
+		return awserr.New(ErrCodeWarmUp, "failed to build endpoint URLs", err)
+	}
+
+	client := svc.Config.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var mu sync.Mutex
+	var errs []error
+	addErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+
+	var wg sync.WaitGroup
+	creds := svc.Config.Credentials
+	if !opts.SkipCredentials && creds != nil && creds != credentials.AnonymousCredentials {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var host string
+			if u, err := url.Parse(svc.Endpoint); err == nil {
+				host = u.Host
+			}
+			if _, err := creds.GetWithContext(ibmcreds.WithServiceEndpoint(ctx, host)); err != nil {
+				addErr(err)
+			}
+		}()
+	}
+
+	// The connections to a host are requested concurrently, so that each
+	// request establishes its own connection.
+	for _, u := range urls {
+		for i := 0; i < opts.Connections; i++ {
+			wg.Add(1)
+			go func(u string) {
+				defer wg.Done()
+				if err := warmUpConnection(ctx, client, u); err != nil {
+					addErr(err)
+				}
+			}(u)
+		}
+	}
+	wg.Wait()
+
+	if len(errs) != 0 {
+		return awserr.NewBatchError(ErrCodeWarmUp, "failed to warm up client", errs)
+	}
+	return nil
+}
+
+// warmUpURLs returns the URLs of the client's endpoint host, and of the
+// hosts of the buckets' requests.
+func warmUpURLs(svc *s3.S3, buckets []string) ([]string, error) {
+	urls := []string{svc.Endpoint + "/"}
+	seen := map[string]struct{}{urls[0]: {}}
+
+	for _, bucket := range buckets {
+		req, _ := svc.HeadBucketRequest(&s3.HeadBucketInput{Bucket: aws.String(bucket)})
+		if err := req.Build(); err != nil {
+			return nil, err
+		}
+
+		u := req.HTTPRequest.URL.Scheme + "://" + req.HTTPRequest.URL.Host + "/"
+		if _, ok := seen[u]; !ok {
+			seen[u] = struct{}{}
+			urls = append(urls, u)
+		}
+	}
+	return urls, nil
+}
+
+// warmUpConnection sends an unsigned HEAD request to the URL, reading its
+// response so that the connection is kept idle for other requests.
+func warmUpConnection(ctx aws.Context, client *http.Client, u string) error {
+	req, err := http.NewRequest("HEAD", u, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(requestWithContext(req, ctx))
+	if err != nil {
+		return err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	return resp.Body.Close()
+}
@@ -0,0 +1,16 @@
+// +build !go1.7
+
+package cos
+
+import (
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// requestWithContext returns the request canceled when the context is done,
+// as requests cannot be made with a context before Go 1.7.
+func requestWithContext(req *http.Request, ctx aws.Context) *http.Request {
+	req.Cancel = ctx.Done()
+	return req
+}
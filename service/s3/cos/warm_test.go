@@ -0,0 +1,94 @@
+package cos_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/credentialstest"
+	"github.com/aws/aws-sdk-go/awstesting"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/cos"
+)
+
+func TestWarmUp(t *testing.T) {
+	var conns, requests int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path == "/" && r.Header.Get("Authorization") != "" {
+			t.Errorf("expect warm up request not to be signed")
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	server.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	provider := &credentialstest.MockProvider{Value: credentials.Value{SessionToken: "token"}}
+	httpClient := awstesting.NewTLSClient(server)
+	httpClient.Transport.(*http.Transport).MaxIdleConnsPerHost = 3
+
+	svc := cos.New(unit.Session, &aws.Config{
+		Endpoint:         aws.String(server.URL),
+		S3ForcePathStyle: aws.Bool(true),
+		HTTPClient:       httpClient,
+		Credentials:      credentials.NewTypedCredentials(provider, "ibm-iam"),
+	})
+
+	err := cos.WarmUp(aws.BackgroundContext(), svc, func(o *cos.WarmUpOptions) {
+		o.Connections = 3
+		o.Buckets = []string{"bucket"}
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := int32(3), atomic.LoadInt32(&conns); e != a {
+		t.Errorf("expect %v connections, got %v", e, a)
+	}
+	if e, a := 1, provider.RetrieveCount(); e != a {
+		t.Errorf("expect %v credentials retrievals, got %v", e, a)
+	}
+
+	// Requests of the client reuse the warm connections.
+	svc.HeadBucket(&s3.HeadBucketInput{Bucket: aws.String("bucket")})
+	if e, a := int32(3), atomic.LoadInt32(&conns); e != a {
+		t.Errorf("expect %v connections, got %v", e, a)
+	}
+	if e, a := int32(4), atomic.LoadInt32(&requests); e != a {
+		t.Errorf("expect %v requests, got %v", e, a)
+	}
+}
+
+func TestWarmUpUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	svc := cos.New(unit.Session, &aws.Config{
+		Endpoint:   aws.String(server.URL),
+		HTTPClient: &http.Client{},
+	})
+
+	err := cos.WarmUp(aws.BackgroundContext(), svc, func(o *cos.WarmUpOptions) {
+		o.SkipCredentials = true
+	})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := cos.ErrCodeWarmUp, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+	if e, a := 2, len(err.(awserr.BatchedErrors).OrigErrs()); e != a {
+		t.Errorf("expect %v errors, got %v", e, a)
+	}
+}
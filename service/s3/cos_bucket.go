@@ -0,0 +1,119 @@
+package s3
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const (
+	// ErrCodeInvalidBucketName is the error code returned when a bucket name
+	// does not follow the IBM Cloud Object Storage bucket naming rules.
+	ErrCodeInvalidBucketName = "InvalidBucketName"
+
+	// ErrCodeInvalidLocationConstraint is the error code returned when a
+	// LocationConstraint is not of the IBM Cloud Object Storage
+	// <location>-<storage class> form.
+	ErrCodeInvalidLocationConstraint = "InvalidLocationConstraint"
+)
+
+// cosStorageClasses are the storage classes of COS LocationConstraints.
+var cosStorageClasses = []string{
+	"standard", "vault", "cold", "flex", "smart", "onerate_active",
+}
+
+// cosLocationRE matches the locations of COS LocationConstraints, such as
+// regions ("eu-de"), cross region locations ("ap"), and single sites ("ams03").
+var cosLocationRE = regexp.MustCompile(`^[a-z]+[0-9]*(-[a-z]+[0-9]*)*$`)
+
+// ValidateCOSBucketName returns an error if the bucket name does not follow
+// the IBM Cloud Object Storage bucket naming rules. Bucket names must be 3 to
+// 63 characters long, contain only lowercase letters, numbers, and
+// nonconsecutive dots and dashes, begin and end with a letter or number, and
+// must not be formatted as an IP address.
+func ValidateCOSBucketName(bucket string) error {
+	if n := len(bucket); n < 3 || n > 63 {
+		return newInvalidBucketNameError(bucket,
+			fmt.Sprintf("must be between 3 and 63 characters long, got %d", n))
+	}
+
+	for i, c := range bucket {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		case c == '.' || c == '-':
+			if i == 0 || i == len(bucket)-1 {
+				return newInvalidBucketNameError(bucket, "must begin and end with a letter or number")
+			}
+			if p := bucket[i-1]; p == '.' || p == '-' {
+				return newInvalidBucketNameError(bucket,
+					fmt.Sprintf("must not contain consecutive dots or dashes, %q at offset %d", bucket[i-1:i+1], i-1))
+			}
+		default:
+			return newInvalidBucketNameError(bucket,
+				fmt.Sprintf("must contain only lowercase letters, numbers, dots, and dashes, %q at offset %d", c, i))
+		}
+	}
+
+	if net.ParseIP(bucket) != nil {
+		return newInvalidBucketNameError(bucket, "must not be formatted as an IP address")
+	}
+
+	return nil
+}
+
+// ValidateCOSLocationConstraint returns an error if the LocationConstraint
+// is not of the IBM Cloud Object Storage <location>-<storage class> form,
+// such as "eu-de-standard", "us-cold", or "ap-smart".
+func ValidateCOSLocationConstraint(locationConstraint string) error {
+	for _, class := range cosStorageClasses {
+		if !strings.HasSuffix(locationConstraint, "-"+class) {
+			continue
+		}
+
+		location := strings.TrimSuffix(locationConstraint, "-"+class)
+		if !cosLocationRE.MatchString(location) {
+			return awserr.New(ErrCodeInvalidLocationConstraint,
+				fmt.Sprintf("invalid LocationConstraint %q, invalid location %q",
+					locationConstraint, location), nil)
+		}
+		return nil
+	}
+
+	return awserr.New(ErrCodeInvalidLocationConstraint,
+		fmt.Sprintf("invalid LocationConstraint %q, must be of the <location>-<storage class> form, such as %q, with one of the storage classes %s",
+			locationConstraint, "eu-de-standard", strings.Join(cosStorageClasses, ", ")), nil)
+}
+
+func newInvalidBucketNameError(bucket, reason string) error {
+	return awserr.New(ErrCodeInvalidBucketName,
+		fmt.Sprintf("invalid bucket name %q, %s", bucket, reason), nil)
+}
+
+// validateCOSCreateBucket validates the bucket name and LocationConstraint of
+// CreateBucket requests if the S3ValidateCOSBucket config option is enabled,
+// and the S3EmulatorMode config option is not.
+func validateCOSCreateBucket(r *request.Request) {
+	if !aws.BoolValue(r.Config.S3ValidateCOSBucket) || aws.BoolValue(r.Config.S3EmulatorMode) || !r.ParamsFilled() {
+		return
+	}
+
+	in := r.Params.(*CreateBucketInput)
+	if in.Bucket != nil {
+		if err := ValidateCOSBucketName(*in.Bucket); err != nil {
+			r.Error = err
+			return
+		}
+	}
+
+	if c := in.CreateBucketConfiguration; c != nil && c.LocationConstraint != nil {
+		if err := ValidateCOSLocationConstraint(*c.LocationConstraint); err != nil {
+			r.Error = err
+			return
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package s3_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestValidateCOSBucketName(t *testing.T) {
+	cases := map[string]bool{
+		"bucket":                 true,
+		"my.bucket-01":           true,
+		"abc":                    true,
+		"ab":                     false,
+		"My-Bucket":              false,
+		"my_bucket":              false,
+		"-bucket":                false,
+		"bucket.":                false,
+		"my..bucket":             false,
+		"my.-bucket":             false,
+		"192.168.1.1":            false,
+		string(make([]byte, 64)): false,
+	}
+
+	for bucket, valid := range cases {
+		err := s3.ValidateCOSBucketName(bucket)
+		if valid {
+			if err != nil {
+				t.Errorf("%q, expect no error, got %v", bucket, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%q, expect error, got none", bucket)
+			continue
+		}
+		if e, a := s3.ErrCodeInvalidBucketName, err.(awserr.Error).Code(); e != a {
+			t.Errorf("%q, expect %v error code, got %v", bucket, e, a)
+		}
+	}
+}
+
+func TestValidateCOSLocationConstraint(t *testing.T) {
+	cases := map[string]bool{
+		"eu-de-standard":    true,
+		"us-south-vault":    true,
+		"us-cold":           true,
+		"ap-smart":          true,
+		"ams03-flex":        true,
+		"us-onerate_active": true,
+		"eu-de":             false,
+		"eu-de-gold":        false,
+		"EU-DE-standard":    false,
+		"-standard":         false,
+		"eu--de-standard":   false,
+	}
+
+	for lc, valid := range cases {
+		err := s3.ValidateCOSLocationConstraint(lc)
+		if valid {
+			if err != nil {
+				t.Errorf("%q, expect no error, got %v", lc, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%q, expect error, got none", lc)
+			continue
+		}
+		if e, a := s3.ErrCodeInvalidLocationConstraint, err.(awserr.Error).Code(); e != a {
+			t.Errorf("%q, expect %v error code, got %v", lc, e, a)
+		}
+	}
+}
+
+func TestCreateBucketCOSValidation(t *testing.T) {
+	cases := []struct {
+		Validate           bool
+		Bucket             string
+		LocationConstraint *string
+		Code               string
+	}{
+		{true, "bucket", aws.String("eu-de-standard"), ""},
+		{true, "bucket", nil, ""},
+		{true, "Bucket", nil, s3.ErrCodeInvalidBucketName},
+		{true, "bucket", aws.String("eu-de"), s3.ErrCodeInvalidLocationConstraint},
+		{false, "Bucket", aws.String("eu-de"), ""},
+	}
+
+	for i, c := range cases {
+		svc := s3.New(unit.Session, &aws.Config{
+			Region:              aws.String("eu-de"),
+			S3ValidateCOSBucket: aws.Bool(c.Validate),
+		})
+
+		in := &s3.CreateBucketInput{Bucket: aws.String(c.Bucket)}
+		if c.LocationConstraint != nil {
+			in.CreateBucketConfiguration = &s3.CreateBucketConfiguration{
+				LocationConstraint: c.LocationConstraint,
+			}
+		}
+		req, _ := svc.CreateBucketRequest(in)
+		err := req.Build()
+
+		if len(c.Code) == 0 {
+			if err != nil {
+				t.Errorf("%d, expect no error, got %v", i, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%d, expect error, got none", i)
+			continue
+		}
+		if e, a := c.Code, err.(awserr.Error).Code(); e != a {
+			t.Errorf("%d, expect %v error code, got %v", i, e, a)
+		}
+	}
+}
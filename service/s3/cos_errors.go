@@ -0,0 +1,105 @@
+package s3
+
+import (
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// cosQuotaExceededCodes are the error codes COS rejects requests exceeding
+// the quotas of a bucket or account with.
+var cosQuotaExceededCodes = map[string]struct{}{
+	"QuotaExceeded":  {},
+	"TooManyBuckets": {},
+}
+
+// IsThrottle returns whether the error is a throttling error, such as COS's
+// 503 SlowDown error, or a 429 Too Many Requests response. Throttled
+// requests should be retried after a delay. Returns false if error is nil.
+//
+// The error and the errors it wraps, such as the errors of s3manager
+// uploads, are checked.
+func IsThrottle(err error) bool {
+	return anyError(err, func(err error) bool {
+		if request.IsErrorThrottle(err) {
+			return true
+		}
+		if _, ok := cosThrottleCodes[errCode(err)]; ok {
+			return true
+		}
+		return errStatusCode(err) == 429
+	})
+}
+
+// IsAuthExpired returns whether the error is the error of a request signed
+// with expired credentials, such as an expired IAM token. The credentials
+// should be refreshed before the request is retried. Returns false if error
+// is nil.
+//
+// The error and the errors it wraps are checked.
+func IsAuthExpired(err error) bool {
+	return anyError(err, func(err error) bool {
+		if request.IsErrorExpiredCreds(err) {
+			return true
+		}
+		_, ok := cosTokenExpiredCodes[errCode(err)]
+		return ok
+	})
+}
+
+// IsNoSuchKey returns whether the error is the error of a request for an
+// object which does not exist, such as a GetObject NoSuchKey error, or the
+// NotFound error of a HeadObject request, which has no error code in its
+// response. NoSuchBucket errors are not. Returns false if error is nil.
+//
+// The error and the errors it wraps are checked.
+func IsNoSuchKey(err error) bool {
+	return anyError(err, func(err error) bool {
+		switch errCode(err) {
+		case ErrCodeNoSuchKey:
+			return true
+		case "NotFound":
+			return errStatusCode(err) == http.StatusNotFound
+		}
+		return false
+	})
+}
+
+// IsQuotaExceeded returns whether the error is the error of a request
+// exceeding a quota, such as the hard quota of a bucket, the number of
+// buckets of an account, or the QuotaExceeded error of s3manager uploads
+// checking their bucket's quota. Requests exceeding quotas fail until the
+// quota is raised or usage is reduced, and should not be retried. Returns
+// false if error is nil.
+//
+// The error and the errors it wraps are checked.
+func IsQuotaExceeded(err error) bool {
+	return anyError(err, func(err error) bool {
+		_, ok := cosQuotaExceededCodes[errCode(err)]
+		return ok
+	})
+}
+
+// anyError returns whether fn returns true for the error, or any of the
+// original errors it wraps.
+func anyError(err error, fn func(error) bool) bool {
+	for err != nil {
+		if fn(err) {
+			return true
+		}
+		aerr, ok := err.(awserr.Error)
+		if !ok {
+			return false
+		}
+		err = aerr.OrigErr()
+	}
+	return false
+}
+
+func errStatusCode(err error) int {
+	if rerr, ok := err.(awserr.RequestFailure); ok {
+		return rerr.StatusCode()
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+// +build go1.7
+
+package s3_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestCOSErrorPredicates(t *testing.T) {
+	failure := func(code string, status int) error {
+		return awserr.NewRequestFailure(awserr.New(code, "message", nil), status, "request-id")
+	}
+
+	cases := map[string]struct {
+		Err                                     error
+		Throttle, AuthExpired, NoSuchKey, Quota bool
+	}{
+		"nil":                 {},
+		"not awserr":          {Err: errors.New("SlowDown")},
+		"slow down":           {Err: failure("SlowDown", 503), Throttle: true},
+		"service unavailable": {Err: failure("ServiceUnavailable", 503), Throttle: true},
+		"too many requests":   {Err: failure("TooManyRequests", 429), Throttle: true},
+		"sdk throttle":        {Err: failure("Throttling", 400), Throttle: true},
+		"expired token":       {Err: failure("ExpiredToken", 400), AuthExpired: true},
+		"token expired":       {Err: failure("TokenExpired", 403), AuthExpired: true},
+		"access denied":       {Err: failure("AccessDenied", 403)},
+		"no such key":         {Err: failure(s3.ErrCodeNoSuchKey, 404), NoSuchKey: true},
+		"head not found":      {Err: failure("NotFound", 404), NoSuchKey: true},
+		"no such bucket":      {Err: failure(s3.ErrCodeNoSuchBucket, 404)},
+		"quota exceeded":      {Err: failure("QuotaExceeded", 403), Quota: true},
+		"too many buckets":    {Err: failure("TooManyBuckets", 400), Quota: true},
+		"wrapped": {
+			Err:      awserr.New("MultipartUpload", "upload multipart failed", failure("SlowDown", 503)),
+			Throttle: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if e, a := c.Throttle, s3.IsThrottle(c.Err); e != a {
+				t.Errorf("expect %v throttle, got %v", e, a)
+			}
+			if e, a := c.AuthExpired, s3.IsAuthExpired(c.Err); e != a {
+				t.Errorf("expect %v auth expired, got %v", e, a)
+			}
+			if e, a := c.NoSuchKey, s3.IsNoSuchKey(c.Err); e != a {
+				t.Errorf("expect %v no such key, got %v", e, a)
+			}
+			if e, a := c.Quota, s3.IsQuotaExceeded(c.Err); e != a {
+				t.Errorf("expect %v quota exceeded, got %v", e, a)
+			}
+		})
+	}
+}
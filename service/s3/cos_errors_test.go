@@ -0,0 +1,36 @@
+package s3_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestIsNoSuchKey_HeadObject(t *testing.T) {
+	svc := s3.New(unit.Session, &aws.Config{MaxRetries: aws.Int(0)})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{
+			StatusCode: http.StatusNotFound,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+		}
+	})
+
+	_, err := svc.HeadObject(&s3.HeadObjectInput{Bucket: aws.String("bucket"), Key: aws.String("key")})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if !s3.IsNoSuchKey(err) {
+		t.Errorf("expect no such key, got %v", err)
+	}
+	if s3.IsThrottle(err) {
+		t.Errorf("expect not throttle, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package s3
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// DefaultCOSMaxRetries is the maximum number of retries of the COSRetryer
+// returned by NewCOSRetryer.
+const DefaultCOSMaxRetries = 5
+
+// DefaultCOSMaxRetryAfter is the longest Retry-After delay the COSRetryer
+// honors if its MaxRetryAfter is not set.
+const DefaultCOSMaxRetryAfter = 20 * time.Second
+
+// cosThrottleCodes are the error codes COS throttles requests with.
+var cosThrottleCodes = map[string]struct{}{
+	"SlowDown":           {},
+	"ServiceUnavailable": {},
+}
+
+// cosTransientCodes are the error codes of transient COS failures, such as
+// those returned while COS fails over.
+var cosTransientCodes = map[string]struct{}{
+	"InternalError":      {},
+	"ServiceUnavailable": {},
+	"SlowDown":           {},
+}
+
+// cosIdempotentPOSTOperations are the POST operations of S3 which are
+// idempotent, and retried as other operations are.
+var cosIdempotentPOSTOperations = map[string]struct{}{
+	opDeleteObjects:           {},
+	opCompleteMultipartUpload: {},
+}
+
+// cosTokenExpiredCodes are the error codes COS rejects requests signed with
+// an expired IAM token with.
+var cosTokenExpiredCodes = map[string]struct{}{
+	"ExpiredToken": {},
+	"TokenExpired": {},
+}
+
+// COSRetryer is a request.Retryer for IBM Cloud Object Storage. In addition
+// to the retries of client.DefaultRetryer it:
+//
+//   * throttles requests COS rejects with 503 SlowDown, or ServiceUnavailable,
+//   * retries 500 errors COS returns while failing over,
+//   * refreshes expired IAM tokens, and retries requests rejected with them,
+//   * delays the retries of 503 and 429 responses by at least their
+//     Retry-After delay, up to MaxRetryAfter,
+//   * never retries non-idempotent, POST, operations, such as
+//     CreateMultipartUpload, unless RetryNonIdempotent is set. The idempotent
+//     POST operations DeleteObjects and CompleteMultipartUpload are retried,
+//     and requests rejected with expired credentials are always retried.
+//
+//     sess := session.Must(session.NewSession())
+//     svc := s3.New(sess, request.WithRetryer(aws.NewConfig(), s3.NewCOSRetryer()))
+type COSRetryer struct {
+	client.DefaultRetryer
+
+	// MaxRetryAfter is the longest Retry-After delay honored. Longer delays
+	// are shortened to MaxRetryAfter. Defaults to DefaultCOSMaxRetryAfter if
+	// not set. Overrides the MaxRetryAfter of the DefaultRetryer.
+	MaxRetryAfter time.Duration
+
+	// RetryNonIdempotent allows non-idempotent operations to be retried. A
+	// retried non-idempotent operation may be applied more than once.
+	RetryNonIdempotent bool
+}
+
+// NewCOSRetryer returns a COSRetryer retrying requests up to
+// DefaultCOSMaxRetries times, configured by the options provided.
+func NewCOSRetryer(options ...func(*COSRetryer)) *COSRetryer {
+	r := &COSRetryer{
+		DefaultRetryer: client.DefaultRetryer{NumMaxRetries: DefaultCOSMaxRetries},
+	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
+}
+
+// ShouldRetry returns true if the request should be retried.
+func (d COSRetryer) ShouldRetry(r *request.Request) bool {
+	// Requests rejected with expired credentials were not applied, and are
+	// retried with refreshed credentials regardless of their idempotency.
+	if request.IsErrorExpiredCreds(r.Error) {
+		return true
+	}
+	if _, ok := cosTokenExpiredCodes[errCode(r.Error)]; ok {
+		// Expired credentials codes of the SDK are expired by the
+		// AfterRetryHandler. COS codes are expired here.
+		if r.Config.Credentials != nil {
+			r.Config.Credentials.Expire()
+		}
+		return true
+	}
+
+	if !d.RetryNonIdempotent && !isIdempotent(r) {
+		return false
+	}
+
+	// If one of the other handlers already set the retry state
+	// we don't want to override it based on the service's state
+	if r.Retryable != nil {
+		return *r.Retryable
+	}
+
+	if _, ok := cosTransientCodes[errCode(r.Error)]; ok {
+		return true
+	}
+
+	return d.DefaultRetryer.ShouldRetry(r)
+}
+
+// RetryRules returns the delay duration before retrying this request again.
+// Requests COS throttles with a 503 response, such as SlowDown, are delayed by
+// at least the response's Retry-After delay, shortened to MaxRetryAfter, and
+// by the exponential backoff of throttled requests otherwise.
+func (d COSRetryer) RetryRules(r *request.Request) time.Duration {
+	retryer := d.DefaultRetryer
+	retryer.MaxRetryAfter = d.MaxRetryAfter
+	if retryer.MaxRetryAfter <= 0 {
+		retryer.MaxRetryAfter = DefaultCOSMaxRetryAfter
+	}
+
+	delay := retryer.RetryRules(r)
+	if _, ok := cosThrottleCodes[errCode(r.Error)]; ok {
+		// Retry throttled requests with at least the delay of throttled
+		// status codes.
+		if min := time.Duration(1<<uint(minInt(r.RetryCount, 8))) * 500 * time.Millisecond; delay < min {
+			delay = min
+		}
+	}
+	return delay
+}
+
+// isIdempotent returns whether the request's operation is idempotent. Only
+// POST operations of S3 are not idempotent, except for those of
+// cosIdempotentPOSTOperations.
+func isIdempotent(r *request.Request) bool {
+	if r.Operation == nil || r.Operation.HTTPMethod != "POST" {
+		return true
+	}
+	_, ok := cosIdempotentPOSTOperations[r.Operation.Name]
+	return ok
+}
+
+func errCode(err error) string {
+	if aerr, ok := err.(awserr.Error); ok {
+		return aerr.Code()
+	}
+	return ""
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
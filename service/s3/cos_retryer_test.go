@@ -0,0 +1,231 @@
+package s3_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/credentialstest"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func newCOSRetryerClient(retryer *s3.COSRetryer, responses []*http.Response, calls *int) *s3.S3 {
+	svc := s3.New(unit.Session, request.WithRetryer(&aws.Config{
+		SleepDelay: func(time.Duration) {},
+	}, retryer))
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		resp := responses[*calls]
+		*calls++
+		r.HTTPResponse = resp
+	})
+	return svc
+}
+
+func newCOSErrorResponse(status int, code string, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	body := `<?xml version="1.0" encoding="UTF-8"?><Error><Code>` + code + `</Code><Message>message</Message></Error>`
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+	}
+}
+
+func newCOSOKResponse() *http.Response {
+	return &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+	}
+}
+
+func TestCOSRetryer_Retries(t *testing.T) {
+	cases := map[string]struct {
+		Response *http.Response
+		Retry    bool
+	}{
+		"slow down":           {newCOSErrorResponse(503, "SlowDown", nil), true},
+		"service unavailable": {newCOSErrorResponse(503, "ServiceUnavailable", nil), true},
+		"internal error":      {newCOSErrorResponse(500, "InternalError", nil), true},
+		"token expired":       {newCOSErrorResponse(403, "TokenExpired", nil), true},
+		"access denied":       {newCOSErrorResponse(403, "AccessDenied", nil), false},
+		"no such key":         {newCOSErrorResponse(404, "NoSuchKey", nil), false},
+	}
+
+	for name, c := range cases {
+		calls := 0
+		svc := newCOSRetryerClient(s3.NewCOSRetryer(),
+			[]*http.Response{c.Response, newCOSOKResponse()}, &calls)
+
+		_, err := svc.PutObject(&s3.PutObjectInput{
+			Bucket: aws.String("bucket"), Key: aws.String("key"),
+		})
+		if c.Retry {
+			if err != nil {
+				t.Errorf("%s, expect no error, got %v", name, err)
+			}
+			if e, a := 2, calls; e != a {
+				t.Errorf("%s, expect %v calls, got %v", name, e, a)
+			}
+		} else {
+			if err == nil {
+				t.Errorf("%s, expect error, got none", name)
+			}
+			if e, a := 1, calls; e != a {
+				t.Errorf("%s, expect %v calls, got %v", name, e, a)
+			}
+		}
+	}
+}
+
+func TestCOSRetryer_NonIdempotent(t *testing.T) {
+	for _, allow := range []bool{false, true} {
+		calls := 0
+		retryer := s3.NewCOSRetryer(func(r *s3.COSRetryer) {
+			r.RetryNonIdempotent = allow
+		})
+		svc := newCOSRetryerClient(retryer, []*http.Response{
+			newCOSErrorResponse(503, "SlowDown", nil), newCOSOKResponse(),
+		}, &calls)
+
+		svc.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+			Bucket: aws.String("bucket"), Key: aws.String("key"),
+		})
+
+		e := 1
+		if allow {
+			e = 2
+		}
+		if a := calls; e != a {
+			t.Errorf("allow %v, expect %v calls, got %v", allow, e, a)
+		}
+	}
+}
+
+func TestCOSRetryer_IdempotentPOST(t *testing.T) {
+	cases := map[string]struct {
+		Send   func(*s3.S3) error
+		Status int
+		Code   string
+		Calls  int
+	}{
+		"delete objects": {
+			Send: func(svc *s3.S3) error {
+				_, err := svc.DeleteObjects(&s3.DeleteObjectsInput{
+					Bucket: aws.String("bucket"),
+					Delete: &s3.Delete{Objects: []*s3.ObjectIdentifier{{Key: aws.String("key")}}},
+				})
+				return err
+			},
+			Status: 503, Code: "SlowDown", Calls: 2,
+		},
+		"complete multipart upload": {
+			Send: func(svc *s3.S3) error {
+				_, err := svc.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+					Bucket: aws.String("bucket"), Key: aws.String("key"), UploadId: aws.String("id"),
+				})
+				return err
+			},
+			Status: 500, Code: "InternalError", Calls: 2,
+		},
+		"create multipart upload token expired": {
+			Send: func(svc *s3.S3) error {
+				_, err := svc.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+					Bucket: aws.String("bucket"), Key: aws.String("key"),
+				})
+				return err
+			},
+			Status: 403, Code: "TokenExpired", Calls: 2,
+		},
+		"create multipart upload expired token": {
+			Send: func(svc *s3.S3) error {
+				_, err := svc.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+					Bucket: aws.String("bucket"), Key: aws.String("key"),
+				})
+				return err
+			},
+			Status: 400, Code: "ExpiredToken", Calls: 2,
+		},
+	}
+
+	for name, c := range cases {
+		calls := 0
+		svc := newCOSRetryerClient(s3.NewCOSRetryer(), []*http.Response{
+			newCOSErrorResponse(c.Status, c.Code, nil), newCOSOKResponse(),
+		}, &calls)
+
+		if err := c.Send(svc); err != nil {
+			t.Errorf("%s, expect no error, got %v", name, err)
+		}
+		if e, a := c.Calls, calls; e != a {
+			t.Errorf("%s, expect %v calls, got %v", name, e, a)
+		}
+	}
+}
+
+func TestCOSRetryer_TokenExpired(t *testing.T) {
+	provider := &credentialstest.MockProvider{Value: credentials.Value{
+		AccessKeyID: "AKID", SecretAccessKey: "SECRET",
+	}}
+	calls := 0
+	svc := newCOSRetryerClient(s3.NewCOSRetryer(), []*http.Response{
+		newCOSErrorResponse(403, "TokenExpired", nil), newCOSOKResponse(),
+	}, &calls)
+	svc.Config.Credentials = credentials.NewCredentials(provider)
+
+	_, err := svc.HeadBucket(&s3.HeadBucketInput{Bucket: aws.String("bucket")})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 2, provider.RetrieveCount(); e != a {
+		t.Errorf("expect credentials to be refreshed, got %v retrievals", a)
+	}
+}
+
+func TestCOSRetryer_RetryAfter(t *testing.T) {
+	retryer := s3.NewCOSRetryer(func(r *s3.COSRetryer) {
+		r.MaxRetryAfter = 10 * time.Second
+	})
+
+	cases := map[string]struct {
+		Status int
+		Code   string
+		Header string
+		Min    time.Duration
+		Max    time.Duration
+	}{
+		"seconds":  {503, "SlowDown", "3", 3 * time.Second, 3 * time.Second},
+		"capped":   {503, "SlowDown", "3600", 10 * time.Second, 10 * time.Second},
+		"date":     {503, "SlowDown", time.Now().Add(5 * time.Second).UTC().Format(http.TimeFormat), 3 * time.Second, 5 * time.Second},
+		"past":     {503, "SlowDown", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 500 * time.Millisecond, 1500 * time.Millisecond},
+		"invalid":  {503, "SlowDown", "soon", 500 * time.Millisecond, 1500 * time.Millisecond},
+		"throttle": {503, "SlowDown", "", 500 * time.Millisecond, 1500 * time.Millisecond},
+		"internal": {500, "InternalError", "3", 0, 60 * time.Millisecond},
+	}
+
+	for name, c := range cases {
+		header := http.Header{}
+		if len(c.Header) != 0 {
+			header.Set("Retry-After", c.Header)
+		}
+		r := &request.Request{
+			HTTPResponse: newCOSErrorResponse(c.Status, c.Code, header),
+			Error:        awserr.New(c.Code, "message", nil),
+		}
+
+		delay := retryer.RetryRules(r)
+		if delay < c.Min || delay > c.Max {
+			t.Errorf("%s, expect delay between %v and %v, got %v", name, c.Min, c.Max, delay)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package s3_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestCOSRetentionHeaders(t *testing.T) {
+	svc := s3.New(unit.Session)
+	expires := time.Date(2030, 1, 12, 3, 4, 5, 0, time.UTC)
+
+	req, _ := svc.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:                  aws.String("bucket"),
+		Key:                     aws.String("key"),
+		RetentionExpirationDate: aws.Time(expires),
+		RetentionLegalHoldId:    aws.String("hold"),
+		RetentionPeriod:         aws.Int64(3600),
+	})
+	if err := req.Build(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	cases := map[string]string{
+		"Retention-Expiration-Date": "Sat, 12 Jan 2030 03:04:05 GMT",
+		"Retention-Legal-Hold-Id":   "hold",
+		"Retention-Period":          "3600",
+	}
+	for k, e := range cases {
+		if a := req.HTTPRequest.Header.Get(k); e != a {
+			t.Errorf("%s, expect %v, got %v", k, e, a)
+		}
+	}
+}
+
+func TestCOSRetentionLegalHoldIdValidation(t *testing.T) {
+	svc := s3.New(unit.Session)
+
+	req, _ := svc.CopyObjectRequest(&s3.CopyObjectInput{
+		Bucket:               aws.String("bucket"),
+		Key:                  aws.String("key"),
+		CopySource:           aws.String("bucket/source"),
+		RetentionDirective:   aws.String(s3.RetentionDirectiveReplace),
+		RetentionLegalHoldId: aws.String(""),
+	})
+	err := req.Build()
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := request.InvalidParameterErrCode, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
+
+func TestCOSKeyProtectHeaders(t *testing.T) {
+	svc := s3.New(unit.Session)
+	crn := "crn:v1:bluemix:public:kms:us-south:a/account:instance:key:root"
+
+	req, _ := svc.CreateBucketRequest(&s3.CreateBucketInput{
+		Bucket:                      aws.String("bucket"),
+		IBMSSEKPCustomerRootKeyCrn:  aws.String(crn),
+		IBMSSEKPEncryptionAlgorithm: aws.String(s3.IBMSSEKPEncryptionAlgorithmAes256),
+	})
+	if err := req.Build(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := crn, req.HTTPRequest.Header.Get("ibm-sse-kp-customer-root-key-crn"); e != a {
+		t.Errorf("expect %v root key CRN, got %v", e, a)
+	}
+	if e, a := "AES256", req.HTTPRequest.Header.Get("ibm-sse-kp-encryption-algorithm"); e != a {
+		t.Errorf("expect %v algorithm, got %v", e, a)
+	}
+
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{
+			StatusCode: 200,
+			Header: http.Header{
+				"Ibm-Sse-Kp-Customer-Root-Key-Crn": []string{crn},
+				"Ibm-Sse-Kp-Enabled":               []string{"true"},
+			},
+			Body: ioutil.NopCloser(bytes.NewReader(nil)),
+		}
+	})
+
+	resp, err := svc.HeadBucket(&s3.HeadBucketInput{Bucket: aws.String("bucket")})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := crn, aws.StringValue(resp.IBMSSEKPCrkId); e != a {
+		t.Errorf("expect %v root key CRN, got %v", e, a)
+	}
+	if !aws.BoolValue(resp.IBMSSEKPEnabled) {
+		t.Errorf("expect Key Protect enabled")
+	}
+}
+
+func TestCOSHeadObjectRetention(t *testing.T) {
+	svc := s3.New(unit.Session)
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{
+			StatusCode: 200,
+			Header: http.Header{
+				"Retention-Expiration-Date":  []string{"Sat, 12 Jan 2030 03:04:05 GMT"},
+				"Retention-Legal-Hold-Count": []string{"2"},
+				"Retention-Period":           []string{"3600"},
+				"X-Ibm-Transition":           []string{`transition="ARCHIVE", date="Mon, 01 Jan 2029 00:00:00 GMT"`},
+			},
+			Body: ioutil.NopCloser(bytes.NewReader(nil)),
+		}
+	})
+
+	resp, err := svc.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String("bucket"), Key: aws.String("key"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := time.Date(2030, 1, 12, 3, 4, 5, 0, time.UTC), aws.TimeValue(resp.RetentionExpirationDate); !e.Equal(a) {
+		t.Errorf("expect %v expiration date, got %v", e, a)
+	}
+	if e, a := int64(2), aws.Int64Value(resp.RetentionLegalHoldCount); e != a {
+		t.Errorf("expect %v legal holds, got %v", e, a)
+	}
+	if e, a := int64(3600), aws.Int64Value(resp.RetentionPeriod); e != a {
+		t.Errorf("expect %v retention period, got %v", e, a)
+	}
+	if len(aws.StringValue(resp.IBMTransition)) == 0 {
+		t.Errorf("expect transition, got none")
+	}
+}
@@ -0,0 +1,172 @@
+package s3
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const (
+	// DefaultCOSWaiterMaxAttempts is the default number of attempts the COS
+	// waiters make before giving up.
+	DefaultCOSWaiterMaxAttempts = 40
+
+	// DefaultCOSWaiterMinDelay is the default delay after the first attempt
+	// of the COS waiters. The delay doubles after each attempt, up to
+	// DefaultCOSWaiterMaxDelay.
+	DefaultCOSWaiterMinDelay = 1 * time.Second
+
+	// DefaultCOSWaiterMaxDelay is the default maximum delay between attempts
+	// of the COS waiters.
+	DefaultCOSWaiterMaxDelay = 10 * time.Second
+)
+
+// COSWaiterDelay returns a WaiterDelay doubling the delay between attempts,
+// starting from minDelay, up to maxDelay.
+//
+// Bucket and object changes are usually visible in IBM Cloud Object Storage
+// within a second or two, so the first attempts are made quickly, while
+// changes taking longer to complete, such as a bucket deletion, are polled
+// less frequently.
+func COSWaiterDelay(minDelay, maxDelay time.Duration) request.WaiterDelay {
+	return func(attempt int) time.Duration {
+		delay := minDelay
+		for i := 1; i < attempt && delay < maxDelay; i++ {
+			delay *= 2
+		}
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+		return delay
+	}
+}
+
+// WaitUntilCOSBucketExists uses the HeadBucket API operation to wait for a
+// bucket to exist, polling with the COS waiter defaults.
+//
+// Unlike WaitUntilBucketExists, a 409 Conflict response, which IBM Cloud
+// Object Storage returns while a bucket is being created or deleted, or while
+// the name of a recently deleted bucket is reserved, is retried rather than
+// failing the waiter.
+func (c *S3) WaitUntilCOSBucketExists(input *HeadBucketInput) error {
+	return c.WaitUntilCOSBucketExistsWithContext(aws.BackgroundContext(), input)
+}
+
+// WaitUntilCOSBucketExistsWithContext is the same as WaitUntilCOSBucketExists
+// with the addition of the ability to pass a context and options to configure
+// the Waiter and the underlying request options.
+//
+// See WaitUntilCOSBucketExists for details on how to use this waiter.
+func (c *S3) WaitUntilCOSBucketExistsWithContext(ctx aws.Context, input *HeadBucketInput, opts ...request.WaiterOption) error {
+	w := c.newCOSWaiter("WaitUntilCOSBucketExists", []request.WaiterAcceptor{
+		statusAcceptor(request.SuccessWaiterState, http.StatusOK),
+		statusAcceptor(request.SuccessWaiterState, http.StatusMovedPermanently),
+		statusAcceptor(request.SuccessWaiterState, http.StatusForbidden),
+		statusAcceptor(request.RetryWaiterState, http.StatusNotFound),
+		statusAcceptor(request.RetryWaiterState, http.StatusConflict),
+	}, func(opts []request.Option) (*request.Request, error) {
+		return c.newHeadBucketWaiterRequest(ctx, input, opts)
+	})
+	w.ApplyOptions(opts...)
+
+	return w.WaitWithContext(ctx)
+}
+
+// WaitUntilCOSBucketNotExists uses the HeadBucket API operation to wait for
+// a bucket to no longer exist, polling with the COS waiter defaults.
+//
+// A 409 Conflict response, returned while the bucket's deletion completes and
+// its name is reserved, is retried. The waiter returns once the bucket is
+// reported as not found.
+func (c *S3) WaitUntilCOSBucketNotExists(input *HeadBucketInput) error {
+	return c.WaitUntilCOSBucketNotExistsWithContext(aws.BackgroundContext(), input)
+}
+
+// WaitUntilCOSBucketNotExistsWithContext is the same as
+// WaitUntilCOSBucketNotExists with the addition of the ability to pass a
+// context and options to configure the Waiter and the underlying request
+// options.
+//
+// See WaitUntilCOSBucketNotExists for details on how to use this waiter.
+func (c *S3) WaitUntilCOSBucketNotExistsWithContext(ctx aws.Context, input *HeadBucketInput, opts ...request.WaiterOption) error {
+	w := c.newCOSWaiter("WaitUntilCOSBucketNotExists", []request.WaiterAcceptor{
+		statusAcceptor(request.SuccessWaiterState, http.StatusNotFound),
+		statusAcceptor(request.RetryWaiterState, http.StatusConflict),
+	}, func(opts []request.Option) (*request.Request, error) {
+		return c.newHeadBucketWaiterRequest(ctx, input, opts)
+	})
+	w.ApplyOptions(opts...)
+
+	return w.WaitWithContext(ctx)
+}
+
+// WaitUntilCOSObjectExists uses the HeadObject API operation to wait for an
+// object to exist, polling with the COS waiter defaults. A 409 Conflict
+// response, returned while the object's bucket is being created, is retried.
+func (c *S3) WaitUntilCOSObjectExists(input *HeadObjectInput) error {
+	return c.WaitUntilCOSObjectExistsWithContext(aws.BackgroundContext(), input)
+}
+
+// WaitUntilCOSObjectExistsWithContext is the same as WaitUntilCOSObjectExists
+// with the addition of the ability to pass a context and options to configure
+// the Waiter and the underlying request options.
+//
+// See WaitUntilCOSObjectExists for details on how to use this waiter.
+func (c *S3) WaitUntilCOSObjectExistsWithContext(ctx aws.Context, input *HeadObjectInput, opts ...request.WaiterOption) error {
+	w := c.newCOSWaiter("WaitUntilCOSObjectExists", []request.WaiterAcceptor{
+		statusAcceptor(request.SuccessWaiterState, http.StatusOK),
+		statusAcceptor(request.RetryWaiterState, http.StatusNotFound),
+		statusAcceptor(request.RetryWaiterState, http.StatusConflict),
+	}, func(opts []request.Option) (*request.Request, error) {
+		var inCpy *HeadObjectInput
+		if input != nil {
+			tmp := *input
+			inCpy = &tmp
+		}
+		req, _ := c.HeadObjectRequest(inCpy)
+		req.SetContext(ctx)
+		req.ApplyOptions(opts...)
+		return req, nil
+	})
+	w.ApplyOptions(opts...)
+
+	return w.WaitWithContext(ctx)
+}
+
+// newCOSWaiter returns a Waiter with the COS waiter defaults.
+func (c *S3) newCOSWaiter(name string, acceptors []request.WaiterAcceptor, newRequest func([]request.Option) (*request.Request, error)) request.Waiter {
+	return request.Waiter{
+		Name:        name,
+		MaxAttempts: DefaultCOSWaiterMaxAttempts,
+		Delay:       COSWaiterDelay(DefaultCOSWaiterMinDelay, DefaultCOSWaiterMaxDelay),
+		Acceptors:   acceptors,
+		Logger:      c.Config.Logger,
+		NewRequest:  newRequest,
+	}
+}
+
+// newHeadBucketWaiterRequest returns a HeadBucket request of a waiter's
+// attempt for a copy of the input.
+func (c *S3) newHeadBucketWaiterRequest(ctx aws.Context, input *HeadBucketInput, opts []request.Option) (*request.Request, error) {
+	var inCpy *HeadBucketInput
+	if input != nil {
+		tmp := *input
+		inCpy = &tmp
+	}
+	req, _ := c.HeadBucketRequest(inCpy)
+	req.SetContext(ctx)
+	req.ApplyOptions(opts...)
+	return req, nil
+}
+
+// statusAcceptor returns a WaiterAcceptor transitioning the waiter to the
+// state when a response has the HTTP status code.
+func statusAcceptor(state request.WaiterState, status int) request.WaiterAcceptor {
+	return request.WaiterAcceptor{
+		State:    state,
+		Matcher:  request.StatusWaiterMatch,
+		Expected: status,
+	}
+}
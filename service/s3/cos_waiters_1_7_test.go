@@ -0,0 +1,44 @@
+// +build go1.7
+
+package s3_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestWaitUntilCOSBucketExists(t *testing.T) {
+	cases := map[string]struct {
+		statuses []int
+		attempts int
+		err      bool
+	}{
+		"exists":         {statuses: []int{200}, attempts: 1},
+		"not owned":      {statuses: []int{403}, attempts: 1},
+		"created":        {statuses: []int{404, 404, 200}, attempts: 3},
+		"name reserved":  {statuses: []int{409, 409, 200}, attempts: 3},
+		"never created":  {statuses: []int{404}, attempts: 3, err: true},
+		"internal error": {statuses: []int{500}, attempts: 3, err: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			svc, attempts := newCOSWaiterTestSvc(c.statuses...)
+
+			err := svc.WaitUntilCOSBucketExistsWithContext(aws.BackgroundContext(),
+				&s3.HeadBucketInput{Bucket: aws.String("bucket")},
+				request.WithWaiterDelay(request.ConstantWaiterDelay(0)),
+				request.WithWaiterMaxAttempts(3),
+			)
+			if e, a := c.err, err != nil; e != a {
+				t.Errorf("expect error %v, got %v", e, err)
+			}
+			if e, a := c.attempts, *attempts; e != a {
+				t.Errorf("expect %v attempts, got %v", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package s3_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func newCOSWaiterTestSvc(statuses ...int) (*s3.S3, *int) {
+	var attempts int
+
+	svc := s3.New(unit.Session, &aws.Config{MaxRetries: aws.Int(0)})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		status := statuses[len(statuses)-1]
+		if attempts < len(statuses) {
+			status = statuses[attempts]
+		}
+		attempts++
+		r.HTTPResponse = &http.Response{
+			StatusCode: status,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}
+	})
+
+	return svc, &attempts
+}
+
+func TestCOSWaiterDelay(t *testing.T) {
+	delay := s3.COSWaiterDelay(time.Second, 10*time.Second)
+
+	for attempt, e := range map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		3:  4 * time.Second,
+		4:  8 * time.Second,
+		5:  10 * time.Second,
+		30: 10 * time.Second,
+	} {
+		if a := delay(attempt); e != a {
+			t.Errorf("attempt %d, expect %v delay, got %v", attempt, e, a)
+		}
+	}
+}
+
+func TestWaitUntilCOSBucketNotExists(t *testing.T) {
+	svc, attempts := newCOSWaiterTestSvc(200, 409, 404)
+
+	err := svc.WaitUntilCOSBucketNotExistsWithContext(aws.BackgroundContext(),
+		&s3.HeadBucketInput{Bucket: aws.String("bucket")},
+		request.WithWaiterDelay(request.ConstantWaiterDelay(0)),
+	)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 3, *attempts; e != a {
+		t.Errorf("expect %v attempts, got %v", e, a)
+	}
+
+	svc, _ = newCOSWaiterTestSvc(409)
+	err = svc.WaitUntilCOSBucketNotExistsWithContext(aws.BackgroundContext(),
+		&s3.HeadBucketInput{Bucket: aws.String("bucket")},
+		request.WithWaiterDelay(request.ConstantWaiterDelay(0)),
+		request.WithWaiterMaxAttempts(2),
+	)
+	aerr, ok := err.(awserr.Error)
+	if !ok {
+		t.Fatalf("expect awserr.Error, got %T %v", err, err)
+	}
+	if e, a := request.WaiterResourceNotReadyErrorCode, aerr.Code(); e != a {
+		t.Errorf("expect %v code, got %v", e, a)
+	}
+}
+
+func TestWaitUntilCOSObjectExists(t *testing.T) {
+	svc, attempts := newCOSWaiterTestSvc(409, 404, 200)
+
+	err := svc.WaitUntilCOSObjectExistsWithContext(aws.BackgroundContext(),
+		&s3.HeadObjectInput{Bucket: aws.String("bucket"), Key: aws.String("key")},
+		request.WithWaiterDelay(request.ConstantWaiterDelay(0)),
+	)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 3, *attempts; e != a {
+		t.Errorf("expect %v attempts, got %v", e, a)
+	}
+}
@@ -28,6 +28,9 @@ func defaultInitRequestFn(r *request.Request) {
 	// e.g. 100-continue support for PUT requests using Go 1.6
 	platformRequestHandlers(r)
 
+	// Encode object keys consistently across operations if enabled
+	initStrictKeyEncoding(r)
+
 	switch r.Operation.Name {
 	case opPutBucketCors, opPutBucketLifecycle, opPutBucketPolicy,
 		opPutBucketTagging, opDeleteObjects, opPutBucketLifecycleConfiguration,
@@ -40,8 +43,23 @@ func defaultInitRequestFn(r *request.Request) {
 	case opCreateBucket:
 		// Auto-populate LocationConstraint with current region
 		r.Handlers.Validate.PushFront(populateLocationConstraint)
+		// Validate the COS bucket name and LocationConstraint, if enabled,
+		// before the LocationConstraint is auto-populated.
+		r.Handlers.Validate.PushFront(validateCOSCreateBucket)
 	case opCopyObject, opUploadPartCopy, opCompleteMultipartUpload:
 		r.Handlers.Unmarshal.PushFront(copyMultipartStatusOKUnmarhsalError)
+	case opPutObject, opUploadPart:
+		// Compute and verify the body's checksum if enabled
+		r.Handlers.Build.PushBack(computeBodyChecksum)
+		r.Handlers.Unmarshal.PushBack(verifyBodyChecksum)
+	case opGetObject:
+		// Validate the object's checksum while reading if enabled
+		r.Handlers.Build.PushBack(enableResponseChecksum)
+		r.Handlers.Unmarshal.PushBack(validateResponseChecksum)
+	case opSelectObjectContent:
+		// Fail with a NotSupported error on endpoints without S3 Select
+		r.Handlers.UnmarshalError.PushBack(selectNotSupportedError)
+		r.Handlers.Unmarshal.PushFront(verifySelectEventStream)
 	}
 }
 
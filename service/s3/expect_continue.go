@@ -0,0 +1,175 @@
+package s3
+
+import (
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// DefaultExpectContinueThreshold is the content length from which PUT
+// requests are sent with the Expect: 100-Continue header, if not configured
+// with WithExpectContinue.
+const DefaultExpectContinueThreshold = 1024 * 1024 * 2
+
+// add100ContinueHandlerName is the name of the Sign handler adding the
+// Expect: 100-Continue header to PUT requests.
+const add100ContinueHandlerName = "awssdk.s3.Add100Continue"
+
+// ExpectContinue configures the Expect: 100-Continue header of PUT requests,
+// such as PutObject and UploadPart, with WithExpectContinue. 100-Continue
+// instructs the HTTP client not to send the body until the service responds
+// with a continue status, so that the body of a request the service rejects
+// is not sent.
+type ExpectContinue struct {
+	// Disable disables the Expect: 100-Continue header. Requests are sent
+	// with their body without waiting for a continue status.
+	Disable bool
+
+	// Threshold is the content length from which requests are sent with the
+	// Expect: 100-Continue header. Defaults to
+	// DefaultExpectContinueThreshold if 0.
+	Threshold int64
+
+	// Timeout is how long the HTTP client waits for the continue status
+	// before sending the body anyway. Defaults to the ExpectContinueTimeout
+	// of the client's http.Transport if 0. Requests with a Timeout are sent
+	// with a copy of the transport, which does not share its connections.
+	// The copy is shared by the requests made with the same
+	// WithExpectContinue option. The Timeout is ignored if the client's
+	// transport is not an *http.Transport, and before Go 1.6.
+	Timeout time.Duration
+}
+
+// WithExpectContinue is a request option configuring the Expect:
+// 100-Continue header of PUT requests, overriding the
+// aws.Config.S3Disable100Continue of the client. Use it for the operations
+// whose requests pass through gateways that handle 100-Continue poorly,
+// instead of disabling 100-Continue for the whole client or transport.
+//
+//     uploader := s3manager.NewUploader(sess, func(u *s3manager.Uploader) {
+//         u.RequestOptions = append(u.RequestOptions, s3.WithExpectContinue(
+//             s3.ExpectContinue{Threshold: 64 * 1024 * 1024, Timeout: 3 * time.Second},
+//         ))
+//     })
+//
+// The option has no effect on requests of operations other than PUT.
+func WithExpectContinue(cfg ExpectContinue) request.Option {
+	clients := &expectContinueClients{}
+	return func(r *request.Request) {
+		r.Handlers.Sign.SwapNamed(request.NamedHandler{
+			Name: add100ContinueHandlerName,
+			Fn: func(r *request.Request) {
+				cfg.add(r, clients)
+			},
+		})
+	}
+}
+
+// add100ContinueHandler adds the Expect: 100-Continue header to PUT
+// requests, unless disabled by the S3Disable100Continue config.
+var add100ContinueHandler = request.NamedHandler{
+	Name: add100ContinueHandlerName,
+	Fn: func(r *request.Request) {
+		cfg := ExpectContinue{Disable: aws.BoolValue(r.Config.S3Disable100Continue)}
+		cfg.add(r, nil)
+	},
+}
+
+// add adds the Expect: 100-Continue header to requests with content from
+// the Threshold, sending them with the Timeout if set, with the HTTP client
+// of the clients.
+func (cfg ExpectContinue) add(r *request.Request, clients *expectContinueClients) {
+	if cfg.Disable {
+		return
+	}
+
+	threshold := cfg.Threshold
+	if threshold <= 0 {
+		threshold = DefaultExpectContinueThreshold
+	}
+	if r.HTTPRequest.ContentLength < threshold {
+		// Ignore smaller requests. This helps prevent delaying requests
+		// unnecessarily.
+		return
+	}
+
+	r.HTTPRequest.Header.Set("Expect", "100-Continue")
+	if cfg.Timeout > 0 && clients != nil {
+		r.Config.HTTPClient = clients.get(r.Config.HTTPClient, cfg.Timeout)
+	}
+}
+
+// expectContinueClients caches the copies of the HTTP clients of the
+// requests made with a WithExpectContinue option, so that the requests share
+// connections. A client is copied again if its transport is replaced. The
+// copies are released with the option.
+type expectContinueClients struct {
+	m       sync.Mutex
+	clients map[*http.Client]expectContinueClient
+}
+
+// expectContinueClient is the copy of an HTTP client, and the transport it
+// was copied from.
+type expectContinueClient struct {
+	transport http.RoundTripper
+	client    *http.Client
+}
+
+// get returns a copy of the HTTP client whose transport waits for the
+// continue status for the timeout. The client is returned unchanged if its
+// transport is not an *http.Transport.
+func (c *expectContinueClients) get(client *http.Client, timeout time.Duration) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	rt := client.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		return client
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if cached, ok := c.clients[client]; ok && cached.transport == rt {
+		return cached.client
+	}
+
+	nt := expectContinueTransport(t, timeout)
+	if nt == t {
+		return client
+	}
+
+	cpy := *client
+	cpy.Transport = nt
+	if c.clients == nil {
+		c.clients = map[*http.Client]expectContinueClient{}
+	}
+	c.clients[client] = expectContinueClient{transport: rt, client: &cpy}
+
+	return &cpy
+}
+
+// copyHTTPTransport returns a copy of the transport's exported fields, the
+// transport's configuration, without its connections. http.Transport's Clone
+// method is only available as of Go 1.13, and the fields of the transport
+// differ by Go version, so they are copied with reflection.
+func copyHTTPTransport(t *http.Transport) *http.Transport {
+	dst := &http.Transport{}
+	dv := reflect.ValueOf(dst).Elem()
+	sv := reflect.ValueOf(t).Elem()
+	for i := 0; i < sv.NumField(); i++ {
+		if len(sv.Type().Field(i).PkgPath) != 0 {
+			continue
+		}
+		dv.Field(i).Set(sv.Field(i))
+	}
+	return dst
+}
@@ -0,0 +1,14 @@
+// +build !go1.6
+
+package s3
+
+import (
+	"net/http"
+	"time"
+)
+
+// expectContinueTransport returns the transport, as the timeout of the
+// continue status cannot be configured before Go 1.6.
+func expectContinueTransport(t *http.Transport, timeout time.Duration) *http.Transport {
+	return t
+}
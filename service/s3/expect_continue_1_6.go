@@ -0,0 +1,21 @@
+// +build go1.6
+
+package s3
+
+import (
+	"net/http"
+	"time"
+)
+
+// expectContinueTransport returns a copy of the transport waiting for the
+// continue status for the timeout, or the transport if it already waits for
+// the timeout.
+func expectContinueTransport(t *http.Transport, timeout time.Duration) *http.Transport {
+	if t.ExpectContinueTimeout == timeout {
+		return t
+	}
+
+	cpy := copyHTTPTransport(t)
+	cpy.ExpectContinueTimeout = timeout
+	return cpy
+}
@@ -0,0 +1,130 @@
+// +build go1.6
+
+package s3_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestWithExpectContinue(t *testing.T) {
+	transport := &http.Transport{ExpectContinueTimeout: time.Second}
+	client := &http.Client{Transport: transport}
+
+	cases := map[string]struct {
+		Config    *aws.Config
+		Option    s3.ExpectContinue
+		Size      int
+		Expect    string
+		Timeout   time.Duration
+		GetObject bool
+	}{
+		"threshold": {
+			Option: s3.ExpectContinue{Threshold: 1024},
+			Size:   2048, Expect: "100-Continue", Timeout: time.Second,
+		},
+		"below threshold": {
+			Option: s3.ExpectContinue{Threshold: 1024 * 1024 * 8},
+			Size:   1024 * 1024 * 5, Timeout: time.Second,
+		},
+		"disabled": {
+			Option: s3.ExpectContinue{Disable: true},
+			Size:   1024 * 1024 * 5, Timeout: time.Second,
+		},
+		"overrides client disable": {
+			Config: aws.NewConfig().WithS3Disable100Continue(true),
+			Option: s3.ExpectContinue{},
+			Size:   1024 * 1024 * 5, Expect: "100-Continue", Timeout: time.Second,
+		},
+		"timeout": {
+			Option: s3.ExpectContinue{Timeout: 3 * time.Second},
+			Size:   1024 * 1024 * 5, Expect: "100-Continue", Timeout: 3 * time.Second,
+		},
+		"non PUT": {
+			Option:    s3.ExpectContinue{Threshold: 1, Timeout: 3 * time.Second},
+			GetObject: true, Timeout: time.Second,
+		},
+	}
+
+	for name, c := range cases {
+		cfg := aws.NewConfig().WithHTTPClient(client)
+		cfg.MergeIn(c.Config)
+		svc := s3.New(unit.Session, cfg)
+
+		var err error
+		var expect string
+		var httpClient *http.Client
+		if c.GetObject {
+			r, _ := svc.GetObjectRequest(&s3.GetObjectInput{
+				Bucket: aws.String("bucket"), Key: aws.String("key"),
+			})
+			r.ApplyOptions(s3.WithExpectContinue(c.Option))
+			err = r.Sign()
+			expect, httpClient = r.HTTPRequest.Header.Get("Expect"), r.Config.HTTPClient
+		} else {
+			r, _ := svc.PutObjectRequest(&s3.PutObjectInput{
+				Bucket: aws.String("bucket"), Key: aws.String("key"),
+				Body: bytes.NewReader(make([]byte, c.Size)),
+			})
+			r.ApplyOptions(s3.WithExpectContinue(c.Option))
+			err = r.Sign()
+			expect, httpClient = r.HTTPRequest.Header.Get("Expect"), r.Config.HTTPClient
+		}
+
+		if err != nil {
+			t.Fatalf("%s, expect no error, got %v", name, err)
+		}
+		if e, a := c.Expect, expect; e != a {
+			t.Errorf("%s, expect %q Expect header, got %q", name, e, a)
+		}
+		if e, a := c.Timeout, httpClient.Transport.(*http.Transport).ExpectContinueTimeout; e != a {
+			t.Errorf("%s, expect %v continue timeout, got %v", name, e, a)
+		}
+	}
+
+	if e, a := time.Second, transport.ExpectContinueTimeout; e != a {
+		t.Errorf("expect client transport not to be modified, got %v", a)
+	}
+}
+
+func TestWithExpectContinue_ClientCache(t *testing.T) {
+	client := &http.Client{Transport: &http.Transport{}}
+	svc := s3.New(unit.Session, aws.NewConfig().WithHTTPClient(client))
+	option := s3.WithExpectContinue(s3.ExpectContinue{Timeout: 3 * time.Second})
+
+	send := func(opt request.Option) *http.Client {
+		r, _ := svc.PutObjectRequest(&s3.PutObjectInput{
+			Bucket: aws.String("bucket"), Key: aws.String("key"),
+			Body: bytes.NewReader(make([]byte, s3.DefaultExpectContinueThreshold)),
+		})
+		r.ApplyOptions(opt)
+		if err := r.Sign(); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		return r.Config.HTTPClient
+	}
+
+	first := send(option)
+	if first == client {
+		t.Fatalf("expect copy of client")
+	}
+	if e, a := first, send(option); e != a {
+		t.Errorf("expect requests of the option to share the client copy")
+	}
+	if e, a := first, send(s3.WithExpectContinue(s3.ExpectContinue{Timeout: 3 * time.Second})); e == a {
+		t.Errorf("expect client copy not to be shared between options")
+	}
+
+	// Replacing the client's transport copies the client again.
+	client.Transport = &http.Transport{}
+	if e, a := first, send(option); e == a {
+		t.Errorf("expect client to be copied again once its transport is replaced")
+	}
+}
@@ -35,6 +35,10 @@ var accelerateOpBlacklist = operationBlacklist{
 // DNS compatible and do not contain "."
 func updateEndpointForS3Config(r *request.Request) {
 	forceHostStyle := aws.BoolValue(r.Config.S3ForcePathStyle)
+	if r.Config.S3ForcePathStyle == nil && aws.BoolValue(r.Config.S3EmulatorMode) {
+		// Emulators address buckets in the path by default.
+		forceHostStyle = true
+	}
 	accelerate := aws.BoolValue(r.Config.S3UseAccelerate)
 
 	if accelerate && accelerateOpBlacklist.Continue(r) {
@@ -0,0 +1,134 @@
+package s3
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/private/protocol/rest"
+)
+
+// initStrictKeyEncoding adds the handlers encoding the request's object keys
+// consistently, if the S3StrictKeyEncoding config is enabled.
+func initStrictKeyEncoding(r *request.Request) {
+	if !aws.BoolValue(r.Config.S3StrictKeyEncoding) {
+		return
+	}
+
+	// Cleaning the path would remove the dot segments and duplicate slashes
+	// of keys, addressing a different object.
+	r.Config.DisableRestProtocolURICleaning = aws.Bool(true)
+
+	switch r.Operation.Name {
+	case opCopyObject, opUploadPartCopy:
+		r.Handlers.Build.PushBack(encodeCopySource)
+	case opListObjects, opListObjectsV2, opListObjectVersions:
+		r.Handlers.Build.PushBack(requestURLEncodedKeys)
+	}
+}
+
+// encodeCopySource re-encodes the x-amz-copy-source header with the same
+// encoding as request paths. The source's key is expected to be URL encoded,
+// and is used as is if it is not a valid encoding.
+func encodeCopySource(r *request.Request) {
+	src := r.HTTPRequest.Header.Get("X-Amz-Copy-Source")
+	if len(src) == 0 {
+		return
+	}
+
+	var query string
+	if i := strings.Index(src, "?"); i >= 0 {
+		src, query = src[:i], src[i:]
+	}
+	if unescaped, err := pathUnescape(src); err == nil {
+		src = unescaped
+	}
+
+	r.HTTPRequest.Header.Set("X-Amz-Copy-Source", rest.EscapePath(src, false)+query)
+}
+
+// requestURLEncodedKeys requests the keys of the listing to be URL encoded,
+// so that keys with characters XML cannot represent are listed, and decodes
+// them once unmarshaled. Listings with an EncodingType set are not modified.
+func requestURLEncodedKeys(r *request.Request) {
+	switch p := r.Params.(type) {
+	case *ListObjectsInput:
+		if p.EncodingType != nil {
+			return
+		}
+	case *ListObjectsV2Input:
+		if p.EncodingType != nil {
+			return
+		}
+	case *ListObjectVersionsInput:
+		if p.EncodingType != nil {
+			return
+		}
+	}
+
+	query := r.HTTPRequest.URL.Query()
+	query.Set("encoding-type", EncodingTypeUrl)
+	r.HTTPRequest.URL.RawQuery = query.Encode()
+
+	r.Handlers.Unmarshal.PushBack(decodeListKeys)
+}
+
+// decodeListKeys decodes the URL encoded keys and prefixes of a listing.
+func decodeListKeys(r *request.Request) {
+	if r.Error != nil {
+		return
+	}
+
+	var keys []*string
+	var prefixes []*CommonPrefix
+	switch out := r.Data.(type) {
+	case *ListObjectsOutput:
+		keys = append(keys, out.Delimiter, out.Marker, out.NextMarker, out.Prefix)
+		for _, obj := range out.Contents {
+			keys = append(keys, obj.Key)
+		}
+		prefixes = out.CommonPrefixes
+		out.EncodingType = nil
+	case *ListObjectsV2Output:
+		keys = append(keys, out.Delimiter, out.Prefix, out.StartAfter)
+		for _, obj := range out.Contents {
+			keys = append(keys, obj.Key)
+		}
+		prefixes = out.CommonPrefixes
+		out.EncodingType = nil
+	case *ListObjectVersionsOutput:
+		keys = append(keys, out.Delimiter, out.KeyMarker, out.NextKeyMarker, out.Prefix)
+		for _, v := range out.Versions {
+			keys = append(keys, v.Key)
+		}
+		for _, m := range out.DeleteMarkers {
+			keys = append(keys, m.Key)
+		}
+		prefixes = out.CommonPrefixes
+		out.EncodingType = nil
+	}
+	for _, p := range prefixes {
+		keys = append(keys, p.Prefix)
+	}
+
+	for _, key := range keys {
+		if key == nil {
+			continue
+		}
+		decoded, err := url.QueryUnescape(*key)
+		if err != nil {
+			r.Error = awserr.New(request.ErrCodeSerialization,
+				"failed to decode URL encoded key", err)
+			return
+		}
+		*key = decoded
+	}
+}
+
+// pathUnescape unescapes the URL path segment, as url.PathUnescape of Go 1.8
+// does. Unlike query components, "+" is not unescaped to a space.
+func pathUnescape(s string) (string, error) {
+	return url.QueryUnescape(strings.Replace(s, "+", "%2B", -1))
+}
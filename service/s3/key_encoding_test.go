@@ -0,0 +1,156 @@
+package s3_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const exoticKey = "/a//b/../c d+é\x01"
+
+func TestStrictKeyEncoding_RequestPath(t *testing.T) {
+	cases := []struct {
+		Strict     bool
+		ExpectPath string
+	}{
+		{Strict: false, ExpectPath: "/bucket/a/c%20d%2B%C3%A9%01"},
+		{Strict: true, ExpectPath: "/bucket//a//b/../c%20d%2B%C3%A9%01"},
+	}
+
+	for i, c := range cases {
+		svc := s3.New(unit.Session, &aws.Config{
+			S3ForcePathStyle:    aws.Bool(true),
+			S3StrictKeyEncoding: aws.Bool(c.Strict),
+		})
+
+		req, _ := svc.GetObjectRequest(&s3.GetObjectInput{
+			Bucket: aws.String("bucket"),
+			Key:    aws.String(exoticKey),
+		})
+		if err := req.Build(); err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		if e, a := c.ExpectPath, req.HTTPRequest.URL.EscapedPath(); e != a {
+			t.Errorf("%d, expect %v path, got %v", i, e, a)
+		}
+	}
+}
+
+func TestStrictKeyEncoding_Presign(t *testing.T) {
+	svc := s3.New(unit.Session, &aws.Config{
+		S3ForcePathStyle:    aws.Bool(true),
+		S3StrictKeyEncoding: aws.Bool(true),
+	})
+
+	req, _ := svc.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String(exoticKey),
+	})
+	urlStr, err := req.Presign(time.Minute)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "/bucket//a//b/../c%20d%2B%C3%A9%01", u.EscapedPath(); e != a {
+		t.Errorf("expect %v path, got %v", e, a)
+	}
+}
+
+func TestStrictKeyEncoding_CopySource(t *testing.T) {
+	cases := []struct {
+		CopySource string
+		Expect     string
+	}{
+		{"bucket/a+b c", "bucket/a%2Bb%20c"},
+		{"bucket/a%2Bb%20c", "bucket/a%2Bb%20c"},
+		{"/bucket/50%off", "/bucket/50%25off"},
+		{"bucket/a!b?versionId=abc", "bucket/a%21b?versionId=abc"},
+	}
+
+	for i, c := range cases {
+		svc, header := newConditionalTestSvc(200, "<CopyObjectResult></CopyObjectResult>")
+		svc.Config.S3StrictKeyEncoding = aws.Bool(true)
+
+		_, err := svc.CopyObject(&s3.CopyObjectInput{
+			Bucket:     aws.String("bucket"),
+			Key:        aws.String("key"),
+			CopySource: aws.String(c.CopySource),
+		})
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		if e, a := c.Expect, header.Get("X-Amz-Copy-Source"); e != a {
+			t.Errorf("%d, expect %v copy source, got %v", i, e, a)
+		}
+	}
+}
+
+const urlEncodedListBody = `<?xml version="1.0" encoding="UTF-8"?>
+<ListBucketResult>
+<Name>bucket</Name><Prefix>dir%2F</Prefix><Delimiter>%2F</Delimiter><EncodingType>url</EncodingType>
+<Contents><Key>dir%2Fa+b%01</Key></Contents>
+<CommonPrefixes><Prefix>dir%2Fsub+dir%2F</Prefix></CommonPrefixes>
+</ListBucketResult>`
+
+func TestStrictKeyEncoding_ListObjectsV2(t *testing.T) {
+	var query url.Values
+	svc := s3.New(unit.Session, &aws.Config{S3StrictKeyEncoding: aws.Bool(true)})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		query = r.HTTPRequest.URL.Query()
+		r.HTTPResponse = &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader(urlEncodedListBody)),
+		}
+	})
+
+	out, err := svc.ListObjectsV2(&s3.ListObjectsV2Input{
+		Bucket:    aws.String("bucket"),
+		Prefix:    aws.String("dir/"),
+		Delimiter: aws.String("/"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := "url", query.Get("encoding-type"); e != a {
+		t.Errorf("expect %v encoding type, got %v", e, a)
+	}
+	if e, a := "dir/", aws.StringValue(out.Prefix); e != a {
+		t.Errorf("expect %q prefix, got %q", e, a)
+	}
+	if e, a := "dir/a b\x01", aws.StringValue(out.Contents[0].Key); e != a {
+		t.Errorf("expect %q key, got %q", e, a)
+	}
+	if e, a := "dir/sub dir/", aws.StringValue(out.CommonPrefixes[0].Prefix); e != a {
+		t.Errorf("expect %q common prefix, got %q", e, a)
+	}
+	if out.EncodingType != nil {
+		t.Errorf("expect no encoding type, got %v", *out.EncodingType)
+	}
+
+	// Listings with an encoding type set are returned as is.
+	out, err = svc.ListObjectsV2(&s3.ListObjectsV2Input{
+		Bucket:       aws.String("bucket"),
+		EncodingType: aws.String(s3.EncodingTypeUrl),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "dir%2Fa+b%01", aws.StringValue(out.Contents[0].Key); e != a {
+		t.Errorf("expect %q key, got %q", e, a)
+	}
+}
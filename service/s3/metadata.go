@@ -0,0 +1,295 @@
+package s3
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// MetadataValue returns the value of the user metadata key, and whether the
+// metadata has the key. Keys are matched case insensitively, so that the
+// value of metadata written with the key "content-id" is returned for the
+// key "Content-Id" the response's metadata is unmarshaled with, see
+// aws.Config.LowerCaseHeaderMaps.
+func MetadataValue(metadata map[string]*string, key string) (string, bool) {
+	if v, ok := metadata[key]; ok {
+		return aws.StringValue(v), true
+	}
+	for k, v := range metadata {
+		if strings.EqualFold(k, key) {
+			return aws.StringValue(v), true
+		}
+	}
+	return "", false
+}
+
+// NormalizeMetadata returns a copy of the user metadata with lower case
+// keys, the same as the metadata of responses unmarshaled with
+// aws.Config.LowerCaseHeaderMaps.
+func NormalizeMetadata(metadata map[string]*string) map[string]*string {
+	if metadata == nil {
+		return nil
+	}
+
+	normalized := make(map[string]*string, len(metadata))
+	for k, v := range metadata {
+		normalized[strings.ToLower(k)] = v
+	}
+	return normalized
+}
+
+// Common Cache-Control values of objects.
+const (
+	// CacheControlImmutable caches objects which never change, such as
+	// objects with a content hash in their key, for a year.
+	CacheControlImmutable = "public, max-age=31536000, immutable"
+
+	// CacheControlRevalidate caches objects, revalidating them before each
+	// use.
+	CacheControlRevalidate = "no-cache"
+
+	// CacheControlNoStore does not cache objects.
+	CacheControlNoStore = "no-store"
+)
+
+// CacheControl is the directives of an object's Cache-Control header.
+//
+//     _, err := svc.PutObject(&s3.PutObjectInput{
+//         Bucket:       aws.String(bucket),
+//         Key:          aws.String(key),
+//         Body:         body,
+//         CacheControl: aws.String(s3.CacheControl{
+//             Public: true,
+//             MaxAge: aws.Int64(3600),
+//         }.String()),
+//     })
+type CacheControl struct {
+	Public         bool
+	Private        bool
+	NoCache        bool
+	NoStore        bool
+	NoTransform    bool
+	MustRevalidate bool
+	Immutable      bool
+
+	// MaxAge and SMaxAge are the max-age and s-maxage directives in seconds,
+	// if not nil.
+	MaxAge  *int64
+	SMaxAge *int64
+
+	// Extensions are the other directives of the header, such as
+	// "stale-while-revalidate=60".
+	Extensions []string
+}
+
+// ParseCacheControl returns the directives of the Cache-Control header
+// value. Directives are matched case insensitively, and max-age and s-maxage
+// directives with invalid values are ignored.
+func ParseCacheControl(v string) CacheControl {
+	var c CacheControl
+	for _, d := range strings.Split(v, ",") {
+		d = strings.TrimSpace(d)
+		if len(d) == 0 {
+			continue
+		}
+
+		name, value := d, ""
+		if i := strings.IndexByte(d, '='); i >= 0 {
+			name, value = strings.TrimSpace(d[:i]), strings.Trim(strings.TrimSpace(d[i+1:]), `"`)
+		}
+
+		switch strings.ToLower(name) {
+		case "public":
+			c.Public = true
+		case "private":
+			c.Private = true
+		case "no-cache":
+			c.NoCache = true
+		case "no-store":
+			c.NoStore = true
+		case "no-transform":
+			c.NoTransform = true
+		case "must-revalidate":
+			c.MustRevalidate = true
+		case "immutable":
+			c.Immutable = true
+		case "max-age":
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				c.MaxAge = aws.Int64(n)
+			}
+		case "s-maxage":
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				c.SMaxAge = aws.Int64(n)
+			}
+		default:
+			c.Extensions = append(c.Extensions, d)
+		}
+	}
+	return c
+}
+
+// String returns the Cache-Control header value of the directives.
+func (c CacheControl) String() string {
+	var ds []string
+	flags := []struct {
+		set  bool
+		name string
+	}{
+		{c.Public, "public"},
+		{c.Private, "private"},
+		{c.NoCache, "no-cache"},
+		{c.NoStore, "no-store"},
+		{c.NoTransform, "no-transform"},
+		{c.MustRevalidate, "must-revalidate"},
+	}
+	for _, f := range flags {
+		if f.set {
+			ds = append(ds, f.name)
+		}
+	}
+	if c.MaxAge != nil {
+		ds = append(ds, "max-age="+strconv.FormatInt(*c.MaxAge, 10))
+	}
+	if c.SMaxAge != nil {
+		ds = append(ds, "s-maxage="+strconv.FormatInt(*c.SMaxAge, 10))
+	}
+	if c.Immutable {
+		ds = append(ds, "immutable")
+	}
+	ds = append(ds, c.Extensions...)
+
+	return strings.Join(ds, ", ")
+}
+
+// Content-Disposition types of objects.
+const (
+	ContentDispositionInline     = "inline"
+	ContentDispositionAttachment = "attachment"
+)
+
+// ContentDisposition is the type and filename of an object's
+// Content-Disposition header.
+//
+//     disposition := s3.ContentDisposition{
+//         Type:     s3.ContentDispositionAttachment,
+//         Filename: "résumé.pdf",
+//     }.String()
+type ContentDisposition struct {
+	Type     string
+	Filename string
+}
+
+// ParseContentDisposition returns the type and filename of the
+// Content-Disposition header value. Filenames encoded with the filename*
+// parameter of RFC 2231 are decoded.
+func ParseContentDisposition(v string) (ContentDisposition, error) {
+	typ, params, err := mime.ParseMediaType(v)
+	if err != nil {
+		return ContentDisposition{}, err
+	}
+	return ContentDisposition{Type: typ, Filename: params["filename"]}, nil
+}
+
+// String returns the Content-Disposition header value of the type and
+// filename. Filenames which are not ASCII are encoded with the filename*
+// parameter of RFC 2231. The type defaults to ContentDispositionAttachment
+// if not set.
+func (d ContentDisposition) String() string {
+	typ := d.Type
+	if len(typ) == 0 {
+		typ = ContentDispositionAttachment
+	}
+
+	var params map[string]string
+	if len(d.Filename) != 0 {
+		params = map[string]string{"filename": d.Filename}
+	}
+	return mime.FormatMediaType(typ, params)
+}
+
+// ObjectHeaders are the HTTP headers of an object returned when the object
+// is downloaded, such as by a browser.
+type ObjectHeaders struct {
+	CacheControl       string
+	ContentDisposition string
+	ContentEncoding    string
+	ContentLanguage    string
+	ContentType        string
+}
+
+// HeadObjectHeaders returns the ObjectHeaders of the object of the
+// HeadObject output.
+func HeadObjectHeaders(out *HeadObjectOutput) ObjectHeaders {
+	return ObjectHeaders{
+		CacheControl:       aws.StringValue(out.CacheControl),
+		ContentDisposition: aws.StringValue(out.ContentDisposition),
+		ContentEncoding:    aws.StringValue(out.ContentEncoding),
+		ContentLanguage:    aws.StringValue(out.ContentLanguage),
+		ContentType:        aws.StringValue(out.ContentType),
+	}
+}
+
+// GetObjectHeaders returns the ObjectHeaders of the object of the GetObject
+// output.
+func GetObjectHeaders(out *GetObjectOutput) ObjectHeaders {
+	return ObjectHeaders{
+		CacheControl:       aws.StringValue(out.CacheControl),
+		ContentDisposition: aws.StringValue(out.ContentDisposition),
+		ContentEncoding:    aws.StringValue(out.ContentEncoding),
+		ContentLanguage:    aws.StringValue(out.ContentLanguage),
+		ContentType:        aws.StringValue(out.ContentType),
+	}
+}
+
+// SetPutObject sets the headers which are not empty on the PutObject input,
+// returning the input for chaining.
+//
+//     headers := s3.ObjectHeaders{
+//         CacheControl:    s3.CacheControlImmutable,
+//         ContentEncoding: "gzip",
+//         ContentType:     "text/css",
+//     }
+//     _, err := svc.PutObject(headers.SetPutObject(&s3.PutObjectInput{
+//         Bucket: aws.String(bucket),
+//         Key:    aws.String(key),
+//         Body:   body,
+//     }))
+func (h ObjectHeaders) SetPutObject(in *PutObjectInput) *PutObjectInput {
+	setObjectHeader(&in.CacheControl, h.CacheControl)
+	setObjectHeader(&in.ContentDisposition, h.ContentDisposition)
+	setObjectHeader(&in.ContentEncoding, h.ContentEncoding)
+	setObjectHeader(&in.ContentLanguage, h.ContentLanguage)
+	setObjectHeader(&in.ContentType, h.ContentType)
+	return in
+}
+
+// SetCreateMultipartUpload sets the headers which are not empty on the
+// CreateMultipartUpload input, returning the input for chaining.
+func (h ObjectHeaders) SetCreateMultipartUpload(in *CreateMultipartUploadInput) *CreateMultipartUploadInput {
+	setObjectHeader(&in.CacheControl, h.CacheControl)
+	setObjectHeader(&in.ContentDisposition, h.ContentDisposition)
+	setObjectHeader(&in.ContentEncoding, h.ContentEncoding)
+	setObjectHeader(&in.ContentLanguage, h.ContentLanguage)
+	setObjectHeader(&in.ContentType, h.ContentType)
+	return in
+}
+
+// SetCopyObject sets the headers which are not empty on the CopyObject
+// input, returning the input for chaining. The headers of the object copied
+// are only replaced if the input's MetadataDirective is REPLACE.
+func (h ObjectHeaders) SetCopyObject(in *CopyObjectInput) *CopyObjectInput {
+	setObjectHeader(&in.CacheControl, h.CacheControl)
+	setObjectHeader(&in.ContentDisposition, h.ContentDisposition)
+	setObjectHeader(&in.ContentEncoding, h.ContentEncoding)
+	setObjectHeader(&in.ContentLanguage, h.ContentLanguage)
+	setObjectHeader(&in.ContentType, h.ContentType)
+	return in
+}
+
+func setObjectHeader(param **string, v string) {
+	if len(v) != 0 {
+		*param = aws.String(v)
+	}
+}
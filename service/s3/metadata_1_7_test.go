@@ -0,0 +1,129 @@
+// +build go1.7
+
+package s3_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestLowerCaseHeaderMaps(t *testing.T) {
+	cases := map[string]struct {
+		Config *aws.Config
+		Key    string
+	}{
+		"canonical": {
+			Config: &aws.Config{},
+			Key:    "Content-Id",
+		},
+		"lower case": {
+			Config: &aws.Config{LowerCaseHeaderMaps: aws.Bool(true)},
+			Key:    "content-id",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			svc := s3.New(unit.Session, c.Config)
+			svc.Handlers.Send.Clear()
+			svc.Handlers.Send.PushBack(func(r *request.Request) {
+				r.HTTPResponse = &http.Response{
+					StatusCode: 200,
+					Header:     http.Header{"X-Amz-Meta-Content-Id": []string{"abc"}},
+					Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+				}
+			})
+
+			out, err := svc.HeadObject(&s3.HeadObjectInput{Bucket: aws.String("bucket"), Key: aws.String("key")})
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := "abc", aws.StringValue(out.Metadata[c.Key]); e != a {
+				t.Errorf("expect %v metadata value of %v, got %v", e, c.Key, a)
+			}
+
+			v, ok := s3.MetadataValue(out.Metadata, "CONTENT-ID")
+			if !ok {
+				t.Fatalf("expect metadata value")
+			}
+			if e, a := "abc", v; e != a {
+				t.Errorf("expect %v metadata value, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	cases := map[string]struct {
+		Header string
+		Expect s3.CacheControl
+		String string
+	}{
+		"immutable": {
+			Header: s3.CacheControlImmutable,
+			Expect: s3.CacheControl{Public: true, MaxAge: aws.Int64(31536000), Immutable: true},
+			String: s3.CacheControlImmutable,
+		},
+		"extensions": {
+			Header: `Private, MAX-AGE="0",stale-while-revalidate=60, s-maxage=x`,
+			Expect: s3.CacheControl{Private: true, MaxAge: aws.Int64(0), Extensions: []string{"stale-while-revalidate=60"}},
+			String: "private, max-age=0, stale-while-revalidate=60",
+		},
+		"empty": {},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			cc := s3.ParseCacheControl(c.Header)
+			if e, a := c.Expect, cc; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %#v, got %#v", e, a)
+			}
+			if e, a := c.String, cc.String(); e != a {
+				t.Errorf("expect %v, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestContentDisposition(t *testing.T) {
+	cases := map[string]struct {
+		Disposition s3.ContentDisposition
+		Header      string
+	}{
+		"ascii": {
+			Disposition: s3.ContentDisposition{Type: s3.ContentDispositionAttachment, Filename: "report 1.csv"},
+			Header:      `attachment; filename="report 1.csv"`,
+		},
+		"unicode": {
+			Disposition: s3.ContentDisposition{Type: s3.ContentDispositionAttachment, Filename: "résumé.pdf"},
+			Header:      `attachment; filename*=utf-8''r%C3%A9sum%C3%A9.pdf`,
+		},
+		"inline": {
+			Disposition: s3.ContentDisposition{Type: s3.ContentDispositionInline},
+			Header:      `inline`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if e, a := c.Header, c.Disposition.String(); e != a {
+				t.Errorf("expect %v, got %v", e, a)
+			}
+			d, err := s3.ParseContentDisposition(c.Header)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Disposition, d; e != a {
+				t.Errorf("expect %v, got %v", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package s3_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestNormalizeMetadata(t *testing.T) {
+	m := s3.NormalizeMetadata(map[string]*string{"Content-Id": aws.String("abc")})
+	if e, a := "abc", aws.StringValue(m["content-id"]); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if m := s3.NormalizeMetadata(nil); m != nil {
+		t.Errorf("expect nil metadata, got %v", m)
+	}
+}
+
+func TestObjectHeaders(t *testing.T) {
+	headers := s3.ObjectHeaders{
+		CacheControl:    s3.CacheControlImmutable,
+		ContentEncoding: "gzip",
+	}
+	in := headers.SetPutObject(&s3.PutObjectInput{ContentType: aws.String("text/css")})
+	if e, a := s3.CacheControlImmutable, aws.StringValue(in.CacheControl); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := "gzip", aws.StringValue(in.ContentEncoding); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := "text/css", aws.StringValue(in.ContentType); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	out := s3.HeadObjectHeaders(&s3.HeadObjectOutput{
+		CacheControl:    in.CacheControl,
+		ContentEncoding: in.ContentEncoding,
+		ContentType:     in.ContentType,
+	})
+	if e, a := (s3.ObjectHeaders{
+		CacheControl:    s3.CacheControlImmutable,
+		ContentEncoding: "gzip",
+		ContentType:     "text/css",
+	}), out; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
@@ -0,0 +1,330 @@
+package s3
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const (
+	objectCacheLookupName = "awssdk.s3.ObjectCache.Lookup"
+	objectCacheStoreName  = "awssdk.s3.ObjectCache.Store"
+)
+
+// DefaultObjectCacheMaxObjectSize is the size of the largest object an
+// ObjectCache caches if its MaxObjectSize is not set.
+const DefaultObjectCacheMaxObjectSize = 5 * 1024 * 1024
+
+// A CachedObject is the response of a GetObject call cached by an
+// ObjectCache.
+type CachedObject struct {
+	Header http.Header
+	Body   []byte
+}
+
+// An ObjectCacheStore stores the CachedObjects of an ObjectCache by key.
+// The CachedObjects returned by Get must not be modified.
+//
+// Implementations must be safe to use concurrently.
+type ObjectCacheStore interface {
+	Get(key string) (*CachedObject, bool)
+	Put(key string, obj *CachedObject)
+}
+
+// An ObjectCache caches the responses of GetObject calls in its Store, so
+// that repeated reads of objects, such as configuration objects read by
+// every process, are not downloaded each time.
+//
+// Objects read by version are immutable, and cached objects are returned
+// without calling the service. Other cached objects are revalidated with
+// their ETag, and returned if the service responds that they are not
+// modified, unless Immutable is set.
+//
+// Calls for ranges or parts of objects, with conditions, response header
+// overrides, or SSE-C keys are not cached.
+//
+//     cache := &s3.ObjectCache{Store: s3.NewMemoryObjectCacheStore(64 * 1024 * 1024)}
+//     cache.AddHandlers(&svc.Handlers)
+//
+// The cache is opt-in. Add it to a client's handlers with AddHandlers, or to
+// a single call with the WithObjectCache request option. An ObjectCache is
+// safe to use concurrently, and may be shared by multiple clients.
+type ObjectCache struct {
+	// Store of the cached objects.
+	Store ObjectCacheStore
+
+	// MaxObjectSize is the size of the largest object cached. Defaults to
+	// DefaultObjectCacheMaxObjectSize if 0.
+	MaxObjectSize int64
+
+	// Immutable returns cached objects without revalidating them, even if
+	// they are not read by version. Set it if the objects are never
+	// overwritten.
+	Immutable bool
+}
+
+// AddHandlers adds the cache's handlers to the handlers provided, such as a
+// client's handlers, caching the responses of the GetObject calls made with
+// them. Adding the handlers multiple times has no additional effect.
+func (c *ObjectCache) AddHandlers(handlers *request.Handlers) {
+	handlers.Validate.SetBackNamed(request.NamedHandler{
+		Name: objectCacheLookupName, Fn: c.lookup,
+	})
+}
+
+// WithObjectCache is a request option which will cache the response of a
+// GetObject call in the cache provided, or return the object's cached
+// response.
+//
+//     resp, err := svc.GetObjectWithContext(ctx, &s3.GetObjectInput{
+//         Bucket:    aws.String(bucket),
+//         Key:       aws.String("config.json"),
+//         VersionId: aws.String(version),
+//     }, s3.WithObjectCache(cache))
+func WithObjectCache(cache *ObjectCache) request.Option {
+	return func(r *request.Request) {
+		cache.AddHandlers(&r.Handlers)
+	}
+}
+
+// lookup returns the cached response of cacheable GetObject calls, or adds
+// the handlers revalidating and caching their response.
+func (c *ObjectCache) lookup(r *request.Request) {
+	if r.Operation.Name != opGetObject || !r.ParamsFilled() || r.Error != nil {
+		return
+	}
+	in := r.Params.(*GetObjectInput)
+	if !isCacheableGetObject(in) {
+		return
+	}
+
+	key := objectCacheKey(in)
+	obj, ok := c.Store.Get(key)
+	if ok && (in.VersionId != nil || c.Immutable) {
+		r.Handlers.Sign.Clear()
+		r.Handlers.Send.Clear()
+		r.Handlers.Send.PushBack(func(r *request.Request) {
+			r.HTTPResponse = obj.response()
+		})
+		return
+	}
+
+	if ok {
+		cp := *in
+		cp.IfNoneMatch = aws.String(obj.Header.Get("Etag"))
+		r.Params = &cp
+
+		r.Handlers.ValidateResponse.PushFront(func(r *request.Request) {
+			if r.HTTPResponse.StatusCode != http.StatusNotModified {
+				return
+			}
+			r.HTTPResponse.Body.Close()
+			r.HTTPResponse = obj.response()
+			r.Handlers.Unmarshal.Remove(request.NamedHandler{Name: objectCacheStoreName})
+		})
+	}
+
+	r.Handlers.Unmarshal.PushBackNamed(request.NamedHandler{
+		Name: objectCacheStoreName,
+		Fn: func(r *request.Request) {
+			c.store(r, key)
+		},
+	})
+}
+
+// store caches the response of the GetObject call if the object is not
+// larger than MaxObjectSize, replacing the output's body with the bytes
+// read.
+func (c *ObjectCache) store(r *request.Request, key string) {
+	if r.Error != nil || r.HTTPResponse.StatusCode != http.StatusOK || !r.DataFilled() {
+		return
+	}
+	out := r.Data.(*GetObjectOutput)
+
+	maxSize := c.MaxObjectSize
+	if maxSize <= 0 {
+		maxSize = DefaultObjectCacheMaxObjectSize
+	}
+	if out.ContentLength == nil || *out.ContentLength > maxSize || out.Body == nil {
+		return
+	}
+
+	body, err := ioutil.ReadAll(out.Body)
+	out.Body.Close()
+	if err != nil {
+		r.Error = err
+		return
+	}
+	out.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if int64(len(body)) == *out.ContentLength {
+		c.Store.Put(key, &CachedObject{Header: cloneHeader(r.HTTPResponse.Header), Body: body})
+	}
+}
+
+// isCacheableGetObject returns true if the response of the GetObject call
+// is the whole object, with the headers it was stored with.
+func isCacheableGetObject(in *GetObjectInput) bool {
+	return in.Range == nil && in.PartNumber == nil &&
+		in.IfMatch == nil && in.IfNoneMatch == nil &&
+		in.IfModifiedSince == nil && in.IfUnmodifiedSince == nil &&
+		in.SSECustomerKey == nil &&
+		in.ResponseCacheControl == nil && in.ResponseContentDisposition == nil &&
+		in.ResponseContentEncoding == nil && in.ResponseContentLanguage == nil &&
+		in.ResponseContentType == nil && in.ResponseExpires == nil
+}
+
+// objectCacheKey returns the cache key of the object read by the call.
+func objectCacheKey(in *GetObjectInput) string {
+	key := strconv.Quote(aws.StringValue(in.Bucket)) + "/" + strconv.Quote(aws.StringValue(in.Key))
+	if in.VersionId != nil {
+		key += "?versionId=" + *in.VersionId
+	}
+	return key
+}
+
+// response returns a response of the cached object.
+func (o *CachedObject) response() *http.Response {
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Status:        http.StatusText(http.StatusOK),
+		Header:        cloneHeader(o.Header),
+		ContentLength: int64(len(o.Body)),
+		Body:          ioutil.NopCloser(bytes.NewReader(o.Body)),
+	}
+}
+
+func cloneHeader(h http.Header) http.Header {
+	cp := make(http.Header, len(h))
+	for k, v := range h {
+		cp[k] = append([]string(nil), v...)
+	}
+	return cp
+}
+
+// MemoryObjectCacheStore is an ObjectCacheStore storing objects in memory,
+// evicting the least recently used objects when the size of the objects
+// stored exceeds its limit.
+type MemoryObjectCacheStore struct {
+	maxBytes int64
+
+	mu      sync.Mutex
+	size    int64
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type memoryObjectCacheEntry struct {
+	key string
+	obj *CachedObject
+}
+
+// NewMemoryObjectCacheStore returns a MemoryObjectCacheStore storing up to
+// maxBytes bytes of object bodies.
+func NewMemoryObjectCacheStore(maxBytes int64) *MemoryObjectCacheStore {
+	return &MemoryObjectCacheStore{
+		maxBytes: maxBytes,
+		lru:      list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+// Get returns the object stored with the key.
+func (s *MemoryObjectCacheStore) Get(key string) (*CachedObject, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	s.lru.MoveToFront(e)
+	return e.Value.(*memoryObjectCacheEntry).obj, true
+}
+
+// Put stores the object with the key, evicting the least recently used
+// objects if the store is full. Objects larger than the store are not
+// stored.
+func (s *MemoryObjectCacheStore) Put(key string, obj *CachedObject) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok {
+		s.remove(e)
+	}
+	if int64(len(obj.Body)) > s.maxBytes {
+		return
+	}
+
+	s.entries[key] = s.lru.PushFront(&memoryObjectCacheEntry{key: key, obj: obj})
+	s.size += int64(len(obj.Body))
+	for s.size > s.maxBytes {
+		s.remove(s.lru.Back())
+	}
+}
+
+func (s *MemoryObjectCacheStore) remove(e *list.Element) {
+	entry := s.lru.Remove(e).(*memoryObjectCacheEntry)
+	delete(s.entries, entry.key)
+	s.size -= int64(len(entry.obj.Body))
+}
+
+// DiskObjectCacheStore is an ObjectCacheStore storing objects in files of
+// a directory, which persist across processes. Objects are not evicted.
+type DiskObjectCacheStore struct {
+	// Dir is the directory of the files, created if it does not exist.
+	Dir string
+}
+
+// Get returns the object stored with the key, if its file can be read.
+func (s *DiskObjectCacheStore) Get(key string) (*CachedObject, bool) {
+	f, err := os.Open(s.filename(key))
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	var obj CachedObject
+	if err := gob.NewDecoder(f).Decode(&obj); err != nil {
+		return nil, false
+	}
+	return &obj, true
+}
+
+// Put stores the object with the key. The object is written to a temporary
+// file renamed to the object's file, so that readers never read a partially
+// written object. Objects which fail to be written are not stored.
+func (s *DiskObjectCacheStore) Put(key string, obj *CachedObject) {
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return
+	}
+	f, err := ioutil.TempFile(s.Dir, ".tmp-")
+	if err != nil {
+		return
+	}
+	defer os.Remove(f.Name())
+
+	err = gob.NewEncoder(f).Encode(obj)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		os.Rename(f.Name(), s.filename(key))
+	}
+}
+
+func (s *DiskObjectCacheStore) filename(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.Dir, hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,146 @@
+package s3_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type objectCacheTestServer struct {
+	etag        string
+	body        string
+	requests    int
+	conditional []string
+}
+
+func newObjectCacheTestSvc(srv *objectCacheTestServer) *s3.S3 {
+	svc := s3.New(unit.Session, &aws.Config{MaxRetries: aws.Int(0)})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		srv.requests++
+		inm := r.HTTPRequest.Header.Get("If-None-Match")
+		srv.conditional = append(srv.conditional, inm)
+
+		header := http.Header{"Etag": []string{srv.etag}}
+		if inm == srv.etag {
+			r.HTTPResponse = &http.Response{
+				StatusCode: http.StatusNotModified,
+				Header:     header,
+				Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+			}
+			return
+		}
+		header.Set("Content-Length", strconv.Itoa(len(srv.body)))
+		r.HTTPResponse = &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(srv.body))),
+		}
+	})
+	return svc
+}
+
+func getCachedObject(t *testing.T, svc *s3.S3, in *s3.GetObjectInput) string {
+	resp, err := svc.GetObject(in)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return string(b)
+}
+
+func TestObjectCache_Revalidate(t *testing.T) {
+	srv := &objectCacheTestServer{etag: `"v1"`, body: "config v1"}
+	svc := newObjectCacheTestSvc(srv)
+
+	cache := &s3.ObjectCache{Store: s3.NewMemoryObjectCacheStore(1024)}
+	cache.AddHandlers(&svc.Handlers)
+	cache.AddHandlers(&svc.Handlers)
+
+	in := &s3.GetObjectInput{Bucket: aws.String("bucket"), Key: aws.String("config")}
+	for i := 0; i < 2; i++ {
+		if e, a := "config v1", getCachedObject(t, svc, in); e != a {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+
+	srv.etag, srv.body = `"v2"`, "config v2"
+	if e, a := "config v2", getCachedObject(t, svc, in); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := "config v2", getCachedObject(t, svc, in); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if e, a := `,"v1","v1","v2"`, strings.Join(srv.conditional, ","); e != a {
+		t.Errorf("expect %v conditions, got %v", e, a)
+	}
+	if in.IfNoneMatch != nil {
+		t.Errorf("expect input not modified, got %v", *in.IfNoneMatch)
+	}
+}
+
+func TestObjectCache_Versioned(t *testing.T) {
+	srv := &objectCacheTestServer{etag: `"v1"`, body: "config v1"}
+	svc := newObjectCacheTestSvc(srv)
+
+	dir, err := ioutil.TempDir("", "s3-object-cache")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+	cache := &s3.ObjectCache{Store: &s3.DiskObjectCacheStore{Dir: dir}}
+
+	in := &s3.GetObjectInput{Bucket: aws.String("bucket"), Key: aws.String("config"), VersionId: aws.String("1")}
+	for i := 0; i < 3; i++ {
+		resp, err := svc.GetObjectWithContext(aws.BackgroundContext(), in, s3.WithObjectCache(cache))
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		b, _ := ioutil.ReadAll(resp.Body)
+		if e, a := "config v1", string(b); e != a {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+		if e, a := `"v1"`, aws.StringValue(resp.ETag); e != a {
+			t.Errorf("%d, expect %v ETag, got %v", i, e, a)
+		}
+	}
+	if e, a := 1, srv.requests; e != a {
+		t.Errorf("expect %v requests, got %v", e, a)
+	}
+
+	// Range requests are not cached.
+	in.Range = aws.String("bytes=0-1")
+	svc.GetObjectWithContext(aws.BackgroundContext(), in, s3.WithObjectCache(cache))
+	if e, a := 2, srv.requests; e != a {
+		t.Errorf("expect %v requests, got %v", e, a)
+	}
+}
+
+func TestMemoryObjectCacheStore(t *testing.T) {
+	store := s3.NewMemoryObjectCacheStore(10)
+	store.Put("a", &s3.CachedObject{Body: []byte("aaaa")})
+	store.Put("b", &s3.CachedObject{Body: []byte("bbbb")})
+	store.Get("a")
+	store.Put("c", &s3.CachedObject{Body: []byte("cccc")})
+	store.Put("d", &s3.CachedObject{Body: []byte("too large value")})
+
+	for key, expect := range map[string]bool{"a": true, "b": false, "c": true, "d": false} {
+		if _, ok := store.Get(key); ok != expect {
+			t.Errorf("expect %v cached %v, got %v", key, expect, ok)
+		}
+	}
+}
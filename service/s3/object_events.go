@@ -0,0 +1,234 @@
+package s3
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const objectEventsHandlerName = "awssdk.s3.ObjectEvents"
+
+// An ObjectEvent is an event emitted by ObjectEvents when an operation
+// changing the objects of a bucket succeeds. ObjectEvents are one of
+// ObjectUploadedEvent, ObjectDeletedEvent, MultipartStartedEvent, or
+// MultipartCompletedEvent.
+type ObjectEvent interface {
+	objectEvent()
+}
+
+// ObjectUploadedEvent is emitted when an object is written by a successful
+// PutObject, CopyObject, or CompleteMultipartUpload call.
+type ObjectUploadedEvent struct {
+	// Operation is the name of the operation which wrote the object.
+	Operation string
+
+	Bucket    string
+	Key       string
+	ETag      string
+	VersionID string
+}
+
+// ObjectDeletedEvent is emitted for an object deleted by a successful
+// DeleteObject call, and for each object deleted by a DeleteObjects call.
+// Objects which failed to be deleted by a DeleteObjects call are not
+// emitted.
+type ObjectDeletedEvent struct {
+	// Operation is the name of the operation which deleted the object.
+	Operation string
+
+	Bucket string
+	Key    string
+
+	// VersionID is the version of the object deleted, or the version of the
+	// delete marker created if DeleteMarker is true.
+	VersionID string
+
+	// DeleteMarker is true if a delete marker was created or deleted, instead
+	// of the object's data.
+	DeleteMarker bool
+}
+
+// MultipartStartedEvent is emitted when a multipart upload is started by a
+// successful CreateMultipartUpload call.
+type MultipartStartedEvent struct {
+	Bucket   string
+	Key      string
+	UploadID string
+}
+
+// MultipartCompletedEvent is emitted when a multipart upload is completed by
+// a successful CompleteMultipartUpload call, before the ObjectUploadedEvent
+// of the object written.
+type MultipartCompletedEvent struct {
+	Bucket    string
+	Key       string
+	UploadID  string
+	ETag      string
+	VersionID string
+}
+
+func (ObjectUploadedEvent) objectEvent()     {}
+func (ObjectDeletedEvent) objectEvent()      {}
+func (MultipartStartedEvent) objectEvent()   {}
+func (MultipartCompletedEvent) objectEvent() {}
+
+// An ObjectEventListener is called with the ObjectEvents of the requests of
+// the ObjectEvents it is subscribed to. Listeners are called on the
+// goroutine of the request once it completes, and should not block.
+type ObjectEventListener func(ObjectEvent)
+
+// ObjectEvents emits ObjectEvents to its listeners after operations changing
+// the objects of a bucket succeed, so that applications can maintain local
+// indexes or caches of the objects without wrapping every call site. Events
+// are not emitted for failed operations.
+//
+// Add the events to a client's handlers with AddHandlers, or to a single call
+// with the WithObjectEvents request option.
+//
+//     events := s3.NewObjectEvents(func(e s3.ObjectEvent) {
+//         switch e := e.(type) {
+//         case s3.ObjectUploadedEvent:
+//             index.Put(e.Bucket, e.Key, e.ETag)
+//         case s3.ObjectDeletedEvent:
+//             index.Delete(e.Bucket, e.Key)
+//         }
+//     })
+//     events.AddHandlers(&svc.Handlers)
+//
+// ObjectEvents is safe to use concurrently, and may be shared by multiple
+// clients.
+type ObjectEvents struct {
+	mu        sync.RWMutex
+	listeners []ObjectEventListener
+}
+
+// NewObjectEvents returns ObjectEvents emitting events to the listeners
+// provided.
+func NewObjectEvents(listeners ...ObjectEventListener) *ObjectEvents {
+	return &ObjectEvents{listeners: listeners}
+}
+
+// Subscribe adds the listener to the listeners events are emitted to.
+func (e *ObjectEvents) Subscribe(listener ObjectEventListener) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.listeners = append(e.listeners, listener)
+}
+
+// AddHandlers adds the handler emitting the events of successful requests
+// to the handlers provided, such as a client's handlers. Adding the handlers
+// multiple times has no additional effect.
+func (e *ObjectEvents) AddHandlers(handlers *request.Handlers) {
+	handlers.Complete.SetBackNamed(request.NamedHandler{
+		Name: objectEventsHandlerName, Fn: e.emitRequest,
+	})
+}
+
+// WithObjectEvents is a request option which will emit the events of the
+// request to the listeners of the ObjectEvents provided, if it succeeds.
+//
+//     _, err := svc.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+//         Bucket: aws.String(bucket),
+//         Key:    aws.String(key),
+//     }, s3.WithObjectEvents(events))
+func WithObjectEvents(events *ObjectEvents) request.Option {
+	return func(r *request.Request) {
+		events.AddHandlers(&r.Handlers)
+	}
+}
+
+// emitRequest emits the events of the request, if it succeeded.
+func (e *ObjectEvents) emitRequest(r *request.Request) {
+	if r.Error != nil || !r.ParamsFilled() || !r.DataFilled() {
+		return
+	}
+
+	switch in := r.Params.(type) {
+	case *PutObjectInput:
+		out := r.Data.(*PutObjectOutput)
+		e.emit(ObjectUploadedEvent{
+			Operation: r.Operation.Name,
+			Bucket:    aws.StringValue(in.Bucket),
+			Key:       aws.StringValue(in.Key),
+			ETag:      aws.StringValue(out.ETag),
+			VersionID: aws.StringValue(out.VersionId),
+		})
+	case *CopyObjectInput:
+		out := r.Data.(*CopyObjectOutput)
+		var etag string
+		if out.CopyObjectResult != nil {
+			etag = aws.StringValue(out.CopyObjectResult.ETag)
+		}
+		e.emit(ObjectUploadedEvent{
+			Operation: r.Operation.Name,
+			Bucket:    aws.StringValue(in.Bucket),
+			Key:       aws.StringValue(in.Key),
+			ETag:      etag,
+			VersionID: aws.StringValue(out.VersionId),
+		})
+	case *CreateMultipartUploadInput:
+		out := r.Data.(*CreateMultipartUploadOutput)
+		e.emit(MultipartStartedEvent{
+			Bucket:   aws.StringValue(in.Bucket),
+			Key:      aws.StringValue(in.Key),
+			UploadID: aws.StringValue(out.UploadId),
+		})
+	case *CompleteMultipartUploadInput:
+		out := r.Data.(*CompleteMultipartUploadOutput)
+		e.emit(MultipartCompletedEvent{
+			Bucket:    aws.StringValue(in.Bucket),
+			Key:       aws.StringValue(in.Key),
+			UploadID:  aws.StringValue(in.UploadId),
+			ETag:      aws.StringValue(out.ETag),
+			VersionID: aws.StringValue(out.VersionId),
+		})
+		e.emit(ObjectUploadedEvent{
+			Operation: r.Operation.Name,
+			Bucket:    aws.StringValue(in.Bucket),
+			Key:       aws.StringValue(in.Key),
+			ETag:      aws.StringValue(out.ETag),
+			VersionID: aws.StringValue(out.VersionId),
+		})
+	case *DeleteObjectInput:
+		out := r.Data.(*DeleteObjectOutput)
+		versionID := out.VersionId
+		if versionID == nil {
+			versionID = in.VersionId
+		}
+		e.emit(ObjectDeletedEvent{
+			Operation:    r.Operation.Name,
+			Bucket:       aws.StringValue(in.Bucket),
+			Key:          aws.StringValue(in.Key),
+			VersionID:    aws.StringValue(versionID),
+			DeleteMarker: aws.BoolValue(out.DeleteMarker),
+		})
+	case *DeleteObjectsInput:
+		out := r.Data.(*DeleteObjectsOutput)
+		for _, d := range out.Deleted {
+			versionID := d.VersionId
+			if aws.BoolValue(d.DeleteMarker) && d.DeleteMarkerVersionId != nil {
+				versionID = d.DeleteMarkerVersionId
+			}
+			e.emit(ObjectDeletedEvent{
+				Operation:    r.Operation.Name,
+				Bucket:       aws.StringValue(in.Bucket),
+				Key:          aws.StringValue(d.Key),
+				VersionID:    aws.StringValue(versionID),
+				DeleteMarker: aws.BoolValue(d.DeleteMarker),
+			})
+		}
+	}
+}
+
+// emit calls the listeners with the event.
+func (e *ObjectEvents) emit(event ObjectEvent) {
+	e.mu.RLock()
+	listeners := e.listeners
+	e.mu.RUnlock()
+
+	for _, l := range listeners {
+		l(event)
+	}
+}
@@ -0,0 +1,144 @@
+package s3_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func newObjectEventsTestSvc(status *int) *s3.S3 {
+	svc := s3.New(unit.Session, &aws.Config{MaxRetries: aws.Int(0)})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		header := http.Header{"Etag": []string{`"etag"`}, "X-Amz-Version-Id": []string{"v1"}}
+		var body string
+		switch r.Operation.Name {
+		case "CopyObject":
+			body = `<CopyObjectResult><ETag>"copy"</ETag></CopyObjectResult>`
+		case "CreateMultipartUpload":
+			body = `<InitiateMultipartUploadResult><UploadId>upload</UploadId></InitiateMultipartUploadResult>`
+		case "CompleteMultipartUpload":
+			body = `<CompleteMultipartUploadResult><ETag>"multi"</ETag></CompleteMultipartUploadResult>`
+		case "DeleteObject":
+			header.Set("X-Amz-Delete-Marker", "true")
+		case "DeleteObjects":
+			body = `<DeleteResult><Deleted><Key>a</Key><VersionId>va</VersionId></Deleted>` +
+				`<Deleted><Key>b</Key><DeleteMarker>true</DeleteMarker><DeleteMarkerVersionId>mb</DeleteMarkerVersionId></Deleted>` +
+				`<Error><Key>c</Key><Code>AccessDenied</Code></Error></DeleteResult>`
+		}
+		if *status != 200 {
+			body = `<Error><Code>InternalError</Code></Error>`
+		}
+		r.HTTPResponse = &http.Response{
+			StatusCode: *status,
+			Header:     header,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		}
+	})
+	return svc
+}
+
+func TestObjectEvents(t *testing.T) {
+	status := 200
+	svc := newObjectEventsTestSvc(&status)
+
+	var events []s3.ObjectEvent
+	objectEvents := s3.NewObjectEvents()
+	objectEvents.Subscribe(func(e s3.ObjectEvent) {
+		events = append(events, e)
+	})
+	objectEvents.AddHandlers(&svc.Handlers)
+	objectEvents.AddHandlers(&svc.Handlers)
+
+	bucket, key := aws.String("bucket"), aws.String("key")
+	calls := []func() error{
+		func() error {
+			_, err := svc.PutObject(&s3.PutObjectInput{Bucket: bucket, Key: key, Body: strings.NewReader("data")})
+			return err
+		},
+		func() error {
+			_, err := svc.CopyObject(&s3.CopyObjectInput{Bucket: bucket, Key: key, CopySource: aws.String("bucket/src")})
+			return err
+		},
+		func() error {
+			_, err := svc.CreateMultipartUpload(&s3.CreateMultipartUploadInput{Bucket: bucket, Key: key})
+			return err
+		},
+		func() error {
+			_, err := svc.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+				Bucket: bucket, Key: key, UploadId: aws.String("upload"),
+			})
+			return err
+		},
+		func() error {
+			_, err := svc.DeleteObject(&s3.DeleteObjectInput{Bucket: bucket, Key: key})
+			return err
+		},
+		func() error {
+			_, err := svc.DeleteObjects(&s3.DeleteObjectsInput{Bucket: bucket, Delete: &s3.Delete{
+				Objects: []*s3.ObjectIdentifier{{Key: aws.String("a")}, {Key: aws.String("b")}, {Key: aws.String("c")}},
+			}})
+			return err
+		},
+		func() error {
+			_, err := svc.GetObject(&s3.GetObjectInput{Bucket: bucket, Key: key})
+			return err
+		},
+	}
+	for i, call := range calls {
+		if err := call(); err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+	}
+
+	expect := []s3.ObjectEvent{
+		s3.ObjectUploadedEvent{Operation: "PutObject", Bucket: "bucket", Key: "key", ETag: `"etag"`, VersionID: "v1"},
+		s3.ObjectUploadedEvent{Operation: "CopyObject", Bucket: "bucket", Key: "key", ETag: `"copy"`, VersionID: "v1"},
+		s3.MultipartStartedEvent{Bucket: "bucket", Key: "key", UploadID: "upload"},
+		s3.MultipartCompletedEvent{Bucket: "bucket", Key: "key", UploadID: "upload", ETag: `"multi"`, VersionID: "v1"},
+		s3.ObjectUploadedEvent{Operation: "CompleteMultipartUpload", Bucket: "bucket", Key: "key", ETag: `"multi"`, VersionID: "v1"},
+		s3.ObjectDeletedEvent{Operation: "DeleteObject", Bucket: "bucket", Key: "key", VersionID: "v1", DeleteMarker: true},
+		s3.ObjectDeletedEvent{Operation: "DeleteObjects", Bucket: "bucket", Key: "a", VersionID: "va"},
+		s3.ObjectDeletedEvent{Operation: "DeleteObjects", Bucket: "bucket", Key: "b", VersionID: "mb", DeleteMarker: true},
+	}
+	if e, a := expect, events; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v events, got %v", e, a)
+	}
+}
+
+func TestObjectEvents_Failed(t *testing.T) {
+	status := 500
+	svc := newObjectEventsTestSvc(&status)
+
+	var events int
+	objectEvents := s3.NewObjectEvents(func(s3.ObjectEvent) { events++ })
+
+	_, err := svc.PutObjectWithContext(aws.BackgroundContext(), &s3.PutObjectInput{
+		Bucket: aws.String("bucket"), Key: aws.String("key"),
+	}, s3.WithObjectEvents(objectEvents))
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := 0, events; e != a {
+		t.Errorf("expect %v events, got %v", e, a)
+	}
+
+	status = 200
+	_, err = svc.PutObjectWithContext(aws.BackgroundContext(), &s3.PutObjectInput{
+		Bucket: aws.String("bucket"), Key: aws.String("key"),
+	}, s3.WithObjectEvents(objectEvents))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 1, events; e != a {
+		t.Errorf("expect %v events, got %v", e, a)
+	}
+}
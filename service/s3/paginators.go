@@ -0,0 +1,193 @@
+package s3
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// ErrCodeNoMorePages is the error code returned by the NextPage method of a
+// paginator which has no more pages.
+const ErrCodeNoMorePages = "NoMorePages"
+
+func newNoMorePagesError(operation string) error {
+	return awserr.New(ErrCodeNoMorePages, "no more pages of "+operation, nil)
+}
+
+// ListObjectsV2APIClient is the client API used by the ListObjectsV2Paginator,
+// such as S3 or s3iface.S3API.
+type ListObjectsV2APIClient interface {
+	ListObjectsV2WithContext(aws.Context, *ListObjectsV2Input, ...request.Option) (*ListObjectsV2Output, error)
+}
+
+// ListObjectsV2Paginator requests the pages of a ListObjectsV2 listing one at
+// a time. Unlike ListObjectsV2Pages, the error of each page is returned with
+// it, and the listing can be stopped at any page by no longer requesting
+// pages.
+//
+//     p := s3.NewListObjectsV2Paginator(svc, &s3.ListObjectsV2Input{
+//         Bucket: aws.String("bucket"),
+//     })
+//     for p.HasMorePages() {
+//         page, err := p.NextPage(ctx)
+//         if err != nil {
+//             return err
+//         }
+//         for _, obj := range page.Contents {
+//             fmt.Println(aws.StringValue(obj.Key))
+//         }
+//     }
+//
+// A page whose request failed is requested again by the next call to
+// NextPage.
+type ListObjectsV2Paginator struct {
+	client  ListObjectsV2APIClient
+	input   ListObjectsV2Input
+	opts    []request.Option
+	started bool
+	more    bool
+}
+
+// NewListObjectsV2Paginator returns a ListObjectsV2Paginator listing the
+// input's bucket with the client. The request options are applied to the
+// request of every page.
+func NewListObjectsV2Paginator(client ListObjectsV2APIClient, input *ListObjectsV2Input, opts ...request.Option) *ListObjectsV2Paginator {
+	p := &ListObjectsV2Paginator{client: client, opts: opts}
+	if input != nil {
+		p.input = *input
+	}
+	return p
+}
+
+// HasMorePages returns true if the listing has more pages to request. Always
+// returns true before the first page is requested.
+func (p *ListObjectsV2Paginator) HasMorePages() bool {
+	return !p.started || p.more
+}
+
+// NextPage requests the next page of the listing, with the context. An error
+// with the code ErrCodeNoMorePages is returned if HasMorePages is false.
+func (p *ListObjectsV2Paginator) NextPage(ctx aws.Context) (*ListObjectsV2Output, error) {
+	if !p.HasMorePages() {
+		return nil, newNoMorePagesError(opListObjectsV2)
+	}
+
+	input := p.input
+	out, err := p.client.ListObjectsV2WithContext(ctx, &input, p.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	p.started = true
+	p.more = aws.BoolValue(out.IsTruncated) && len(aws.StringValue(out.NextContinuationToken)) != 0
+	p.input.ContinuationToken = out.NextContinuationToken
+	return out, nil
+}
+
+// ListMultipartUploadsAPIClient is the client API used by the
+// ListMultipartUploadsPaginator, such as S3 or s3iface.S3API.
+type ListMultipartUploadsAPIClient interface {
+	ListMultipartUploadsWithContext(aws.Context, *ListMultipartUploadsInput, ...request.Option) (*ListMultipartUploadsOutput, error)
+}
+
+// ListMultipartUploadsPaginator requests the pages of a ListMultipartUploads
+// listing one at a time, the same as the ListObjectsV2Paginator.
+type ListMultipartUploadsPaginator struct {
+	client  ListMultipartUploadsAPIClient
+	input   ListMultipartUploadsInput
+	opts    []request.Option
+	started bool
+	more    bool
+}
+
+// NewListMultipartUploadsPaginator returns a ListMultipartUploadsPaginator
+// listing the multipart uploads of the input's bucket with the client. The
+// request options are applied to the request of every page.
+func NewListMultipartUploadsPaginator(client ListMultipartUploadsAPIClient, input *ListMultipartUploadsInput, opts ...request.Option) *ListMultipartUploadsPaginator {
+	p := &ListMultipartUploadsPaginator{client: client, opts: opts}
+	if input != nil {
+		p.input = *input
+	}
+	return p
+}
+
+// HasMorePages returns true if the listing has more pages to request. Always
+// returns true before the first page is requested.
+func (p *ListMultipartUploadsPaginator) HasMorePages() bool {
+	return !p.started || p.more
+}
+
+// NextPage requests the next page of the listing, with the context. An error
+// with the code ErrCodeNoMorePages is returned if HasMorePages is false.
+func (p *ListMultipartUploadsPaginator) NextPage(ctx aws.Context) (*ListMultipartUploadsOutput, error) {
+	if !p.HasMorePages() {
+		return nil, newNoMorePagesError(opListMultipartUploads)
+	}
+
+	input := p.input
+	out, err := p.client.ListMultipartUploadsWithContext(ctx, &input, p.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	p.started = true
+	p.more = aws.BoolValue(out.IsTruncated) &&
+		(len(aws.StringValue(out.NextKeyMarker)) != 0 || len(aws.StringValue(out.NextUploadIdMarker)) != 0)
+	p.input.KeyMarker = out.NextKeyMarker
+	p.input.UploadIdMarker = out.NextUploadIdMarker
+	return out, nil
+}
+
+// ListObjectVersionsAPIClient is the client API used by the
+// ListObjectVersionsPaginator, such as S3 or s3iface.S3API.
+type ListObjectVersionsAPIClient interface {
+	ListObjectVersionsWithContext(aws.Context, *ListObjectVersionsInput, ...request.Option) (*ListObjectVersionsOutput, error)
+}
+
+// ListObjectVersionsPaginator requests the pages of a ListObjectVersions
+// listing one at a time, the same as the ListObjectsV2Paginator.
+type ListObjectVersionsPaginator struct {
+	client  ListObjectVersionsAPIClient
+	input   ListObjectVersionsInput
+	opts    []request.Option
+	started bool
+	more    bool
+}
+
+// NewListObjectVersionsPaginator returns a ListObjectVersionsPaginator
+// listing the object versions of the input's bucket with the client. The
+// request options are applied to the request of every page.
+func NewListObjectVersionsPaginator(client ListObjectVersionsAPIClient, input *ListObjectVersionsInput, opts ...request.Option) *ListObjectVersionsPaginator {
+	p := &ListObjectVersionsPaginator{client: client, opts: opts}
+	if input != nil {
+		p.input = *input
+	}
+	return p
+}
+
+// HasMorePages returns true if the listing has more pages to request. Always
+// returns true before the first page is requested.
+func (p *ListObjectVersionsPaginator) HasMorePages() bool {
+	return !p.started || p.more
+}
+
+// NextPage requests the next page of the listing, with the context. An error
+// with the code ErrCodeNoMorePages is returned if HasMorePages is false.
+func (p *ListObjectVersionsPaginator) NextPage(ctx aws.Context) (*ListObjectVersionsOutput, error) {
+	if !p.HasMorePages() {
+		return nil, newNoMorePagesError(opListObjectVersions)
+	}
+
+	input := p.input
+	out, err := p.client.ListObjectVersionsWithContext(ctx, &input, p.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	p.started = true
+	p.more = aws.BoolValue(out.IsTruncated) &&
+		(len(aws.StringValue(out.NextKeyMarker)) != 0 || len(aws.StringValue(out.NextVersionIdMarker)) != 0)
+	p.input.KeyMarker = out.NextKeyMarker
+	p.input.VersionIdMarker = out.NextVersionIdMarker
+	return out, nil
+}
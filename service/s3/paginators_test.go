@@ -0,0 +1,136 @@
+package s3_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type listObjectsV2Client struct {
+	tokens []string
+	pages  []*s3.ListObjectsV2Output
+	errs   []error
+}
+
+func (c *listObjectsV2Client) ListObjectsV2WithContext(ctx aws.Context, input *s3.ListObjectsV2Input, opts ...request.Option) (*s3.ListObjectsV2Output, error) {
+	c.tokens = append(c.tokens, aws.StringValue(input.ContinuationToken))
+	if len(c.errs) != 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+	page := c.pages[0]
+	c.pages = c.pages[1:]
+	return page, nil
+}
+
+func TestListObjectsV2Paginator(t *testing.T) {
+	client := &listObjectsV2Client{
+		pages: []*s3.ListObjectsV2Output{
+			{IsTruncated: aws.Bool(true), NextContinuationToken: aws.String("token1")},
+			{IsTruncated: aws.Bool(true), NextContinuationToken: aws.String("token2")},
+			{IsTruncated: aws.Bool(false)},
+		},
+		errs: []error{nil, errors.New("failed"), nil},
+	}
+	input := &s3.ListObjectsV2Input{Bucket: aws.String("bucket")}
+	p := s3.NewListObjectsV2Paginator(client, input)
+
+	var pages, errs int
+	for p.HasMorePages() {
+		_, err := p.NextPage(aws.BackgroundContext())
+		if err != nil {
+			errs++
+			continue
+		}
+		pages++
+	}
+
+	if e, a := 3, pages; e != a {
+		t.Errorf("expect %v pages, got %v", e, a)
+	}
+	if e, a := 1, errs; e != a {
+		t.Errorf("expect %v errors, got %v", e, a)
+	}
+	if e, a := []string{"", "token1", "token1", "token2"}, client.tokens; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v tokens, got %v", e, a)
+	}
+	if input.ContinuationToken != nil {
+		t.Errorf("expect input not to be modified, got %v", *input.ContinuationToken)
+	}
+
+	_, err := p.NextPage(aws.BackgroundContext())
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := s3.ErrCodeNoMorePages, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
+
+func TestListMultipartUploadsPaginator(t *testing.T) {
+	svc, _ := newCOSWaiterTestSvc(200)
+	var markers []string
+	svc.Handlers.Unmarshal.Clear()
+	svc.Handlers.Unmarshal.PushBack(func(r *request.Request) {
+		in := r.Params.(*s3.ListMultipartUploadsInput)
+		markers = append(markers, aws.StringValue(in.KeyMarker)+"/"+aws.StringValue(in.UploadIdMarker))
+		out := r.Data.(*s3.ListMultipartUploadsOutput)
+		if len(markers) == 1 {
+			out.IsTruncated = aws.Bool(true)
+			out.NextKeyMarker = aws.String("key")
+			out.NextUploadIdMarker = aws.String("upload")
+		}
+	})
+
+	p := s3.NewListMultipartUploadsPaginator(svc, &s3.ListMultipartUploadsInput{Bucket: aws.String("bucket")})
+	for p.HasMorePages() {
+		if _, err := p.NextPage(aws.BackgroundContext()); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+
+	if e, a := []string{"/", "key/upload"}, markers; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v markers, got %v", e, a)
+	}
+}
+
+func TestListObjectVersionsPaginator(t *testing.T) {
+	svc, _ := newCOSWaiterTestSvc(200)
+	var markers []string
+	svc.Handlers.Unmarshal.Clear()
+	svc.Handlers.Unmarshal.PushBack(func(r *request.Request) {
+		in := r.Params.(*s3.ListObjectVersionsInput)
+		markers = append(markers, aws.StringValue(in.KeyMarker)+"/"+aws.StringValue(in.VersionIdMarker))
+		out := r.Data.(*s3.ListObjectVersionsOutput)
+		if len(markers) < 3 {
+			out.IsTruncated = aws.Bool(true)
+			out.NextKeyMarker = aws.String("key")
+			out.NextVersionIdMarker = aws.String(strconv.Itoa(len(markers)))
+		}
+	})
+
+	p := s3.NewListObjectVersionsPaginator(svc, &s3.ListObjectVersionsInput{Bucket: aws.String("bucket")})
+
+	// Stop early after the second page.
+	for i := 0; i < 2 && p.HasMorePages(); i++ {
+		if _, err := p.NextPage(aws.BackgroundContext()); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+
+	if e, a := []string{"/", "key/1"}, markers; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v markers, got %v", e, a)
+	}
+	if !p.HasMorePages() {
+		t.Errorf("expect more pages")
+	}
+}
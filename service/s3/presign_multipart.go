@@ -0,0 +1,71 @@
+package s3
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// PresignUploadPart presigns an UploadPart request of a multipart upload,
+// returning the URL the part can be uploaded to with a PUT request until the
+// URL expires, and the headers which must be sent with the request. This
+// allows a coordinator service to create the multipart upload, and hand out
+// part upload URLs to browsers or devices, which upload the parts directly
+// without holding any credentials.
+//
+// The request is presigned with HMAC credentials, the client's, or the
+// credentials of a request.WithCredentials option. Requests cannot be
+// presigned with IBM IAM credentials. The part's body is not part of the
+// signature, but the part's ContentMD5, if set, is a signed header which the
+// upload must match.
+//
+// Example:
+//     out, err := svc.CreateMultipartUpload(createParams)
+//     ...
+//     url, header, err := svc.PresignUploadPart(&s3.UploadPartInput{
+//         Bucket:     out.Bucket,
+//         Key:        out.Key,
+//         UploadId:   out.UploadId,
+//         PartNumber: aws.Int64(1),
+//     }, 15*time.Minute, request.WithCredentials(hmacCreds))
+func (c *S3) PresignUploadPart(input *UploadPartInput, expire time.Duration, opts ...request.Option) (string, http.Header, error) {
+	return c.PresignUploadPartWithContext(aws.BackgroundContext(), input, expire, opts...)
+}
+
+// PresignUploadPartWithContext is the same as PresignUploadPart with the
+// addition of the ability to pass a context.
+//
+// See PresignUploadPart for details on how to use this method.
+func (c *S3) PresignUploadPartWithContext(ctx aws.Context, input *UploadPartInput, expire time.Duration, opts ...request.Option) (string, http.Header, error) {
+	req, _ := c.UploadPartRequest(input)
+	req.SetContext(ctx)
+	req.ApplyOptions(opts...)
+	return req.PresignRequest(expire)
+}
+
+// PresignCompleteMultipartUpload presigns a CompleteMultipartUpload request,
+// returning the URL to complete the multipart upload with a POST request
+// until the URL expires, and the headers which must be sent with the
+// request. The body of the request, the XML list of the uploaded parts and
+// their ETags, is sent by the holder of the URL, and is not part of the
+// signature.
+//
+// The request is presigned with HMAC credentials, the client's, or the
+// credentials of a request.WithCredentials option, as for PresignUploadPart.
+func (c *S3) PresignCompleteMultipartUpload(input *CompleteMultipartUploadInput, expire time.Duration, opts ...request.Option) (string, http.Header, error) {
+	return c.PresignCompleteMultipartUploadWithContext(aws.BackgroundContext(), input, expire, opts...)
+}
+
+// PresignCompleteMultipartUploadWithContext is the same as
+// PresignCompleteMultipartUpload with the addition of the ability to pass a
+// context.
+//
+// See PresignCompleteMultipartUpload for details on how to use this method.
+func (c *S3) PresignCompleteMultipartUploadWithContext(ctx aws.Context, input *CompleteMultipartUploadInput, expire time.Duration, opts ...request.Option) (string, http.Header, error) {
+	req, _ := c.CompleteMultipartUploadRequest(input)
+	req.SetContext(ctx)
+	req.ApplyOptions(opts...)
+	return req.PresignRequest(expire)
+}
@@ -0,0 +1,121 @@
+package s3_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/credentialstest"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/signer/ibm"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func newPresignTestSvc(endpoint string) *s3.S3 {
+	svc := s3.New(unit.Session, aws.NewConfig().
+		WithEndpoint(endpoint).
+		WithS3ForcePathStyle(true).
+		WithS3ChecksumAlgorithm(s3.ChecksumAlgorithmCRC32C).
+		WithCredentials(credentials.NewTypedCredentials(&credentialstest.MockProvider{
+			Value: credentials.Value{SessionToken: "token"},
+		}, "ibm-iam")))
+	svc.Handlers.Sign.Remove(v4.SignRequestHandler)
+	svc.Handlers.Sign.PushBackNamed(ibm.SignRequestHandler)
+
+	return svc
+}
+
+func TestPresignUploadPart(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if e, a := "PUT", r.Method; e != a {
+			t.Errorf("expect %v method, got %v", e, a)
+		}
+		q := r.URL.Query()
+		if e, a := "upload", q.Get("uploadId"); e != a {
+			t.Errorf("expect %v upload ID, got %v", e, a)
+		}
+		if e, a := "2", q.Get("partNumber"); e != a {
+			t.Errorf("expect %v part number, got %v", e, a)
+		}
+		if len(q.Get("X-Amz-Signature")) == 0 {
+			t.Errorf("expect signature, got none")
+		}
+		if e, a := "", r.Header.Get("Authorization"); e != a {
+			t.Errorf("expect no authorization header, got %v", a)
+		}
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	svc := newPresignTestSvc(server.URL)
+	u, header, err := svc.PresignUploadPart(&s3.UploadPartInput{
+		Bucket:     aws.String("bucket"),
+		Key:        aws.String("key"),
+		UploadId:   aws.String("upload"),
+		PartNumber: aws.Int64(2),
+	}, 15*time.Minute, request.WithCredentials(credentials.NewStaticCredentials("AKID", "SECRET", "")))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	for k := range header {
+		if k == "X-Amz-Sdk-Checksum-Algorithm" {
+			t.Errorf("expect no checksum header, got %v", k)
+		}
+	}
+
+	req, _ := http.NewRequest("PUT", u, bytes.NewReader([]byte("part")))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	resp.Body.Close()
+	if e, a := "part", string(body); e != a {
+		t.Errorf("expect %v body, got %v", e, a)
+	}
+}
+
+func TestPresignCompleteMultipartUpload(t *testing.T) {
+	svc := newPresignTestSvc("https://s3.us-south.cloud-object-storage.appdomain.cloud")
+	u, _, err := svc.PresignCompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:   aws.String("bucket"),
+		Key:      aws.String("key"),
+		UploadId: aws.String("upload"),
+	}, 15*time.Minute, request.WithCredentials(credentials.NewStaticCredentials("AKID", "SECRET", "")))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	req, _ := http.NewRequest("POST", u, nil)
+	q := req.URL.Query()
+	if e, a := "upload", q.Get("uploadId"); e != a {
+		t.Errorf("expect %v upload ID, got %v", e, a)
+	}
+	if e, a := "AKID/", q.Get("X-Amz-Credential"); len(a) < len(e) || a[:len(e)] != e {
+		t.Errorf("expect %v credential prefix, got %v", e, a)
+	}
+}
+
+func TestPresignUploadPart_IAMCredentials(t *testing.T) {
+	svc := newPresignTestSvc("https://s3.us-south.cloud-object-storage.appdomain.cloud")
+	_, _, err := svc.PresignUploadPart(&s3.UploadPartInput{
+		Bucket:     aws.String("bucket"),
+		Key:        aws.String("key"),
+		UploadId:   aws.String("upload"),
+		PartNumber: aws.Int64(1),
+	}, 15*time.Minute)
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := ibm.ErrCodePresignNotSupported, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
@@ -0,0 +1,279 @@
+package s3
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+const (
+	// ErrCodeInvalidPresignedURL is the error code returned when a URL is not
+	// a presigned URL, or its signing parameters are malformed.
+	ErrCodeInvalidPresignedURL = "InvalidPresignedURL"
+
+	// ErrCodePresignedURLExpired is the error code returned when a presigned
+	// URL has expired, or is signed in the future.
+	ErrCodePresignedURLExpired = "PresignedURLExpired"
+
+	// ErrCodePresignedURLDenied is the error code returned when a presigned
+	// URL is not signed with one of the verifier's keys, its signature does
+	// not match, or it is not for an allowed endpoint, method, or object.
+	ErrCodePresignedURLDenied = "PresignedURLDenied"
+)
+
+const (
+	// presignMaxExpiry is the longest expiry of presigned URLs.
+	presignMaxExpiry = 7 * 24 * time.Hour
+
+	// presignMaxClockSkew is how far in the future presigned URLs can be
+	// signed at, for the clock skew of the hosts signing them.
+	presignMaxClockSkew = 5 * time.Minute
+)
+
+// presignQueryParams are the query parameters added to presigned URLs by
+// the v4 signer.
+var presignQueryParams = []string{
+	"X-Amz-Algorithm", "X-Amz-Credential", "X-Amz-Date", "X-Amz-Expires",
+	"X-Amz-SignedHeaders", "X-Amz-Signature", "X-Amz-Security-Token",
+}
+
+// A PresignedURLScope is a bucket, and key prefix in the bucket, of the
+// objects presigned URLs are allowed for.
+type PresignedURLScope struct {
+	Bucket string
+
+	// Prefix of the keys of the objects. Any object of the bucket is allowed
+	// if empty.
+	Prefix string
+}
+
+// A VerifiedPresignedURL is the object and signing parameters of a presigned
+// URL verified by a PresignedURLVerifier.
+type VerifiedPresignedURL struct {
+	AccessKeyID string
+	Region      string
+	Bucket      string
+	Key         string
+	SignedAt    time.Time
+	Expires     time.Time
+}
+
+// A PresignedURLVerifier verifies presigned COS URLs supplied by clients
+// were signed with one of its HMAC keys, have not expired, and are for an
+// allowed object, so that services proxying the URLs only proxy URLs they
+// minted.
+//
+//     verifier := &s3.PresignedURLVerifier{
+//         Keys:      map[string]string{accessKeyID: secretAccessKey},
+//         Endpoints: []string{"s3.us-south.cloud-object-storage.appdomain.cloud"},
+//         Scopes:    []s3.PresignedURLScope{{Bucket: "uploads", Prefix: "incoming/"}},
+//         Methods:   []string{"PUT"},
+//     }
+//
+//     u, err := url.Parse(clientURL)
+//     if err != nil {
+//         return err
+//     }
+//     if _, err := verifier.Verify("PUT", u, nil); err != nil {
+//         return err
+//     }
+//
+// URLs are verified against v4 signatures, presigned with
+// credentials.NewStaticCredentials of the HMAC keys, such as by the Presign
+// method of requests of COS clients. The verifier's fields must not be
+// modified once it is in use.
+type PresignedURLVerifier struct {
+	// Keys are the secret access keys of the HMAC keys URLs are signed with,
+	// by access key ID.
+	Keys map[string]string
+
+	// Endpoints are the hosts of the endpoints URLs are allowed for. URLs
+	// for buckets addressed by virtual host, such as
+	// "bucket.s3.us-south.cloud-object-storage.appdomain.cloud", are allowed
+	// for the endpoint of their host without the bucket.
+	Endpoints []string
+
+	// Scopes are the buckets and key prefixes URLs are allowed for.
+	Scopes []PresignedURLScope
+
+	// Methods are the HTTP methods URLs are allowed for. URLs for any method
+	// are allowed if empty.
+	Methods []string
+
+	// CurrentTime returns the time URLs are verified at. Defaults to
+	// time.Now if not set.
+	CurrentTime func() time.Time
+}
+
+// Verify returns the object and signing parameters of the presigned URL if
+// it was signed for the method with one of the verifier's keys, has not
+// expired, and is for an allowed endpoint, method, and object. The header
+// has the values of the headers signed with the URL other than host, if
+// any, such as the headers returned by PresignRequest.
+func (v *PresignedURLVerifier) Verify(method string, u *url.URL, header http.Header) (*VerifiedPresignedURL, error) {
+	query := u.Query()
+	if query.Get("X-Amz-Algorithm") != "AWS4-HMAC-SHA256" || len(query.Get("X-Amz-Signature")) == 0 {
+		return nil, awserr.New(ErrCodeInvalidPresignedURL, "URL is not presigned with a v4 signature", nil)
+	}
+
+	// Credential is <access key ID>/<date>/<region>/<service>/aws4_request.
+	scope := strings.Split(query.Get("X-Amz-Credential"), "/")
+	if len(scope) != 5 || scope[3] != "s3" || scope[4] != "aws4_request" {
+		return nil, awserr.New(ErrCodeInvalidPresignedURL, "malformed X-Amz-Credential", nil)
+	}
+	signedAt, err := time.Parse("20060102T150405Z", query.Get("X-Amz-Date"))
+	if err != nil {
+		return nil, awserr.New(ErrCodeInvalidPresignedURL, "malformed X-Amz-Date", err)
+	}
+	expires, err := strconv.ParseInt(query.Get("X-Amz-Expires"), 10, 64)
+	if err != nil || expires <= 0 || time.Duration(expires)*time.Second > presignMaxExpiry {
+		return nil, awserr.New(ErrCodeInvalidPresignedURL, "malformed X-Amz-Expires", err)
+	}
+
+	out := &VerifiedPresignedURL{
+		AccessKeyID: scope[0],
+		Region:      scope[2],
+		SignedAt:    signedAt,
+		Expires:     signedAt.Add(time.Duration(expires) * time.Second),
+	}
+
+	secret, ok := v.Keys[out.AccessKeyID]
+	if !ok {
+		return nil, awserr.New(ErrCodePresignedURLDenied,
+			fmt.Sprintf("URL is not signed with a known key, %s", out.AccessKeyID), nil)
+	}
+	if err := v.verifySignature(method, u, header, secret, out); err != nil {
+		return nil, err
+	}
+
+	now := v.now()
+	if !now.Before(out.Expires) {
+		return nil, awserr.New(ErrCodePresignedURLExpired,
+			fmt.Sprintf("URL expired at %s", out.Expires.Format(time.RFC3339)), nil)
+	}
+	if signedAt.After(now.Add(presignMaxClockSkew)) {
+		return nil, awserr.New(ErrCodePresignedURLExpired,
+			fmt.Sprintf("URL is signed in the future at %s", signedAt.Format(time.RFC3339)), nil)
+	}
+
+	if len(v.Methods) != 0 && !containsFold(v.Methods, method) {
+		return nil, awserr.New(ErrCodePresignedURLDenied,
+			fmt.Sprintf("method %s is not allowed", method), nil)
+	}
+	if err := v.verifyObject(u, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// verifySignature verifies the signature of the URL is the v4 signature of
+// the URL without it, signed with the secret at the time it was signed.
+func (v *PresignedURLVerifier) verifySignature(method string, u *url.URL, header http.Header, secret string, out *VerifiedPresignedURL) error {
+	query := u.Query()
+	signature := query.Get("X-Amz-Signature")
+	signedHeaders := strings.Split(query.Get("X-Amz-SignedHeaders"), ";")
+	for _, p := range presignQueryParams {
+		query.Del(p)
+	}
+
+	// The header's keys may not be canonical, such as the lower case keys of
+	// the headers returned by PresignRequest.
+	r := &http.Request{Method: method, Host: u.Host, Header: http.Header{}}
+	for k, vs := range header {
+		if k = strings.ToLower(k); k != "host" && containsFold(signedHeaders, k) {
+			r.Header[http.CanonicalHeaderKey(k)] = append(r.Header[http.CanonicalHeaderKey(k)], vs...)
+		}
+	}
+
+	// URLs presigned with headers hoisted to the query have the payload hash
+	// in the query, while URLs presigned with NotHoist have it signed as a
+	// header.
+	hoisted := query.Get("X-Amz-Content-Sha256")
+	if len(hoisted) != 0 {
+		query.Del("X-Amz-Content-Sha256")
+		r.Header.Set("X-Amz-Content-Sha256", hoisted)
+	}
+
+	unsigned := *u
+	unsigned.RawQuery = query.Encode()
+	r.URL = &unsigned
+
+	signer := v4.NewSigner(credentials.NewStaticCredentials(out.AccessKeyID, secret, ""),
+		func(s *v4.Signer) {
+			s.DisableURIPathEscaping = true
+			s.DisableHeaderHoisting = len(hoisted) == 0
+		})
+	_, err := signer.Presign(r, nil, "s3", out.Region, out.Expires.Sub(out.SignedAt), out.SignedAt)
+	if err != nil {
+		return awserr.New(ErrCodeInvalidPresignedURL, "failed to sign URL", err)
+	}
+
+	expect := r.URL.Query().Get("X-Amz-Signature")
+	if subtle.ConstantTimeCompare([]byte(expect), []byte(signature)) != 1 {
+		return awserr.New(ErrCodePresignedURLDenied, "URL signature does not match", nil)
+	}
+	return nil
+}
+
+// verifyObject sets the bucket and key of the URL, verifying the URL is for
+// an allowed endpoint and object.
+func (v *PresignedURLVerifier) verifyObject(u *url.URL, out *VerifiedPresignedURL) error {
+	host := strings.ToLower(aws.URLHostname(u))
+	path := strings.TrimPrefix(u.Path, "/")
+
+	var endpoint bool
+	for _, e := range v.Endpoints {
+		e = strings.ToLower(e)
+		if host == e {
+			endpoint = true
+			if i := strings.IndexByte(path, '/'); i >= 0 {
+				out.Bucket, out.Key = path[:i], path[i+1:]
+			} else {
+				out.Bucket = path
+			}
+			break
+		}
+		if strings.HasSuffix(host, "."+e) {
+			endpoint = true
+			out.Bucket, out.Key = strings.TrimSuffix(host, "."+e), path
+			break
+		}
+	}
+	if !endpoint {
+		return awserr.New(ErrCodePresignedURLDenied,
+			fmt.Sprintf("endpoint %s is not allowed", host), nil)
+	}
+
+	for _, s := range v.Scopes {
+		if out.Bucket == s.Bucket && strings.HasPrefix(out.Key, s.Prefix) {
+			return nil
+		}
+	}
+	return awserr.New(ErrCodePresignedURLDenied,
+		fmt.Sprintf("object %s/%s is not allowed", out.Bucket, out.Key), nil)
+}
+
+func (v *PresignedURLVerifier) now() time.Time {
+	if v.CurrentTime == nil {
+		return time.Now()
+	}
+	return v.CurrentTime()
+}
+
+func containsFold(vs []string, s string) bool {
+	for _, v := range vs {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+// +build go1.7
+
+package s3_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestPresignedURLVerifier_Errors(t *testing.T) {
+	cases := map[string]struct {
+		URL    func(t *testing.T) *url.URL
+		Method string
+		Now    time.Time
+		Code   string
+	}{
+		"not presigned": {
+			URL: func(t *testing.T) *url.URL {
+				u, _ := url.Parse("https://" + presignVerifyEndpoint + "/uploads/incoming/key")
+				return u
+			},
+			Code: s3.ErrCodeInvalidPresignedURL,
+		},
+		"unknown key": {
+			URL: func(t *testing.T) *url.URL {
+				u := presignTestURL(t, "SECRET", true, "incoming/key")
+				u.RawQuery = strings.Replace(u.RawQuery, "AKID", "OTHER", 1)
+				return u
+			},
+			Code: s3.ErrCodePresignedURLDenied,
+		},
+		"wrong secret": {
+			URL: func(t *testing.T) *url.URL {
+				return presignTestURL(t, "OTHER", true, "incoming/key")
+			},
+			Code: s3.ErrCodePresignedURLDenied,
+		},
+		"tampered key": {
+			URL: func(t *testing.T) *url.URL {
+				u := presignTestURL(t, "SECRET", true, "incoming/key")
+				u.Path = "/uploads/incoming/other"
+				u.RawPath = ""
+				return u
+			},
+			Code: s3.ErrCodePresignedURLDenied,
+		},
+		"method": {
+			URL: func(t *testing.T) *url.URL {
+				return presignTestURL(t, "SECRET", true, "incoming/key")
+			},
+			Method: "GET",
+			Code:   s3.ErrCodePresignedURLDenied,
+		},
+		"expired": {
+			URL: func(t *testing.T) *url.URL {
+				return presignTestURL(t, "SECRET", true, "incoming/key")
+			},
+			Now:  time.Now().Add(time.Hour),
+			Code: s3.ErrCodePresignedURLExpired,
+		},
+		"signed in future": {
+			URL: func(t *testing.T) *url.URL {
+				return presignTestURL(t, "SECRET", true, "incoming/key")
+			},
+			Now:  time.Now().Add(-time.Hour),
+			Code: s3.ErrCodePresignedURLExpired,
+		},
+		"prefix": {
+			URL: func(t *testing.T) *url.URL {
+				return presignTestURL(t, "SECRET", true, "outgoing/key")
+			},
+			Code: s3.ErrCodePresignedURLDenied,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if c.Now.IsZero() {
+				c.Now = time.Now()
+			}
+			if len(c.Method) == 0 {
+				c.Method = "PUT"
+			}
+
+			_, err := newTestPresignedURLVerifier(c.Now).Verify(c.Method, c.URL(t), nil)
+			if err == nil {
+				t.Fatalf("expect error, got none")
+			}
+			if e, a := c.Code, err.(awserr.Error).Code(); e != a {
+				t.Errorf("expect %v error code, got %v, %v", e, a, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package s3_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const presignVerifyEndpoint = "s3.us-south.cloud-object-storage.appdomain.cloud"
+
+func presignTestURL(t *testing.T, secret string, pathStyle bool, key string) *url.URL {
+	svc := s3.New(unit.Session, &aws.Config{
+		Region:           aws.String("us-south"),
+		Endpoint:         aws.String("https://" + presignVerifyEndpoint),
+		Credentials:      credentials.NewStaticCredentials("AKID", secret, ""),
+		S3ForcePathStyle: aws.Bool(pathStyle),
+	})
+	req, _ := svc.PutObjectRequest(&s3.PutObjectInput{
+		Bucket: aws.String("uploads"),
+		Key:    aws.String(key),
+	})
+	s, err := req.Presign(15 * time.Minute)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return u
+}
+
+func newTestPresignedURLVerifier(now time.Time) *s3.PresignedURLVerifier {
+	return &s3.PresignedURLVerifier{
+		Keys:        map[string]string{"AKID": "SECRET"},
+		Endpoints:   []string{presignVerifyEndpoint},
+		Scopes:      []s3.PresignedURLScope{{Bucket: "uploads", Prefix: "incoming/"}},
+		Methods:     []string{"PUT"},
+		CurrentTime: func() time.Time { return now },
+	}
+}
+
+func TestPresignedURLVerifier(t *testing.T) {
+	for _, pathStyle := range []bool{true, false} {
+		u := presignTestURL(t, "SECRET", pathStyle, "incoming/my file+1.txt")
+		v := newTestPresignedURLVerifier(time.Now())
+
+		out, err := v.Verify("PUT", u, nil)
+		if err != nil {
+			t.Fatalf("path style %v, expect no error, got %v", pathStyle, err)
+		}
+		if e, a := "uploads", out.Bucket; e != a {
+			t.Errorf("expect %v bucket, got %v", e, a)
+		}
+		if e, a := "incoming/my file+1.txt", out.Key; e != a {
+			t.Errorf("expect %v key, got %v", e, a)
+		}
+		if e, a := "us-south", out.Region; e != a {
+			t.Errorf("expect %v region, got %v", e, a)
+		}
+	}
+}
+
+func TestPresignedURLVerifier_SignedHeaders(t *testing.T) {
+	svc := s3.New(unit.Session, &aws.Config{
+		Region:      aws.String("us-south"),
+		Endpoint:    aws.String("https://" + presignVerifyEndpoint),
+		Credentials: credentials.NewStaticCredentials("AKID", "SECRET", ""),
+	})
+	req, _ := svc.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:   aws.String("uploads"),
+		Key:      aws.String("incoming/key"),
+		Metadata: map[string]*string{"owner": aws.String("alice")},
+	})
+	s, header, err := req.PresignRequest(15 * time.Minute)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	u, _ := url.Parse(s)
+	v := newTestPresignedURLVerifier(time.Now())
+
+	if _, err := v.Verify("PUT", u, header); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	header.Set("X-Amz-Meta-Owner", "mallory")
+	if _, err := v.Verify("PUT", u, header); err == nil {
+		t.Fatalf("expect error, got none")
+	} else if e, a := s3.ErrCodePresignedURLDenied, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
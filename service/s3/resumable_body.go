@@ -0,0 +1,190 @@
+package s3
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// ErrCodeResumeBody is the error code returned by the body of a GetObject
+// response when the body's download was interrupted, and could not be
+// resumed.
+const ErrCodeResumeBody = "ResumeBodyError"
+
+const resumableBodyHandlerName = "s3.ResumableBodyHandler"
+
+// WithResumableBody is a request option for GetObject requests which wraps
+// the response's body in a reader that transparently resumes the download if
+// reading the body fails mid-stream, such as on a connection reset. The
+// download is resumed by a ranged GetObject request starting at the last byte
+// read, conditional on the object's ETag matching the original response. Up
+// to maxResumes attempts will be made to resume the download of the body.
+//
+// Responses without an ETag, and requests with a suffix byte range, e.g.
+// "bytes=-500", are not resumable. The option has no effect on other API
+// operations.
+//
+//     out, err := svc.GetObjectWithContext(ctx, params, s3.WithResumableBody(3))
+func WithResumableBody(maxResumes int) request.Option {
+	return func(r *request.Request) {
+		if r.Operation.Name != opGetObject {
+			return
+		}
+
+		r.Handlers.UnmarshalMeta.PushBackNamed(request.NamedHandler{
+			Name: resumableBodyHandlerName,
+			Fn: func(r *request.Request) {
+				wrapResumableBody(r, maxResumes)
+			},
+		})
+	}
+}
+
+// wrapResumableBody replaces the HTTP response body of successful GetObject
+// requests with a resumableBody.
+func wrapResumableBody(r *request.Request, maxResumes int) {
+	if maxResumes <= 0 || r.HTTPResponse.StatusCode/100 != 2 {
+		return
+	}
+
+	in, ok := r.Params.(*GetObjectInput)
+	if !ok {
+		return
+	}
+	etag := r.HTTPResponse.Header.Get("ETag")
+	if len(etag) == 0 {
+		return
+	}
+	start, end, ok := parseByteRange(aws.StringValue(in.Range))
+	if !ok {
+		return
+	}
+
+	r.HTTPResponse.Body = &resumableBody{
+		body:       r.HTTPResponse.Body,
+		req:        r,
+		input:      *in,
+		etag:       etag,
+		start:      start,
+		end:        end,
+		maxResumes: maxResumes,
+	}
+}
+
+// parseByteRange returns the first and last byte positions of a GetObject
+// Range value. The last byte position is -1 if the range is open ended.
+// Returns false if the range cannot be resumed.
+func parseByteRange(v string) (start, end int64, ok bool) {
+	if len(v) == 0 {
+		return 0, -1, true
+	}
+
+	const prefix = "bytes="
+	if !strings.HasPrefix(v, prefix) || strings.Contains(v, ",") {
+		return 0, 0, false
+	}
+
+	parts := strings.SplitN(v[len(prefix):], "-", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 {
+		return 0, 0, false
+	}
+
+	start, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if len(parts[1]) == 0 {
+		return start, -1, true
+	}
+
+	end, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return start, end, true
+}
+
+// resumableBody reads the body of a GetObject response, resuming the download
+// with a ranged GetObject request if reading the body fails.
+type resumableBody struct {
+	body io.ReadCloser
+
+	req   *request.Request
+	input GetObjectInput
+	etag  string
+
+	start, end int64
+	read       int64
+
+	resumes    int
+	maxResumes int
+}
+
+func (b *resumableBody) Read(p []byte) (int, error) {
+	for {
+		n, err := b.body.Read(p)
+		b.read += int64(n)
+		if err == nil || err == io.EOF {
+			return n, err
+		}
+
+		if ctx := b.req.Context(); ctx.Err() != nil || b.resumes >= b.maxResumes {
+			return n, err
+		}
+		b.resumes++
+
+		if rerr := b.resume(); rerr != nil {
+			return n, awserr.New(ErrCodeResumeBody,
+				fmt.Sprintf("failed to resume body after %d bytes, %v", b.read, err), rerr)
+		}
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+func (b *resumableBody) Close() error {
+	return b.body.Close()
+}
+
+// resume requests the remaining bytes of the object, replacing the
+// interrupted body with the body of the ranged response.
+func (b *resumableBody) resume() error {
+	// The interrupted body is closed after the ranged request is sent, since
+	// closing the body may release the request's context.
+	defer b.body.Close()
+
+	in := b.input
+	rng := fmt.Sprintf("bytes=%d-", b.start+b.read)
+	if b.end >= 0 {
+		rng += strconv.FormatInt(b.end, 10)
+	}
+	in.Range = aws.String(rng)
+	in.IfMatch = aws.String(b.etag)
+
+	handlers := b.req.Handlers.Copy()
+	handlers.UnmarshalMeta.RemoveByName(resumableBodyHandlerName)
+
+	out := &GetObjectOutput{}
+	req := request.New(b.req.Config, b.req.ClientInfo, handlers, b.req.Retryer,
+		b.req.Operation, &in, out)
+	req.SetContext(b.req.Context())
+	if err := req.Send(); err != nil {
+		return err
+	}
+
+	if etag := aws.StringValue(out.ETag); etag != b.etag {
+		out.Body.Close()
+		return awserr.New(ErrCodeResumeBody,
+			fmt.Sprintf("object ETag changed from %s to %s", b.etag, etag), nil)
+	}
+
+	b.body = out.Body
+	return nil
+}
@@ -0,0 +1,179 @@
+package s3_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// interruptedReader returns an error after reading the first n bytes.
+type interruptedReader struct {
+	r io.Reader
+	n int
+}
+
+func (r *interruptedReader) Read(p []byte) (int, error) {
+	if r.n <= 0 {
+		return 0, fmt.Errorf("read: connection reset by peer")
+	}
+	if len(p) > r.n {
+		p = p[:r.n]
+	}
+	n, err := r.r.Read(p)
+	r.n -= n
+	return n, err
+}
+
+type resumeTestResponse struct {
+	ETag    string
+	Status  int
+	Content string
+	Cutoff  int
+}
+
+func newResumeTestSvc(t *testing.T, resps []resumeTestResponse) (*s3.S3, *[]http.Header) {
+	var reqHeaders []http.Header
+
+	svc := s3.New(unit.Session)
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		if len(reqHeaders) >= len(resps) {
+			t.Fatalf("unexpected request %d", len(reqHeaders)+1)
+		}
+		resp := resps[len(reqHeaders)]
+		reqHeaders = append(reqHeaders, r.HTTPRequest.Header)
+
+		var body io.Reader = bytes.NewReader([]byte(resp.Content))
+		if resp.Cutoff > 0 {
+			body = &interruptedReader{r: body, n: resp.Cutoff}
+		}
+
+		r.HTTPResponse = &http.Response{
+			StatusCode: resp.Status,
+			Header:     http.Header{"Etag": []string{resp.ETag}},
+			Body:       ioutil.NopCloser(body),
+		}
+	})
+
+	return svc, &reqHeaders
+}
+
+func TestGetObjectResumableBody(t *testing.T) {
+	svc, headers := newResumeTestSvc(t, []resumeTestResponse{
+		{ETag: `"abc"`, Status: 200, Content: "0123456789", Cutoff: 4},
+		{ETag: `"abc"`, Status: 206, Content: "456789", Cutoff: 3},
+		{ETag: `"abc"`, Status: 206, Content: "789"},
+	})
+
+	out, err := svc.GetObjectWithContext(aws.BackgroundContext(), &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("key"),
+	}, s3.WithResumableBody(2))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer out.Body.Close()
+
+	b, err := ioutil.ReadAll(out.Body)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "0123456789", string(b); e != a {
+		t.Errorf("expect %q body, got %q", e, a)
+	}
+
+	if e, a := 3, len(*headers); e != a {
+		t.Fatalf("expect %v requests, got %v", e, a)
+	}
+	for i, rng := range []string{"bytes=4-", "bytes=7-"} {
+		h := (*headers)[i+1]
+		if e, a := rng, h.Get("Range"); e != a {
+			t.Errorf("%d, expect %v range, got %v", i, e, a)
+		}
+		if e, a := `"abc"`, h.Get("If-Match"); e != a {
+			t.Errorf("%d, expect %v if-match, got %v", i, e, a)
+		}
+	}
+}
+
+func TestGetObjectResumableBody_Range(t *testing.T) {
+	svc, headers := newResumeTestSvc(t, []resumeTestResponse{
+		{ETag: `"abc"`, Status: 206, Content: "2345", Cutoff: 1},
+		{ETag: `"abc"`, Status: 206, Content: "345"},
+	})
+
+	out, err := svc.GetObjectWithContext(aws.BackgroundContext(), &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("key"),
+		Range:  aws.String("bytes=2-5"),
+	}, s3.WithResumableBody(1))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	b, err := ioutil.ReadAll(out.Body)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "2345", string(b); e != a {
+		t.Errorf("expect %q body, got %q", e, a)
+	}
+	if e, a := "bytes=3-5", (*headers)[1].Get("Range"); e != a {
+		t.Errorf("expect %v range, got %v", e, a)
+	}
+}
+
+func TestGetObjectResumableBody_Exhausted(t *testing.T) {
+	svc, _ := newResumeTestSvc(t, []resumeTestResponse{
+		{ETag: `"abc"`, Status: 200, Content: "0123456789", Cutoff: 4},
+		{ETag: `"abc"`, Status: 206, Content: "456789", Cutoff: 3},
+	})
+
+	out, err := svc.GetObjectWithContext(aws.BackgroundContext(), &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("key"),
+	}, s3.WithResumableBody(1))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	b, err := ioutil.ReadAll(out.Body)
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := "0123456", string(b); e != a {
+		t.Errorf("expect %q body, got %q", e, a)
+	}
+}
+
+func TestGetObjectResumableBody_ETagChanged(t *testing.T) {
+	svc, _ := newResumeTestSvc(t, []resumeTestResponse{
+		{ETag: `"abc"`, Status: 200, Content: "0123456789", Cutoff: 4},
+		{ETag: `"def"`, Status: 206, Content: "456789"},
+	})
+
+	out, err := svc.GetObjectWithContext(aws.BackgroundContext(), &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("key"),
+	}, s3.WithResumableBody(1))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	_, err = ioutil.ReadAll(out.Body)
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := s3.ErrCodeResumeBody, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
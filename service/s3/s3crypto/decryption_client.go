@@ -6,6 +6,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/keyprotect"
 	"github.com/aws/aws-sdk-go/service/kms"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
@@ -21,6 +22,7 @@ type CEKEntry func(CipherData) (ContentCipher, error)
 // will handle all get object requests from Amazon S3.
 // Supported key wrapping algorithms:
 //	*AWS KMS
+//	*IBM Key Protect and Hyper Protect Crypto Services
 //
 // Supported content ciphers:
 //	* AES/GCM
@@ -56,6 +58,7 @@ func NewDecryptionClient(prov client.ConfigProvider, options ...func(*Decryption
 			KMSWrap: (kmsKeyHandler{
 				kms: kms.New(prov),
 			}).decryptHandler,
+			KeyProtectWrap: NewKeyProtectWrapEntry(keyprotect.New(prov)),
 		},
 		CEKRegistry: map[string]CEKEntry{
 			AESGCMNoPadding:                                          newAESGCMContentCipher,
@@ -25,6 +25,13 @@ Creating an S3 cryptography client
 	svc := s3crypto.NewEncryptionClient(sess, s3crypto.AESGCMContentCipherBuilder(handler))
 	svc := s3crypto.NewDecryptionClient(sess)
 
+Data keys can also be wrapped with the root keys of IBM Key Protect or Hyper Protect Crypto Services
+instances, identified by their CRN, to encrypt the objects of IBM Cloud Object Storage buckets. The
+decryption client unwraps these keys with a Key Protect client of the session.
+
+	rootKeyCRN := "crn:v1:bluemix:public:kms:us-south:a/<account>:<instance>:key:<key>"
+	handler, err := s3crypto.NewKeyProtectKeyGenerator(keyprotect.New(sess), rootKeyCRN)
+
 Configuration of the S3 cryptography client
 
 	cfg := s3crypto.EncryptionConfig{
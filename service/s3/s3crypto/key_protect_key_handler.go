@@ -0,0 +1,171 @@
+package s3crypto
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/keyprotect"
+	"github.com/aws/aws-sdk-go/service/keyprotect/keyprotectiface"
+)
+
+const (
+	// KeyProtectWrap is a constant used during decryption to build an IBM Key
+	// Protect key handler. Keys wrapped with Hyper Protect Crypto Services
+	// root keys use the same wrap algorithm.
+	KeyProtectWrap = "ibm-kp"
+
+	// keyProtectRootKeyCRN is the material description key of the root key's
+	// CRN.
+	keyProtectRootKeyCRN = "ibm_root_key_crn"
+)
+
+// keyProtectKeyHandler will make calls to Key Protect to wrap and unwrap keys
+// with a root key.
+type keyProtectKeyHandler struct {
+	kp      keyprotectiface.KeyProtectAPI
+	rootKey keyprotect.KeyCRN
+
+	CipherData
+}
+
+// NewKeyProtectKeyGenerator builds a new Key Protect key provider using the
+// CRN of the Key Protect or Hyper Protect Crypto Services root key wrapping
+// the data keys. The client must send requests to the endpoint of the root
+// key's instance.
+//
+// Example:
+//	sess := session.Must(session.NewSession(&aws.Config{
+//		Region:      aws.String("us-south"),
+//		Credentials: ibmcreds.NewCredentialsClient(apiKey, serviceInstanceID, ""),
+//	}))
+//	rootKeyCRN := "crn:v1:bluemix:public:kms:us-south:a/account:instance-guid:key:key-id"
+//	handler, err := s3crypto.NewKeyProtectKeyGenerator(keyprotect.New(sess), rootKeyCRN)
+//	svc := s3crypto.NewEncryptionClient(sess, s3crypto.AESGCMContentCipherBuilder(handler), func(c *s3crypto.EncryptionClient) {
+//		c.S3Client = cos.New(sess)
+//	})
+func NewKeyProtectKeyGenerator(kpClient keyprotectiface.KeyProtectAPI, rootKeyCRN string) (CipherDataGenerator, error) {
+	return NewKeyProtectKeyGeneratorWithMatDesc(kpClient, rootKeyCRN, MaterialDescription{})
+}
+
+// NewKeyProtectKeyGeneratorWithMatDesc builds a new Key Protect key provider
+// using the CRN of the root key and material description. The material
+// description is the additional authentication data of the wrapped keys.
+//
+// Example:
+//	matdesc := s3crypto.MaterialDescription{}
+//	handler, err := s3crypto.NewKeyProtectKeyGeneratorWithMatDesc(keyprotect.New(sess), rootKeyCRN, matdesc)
+func NewKeyProtectKeyGeneratorWithMatDesc(kpClient keyprotectiface.KeyProtectAPI, rootKeyCRN string, matdesc MaterialDescription) (CipherDataGenerator, error) {
+	rootKey, err := keyprotect.ParseKeyCRN(rootKeyCRN)
+	if err != nil {
+		return nil, err
+	}
+
+	if matdesc == nil {
+		matdesc = MaterialDescription{}
+	}
+	matdesc[keyProtectRootKeyCRN] = &rootKeyCRN
+
+	// These values are read only making them thread safe
+	kp := &keyProtectKeyHandler{
+		kp:      kpClient,
+		rootKey: rootKey,
+	}
+	// These values are read only making them thread safe
+	kp.CipherData.WrapAlgorithm = KeyProtectWrap
+	kp.CipherData.MaterialDescription = matdesc
+	return kp, nil
+}
+
+// NewKeyProtectWrapEntry returns the WrapEntry of the decryption client
+// unwrapping keys with the Key Protect client provided, such as a client of
+// a Hyper Protect Crypto Services instance's endpoint.
+//
+// Example:
+//	svc := s3crypto.NewDecryptionClient(sess)
+//	svc.WrapRegistry[s3crypto.KeyProtectWrap] = s3crypto.NewKeyProtectWrapEntry(keyprotect.New(sess, &aws.Config{
+//		Endpoint: aws.String(hpcsEndpoint),
+//	}))
+func NewKeyProtectWrapEntry(kpClient keyprotectiface.KeyProtectAPI) WrapEntry {
+	return (keyProtectKeyHandler{kp: kpClient}).decryptHandler
+}
+
+// decryptHandler initializes a Key Protect keyprovider with a material
+// description. This is used with decrypting Key Protect wrapped content, due
+// to the root key CRN being in the material description.
+func (kp keyProtectKeyHandler) decryptHandler(env Envelope) (CipherDataDecrypter, error) {
+	m := MaterialDescription{}
+	err := m.decodeDescription([]byte(env.MatDesc))
+	if err != nil {
+		return nil, err
+	}
+
+	crn, ok := m[keyProtectRootKeyCRN]
+	if !ok {
+		return nil, awserr.New("MissingRootKeyCRNError", "Material description is missing root key CRN", nil)
+	}
+	rootKey, err := keyprotect.ParseKeyCRN(aws.StringValue(crn))
+	if err != nil {
+		return nil, err
+	}
+
+	kp.CipherData.MaterialDescription = m
+	kp.rootKey = rootKey
+	kp.WrapAlgorithm = KeyProtectWrap
+	return &kp, nil
+}
+
+// DecryptKey makes a call to Key Protect to unwrap the key.
+func (kp *keyProtectKeyHandler) DecryptKey(key []byte) ([]byte, error) {
+	out, err := kp.kp.UnwrapKey(&keyprotect.UnwrapKeyInput{
+		AAD:        kp.aad(),
+		Ciphertext: key,
+		InstanceID: aws.String(kp.rootKey.InstanceID),
+		KeyID:      aws.String(kp.rootKey.KeyID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
+
+// GenerateCipherData generates a data key, and makes a call to Key Protect
+// to wrap it with the root key, setting the encrypted key.
+func (kp *keyProtectKeyHandler) GenerateCipherData(keySize, ivSize int) (CipherData, error) {
+	key := generateBytes(keySize)
+	out, err := kp.kp.WrapKey(&keyprotect.WrapKeyInput{
+		AAD:        kp.aad(),
+		InstanceID: aws.String(kp.rootKey.InstanceID),
+		KeyID:      aws.String(kp.rootKey.KeyID),
+		Plaintext:  key,
+	})
+	if err != nil {
+		return CipherData{}, err
+	}
+
+	iv := generateBytes(ivSize)
+	cd := CipherData{
+		Key:                 key,
+		IV:                  iv,
+		WrapAlgorithm:       KeyProtectWrap,
+		MaterialDescription: kp.CipherData.MaterialDescription,
+		EncryptedKey:        out.Ciphertext,
+	}
+	return cd, nil
+}
+
+// aad returns the additional authentication data of the key handler's
+// material description, its "key=value" pairs sorted by key.
+func (kp *keyProtectKeyHandler) aad() []*string {
+	keys := make([]string, 0, len(kp.CipherData.MaterialDescription))
+	for k := range kp.CipherData.MaterialDescription {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	aad := make([]*string, 0, len(keys))
+	for _, k := range keys {
+		aad = append(aad, aws.String(k+"="+aws.StringValue(kp.CipherData.MaterialDescription[k])))
+	}
+	return aad
+}
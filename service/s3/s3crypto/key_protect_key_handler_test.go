@@ -0,0 +1,122 @@
+package s3crypto
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/keyprotect"
+	"github.com/aws/aws-sdk-go/service/keyprotect/keyprotectiface"
+)
+
+const testRootKeyCRN = "crn:v1:bluemix:public:kms:us-south:a/account:instance-guid:key:key-id"
+
+// mockKeyProtect "wraps" keys by reversing them, checking the root key and
+// additional authentication data of the requests.
+type mockKeyProtect struct {
+	keyprotectiface.KeyProtectAPI
+	t   *testing.T
+	aad []string
+}
+
+func (m *mockKeyProtect) check(instanceID, keyID *string, aad []*string) {
+	if e, a := "instance-guid", aws.StringValue(instanceID); e != a {
+		m.t.Errorf("expect %v instance ID, got %v", e, a)
+	}
+	if e, a := "key-id", aws.StringValue(keyID); e != a {
+		m.t.Errorf("expect %v key ID, got %v", e, a)
+	}
+	if e, a := m.aad, aws.StringValueSlice(aad); len(e) != len(a) {
+		m.t.Errorf("expect %v AAD, got %v", e, a)
+	} else {
+		for i := range e {
+			if e[i] != a[i] {
+				m.t.Errorf("expect %v AAD, got %v", e, a)
+			}
+		}
+	}
+}
+
+func (m *mockKeyProtect) WrapKey(input *keyprotect.WrapKeyInput) (*keyprotect.WrapKeyOutput, error) {
+	m.check(input.InstanceID, input.KeyID, input.AAD)
+	return &keyprotect.WrapKeyOutput{Ciphertext: reverseBytes(input.Plaintext)}, nil
+}
+
+func (m *mockKeyProtect) UnwrapKey(input *keyprotect.UnwrapKeyInput) (*keyprotect.UnwrapKeyOutput, error) {
+	m.check(input.InstanceID, input.KeyID, input.AAD)
+	return &keyprotect.UnwrapKeyOutput{Plaintext: reverseBytes(input.Ciphertext)}, nil
+}
+
+func reverseBytes(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
+
+func TestKeyProtectGenerateDecryptCipherData(t *testing.T) {
+	kp := &mockKeyProtect{t: t, aad: []string{
+		"ibm_root_key_crn=" + testRootKeyCRN,
+		"purpose=test",
+	}}
+
+	handler, err := NewKeyProtectKeyGeneratorWithMatDesc(kp, testRootKeyCRN, MaterialDescription{
+		"purpose": aws.String("test"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	cd, err := handler.GenerateCipherData(32, 16)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 32, len(cd.Key); e != a {
+		t.Errorf("expect %v key size, got %v", e, a)
+	}
+	if e, a := 16, len(cd.IV); e != a {
+		t.Errorf("expect %v IV size, got %v", e, a)
+	}
+	if e, a := KeyProtectWrap, cd.WrapAlgorithm; e != a {
+		t.Errorf("expect %v wrap algorithm, got %v", e, a)
+	}
+	if e, a := reverseBytes(cd.Key), cd.EncryptedKey; !bytes.Equal(e, a) {
+		t.Errorf("expect %v encrypted key, got %v", e, a)
+	}
+
+	matdesc, err := cd.MaterialDescription.encodeDescription()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	decrypter, err := NewKeyProtectWrapEntry(kp)(Envelope{MatDesc: string(matdesc)})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	key, err := decrypter.DecryptKey(cd.EncryptedKey)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := cd.Key, key; !bytes.Equal(e, a) {
+		t.Errorf("expect %v key, got %v", e, a)
+	}
+}
+
+func TestKeyProtectInvalidRootKeyCRN(t *testing.T) {
+	_, err := NewKeyProtectKeyGenerator(&mockKeyProtect{t: t}, "key-id")
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := keyprotect.ErrCodeInvalidKeyCRN, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+
+	_, err = NewKeyProtectWrapEntry(&mockKeyProtect{t: t})(Envelope{MatDesc: `{"purpose":"test"}`})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := "MissingRootKeyCRNError", err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
@@ -0,0 +1,177 @@
+package s3manager
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// DefaultMaxAdaptiveConcurrency is the default maximum number of parts
+// transferred in parallel when adaptive concurrency is enabled.
+const DefaultMaxAdaptiveConcurrency = 16
+
+// adaptiveRateDrop is the fraction of the previous round's throughput below
+// which the concurrency limit is decreased by one part.
+const adaptiveRateDrop = 0.8
+
+// concurrencyLimiter limits the number of parts of a transfer in flight,
+// adjusting the limit with the transfer's throughput and errors. The limit is
+// increased by one part each round of parts the throughput does not decrease
+// in, and halved when a part is retried or fails. A nil concurrencyLimiter
+// does not limit the transfer.
+type concurrencyLimiter struct {
+	now func() time.Time
+
+	m        sync.Mutex
+	cond     *sync.Cond
+	limit    int
+	max      int
+	inFlight int
+
+	// The round of parts the throughput is measured over. A round completes
+	// once as many parts as the limit have been transferred.
+	roundStart time.Time
+	roundBytes int64
+	roundParts int
+	lastRate   float64
+
+	// Set once the limit has been decreased, until the next round completes,
+	// so that parts failing together only decrease the limit once.
+	decreased bool
+}
+
+// newConcurrencyLimiter returns a concurrencyLimiter starting at the initial
+// limit, if adaptive is set, or nil otherwise.
+func newConcurrencyLimiter(adaptive bool, initial, max int) *concurrencyLimiter {
+	if !adaptive {
+		return nil
+	}
+
+	if max <= 0 {
+		max = DefaultMaxAdaptiveConcurrency
+	}
+	if initial > max {
+		initial = max
+	}
+	if initial < 1 {
+		initial = 1
+	}
+
+	l := &concurrencyLimiter{now: time.Now, limit: initial, max: max}
+	l.cond = sync.NewCond(&l.m)
+	l.roundStart = l.now()
+
+	return l
+}
+
+// workers returns the number of worker goroutines a transfer should spin up,
+// enough for the maximum limit if the transfer is limited.
+func (l *concurrencyLimiter) workers(concurrency int) int {
+	if l == nil {
+		return concurrency
+	}
+	return l.max
+}
+
+// getLimit returns the current limit.
+func (l *concurrencyLimiter) getLimit() int {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	return l.limit
+}
+
+// acquire blocks until the limit allows another part in flight.
+func (l *concurrencyLimiter) acquire() {
+	if l == nil {
+		return
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	for l.inFlight >= l.limit {
+		l.cond.Wait()
+	}
+	l.inFlight++
+}
+
+// release records a part of n bytes as no longer in flight, and adjusts the
+// limit. Congested is whether the part was retried or failed.
+func (l *concurrencyLimiter) release(n int64, congested bool) {
+	if l == nil {
+		return
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	l.inFlight--
+	defer l.cond.Broadcast()
+
+	now := l.now()
+	if congested {
+		if !l.decreased {
+			// Multiplicative decrease.
+			if l.limit /= 2; l.limit < 1 {
+				l.limit = 1
+			}
+			l.decreased = true
+			l.lastRate = 0
+		}
+		l.resetRound(now)
+		return
+	}
+
+	l.roundBytes += n
+	l.roundParts++
+	if l.roundParts < l.limit {
+		return
+	}
+
+	elapsed := now.Sub(l.roundStart)
+	if elapsed <= 0 {
+		l.resetRound(now)
+		return
+	}
+
+	rate := float64(l.roundBytes) / elapsed.Seconds()
+	switch {
+	case rate >= l.lastRate && l.limit < l.max:
+		// Additive increase while the throughput improves.
+		l.limit++
+	case rate < l.lastRate*adaptiveRateDrop && l.limit > 1:
+		l.limit--
+	}
+	l.lastRate = rate
+	l.decreased = false
+	l.resetRound(now)
+}
+
+// resetRound starts a new round of parts.
+func (l *concurrencyLimiter) resetRound(now time.Time) {
+	l.roundStart = now
+	l.roundBytes = 0
+	l.roundParts = 0
+}
+
+// observe returns the request options with an additional option setting
+// congested if the request is retried, so that retries due to throttling or
+// server errors decrease the limit. If the transfer is not limited the
+// options are returned unchanged.
+func (l *concurrencyLimiter) observe(opts []request.Option, congested *bool) []request.Option {
+	if l == nil {
+		return opts
+	}
+
+	observed := make([]request.Option, 0, len(opts)+1)
+	observed = append(observed, opts...)
+	return append(observed, func(r *request.Request) {
+		r.Handlers.Complete.PushBack(func(r *request.Request) {
+			if r.RetryCount > 0 {
+				*congested = true
+			}
+		})
+	})
+}
@@ -0,0 +1,110 @@
+package s3manager
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestLimiter(initial, max int) (*concurrencyLimiter, *time.Time) {
+	l := newConcurrencyLimiter(true, initial, max)
+	now := l.roundStart
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestConcurrencyLimiterIncrease(t *testing.T) {
+	l, now := newTestLimiter(2, 3)
+
+	// Each round completes once as many parts as the limit are released.
+	for _, e := range []int{2, 3, 3, 3} {
+		if a := l.getLimit(); e != a {
+			t.Fatalf("expect %v limit, got %v", e, a)
+		}
+		for i := 0; i < l.getLimit(); i++ {
+			l.acquire()
+		}
+		*now = now.Add(time.Second)
+		for i, n := 0, l.getLimit(); i < n; i++ {
+			l.release(100, false)
+		}
+	}
+}
+
+func TestConcurrencyLimiterRateDrop(t *testing.T) {
+	l, now := newTestLimiter(4, 8)
+
+	release := func(parts int, d time.Duration) {
+		for i := 0; i < parts; i++ {
+			l.acquire()
+		}
+		*now = now.Add(d)
+		for i := 0; i < parts; i++ {
+			l.release(100, false)
+		}
+	}
+
+	release(4, time.Second)
+	if e, a := 5, l.getLimit(); e != a {
+		t.Fatalf("expect %v limit, got %v", e, a)
+	}
+
+	// The throughput halves, backing off by one part.
+	release(5, 2*time.Second+500*time.Millisecond)
+	if e, a := 4, l.getLimit(); e != a {
+		t.Errorf("expect %v limit, got %v", e, a)
+	}
+}
+
+func TestConcurrencyLimiterDecrease(t *testing.T) {
+	l, _ := newTestLimiter(8, 8)
+
+	for i := 0; i < 8; i++ {
+		l.acquire()
+	}
+
+	// Parts failing together only halve the limit once.
+	l.release(0, true)
+	l.release(0, true)
+	if e, a := 4, l.getLimit(); e != a {
+		t.Errorf("expect %v limit, got %v", e, a)
+	}
+}
+
+func TestConcurrencyLimiterAcquireBlocks(t *testing.T) {
+	l, _ := newTestLimiter(1, 1)
+	l.acquire()
+
+	acquired := make(chan struct{})
+	go func() {
+		l.acquire()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatalf("expect acquire to block while the limit is reached")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	l.release(0, false)
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatalf("expect acquire to unblock once released")
+	}
+}
+
+func TestConcurrencyLimiterDisabled(t *testing.T) {
+	l := newConcurrencyLimiter(false, 5, 10)
+	if l != nil {
+		t.Fatalf("expect nil limiter, got %v", l)
+	}
+	if e, a := 5, l.workers(5); e != a {
+		t.Errorf("expect %v workers, got %v", e, a)
+	}
+
+	opts := l.observe(nil, new(bool))
+	if e, a := 0, len(opts); e != a {
+		t.Errorf("expect %v options, got %v", e, a)
+	}
+}
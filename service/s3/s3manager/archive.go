@@ -0,0 +1,834 @@
+package s3manager
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// The archive formats objects can be packed into, and unpacked from.
+const (
+	// ArchiveFormatTar is the format of uncompressed tar archives.
+	ArchiveFormatTar = "tar"
+
+	// ArchiveFormatZip is the format of zip archives, whose entries are
+	// compressed with deflate.
+	ArchiveFormatZip = "zip"
+)
+
+// ErrCodeInvalidArchiveFormat is the error code returned when the format of
+// an archive is not one of the ArchiveFormat values.
+const ErrCodeInvalidArchiveFormat = "InvalidArchiveFormat"
+
+// ErrCodeInvalidArchive is the error code returned when an archive object is
+// malformed, or one of its entries cannot be read.
+const ErrCodeInvalidArchive = "InvalidArchive"
+
+// DefaultArchiveConcurrency is the default number of objects fetched ahead
+// when packing objects, and of entries uploaded in parallel when unpacking an
+// archive, with the Archiver.
+const DefaultArchiveConcurrency = 5
+
+// archiveReadAhead is the minimum size of the ranges read of a zip archive
+// object when reading its directory, for the directory not to be read with a
+// request per record.
+const archiveReadAhead = 1024 * 1024
+
+// The Archiver packs many objects into a single tar or zip archive object, and
+// unpacks archive objects back into individual objects, streaming the objects
+// and archives without staging them on disk. Packing small objects into an
+// archive saves a request per object when the objects are written and read
+// together. It is safe to call the Archiver's methods for multiple archives
+// and across concurrent goroutines. Mutating the Archiver's properties is not
+// safe to be done concurrently.
+type Archiver struct {
+	// The number of objects to fetch ahead of the one being written to an
+	// archive when packing, and the number of entries to upload in parallel
+	// when unpacking. If this is set to zero, the DefaultArchiveConcurrency
+	// value will be used.
+	Concurrency int
+
+	// An S3 client to use when listing and reading objects.
+	S3 s3iface.S3API
+
+	// The Uploader to upload archives, and unpacked entries, with. Entries of
+	// tar archives no larger than its PartSize are buffered in memory to be
+	// uploaded in parallel, larger entries are streamed one at a time.
+	Uploader *Uploader
+
+	// List of request options that will be passed down to the requests
+	// listing and reading objects made by the archiver.
+	RequestOptions []request.Option
+}
+
+// NewArchiver creates a new Archiver instance to pack and unpack archives
+// with. Pass in additional functional options to customize the archiver
+// behavior.
+//
+// Example:
+//     // The session the S3 Archiver will use
+//     sess := session.Must(session.NewSession())
+//
+//     // Create an archiver with the session, fetching 10 objects ahead.
+//     archiver := s3manager.NewArchiver(sess, func(a *s3manager.Archiver) {
+//          a.Concurrency = 10
+//     })
+func NewArchiver(c client.ConfigProvider, options ...func(*Archiver)) *Archiver {
+	return NewArchiverWithClient(s3.New(c), options...)
+}
+
+// NewArchiverWithClient creates a new Archiver instance to pack and unpack
+// archives with the S3 service client provided. The archiver's Uploader is
+// created with the same client.
+func NewArchiverWithClient(svc s3iface.S3API, options ...func(*Archiver)) *Archiver {
+	a := &Archiver{
+		S3:          svc,
+		Concurrency: DefaultArchiveConcurrency,
+		Uploader:    NewUploaderWithClient(svc),
+	}
+
+	for _, option := range options {
+		option(a)
+	}
+
+	return a
+}
+
+// PackInput provides the objects to pack, and the archive object to pack them
+// into.
+type PackInput struct {
+	// The bucket of the objects to pack.
+	Bucket *string
+
+	// The key prefix of the objects to pack. The name of an object's entry
+	// is its key relative to the prefix.
+	Prefix *string
+
+	// The keys of the objects to pack, in order, if set. Otherwise every
+	// object with a key beginning with Prefix is packed, in the order of the
+	// bucket's listing. Keys ending with a slash are not packed.
+	Keys []string
+
+	// The bucket of the archive object. Defaults to Bucket if not set.
+	ArchiveBucket *string
+
+	// The key of the archive object.
+	ArchiveKey *string
+
+	// The format of the archive, one of the ArchiveFormat values. If not set
+	// the format is inferred from the extension of ArchiveKey, tar unless
+	// the key ends with ".zip".
+	Format string
+}
+
+// PackOutput represents a response from the Pack() call.
+type PackOutput struct {
+	// The URL where the archive was uploaded to.
+	Location string
+
+	// The version of the archive that was uploaded. Will only be populated
+	// if the S3 Bucket is versioned.
+	VersionID *string
+
+	// The ID of the multipart upload of the archive, if it was uploaded in
+	// parts.
+	UploadID string
+
+	// The number of objects packed.
+	Count int64
+
+	// The total size (in bytes) of the objects packed.
+	Size int64
+}
+
+// UnpackInput provides the archive object to unpack, and the bucket and key
+// prefix to unpack its entries to.
+type UnpackInput struct {
+	// The bucket of the archive object.
+	Bucket *string
+
+	// The key of the archive object.
+	Key *string
+
+	// The format of the archive, one of the ArchiveFormat values. If not set
+	// the format is inferred from the extension of Key, tar unless the key
+	// ends with ".zip".
+	Format string
+
+	// The bucket to unpack the entries to. Defaults to Bucket if not set.
+	DestinationBucket *string
+
+	// The key prefix of the unpacked objects. The key of an object is the
+	// prefix followed by the name of its entry. A slash is appended to the
+	// prefix if it does not end with one.
+	Prefix *string
+}
+
+// UnpackOutput represents a response from the Unpack() call.
+type UnpackOutput struct {
+	// The number of entries unpacked. Directory entries, and entries which
+	// are not regular files, are skipped.
+	Count int64
+}
+
+// Pack streams the objects of the input into a single archive object,
+// uploaded with the archiver's Uploader as the objects are read. The objects
+// are fetched ahead of being written to the archive, up to the archiver's
+// Concurrency.
+//
+// Additional functional options can be provided to configure the individual
+// call. These options are copies of the Archiver instance Pack is called
+// from. Modifying the options will not impact the original Archiver instance.
+//
+// It is safe to call this method concurrently across goroutines.
+//
+// Example:
+//     // Pack the day's small log objects into a single archive.
+//     result, err := archiver.Pack(&s3manager.PackInput{
+//         Bucket:     aws.String("bucket"),
+//         Prefix:     aws.String("logs/2020-01-02/"),
+//         ArchiveKey: aws.String("archives/logs-2020-01-02.tar"),
+//     })
+func (a Archiver) Pack(input *PackInput, options ...func(*Archiver)) (*PackOutput, error) {
+	return a.PackWithContext(aws.BackgroundContext(), input, options...)
+}
+
+// PackWithContext packs the objects into an archive the same as Pack, with
+// the addition of the ability to pass a context and additional request
+// options.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (a Archiver) PackWithContext(ctx aws.Context, input *PackInput, options ...func(*Archiver)) (*PackOutput, error) {
+	a.init(options)
+
+	format, err := archiveFormat(input.Format, input.ArchiveKey)
+	if err != nil {
+		return nil, err
+	}
+	bucket := input.ArchiveBucket
+	if bucket == nil {
+		bucket = input.Bucket
+	}
+
+	out := &PackOutput{}
+	pr, pw := io.Pipe()
+	packed := make(chan error, 1)
+	go func() {
+		packed <- a.pack(ctx, input, format, pw, out)
+	}()
+
+	contentType := "application/x-tar"
+	if format == ArchiveFormatZip {
+		contentType = "application/zip"
+	}
+	resp, err := a.Uploader.UploadWithContext(ctx, &UploadInput{
+		Bucket:      bucket,
+		Key:         input.ArchiveKey,
+		Body:        pr,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		// Stop the packing of the objects, if the upload failed before the
+		// archive was read entirely.
+		pr.CloseWithError(err)
+	}
+	if packErr := <-packed; packErr != nil && packErr != err {
+		return out, packErr
+	}
+	if err != nil {
+		return out, err
+	}
+
+	out.Location = resp.Location
+	out.VersionID = resp.VersionID
+	out.UploadID = resp.UploadID
+	return out, nil
+}
+
+// Unpack reads the archive object of the input, uploading each of its
+// entries as an individual object with the archiver's Uploader. Tar archives
+// are streamed, zip archives are read with ranged GetObject requests of
+// their directory and of each entry. Entries are uploaded in parallel, up to
+// the archiver's Concurrency.
+//
+// The entries which could not be uploaded, and the error of the archive's
+// reading if any, are returned as a BatchError, along with the output.
+//
+// Additional functional options can be provided to configure the individual
+// call. These options are copies of the Archiver instance Unpack is called
+// from. Modifying the options will not impact the original Archiver instance.
+//
+// It is safe to call this method concurrently across goroutines.
+//
+// Example:
+//     // Unpack an archive to the objects under its original prefix.
+//     result, err := archiver.Unpack(&s3manager.UnpackInput{
+//         Bucket: aws.String("bucket"),
+//         Key:    aws.String("archives/logs-2020-01-02.tar"),
+//         Prefix: aws.String("logs/2020-01-02/"),
+//     })
+func (a Archiver) Unpack(input *UnpackInput, options ...func(*Archiver)) (*UnpackOutput, error) {
+	return a.UnpackWithContext(aws.BackgroundContext(), input, options...)
+}
+
+// UnpackWithContext unpacks the archive the same as Unpack, with the addition
+// of the ability to pass a context and additional request options.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (a Archiver) UnpackWithContext(ctx aws.Context, input *UnpackInput, options ...func(*Archiver)) (*UnpackOutput, error) {
+	a.init(options)
+
+	format, err := archiveFormat(input.Format, input.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &unpacker{
+		Archiver: a,
+		ctx:      ctx,
+		in:       input,
+		out:      &UnpackOutput{},
+		bucket:   input.DestinationBucket,
+		slots:    make(chan struct{}, a.Concurrency),
+	}
+	if u.bucket == nil {
+		u.bucket = input.Bucket
+	}
+
+	if format == ArchiveFormatZip {
+		err = u.unpackZip()
+	} else {
+		err = u.unpackTar()
+	}
+	u.wg.Wait()
+
+	if err != nil {
+		u.errs = append(u.errs, newError(err, input.Bucket, input.Key))
+	}
+	if len(u.errs) > 0 {
+		return u.out, NewBatchError("BatchedUnpackIncomplete", "some archive entries have failed to be unpacked.", u.errs)
+	}
+	return u.out, nil
+}
+
+// init applies the options to the archiver, and the defaults of its unset
+// properties.
+func (a *Archiver) init(options []func(*Archiver)) {
+	for _, option := range options {
+		option(a)
+	}
+	a.RequestOptions = append(a.RequestOptions, request.WithAppendUserAgent("S3Manager"))
+
+	if a.Concurrency <= 0 {
+		a.Concurrency = DefaultArchiveConcurrency
+	}
+}
+
+// archiveFormat returns the format of the archive object of the key, the
+// format provided if set.
+func archiveFormat(format string, key *string) (string, error) {
+	switch format {
+	case ArchiveFormatTar, ArchiveFormatZip:
+		return format, nil
+	case "":
+		if strings.HasSuffix(strings.ToLower(aws.StringValue(key)), ".zip") {
+			return ArchiveFormatZip, nil
+		}
+		return ArchiveFormatTar, nil
+	default:
+		return "", awserr.New(ErrCodeInvalidArchiveFormat,
+			fmt.Sprintf("invalid archive format %q, must be %q or %q", format, ArchiveFormatTar, ArchiveFormatZip), nil)
+	}
+}
+
+// packFetch is an object fetched to be packed into an archive.
+type packFetch struct {
+	key  string
+	resp *s3.GetObjectOutput
+	err  error
+	done chan struct{}
+}
+
+// close waits for the object to be fetched, and closes its body.
+func (f *packFetch) close() {
+	<-f.done
+	if f.resp != nil {
+		f.resp.Body.Close()
+	}
+}
+
+// pack writes the objects of the input to the pipe as an archive, closing the
+// pipe with the error which stopped the packing, if any.
+func (a Archiver) pack(ctx aws.Context, input *PackInput, format string, pw *io.PipeWriter, out *PackOutput) (err error) {
+	defer func() {
+		pw.CloseWithError(err)
+	}()
+
+	var w archiveWriter
+	if format == ArchiveFormatZip {
+		w = zipArchiveWriter{zip.NewWriter(pw)}
+	} else {
+		w = tarArchiveWriter{tar.NewWriter(pw)}
+	}
+
+	stop := make(chan struct{})
+	fetches := make(chan *packFetch, a.Concurrency)
+	go a.fetchObjects(ctx, input, fetches, stop)
+
+	for f := range fetches {
+		<-f.done
+		if err = f.err; err == nil {
+			name := strings.TrimPrefix(f.key, aws.StringValue(input.Prefix))
+			if name = strings.TrimLeft(name, "/"); len(name) == 0 {
+				name = f.key
+			}
+			err = w.writeEntry(name, f.resp)
+			f.resp.Body.Close()
+		}
+		if err != nil {
+			break
+		}
+
+		out.Count++
+		out.Size += aws.Int64Value(f.resp.ContentLength)
+	}
+
+	if err != nil {
+		close(stop)
+		for f := range fetches {
+			f.close()
+		}
+		return err
+	}
+
+	return w.Close()
+}
+
+// fetchObjects fetches the objects of the input in order, sending them to
+// the channel, until all objects have been fetched or stop is closed.
+func (a Archiver) fetchObjects(ctx aws.Context, input *PackInput, fetches chan<- *packFetch, stop <-chan struct{}) {
+	defer close(fetches)
+
+	send := func(f *packFetch) bool {
+		select {
+		case fetches <- f:
+			return true
+		case <-stop:
+			f.close()
+			return false
+		}
+	}
+	fetch := func(key string) bool {
+		f := &packFetch{key: key, done: make(chan struct{})}
+		go func() {
+			defer close(f.done)
+			f.resp, f.err = a.S3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+				Bucket: input.Bucket,
+				Key:    aws.String(key),
+			}, a.RequestOptions...)
+		}()
+		return send(f)
+	}
+
+	if input.Keys != nil {
+		for _, key := range input.Keys {
+			if !fetch(key) {
+				return
+			}
+		}
+		return
+	}
+
+	stopped := false
+	err := a.S3.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: input.Bucket,
+		Prefix: input.Prefix,
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			key := aws.StringValue(obj.Key)
+			if strings.HasSuffix(key, "/") {
+				continue
+			}
+			if !fetch(key) {
+				stopped = true
+				return false
+			}
+		}
+		return true
+	}, a.RequestOptions...)
+	if err != nil && !stopped {
+		f := &packFetch{err: err, done: make(chan struct{})}
+		close(f.done)
+		send(f)
+	}
+}
+
+// archiveWriter writes the objects of an archive.
+type archiveWriter interface {
+	writeEntry(name string, resp *s3.GetObjectOutput) error
+	Close() error
+}
+
+type tarArchiveWriter struct {
+	*tar.Writer
+}
+
+func (w tarArchiveWriter) writeEntry(name string, resp *s3.GetObjectOutput) error {
+	size := aws.Int64Value(resp.ContentLength)
+	err := w.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     size,
+		ModTime:  aws.TimeValue(resp.LastModified),
+		Typeflag: tar.TypeReg,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.CopyN(w, resp.Body, size); err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+type zipArchiveWriter struct {
+	*zip.Writer
+}
+
+func (w zipArchiveWriter) writeEntry(name string, resp *s3.GetObjectOutput) error {
+	header := &zip.FileHeader{
+		Name:   name,
+		Method: zip.Deflate,
+	}
+	header.SetModTime(aws.TimeValue(resp.LastModified))
+	header.SetMode(0644)
+
+	ew, err := w.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(ew, resp.Body)
+	return err
+}
+
+// unpacker unpacks the entries of an archive object.
+type unpacker struct {
+	Archiver
+
+	ctx    aws.Context
+	in     *UnpackInput
+	out    *UnpackOutput
+	bucket *string
+
+	wg    sync.WaitGroup
+	slots chan struct{}
+
+	m    sync.Mutex
+	errs []Error
+}
+
+// unpackTar streams the tar archive, uploading its entries. Entries no larger
+// than the Uploader's PartSize are buffered to be uploaded in parallel.
+func (u *unpacker) unpackTar() error {
+	resp, err := u.S3.GetObjectWithContext(u.ctx, &s3.GetObjectInput{
+		Bucket: u.in.Bucket,
+		Key:    u.in.Key,
+	}, u.RequestOptions...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	bufferSize := u.Uploader.PartSize
+	if bufferSize == 0 {
+		bufferSize = DefaultUploadPartSize
+	}
+
+	tr := tar.NewReader(resp.Body)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+		name := entryName(header.Name)
+		if len(name) == 0 {
+			continue
+		}
+
+		if header.Size > bufferSize {
+			u.done(name, u.upload(name, tr))
+			continue
+		}
+
+		buf := make([]byte, header.Size)
+		if _, err := io.ReadFull(tr, buf); err != nil {
+			return err
+		}
+		u.start(name, func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(buf)), nil
+		})
+	}
+}
+
+// unpackZip reads the directory of the zip archive, uploading its entries in
+// parallel, each read with a ranged GetObject request.
+func (u *unpacker) unpackZip() error {
+	head, err := u.S3.HeadObjectWithContext(u.ctx, &s3.HeadObjectInput{
+		Bucket: u.in.Bucket,
+		Key:    u.in.Key,
+	}, u.RequestOptions...)
+	if err != nil {
+		return err
+	}
+
+	r := &objectReaderAt{
+		ctx:     u.ctx,
+		svc:     u.S3,
+		bucket:  u.in.Bucket,
+		key:     u.in.Key,
+		etag:    head.ETag,
+		size:    aws.Int64Value(head.ContentLength),
+		options: u.RequestOptions,
+	}
+	zr, err := zip.NewReader(r, r.size)
+	if err != nil {
+		if _, ok := err.(awserr.Error); !ok {
+			err = awserr.New(ErrCodeInvalidArchive, "failed to read zip archive directory", err)
+		}
+		return err
+	}
+
+	for _, f := range zr.File {
+		if strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		name := entryName(f.Name)
+		if len(name) == 0 {
+			continue
+		}
+
+		f := f
+		u.start(name, func() (io.ReadCloser, error) {
+			return r.openEntry(f)
+		})
+	}
+	return nil
+}
+
+// entryName returns the name of the object of an archive entry, relative to
+// the unpacked prefix.
+func entryName(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// start uploads the entry in a goroutine, once fewer than Concurrency
+// entries are being uploaded.
+func (u *unpacker) start(name string, open func() (io.ReadCloser, error)) {
+	u.slots <- struct{}{}
+	u.wg.Add(1)
+	go func() {
+		defer func() {
+			<-u.slots
+			u.wg.Done()
+		}()
+
+		body, err := open()
+		if err == nil {
+			err = u.upload(name, body)
+			body.Close()
+		}
+		u.done(name, err)
+	}()
+}
+
+// upload uploads the body of the entry to its object.
+func (u *unpacker) upload(name string, body io.Reader) error {
+	_, err := u.Uploader.UploadWithContext(u.ctx, &UploadInput{
+		Bucket: u.bucket,
+		Key:    aws.String(u.key(name)),
+		Body:   body,
+	})
+	return err
+}
+
+// done records the result of the upload of the entry.
+func (u *unpacker) done(name string, err error) {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	if err != nil {
+		u.errs = append(u.errs, newError(err, u.bucket, aws.String(u.key(name))))
+		return
+	}
+	u.out.Count++
+}
+
+// key returns the key of the object of the entry.
+func (u *unpacker) key(name string) string {
+	prefix := aws.StringValue(u.in.Prefix)
+	if len(prefix) > 0 && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix + name
+}
+
+// objectReaderAt is an io.ReaderAt of an object, reading it with ranged
+// GetObject requests conditional on the object's ETag. The last range read
+// is cached, ranges are read at least archiveReadAhead bytes at a time.
+type objectReaderAt struct {
+	ctx     aws.Context
+	svc     s3iface.S3API
+	bucket  *string
+	key     *string
+	etag    *string
+	size    int64
+	options []request.Option
+
+	m   sync.Mutex
+	off int64
+	buf []byte
+}
+
+// ReadAt reads the bytes of the object at the offset, from the cached range
+// if it contains them.
+func (r *objectReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	end := off + int64(len(p))
+	if end > r.size {
+		end = r.size
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if off < r.off || end > r.off+int64(len(r.buf)) {
+		n := end - off
+		if n < archiveReadAhead {
+			n = archiveReadAhead
+		}
+		if off+n > r.size {
+			n = r.size - off
+		}
+
+		body, err := r.getRange(off, n)
+		if err != nil {
+			return 0, err
+		}
+		defer body.Close()
+
+		buf, err := ioutil.ReadAll(body)
+		if err != nil {
+			return 0, err
+		}
+		r.off, r.buf = off, buf
+	}
+
+	n := copy(p, r.buf[off-r.off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// getRange returns the body of the n bytes of the object at the offset.
+func (r *objectReaderAt) getRange(off, n int64) (io.ReadCloser, error) {
+	resp, err := r.svc.GetObjectWithContext(r.ctx, &s3.GetObjectInput{
+		Bucket:  r.bucket,
+		Key:     r.key,
+		IfMatch: r.etag,
+		Range:   aws.String(fmt.Sprintf("bytes=%d-%d", off, off+n-1)),
+	}, r.options...)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// openEntry returns a reader of the decompressed content of the zip archive
+// entry, read with a single ranged GetObject request, verifying its checksum.
+func (r *objectReaderAt) openEntry(f *zip.File) (io.ReadCloser, error) {
+	off, err := f.DataOffset()
+	if err != nil {
+		return nil, err
+	}
+
+	body := ioutil.NopCloser(bytes.NewReader(nil))
+	if f.CompressedSize64 > 0 {
+		if body, err = r.getRange(off, int64(f.CompressedSize64)); err != nil {
+			return nil, err
+		}
+	}
+
+	entry := &zipEntryReader{body: body, f: f, hash: crc32.NewIEEE()}
+	switch f.Method {
+	case zip.Store:
+		entry.r = body
+	case zip.Deflate:
+		entry.r = flate.NewReader(body)
+	default:
+		body.Close()
+		return nil, awserr.New(ErrCodeInvalidArchive,
+			fmt.Sprintf("unsupported compression method %d of zip entry %s", f.Method, f.Name), nil)
+	}
+	return entry, nil
+}
+
+// zipEntryReader reads the decompressed content of a zip archive entry,
+// verifying its size and CRC-32 checksum once read entirely.
+type zipEntryReader struct {
+	r    io.Reader
+	body io.ReadCloser
+	f    *zip.File
+	hash hash.Hash32
+	n    uint64
+}
+
+func (e *zipEntryReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	e.hash.Write(p[:n])
+	e.n += uint64(n)
+
+	if err == io.EOF && (e.n != e.f.UncompressedSize64 || e.hash.Sum32() != e.f.CRC32) {
+		err = awserr.New(ErrCodeInvalidArchive,
+			fmt.Sprintf("zip entry %s does not match its checksum", e.f.Name), nil)
+	}
+	return n, err
+}
+
+func (e *zipEntryReader) Close() error {
+	if c, ok := e.r.(io.Closer); ok && e.r != e.body {
+		c.Close()
+	}
+	return e.body.Close()
+}
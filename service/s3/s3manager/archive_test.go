@@ -0,0 +1,167 @@
+package s3manager_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+func newArchiveBucket() *syncBucket {
+	modTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &syncBucket{objects: map[string]syncObject{
+		"logs/a.txt":     {data: []byte("aaa"), modTime: modTime},
+		"logs/b/c.txt":   {data: []byte(strings.Repeat("c", 2048)), modTime: modTime},
+		"logs/empty.txt": {data: []byte{}, modTime: modTime},
+		"logs/dir/":      {data: []byte{}, modTime: modTime},
+		"other/d.txt":    {data: []byte("ddd"), modTime: modTime},
+	}}
+}
+
+func TestArchiverPackUnpackTar(t *testing.T) {
+	b := newArchiveBucket()
+	archiver := s3manager.NewArchiverWithClient(b.client())
+
+	out, err := archiver.Pack(&s3manager.PackInput{
+		Bucket:     aws.String("bucket"),
+		Prefix:     aws.String("logs/"),
+		ArchiveKey: aws.String("archive.tar"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := int64(3), out.Count; e != a {
+		t.Errorf("expect %v objects packed, got %v", e, a)
+	}
+	if e, a := int64(2051), out.Size; e != a {
+		t.Errorf("expect %v bytes packed, got %v", e, a)
+	}
+
+	tr := tar.NewReader(bytes.NewReader(b.objects["archive.tar"].data))
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, header.Name)
+	}
+	if e, a := "a.txt,b/c.txt,empty.txt", strings.Join(names, ","); e != a {
+		t.Errorf("expect %v entries, got %v", e, a)
+	}
+
+	unpacked, err := archiver.Unpack(&s3manager.UnpackInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("archive.tar"),
+		Prefix: aws.String("restored"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := int64(3), unpacked.Count; e != a {
+		t.Errorf("expect %v entries unpacked, got %v", e, a)
+	}
+	for _, name := range []string{"a.txt", "b/c.txt", "empty.txt"} {
+		if e, a := string(b.objects["logs/"+name].data), string(b.objects["restored/"+name].data); e != a {
+			t.Errorf("%s, expect %v data, got %v", name, e, a)
+		}
+	}
+}
+
+func TestArchiverPackUnpackZip(t *testing.T) {
+	b := newArchiveBucket()
+	archiver := s3manager.NewArchiverWithClient(b.client())
+
+	_, err := archiver.Pack(&s3manager.PackInput{
+		Bucket:     aws.String("bucket"),
+		Keys:       []string{"other/d.txt", "logs/b/c.txt"},
+		ArchiveKey: aws.String("archive.zip"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	data := b.objects["archive.zip"].data
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 2, len(zr.File); e != a {
+		t.Fatalf("expect %v entries, got %v", e, a)
+	}
+	if e, a := "other/d.txt", zr.File[0].Name; e != a {
+		t.Errorf("expect %v entry, got %v", e, a)
+	}
+	b.takeOps()
+
+	unpacked, err := archiver.Unpack(&s3manager.UnpackInput{
+		Bucket:            aws.String("bucket"),
+		Key:               aws.String("archive.zip"),
+		DestinationBucket: aws.String("restored"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := int64(2), unpacked.Count; e != a {
+		t.Errorf("expect %v entries unpacked, got %v", e, a)
+	}
+
+	// The directory is read with a single range, and each entry with one.
+	expect := []string{
+		"get archive.zip", "get archive.zip", "get archive.zip",
+		"head archive.zip",
+		"put logs/b/c.txt", "put other/d.txt",
+	}
+	if e, a := strings.Join(expect, ","), strings.Join(b.takeOps(), ","); e != a {
+		t.Errorf("expect %v operations, got %v", e, a)
+	}
+}
+
+func TestArchiverUnpackZipChecksum(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.CreateHeader(&zip.FileHeader{Name: "a.txt", Method: zip.Store})
+	w.Write([]byte("abc"))
+	zw.Close()
+	data := bytes.Replace(buf.Bytes(), []byte("abc"), []byte("abd"), 1)
+
+	b := &syncBucket{objects: map[string]syncObject{"archive.zip": {data: data}}}
+	_, err := s3manager.NewArchiverWithClient(b.client()).Unpack(&s3manager.UnpackInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("archive.zip"),
+	})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if _, ok := b.objects["a.txt"]; ok {
+		t.Errorf("expect corrupt entry not to be uploaded")
+	}
+	errs := err.(*s3manager.BatchError).Errors
+	if e, a := 1, len(errs); e != a {
+		t.Fatalf("expect %v errors, got %v", e, a)
+	}
+	if e, a := "a.txt", aws.StringValue(errs[0].Key); e != a {
+		t.Errorf("expect %v key, got %v", e, a)
+	}
+}
+
+func TestArchiverInvalidFormat(t *testing.T) {
+	b := newArchiveBucket()
+	_, err := s3manager.NewArchiverWithClient(b.client()).Pack(&s3manager.PackInput{
+		Bucket:     aws.String("bucket"),
+		ArchiveKey: aws.String("archive.rar"),
+		Format:     "rar",
+	})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := s3manager.ErrCodeInvalidArchiveFormat, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
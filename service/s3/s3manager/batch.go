@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -18,8 +19,41 @@ const (
 	// This value is used when calling DeleteObjects. This represents how many objects to delete
 	// per DeleteObjects call.
 	DefaultBatchSize = 100
+
+	// MaxBatchSize is the maximum number of objects a DeleteObjects call can
+	// delete. Larger batch sizes are lowered to this value.
+	MaxBatchSize = 1000
+
+	// DefaultBatchDeleteRetries is the number of times the batch delete client
+	// retries deleting the objects which failed to be deleted with a
+	// retryable error.
+	DefaultBatchDeleteRetries = 3
 )
 
+// batchRetryDelay is the delay before the first retry of the objects of a
+// batch which failed to be processed, doubled for each following retry.
+var batchRetryDelay = 100 * time.Millisecond
+
+// retryableBatchErrorCodes are the error codes of the objects of a
+// DeleteObjects call which are retried.
+var retryableBatchErrorCodes = map[string]struct{}{
+	"InternalError":      {},
+	"RequestTimeout":     {},
+	"ServiceUnavailable": {},
+	"SlowDown":           {},
+}
+
+// BatchProgress is the progress of a batch operation, reported to the
+// OnProgress callback of the BatchDelete and BatchCopy clients as objects are
+// processed.
+type BatchProgress struct {
+	// The number of objects processed successfully so far.
+	Succeeded int64
+
+	// The number of objects which failed to be processed so far.
+	Failed int64
+}
+
 // BatchError will contain the key and bucket of the object that failed to
 // either upload or download.
 type BatchError struct {
@@ -60,7 +94,8 @@ func newError(err error, bucket, key *string) Error {
 }
 
 func (err *Error) Error() string {
-	return fmt.Sprintf("failed to upload %q to %q:\n%s", err.Key, err.Bucket, err.OrigErr.Error())
+	return fmt.Sprintf("failed to process %q in %q:\n%s",
+		aws.StringValue(err.Key), aws.StringValue(err.Bucket), err.OrigErr.Error())
 }
 
 // NewBatchError will return a BatchError that satisfies the awserr.Error interface.
@@ -189,6 +224,19 @@ func (iter *DeleteListIterator) DeleteObject() BatchDeleteObject {
 type BatchDelete struct {
 	Client    s3iface.S3API
 	BatchSize int
+
+	// The number of times the objects of a batch which failed to be deleted
+	// with a retryable error, such as SlowDown or InternalError, are retried
+	// in a new DeleteObjects call.
+	PartialFailureRetries int
+
+	// List of request options that will be passed down to the DeleteObjects
+	// requests made by the batch delete client.
+	RequestOptions []request.Option
+
+	// OnProgress is called with the progress of the batch delete after each
+	// DeleteObjects call, if set.
+	OnProgress func(BatchProgress)
 }
 
 // NewBatchDeleteWithClient will return a new delete client that can delete a batched amount of
@@ -213,8 +261,9 @@ type BatchDelete struct {
 //	}
 func NewBatchDeleteWithClient(client s3iface.S3API, options ...func(*BatchDelete)) *BatchDelete {
 	svc := &BatchDelete{
-		Client:    client,
-		BatchSize: DefaultBatchSize,
+		Client:                client,
+		BatchSize:             DefaultBatchSize,
+		PartialFailureRetries: DefaultBatchDeleteRetries,
 	}
 
 	for _, opt := range options {
@@ -290,8 +339,17 @@ func (iter *DeleteObjectsIterator) DeleteObject() BatchDeleteObject {
 
 // Delete will use the iterator to queue up objects that need to be deleted.
 // Once the batch size is met, this will call the deleteBatch function.
+//
+// The batch size is lowered to MaxBatchSize if larger. The objects which
+// failed to be deleted are returned as a BatchError.
 func (d *BatchDelete) Delete(ctx aws.Context, iter BatchDeleteIterator) error {
 	var errs []Error
+	var progress BatchProgress
+
+	batchSize := d.BatchSize
+	if batchSize <= 0 || batchSize > MaxBatchSize {
+		batchSize = MaxBatchSize
+	}
 	objects := []BatchDeleteObject{}
 	var input *s3.DeleteObjectsInput
 
@@ -311,8 +369,8 @@ func (d *BatchDelete) Delete(ctx aws.Context, iter BatchDeleteIterator) error {
 			objects = append(objects, o)
 		}
 
-		if len(input.Delete.Objects) == d.BatchSize || !parity {
-			if err := deleteBatch(d, input, objects); err != nil {
+		if len(input.Delete.Objects) == batchSize || !parity {
+			if err := deleteBatch(ctx, d, input, objects, &progress); err != nil {
 				errs = append(errs, err...)
 			}
 
@@ -331,7 +389,7 @@ func (d *BatchDelete) Delete(ctx aws.Context, iter BatchDeleteIterator) error {
 	}
 
 	if input != nil && len(input.Delete.Objects) > 0 {
-		if err := deleteBatch(d, input, objects); err != nil {
+		if err := deleteBatch(ctx, d, input, objects, &progress); err != nil {
 			errs = append(errs, err...)
 		}
 	}
@@ -351,19 +409,52 @@ func initDeleteObjectsInput(o *s3.DeleteObjectInput) *s3.DeleteObjectsInput {
 	}
 }
 
-// deleteBatch will delete a batch of items in the objects parameters.
-func deleteBatch(d *BatchDelete, input *s3.DeleteObjectsInput, objects []BatchDeleteObject) []Error {
+// deleteBatch will delete a batch of items in the objects parameters. The
+// objects which failed to be deleted with a retryable error are retried up
+// to the client's PartialFailureRetries times.
+func deleteBatch(ctx aws.Context, d *BatchDelete, input *s3.DeleteObjectsInput, objects []BatchDeleteObject, progress *BatchProgress) []Error {
 	errs := []Error{}
 
-	if result, err := d.Client.DeleteObjects(input); err != nil {
-		for i := 0; i < len(input.Delete.Objects); i++ {
-			errs = append(errs, newError(err, input.Bucket, input.Delete.Objects[i].Key))
+	pending := input.Delete.Objects
+	for retry := 0; len(pending) > 0; retry++ {
+		if retry > 0 {
+			delay := batchRetryDelay * time.Duration(1<<uint(retry-1))
+			if err := aws.SleepWithContext(ctx, delay); err != nil {
+				for _, o := range pending {
+					errs = append(errs, newError(err, input.Bucket, o.Key))
+				}
+				break
+			}
+		}
+
+		params := *input
+		params.Delete = &s3.Delete{Objects: pending, Quiet: input.Delete.Quiet}
+		result, err := d.Client.DeleteObjectsWithContext(ctx, &params, d.RequestOptions...)
+		if err != nil {
+			for _, o := range pending {
+				errs = append(errs, newError(err, input.Bucket, o.Key))
+			}
+			break
 		}
-	} else if len(result.Errors) > 0 {
-		for i := 0; i < len(result.Errors); i++ {
-			errs = append(errs, newError(err, input.Bucket, result.Errors[i].Key))
+
+		pending = nil
+		for _, e := range result.Errors {
+			if _, ok := retryableBatchErrorCodes[aws.StringValue(e.Code)]; ok && retry < d.PartialFailureRetries {
+				pending = append(pending, &s3.ObjectIdentifier{Key: e.Key, VersionId: e.VersionId})
+				continue
+			}
+			errs = append(errs, newError(
+				awserr.New(aws.StringValue(e.Code), aws.StringValue(e.Message), nil),
+				input.Bucket, e.Key))
 		}
 	}
+
+	progress.Failed += int64(len(errs))
+	progress.Succeeded += int64(len(input.Delete.Objects) - len(errs))
+	if d.OnProgress != nil {
+		d.OnProgress(*progress)
+	}
+
 	for _, object := range objects {
 		if object.After == nil {
 			continue
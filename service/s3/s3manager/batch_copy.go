@@ -0,0 +1,184 @@
+package s3manager
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// DefaultBatchCopyConcurrency is the default number of goroutines to spin up
+// when copying objects with the batch copy client.
+const DefaultBatchCopyConcurrency = 5
+
+// BatchCopyIterator is an interface that uses the scanner pattern to iterate
+// through what needs to be copied.
+type BatchCopyIterator interface {
+	Next() bool
+	Err() error
+	CopyObject() BatchCopyObject
+}
+
+// BatchCopyObject contains all necessary information to run a batch copy
+// operation once.
+type BatchCopyObject struct {
+	Object *s3.CopyObjectInput
+	// After will run after each iteration during the batch process. This function will
+	// be executed whether or not the request was successful.
+	After func() error
+}
+
+// CopyObjectsIterator implements the BatchCopyIterator interface and allows
+// for batched copy of objects.
+type CopyObjectsIterator struct {
+	Objects []BatchCopyObject
+	index   int
+	inc     bool
+}
+
+// Next will increment the default iterator's index and and ensure that there
+// is another object to iterator to.
+func (iter *CopyObjectsIterator) Next() bool {
+	if iter.inc {
+		iter.index++
+	} else {
+		iter.inc = true
+	}
+	return iter.index < len(iter.Objects)
+}
+
+// Err will return an error. Since this is just used to satisfy the BatchCopyIterator interface
+// this will only return nil.
+func (iter *CopyObjectsIterator) Err() error {
+	return nil
+}
+
+// CopyObject will return the BatchCopyObject at the current batched index.
+func (iter *CopyObjectsIterator) CopyObject() BatchCopyObject {
+	return iter.Objects[iter.index]
+}
+
+// BatchCopy will use the s3 package's service client to copy objects
+// concurrently with CopyObject calls.
+type BatchCopy struct {
+	Client s3iface.S3API
+
+	// The number of objects to copy in parallel. If this is set to zero, the
+	// DefaultBatchCopyConcurrency value will be used.
+	Concurrency int
+
+	// List of request options that will be passed down to the CopyObject
+	// requests made by the batch copy client.
+	RequestOptions []request.Option
+
+	// OnProgress is called with the progress of the batch copy after each
+	// object is copied, or fails to be, if set.
+	OnProgress func(BatchProgress)
+}
+
+// NewBatchCopyWithClient will return a new copy client that can copy a
+// batched amount of objects.
+//
+// Example:
+//	batcher := s3manager.NewBatchCopyWithClient(client)
+//
+//	objects := []BatchCopyObject{
+//		{
+//			Object:	&s3.CopyObjectInput {
+//				Bucket: aws.String("bucket"),
+//				Key: aws.String("copy"),
+//				CopySource: aws.String("bucket/key"),
+//			},
+//		},
+//	}
+//
+//	if err := batcher.Copy(aws.BackgroundContext(), &s3manager.CopyObjectsIterator{
+//		Objects: objects,
+//	}); err != nil {
+//		return err
+//	}
+func NewBatchCopyWithClient(client s3iface.S3API, options ...func(*BatchCopy)) *BatchCopy {
+	svc := &BatchCopy{
+		Client:      client,
+		Concurrency: DefaultBatchCopyConcurrency,
+	}
+
+	for _, opt := range options {
+		opt(svc)
+	}
+
+	return svc
+}
+
+// NewBatchCopy will return a new copy client that can copy a batched amount
+// of objects.
+func NewBatchCopy(c client.ConfigProvider, options ...func(*BatchCopy)) *BatchCopy {
+	client := s3.New(c)
+	return NewBatchCopyWithClient(client, options...)
+}
+
+// Copy will use the iterator to queue up objects that need to be copied, and
+// copy them concurrently. The objects which failed to be copied, and the
+// error of the iterator if any, are returned as a BatchError.
+func (c *BatchCopy) Copy(ctx aws.Context, iter BatchCopyIterator) error {
+	concurrency := c.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchCopyConcurrency
+	}
+
+	var (
+		wg       sync.WaitGroup
+		m        sync.Mutex
+		errs     []Error
+		progress BatchProgress
+		ch       = make(chan BatchCopyObject)
+	)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for o := range ch {
+				var objErrs []Error
+				if _, err := c.Client.CopyObjectWithContext(ctx, o.Object, c.RequestOptions...); err != nil {
+					objErrs = append(objErrs, newError(err, o.Object.Bucket, o.Object.Key))
+				}
+				if o.After != nil {
+					if err := o.After(); err != nil {
+						objErrs = append(objErrs, newError(err, o.Object.Bucket, o.Object.Key))
+					}
+				}
+
+				m.Lock()
+				errs = append(errs, objErrs...)
+				if len(objErrs) != 0 {
+					progress.Failed++
+				} else {
+					progress.Succeeded++
+				}
+				if c.OnProgress != nil {
+					c.OnProgress(progress)
+				}
+				m.Unlock()
+			}
+		}()
+	}
+
+	for iter.Next() {
+		ch <- iter.CopyObject()
+	}
+	close(ch)
+	wg.Wait()
+
+	if err := iter.Err(); err != nil {
+		errs = append(errs, newError(err, nil, nil))
+	}
+
+	if len(errs) > 0 {
+		return NewBatchError("BatchedCopyIncomplete", "some objects have failed to be copied.", errs)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package s3manager
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// DefaultBatchHeadConcurrency is the default number of goroutines to spin up
+// when heading objects with the batch head client.
+const DefaultBatchHeadConcurrency = 10
+
+// BatchHeadResult is the result of heading an object with the batch head
+// client.
+type BatchHeadResult struct {
+	// The bucket and key of the object.
+	Bucket *string
+	Key    *string
+
+	// Whether the object exists. Exists is also false for objects which
+	// failed to be headed, such as when access is denied, which are returned
+	// in the BatchError.
+	Exists bool
+
+	// The output of the object's HeadObject request, nil if the object does
+	// not exist.
+	Output *s3.HeadObjectOutput
+}
+
+// BatchHead will use the s3 package's service client to check the existence,
+// and retrieve the metadata, of objects concurrently with HeadObject calls.
+//
+// The HeadObject requests are made with the client's configuration, sharing
+// its HTTP client's connections and its RateLimiter, if set, with the other
+// requests of the client. The Concurrency should not exceed the
+// MaxIdleConnsPerHost of the HTTP client's transport, for connections to be
+// reused instead of closed once idle.
+type BatchHead struct {
+	Client s3iface.S3API
+
+	// The number of objects to head in parallel. If this is set to zero, the
+	// DefaultBatchHeadConcurrency value will be used.
+	Concurrency int
+
+	// List of request options that will be passed down to the HeadObject
+	// requests made by the batch head client.
+	RequestOptions []request.Option
+
+	// OnProgress is called with the progress of the batch head after each
+	// object is headed, or fails to be, if set. Objects which do not exist
+	// are headed successfully.
+	OnProgress func(BatchProgress)
+}
+
+// NewBatchHeadWithClient will return a new head client that can head a
+// batched amount of objects.
+//
+// Example:
+//	batcher := s3manager.NewBatchHeadWithClient(client)
+//
+//	exists, err := batcher.Exists(aws.BackgroundContext(), "bucket", keys)
+//	if err != nil {
+//		return err
+//	}
+//	for _, key := range keys {
+//		fmt.Println(key, exists[key])
+//	}
+func NewBatchHeadWithClient(client s3iface.S3API, options ...func(*BatchHead)) *BatchHead {
+	svc := &BatchHead{
+		Client:      client,
+		Concurrency: DefaultBatchHeadConcurrency,
+	}
+
+	for _, opt := range options {
+		opt(svc)
+	}
+
+	return svc
+}
+
+// NewBatchHead will return a new head client that can head a batched amount
+// of objects.
+func NewBatchHead(c client.ConfigProvider, options ...func(*BatchHead)) *BatchHead {
+	client := s3.New(c)
+	return NewBatchHeadWithClient(client, options...)
+}
+
+// Head heads the objects of the inputs concurrently, returning the result of
+// each object in the order of the inputs. Objects which do not exist are not
+// an error, their result is returned with Exists false. The objects which
+// failed to be headed are returned as a BatchError, along with the results of
+// all objects.
+func (h *BatchHead) Head(ctx aws.Context, inputs []*s3.HeadObjectInput) ([]BatchHeadResult, error) {
+	concurrency := h.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchHeadConcurrency
+	}
+
+	var (
+		wg       sync.WaitGroup
+		m        sync.Mutex
+		errs     []Error
+		progress BatchProgress
+		results  = make([]BatchHeadResult, len(inputs))
+		ch       = make(chan int)
+	)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range ch {
+				in := inputs[idx]
+				results[idx] = BatchHeadResult{Bucket: in.Bucket, Key: in.Key}
+
+				out, err := h.Client.HeadObjectWithContext(ctx, in, h.RequestOptions...)
+				if err != nil && isNotFound(err) {
+					err = nil
+				} else if err == nil {
+					results[idx].Exists = true
+					results[idx].Output = out
+				}
+
+				m.Lock()
+				if err != nil {
+					errs = append(errs, newError(err, in.Bucket, in.Key))
+					progress.Failed++
+				} else {
+					progress.Succeeded++
+				}
+				if h.OnProgress != nil {
+					h.OnProgress(progress)
+				}
+				m.Unlock()
+			}
+		}()
+	}
+
+	for i := range inputs {
+		ch <- i
+	}
+	close(ch)
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return results, NewBatchError("BatchedHeadIncomplete", "some objects have failed to be headed.", errs)
+	}
+	return results, nil
+}
+
+// Exists checks the existence of the objects of the keys in the bucket
+// concurrently, returning whether the object of each key exists. The objects
+// which failed to be checked are returned as a BatchError, along with the
+// existence of all objects.
+func (h *BatchHead) Exists(ctx aws.Context, bucket string, keys []string) (map[string]bool, error) {
+	inputs := make([]*s3.HeadObjectInput, len(keys))
+	for i, key := range keys {
+		inputs[i] = &s3.HeadObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		}
+	}
+
+	results, err := h.Head(ctx, inputs)
+
+	exists := make(map[string]bool, len(results))
+	for _, r := range results {
+		exists[aws.StringValue(r.Key)] = r.Exists
+	}
+	return exists, err
+}
+
+// isNotFound returns whether the error is the error of a HeadObject request
+// of an object which does not exist.
+func isNotFound(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchBucket {
+		return false
+	}
+	if rerr, ok := err.(awserr.RequestFailure); ok && rerr.StatusCode() == http.StatusNotFound {
+		return true
+	}
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case "NotFound", s3.ErrCodeNoSuchKey:
+			return true
+		}
+	}
+	return false
+}
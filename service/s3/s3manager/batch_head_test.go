@@ -0,0 +1,105 @@
+package s3manager_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// batchHeadSvc returns a client heading objects of the keys "exists*", and
+// failing to head the object of failKey.
+func batchHeadSvc(failKey string) *s3.S3 {
+	svc := s3.New(unit.Session, &aws.Config{MaxRetries: aws.Int(0)})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Unmarshal.Clear()
+	svc.Handlers.UnmarshalMeta.Clear()
+	svc.Handlers.UnmarshalError.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte{})),
+		}
+
+		key := aws.StringValue(r.Params.(*s3.HeadObjectInput).Key)
+		switch {
+		case key == failKey:
+			r.Error = awserr.NewRequestFailure(awserr.New("Forbidden", "forbidden", nil), 403, "")
+		case len(key) >= 6 && key[:6] == "exists":
+			r.Data.(*s3.HeadObjectOutput).ContentLength = aws.Int64(int64(len(key)))
+		default:
+			r.Error = awserr.NewRequestFailure(awserr.New("NotFound", "not found", nil), 404, "")
+		}
+	})
+
+	return svc
+}
+
+func TestBatchHeadExists(t *testing.T) {
+	var m sync.Mutex
+	var progress s3manager.BatchProgress
+	batcher := s3manager.NewBatchHeadWithClient(batchHeadSvc(""), func(h *s3manager.BatchHead) {
+		h.OnProgress = func(p s3manager.BatchProgress) {
+			m.Lock()
+			progress = p
+			m.Unlock()
+		}
+	})
+
+	keys := []string{"exists1", "missing1", "exists2", "missing2"}
+	exists, err := batcher.Exists(aws.BackgroundContext(), "bucket", keys)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	for _, key := range keys {
+		if e, a := key[:6] == "exists", exists[key]; e != a {
+			t.Errorf("%s, expect %v exists, got %v", key, e, a)
+		}
+	}
+	if e, a := int64(len(keys)), progress.Succeeded; e != a {
+		t.Errorf("expect %v succeeded, got %v", e, a)
+	}
+}
+
+func TestBatchHeadHead(t *testing.T) {
+	batcher := s3manager.NewBatchHeadWithClient(batchHeadSvc("denied"))
+
+	results, err := batcher.Head(aws.BackgroundContext(), []*s3.HeadObjectInput{
+		{Bucket: aws.String("bucket"), Key: aws.String("exists1")},
+		{Bucket: aws.String("bucket"), Key: aws.String("denied")},
+		{Bucket: aws.String("bucket"), Key: aws.String("missing")},
+	})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	berr := err.(*s3manager.BatchError)
+	if e, a := 1, len(berr.Errors); e != a {
+		t.Fatalf("expect %v errors, got %v", e, a)
+	}
+	if e, a := "denied", aws.StringValue(berr.Errors[0].Key); e != a {
+		t.Errorf("expect %v error key, got %v", e, a)
+	}
+
+	if e, a := 3, len(results); e != a {
+		t.Fatalf("expect %v results, got %v", e, a)
+	}
+	if !results[0].Exists {
+		t.Errorf("expect object to exist")
+	}
+	if e, a := int64(7), aws.Int64Value(results[0].Output.ContentLength); e != a {
+		t.Errorf("expect %v content length, got %v", e, a)
+	}
+	for _, r := range results[1:] {
+		if r.Exists || r.Output != nil {
+			t.Errorf("%s, expect object to not exist", aws.StringValue(r.Key))
+		}
+	}
+}
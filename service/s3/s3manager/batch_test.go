@@ -6,10 +6,16 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/awstesting/unit"
@@ -973,3 +979,171 @@ func TestAfter(t *testing.T) {
 		t.Error("Expected 'afterUpload' to be true, but received false")
 	}
 }
+
+func batchSvc(fn func(r *request.Request)) *s3.S3 {
+	svc := s3.New(unit.Session, &aws.Config{MaxRetries: aws.Int(0)})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Unmarshal.Clear()
+	svc.Handlers.UnmarshalMeta.Clear()
+	svc.Handlers.UnmarshalError.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte{})),
+		}
+		fn(r)
+	})
+	return svc
+}
+
+func TestBatchDeleteMaxBatchSize(t *testing.T) {
+	var sizes []int
+	svc := batchSvc(func(r *request.Request) {
+		sizes = append(sizes, len(r.Params.(*s3.DeleteObjectsInput).Delete.Objects))
+	})
+
+	objects := []BatchDeleteObject{}
+	for i := 0; i < 2500; i++ {
+		objects = append(objects, BatchDeleteObject{Object: &s3.DeleteObjectInput{
+			Bucket: aws.String("bucket"),
+			Key:    aws.String(strconv.Itoa(i)),
+		}})
+	}
+
+	batcher := NewBatchDeleteWithClient(svc, func(d *BatchDelete) {
+		d.BatchSize = 5000
+	})
+	if err := batcher.Delete(aws.BackgroundContext(), &DeleteObjectsIterator{Objects: objects}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := []int{1000, 1000, 500}, sizes; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v batch sizes, got %v", e, a)
+	}
+}
+
+func TestBatchDeletePartialFailureRetry(t *testing.T) {
+	defer func(d time.Duration) { batchRetryDelay = d }(batchRetryDelay)
+	batchRetryDelay = 0
+
+	var requested [][]string
+	svc := batchSvc(func(r *request.Request) {
+		keys := []string{}
+		for _, o := range r.Params.(*s3.DeleteObjectsInput).Delete.Objects {
+			keys = append(keys, *o.Key)
+		}
+		requested = append(requested, keys)
+
+		out := r.Data.(*s3.DeleteObjectsOutput)
+		if len(requested) == 1 {
+			out.Errors = []*s3.Error{
+				{Key: aws.String("2"), Code: aws.String("SlowDown"), Message: aws.String("slow down")},
+				{Key: aws.String("3"), Code: aws.String("AccessDenied"), Message: aws.String("access denied")},
+			}
+		}
+	})
+
+	var progress []BatchProgress
+	batcher := NewBatchDeleteWithClient(svc, func(d *BatchDelete) {
+		d.OnProgress = func(p BatchProgress) {
+			progress = append(progress, p)
+		}
+	})
+
+	objects := []BatchDeleteObject{}
+	for _, key := range []string{"1", "2", "3"} {
+		objects = append(objects, BatchDeleteObject{Object: &s3.DeleteObjectInput{
+			Bucket: aws.String("bucket"),
+			Key:    aws.String(key),
+		}})
+	}
+	err := batcher.Delete(aws.BackgroundContext(), &DeleteObjectsIterator{Objects: objects})
+
+	batchErr, ok := err.(*BatchError)
+	if !ok {
+		t.Fatalf("expect *BatchError, got %v", err)
+	}
+	if e, a := 1, len(batchErr.Errors); e != a {
+		t.Fatalf("expect %v errors, got %v", e, a)
+	}
+	if e, a := "3", aws.StringValue(batchErr.Errors[0].Key); e != a {
+		t.Errorf("expect %v failed key, got %v", e, a)
+	}
+	if e, a := "AccessDenied", batchErr.Errors[0].OrigErr.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+	if e, a := "failed to process \"3\" in \"bucket\"", batchErr.Errors[0].Error(); !strings.HasPrefix(a, e) {
+		t.Errorf("expect %q error prefix, got %q", e, a)
+	}
+
+	if e, a := [][]string{{"1", "2", "3"}, {"2"}}, requested; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v requested keys, got %v", e, a)
+	}
+	if e, a := []BatchProgress{{Succeeded: 2, Failed: 1}}, progress; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v progress, got %v", e, a)
+	}
+}
+
+func TestBatchCopy(t *testing.T) {
+	var m sync.Mutex
+	copied := []string{}
+	svc := batchSvc(func(r *request.Request) {
+		in := r.Params.(*s3.CopyObjectInput)
+		if *in.Key == "fail" {
+			r.Error = awserr.New("AccessDenied", "access denied", nil)
+			return
+		}
+		m.Lock()
+		copied = append(copied, *in.CopySource+" "+*in.Key)
+		m.Unlock()
+	})
+
+	var last BatchProgress
+	batcher := NewBatchCopyWithClient(svc, func(c *BatchCopy) {
+		c.Concurrency = 2
+		c.OnProgress = func(p BatchProgress) {
+			last = p
+		}
+	})
+
+	after := 0
+	objects := []BatchCopyObject{}
+	for _, key := range []string{"a", "fail", "b"} {
+		objects = append(objects, BatchCopyObject{
+			Object: &s3.CopyObjectInput{
+				Bucket:     aws.String("dst"),
+				Key:        aws.String(key),
+				CopySource: aws.String("src/" + key),
+			},
+			After: func() error {
+				m.Lock()
+				after++
+				m.Unlock()
+				return nil
+			},
+		})
+	}
+	err := batcher.Copy(aws.BackgroundContext(), &CopyObjectsIterator{Objects: objects})
+
+	batchErr, ok := err.(*BatchError)
+	if !ok {
+		t.Fatalf("expect *BatchError, got %v", err)
+	}
+	if e, a := 1, len(batchErr.Errors); e != a {
+		t.Fatalf("expect %v errors, got %v", e, a)
+	}
+	if e, a := "fail", aws.StringValue(batchErr.Errors[0].Key); e != a {
+		t.Errorf("expect %v failed key, got %v", e, a)
+	}
+
+	sort.Strings(copied)
+	if e, a := []string{"src/a a", "src/b b"}, copied; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v copied, got %v", e, a)
+	}
+	if e, a := 3, after; e != a {
+		t.Errorf("expect %v After calls, got %v", e, a)
+	}
+	if e, a := (BatchProgress{Succeeded: 2, Failed: 1}), last; e != a {
+		t.Errorf("expect %v progress, got %v", e, a)
+	}
+}
@@ -0,0 +1,72 @@
+package s3manager
+
+import "sync"
+
+// UploadBufferProvider provides the buffers the Uploader reads parts of an
+// upload's body into. Buffers are only needed for bodies that cannot be read
+// concurrently. Bodies implementing io.ReaderAt and io.Seeker, or io.ReaderAt
+// with a Size method such as bytes.Reader, are uploaded without buffering.
+//
+// Implementations must be safe to use concurrently.
+type UploadBufferProvider interface {
+	// GetBuffer returns a buffer with a length of size bytes.
+	GetBuffer(size int) []byte
+
+	// PutBuffer releases a buffer previously returned by GetBuffer, once
+	// the part read into it has been uploaded. The buffer must not be used
+	// by the caller after it has been released.
+	PutBuffer(b []byte)
+}
+
+// PooledBufferProvider is an UploadBufferProvider which reuses released
+// buffers with a sync.Pool for each buffer size. Reusing buffers across parts
+// and uploads significantly reduces the allocations and garbage collection
+// of uploading many large objects.
+//
+// The zero value is ready for use, and is safe to use concurrently.
+type PooledBufferProvider struct {
+	m     sync.Mutex
+	pools map[int]*sync.Pool
+}
+
+// NewPooledBufferProvider returns an initialized PooledBufferProvider.
+func NewPooledBufferProvider() *PooledBufferProvider {
+	return &PooledBufferProvider{}
+}
+
+// GetBuffer returns a buffer with a length of size bytes, reusing a released
+// buffer of the same size if one is available.
+func (p *PooledBufferProvider) GetBuffer(size int) []byte {
+	if b, ok := p.pool(size).Get().(*[]byte); ok {
+		return *b
+	}
+
+	return make([]byte, size)
+}
+
+// PutBuffer releases the buffer so that it can be reused.
+func (p *PooledBufferProvider) PutBuffer(b []byte) {
+	b = b[:cap(b)]
+	p.pool(len(b)).Put(&b)
+}
+
+func (p *PooledBufferProvider) pool(size int) *sync.Pool {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if p.pools == nil {
+		p.pools = map[int]*sync.Pool{}
+	}
+
+	pool, ok := p.pools[size]
+	if !ok {
+		pool = &sync.Pool{}
+		p.pools[size] = pool
+	}
+
+	return pool
+}
+
+// defaultUploadBufferProvider is shared by all Uploaders without a
+// BufferProvider so buffers are reused across uploads.
+var defaultUploadBufferProvider = NewPooledBufferProvider()
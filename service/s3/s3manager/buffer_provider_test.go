@@ -0,0 +1,23 @@
+package s3manager
+
+import "testing"
+
+func TestPooledBufferProvider(t *testing.T) {
+	p := NewPooledBufferProvider()
+
+	b := p.GetBuffer(1024)
+	if e, a := 1024, len(b); e != a {
+		t.Fatalf("expect %v length, got %v", e, a)
+	}
+
+	p.PutBuffer(b[:10])
+
+	b = p.GetBuffer(1024)
+	if e, a := 1024, len(b); e != a {
+		t.Errorf("expect %v length, got %v", e, a)
+	}
+
+	if e, a := 2048, len(p.GetBuffer(2048)); e != a {
+		t.Errorf("expect %v length, got %v", e, a)
+	}
+}
@@ -0,0 +1,520 @@
+package s3manager
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// MaxCopyPartSize is the maximum allowed size of a part copied from an
+// existing object with UploadPartCopy.
+const MaxCopyPartSize = MaxUploadPartSize
+
+// DefaultComposeConcurrency is the default number of goroutines to spin up
+// when composing an object with the Composer.
+const DefaultComposeConcurrency = 5
+
+// The Composer builds an object from the concatenation of existing objects
+// with a multipart upload, copying the objects server-side with
+// UploadPartCopy. It is safe to call Compose on this structure for multiple
+// objects and across concurrent goroutines. Mutating the Composer's
+// properties is not safe to be done concurrently.
+type Composer struct {
+	// The number of goroutines to spin up in parallel when sending parts.
+	// If this is set to zero, the DefaultComposeConcurrency value will be
+	// used.
+	Concurrency int
+
+	// The maximum size of the parts copied from a source object. Larger
+	// sources are copied in multiple parts. If this is set to zero, or is
+	// larger than MaxCopyPartSize, the MaxCopyPartSize value will be used.
+	PartSize int64
+
+	// Setting this value to true will cause the SDK to avoid calling
+	// AbortMultipartUpload on a failure, leaving all successfully uploaded
+	// parts on S3 for manual recovery.
+	LeavePartsOnError bool
+
+	// An S3 client to use when composing objects.
+	S3 s3iface.S3API
+
+	// List of request options that will be passed down to individual API
+	// operation requests made by the composer.
+	RequestOptions []request.Option
+}
+
+// NewComposer creates a new Composer instance to compose objects with. Pass
+// in additional functional options to customize the composer behavior.
+//
+// Example:
+//     // The session the S3 Composer will use
+//     sess := session.Must(session.NewSession())
+//
+//     // Create a composer with the session and custom options
+//     composer := s3manager.NewComposer(sess, func(c *s3manager.Composer) {
+//          c.Concurrency = 10
+//     })
+func NewComposer(c client.ConfigProvider, options ...func(*Composer)) *Composer {
+	return NewComposerWithClient(s3.New(c), options...)
+}
+
+// NewComposerWithClient creates a new Composer instance to compose objects
+// with the S3 service client provided.
+func NewComposerWithClient(svc s3iface.S3API, options ...func(*Composer)) *Composer {
+	c := &Composer{
+		S3:          svc,
+		Concurrency: DefaultComposeConcurrency,
+		PartSize:    MaxCopyPartSize,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+// ComposeSource is an existing object to compose an object of.
+type ComposeSource struct {
+	// The bucket of the object.
+	Bucket *string
+
+	// The key of the object.
+	Key *string
+
+	// The version of the object, if not the latest.
+	VersionId *string
+}
+
+// ComposeInput provides the sources of the object to compose, and the
+// object's bucket, key, and attributes.
+type ComposeInput struct {
+	// The bucket of the composed object.
+	Bucket *string
+
+	// The key of the composed object.
+	Key *string
+
+	// The objects to concatenate, in order.
+	Sources []ComposeSource
+
+	// The canned ACL to apply to the composed object.
+	ACL *string
+
+	// A standard MIME type describing the format of the composed object.
+	ContentType *string
+
+	// A map of metadata to store with the composed object.
+	Metadata map[string]*string
+}
+
+// ComposeOutput represents a response from the Compose() call.
+type ComposeOutput struct {
+	// The URL where the composed object was uploaded to.
+	Location string
+
+	// The ETag of the composed object.
+	ETag *string
+
+	// The version of the composed object that was uploaded. Will only be
+	// populated if the S3 Bucket is versioned.
+	VersionID *string
+
+	// The ID of the multipart upload the object was composed with.
+	UploadID string
+}
+
+// composeSegment is a byte range of a source object.
+type composeSegment struct {
+	source     ComposeSource
+	etag       *string
+	start, end int64
+}
+
+// composePart is a part of the composed object, copied from a single
+// segment, or uploaded from the segments' data if the segments are too small
+// to be copied as a part of their own.
+type composePart struct {
+	num      int64
+	copy     bool
+	segments []composeSegment
+}
+
+// Compose creates the object from the concatenation of the input's source
+// objects, in order, with a multipart upload. Sources of at least
+// MinUploadPartSize bytes are copied server-side with UploadPartCopy. Since
+// all parts but the last must be at least MinUploadPartSize, smaller sources
+// are downloaded and uploaded together with the start of the following
+// source instead.
+//
+// The sources are copied on the condition that their ETag has not changed
+// since the composer read their size. If composing fails, the multipart
+// upload is aborted, unless the composer's LeavePartsOnError is set.
+//
+// Additional functional options can be provided to configure the individual
+// call. These options are copies of the Composer instance Compose is called
+// from. Modifying the options will not impact the original Composer
+// instance.
+//
+// It is safe to call this method concurrently across goroutines.
+//
+// Example:
+//     result, err := composer.Compose(&s3manager.ComposeInput{
+//         Bucket: aws.String("bucket"),
+//         Key:    aws.String("logs/2017-10.log"),
+//         Sources: []s3manager.ComposeSource{
+//             {Bucket: aws.String("bucket"), Key: aws.String("logs/2017-10-01.log")},
+//             {Bucket: aws.String("bucket"), Key: aws.String("logs/2017-10-02.log")},
+//         },
+//     })
+func (c Composer) Compose(input *ComposeInput, options ...func(*Composer)) (*ComposeOutput, error) {
+	return c.ComposeWithContext(aws.BackgroundContext(), input, options...)
+}
+
+// ComposeWithContext creates the object from the concatenation of source
+// objects the same as Compose, with the addition of the ability to pass a
+// context.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (c Composer) ComposeWithContext(ctx aws.Context, input *ComposeInput, options ...func(*Composer)) (*ComposeOutput, error) {
+	for _, option := range options {
+		option(&c)
+	}
+	c.RequestOptions = append(c.RequestOptions, request.WithAppendUserAgent("S3Manager"))
+
+	if c.Concurrency <= 0 {
+		c.Concurrency = DefaultComposeConcurrency
+	}
+	if c.PartSize <= 0 || c.PartSize > MaxCopyPartSize {
+		c.PartSize = MaxCopyPartSize
+	}
+	if c.PartSize < 2*MinUploadPartSize {
+		c.PartSize = 2 * MinUploadPartSize
+	}
+
+	if len(input.Sources) == 0 {
+		return nil, awserr.New("InvalidParameter", "at least one compose source is required", nil)
+	}
+
+	segments, err := c.headSources(ctx, input.Sources)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := c.planParts(segments)
+	if len(parts) > MaxUploadParts {
+		msg := fmt.Sprintf("exceeded total allowed S3 limit MaxUploadParts (%d). Adjust PartSize to fit in this limit",
+			MaxUploadParts)
+		return nil, awserr.New("TotalPartsExceeded", msg, nil)
+	}
+
+	resp, err := c.S3.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      input.Bucket,
+		Key:         input.Key,
+		ACL:         input.ACL,
+		ContentType: input.ContentType,
+		Metadata:    input.Metadata,
+	}, c.RequestOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &multicomposer{Composer: c, ctx: ctx, in: input, uploadID: aws.StringValue(resp.UploadId)}
+	return m.compose(parts)
+}
+
+// headSources returns a segment of the entire object for each of the
+// sources, skipping empty objects.
+func (c Composer) headSources(ctx aws.Context, sources []ComposeSource) ([]composeSegment, error) {
+	var segments []composeSegment
+	for _, source := range sources {
+		head, err := c.S3.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+			Bucket:    source.Bucket,
+			Key:       source.Key,
+			VersionId: source.VersionId,
+		}, c.RequestOptions...)
+		if err != nil {
+			return nil, err
+		}
+
+		if size := aws.Int64Value(head.ContentLength); size > 0 {
+			segments = append(segments, composeSegment{source: source, etag: head.ETag, end: size})
+		}
+	}
+	return segments, nil
+}
+
+// planParts splits the sources' segments into the parts of the composed
+// object. Segments of at least MinUploadPartSize are copied, in parts of at
+// most the composer's PartSize. Smaller segments, which are not at the end of
+// the object, are buffered into a part with the data following them, until
+// the part reaches MinUploadPartSize.
+func (c Composer) planParts(segments []composeSegment) []composePart {
+	// The number of bytes of the sources following each source.
+	rest := make([]int64, len(segments))
+	for i := len(segments) - 2; i >= 0; i-- {
+		rest[i] = rest[i+1] + segments[i+1].end
+	}
+
+	var parts []composePart
+	var pending []composeSegment
+	var pendingSize int64
+	addPart := func(p composePart) {
+		p.num = int64(len(parts) + 1)
+		parts = append(parts, p)
+	}
+
+	for i, seg := range segments {
+		for pos := int64(0); pos < seg.end; {
+			remaining := seg.end - pos
+
+			if pendingSize > 0 {
+				// Fill the buffered part up to the minimum part size.
+				n := MinUploadPartSize - pendingSize
+				if n > remaining {
+					n = remaining
+				}
+				pending = append(pending, composeSegment{source: seg.source, etag: seg.etag, start: pos, end: pos + n})
+				pendingSize += n
+				pos += n
+
+				if pendingSize >= MinUploadPartSize {
+					addPart(composePart{segments: pending})
+					pending, pendingSize = nil, 0
+				}
+				continue
+			}
+
+			if remaining < MinUploadPartSize && rest[i] > 0 {
+				// Too small to be copied as a part, other than the last.
+				pending = append(pending, composeSegment{source: seg.source, etag: seg.etag, start: pos, end: seg.end})
+				pendingSize += remaining
+				pos = seg.end
+				continue
+			}
+
+			n := remaining
+			if n > c.PartSize {
+				n = c.PartSize
+				if left := remaining - n; left < MinUploadPartSize {
+					// Leave enough for the following part to be copied.
+					n = remaining - MinUploadPartSize
+				}
+			}
+			addPart(composePart{copy: true, segments: []composeSegment{
+				{source: seg.source, etag: seg.etag, start: pos, end: pos + n},
+			}})
+			pos += n
+		}
+	}
+
+	if pendingSize > 0 || len(parts) == 0 {
+		addPart(composePart{segments: pending})
+	}
+
+	return parts
+}
+
+// multicomposer is the implementation structure used internally by Composer
+// to upload the parts of a composed object.
+type multicomposer struct {
+	Composer
+
+	ctx      aws.Context
+	in       *ComposeInput
+	uploadID string
+
+	wg    sync.WaitGroup
+	m     sync.Mutex
+	err   error
+	parts completedParts
+}
+
+// compose uploads the parts concurrently, and completes the upload.
+func (u *multicomposer) compose(parts []composePart) (*ComposeOutput, error) {
+	ch := make(chan composePart, u.Concurrency)
+	for i := 0; i < u.Concurrency; i++ {
+		u.wg.Add(1)
+		go func() {
+			defer u.wg.Done()
+			for part := range ch {
+				if u.geterr() != nil {
+					continue
+				}
+				if err := u.sendPart(part); err != nil {
+					u.seterr(err)
+				}
+			}
+		}()
+	}
+
+	for _, part := range parts {
+		if u.geterr() != nil {
+			break
+		}
+		ch <- part
+	}
+	close(ch)
+	u.wg.Wait()
+
+	var resp *s3.CompleteMultipartUploadOutput
+	if u.geterr() == nil {
+		// Parts must be sorted in PartNumber order.
+		sort.Sort(u.parts)
+
+		var err error
+		resp, err = u.S3.CompleteMultipartUploadWithContext(u.ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          u.in.Bucket,
+			Key:             u.in.Key,
+			UploadId:        &u.uploadID,
+			MultipartUpload: &s3.CompletedMultipartUpload{Parts: u.parts},
+		}, u.RequestOptions...)
+		if err != nil {
+			u.seterr(err)
+		}
+	}
+
+	if err := u.geterr(); err != nil {
+		u.fail()
+		return nil, &multiUploadError{
+			awsError: awserr.New("MultipartCompose", "compose multipart failed", err),
+			uploadID: u.uploadID,
+		}
+	}
+
+	return &ComposeOutput{
+		Location:  aws.StringValue(resp.Location),
+		ETag:      resp.ETag,
+		VersionID: resp.VersionId,
+		UploadID:  u.uploadID,
+	}, nil
+}
+
+// sendPart copies, or uploads, the part, and keeps track of the completed
+// part information.
+func (u *multicomposer) sendPart(part composePart) error {
+	var etag *string
+	if part.copy {
+		seg := part.segments[0]
+		resp, err := u.S3.UploadPartCopyWithContext(u.ctx, &s3.UploadPartCopyInput{
+			Bucket:            u.in.Bucket,
+			Key:               u.in.Key,
+			UploadId:          &u.uploadID,
+			PartNumber:        aws.Int64(part.num),
+			CopySource:        aws.String(copySource(seg.source)),
+			CopySourceIfMatch: seg.etag,
+			CopySourceRange:   aws.String(fmt.Sprintf("bytes=%d-%d", seg.start, seg.end-1)),
+		}, u.RequestOptions...)
+		if err != nil {
+			return err
+		}
+		if resp.CopyPartResult != nil {
+			etag = resp.CopyPartResult.ETag
+		}
+	} else {
+		body, err := u.readSegments(part.segments)
+		if err != nil {
+			return err
+		}
+		resp, err := u.S3.UploadPartWithContext(u.ctx, &s3.UploadPartInput{
+			Bucket:     u.in.Bucket,
+			Key:        u.in.Key,
+			UploadId:   &u.uploadID,
+			PartNumber: aws.Int64(part.num),
+			Body:       bytes.NewReader(body),
+		}, u.RequestOptions...)
+		if err != nil {
+			return err
+		}
+		etag = resp.ETag
+	}
+
+	u.m.Lock()
+	u.parts = append(u.parts, &s3.CompletedPart{ETag: etag, PartNumber: aws.Int64(part.num)})
+	u.m.Unlock()
+
+	return nil
+}
+
+// readSegments downloads the segments' data with ranged GetObject requests.
+func (u *multicomposer) readSegments(segments []composeSegment) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, seg := range segments {
+		resp, err := u.S3.GetObjectWithContext(u.ctx, &s3.GetObjectInput{
+			Bucket:    seg.source.Bucket,
+			Key:       seg.source.Key,
+			VersionId: seg.source.VersionId,
+			IfMatch:   seg.etag,
+			Range:     aws.String(fmt.Sprintf("bytes=%d-%d", seg.start, seg.end-1)),
+		}, u.RequestOptions...)
+		if err != nil {
+			return nil, err
+		}
+
+		n, err := io.Copy(&buf, resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, awserr.New("ReadRequestBody", "read compose source failed", err)
+		}
+		if n != seg.end-seg.start {
+			return nil, awserr.New("ReadRequestBody",
+				fmt.Sprintf("read %d bytes of compose source, expected %d", n, seg.end-seg.start), nil)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// geterr is a thread-safe getter for the error object
+func (u *multicomposer) geterr() error {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	return u.err
+}
+
+// seterr is a thread-safe setter for the error object
+func (u *multicomposer) seterr(e error) {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	u.err = e
+}
+
+// fail will abort the multipart unless LeavePartsOnError is set to true.
+func (u *multicomposer) fail() {
+	if u.LeavePartsOnError {
+		return
+	}
+
+	_, err := u.S3.AbortMultipartUploadWithContext(u.ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   u.in.Bucket,
+		Key:      u.in.Key,
+		UploadId: &u.uploadID,
+	}, u.RequestOptions...)
+	if err != nil {
+		logMessage(u.S3, aws.LogDebug, fmt.Sprintf("failed to abort multipart upload, %v", err))
+	}
+}
+
+// copySource returns the CopySource value of the source object, its bucket
+// and URL encoded key, and version if any.
+func copySource(source ComposeSource) string {
+	src := (&url.URL{Path: aws.StringValue(source.Bucket) + "/" + aws.StringValue(source.Key)}).EscapedPath()
+	if source.VersionId != nil {
+		src += "?versionId=" + url.QueryEscape(*source.VersionId)
+	}
+	return src
+}
@@ -0,0 +1,222 @@
+package s3manager
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const mb = 1024 * 1024
+
+func planString(parts []composePart) []string {
+	var plan []string
+	for _, p := range parts {
+		op := "upload"
+		if p.copy {
+			op = "copy"
+		}
+		for _, seg := range p.segments {
+			op += fmt.Sprintf(" %s[%d-%d]", aws.StringValue(seg.source.Key), seg.start/mb, seg.end/mb)
+		}
+		plan = append(plan, op)
+	}
+	return plan
+}
+
+func composeSegments(sizes ...int64) []composeSegment {
+	var segments []composeSegment
+	for i, size := range sizes {
+		segments = append(segments, composeSegment{
+			source: ComposeSource{Key: aws.String(strconv.Itoa(i))},
+			end:    size * mb,
+		})
+	}
+	return segments
+}
+
+func TestComposePlanParts(t *testing.T) {
+	cases := []struct {
+		sizes  []int64
+		expect []string
+	}{
+		{
+			sizes:  []int64{6, 2},
+			expect: []string{"copy 0[0-6]", "copy 1[0-2]"},
+		},
+		{
+			sizes:  []int64{1, 2},
+			expect: []string{"upload 0[0-1] 1[0-2]"},
+		},
+		{
+			sizes:  []int64{2, 8, 6},
+			expect: []string{"upload 0[0-2] 1[0-3]", "copy 1[3-8]", "copy 2[0-6]"},
+		},
+		{
+			sizes:  []int64{2, 6, 1},
+			expect: []string{"upload 0[0-2] 1[0-3]", "upload 1[3-6] 2[0-1]"},
+		},
+		{
+			sizes:  []int64{1, 1, 1, 1, 1, 1, 7},
+			expect: []string{"upload 0[0-1] 1[0-1] 2[0-1] 3[0-1] 4[0-1]", "upload 5[0-1] 6[0-4]", "copy 6[4-7]"},
+		},
+		{
+			// Sources larger than the part size are copied in parts, leaving
+			// enough for the last part to be copied.
+			sizes:  []int64{24},
+			expect: []string{"copy 0[0-10]", "copy 0[10-19]", "copy 0[19-24]"},
+		},
+	}
+
+	c := Composer{PartSize: 10 * mb}
+	for i, tc := range cases {
+		if e, a := tc.expect, planString(c.planParts(composeSegments(tc.sizes...))); !reflect.DeepEqual(e, a) {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+}
+
+// composeSvc serves the requests of the Composer from in-memory objects,
+// recording the operations made.
+func composeSvc(objects map[string][]byte, failPart int64) (*s3.S3, *[]string, *[]byte) {
+	var m sync.Mutex
+	var ops []string
+	parts := map[int64][]byte{}
+	var composed []byte
+
+	rng := regexp.MustCompile(`bytes=(\d+)-(\d+)`)
+	slice := func(key, r string) []byte {
+		match := rng.FindStringSubmatch(r)
+		start, _ := strconv.Atoi(match[1])
+		end, _ := strconv.Atoi(match[2])
+		return objects[key][start : end+1]
+	}
+
+	svc := batchSvc(func(r *request.Request) {
+		m.Lock()
+		defer m.Unlock()
+
+		ops = append(ops, r.Operation.Name)
+		switch in := r.Params.(type) {
+		case *s3.HeadObjectInput:
+			out := r.Data.(*s3.HeadObjectOutput)
+			out.ContentLength = aws.Int64(int64(len(objects[*in.Key])))
+			out.ETag = aws.String(`"` + *in.Key + `"`)
+		case *s3.GetObjectInput:
+			out := r.Data.(*s3.GetObjectOutput)
+			out.Body = ioutil.NopCloser(bytes.NewReader(slice(*in.Key, *in.Range)))
+		case *s3.CreateMultipartUploadInput:
+			r.Data.(*s3.CreateMultipartUploadOutput).UploadId = aws.String("UPLOAD-ID")
+		case *s3.UploadPartCopyInput:
+			if *in.PartNumber == failPart {
+				r.Error = awserr.New("AccessDenied", "access denied", nil)
+				return
+			}
+			key := (*in.CopySource)[len("bucket/"):]
+			parts[*in.PartNumber] = slice(key, *in.CopySourceRange)
+			r.Data.(*s3.UploadPartCopyOutput).CopyPartResult = &s3.CopyPartResult{
+				ETag: aws.String(fmt.Sprintf("ETAG%d", *in.PartNumber)),
+			}
+		case *s3.UploadPartInput:
+			b, _ := ioutil.ReadAll(in.Body)
+			parts[*in.PartNumber] = b
+			r.Data.(*s3.UploadPartOutput).ETag = aws.String(fmt.Sprintf("ETAG%d", *in.PartNumber))
+		case *s3.CompleteMultipartUploadInput:
+			for _, p := range in.MultipartUpload.Parts {
+				if e, a := fmt.Sprintf("ETAG%d", *p.PartNumber), *p.ETag; e != a {
+					r.Error = awserr.New("InvalidPart", "invalid part "+a, nil)
+					return
+				}
+				composed = append(composed, parts[*p.PartNumber]...)
+			}
+			r.Data.(*s3.CompleteMultipartUploadOutput).ETag = aws.String("COMPOSED")
+		}
+	})
+
+	return svc, &ops, &composed
+}
+
+func composeObjects(sizes ...int) map[string][]byte {
+	objects := map[string][]byte{}
+	for i, size := range sizes {
+		objects[strconv.Itoa(i)] = bytes.Repeat([]byte{byte('a' + i)}, size)
+	}
+	return objects
+}
+
+func composeInput(n int) *ComposeInput {
+	input := &ComposeInput{Bucket: aws.String("bucket"), Key: aws.String("composed")}
+	for i := 0; i < n; i++ {
+		input.Sources = append(input.Sources, ComposeSource{
+			Bucket: aws.String("bucket"),
+			Key:    aws.String(strconv.Itoa(i)),
+		})
+	}
+	return input
+}
+
+func TestCompose(t *testing.T) {
+	objects := composeObjects(2*mb, 7*mb, 0, 1*mb)
+	svc, _, composed := composeSvc(objects, 0)
+
+	out, err := NewComposerWithClient(svc).Compose(composeInput(4))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "COMPOSED", aws.StringValue(out.ETag); e != a {
+		t.Errorf("expect %v ETag, got %v", e, a)
+	}
+	if e, a := "UPLOAD-ID", out.UploadID; e != a {
+		t.Errorf("expect %v upload ID, got %v", e, a)
+	}
+
+	expect := append(append([]byte{}, objects["0"]...), objects["1"]...)
+	expect = append(expect, objects["3"]...)
+	if !bytes.Equal(expect, *composed) {
+		t.Errorf("expect composed object to be the concatenation of the sources")
+	}
+}
+
+func TestComposeFailure(t *testing.T) {
+	svc, ops, _ := composeSvc(composeObjects(6*mb, 6*mb), 2)
+
+	_, err := NewComposerWithClient(svc, func(c *Composer) {
+		c.Concurrency = 1
+	}).Compose(composeInput(2))
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := "UPLOAD-ID", err.(MultiUploadFailure).UploadID(); e != a {
+		t.Errorf("expect %v upload ID, got %v", e, a)
+	}
+
+	expect := []string{"HeadObject", "HeadObject", "CreateMultipartUpload", "UploadPartCopy", "UploadPartCopy", "AbortMultipartUpload"}
+	if e, a := expect, *ops; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v API calls, got %v", e, a)
+	}
+}
+
+func TestCopySource(t *testing.T) {
+	cases := []struct {
+		source ComposeSource
+		expect string
+	}{
+		{ComposeSource{Bucket: aws.String("bucket"), Key: aws.String("dir/a b+c")}, "bucket/dir/a%20b+c"},
+		{ComposeSource{Bucket: aws.String("bucket"), Key: aws.String("key"), VersionId: aws.String("v1")}, "bucket/key?versionId=v1"},
+	}
+
+	for i, c := range cases {
+		if e, a := c.expect, copySource(c.source); e != a {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+}
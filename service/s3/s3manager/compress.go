@@ -0,0 +1,231 @@
+package s3manager
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+// ErrCodeDecompress is the error code returned when a download's object
+// cannot be decompressed.
+const ErrCodeDecompress = "DecompressError"
+
+// DefaultCompressMinSize is the default size (in bytes) below which the
+// bodies of uploads are not compressed when the Uploader's Compress is set.
+const DefaultCompressMinSize int64 = 1024
+
+// gzipContentEncoding is the Content-Encoding of objects compressed with gzip.
+const gzipContentEncoding = "gzip"
+
+// DefaultCompressContentTypes is the default list of content types the bodies
+// of uploads are compressed for when the Uploader's Compress is set.
+var DefaultCompressContentTypes = []string{
+	"text/*",
+	"application/json",
+	"application/javascript",
+	"application/x-ndjson",
+	"application/xml",
+	"image/svg+xml",
+}
+
+// shouldCompress returns whether the body of the upload is compressed. Bodies
+// are compressed if the Uploader's Compress is set, the upload's content type
+// is allowed, the body is not already encoded, and the body is at least
+// CompressMinSize bytes, if its size is known.
+func (u *uploader) shouldCompress() bool {
+	if !u.cfg.Compress || u.in.ContentEncoding != nil {
+		return false
+	}
+
+	minSize := u.cfg.CompressMinSize
+	if minSize == 0 {
+		minSize = DefaultCompressMinSize
+	}
+	if u.totalSize >= 0 && u.totalSize < minSize {
+		return false
+	}
+
+	types := u.cfg.CompressContentTypes
+	if types == nil {
+		types = DefaultCompressContentTypes
+	}
+	return matchContentType(aws.StringValue(u.in.ContentType), types)
+}
+
+// matchContentType returns whether the media type of the content type matches
+// one of the patterns, such as "text/*".
+func matchContentType(contentType string, patterns []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), mediaType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// compressBody replaces the body of the upload with its gzip compression,
+// setting the upload's Content-Encoding. The size of the compressed body is
+// not known until it has been read entirely. The reader returned must be
+// closed once the upload completes, to stop the compression.
+func (u *uploader) compressBody() io.Closer {
+	pr, pw := io.Pipe()
+	go func(body io.Reader) {
+		gw := gzip.NewWriter(pw)
+		_, err := io.Copy(gw, body)
+		if err == nil {
+			err = gw.Close()
+		}
+		pw.CloseWithError(err)
+	}(u.in.Body)
+
+	in := *u.in
+	in.Body = pr
+	in.ContentEncoding = aws.String(gzipContentEncoding)
+	u.in = &in
+
+	u.totalSize = -1
+	u.progress.setTotal(-1, -1)
+
+	return pr
+}
+
+// isGzipEncoded returns whether an object with the Content-Encoding provided
+// is compressed with gzip.
+func isGzipEncoded(contentEncoding *string) bool {
+	for _, enc := range strings.Split(aws.StringValue(contentEncoding), ",") {
+		if strings.EqualFold(strings.TrimSpace(enc), gzipContentEncoding) {
+			return true
+		}
+	}
+	return false
+}
+
+// decompressor decompresses the chunks of a gzip compressed object, writing
+// the decompressed object to a writer. Chunks may be downloaded in any order,
+// but are decompressed in order, each chunk waiting for the chunks before it.
+type decompressor struct {
+	pw *io.PipeWriter
+
+	m    sync.Mutex
+	cond *sync.Cond
+	next int64 // the start of the next chunk to decompress
+	err  error
+
+	// Set by the decompressing goroutine before done is closed.
+	n       int64
+	readErr error
+	done    chan struct{}
+}
+
+// newDecompressor returns a decompressor writing the decompressed object to w.
+func newDecompressor(w io.WriterAt) *decompressor {
+	pr, pw := io.Pipe()
+	d := &decompressor{pw: pw, done: make(chan struct{})}
+	d.cond = sync.NewCond(&d.m)
+
+	go func() {
+		defer close(d.done)
+
+		gr, err := gzip.NewReader(pr)
+		if err == nil {
+			d.n, err = io.Copy(&offsetWriter{w: w}, gr)
+		}
+		d.readErr = err
+		pr.CloseWithError(err)
+	}()
+
+	return d
+}
+
+// write decompresses the chunk starting at start, once all chunks before it
+// have been decompressed.
+func (d *decompressor) write(start int64, p []byte) error {
+	if d == nil {
+		return nil
+	}
+
+	d.m.Lock()
+	for d.next != start && d.err == nil {
+		d.cond.Wait()
+	}
+	if err := d.err; err != nil {
+		d.m.Unlock()
+		return err
+	}
+	d.m.Unlock()
+
+	_, err := d.pw.Write(p)
+
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	if err != nil {
+		// The decompression stopped, either aborted or failing to
+		// decompress the object.
+		if d.err == nil {
+			d.err = newDecompressError(err)
+		}
+		err = d.err
+	}
+	d.next += int64(len(p))
+	d.cond.Broadcast()
+
+	return err
+}
+
+// abort stops the decompression, failing the chunks waiting to be
+// decompressed.
+func (d *decompressor) abort(err error) {
+	if d == nil {
+		return
+	}
+
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	if d.err == nil {
+		d.err = err
+	}
+	d.pw.CloseWithError(err)
+	d.cond.Broadcast()
+}
+
+// close waits for the decompression of the chunks written to complete, and
+// returns the number of decompressed bytes written.
+func (d *decompressor) close() (int64, error) {
+	d.pw.Close()
+	<-d.done
+
+	if d.readErr != nil {
+		return d.n, newDecompressError(d.readErr)
+	}
+	return d.n, d.err
+}
+
+// newDecompressError returns the error of an object failing to decompress.
+func newDecompressError(err error) error {
+	return awserr.New(ErrCodeDecompress, "failed to decompress object", err)
+}
+
+// offsetWriter writes sequentially to an io.WriterAt.
+type offsetWriter struct {
+	w   io.WriterAt
+	off int64
+}
+
+func (o *offsetWriter) Write(p []byte) (int, error) {
+	n, err := o.w.WriteAt(p, o.off)
+	o.off += int64(n)
+	return n, err
+}
@@ -0,0 +1,46 @@
+package s3manager
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestMatchContentType(t *testing.T) {
+	cases := []struct {
+		contentType string
+		expect      bool
+	}{
+		{"text/plain", true},
+		{"text/csv; charset=utf-8", true},
+		{"Application/JSON", true},
+		{"application/octet-stream", false},
+		{"image/png", false},
+		{"", false},
+	}
+
+	for i, c := range cases {
+		if e, a := c.expect, matchContentType(c.contentType, DefaultCompressContentTypes); e != a {
+			t.Errorf("%d, expect %v for %q, got %v", i, e, c.contentType, a)
+		}
+	}
+}
+
+func TestIsGzipEncoded(t *testing.T) {
+	cases := []struct {
+		encoding *string
+		expect   bool
+	}{
+		{aws.String("gzip"), true},
+		{aws.String("GZIP"), true},
+		{aws.String("identity, gzip"), true},
+		{aws.String("br"), false},
+		{nil, false},
+	}
+
+	for i, c := range cases {
+		if e, a := c.expect, isGzipEncoded(c.encoding); e != a {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+}
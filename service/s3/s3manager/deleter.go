@@ -0,0 +1,313 @@
+package s3manager
+
+import (
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// ErrCodeInvalidPattern is the error code returned when the glob pattern of
+// the objects to delete is malformed.
+const ErrCodeInvalidPattern = "InvalidPattern"
+
+// The Deleter deletes the objects of a bucket matching a key prefix, or a
+// glob pattern, streaming the matching keys from the bucket's listing into
+// batched DeleteObjects calls. It is safe to call DeleteMatching on this
+// structure for multiple buckets and across concurrent goroutines. Mutating
+// the Deleter's properties is not safe to be done concurrently.
+type Deleter struct {
+	// Setting this value to true will only list the matching objects,
+	// without deleting them. The objects which would be deleted are counted,
+	// and passed to the input's OnMatch callback, as usual.
+	DryRun bool
+
+	// An S3 client to use when listing objects.
+	S3 s3iface.S3API
+
+	// The batch delete client to delete the matching objects with. Its
+	// BatchSize, PartialFailureRetries, and OnProgress configure how the
+	// objects are deleted, and how the progress of the deletion is reported.
+	BatchDelete *BatchDelete
+
+	// List of request options that will be passed down to the requests
+	// listing objects made by the deleter.
+	RequestOptions []request.Option
+}
+
+// NewDeleter creates a new Deleter instance to delete the objects matching a
+// prefix or pattern with. Pass in additional functional options to customize
+// the deleter behavior.
+//
+// Example:
+//     // The session the S3 Deleter will use
+//     sess := session.Must(session.NewSession())
+//
+//     // Create a deleter with the session, reporting the deletion's progress.
+//     deleter := s3manager.NewDeleter(sess, func(d *s3manager.Deleter) {
+//          d.BatchDelete.OnProgress = func(p s3manager.BatchProgress) {
+//              fmt.Println(p.Succeeded, "deleted,", p.Failed, "failed")
+//          }
+//     })
+func NewDeleter(c client.ConfigProvider, options ...func(*Deleter)) *Deleter {
+	return NewDeleterWithClient(s3.New(c), options...)
+}
+
+// NewDeleterWithClient creates a new Deleter instance to delete the objects
+// matching a prefix or pattern with the S3 service client provided. The
+// deleter's BatchDelete is created with the same client.
+func NewDeleterWithClient(svc s3iface.S3API, options ...func(*Deleter)) *Deleter {
+	d := &Deleter{
+		S3:          svc,
+		BatchDelete: NewBatchDeleteWithClient(svc),
+	}
+
+	for _, option := range options {
+		option(d)
+	}
+
+	return d
+}
+
+// DeleteMatchingInput provides the bucket, and the key prefix or glob pattern,
+// of the objects to delete.
+type DeleteMatchingInput struct {
+	// The bucket of the objects.
+	Bucket *string
+
+	// Only objects with keys beginning with the prefix are deleted, if set.
+	Prefix *string
+
+	// Only objects with keys matching the glob pattern are deleted, if set.
+	// The pattern is matched against the whole key with path.Match, so "*"
+	// does not match slashes, such as "logs/2019-*/*.gz". The listing is
+	// limited to the keys beginning with the pattern's leading literal
+	// characters.
+	Pattern string
+
+	// Setting this value to true deletes every version, and delete marker,
+	// of the matching objects of a versioned bucket. Otherwise only the
+	// current versions are deleted, which adds a delete marker per object to
+	// versioned buckets.
+	AllVersions bool
+
+	// OnMatch is called with each matching object, and version, as it is
+	// listed, before it is deleted, if set.
+	OnMatch func(*s3.ObjectIdentifier)
+}
+
+// DeleteMatchingOutput represents a response from the DeleteMatching() call.
+type DeleteMatchingOutput struct {
+	// The number of matching objects, and versions, deleted, or which would
+	// be deleted if the deleter is in dry-run mode. Objects which failed to
+	// be deleted are included, and returned in the BatchError.
+	Matched int64
+}
+
+// DeleteMatching lists the objects of the bucket matching the input's prefix
+// and pattern, and deletes them in batched DeleteObjects calls as they are
+// listed, without holding the listing in memory. If the deleter is in
+// dry-run mode the objects are only listed.
+//
+// The objects which could not be deleted, and the error of the listing if
+// any, are returned as a BatchError, along with the output.
+//
+// Additional functional options can be provided to configure the individual
+// call. These options are copies of the Deleter instance DeleteMatching is
+// called from. Modifying the options will not impact the original Deleter
+// instance.
+//
+// It is safe to call this method concurrently across goroutines.
+//
+// Example:
+//     // Delete every version of the gzipped logs of 2019.
+//     result, err := deleter.DeleteMatching(&s3manager.DeleteMatchingInput{
+//         Bucket:      aws.String("bucket"),
+//         Pattern:     "logs/2019-*/*.gz",
+//         AllVersions: true,
+//     })
+func (d Deleter) DeleteMatching(input *DeleteMatchingInput, options ...func(*Deleter)) (*DeleteMatchingOutput, error) {
+	return d.DeleteMatchingWithContext(aws.BackgroundContext(), input, options...)
+}
+
+// DeleteMatchingWithContext lists and deletes the matching objects the same
+// as DeleteMatching, with the addition of the ability to pass a context and
+// additional request options.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (d Deleter) DeleteMatchingWithContext(ctx aws.Context, input *DeleteMatchingInput, options ...func(*Deleter)) (*DeleteMatchingOutput, error) {
+	for _, option := range options {
+		option(&d)
+	}
+	d.RequestOptions = append(d.RequestOptions, request.WithAppendUserAgent("S3Manager"))
+
+	if len(input.Pattern) != 0 {
+		if _, err := path.Match(input.Pattern, ""); err != nil {
+			return nil, awserr.New(ErrCodeInvalidPattern,
+				"invalid glob pattern, "+input.Pattern, err)
+		}
+	}
+
+	iter := newMatchingDeleteIterator(ctx, d.S3, input, d.RequestOptions)
+
+	var err error
+	if d.DryRun {
+		for iter.Next() {
+		}
+	} else {
+		err = d.BatchDelete.Delete(ctx, iter)
+	}
+
+	out := &DeleteMatchingOutput{Matched: iter.matched}
+	if listErr := iter.Err(); listErr != nil {
+		var errs []Error
+		if berr, ok := err.(*BatchError); ok {
+			errs = berr.Errors
+		}
+		errs = append(errs, newError(listErr, input.Bucket, nil))
+		return out, NewBatchError("BatchedDeleteIncomplete", "some objects have failed to be deleted.", errs)
+	}
+
+	return out, err
+}
+
+// listPrefix returns the prefix of the listing of the objects matching the
+// prefix and pattern, the longest of the prefix and the leading literal
+// characters of the pattern.
+func listPrefix(prefix, pattern string) string {
+	literal := pattern
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		literal = pattern[:i]
+	}
+	if strings.HasPrefix(literal, prefix) {
+		return literal
+	}
+	return prefix
+}
+
+// matchingDeleteIterator is a BatchDeleteIterator iterating through the
+// objects, or versions, of a bucket's listing matching a prefix and pattern.
+type matchingDeleteIterator struct {
+	bucket    *string
+	prefix    string
+	pattern   string
+	versions  bool
+	onMatch   func(*s3.ObjectIdentifier)
+	paginator request.Pagination
+
+	objects []*s3.ObjectIdentifier
+	started bool
+	matched int64
+}
+
+func newMatchingDeleteIterator(ctx aws.Context, svc s3iface.S3API, input *DeleteMatchingInput, opts []request.Option) *matchingDeleteIterator {
+	iter := &matchingDeleteIterator{
+		bucket:   input.Bucket,
+		prefix:   aws.StringValue(input.Prefix),
+		pattern:  input.Pattern,
+		versions: input.AllVersions,
+		onMatch:  input.OnMatch,
+	}
+	prefix := aws.String(listPrefix(iter.prefix, iter.pattern))
+
+	iter.paginator.NewRequest = func() (*request.Request, error) {
+		var req *request.Request
+		if iter.versions {
+			req, _ = svc.ListObjectVersionsRequest(&s3.ListObjectVersionsInput{
+				Bucket: input.Bucket,
+				Prefix: prefix,
+			})
+		} else {
+			req, _ = svc.ListObjectsV2Request(&s3.ListObjectsV2Input{
+				Bucket: input.Bucket,
+				Prefix: prefix,
+			})
+		}
+		req.SetContext(ctx)
+		req.ApplyOptions(opts...)
+		return req, nil
+	}
+
+	return iter
+}
+
+// Next advances the iterator to the next matching object, listing the next
+// page of objects if needed.
+func (iter *matchingDeleteIterator) Next() bool {
+	if iter.started && len(iter.objects) > 0 {
+		iter.objects = iter.objects[1:]
+	}
+	iter.started = true
+
+	for len(iter.objects) == 0 && iter.paginator.Next() {
+		iter.objects = iter.matching(iter.paginator.Page())
+	}
+	if len(iter.objects) == 0 {
+		return false
+	}
+
+	iter.matched++
+	if iter.onMatch != nil {
+		iter.onMatch(iter.objects[0])
+	}
+	return true
+}
+
+// matching returns the objects of the listing's page matching the prefix
+// and pattern.
+func (iter *matchingDeleteIterator) matching(page interface{}) []*s3.ObjectIdentifier {
+	var objects []*s3.ObjectIdentifier
+	add := func(key, versionID *string) {
+		k := aws.StringValue(key)
+		if !strings.HasPrefix(k, iter.prefix) {
+			return
+		}
+		if len(iter.pattern) != 0 {
+			if ok, _ := path.Match(iter.pattern, k); !ok {
+				return
+			}
+		}
+		objects = append(objects, &s3.ObjectIdentifier{Key: key, VersionId: versionID})
+	}
+
+	switch p := page.(type) {
+	case *s3.ListObjectsV2Output:
+		for _, o := range p.Contents {
+			add(o.Key, nil)
+		}
+	case *s3.ListObjectVersionsOutput:
+		for _, v := range p.Versions {
+			add(v.Key, v.VersionId)
+		}
+		for _, m := range p.DeleteMarkers {
+			add(m.Key, m.VersionId)
+		}
+	}
+
+	return objects
+}
+
+// Err returns the error of the listing which ended the iteration, if any.
+func (iter *matchingDeleteIterator) Err() error {
+	return iter.paginator.Err()
+}
+
+// DeleteObject returns the current object to be deleted.
+func (iter *matchingDeleteIterator) DeleteObject() BatchDeleteObject {
+	o := iter.objects[0]
+	return BatchDeleteObject{
+		Object: &s3.DeleteObjectInput{
+			Bucket:    iter.bucket,
+			Key:       o.Key,
+			VersionId: o.VersionId,
+		},
+	}
+}
@@ -0,0 +1,169 @@
+package s3manager_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+var deleterKeys = []string{
+	"logs/2019-01/a.gz",
+	"logs/2019-01/b.txt",
+	"logs/2019-02/c.gz",
+	"logs/2019-02/sub/d.gz",
+	"logs/2020-01/e.gz",
+	"other/f.gz",
+}
+
+// deleterSvc returns a client listing the deleterKeys, with two versions of
+// each key, recording the objects deleted as "key@version".
+func deleterSvc() (*s3.S3, *[]string, *[]string) {
+	var m sync.Mutex
+	deleted := []string{}
+	prefixes := []string{}
+
+	svc := s3.New(unit.Session, &aws.Config{MaxRetries: aws.Int(0)})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Unmarshal.Clear()
+	svc.Handlers.UnmarshalMeta.Clear()
+	svc.Handlers.UnmarshalError.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte{})),
+		}
+
+		switch in := r.Params.(type) {
+		case *s3.ListObjectsV2Input:
+			prefixes = append(prefixes, aws.StringValue(in.Prefix))
+			out := r.Data.(*s3.ListObjectsV2Output)
+			for _, key := range deleterKeys {
+				if strings.HasPrefix(key, aws.StringValue(in.Prefix)) {
+					out.Contents = append(out.Contents, &s3.Object{Key: aws.String(key)})
+				}
+			}
+		case *s3.ListObjectVersionsInput:
+			prefixes = append(prefixes, aws.StringValue(in.Prefix))
+			out := r.Data.(*s3.ListObjectVersionsOutput)
+			for _, key := range deleterKeys {
+				if strings.HasPrefix(key, aws.StringValue(in.Prefix)) {
+					out.Versions = append(out.Versions, &s3.ObjectVersion{Key: aws.String(key), VersionId: aws.String("v1")})
+					out.DeleteMarkers = append(out.DeleteMarkers, &s3.DeleteMarkerEntry{Key: aws.String(key), VersionId: aws.String("v2")})
+				}
+			}
+		case *s3.DeleteObjectsInput:
+			m.Lock()
+			defer m.Unlock()
+			for _, o := range in.Delete.Objects {
+				deleted = append(deleted, aws.StringValue(o.Key)+"@"+aws.StringValue(o.VersionId))
+			}
+		}
+	})
+
+	return svc, &deleted, &prefixes
+}
+
+func TestDeleteMatching(t *testing.T) {
+	cases := map[string]struct {
+		Input    s3manager.DeleteMatchingInput
+		Deleted  []string
+		Prefixes []string
+	}{
+		"prefix": {
+			Input:    s3manager.DeleteMatchingInput{Prefix: aws.String("logs/2019-02/")},
+			Deleted:  []string{"logs/2019-02/c.gz@", "logs/2019-02/sub/d.gz@"},
+			Prefixes: []string{"logs/2019-02/"},
+		},
+		"pattern": {
+			Input:    s3manager.DeleteMatchingInput{Pattern: "logs/2019-*/*.gz"},
+			Deleted:  []string{"logs/2019-01/a.gz@", "logs/2019-02/c.gz@"},
+			Prefixes: []string{"logs/2019-"},
+		},
+		"prefix and pattern": {
+			Input:    s3manager.DeleteMatchingInput{Prefix: aws.String("logs/"), Pattern: "*/*/*.gz"},
+			Deleted:  []string{"logs/2019-01/a.gz@", "logs/2019-02/c.gz@", "logs/2020-01/e.gz@"},
+			Prefixes: []string{"logs/"},
+		},
+		"all versions": {
+			Input:    s3manager.DeleteMatchingInput{Pattern: "logs/2020-01/*", AllVersions: true},
+			Deleted:  []string{"logs/2020-01/e.gz@v1", "logs/2020-01/e.gz@v2"},
+			Prefixes: []string{"logs/2020-01/"},
+		},
+	}
+
+	for name, c := range cases {
+		svc, deleted, prefixes := deleterSvc()
+		deleter := s3manager.NewDeleterWithClient(svc)
+
+		var matched []string
+		input := c.Input
+		input.Bucket = aws.String("bucket")
+		input.OnMatch = func(o *s3.ObjectIdentifier) {
+			matched = append(matched, aws.StringValue(o.Key))
+		}
+
+		out, err := deleter.DeleteMatching(&input)
+		if err != nil {
+			t.Fatalf("%s, expect no error, got %v", name, err)
+		}
+		sort.Strings(*deleted)
+		if e, a := strings.Join(c.Deleted, ","), strings.Join(*deleted, ","); e != a {
+			t.Errorf("%s, expect %v deleted, got %v", name, e, a)
+		}
+		if e, a := int64(len(c.Deleted)), out.Matched; e != a {
+			t.Errorf("%s, expect %v matched, got %v", name, e, a)
+		}
+		if e, a := len(c.Deleted), len(matched); e != a {
+			t.Errorf("%s, expect %v OnMatch calls, got %v", name, e, a)
+		}
+		if e, a := strings.Join(c.Prefixes, ","), strings.Join(*prefixes, ","); e != a {
+			t.Errorf("%s, expect %v listing prefixes, got %v", name, e, a)
+		}
+	}
+}
+
+func TestDeleteMatching_DryRun(t *testing.T) {
+	svc, deleted, _ := deleterSvc()
+	deleter := s3manager.NewDeleterWithClient(svc, func(d *s3manager.Deleter) {
+		d.DryRun = true
+	})
+
+	out, err := deleter.DeleteMatching(&s3manager.DeleteMatchingInput{
+		Bucket:  aws.String("bucket"),
+		Pattern: "*/*.gz",
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := int64(1), out.Matched; e != a {
+		t.Errorf("expect %v matched, got %v", e, a)
+	}
+	if e, a := 0, len(*deleted); e != a {
+		t.Errorf("expect %v deleted, got %v", e, a)
+	}
+}
+
+func TestDeleteMatching_InvalidPattern(t *testing.T) {
+	svc, _, _ := deleterSvc()
+	_, err := s3manager.NewDeleterWithClient(svc).DeleteMatching(&s3manager.DeleteMatchingInput{
+		Bucket:  aws.String("bucket"),
+		Pattern: "logs/[",
+	})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := s3manager.ErrCodeInvalidPattern, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
@@ -42,12 +42,39 @@ type Downloader struct {
 	// Concurrency is ignored if the Range input parameter is provided.
 	Concurrency int
 
+	// Setting this value to true will cause the downloader to adjust the
+	// number of parts downloaded in parallel to the observed throughput,
+	// starting from Concurrency. The number is increased while the throughput
+	// improves, and halved when parts are retried or fail, up to
+	// MaxConcurrency.
+	AdaptiveConcurrency bool
+
+	// The maximum number of parts downloaded in parallel when
+	// AdaptiveConcurrency is set. If this is set to zero, the
+	// DefaultMaxAdaptiveConcurrency value will be used.
+	MaxConcurrency int
+
 	// An S3 client to use when performing downloads.
 	S3 s3iface.S3API
 
 	// List of request options that will be passed down to individual API
 	// operation requests made by the downloader.
 	RequestOptions []request.Option
+
+	// OnProgress is called with the progress of the download each time a
+	// part has been downloaded, if set. Use ProgressChannel to receive the
+	// progress on a channel instead.
+	OnProgress ProgressFunc
+
+	// Setting this value to true will cause the downloader to decompress
+	// objects whose Content-Encoding is gzip, such as those uploaded by an
+	// Uploader with Compress set, writing the decompressed object to the
+	// writer. The parts of the object are buffered in memory until the parts
+	// before them have been decompressed.
+	//
+	// Decompress is ignored if the Range input parameter is provided, and by
+	// DownloadResumable.
+	Decompress bool
 }
 
 // WithDownloaderRequestOptions appends to the Downloader's API request options.
@@ -186,6 +213,9 @@ func (d Downloader) DownloadWithContext(ctx aws.Context, w io.WriterAt, input *s
 		impl.cfg.PartSize = DefaultDownloadPartSize
 	}
 
+	impl.progress = newProgressTracker(impl.cfg.OnProgress)
+	impl.limiter = newConcurrencyLimiter(impl.cfg.AdaptiveConcurrency, impl.cfg.Concurrency, impl.cfg.MaxConcurrency)
+
 	return impl.download()
 }
 
@@ -266,6 +296,10 @@ type downloader struct {
 	err        error
 
 	partBodyMaxRetries int
+
+	progress     *progressTracker
+	limiter      *concurrencyLimiter
+	decompressor *decompressor
 }
 
 // download performs the implementation of the object download across ranged
@@ -286,7 +320,7 @@ func (d *downloader) download() (n int64, err error) {
 		// Spin up workers
 		ch := make(chan dlchunk, d.cfg.Concurrency)
 
-		for i := 0; i < d.cfg.Concurrency; i++ {
+		for i := 0; i < d.limiter.workers(d.cfg.Concurrency); i++ {
 			d.wg.Add(1)
 			go d.downloadPart(ch)
 		}
@@ -298,7 +332,7 @@ func (d *downloader) download() (n int64, err error) {
 			}
 
 			// Queue the next range of bytes to read.
-			ch <- dlchunk{w: d.w, start: d.pos, size: d.cfg.PartSize}
+			ch <- d.newChunk()
 			d.pos += d.cfg.PartSize
 		}
 
@@ -322,6 +356,17 @@ func (d *downloader) download() (n int64, err error) {
 		}
 	}
 
+	if d.decompressor != nil {
+		if d.err != nil {
+			d.decompressor.abort(d.err)
+		}
+		n, err := d.decompressor.close()
+		if err != nil && d.err == nil {
+			d.err = err
+		}
+		d.written = n
+	}
+
 	// Return error
 	return d.written, d.err
 }
@@ -344,8 +389,14 @@ func (d *downloader) downloadPart(ch chan dlchunk) {
 			continue
 		}
 
-		if err := d.downloadChunk(chunk); err != nil {
+		err := d.downloadChunk(chunk)
+		if err == nil {
+			err = d.flushChunk(chunk)
+		}
+		if err != nil {
 			d.setErr(err)
+			// Fail the chunks waiting on this chunk to be decompressed.
+			d.decompressor.abort(err)
 		}
 	}
 }
@@ -357,14 +408,43 @@ func (d *downloader) getChunk() {
 		return
 	}
 
-	chunk := dlchunk{w: d.w, start: d.pos, size: d.cfg.PartSize}
+	chunk := d.newChunk()
 	d.pos += d.cfg.PartSize
 
-	if err := d.downloadChunk(chunk); err != nil {
+	err := d.downloadChunk(chunk)
+	if err == nil {
+		err = d.flushChunk(chunk)
+	}
+	if err != nil {
 		d.setErr(err)
 	}
 }
 
+// newChunk returns the chunk of the object starting at the current position.
+// If the object may need to be decompressed the chunk is buffered in memory
+// instead of being written to the writer directly.
+func (d *downloader) newChunk() dlchunk {
+	chunk := dlchunk{w: d.w, start: d.pos, size: d.cfg.PartSize}
+	if d.cfg.Decompress {
+		chunk.buf = aws.NewWriteAtBuffer(make([]byte, 0, d.cfg.PartSize))
+	}
+	return chunk
+}
+
+// flushChunk writes a chunk buffered in memory to the writer, decompressing
+// it if the object is compressed.
+func (d *downloader) flushChunk(chunk dlchunk) error {
+	if chunk.buf == nil {
+		return nil
+	}
+	if d.decompressor != nil {
+		return d.decompressor.write(chunk.start, chunk.buf.Bytes())
+	}
+
+	_, err := d.w.WriteAt(chunk.buf.Bytes(), chunk.start)
+	return err
+}
+
 // downloadRange downloads an Object given the passed in Byte-Range value.
 // The chunk used down download the range will be configured for that range.
 func (d *downloader) downloadRange(rng string) {
@@ -394,13 +474,27 @@ func (d *downloader) downloadChunk(chunk dlchunk) error {
 
 	var n int64
 	var err error
+	var congested bool
+	d.limiter.acquire()
+	defer func() {
+		d.limiter.release(n, congested || err != nil)
+	}()
+
+	opts := d.limiter.observe(d.cfg.RequestOptions, &congested)
 	for retry := 0; retry <= d.partBodyMaxRetries; retry++ {
 		var resp *s3.GetObjectOutput
-		resp, err = d.cfg.S3.GetObjectWithContext(d.ctx, in, d.cfg.RequestOptions...)
+		resp, err = d.cfg.S3.GetObjectWithContext(d.ctx, in, opts...)
 		if err != nil {
 			return err
 		}
 		d.setTotalBytes(resp) // Set total if not yet set.
+		d.setProgressTotal(chunk, resp)
+
+		// The first chunk is downloaded before any other, so the
+		// decompressor is set before the other chunks are flushed.
+		if chunk.buf != nil && chunk.start == 0 && d.decompressor == nil && isGzipEncoded(resp.ContentEncoding) {
+			d.decompressor = newDecompressor(d.w)
+		}
 
 		n, err = io.Copy(&chunk, resp.Body)
 		resp.Body.Close()
@@ -409,16 +503,30 @@ func (d *downloader) downloadChunk(chunk dlchunk) error {
 		}
 
 		chunk.cur = 0
+		congested = true
 		logMessage(d.cfg.S3, aws.LogDebugWithRequestRetries,
 			fmt.Sprintf("DEBUG: object part body download interrupted %s, err, %v, retrying attempt %d",
 				aws.StringValue(in.Key), err, retry))
 	}
 
 	d.incrWritten(n)
+	if err == nil {
+		d.progress.partCompleted(n)
+	}
 
 	return err
 }
 
+// setProgressTotal sets the total bytes and parts of the download's progress
+// once known. A download of a range is made of the single part of the range.
+func (d *downloader) setProgressTotal(chunk dlchunk, resp *s3.GetObjectOutput) {
+	if len(chunk.withRange) != 0 {
+		d.progress.setTotal(aws.Int64Value(resp.ContentLength), 1)
+	} else if total := d.getTotalBytes(); total >= 0 {
+		d.progress.setTotal(total, partCount(total, d.cfg.PartSize))
+	}
+}
+
 func logMessage(svc s3iface.S3API, level aws.LogLevelType, msg string) {
 	s, ok := svc.(*s3.S3)
 	if !ok {
@@ -518,6 +626,9 @@ type dlchunk struct {
 
 	// specifies the byte range the chunk should be downloaded with.
 	withRange string
+
+	// buffers the chunk in memory instead of writing it to w, if set.
+	buf *aws.WriteAtBuffer
 }
 
 // Write wraps io.WriterAt for the dlchunk, writing from the dlchunk's start
@@ -530,7 +641,11 @@ func (c *dlchunk) Write(p []byte) (n int, err error) {
 		return 0, io.EOF
 	}
 
-	n, err = c.w.WriteAt(p, c.start+c.cur)
+	if c.buf != nil {
+		n, err = c.buf.WriteAt(p, c.cur)
+	} else {
+		n, err = c.w.WriteAt(p, c.start+c.cur)
+	}
 	c.cur += int64(n)
 
 	return
@@ -0,0 +1,357 @@
+package s3manager
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// DownloadCheckpointSuffix is the suffix of the checkpoint file a resumable
+// download records its progress in, next to the file being downloaded to.
+const DownloadCheckpointSuffix = ".s3download"
+
+// ErrCodeDownloadIntegrity is the error code returned when a resumable
+// download's file does not match the object's size or ETag once completed.
+const ErrCodeDownloadIntegrity = "DownloadIntegrityError"
+
+// downloadCheckpoint is the progress of a resumable download, stored as JSON
+// in the download's checkpoint file.
+type downloadCheckpoint struct {
+	Bucket    string  `json:"bucket"`
+	Key       string  `json:"key"`
+	VersionID string  `json:"versionId,omitempty"`
+	ETag      string  `json:"etag"`
+	Size      int64   `json:"size"`
+	PartSize  int64   `json:"partSize"`
+	Completed []int64 `json:"completed"`
+}
+
+// matches returns whether the checkpoint is of the object version and part
+// size provided, so that its completed parts can be reused.
+func (c *downloadCheckpoint) matches(o *downloadCheckpoint) bool {
+	return c.Bucket == o.Bucket && c.Key == o.Key && c.VersionID == o.VersionID &&
+		c.ETag == o.ETag && c.Size == o.Size && c.PartSize == o.PartSize
+}
+
+// numParts returns the number of parts of the object.
+func (c *downloadCheckpoint) numParts() int64 {
+	return (c.Size + c.PartSize - 1) / c.PartSize
+}
+
+// DownloadResumable downloads an object in S3 into the file using concurrent
+// GET requests, recording the parts downloaded in a checkpoint file, named
+// the file's name with the DownloadCheckpointSuffix. If the download is
+// interrupted, a later DownloadResumable call for the same object and file
+// resumes it, requesting only the parts which were not yet downloaded.
+//
+// A download is only resumed if the object's ETag and size, and the
+// downloader's PartSize, match those of the checkpoint. Otherwise the file is
+// truncated, and the object downloaded from the start. The parts are
+// requested with the object's ETag as the If-Match condition, so that parts
+// of a modified object are never written to the file.
+//
+// Once completed the file's size is verified against the object's, and its
+// MD5 digest against the object's ETag if the ETag is the MD5 digest of the
+// object, before the checkpoint file is removed.
+//
+// The file must be opened for both reading and writing. The number of bytes
+// downloaded by the call is returned, excluding the parts of earlier calls.
+//
+// Example:
+//     f, err := os.OpenFile("archive.tar", os.O_RDWR|os.O_CREATE, 0644)
+//     if err != nil {
+//         return err
+//     }
+//     defer f.Close()
+//
+//     _, err = downloader.DownloadResumable(f, &s3.GetObjectInput{
+//         Bucket: aws.String("bucket"),
+//         Key:    aws.String("archive.tar"),
+//     })
+func (d Downloader) DownloadResumable(file *os.File, input *s3.GetObjectInput, options ...func(*Downloader)) (n int64, err error) {
+	return d.DownloadResumableWithContext(aws.BackgroundContext(), file, input, options...)
+}
+
+// DownloadResumableWithContext downloads an object in S3 into the file the
+// same as DownloadResumable, with the addition of the ability to pass a
+// context.
+//
+// The Context must not be nil. A nil Context will cause a panic. Use the
+// Context to add deadlining, timeouts, ect. The download can be resumed after
+// the Context is canceled.
+func (d Downloader) DownloadResumableWithContext(ctx aws.Context, file *os.File, input *s3.GetObjectInput, options ...func(*Downloader)) (n int64, err error) {
+	impl := downloader{w: file, cfg: d, ctx: ctx, totalBytes: -1}
+
+	for _, option := range options {
+		option(&impl.cfg)
+	}
+	impl.cfg.RequestOptions = append(impl.cfg.RequestOptions, request.WithAppendUserAgent("S3Manager"))
+
+	if s, ok := d.S3.(maxRetrier); ok {
+		impl.partBodyMaxRetries = s.MaxRetries()
+	}
+
+	if impl.cfg.Concurrency == 0 {
+		impl.cfg.Concurrency = DefaultDownloadConcurrency
+	}
+
+	if impl.cfg.PartSize == 0 {
+		impl.cfg.PartSize = DefaultDownloadPartSize
+	}
+
+	impl.progress = newProgressTracker(impl.cfg.OnProgress)
+	impl.limiter = newConcurrencyLimiter(impl.cfg.AdaptiveConcurrency, impl.cfg.Concurrency, impl.cfg.MaxConcurrency)
+
+	if len(aws.StringValue(input.Range)) != 0 {
+		return 0, awserr.New("InvalidParameter", "resumable downloads do not support the Range parameter", nil)
+	}
+
+	r := &resumableDownloader{downloader: &impl, file: file, path: file.Name() + DownloadCheckpointSuffix}
+	return r.download(input)
+}
+
+// resumableDownloader is the implementation structure used internally by
+// DownloadResumable.
+type resumableDownloader struct {
+	*downloader
+
+	file *os.File
+	path string
+
+	cpMu sync.Mutex
+	cp   *downloadCheckpoint
+}
+
+// download performs the implementation of the resumable download.
+func (r *resumableDownloader) download(input *s3.GetObjectInput) (int64, error) {
+	head, err := r.cfg.S3.HeadObjectWithContext(r.ctx, &s3.HeadObjectInput{
+		Bucket:               input.Bucket,
+		Key:                  input.Key,
+		VersionId:            input.VersionId,
+		IfMatch:              input.IfMatch,
+		RequestPayer:         input.RequestPayer,
+		SSECustomerAlgorithm: input.SSECustomerAlgorithm,
+		SSECustomerKey:       input.SSECustomerKey,
+		SSECustomerKeyMD5:    input.SSECustomerKeyMD5,
+	}, r.cfg.RequestOptions...)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := r.loadCheckpoint(input, head); err != nil {
+		return 0, err
+	}
+	r.resumeProgress()
+
+	// Request the parts of the object version the checkpoint is of.
+	r.in = &s3.GetObjectInput{}
+	awsutil.Copy(r.in, input)
+	r.in.IfMatch = head.ETag
+
+	r.downloadParts()
+	if err := r.getErr(); err != nil {
+		return r.written, err
+	}
+
+	if err := r.verify(head); err != nil {
+		os.Remove(r.path)
+		return r.written, err
+	}
+
+	if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+		return r.written, err
+	}
+	return r.written, nil
+}
+
+// loadCheckpoint loads the download's checkpoint, if any, and resets the
+// download if the checkpoint does not match the object.
+func (r *resumableDownloader) loadCheckpoint(input *s3.GetObjectInput, head *s3.HeadObjectOutput) error {
+	cp := &downloadCheckpoint{
+		Bucket:    aws.StringValue(input.Bucket),
+		Key:       aws.StringValue(input.Key),
+		VersionID: aws.StringValue(head.VersionId),
+		ETag:      aws.StringValue(head.ETag),
+		Size:      aws.Int64Value(head.ContentLength),
+		PartSize:  r.cfg.PartSize,
+	}
+
+	var stored downloadCheckpoint
+	if b, err := ioutil.ReadFile(r.path); err == nil && json.Unmarshal(b, &stored) == nil &&
+		stored.matches(cp) && r.fileHasParts(&stored) {
+		cp = &stored
+	} else {
+		// Nothing to resume, start the download from the start.
+		if err := r.file.Truncate(0); err != nil {
+			return awserr.New("WriteError", "failed to truncate file", err)
+		}
+	}
+
+	r.cp = cp
+	return r.saveCheckpoint()
+}
+
+// fileHasParts returns whether the file is large enough to hold the
+// checkpoint's completed parts, which it is not if the file was truncated
+// since.
+func (r *resumableDownloader) fileHasParts(cp *downloadCheckpoint) bool {
+	fi, err := r.file.Stat()
+	if err != nil {
+		return false
+	}
+
+	for _, part := range cp.Completed {
+		end := (part + 1) * cp.PartSize
+		if end > cp.Size {
+			end = cp.Size
+		}
+		if part < 0 || part >= cp.numParts() || fi.Size() < end {
+			return false
+		}
+	}
+	return true
+}
+
+// saveCheckpoint writes the checkpoint to the checkpoint file. The file is
+// replaced atomically, so that an interrupted write does not corrupt it.
+func (r *resumableDownloader) saveCheckpoint() error {
+	b, err := json.Marshal(r.cp)
+	if err != nil {
+		return err
+	}
+
+	tmp := r.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return awserr.New("WriteError", "failed to write download checkpoint", err)
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		return awserr.New("WriteError", "failed to write download checkpoint", err)
+	}
+	return nil
+}
+
+// completePart records the part as downloaded in the checkpoint.
+func (r *resumableDownloader) completePart(part int64) error {
+	r.cpMu.Lock()
+	defer r.cpMu.Unlock()
+
+	r.cp.Completed = append(r.cp.Completed, part)
+	return r.saveCheckpoint()
+}
+
+// resumeProgress sets the total of the download's progress, including the
+// parts completed by earlier calls.
+func (r *resumableDownloader) resumeProgress() {
+	r.progress.setTotal(r.cp.Size, r.cp.numParts())
+
+	var n int64
+	for _, part := range r.cp.Completed {
+		end := (part + 1) * r.cp.PartSize
+		if end > r.cp.Size {
+			end = r.cp.Size
+		}
+		n += end - part*r.cp.PartSize
+	}
+	r.progress.resume(n, int64(len(r.cp.Completed)))
+}
+
+// downloadParts downloads the parts which are not yet completed concurrently.
+func (r *resumableDownloader) downloadParts() {
+	completed := map[int64]struct{}{}
+	for _, part := range r.cp.Completed {
+		completed[part] = struct{}{}
+	}
+
+	ch := make(chan int64, r.cfg.Concurrency)
+	for i := 0; i < r.limiter.workers(r.cfg.Concurrency); i++ {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			for part := range ch {
+				if r.getErr() != nil {
+					// Drain the channel if there is an error, to prevent
+					// deadlocking of download producer.
+					continue
+				}
+				if err := r.downloadPart(part); err != nil {
+					r.setErr(err)
+				}
+			}
+		}()
+	}
+
+	for part := int64(0); part < r.cp.numParts() && r.getErr() == nil; part++ {
+		if _, ok := completed[part]; !ok {
+			ch <- part
+		}
+	}
+	close(ch)
+	r.wg.Wait()
+}
+
+// downloadPart downloads the part into the file, and records it as completed.
+func (r *resumableDownloader) downloadPart(part int64) error {
+	start := part * r.cp.PartSize
+	size := r.cp.PartSize
+	if start+size > r.cp.Size {
+		size = r.cp.Size - start
+	}
+
+	chunk := dlchunk{w: r.w, start: start, size: size}
+	if err := r.downloadChunk(chunk); err != nil {
+		return err
+	}
+
+	return r.completePart(part)
+}
+
+// verify validates the file's size against the object's, and the file's MD5
+// digest against the object's ETag, if the ETag is the object's MD5 digest.
+func (r *resumableDownloader) verify(head *s3.HeadObjectOutput) error {
+	fi, err := r.file.Stat()
+	if err != nil {
+		return awserr.New("ReadError", "failed to stat file", err)
+	}
+	if fi.Size() != r.cp.Size {
+		return awserr.New(ErrCodeDownloadIntegrity,
+			fmt.Sprintf("downloaded file size %d does not match object size %d", fi.Size(), r.cp.Size), nil)
+	}
+
+	etag := strings.Trim(r.cp.ETag, `"`)
+	if !isMD5ETag(etag) || head.SSECustomerAlgorithm != nil ||
+		aws.StringValue(head.ServerSideEncryption) == s3.ServerSideEncryptionAwsKms {
+		return nil
+	}
+
+	h := md5.New()
+	if _, err := io.Copy(h, io.NewSectionReader(r.file, 0, r.cp.Size)); err != nil {
+		return awserr.New("ReadError", "failed to read file", err)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != etag {
+		return awserr.New(ErrCodeDownloadIntegrity,
+			fmt.Sprintf("downloaded file MD5 %s does not match object ETag %s", sum, etag), nil)
+	}
+	return nil
+}
+
+// isMD5ETag returns whether the ETag is an MD5 digest, which multipart
+// upload ETags, suffixed with the number of parts, are not.
+func isMD5ETag(etag string) bool {
+	if len(etag) != md5.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(etag)
+	return err == nil
+}
@@ -0,0 +1,198 @@
+package s3manager_test
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+type resumeObject struct {
+	m      sync.Mutex
+	data   []byte
+	etag   string
+	fail   map[string]bool
+	ranges []string
+}
+
+func newResumeObject(data []byte) *resumeObject {
+	sum := md5.Sum(data)
+	return &resumeObject{data: data, etag: `"` + hex.EncodeToString(sum[:]) + `"`, fail: map[string]bool{}}
+}
+
+func (o *resumeObject) client() *s3.S3 {
+	svc := s3.New(unit.Session, &aws.Config{MaxRetries: aws.Int(0)})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		o.m.Lock()
+		defer o.m.Unlock()
+
+		header := http.Header{}
+		header.Set("ETag", o.etag)
+		r.HTTPResponse = &http.Response{StatusCode: 200, Header: header}
+
+		if r.Operation.Name == "HeadObject" {
+			header.Set("Content-Length", strconv.Itoa(len(o.data)))
+			r.HTTPResponse.Body = ioutil.NopCloser(bytes.NewReader(nil))
+			return
+		}
+
+		rng := r.HTTPRequest.Header.Get("Range")
+		o.ranges = append(o.ranges, rng)
+		if o.fail[rng] || r.HTTPRequest.Header.Get("If-Match") != o.etag {
+			r.HTTPResponse.StatusCode = 412
+			r.HTTPResponse.Body = ioutil.NopCloser(bytes.NewReader(nil))
+			return
+		}
+
+		m := regexp.MustCompile(`bytes=(\d+)-(\d+)`).FindStringSubmatch(rng)
+		start, _ := strconv.Atoi(m[1])
+		fin, _ := strconv.Atoi(m[2])
+		if fin++; fin > len(o.data) {
+			fin = len(o.data)
+		}
+		header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, fin-1, len(o.data)))
+		header.Set("Content-Length", strconv.Itoa(fin-start))
+		r.HTTPResponse.Body = ioutil.NopCloser(bytes.NewReader(o.data[start:fin]))
+	})
+	return svc
+}
+
+func (o *resumeObject) takeRanges() []string {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	ranges := o.ranges
+	o.ranges = nil
+	return ranges
+}
+
+func openResumeFile(t *testing.T) (*os.File, func()) {
+	dir, err := ioutil.TempDir("", "s3manager-resume")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, "object"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return f, func() {
+		f.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func resumeInput() *s3.GetObjectInput {
+	return &s3.GetObjectInput{Bucket: aws.String("bucket"), Key: aws.String("key")}
+}
+
+func TestDownloadResumable(t *testing.T) {
+	obj := newResumeObject([]byte("0123456789"))
+	f, cleanup := openResumeFile(t)
+	defer cleanup()
+
+	d := s3manager.NewDownloaderWithClient(obj.client(), func(d *s3manager.Downloader) {
+		d.PartSize = 4
+		d.Concurrency = 1
+	})
+
+	// Fail the last part, interrupting the download.
+	obj.fail["bytes=8-9"] = true
+	if _, err := d.DownloadResumable(f, resumeInput()); err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if _, err := os.Stat(f.Name() + s3manager.DownloadCheckpointSuffix); err != nil {
+		t.Fatalf("expect checkpoint file, got %v", err)
+	}
+	obj.takeRanges()
+
+	// Resume the download, requesting only the missing part.
+	delete(obj.fail, "bytes=8-9")
+	n, err := d.DownloadResumable(f, resumeInput())
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := int64(2), n; e != a {
+		t.Errorf("expect %v bytes downloaded, got %v", e, a)
+	}
+	if e, a := "[bytes=8-9]", fmt.Sprint(obj.takeRanges()); e != a {
+		t.Errorf("expect %v ranges, got %v", e, a)
+	}
+
+	b, _ := ioutil.ReadFile(f.Name())
+	if e, a := "0123456789", string(b); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+	if _, err := os.Stat(f.Name() + s3manager.DownloadCheckpointSuffix); !os.IsNotExist(err) {
+		t.Errorf("expect checkpoint file to be removed, got %v", err)
+	}
+}
+
+func TestDownloadResumable_ObjectChanged(t *testing.T) {
+	obj := newResumeObject([]byte("0123456789"))
+	f, cleanup := openResumeFile(t)
+	defer cleanup()
+
+	d := s3manager.NewDownloaderWithClient(obj.client(), func(d *s3manager.Downloader) {
+		d.PartSize = 4
+		d.Concurrency = 1
+	})
+
+	obj.fail["bytes=8-9"] = true
+	if _, err := d.DownloadResumable(f, resumeInput()); err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	obj.takeRanges()
+
+	// The object is replaced, the download must start from the start.
+	changed := newResumeObject([]byte("abcdefghij"))
+	obj.data, obj.etag, obj.fail = changed.data, changed.etag, changed.fail
+	if _, err := d.DownloadResumable(f, resumeInput()); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "[bytes=0-3 bytes=4-7 bytes=8-9]", fmt.Sprint(obj.takeRanges()); e != a {
+		t.Errorf("expect %v ranges, got %v", e, a)
+	}
+
+	b, _ := ioutil.ReadFile(f.Name())
+	if e, a := "abcdefghij", string(b); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+}
+
+func TestDownloadResumable_IntegrityError(t *testing.T) {
+	obj := newResumeObject([]byte("0123456789"))
+	obj.etag = `"00000000000000000000000000000000"`
+	f, cleanup := openResumeFile(t)
+	defer cleanup()
+
+	d := s3manager.NewDownloaderWithClient(obj.client(), func(d *s3manager.Downloader) {
+		d.PartSize = 4
+	})
+
+	_, err := d.DownloadResumable(f, resumeInput())
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := s3manager.ErrCodeDownloadIntegrity, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+	if _, err := os.Stat(f.Name() + s3manager.DownloadCheckpointSuffix); !os.IsNotExist(err) {
+		t.Errorf("expect checkpoint file to be removed, got %v", err)
+	}
+}
@@ -2,9 +2,11 @@ package s3manager_test
 
 import (
 	"bytes"
+	"compress/gzip"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"net/http"
 	"reflect"
 	"regexp"
@@ -656,3 +658,141 @@ func (r *testErrReader) Read(p []byte) (int, error) {
 
 	return n, nil
 }
+
+func TestDownloadProgress(t *testing.T) {
+	s, _, _ := dlLoggingSvc(buf12MB)
+
+	var reports []s3manager.Progress
+	d := s3manager.NewDownloaderWithClient(s, func(d *s3manager.Downloader) {
+		d.OnProgress = func(p s3manager.Progress) {
+			reports = append(reports, p)
+		}
+	})
+	_, err := d.Download(&aws.WriteAtBuffer{}, &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("key"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := 3, len(reports); e != a {
+		t.Fatalf("expect %v reports, got %v", e, a)
+	}
+	for i, p := range reports {
+		if e, a := int64(i+1), p.PartsCompleted; e != a {
+			t.Errorf("%d, expect %v parts completed, got %v", i, e, a)
+		}
+		if e, a := int64(3), p.TotalParts; e != a {
+			t.Errorf("%d, expect %v total parts, got %v", i, e, a)
+		}
+		if e, a := int64(len(buf12MB)), p.TotalBytes; e != a {
+			t.Errorf("%d, expect %v total bytes, got %v", i, e, a)
+		}
+	}
+	if e, a := int64(len(buf12MB)), reports[2].BytesTransferred; e != a {
+		t.Errorf("expect %v bytes transferred, got %v", e, a)
+	}
+}
+
+func TestDownloadAdaptiveConcurrency(t *testing.T) {
+	s, names, _ := dlLoggingSvc(buf12MB)
+
+	d := s3manager.NewDownloaderWithClient(s, func(d *s3manager.Downloader) {
+		d.AdaptiveConcurrency = true
+		d.Concurrency = 1
+		d.MaxConcurrency = 2
+	})
+	w := &aws.WriteAtBuffer{}
+	n, err := d.Download(w, &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("key"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := int64(len(buf12MB)), n; e != a {
+		t.Errorf("expect %d bytes downloaded, got %d", e, a)
+	}
+	if e, a := 3, len(*names); e != a {
+		t.Errorf("expect %v API calls, got %v", e, a)
+	}
+	if e, a := buf12MB, w.Bytes(); !bytes.Equal(e, a) {
+		t.Errorf("expect downloaded data to match object")
+	}
+}
+
+func TestDownloadDecompress(t *testing.T) {
+	object := make([]byte, 1024*1024*12)
+	r := rand.New(rand.NewSource(1))
+	for i := range object {
+		object[i] = byte(r.Intn(256))
+	}
+
+	var compressed bytes.Buffer
+	gw := gzip.NewWriter(&compressed)
+	gw.Write(object)
+	gw.Close()
+
+	cases := map[string]struct {
+		data      []byte
+		encoding  string
+		expect    []byte
+		expectErr string
+	}{
+		"gzip": {
+			data: compressed.Bytes(), encoding: "gzip",
+			expect: object,
+		},
+		"not encoded": {
+			data:   buf12MB,
+			expect: buf12MB,
+		},
+		"invalid gzip": {
+			data: buf12MB, encoding: "gzip",
+			expectErr: s3manager.ErrCodeDecompress,
+		},
+	}
+
+	for name, c := range cases {
+		s, names, _ := dlLoggingSvc(c.data)
+		s.Handlers.Send.PushBack(func(r *request.Request) {
+			if len(c.encoding) != 0 {
+				r.HTTPResponse.Header.Set("Content-Encoding", c.encoding)
+			}
+		})
+
+		d := s3manager.NewDownloaderWithClient(s, func(d *s3manager.Downloader) {
+			d.Concurrency = 3
+			d.Decompress = true
+		})
+		w := &aws.WriteAtBuffer{}
+		n, err := d.Download(w, &s3.GetObjectInput{
+			Bucket: aws.String("bucket"),
+			Key:    aws.String("key"),
+		})
+
+		if len(c.expectErr) != 0 {
+			aerr, ok := err.(awserr.Error)
+			if !ok {
+				t.Fatalf("%s, expect awserr.Error, got %v", name, err)
+			}
+			if e, a := c.expectErr, aerr.Code(); e != a {
+				t.Errorf("%s, expect %v error code, got %v", name, e, a)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s, expect no error, got %v", name, err)
+		}
+		if e, a := int64(len(c.expect)), n; e != a {
+			t.Errorf("%s, expect %d bytes written, got %d", name, e, a)
+		}
+		if !bytes.Equal(c.expect, w.Bytes()) {
+			t.Errorf("%s, expect object to match", name)
+		}
+		if e, a := len(c.data)/(1024*1024*5)+1, len(*names); e != a {
+			t.Errorf("%s, expect %d API calls, got %d", name, e, a)
+		}
+	}
+}
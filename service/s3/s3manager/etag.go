@@ -0,0 +1,210 @@
+package s3manager
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"hash"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+// ErrCodeInvalidETag is the error code returned when an ETag is not the MD5
+// digest of an object, or of the parts of a multipart object.
+const ErrCodeInvalidETag = "InvalidETag"
+
+// mebibyte is the unit of the part sizes inferred from multipart ETags.
+const mebibyte int64 = 1024 * 1024
+
+// ComputeETag returns the ETag of an object uploaded from the reader with the
+// part size, as the Uploader would upload it. Objects no larger than the part
+// size are uploaded in a single part, and their ETag is the MD5 digest of the
+// object. The ETag of multipart objects is the MD5 digest of the MD5 digests
+// of their parts, followed by the number of parts, such as "...-3".
+//
+// ETags are only the digest of objects which are not encrypted with SSE-C or
+// SSE-KMS. Uploads of unknown size, whose part size grows as they are
+// uploaded, cannot be computed.
+//
+//     f, err := os.Open("backup.tar")
+//     ...
+//     etag, err := s3manager.ComputeETag(f, s3manager.DefaultUploadPartSize)
+func ComputeETag(r io.Reader, partSize int64) (string, error) {
+	if partSize <= 0 {
+		return "", awserr.New("ConfigError", "part size must be greater than zero", nil)
+	}
+
+	h := newETagHash(partSize)
+	if _, err := io.Copy(h, r); err != nil {
+		return "", awserr.New("ReadRequestBody", "read object data failed", err)
+	}
+	return h.etag(), nil
+}
+
+// InferPartSizes returns the part sizes a multipart object of the size could
+// have been uploaded with to have the number of parts of the ETag, smallest
+// first. Only part sizes of whole mebibytes, the sizes most uploaders use,
+// and the part size the Uploader adjusts to for objects which would exceed
+// MaxUploadParts are returned. Nil is returned if the ETag is not the ETag of
+// a multipart object.
+func InferPartSizes(size int64, etag string) []int64 {
+	_, parts, err := parseETag(etag)
+	if err != nil || parts < 2 {
+		return nil
+	}
+	n := int64(parts)
+
+	// The part sizes which split the object into n parts, the last part
+	// holding the remainder.
+	min := (size + n - 1) / n
+	max := (size - 1) / (n - 1)
+	if min < MinUploadPartSize {
+		min = MinUploadPartSize
+	}
+	if max > MaxUploadPartSize {
+		max = MaxUploadPartSize
+	}
+
+	var sizes []int64
+	for ps := (min + mebibyte - 1) / mebibyte * mebibyte; ps <= max; ps += mebibyte {
+		sizes = append(sizes, ps)
+	}
+
+	// The Uploader adjusts the part size of objects which would otherwise
+	// exceed MaxUploadParts.
+	if ps := size/int64(MaxUploadParts) + 1; ps >= min && ps <= max && ps%mebibyte != 0 {
+		i := sort.Search(len(sizes), func(i int) bool { return sizes[i] > ps })
+		sizes = append(sizes, 0)
+		copy(sizes[i+1:], sizes[i:])
+		sizes[i] = ps
+	}
+
+	return sizes
+}
+
+// VerifyETag reads the object of the size from the reader, returning whether
+// it matches the ETag of an uploaded object, without downloading the object.
+// The part sizes of multipart ETags are inferred with InferPartSizes, and all
+// are computed in a single read of the object. The part size the ETag matched
+// is returned, or zero if the ETag is of an object uploaded in a single part.
+//
+// An ErrCodeInvalidETag error is returned if the ETag is not the digest of
+// an object, such as the ETag of an object encrypted with SSE-C.
+//
+//     f, err := os.Open("backup.tar")
+//     ...
+//     info, err := f.Stat()
+//     ...
+//     _, ok, err := s3manager.VerifyETag(f, info.Size(), aws.StringValue(head.ETag))
+func VerifyETag(r io.Reader, size int64, etag string) (partSize int64, ok bool, err error) {
+	digest, parts, err := parseETag(etag)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if parts == 0 {
+		h := md5.New()
+		if _, err := io.Copy(h, r); err != nil {
+			return 0, false, awserr.New("ReadRequestBody", "read object data failed", err)
+		}
+		return 0, hex.EncodeToString(h.Sum(nil)) == digest, nil
+	}
+
+	partSizes := InferPartSizes(size, etag)
+	if len(partSizes) == 0 {
+		return 0, false, nil
+	}
+
+	hashes := make([]*etagHash, len(partSizes))
+	writers := make([]io.Writer, len(partSizes))
+	for i, ps := range partSizes {
+		hashes[i] = newETagHash(ps)
+		writers[i] = hashes[i]
+	}
+	if _, err := io.Copy(io.MultiWriter(writers...), r); err != nil {
+		return 0, false, awserr.New("ReadRequestBody", "read object data failed", err)
+	}
+
+	expect := digest + "-" + strconv.Itoa(parts)
+	for i, h := range hashes {
+		if h.etag() == expect {
+			return partSizes[i], true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// parseETag returns the hex digest of the ETag, and its number of parts, or
+// zero if the ETag is not the ETag of a multipart object.
+func parseETag(etag string) (string, int, error) {
+	etag = strings.ToLower(strings.Trim(etag, `"`))
+
+	digest, parts := etag, 0
+	if i := strings.Index(etag, "-"); i >= 0 {
+		n, err := strconv.Atoi(etag[i+1:])
+		if err != nil || n < 1 {
+			return "", 0, awserr.New(ErrCodeInvalidETag, "invalid multipart ETag part count", nil)
+		}
+		digest, parts = etag[:i], n
+	}
+
+	if b, err := hex.DecodeString(digest); err != nil || len(b) != md5.Size {
+		return "", 0, awserr.New(ErrCodeInvalidETag, "ETag is not an MD5 digest", nil)
+	}
+	return digest, parts, nil
+}
+
+// etagHash computes the ETag of an object uploaded with a part size.
+type etagHash struct {
+	partSize int64
+	part     hash.Hash
+	partLen  int64
+	parts    int
+	sums     []byte
+}
+
+func newETagHash(partSize int64) *etagHash {
+	return &etagHash{partSize: partSize, part: md5.New()}
+}
+
+func (h *etagHash) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		m := h.partSize - h.partLen
+		if int64(len(p)) < m {
+			m = int64(len(p))
+		}
+
+		h.part.Write(p[:m])
+		h.partLen += m
+		p = p[m:]
+
+		if h.partLen == h.partSize {
+			h.sums = h.part.Sum(h.sums)
+			h.part.Reset()
+			h.partLen = 0
+			h.parts++
+		}
+	}
+	return n, nil
+}
+
+// etag returns the ETag of the data written.
+func (h *etagHash) etag() string {
+	sums, parts := h.sums, h.parts
+	if h.partLen > 0 || parts == 0 {
+		sums = h.part.Sum(sums)
+		parts++
+	}
+
+	// Objects of a single part are uploaded with PutObject.
+	if parts == 1 {
+		return hex.EncodeToString(sums)
+	}
+
+	digest := md5.Sum(sums)
+	return hex.EncodeToString(digest[:]) + "-" + strconv.Itoa(parts)
+}
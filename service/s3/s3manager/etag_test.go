@@ -0,0 +1,120 @@
+package s3manager_test
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// multipartETag returns the ETag of the parts, computed independently of
+// the s3manager.
+func multipartETag(parts ...[]byte) string {
+	var sums []byte
+	for _, p := range parts {
+		sum := md5.Sum(p)
+		sums = append(sums, sum[:]...)
+	}
+	sum := md5.Sum(sums)
+	return hex.EncodeToString(sum[:]) + "-" + strconv.Itoa(len(parts))
+}
+
+func TestComputeETag(t *testing.T) {
+	const partSize = 5 * 1024 * 1024
+	data := make([]byte, 2*partSize+100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	singleSum := md5.Sum(data[:partSize])
+	cases := []struct {
+		Data   []byte
+		Expect string
+	}{
+		// Objects no larger than the part size are uploaded in one part.
+		{data[:partSize], hex.EncodeToString(singleSum[:])},
+		{data[:partSize+1], multipartETag(data[:partSize], data[partSize:partSize+1])},
+		{data, multipartETag(data[:partSize], data[partSize:2*partSize], data[2*partSize:])},
+	}
+
+	for i, c := range cases {
+		etag, err := s3manager.ComputeETag(bytes.NewReader(c.Data), partSize)
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		if e, a := c.Expect, etag; e != a {
+			t.Errorf("%d, expect %v ETag, got %v", i, e, a)
+		}
+	}
+}
+
+func TestInferPartSizes(t *testing.T) {
+	const mib = 1024 * 1024
+
+	cases := []struct {
+		Size   int64
+		ETag   string
+		Expect []int64
+	}{
+		{20 * mib, `"00000000000000000000000000000000-4"`, []int64{5 * mib, 6 * mib}},
+		{20*mib + 1, "00000000000000000000000000000000-3", []int64{7 * mib, 8 * mib, 9 * mib, 10 * mib}},
+		{20 * mib, "00000000000000000000000000000000-100", nil},
+		{20 * mib, "00000000000000000000000000000000", nil},
+		// The Uploader's adjusted part size of objects exceeding
+		// MaxUploadParts parts of the default part size.
+		{60000 * mib, "00000000000000000000000000000000-10000",
+			[]int64{6 * mib, 6*mib + 1}},
+	}
+
+	for i, c := range cases {
+		if e, a := c.Expect, s3manager.InferPartSizes(c.Size, c.ETag); !reflect.DeepEqual(e, a) {
+			t.Errorf("%d, expect %v part sizes, got %v", i, e, a)
+		}
+	}
+}
+
+func TestVerifyETag(t *testing.T) {
+	const mib = 1024 * 1024
+	data := make([]byte, 13*mib)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	size := int64(len(data))
+
+	etag := multipartETag(data[:6*mib], data[6*mib:12*mib], data[12*mib:])
+	partSize, ok, err := s3manager.VerifyETag(bytes.NewReader(data), size, `"`+etag+`"`)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !ok {
+		t.Fatalf("expect ETag to match")
+	}
+	if e, a := int64(6*mib), partSize; e != a {
+		t.Errorf("expect %v part size, got %v", e, a)
+	}
+
+	// Modified objects do not match.
+	modified := append([]byte{}, data...)
+	modified[len(modified)-1]++
+	if _, ok, _ := s3manager.VerifyETag(bytes.NewReader(modified), size, etag); ok {
+		t.Errorf("expect modified object not to match")
+	}
+
+	sum := md5.Sum(data)
+	if _, ok, err := s3manager.VerifyETag(bytes.NewReader(data), size, hex.EncodeToString(sum[:])); err != nil || !ok {
+		t.Errorf("expect single part ETag to match, got %v, %v", ok, err)
+	}
+
+	_, _, err = s3manager.VerifyETag(bytes.NewReader(data), size, "not-an-etag")
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := s3manager.ErrCodeInvalidETag, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
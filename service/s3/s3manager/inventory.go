@@ -0,0 +1,480 @@
+package s3manager
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// DefaultInventoryConcurrency is the default number of shards to list, and
+// HeadObject requests to make, in parallel when exporting an inventory with
+// the InventoryExporter.
+const DefaultInventoryConcurrency = 5
+
+// Formats the InventoryExporter writes inventories in.
+const (
+	// InventoryFormatCSV writes the inventory as CSV, with a header row.
+	InventoryFormatCSV = "csv"
+
+	// InventoryFormatJSONLines writes the inventory as JSON lines, a JSON
+	// object per object of the bucket.
+	InventoryFormatJSONLines = "jsonl"
+)
+
+// ErrCodeInvalidInventoryFormat is the error code returned when the format
+// of an inventory export is not a supported format.
+const ErrCodeInvalidInventoryFormat = "InvalidInventoryFormat"
+
+// inventoryCSVHeader is the header row of CSV inventories. The content type
+// and metadata columns are only set for inventories enriched with
+// HeadObject.
+var inventoryCSVHeader = []string{
+	"key", "size", "last_modified", "etag", "storage_class", "content_type", "metadata",
+}
+
+// An InventoryRecord is the inventory record of an object, written as a row
+// of CSV inventories, or a line of JSON lines inventories.
+type InventoryRecord struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+	ETag         string    `json:"etag"`
+	StorageClass string    `json:"storage_class,omitempty"`
+
+	// The content type and user metadata of the object, only set if the
+	// inventory is enriched with HeadObject.
+	ContentType string            `json:"content_type,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// The InventoryExporter exports the inventory of a bucket's objects, writing
+// a record per object to an io.Writer. It is safe to call ExportInventory on
+// this structure for multiple buckets and across concurrent goroutines.
+// Mutating the InventoryExporter's properties is not safe to be done
+// concurrently.
+type InventoryExporter struct {
+	// The number of shards to list, and HeadObject requests to make, in
+	// parallel. If this is set to zero, the DefaultInventoryConcurrency value
+	// will be used.
+	Concurrency int
+
+	// An S3 client to use when listing and heading objects.
+	S3 s3iface.S3API
+
+	// List of request options that will be passed down to individual API
+	// operation requests made by the exporter.
+	RequestOptions []request.Option
+}
+
+// WithInventoryExporterRequestOptions appends to the InventoryExporter's API
+// request options.
+func WithInventoryExporterRequestOptions(opts ...request.Option) func(*InventoryExporter) {
+	return func(e *InventoryExporter) {
+		e.RequestOptions = append(e.RequestOptions, opts...)
+	}
+}
+
+// NewInventoryExporter creates a new InventoryExporter instance to export
+// bucket inventories with. Pass in additional functional options to
+// customize the exporter behavior.
+//
+// Example:
+//     // The session the S3 InventoryExporter will use
+//     sess := session.Must(session.NewSession())
+//
+//     // Create an exporter with the session, listing 10 shards in parallel.
+//     exporter := s3manager.NewInventoryExporter(sess, func(e *s3manager.InventoryExporter) {
+//          e.Concurrency = 10
+//     })
+func NewInventoryExporter(c client.ConfigProvider, options ...func(*InventoryExporter)) *InventoryExporter {
+	return NewInventoryExporterWithClient(s3.New(c), options...)
+}
+
+// NewInventoryExporterWithClient creates a new InventoryExporter instance to
+// export bucket inventories with the S3 service client provided.
+func NewInventoryExporterWithClient(svc s3iface.S3API, options ...func(*InventoryExporter)) *InventoryExporter {
+	e := &InventoryExporter{
+		S3:          svc,
+		Concurrency: DefaultInventoryConcurrency,
+	}
+
+	for _, option := range options {
+		option(e)
+	}
+
+	return e
+}
+
+// ExportInventoryInput provides the bucket, and optional key prefix, of the
+// objects to export the inventory of, and how to write and shard it.
+type ExportInventoryInput struct {
+	// The bucket of the objects.
+	Bucket *string
+
+	// Only objects with keys beginning with the prefix are exported, if set.
+	Prefix *string
+
+	// The writer the inventory is written to.
+	Writer io.Writer
+
+	// The format of the inventory, InventoryFormatCSV or
+	// InventoryFormatJSONLines. Defaults to InventoryFormatCSV.
+	Format string
+
+	// Setting this value to true enriches the records of the objects with
+	// their content type and user metadata, with a HeadObject request per
+	// object.
+	HeadObject bool
+
+	// The key prefixes, following Prefix, the listing of the objects is
+	// sharded by, such as "0" to "9" and "a" to "f" for keys starting with a
+	// hexadecimal digit. Shards are listed in parallel, and the records of
+	// different shards are interleaved in the inventory. Objects whose keys
+	// do not begin with one of the shards are not exported. If not set, the
+	// objects are listed as a single shard.
+	Shards []string
+
+	// The resume markers of a previous export, which failed or was canceled,
+	// to resume the export from, the Markers of its ExportInventoryOutput.
+	// The records of objects up to the marker of each shard are not written
+	// again. The CSV header row is not written when resuming an export.
+	Markers map[string]string
+}
+
+// ExportInventoryOutput represents a response from the ExportInventory()
+// call.
+type ExportInventoryOutput struct {
+	// The number of records written.
+	Count int64
+
+	// The key of the last object written of each shard, keyed by shard. The
+	// markers are returned along with the error of failed exports, to resume
+	// the export from with the input's Markers.
+	Markers map[string]string
+}
+
+// ExportInventory lists the objects of the bucket, writing the inventory
+// record of each object to the input's writer. The shards of the listing are
+// listed in parallel, and the records of the objects of a page of a shard are
+// written once the page is listed, and enriched with HeadObject if enabled.
+//
+// Exports which fail return the output of the records written so far, with
+// the resume markers to resume the export from.
+//
+// Additional functional options can be provided to configure the individual
+// call. These options are copies of the InventoryExporter instance
+// ExportInventory is called from. Modifying the options will not impact the
+// original InventoryExporter instance.
+//
+// It is safe to call this method concurrently across goroutines.
+//
+// Example:
+//     f, err := os.Create("inventory.csv")
+//     ...
+//     out, err := exporter.ExportInventory(&s3manager.ExportInventoryInput{
+//         Bucket: aws.String("bucket"),
+//         Writer: f,
+//         Shards: []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"},
+//     })
+//     if err != nil {
+//         // Resume the export later with the markers of the output.
+//         resumeInput.Markers = out.Markers
+//     }
+func (e InventoryExporter) ExportInventory(input *ExportInventoryInput, options ...func(*InventoryExporter)) (*ExportInventoryOutput, error) {
+	return e.ExportInventoryWithContext(aws.BackgroundContext(), input, options...)
+}
+
+// ExportInventoryWithContext exports the inventory of a bucket the same as
+// ExportInventory, with the addition of the ability to pass a context and
+// additional request options.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (e InventoryExporter) ExportInventoryWithContext(ctx aws.Context, input *ExportInventoryInput, options ...func(*InventoryExporter)) (*ExportInventoryOutput, error) {
+	for _, option := range options {
+		option(&e)
+	}
+	e.RequestOptions = append(e.RequestOptions, request.WithAppendUserAgent("S3Manager"))
+
+	concurrency := e.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultInventoryConcurrency
+	}
+
+	w, err := newInventoryWriter(input.Writer, input.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	x := &inventoryExport{
+		InventoryExporter: e,
+		ctx:               ctx,
+		input:             input,
+		w:                 w,
+		heads:             make(chan struct{}, concurrency),
+		out:               &ExportInventoryOutput{Markers: map[string]string{}},
+	}
+	for shard, marker := range input.Markers {
+		x.out.Markers[shard] = marker
+	}
+
+	if len(input.Markers) == 0 {
+		if err := w.writeHeader(); err != nil {
+			return x.out, err
+		}
+	}
+
+	shards := input.Shards
+	if len(shards) == 0 {
+		shards = []string{""}
+	}
+
+	var wg sync.WaitGroup
+	ch := make(chan string)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for shard := range ch {
+				x.exportShard(shard)
+			}
+		}()
+	}
+
+	for _, shard := range shards {
+		ch <- shard
+	}
+	close(ch)
+	wg.Wait()
+
+	return x.out, x.err
+}
+
+// inventoryExport is the state of an inventory export.
+type inventoryExport struct {
+	InventoryExporter
+
+	ctx   aws.Context
+	input *ExportInventoryInput
+	w     inventoryWriter
+
+	// heads bounds the number of HeadObject requests made in parallel.
+	heads chan struct{}
+
+	m   sync.Mutex
+	out *ExportInventoryOutput
+	err error
+}
+
+// exportShard lists the objects of the shard after the shard's marker,
+// writing the records of each page of objects.
+func (x *inventoryExport) exportShard(shard string) {
+	if x.getErr() != nil {
+		return
+	}
+
+	x.m.Lock()
+	marker := x.out.Markers[shard]
+	x.m.Unlock()
+
+	in := &s3.ListObjectsV2Input{
+		Bucket: x.input.Bucket,
+		Prefix: aws.String(aws.StringValue(x.input.Prefix) + shard),
+	}
+	if len(marker) != 0 {
+		in.StartAfter = aws.String(marker)
+	}
+
+	err := x.S3.ListObjectsV2PagesWithContext(x.ctx, in, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		records, err := x.records(page.Contents)
+		if err == nil {
+			err = x.writeRecords(shard, records)
+		}
+		if err != nil {
+			x.setErr(err)
+			return false
+		}
+		return x.getErr() == nil
+	}, x.RequestOptions...)
+	if err != nil {
+		x.setErr(err)
+	}
+}
+
+// records returns the inventory records of the objects, enriched with
+// HeadObject requests made in parallel if enabled.
+func (x *inventoryExport) records(objects []*s3.Object) ([]InventoryRecord, error) {
+	records := make([]InventoryRecord, len(objects))
+	for i, obj := range objects {
+		records[i] = InventoryRecord{
+			Key:          aws.StringValue(obj.Key),
+			Size:         aws.Int64Value(obj.Size),
+			LastModified: aws.TimeValue(obj.LastModified),
+			ETag:         aws.StringValue(obj.ETag),
+			StorageClass: aws.StringValue(obj.StorageClass),
+		}
+	}
+	if !x.input.HeadObject {
+		return records, nil
+	}
+
+	var (
+		wg   sync.WaitGroup
+		m    sync.Mutex
+		errs []Error
+	)
+	for i := range records {
+		x.heads <- struct{}{}
+		wg.Add(1)
+		go func(r *InventoryRecord) {
+			defer func() { <-x.heads }()
+			defer wg.Done()
+
+			out, err := x.S3.HeadObjectWithContext(x.ctx, &s3.HeadObjectInput{
+				Bucket: x.input.Bucket,
+				Key:    aws.String(r.Key),
+			}, x.RequestOptions...)
+			if err != nil {
+				// Objects deleted since they were listed are exported as
+				// listed.
+				if isNotFound(err) {
+					return
+				}
+				m.Lock()
+				errs = append(errs, newError(err, x.input.Bucket, aws.String(r.Key)))
+				m.Unlock()
+				return
+			}
+			r.ContentType = aws.StringValue(out.ContentType)
+			r.Metadata = aws.StringValueMap(out.Metadata)
+		}(&records[i])
+	}
+	wg.Wait()
+
+	if len(errs) != 0 {
+		return nil, NewBatchError("ExportInventoryError", "some objects failed to be enriched", errs)
+	}
+	return records, nil
+}
+
+// writeRecords writes the records of a page of the shard, updating the
+// shard's marker once the records are written.
+func (x *inventoryExport) writeRecords(shard string, records []InventoryRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	x.m.Lock()
+	defer x.m.Unlock()
+
+	for _, r := range records {
+		if err := x.w.write(r); err != nil {
+			return err
+		}
+	}
+	if err := x.w.flush(); err != nil {
+		return err
+	}
+
+	x.out.Count += int64(len(records))
+	x.out.Markers[shard] = records[len(records)-1].Key
+	return nil
+}
+
+func (x *inventoryExport) setErr(err error) {
+	x.m.Lock()
+	defer x.m.Unlock()
+
+	if x.err == nil {
+		x.err = err
+	}
+}
+
+func (x *inventoryExport) getErr() error {
+	x.m.Lock()
+	defer x.m.Unlock()
+
+	return x.err
+}
+
+// inventoryWriter writes the inventory records of a format.
+type inventoryWriter interface {
+	writeHeader() error
+	write(InventoryRecord) error
+	flush() error
+}
+
+func newInventoryWriter(w io.Writer, format string) (inventoryWriter, error) {
+	switch format {
+	case "", InventoryFormatCSV:
+		return &csvInventoryWriter{w: csv.NewWriter(w)}, nil
+	case InventoryFormatJSONLines:
+		return &jsonInventoryWriter{enc: json.NewEncoder(w)}, nil
+	default:
+		return nil, awserr.New(ErrCodeInvalidInventoryFormat,
+			"unsupported inventory format, "+format, nil)
+	}
+}
+
+type csvInventoryWriter struct {
+	w *csv.Writer
+}
+
+func (w *csvInventoryWriter) writeHeader() error {
+	w.w.Write(inventoryCSVHeader)
+	return w.flush()
+}
+
+func (w *csvInventoryWriter) write(r InventoryRecord) error {
+	var metadata string
+	if len(r.Metadata) != 0 {
+		b, err := json.Marshal(r.Metadata)
+		if err != nil {
+			return err
+		}
+		metadata = string(b)
+	}
+
+	return w.w.Write([]string{
+		r.Key,
+		strconv.FormatInt(r.Size, 10),
+		r.LastModified.UTC().Format(time.RFC3339),
+		r.ETag,
+		r.StorageClass,
+		r.ContentType,
+		metadata,
+	})
+}
+
+func (w *csvInventoryWriter) flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type jsonInventoryWriter struct {
+	enc *json.Encoder
+}
+
+func (w *jsonInventoryWriter) writeHeader() error {
+	return nil
+}
+
+func (w *jsonInventoryWriter) write(r InventoryRecord) error {
+	return w.enc.Encode(r)
+}
+
+func (w *jsonInventoryWriter) flush() error {
+	return nil
+}
@@ -0,0 +1,206 @@
+package s3manager_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+var inventoryKeys = []string{"a0", "a1", "a2", "b0", "b1", "c0"}
+
+func inventorySvc(failKey *string) *s3.S3 {
+	svc := s3.New(unit.Session, &aws.Config{MaxRetries: aws.Int(0)})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Unmarshal.Clear()
+	svc.Handlers.UnmarshalMeta.Clear()
+	svc.Handlers.UnmarshalError.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte{})),
+		}
+
+		switch in := r.Params.(type) {
+		case *s3.ListObjectsV2Input:
+			// Two objects per page, after the continuation token or
+			// StartAfter key.
+			after := aws.StringValue(in.StartAfter)
+			if in.ContinuationToken != nil {
+				after = *in.ContinuationToken
+			}
+			var keys []string
+			for _, key := range inventoryKeys {
+				if strings.HasPrefix(key, aws.StringValue(in.Prefix)) && key > after {
+					keys = append(keys, key)
+				}
+			}
+			out := r.Data.(*s3.ListObjectsV2Output)
+			for i, key := range keys {
+				if i == 2 {
+					out.IsTruncated = aws.Bool(true)
+					out.NextContinuationToken = aws.String(keys[i-1])
+					break
+				}
+				out.Contents = append(out.Contents, &s3.Object{
+					Key:          aws.String(key),
+					Size:         aws.Int64(int64(len(key))),
+					LastModified: aws.Time(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)),
+					ETag:         aws.String(`"etag-` + key + `"`),
+					StorageClass: aws.String("STANDARD"),
+				})
+			}
+		case *s3.HeadObjectInput:
+			if aws.StringValue(in.Key) == aws.StringValue(failKey) {
+				r.Error = awserr.New("AccessDenied", "access denied", nil)
+				return
+			}
+			out := r.Data.(*s3.HeadObjectOutput)
+			out.ContentType = aws.String("text/plain")
+			out.Metadata = map[string]*string{"owner": in.Key}
+		}
+	})
+
+	return svc
+}
+
+func TestExportInventory_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	exporter := s3manager.NewInventoryExporterWithClient(inventorySvc(nil))
+	out, err := exporter.ExportInventory(&s3manager.ExportInventoryInput{
+		Bucket: aws.String("bucket"),
+		Writer: &buf,
+		Shards: []string{"a", "b", "c"},
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := int64(len(inventoryKeys)), out.Count; e != a {
+		t.Errorf("expect %v records, got %v", e, a)
+	}
+	if e, a := "a2", out.Markers["a"]; e != a {
+		t.Errorf("expect %v marker, got %v", e, a)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "key,size,last_modified,etag,storage_class,content_type,metadata", strings.Join(rows[0], ","); e != a {
+		t.Errorf("expect %v header, got %v", e, a)
+	}
+	var keys []string
+	byKey := map[string][]string{}
+	for _, row := range rows[1:] {
+		keys = append(keys, row[0])
+		byKey[row[0]] = row
+	}
+	sort.Strings(keys)
+	if e, a := strings.Join(inventoryKeys, ","), strings.Join(keys, ","); e != a {
+		t.Errorf("expect %v keys, got %v", e, a)
+	}
+	if e, a := `b1,2,2020-01-02T03:04:05Z,"etag-b1",STANDARD,,`, strings.Join(byKey["b1"], ","); e != a {
+		t.Errorf("expect %v row, got %v", e, a)
+	}
+}
+
+func TestExportInventory_JSONLinesHeadObject(t *testing.T) {
+	var buf bytes.Buffer
+	exporter := s3manager.NewInventoryExporterWithClient(inventorySvc(nil))
+	_, err := exporter.ExportInventory(&s3manager.ExportInventoryInput{
+		Bucket:     aws.String("bucket"),
+		Prefix:     aws.String("a"),
+		Writer:     &buf,
+		Format:     s3manager.InventoryFormatJSONLines,
+		HeadObject: true,
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	var records []s3manager.InventoryRecord
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var r s3manager.InventoryRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		records = append(records, r)
+	}
+	if e, a := 3, len(records); e != a {
+		t.Fatalf("expect %v records, got %v", e, a)
+	}
+	for i, r := range records {
+		if e, a := inventoryKeys[i], r.Key; e != a {
+			t.Errorf("%d, expect %v key, got %v", i, e, a)
+		}
+		if e, a := "text/plain", r.ContentType; e != a {
+			t.Errorf("%d, expect %v content type, got %v", i, e, a)
+		}
+		if e, a := r.Key, r.Metadata["owner"]; e != a {
+			t.Errorf("%d, expect %v metadata, got %v", i, e, a)
+		}
+	}
+}
+
+func TestExportInventory_Resume(t *testing.T) {
+	var buf bytes.Buffer
+	exporter := s3manager.NewInventoryExporterWithClient(inventorySvc(aws.String("a2")))
+	input := &s3manager.ExportInventoryInput{
+		Bucket:     aws.String("bucket"),
+		Writer:     &buf,
+		Format:     s3manager.InventoryFormatJSONLines,
+		HeadObject: true,
+	}
+	out, err := exporter.ExportInventory(input)
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := int64(2), out.Count; e != a {
+		t.Errorf("expect %v records, got %v", e, a)
+	}
+	if e, a := "a1", out.Markers[""]; e != a {
+		t.Errorf("expect %v marker, got %v", e, a)
+	}
+
+	input.Markers = out.Markers
+	exporter.S3 = inventorySvc(nil)
+	out, err = exporter.ExportInventory(input)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := int64(len(inventoryKeys)-2), out.Count; e != a {
+		t.Errorf("expect %v records, got %v", e, a)
+	}
+	if e, a := len(inventoryKeys), strings.Count(buf.String(), "\n"); e != a {
+		t.Errorf("expect %v lines, got %v", e, a)
+	}
+}
+
+func TestExportInventory_InvalidFormat(t *testing.T) {
+	exporter := s3manager.NewInventoryExporterWithClient(inventorySvc(nil))
+	_, err := exporter.ExportInventory(&s3manager.ExportInventoryInput{
+		Bucket: aws.String("bucket"),
+		Writer: ioutil.Discard,
+		Format: "xml",
+	})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := s3manager.ErrCodeInvalidInventoryFormat, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
@@ -0,0 +1,240 @@
+package s3manager
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// DefaultJanitorConcurrency is the default number of goroutines to spin up
+// when aborting multipart uploads with the UploadJanitor.
+const DefaultJanitorConcurrency = 5
+
+// The UploadJanitor aborts stale in-progress multipart uploads, whose parts
+// are stored, and billed for, until the upload is completed or aborted. It is
+// safe to call AbortStaleUploads on this structure for multiple buckets and
+// across concurrent goroutines. Mutating the UploadJanitor's properties is
+// not safe to be done concurrently.
+type UploadJanitor struct {
+	// The number of goroutines to spin up in parallel when aborting uploads.
+	// If this is set to zero, the DefaultJanitorConcurrency value will be
+	// used.
+	Concurrency int
+
+	// Setting this value to true will only list the stale uploads, without
+	// aborting them. The uploads that would be aborted are returned as usual.
+	DryRun bool
+
+	// An S3 client to use when listing and aborting uploads.
+	S3 s3iface.S3API
+
+	// List of request options that will be passed down to individual API
+	// operation requests made by the janitor.
+	RequestOptions []request.Option
+}
+
+// WithUploadJanitorRequestOptions appends to the UploadJanitor's API request
+// options.
+func WithUploadJanitorRequestOptions(opts ...request.Option) func(*UploadJanitor) {
+	return func(j *UploadJanitor) {
+		j.RequestOptions = append(j.RequestOptions, opts...)
+	}
+}
+
+// NewUploadJanitor creates a new UploadJanitor instance to abort stale
+// multipart uploads with. Pass in additional functional options to customize
+// the janitor behavior.
+//
+// Example:
+//     // The session the S3 UploadJanitor will use
+//     sess := session.Must(session.NewSession())
+//
+//     // Create a janitor with the session, listing the uploads it would
+//     // abort without aborting them.
+//     janitor := s3manager.NewUploadJanitor(sess, func(j *s3manager.UploadJanitor) {
+//          j.DryRun = true
+//     })
+func NewUploadJanitor(c client.ConfigProvider, options ...func(*UploadJanitor)) *UploadJanitor {
+	return NewUploadJanitorWithClient(s3.New(c), options...)
+}
+
+// NewUploadJanitorWithClient creates a new UploadJanitor instance to abort
+// stale multipart uploads with the S3 service client provided.
+//
+// Example:
+//     // The session the S3 UploadJanitor will use
+//     sess := session.Must(session.NewSession())
+//
+//     // S3 service client the UploadJanitor will use
+//     s3Svc := s3.New(sess)
+//
+//     // Create a janitor with S3 client and custom concurrency
+//     janitor := s3manager.NewUploadJanitorWithClient(s3Svc, func(j *s3manager.UploadJanitor) {
+//          j.Concurrency = 10
+//     })
+func NewUploadJanitorWithClient(svc s3iface.S3API, options ...func(*UploadJanitor)) *UploadJanitor {
+	j := &UploadJanitor{
+		S3:          svc,
+		Concurrency: DefaultJanitorConcurrency,
+	}
+
+	for _, option := range options {
+		option(j)
+	}
+
+	return j
+}
+
+// AbortStaleUploadsInput provides the bucket, and optional key prefix, of the
+// multipart uploads to abort, and how old uploads must be to be aborted.
+type AbortStaleUploadsInput struct {
+	// The bucket of the uploads.
+	Bucket *string
+
+	// Only uploads of keys beginning with the prefix are aborted, if set.
+	Prefix *string
+
+	// Only uploads initiated longer than OlderThan ago are aborted.
+	OlderThan time.Duration
+}
+
+// AbortStaleUploadsOutput represents a response from the AbortStaleUploads()
+// call.
+type AbortStaleUploadsOutput struct {
+	// The stale uploads aborted, or the uploads that would be aborted if the
+	// janitor is in dry-run mode.
+	Uploads []*s3.MultipartUpload
+}
+
+// AbortStaleUploads lists the bucket's in-progress multipart uploads, and
+// aborts the uploads initiated longer ago than the input's OlderThan
+// duration concurrently. If the janitor is in dry-run mode the uploads are
+// only listed.
+//
+// The uploads that could not be aborted are returned as a BatchError, along
+// with the output of the uploads which were aborted.
+//
+// Additional functional options can be provided to configure the individual
+// call. These options are copies of the UploadJanitor instance
+// AbortStaleUploads is called from. Modifying the options will not impact the
+// original UploadJanitor instance.
+//
+// It is safe to call this method concurrently across goroutines.
+//
+// Example:
+//     // Abort the uploads of the bucket initiated more than a week ago.
+//     result, err := janitor.AbortStaleUploads(&s3manager.AbortStaleUploadsInput{
+//         Bucket:    aws.String("bucket"),
+//         OlderThan: 7 * 24 * time.Hour,
+//     })
+func (j UploadJanitor) AbortStaleUploads(input *AbortStaleUploadsInput, options ...func(*UploadJanitor)) (*AbortStaleUploadsOutput, error) {
+	return j.AbortStaleUploadsWithContext(aws.BackgroundContext(), input, options...)
+}
+
+// AbortStaleUploadsWithContext lists and aborts stale multipart uploads the
+// same as AbortStaleUploads, with the addition of the ability to pass a
+// context and additional request options.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur. In the future the SDK may create
+// sub-contexts for http.Requests. See https://golang.org/pkg/context/
+// for more information on using Contexts.
+func (j UploadJanitor) AbortStaleUploadsWithContext(ctx aws.Context, input *AbortStaleUploadsInput, options ...func(*UploadJanitor)) (*AbortStaleUploadsOutput, error) {
+	for _, option := range options {
+		option(&j)
+	}
+	j.RequestOptions = append(j.RequestOptions, request.WithAppendUserAgent("S3Manager"))
+
+	uploads, err := j.listStaleUploads(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if j.DryRun {
+		return &AbortStaleUploadsOutput{Uploads: uploads}, nil
+	}
+
+	return j.abortUploads(ctx, input.Bucket, uploads)
+}
+
+// listStaleUploads returns the bucket's uploads initiated before the input's
+// OlderThan duration.
+func (j UploadJanitor) listStaleUploads(ctx aws.Context, input *AbortStaleUploadsInput) ([]*s3.MultipartUpload, error) {
+	cutoff := time.Now().Add(-input.OlderThan)
+
+	var uploads []*s3.MultipartUpload
+	err := j.S3.ListMultipartUploadsPagesWithContext(ctx, &s3.ListMultipartUploadsInput{
+		Bucket: input.Bucket,
+		Prefix: input.Prefix,
+	}, func(page *s3.ListMultipartUploadsOutput, lastPage bool) bool {
+		for _, upload := range page.Uploads {
+			if upload.Initiated != nil && upload.Initiated.Before(cutoff) {
+				uploads = append(uploads, upload)
+			}
+		}
+		return true
+	}, j.RequestOptions...)
+
+	return uploads, err
+}
+
+// abortUploads aborts the uploads concurrently, returning the uploads which
+// were aborted, and a BatchError of those which could not be.
+func (j UploadJanitor) abortUploads(ctx aws.Context, bucket *string, uploads []*s3.MultipartUpload) (*AbortStaleUploadsOutput, error) {
+	concurrency := j.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultJanitorConcurrency
+	}
+
+	var (
+		wg      sync.WaitGroup
+		m       sync.Mutex
+		errs    []Error
+		aborted = make([]bool, len(uploads))
+		ch      = make(chan int)
+	)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range ch {
+				upload := uploads[idx]
+				_, err := j.S3.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+					Bucket:   bucket,
+					Key:      upload.Key,
+					UploadId: upload.UploadId,
+				}, j.RequestOptions...)
+				if err != nil {
+					m.Lock()
+					errs = append(errs, newError(err, bucket, upload.Key))
+					m.Unlock()
+					continue
+				}
+				aborted[idx] = true
+			}
+		}()
+	}
+
+	for i := range uploads {
+		ch <- i
+	}
+	close(ch)
+	wg.Wait()
+
+	out := &AbortStaleUploadsOutput{}
+	for i, upload := range uploads {
+		if aborted[i] {
+			out.Uploads = append(out.Uploads, upload)
+		}
+	}
+
+	if len(errs) != 0 {
+		return out, NewBatchError("AbortStaleUploadsError", "some uploads failed to abort", errs)
+	}
+	return out, nil
+}
@@ -0,0 +1,164 @@
+package s3manager_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+func janitorSvc(uploads []*s3.MultipartUpload, failKey string) (*s3.S3, *[]string) {
+	var m sync.Mutex
+	aborted := []string{}
+
+	svc := s3.New(unit.Session, &aws.Config{MaxRetries: aws.Int(0)})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Unmarshal.Clear()
+	svc.Handlers.UnmarshalMeta.Clear()
+	svc.Handlers.UnmarshalError.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte{})),
+		}
+
+		switch in := r.Params.(type) {
+		case *s3.ListMultipartUploadsInput:
+			// Two uploads per page.
+			start := 0
+			if in.KeyMarker != nil {
+				fmt.Sscanf(*in.KeyMarker, "key%d", &start)
+				start++
+			}
+			end := start + 2
+			if end > len(uploads) {
+				end = len(uploads)
+			}
+			out := r.Data.(*s3.ListMultipartUploadsOutput)
+			out.Uploads = uploads[start:end]
+			out.IsTruncated = aws.Bool(end < len(uploads))
+			if end > 0 {
+				out.NextKeyMarker = uploads[end-1].Key
+				out.NextUploadIdMarker = uploads[end-1].UploadId
+			}
+		case *s3.AbortMultipartUploadInput:
+			if aws.StringValue(in.Key) == failKey {
+				r.Error = awserr.New("NoSuchUpload", "no such upload", nil)
+				return
+			}
+			m.Lock()
+			aborted = append(aborted, aws.StringValue(in.Key))
+			m.Unlock()
+		}
+	})
+
+	return svc, &aborted
+}
+
+func newJanitorUploads(ages ...time.Duration) []*s3.MultipartUpload {
+	uploads := []*s3.MultipartUpload{}
+	for i, age := range ages {
+		uploads = append(uploads, &s3.MultipartUpload{
+			Key:       aws.String(fmt.Sprintf("key%d", i)),
+			UploadId:  aws.String(fmt.Sprintf("upload%d", i)),
+			Initiated: aws.Time(time.Now().Add(-age)),
+		})
+	}
+	return uploads
+}
+
+func uploadKeys(uploads []*s3.MultipartUpload) []string {
+	keys := []string{}
+	for _, u := range uploads {
+		keys = append(keys, aws.StringValue(u.Key))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestUploadJanitorAbortStaleUploads(t *testing.T) {
+	uploads := newJanitorUploads(48*time.Hour, time.Minute, 72*time.Hour, 25*time.Hour, time.Hour)
+	svc, aborted := janitorSvc(uploads, "")
+
+	janitor := s3manager.NewUploadJanitorWithClient(svc, func(j *s3manager.UploadJanitor) {
+		j.Concurrency = 2
+	})
+	out, err := janitor.AbortStaleUploads(&s3manager.AbortStaleUploadsInput{
+		Bucket:    aws.String("bucket"),
+		OlderThan: 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := []string{"key0", "key2", "key3"}
+	if e, a := fmt.Sprint(expect), fmt.Sprint(uploadKeys(out.Uploads)); e != a {
+		t.Errorf("expect %v uploads, got %v", e, a)
+	}
+	sort.Strings(*aborted)
+	if e, a := fmt.Sprint(expect), fmt.Sprint(*aborted); e != a {
+		t.Errorf("expect %v aborted, got %v", e, a)
+	}
+}
+
+func TestUploadJanitorDryRun(t *testing.T) {
+	uploads := newJanitorUploads(48*time.Hour, time.Minute, 72*time.Hour)
+	svc, aborted := janitorSvc(uploads, "")
+
+	janitor := s3manager.NewUploadJanitorWithClient(svc)
+	out, err := janitor.AbortStaleUploads(&s3manager.AbortStaleUploadsInput{
+		Bucket:    aws.String("bucket"),
+		OlderThan: 24 * time.Hour,
+	}, func(j *s3manager.UploadJanitor) {
+		j.DryRun = true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := "[key0 key2]", fmt.Sprint(uploadKeys(out.Uploads)); e != a {
+		t.Errorf("expect %v uploads, got %v", e, a)
+	}
+	if e, a := 0, len(*aborted); e != a {
+		t.Errorf("expect no uploads aborted in dry run, got %v", a)
+	}
+}
+
+func TestUploadJanitorAbortError(t *testing.T) {
+	uploads := newJanitorUploads(48*time.Hour, 48*time.Hour, 48*time.Hour)
+	svc, _ := janitorSvc(uploads, "key1")
+
+	janitor := s3manager.NewUploadJanitorWithClient(svc)
+	out, err := janitor.AbortStaleUploads(&s3manager.AbortStaleUploadsInput{
+		Bucket:    aws.String("bucket"),
+		OlderThan: 24 * time.Hour,
+	})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+
+	batchErr, ok := err.(*s3manager.BatchError)
+	if !ok {
+		t.Fatalf("expect *s3manager.BatchError, got %T", err)
+	}
+	if e, a := 1, len(batchErr.Errors); e != a {
+		t.Fatalf("expect %v errors, got %v", e, a)
+	}
+	if e, a := "key1", aws.StringValue(batchErr.Errors[0].Key); e != a {
+		t.Errorf("expect %v failed key, got %v", e, a)
+	}
+	if e, a := "[key0 key2]", fmt.Sprint(uploadKeys(out.Uploads)); e != a {
+		t.Errorf("expect %v uploads, got %v", e, a)
+	}
+}
@@ -0,0 +1,131 @@
+package s3manager
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// ListObjectsIterator iterates through the objects listed by ListObjectsV2,
+// requesting the next page of objects only once the objects of the current
+// page have been iterated through. Only one page of objects is held in memory
+// at a time, regardless of the number of objects listed.
+//
+// Example:
+//     iter := s3manager.NewListObjectsIterator(ctx, svc, &s3.ListObjectsV2Input{
+//         Bucket: aws.String("bucket"),
+//         Prefix: aws.String("inventory/"),
+//     })
+//     for iter.Next() {
+//         obj := iter.Object()
+//         fmt.Println(aws.StringValue(obj.Key), aws.Int64Value(obj.Size))
+//     }
+//     if err := iter.Err(); err != nil {
+//         return err
+//     }
+type ListObjectsIterator struct {
+	Paginator request.Pagination
+	objects   []*s3.Object
+	started   bool
+}
+
+// NewListObjectsIterator returns a ListObjectsIterator listing the objects
+// of the input's bucket. The context is used for the ListObjectsV2 requests,
+// cancelling the iteration when the context is cancelled.
+func NewListObjectsIterator(ctx aws.Context, svc s3iface.S3API, input *s3.ListObjectsV2Input, opts ...request.Option) *ListObjectsIterator {
+	return &ListObjectsIterator{
+		Paginator: request.Pagination{
+			NewRequest: func() (*request.Request, error) {
+				var inCpy *s3.ListObjectsV2Input
+				if input != nil {
+					tmp := *input
+					inCpy = &tmp
+				}
+				req, _ := svc.ListObjectsV2Request(inCpy)
+				req.SetContext(ctx)
+				req.ApplyOptions(opts...)
+				return req, nil
+			},
+		},
+	}
+}
+
+// Next advances the iterator to the next object, requesting the next page
+// of objects if needed. Next returns false once all objects have been
+// iterated through, or a page could not be retrieved.
+func (iter *ListObjectsIterator) Next() bool {
+	if iter.started && len(iter.objects) > 0 {
+		iter.objects = iter.objects[1:]
+	}
+	iter.started = true
+
+	// Pages may be empty, such as the pages of a delimited listing whose
+	// keys are all grouped into common prefixes.
+	for len(iter.objects) == 0 && iter.Paginator.Next() {
+		iter.objects = iter.Paginator.Page().(*s3.ListObjectsV2Output).Contents
+	}
+
+	return len(iter.objects) > 0
+}
+
+// Err returns the error of the page request which ended the iteration, if
+// any.
+func (iter *ListObjectsIterator) Err() error {
+	return iter.Paginator.Err()
+}
+
+// Object returns the current object. Object should only be called after a
+// call to Next returned true.
+func (iter *ListObjectsIterator) Object() *s3.Object {
+	return iter.objects[0]
+}
+
+// ListObjectsChannel lists the objects of the input's bucket with
+// ListObjectsV2, sending the objects on the channel returned as they are
+// listed. The next page of objects is only requested once the objects of the
+// current page have been received, so only one page of objects is held in
+// memory at a time.
+//
+// The objects channel is closed once all objects have been listed, the
+// listing fails, or the context is cancelled. The error channel then receives
+// the error which ended the listing, or nil if all objects were listed.
+// Cancel the context to stop the listing early.
+//
+// Example:
+//     ctx, cancel := context.WithCancel(context.Background())
+//     defer cancel()
+//
+//     objects, errc := s3manager.ListObjectsChannel(ctx, svc, &s3.ListObjectsV2Input{
+//         Bucket: aws.String("bucket"),
+//     })
+//     for obj := range objects {
+//         fmt.Println(aws.StringValue(obj.Key))
+//     }
+//     if err := <-errc; err != nil {
+//         return err
+//     }
+func ListObjectsChannel(ctx aws.Context, svc s3iface.S3API, input *s3.ListObjectsV2Input, opts ...request.Option) (<-chan *s3.Object, <-chan error) {
+	objects := make(chan *s3.Object)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(objects)
+
+		iter := NewListObjectsIterator(ctx, svc, input, opts...)
+		for iter.Next() {
+			select {
+			case objects <- iter.Object():
+			case <-ctx.Done():
+				errc <- awserr.New(request.CanceledErrorCode,
+					"listing objects canceled", ctx.Err())
+				return
+			}
+		}
+		errc <- iter.Err()
+	}()
+
+	return objects, errc
+}
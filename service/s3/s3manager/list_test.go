@@ -0,0 +1,138 @@
+package s3manager_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// listSvc returns a client listing the pages of keys provided, and the
+// number of ListObjectsV2 requests made.
+func listSvc(pages [][]string) (*s3.S3, func() int) {
+	var m sync.Mutex
+	requests := 0
+
+	svc := s3.New(unit.Session)
+	svc.Handlers.Unmarshal.Clear()
+	svc.Handlers.UnmarshalMeta.Clear()
+	svc.Handlers.UnmarshalError.Clear()
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		m.Lock()
+		defer m.Unlock()
+
+		page := 0
+		if token := r.Params.(*s3.ListObjectsV2Input).ContinuationToken; token != nil {
+			fmt.Sscanf(*token, "page%d", &page)
+		}
+		requests++
+
+		r.HTTPResponse = &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte{})),
+		}
+
+		data := r.Data.(*s3.ListObjectsV2Output)
+		for _, key := range pages[page] {
+			data.Contents = append(data.Contents, &s3.Object{Key: aws.String(key)})
+		}
+		if page < len(pages)-1 {
+			data.IsTruncated = aws.Bool(true)
+			data.NextContinuationToken = aws.String(fmt.Sprintf("page%d", page+1))
+		}
+	})
+
+	return svc, func() int {
+		m.Lock()
+		defer m.Unlock()
+		return requests
+	}
+}
+
+func TestListObjectsIterator(t *testing.T) {
+	svc, requests := listSvc([][]string{{"a", "b"}, {}, {"c"}})
+
+	iter := s3manager.NewListObjectsIterator(aws.BackgroundContext(), svc, &s3.ListObjectsV2Input{
+		Bucket: aws.String("bucket"),
+	})
+
+	var keys []string
+	for iter.Next() {
+		keys = append(keys, aws.StringValue(iter.Object().Key))
+		if len(keys) == 1 {
+			if e, a := 1, requests(); e != a {
+				t.Errorf("expect %v requests before the first page is iterated, got %v", e, a)
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := []string{"a", "b", "c"}, keys; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v keys, got %v", e, a)
+	}
+	if e, a := 3, requests(); e != a {
+		t.Errorf("expect %v requests, got %v", e, a)
+	}
+}
+
+func TestListObjectsChannel(t *testing.T) {
+	svc, _ := listSvc([][]string{{"a", "b"}, {"c", "d"}})
+
+	objects, errc := s3manager.ListObjectsChannel(aws.BackgroundContext(), svc, &s3.ListObjectsV2Input{
+		Bucket: aws.String("bucket"),
+	})
+
+	var keys []string
+	for obj := range objects {
+		keys = append(keys, aws.StringValue(obj.Key))
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := []string{"a", "b", "c", "d"}, keys; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v keys, got %v", e, a)
+	}
+}
+
+func TestListObjectsChannelCanceled(t *testing.T) {
+	svc, requests := listSvc([][]string{{"a", "b"}, {"c", "d"}})
+	ctx := &awstesting.FakeContext{DoneCh: make(chan struct{})}
+
+	objects, errc := s3manager.ListObjectsChannel(ctx, svc, &s3.ListObjectsV2Input{
+		Bucket: aws.String("bucket"),
+	})
+
+	if obj := <-objects; aws.StringValue(obj.Key) != "a" {
+		t.Fatalf("expect first object a, got %v", obj)
+	}
+	ctx.Error = fmt.Errorf("context canceled")
+	close(ctx.DoneCh)
+
+	// The listing stops without the remaining objects being received.
+	err := <-errc
+	aerr, ok := err.(awserr.Error)
+	if !ok {
+		t.Fatalf("expect awserr.Error, got %v", err)
+	}
+	if e, a := request.CanceledErrorCode, aerr.Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+	if e, a := 1, requests(); e != a {
+		t.Errorf("expect %v requests, got %v", e, a)
+	}
+}
@@ -0,0 +1,193 @@
+package s3manager
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+// Formats the UploadManifest is written in.
+const (
+	// ManifestFormatCSV writes the manifest as CSV, with a header row.
+	ManifestFormatCSV = "csv"
+
+	// ManifestFormatJSONLines writes the manifest as JSON lines, a JSON
+	// object per object uploaded.
+	ManifestFormatJSONLines = "jsonl"
+)
+
+// ErrCodeInvalidManifestFormat is the error code returned when the format
+// of an upload manifest is not a supported format.
+const ErrCodeInvalidManifestFormat = "InvalidManifestFormat"
+
+// manifestCSVHeader is the header row of CSV manifests.
+var manifestCSVHeader = []string{"bucket", "key", "size", "sha256", "version_id"}
+
+// An UploadManifestRecord is the manifest record of an object uploaded,
+// written as a row of CSV manifests, or a line of JSON lines manifests.
+type UploadManifestRecord struct {
+	Bucket string `json:"bucket"`
+	Key    string `json:"key"`
+
+	// The size, and hex encoded SHA-256 digest, of the object's content as
+	// uploaded. The content of uploads compressed by the Uploader is the
+	// compressed body.
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+
+	// The version of the object uploaded, only set if the bucket is
+	// versioned.
+	VersionID string `json:"version_id,omitempty"`
+}
+
+// An UploadManifest records the objects uploaded by an Uploader, such as
+// the uploads of a batch upload or of a Syncer, to be written to a file or
+// an object for the verification and audit of the uploads. Only uploads
+// which succeed are recorded. It is safe to use concurrently, and may be
+// shared by multiple Uploaders.
+//
+// Example:
+//     manifest := &s3manager.UploadManifest{Format: s3manager.ManifestFormatJSONLines}
+//     syncer := s3manager.NewSyncer(sess, func(s *s3manager.Syncer) {
+//          s.Uploader.Manifest = manifest
+//     })
+//
+//     _, err := syncer.Upload(&s3manager.SyncInput{...})
+//     ...
+//     err = manifest.WriteFile("manifest.jsonl")
+type UploadManifest struct {
+	// The format of the manifest, ManifestFormatCSV or
+	// ManifestFormatJSONLines. Defaults to ManifestFormatCSV.
+	Format string
+
+	m       sync.Mutex
+	records []UploadManifestRecord
+}
+
+// WithUploadManifest sets the manifest the Uploader records its uploads in.
+func WithUploadManifest(m *UploadManifest) func(*Uploader) {
+	return func(u *Uploader) {
+		u.Manifest = m
+	}
+}
+
+// Records returns the records of the objects uploaded so far, sorted by
+// bucket and key.
+func (m *UploadManifest) Records() []UploadManifestRecord {
+	m.m.Lock()
+	records := append([]UploadManifestRecord(nil), m.records...)
+	m.m.Unlock()
+
+	sort.Stable(manifestRecords(records))
+	return records
+}
+
+// manifestRecords is a wrapper to make records sortable by their bucket and
+// key.
+type manifestRecords []UploadManifestRecord
+
+func (a manifestRecords) Len() int      { return len(a) }
+func (a manifestRecords) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a manifestRecords) Less(i, j int) bool {
+	if a[i].Bucket != a[j].Bucket {
+		return a[i].Bucket < a[j].Bucket
+	}
+	return a[i].Key < a[j].Key
+}
+
+// Encode writes the records of the objects uploaded so far to the writer,
+// in the manifest's format.
+func (m *UploadManifest) Encode(w io.Writer) error {
+	records := m.Records()
+
+	switch m.Format {
+	case "", ManifestFormatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write(manifestCSVHeader)
+		for _, r := range records {
+			cw.Write([]string{
+				r.Bucket,
+				r.Key,
+				strconv.FormatInt(r.Size, 10),
+				r.SHA256,
+				r.VersionID,
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	case ManifestFormatJSONLines:
+		enc := json.NewEncoder(w)
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return awserr.New(ErrCodeInvalidManifestFormat,
+			"unsupported manifest format, "+m.Format, nil)
+	}
+}
+
+// WriteFile writes the manifest to the named file, creating or truncating
+// it.
+func (m *UploadManifest) WriteFile(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	err = m.Encode(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// UploadObject writes the manifest to an object, uploaded with the Uploader
+// provided. The manifest object itself is not recorded in the manifest.
+//
+// Example:
+//     _, err := manifest.UploadObject(ctx, uploader, "audit", "manifests/2006-01-02.csv")
+func (m *UploadManifest) UploadObject(ctx aws.Context, u *Uploader, bucket, key string) (*UploadOutput, error) {
+	var buf bytes.Buffer
+	if err := m.Encode(&buf); err != nil {
+		return nil, err
+	}
+
+	contentType := "text/csv"
+	if m.Format == ManifestFormatJSONLines {
+		contentType = "application/x-ndjson"
+	}
+
+	return u.UploadWithContext(ctx, &UploadInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(buf.Bytes()),
+		ContentType: aws.String(contentType),
+	}, WithUploadManifest(nil))
+}
+
+// record records the upload of the object, of the size and digest provided.
+func (m *UploadManifest) record(in *UploadInput, size int64, digest hash.Hash, out *UploadOutput) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	m.records = append(m.records, UploadManifestRecord{
+		Bucket:    aws.StringValue(in.Bucket),
+		Key:       aws.StringValue(in.Key),
+		Size:      size,
+		SHA256:    hex.EncodeToString(digest.Sum(nil)),
+		VersionID: aws.StringValue(out.VersionID),
+	})
+}
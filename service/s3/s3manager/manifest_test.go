@@ -0,0 +1,125 @@
+package s3manager_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+func TestUploadManifest(t *testing.T) {
+	s, _, _ := loggingSvc(emptyList)
+	manifest := &s3manager.UploadManifest{}
+	u := s3manager.NewUploaderWithClient(s, s3manager.WithUploadManifest(manifest))
+
+	multi := bytes.Repeat([]byte("abcdefgh"), 1024*1024*12/8)
+	small := []byte("small object")
+	cases := map[string]io.Reader{
+		"multi":    bytes.NewReader(multi),
+		"buffered": io.MultiReader(bytes.NewReader(multi)),
+		"single":   bytes.NewReader(small),
+	}
+	for key, body := range cases {
+		if _, err := u.Upload(&s3manager.UploadInput{
+			Bucket: aws.String("Bucket"),
+			Key:    aws.String(key),
+			Body:   body,
+		}); err != nil {
+			t.Fatalf("%s, expect no error, got %v", key, err)
+		}
+	}
+
+	digest := func(b []byte) string {
+		sum := sha256.Sum256(b)
+		return hex.EncodeToString(sum[:])
+	}
+	expect := []s3manager.UploadManifestRecord{
+		{Bucket: "Bucket", Key: "buffered", Size: int64(len(multi)), SHA256: digest(multi), VersionID: "VERSION-ID"},
+		{Bucket: "Bucket", Key: "multi", Size: int64(len(multi)), SHA256: digest(multi), VersionID: "VERSION-ID"},
+		{Bucket: "Bucket", Key: "single", Size: int64(len(small)), SHA256: digest(small), VersionID: "VERSION-ID"},
+	}
+	records := manifest.Records()
+	if e, a := len(expect), len(records); e != a {
+		t.Fatalf("expect %v records, got %v", e, a)
+	}
+	for i := range expect {
+		if e, a := expect[i], records[i]; e != a {
+			t.Errorf("%d, expect %v, got %v", i, e, a)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := manifest.Encode(&buf); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if e, a := "bucket,key,size,sha256,version_id", lines[0]; e != a {
+		t.Errorf("expect %v header, got %v", e, a)
+	}
+	if e, a := "Bucket,single,12,"+digest(small)+",VERSION-ID", lines[3]; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	manifest.Format = s3manager.ManifestFormatJSONLines
+	buf.Reset()
+	if err := manifest.Encode(&buf); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if e, a := `{"bucket":"Bucket","key":"single","size":12,"sha256":"`+digest(small)+`","version_id":"VERSION-ID"}`, lines[2]; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	manifest.Format = "xml"
+	err := manifest.Encode(&buf)
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := s3manager.ErrCodeInvalidManifestFormat, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
+
+func TestUploadManifestUploadObject(t *testing.T) {
+	s, ops, args := loggingSvc(emptyList)
+	manifest := &s3manager.UploadManifest{Format: s3manager.ManifestFormatJSONLines}
+	u := s3manager.NewUploaderWithClient(s, s3manager.WithUploadManifest(manifest))
+
+	if _, err := u.Upload(&s3manager.UploadInput{
+		Bucket: aws.String("Bucket"),
+		Key:    aws.String("key"),
+		Body:   strings.NewReader("data"),
+	}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if _, err := manifest.UploadObject(aws.BackgroundContext(), u, "audit", "manifest.jsonl"); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 1, len(manifest.Records()); e != a {
+		t.Errorf("expect %v records, got %v", e, a)
+	}
+	if e, a := []string{"PutObject", "PutObject"}, *ops; len(a) != 2 || e[1] != a[1] {
+		t.Fatalf("expect %v ops, got %v", e, a)
+	}
+
+	params := (*args)[1].(*s3.PutObjectInput)
+	if e, a := "audit", aws.StringValue(params.Bucket); e != a {
+		t.Errorf("expect %v bucket, got %v", e, a)
+	}
+	if e, a := "application/x-ndjson", aws.StringValue(params.ContentType); e != a {
+		t.Errorf("expect %v content type, got %v", e, a)
+	}
+	body, _ := ioutil.ReadAll(params.Body)
+	if e, a := `"key":"key"`, string(body); !strings.Contains(a, e) {
+		t.Errorf("expect %v to contain %v", a, e)
+	}
+}
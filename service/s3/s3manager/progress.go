@@ -0,0 +1,162 @@
+package s3manager
+
+import (
+	"sync"
+	"time"
+)
+
+// progressRateWindow is the minimum period the transfer rate reported in
+// Progress is measured over.
+const progressRateWindow = time.Second
+
+// Progress is a snapshot of the progress of an upload or download, reported
+// to the Uploader's and Downloader's OnProgress callback each time a part of
+// the object has been transferred.
+type Progress struct {
+	// The number of bytes transferred so far.
+	BytesTransferred int64
+
+	// The total number of bytes to transfer, or -1 if it is not yet known.
+	// The total is not known before the body of an upload of unknown size
+	// has been read entirely.
+	TotalBytes int64
+
+	// The number of parts transferred so far.
+	PartsCompleted int64
+
+	// The total number of parts to transfer, or -1 if it is not yet known.
+	TotalParts int64
+
+	// The transfer rate, in bytes per second, measured over the last second
+	// or so of the transfer.
+	BytesPerSecond float64
+
+	// The time elapsed since the transfer started.
+	Elapsed time.Duration
+}
+
+// ProgressFunc is called with the progress of a transfer each time a part of
+// the object has been transferred. The function is called from the
+// transfer's goroutines, one call at a time, and must not block.
+type ProgressFunc func(Progress)
+
+// ProgressChannel returns a ProgressFunc sending the progress reports of a
+// transfer to the channel provided. Reports are dropped, rather than blocking
+// the transfer, if the channel is not ready to receive them.
+//
+// Example:
+//     ch := make(chan s3manager.Progress, 1)
+//     go func() {
+//         for p := range ch {
+//             fmt.Printf("%d/%d bytes, %.0f B/s\n", p.BytesTransferred, p.TotalBytes, p.BytesPerSecond)
+//         }
+//     }()
+//
+//     _, err := uploader.Upload(upParams, func(u *s3manager.Uploader) {
+//         u.OnProgress = s3manager.ProgressChannel(ch)
+//     })
+//     close(ch)
+func ProgressChannel(ch chan<- Progress) ProgressFunc {
+	return func(p Progress) {
+		select {
+		case ch <- p:
+		default:
+		}
+	}
+}
+
+// progressTracker accumulates the progress of a transfer and reports it to a
+// ProgressFunc. A nil progressTracker ignores all updates, so that transfers
+// without an OnProgress callback do not need to check for one.
+type progressTracker struct {
+	fn  ProgressFunc
+	now func() time.Time
+
+	m     sync.Mutex
+	p     Progress
+	start time.Time
+
+	// The point in the transfer the rate is measured from.
+	rateTime  time.Time
+	rateBytes int64
+}
+
+// newProgressTracker returns a progressTracker reporting to fn, or nil if fn
+// is nil.
+func newProgressTracker(fn ProgressFunc) *progressTracker {
+	if fn == nil {
+		return nil
+	}
+
+	t := &progressTracker{fn: fn, now: time.Now}
+	t.start = t.now()
+	t.rateTime = t.start
+	t.p.TotalBytes = -1
+	t.p.TotalParts = -1
+
+	return t
+}
+
+// setTotal sets the total number of bytes and parts of the transfer.
+func (t *progressTracker) setTotal(bytes, parts int64) {
+	if t == nil {
+		return
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	t.p.TotalBytes = bytes
+	t.p.TotalParts = parts
+}
+
+// resume records bytes and parts transferred before the transfer started,
+// such as those of an earlier attempt of a resumable download. They are not
+// included in the transfer rate.
+func (t *progressTracker) resume(bytes, parts int64) {
+	if t == nil {
+		return
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	t.p.BytesTransferred += bytes
+	t.p.PartsCompleted += parts
+	t.rateBytes += bytes
+}
+
+// partCompleted records a part of n bytes as transferred, and reports the
+// transfer's progress.
+func (t *progressTracker) partCompleted(n int64) {
+	if t == nil {
+		return
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	now := t.now()
+	t.p.BytesTransferred += n
+	t.p.PartsCompleted++
+	t.p.Elapsed = now.Sub(t.start)
+
+	if elapsed := now.Sub(t.rateTime); elapsed > 0 {
+		t.p.BytesPerSecond = float64(t.p.BytesTransferred-t.rateBytes) / elapsed.Seconds()
+		if elapsed >= progressRateWindow {
+			t.rateTime = now
+			t.rateBytes = t.p.BytesTransferred
+		}
+	}
+
+	t.fn(t.p)
+}
+
+// partCount returns the number of parts of size partSize a transfer of size
+// bytes is made of. A transfer has at least one part, even if empty.
+func partCount(size, partSize int64) int64 {
+	if size <= partSize {
+		return 1
+	}
+	return (size + partSize - 1) / partSize
+}
@@ -0,0 +1,69 @@
+package s3manager
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProgressTrackerRate(t *testing.T) {
+	var reports []Progress
+	tr := newProgressTracker(func(p Progress) {
+		reports = append(reports, p)
+	})
+
+	now := tr.start
+	tr.now = func() time.Time { return now }
+	tr.setTotal(300, 3)
+
+	now = now.Add(500 * time.Millisecond)
+	tr.partCompleted(100)
+	now = now.Add(500 * time.Millisecond)
+	tr.partCompleted(100)
+	now = now.Add(250 * time.Millisecond)
+	tr.partCompleted(100)
+
+	// The rate is measured over the window since the second report.
+	expect := []float64{200, 200, 400}
+	for i, p := range reports {
+		if e, a := expect[i], p.BytesPerSecond; e != a {
+			t.Errorf("%d, expect %v bytes per second, got %v", i, e, a)
+		}
+	}
+	if e, a := 1250*time.Millisecond, reports[2].Elapsed; e != a {
+		t.Errorf("expect %v elapsed, got %v", e, a)
+	}
+}
+
+func TestProgressTrackerResume(t *testing.T) {
+	var last Progress
+	tr := newProgressTracker(func(p Progress) { last = p })
+
+	now := tr.start
+	tr.now = func() time.Time { return now }
+	tr.resume(200, 2)
+
+	now = now.Add(time.Second)
+	tr.partCompleted(100)
+
+	if e, a := int64(300), last.BytesTransferred; e != a {
+		t.Errorf("expect %v bytes transferred, got %v", e, a)
+	}
+	if e, a := int64(3), last.PartsCompleted; e != a {
+		t.Errorf("expect %v parts completed, got %v", e, a)
+	}
+	if e, a := float64(100), last.BytesPerSecond; e != a {
+		t.Errorf("expect %v bytes per second, got %v", e, a)
+	}
+}
+
+func TestProgressTrackerNil(t *testing.T) {
+	tr := newProgressTracker(nil)
+	if tr != nil {
+		t.Fatalf("expect nil tracker, got %v", tr)
+	}
+
+	// Updates of a nil tracker are ignored.
+	tr.setTotal(1, 1)
+	tr.resume(1, 1)
+	tr.partCompleted(1)
+}
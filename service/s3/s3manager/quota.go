@@ -0,0 +1,111 @@
+package s3manager
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+const (
+	// ErrCodeQuotaExceeded is the error code of the QuotaExceededError returned
+	// when an upload would exceed the hard quota of its bucket.
+	ErrCodeQuotaExceeded = "QuotaExceeded"
+
+	// ErrCodeQuotaCheck is the error code returned when the quota of an
+	// upload's bucket could not be checked.
+	ErrCodeQuotaCheck = "QuotaCheckError"
+)
+
+// DefaultQuotaCheckMinSize is the default size (in bytes) below which the
+// quota of an upload's bucket is not checked when the Uploader's QuotaCheck
+// is set.
+const DefaultQuotaCheckMinSize int64 = 100 * 1024 * 1024
+
+// BucketQuota is the hard quota and usage of a bucket.
+type BucketQuota struct {
+	// The maximum number of bytes the bucket may store, 0 if the bucket has
+	// no quota.
+	HardQuota int64
+
+	// The number of bytes stored in the bucket.
+	BytesUsed int64
+}
+
+// QuotaFunc returns the hard quota and usage of a bucket, such as the
+// function returned by cos.BucketQuotaFunc.
+type QuotaFunc func(ctx aws.Context, bucket string) (BucketQuota, error)
+
+// A QuotaExceededError is returned by the Uploader, before any data is
+// uploaded, when an upload would exceed the hard quota of its bucket.
+//
+//     _, err := uploader.Upload(input)
+//     if qerr, ok := err.(*s3manager.QuotaExceededError); ok {
+//         fmt.Println("bucket has", qerr.HardQuota-qerr.BytesUsed, "bytes left")
+//     }
+type QuotaExceededError struct {
+	// The bucket of the upload.
+	Bucket string
+
+	// The hard quota and usage of the bucket.
+	HardQuota int64
+	BytesUsed int64
+
+	// The size of the upload, which is a lower bound if the size of the
+	// upload's body is not known.
+	UploadSize int64
+}
+
+// Code returns the ErrCodeQuotaExceeded error code.
+func (e *QuotaExceededError) Code() string {
+	return ErrCodeQuotaExceeded
+}
+
+// Message returns the error message.
+func (e *QuotaExceededError) Message() string {
+	return fmt.Sprintf("upload of %d bytes exceeds the hard quota of bucket %s, %d of %d bytes used",
+		e.UploadSize, e.Bucket, e.BytesUsed, e.HardQuota)
+}
+
+// OrigErr returns nil, the error has no underlying error.
+func (e *QuotaExceededError) OrigErr() error {
+	return nil
+}
+
+// Error returns the string representation of the error.
+func (e *QuotaExceededError) Error() string {
+	return awserr.SprintError(e.Code(), e.Message(), "", nil)
+}
+
+// checkQuota returns a QuotaExceededError if the upload of size bytes would
+// exceed the hard quota of the upload's bucket. The quota is only checked if
+// the Uploader's QuotaCheck is set, and size is at least QuotaCheckMinSize.
+func (u *uploader) checkQuota(size int64) error {
+	if u.cfg.QuotaCheck == nil {
+		return nil
+	}
+
+	minSize := u.cfg.QuotaCheckMinSize
+	if minSize == 0 {
+		minSize = DefaultQuotaCheckMinSize
+	}
+	if size < minSize {
+		return nil
+	}
+
+	bucket := aws.StringValue(u.in.Bucket)
+	quota, err := u.cfg.QuotaCheck(u.ctx, bucket)
+	if err != nil {
+		return awserr.New(ErrCodeQuotaCheck, "failed to check bucket quota", err)
+	}
+	if quota.HardQuota > 0 && quota.BytesUsed+size > quota.HardQuota {
+		return &QuotaExceededError{
+			Bucket:     bucket,
+			HardQuota:  quota.HardQuota,
+			BytesUsed:  quota.BytesUsed,
+			UploadSize: size,
+		}
+	}
+
+	return nil
+}
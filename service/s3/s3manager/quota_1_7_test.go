@@ -0,0 +1,65 @@
+// +build go1.7
+
+package s3manager_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+func TestUploadQuotaNotExceeded(t *testing.T) {
+	cases := map[string]struct {
+		quota       s3manager.BucketQuota
+		minSize     int64
+		body        []byte
+		expectCalls int
+	}{
+		"under quota": {
+			quota:       s3manager.BucketQuota{HardQuota: 20 * 1024 * 1024},
+			minSize:     1024 * 1024,
+			body:        make([]byte, 1024*1024*12),
+			expectCalls: 1,
+		},
+		"no quota": {
+			quota:       s3manager.BucketQuota{BytesUsed: 20 * 1024 * 1024},
+			minSize:     1024 * 1024,
+			body:        make([]byte, 1024*1024*12),
+			expectCalls: 1,
+		},
+		"below min size": {
+			quota:   s3manager.BucketQuota{HardQuota: 1024},
+			body:    make([]byte, 1024*1024*12),
+			minSize: 0,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, ops, _ := loggingSvc(emptyList)
+
+			var calls int
+			mgr := s3manager.NewUploaderWithClient(s, func(u *s3manager.Uploader) {
+				u.QuotaCheck = quotaFunc(c.quota, nil, &calls)
+				u.QuotaCheckMinSize = c.minSize
+			})
+
+			_, err := mgr.Upload(&s3manager.UploadInput{
+				Bucket: aws.String("Bucket"),
+				Key:    aws.String("Key"),
+				Body:   bytes.NewReader(c.body),
+			})
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.expectCalls, calls; e != a {
+				t.Errorf("expect %v quota checks, got %v", e, a)
+			}
+			if e, a := 5, len(*ops); e != a {
+				t.Errorf("expect %v operations, got %v", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package s3manager_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+func quotaFunc(quota s3manager.BucketQuota, err error, calls *int) s3manager.QuotaFunc {
+	return func(ctx aws.Context, bucket string) (s3manager.BucketQuota, error) {
+		*calls++
+		return quota, err
+	}
+}
+
+func TestUploadQuotaExceeded(t *testing.T) {
+	s, ops, _ := loggingSvc(emptyList)
+
+	var calls int
+	mgr := s3manager.NewUploaderWithClient(s, func(u *s3manager.Uploader) {
+		u.QuotaCheck = quotaFunc(s3manager.BucketQuota{HardQuota: 20 * 1024 * 1024, BytesUsed: 10 * 1024 * 1024}, nil, &calls)
+		u.QuotaCheckMinSize = 1024 * 1024
+	})
+
+	_, err := mgr.Upload(&s3manager.UploadInput{
+		Bucket: aws.String("Bucket"),
+		Key:    aws.String("Key"),
+		Body:   bytes.NewReader(make([]byte, 1024*1024*12)),
+	})
+	qerr, ok := err.(*s3manager.QuotaExceededError)
+	if !ok {
+		t.Fatalf("expect quota exceeded error, got %v", err)
+	}
+	if e, a := s3manager.ErrCodeQuotaExceeded, qerr.Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+	if e, a := int64(1024*1024*12), qerr.UploadSize; e != a {
+		t.Errorf("expect %v upload size, got %v", e, a)
+	}
+	if e, a := "Bucket", qerr.Bucket; e != a {
+		t.Errorf("expect %v bucket, got %v", e, a)
+	}
+	if e, a := 1, calls; e != a {
+		t.Errorf("expect %v quota checks, got %v", e, a)
+	}
+	if e, a := 0, len(*ops); e != a {
+		t.Errorf("expect %v operations, got %v", e, a)
+	}
+}
+
+func TestUploadQuotaUnknownSize(t *testing.T) {
+	s, ops, _ := loggingSvc(emptyList)
+
+	var calls int
+	mgr := s3manager.NewUploaderWithClient(s, func(u *s3manager.Uploader) {
+		u.QuotaCheck = quotaFunc(s3manager.BucketQuota{HardQuota: 8 * 1024 * 1024, BytesUsed: 4 * 1024 * 1024}, nil, &calls)
+		u.QuotaCheckMinSize = 1024 * 1024
+	})
+
+	_, err := mgr.Upload(&s3manager.UploadInput{
+		Bucket: aws.String("Bucket"),
+		Key:    aws.String("Key"),
+		Body:   &sizedReader{size: 1024 * 1024 * 12},
+	})
+	qerr, ok := err.(*s3manager.QuotaExceededError)
+	if !ok {
+		t.Fatalf("expect quota exceeded error, got %v", err)
+	}
+	if e, a := int64(s3manager.DefaultUploadPartSize), qerr.UploadSize; e != a {
+		t.Errorf("expect %v upload size, got %v", e, a)
+	}
+	if e, a := 0, len(*ops); e != a {
+		t.Errorf("expect %v operations, got %v", e, a)
+	}
+}
+
+func TestUploadQuotaCheckError(t *testing.T) {
+	s, ops, _ := loggingSvc(emptyList)
+
+	var calls int
+	mgr := s3manager.NewUploaderWithClient(s, func(u *s3manager.Uploader) {
+		u.QuotaCheck = quotaFunc(s3manager.BucketQuota{}, errors.New("unavailable"), &calls)
+		u.QuotaCheckMinSize = 1024
+	})
+
+	_, err := mgr.Upload(&s3manager.UploadInput{
+		Bucket: aws.String("Bucket"),
+		Key:    aws.String("Key"),
+		Body:   bytes.NewReader(make([]byte, 1024*1024)),
+	})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := s3manager.ErrCodeQuotaCheck, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+	if e, a := 0, len(*ops); e != a {
+		t.Errorf("expect %v operations, got %v", e, a)
+	}
+}
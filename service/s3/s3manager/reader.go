@@ -0,0 +1,316 @@
+package s3manager
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// DefaultReaderBlockSize is the default size of the blocks an ObjectReader
+// reads an object in.
+const DefaultReaderBlockSize = 1024 * 1024
+
+// DefaultReaderCacheBlocks is the default number of blocks an ObjectReader
+// caches.
+const DefaultReaderCacheBlocks = 16
+
+// DefaultReaderReadAhead is the default number of blocks an ObjectReader
+// reads ahead of sequential reads.
+const DefaultReaderReadAhead = 2
+
+// ErrCodeInvalidOffset is the error code returned when an ObjectReader is
+// read at, or seeked to, a negative offset.
+const ErrCodeInvalidOffset = "InvalidOffset"
+
+// ObjectReaderInput provides the object an ObjectReader reads.
+type ObjectReaderInput struct {
+	// The bucket of the object.
+	Bucket *string
+
+	// The key of the object.
+	Key *string
+
+	// The version of the object, if set.
+	VersionId *string
+}
+
+// An ObjectReader reads an object with ranged GetObject requests, so that
+// files with indexes, such as parquet, zip, or indexed tar files, can be
+// read without downloading the whole object. It implements io.ReaderAt, and
+// io.ReadSeeker.
+//
+// The object is read in blocks of BlockSize bytes, the most recently used of
+// which are cached. Reads of consecutive blocks read the blocks following
+// them ahead in the background. The blocks are read conditional on the ETag
+// of the object when the reader is created, and reads fail with a
+// PreconditionFailed error if the object changes.
+//
+//     r, err := s3manager.NewObjectReader(svc, &s3manager.ObjectReaderInput{
+//         Bucket: aws.String(bucket),
+//         Key:    aws.String("archive.zip"),
+//     })
+//     if err != nil {
+//         return err
+//     }
+//     zr, err := zip.NewReader(r, r.Size())
+//
+// It is safe to call ReadAt concurrently. Read and Seek share the reader's
+// offset, and are not safe to call concurrently. Mutating the reader's
+// properties once it is used is not safe.
+type ObjectReader struct {
+	// The size of the blocks the object is read in. If this is set to zero,
+	// the DefaultReaderBlockSize value will be used.
+	BlockSize int64
+
+	// The number of blocks cached. If this is set to zero, the
+	// DefaultReaderCacheBlocks value will be used.
+	CacheBlocks int
+
+	// The number of blocks read ahead of sequential reads. Blocks are not
+	// read ahead if this is negative. If this is set to zero, the
+	// DefaultReaderReadAhead value will be used.
+	ReadAhead int
+
+	// List of request options that will be passed down to the GetObject
+	// requests made by the reader.
+	RequestOptions []request.Option
+
+	ctx       aws.Context
+	svc       s3iface.S3API
+	bucket    *string
+	key       *string
+	versionID *string
+	etag      *string
+	size      int64
+
+	m      sync.Mutex
+	blocks map[int64]*readerBlock
+	lru    *list.List
+	last   int64
+	off    int64
+}
+
+// readerBlock is a block of an object read by an ObjectReader. The data and
+// error of the block are set before done is closed.
+type readerBlock struct {
+	index int64
+	elem  *list.Element
+	done  chan struct{}
+	data  []byte
+	err   error
+}
+
+// NewObjectReader returns an ObjectReader of the object, whose size and
+// ETag are read with a HeadObject request. Pass in additional functional
+// options to customize the reader behavior.
+func NewObjectReader(svc s3iface.S3API, input *ObjectReaderInput, options ...func(*ObjectReader)) (*ObjectReader, error) {
+	return NewObjectReaderWithContext(aws.BackgroundContext(), svc, input, options...)
+}
+
+// NewObjectReaderWithContext returns an ObjectReader the same as
+// NewObjectReader. The context is used for the HeadObject request, and the
+// GetObject requests of the reader.
+func NewObjectReaderWithContext(ctx aws.Context, svc s3iface.S3API, input *ObjectReaderInput, options ...func(*ObjectReader)) (*ObjectReader, error) {
+	r := &ObjectReader{
+		BlockSize:   DefaultReaderBlockSize,
+		CacheBlocks: DefaultReaderCacheBlocks,
+		ReadAhead:   DefaultReaderReadAhead,
+	}
+	for _, option := range options {
+		option(r)
+	}
+	if r.BlockSize <= 0 {
+		r.BlockSize = DefaultReaderBlockSize
+	}
+	if r.CacheBlocks <= 0 {
+		r.CacheBlocks = DefaultReaderCacheBlocks
+	}
+	if r.ReadAhead == 0 {
+		r.ReadAhead = DefaultReaderReadAhead
+	}
+
+	head, err := svc.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket:    input.Bucket,
+		Key:       input.Key,
+		VersionId: input.VersionId,
+	}, r.RequestOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	r.ctx, r.svc = ctx, svc
+	r.bucket, r.key, r.versionID = input.Bucket, input.Key, input.VersionId
+	r.etag, r.size = head.ETag, aws.Int64Value(head.ContentLength)
+	r.blocks = map[int64]*readerBlock{}
+	r.lru = list.New()
+	r.last = -1
+	return r, nil
+}
+
+// Size returns the size of the object.
+func (r *ObjectReader) Size() int64 {
+	return r.size
+}
+
+// ReadAt reads len(p) bytes of the object at the offset, from the cached
+// blocks, or blocks read with GetObject requests.
+func (r *ObjectReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, awserr.New(ErrCodeInvalidOffset, "negative offset", nil)
+	}
+	if off >= r.size {
+		return 0, io.EOF
+	}
+
+	var n int
+	for n < len(p) && off < r.size {
+		b := r.block(off / r.BlockSize)
+		<-b.done
+		if b.err != nil {
+			return n, b.err
+		}
+
+		m := copy(p[n:], b.data[off-b.index*r.BlockSize:])
+		n += m
+		off += int64(m)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Read reads the object at the reader's offset, advancing the offset by the
+// number of bytes read.
+func (r *ObjectReader) Read(p []byte) (int, error) {
+	if r.off >= r.size {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.size-r.off {
+		p = p[:r.size-r.off]
+	}
+
+	n, err := r.ReadAt(p, r.off)
+	r.off += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// Seek sets the offset of the next Read, see io.Seeker.
+func (r *ObjectReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case 0: // io.SeekStart
+	case 1: // io.SeekCurrent
+		offset += r.off
+	case 2: // io.SeekEnd
+		offset += r.size
+	default:
+		return 0, awserr.New(ErrCodeInvalidOffset, fmt.Sprintf("invalid whence %d", whence), nil)
+	}
+	if offset < 0 {
+		return 0, awserr.New(ErrCodeInvalidOffset, "negative offset", nil)
+	}
+
+	r.off = offset
+	return offset, nil
+}
+
+// block returns the block of the index, reading it if it is not cached, and
+// reads the blocks following it ahead if the block follows the last block
+// returned, or is the first block.
+func (r *ObjectReader) block(index int64) *readerBlock {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	b := r.cachedBlock(index)
+	if index == r.last || index == r.last+1 {
+		// Blocks read ahead must not evict the block read.
+		ahead := r.ReadAhead
+		if ahead > r.CacheBlocks-1 {
+			ahead = r.CacheBlocks - 1
+		}
+		for i := int64(1); i <= int64(ahead); i++ {
+			if (index+i)*r.BlockSize >= r.size {
+				break
+			}
+			r.cachedBlock(index + i)
+		}
+	}
+	r.last = index
+	return b
+}
+
+// cachedBlock returns the cached block of the index, or starts reading the
+// block and caches it, evicting the least recently used block if the cache
+// is full. The reader's lock must be held.
+func (r *ObjectReader) cachedBlock(index int64) *readerBlock {
+	if b, ok := r.blocks[index]; ok {
+		r.lru.MoveToFront(b.elem)
+		return b
+	}
+
+	b := &readerBlock{index: index, done: make(chan struct{})}
+	b.elem = r.lru.PushFront(b)
+	r.blocks[index] = b
+	for r.lru.Len() > r.CacheBlocks {
+		evicted := r.lru.Remove(r.lru.Back()).(*readerBlock)
+		delete(r.blocks, evicted.index)
+	}
+
+	go r.readBlock(b)
+	return b
+}
+
+// readBlock reads the block with a ranged GetObject request. Blocks which
+// fail to be read are removed from the cache, so that they are read again by
+// the next read of the block.
+func (r *ObjectReader) readBlock(b *readerBlock) {
+	defer close(b.done)
+
+	off := b.index * r.BlockSize
+	n := r.BlockSize
+	if off+n > r.size {
+		n = r.size - off
+	}
+
+	b.data, b.err = r.getRange(off, n)
+	if b.err == nil && int64(len(b.data)) != n {
+		b.err = awserr.New(request.ErrCodeRead,
+			fmt.Sprintf("read %d bytes of object range, expected %d", len(b.data), n), nil)
+	}
+	if b.err != nil {
+		r.m.Lock()
+		if r.blocks[b.index] == b {
+			r.lru.Remove(b.elem)
+			delete(r.blocks, b.index)
+		}
+		r.m.Unlock()
+	}
+}
+
+// getRange returns the n bytes of the object at the offset.
+func (r *ObjectReader) getRange(off, n int64) ([]byte, error) {
+	resp, err := r.svc.GetObjectWithContext(r.ctx, &s3.GetObjectInput{
+		Bucket:    r.bucket,
+		Key:       r.key,
+		VersionId: r.versionID,
+		IfMatch:   r.etag,
+		Range:     aws.String(fmt.Sprintf("bytes=%d-%d", off, off+n-1)),
+	}, r.RequestOptions...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return ioutil.ReadAll(resp.Body)
+}
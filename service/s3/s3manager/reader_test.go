@@ -0,0 +1,232 @@
+package s3manager_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+func readerTestSvc(data []byte) (*s3.S3, func() []string) {
+	var m sync.Mutex
+	var ranges []string
+
+	svc := s3.New(unit.Session, &aws.Config{MaxRetries: aws.Int(0)})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		header := http.Header{"Etag": []string{`"etag"`}}
+		if r.Operation.Name == "HeadObject" {
+			header.Set("Content-Length", strconv.Itoa(len(data)))
+			r.HTTPResponse = &http.Response{
+				StatusCode: 200,
+				Header:     header,
+				Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+			}
+			return
+		}
+
+		rng := r.HTTPRequest.Header.Get("Range")
+		m.Lock()
+		ranges = append(ranges, rng)
+		m.Unlock()
+
+		if e, a := `"etag"`, r.HTTPRequest.Header.Get("If-Match"); e != a {
+			r.HTTPResponse = &http.Response{
+				StatusCode: 412,
+				Header:     header,
+				Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+			}
+			return
+		}
+
+		match := regexp.MustCompile(`bytes=(\d+)-(\d+)`).FindStringSubmatch(rng)
+		start, _ := strconv.ParseInt(match[1], 10, 64)
+		end, _ := strconv.ParseInt(match[2], 10, 64)
+		header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+		r.HTTPResponse = &http.Response{
+			StatusCode: 206,
+			Header:     header,
+			Body:       ioutil.NopCloser(bytes.NewReader(data[start : end+1])),
+		}
+	})
+
+	return svc, func() []string {
+		m.Lock()
+		defer m.Unlock()
+		return append([]string{}, ranges...)
+	}
+}
+
+func TestObjectReader_ReadAt(t *testing.T) {
+	data := make([]byte, 100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	svc, ranges := readerTestSvc(data)
+
+	r, err := s3manager.NewObjectReader(svc, &s3manager.ObjectReaderInput{
+		Bucket: aws.String("bucket"), Key: aws.String("key"),
+	}, func(r *s3manager.ObjectReader) {
+		r.BlockSize = 10
+		r.CacheBlocks = 2
+		r.ReadAhead = -1
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := int64(100), r.Size(); e != a {
+		t.Errorf("expect %v size, got %v", e, a)
+	}
+
+	p := make([]byte, 15)
+	n, err := r.ReadAt(p, 95)
+	if e, a := io.EOF, err; e != a {
+		t.Errorf("expect %v error, got %v", e, a)
+	}
+	if e, a := data[95:], p[:n]; !bytes.Equal(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	n, err = r.ReadAt(p, 8)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := data[8:23], p[:n]; !bytes.Equal(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	// Block 9 was evicted by blocks 0 and 1, block 2 is cached.
+	if _, err := r.ReadAt(p[:5], 20); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if _, err := r.ReadAt(p[:5], 90); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := []string{"bytes=90-99", "bytes=0-9", "bytes=10-19", "bytes=20-29", "bytes=90-99"}
+	if e, a := fmt.Sprint(expect), fmt.Sprint(ranges()); e != a {
+		t.Errorf("expect %v ranges, got %v", e, a)
+	}
+
+	if _, err := r.ReadAt(p, -1); err == nil {
+		t.Errorf("expect error, got none")
+	} else if e, a := s3manager.ErrCodeInvalidOffset, err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
+
+func TestObjectReader_ReadAhead(t *testing.T) {
+	data := make([]byte, 100)
+	svc, ranges := readerTestSvc(data)
+
+	r, err := s3manager.NewObjectReader(svc, &s3manager.ObjectReaderInput{
+		Bucket: aws.String("bucket"), Key: aws.String("key"),
+	}, func(r *s3manager.ObjectReader) {
+		r.BlockSize = 10
+		r.ReadAhead = 2
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := data, b; !bytes.Equal(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := 10, len(ranges()); e != a {
+		t.Errorf("expect %v ranges, got %v", e, a)
+	}
+
+	// Random reads do not read ahead.
+	r, _ = s3manager.NewObjectReader(svc, &s3manager.ObjectReaderInput{
+		Bucket: aws.String("bucket"), Key: aws.String("key"),
+	}, func(r *s3manager.ObjectReader) {
+		r.BlockSize = 10
+	})
+	before := len(ranges())
+	if _, err := r.ReadAt(make([]byte, 5), 50); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 1, len(ranges())-before; e != a {
+		t.Errorf("expect %v ranges, got %v", e, a)
+	}
+}
+
+func TestObjectReader_Seek(t *testing.T) {
+	data := []byte("0123456789")
+	svc, _ := readerTestSvc(data)
+
+	r, err := s3manager.NewObjectReader(svc, &s3manager.ObjectReaderInput{
+		Bucket: aws.String("bucket"), Key: aws.String("key"),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if off, err := r.Seek(-3, 2); err != nil || off != 7 {
+		t.Fatalf("expect offset 7, got %v, %v", off, err)
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "789", string(b); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if _, err := r.Seek(-11, 1); err == nil {
+		t.Errorf("expect error, got none")
+	}
+}
+
+func TestObjectReader_Zip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i := 0; i < 3; i++ {
+		w, _ := zw.Create(fmt.Sprintf("file%d.txt", i))
+		w.Write(bytes.Repeat([]byte{byte('a' + i)}, 1000))
+	}
+	zw.Close()
+	svc, _ := readerTestSvc(buf.Bytes())
+
+	r, err := s3manager.NewObjectReader(svc, &s3manager.ObjectReaderInput{
+		Bucket: aws.String("bucket"), Key: aws.String("archive.zip"),
+	}, func(r *s3manager.ObjectReader) {
+		r.BlockSize = 64
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	zr, err := zip.NewReader(r, r.Size())
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	rc, err := zr.File[1].Open()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer rc.Close()
+	b, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := bytes.Repeat([]byte("b"), 1000), b; !bytes.Equal(e, a) {
+		t.Errorf("expect entry content, got %q", a)
+	}
+}
@@ -24,3 +24,55 @@ type UploaderAPI interface {
 }
 
 var _ UploaderAPI = (*s3manager.Uploader)(nil)
+
+// UploadJanitorAPI is the interface type for s3manager.UploadJanitor.
+type UploadJanitorAPI interface {
+	AbortStaleUploads(*s3manager.AbortStaleUploadsInput, ...func(*s3manager.UploadJanitor)) (*s3manager.AbortStaleUploadsOutput, error)
+	AbortStaleUploadsWithContext(aws.Context, *s3manager.AbortStaleUploadsInput, ...func(*s3manager.UploadJanitor)) (*s3manager.AbortStaleUploadsOutput, error)
+}
+
+var _ UploadJanitorAPI = (*s3manager.UploadJanitor)(nil)
+
+// SyncerAPI is the interface type for s3manager.Syncer.
+type SyncerAPI interface {
+	Upload(*s3manager.SyncInput, ...func(*s3manager.Syncer)) (*s3manager.SyncOutput, error)
+	UploadWithContext(aws.Context, *s3manager.SyncInput, ...func(*s3manager.Syncer)) (*s3manager.SyncOutput, error)
+	Download(*s3manager.SyncInput, ...func(*s3manager.Syncer)) (*s3manager.SyncOutput, error)
+	DownloadWithContext(aws.Context, *s3manager.SyncInput, ...func(*s3manager.Syncer)) (*s3manager.SyncOutput, error)
+}
+
+var _ SyncerAPI = (*s3manager.Syncer)(nil)
+
+// ComposerAPI is the interface type for s3manager.Composer.
+type ComposerAPI interface {
+	Compose(*s3manager.ComposeInput, ...func(*s3manager.Composer)) (*s3manager.ComposeOutput, error)
+	ComposeWithContext(aws.Context, *s3manager.ComposeInput, ...func(*s3manager.Composer)) (*s3manager.ComposeOutput, error)
+}
+
+var _ ComposerAPI = (*s3manager.Composer)(nil)
+
+// InventoryExporterAPI is the interface type for s3manager.InventoryExporter.
+type InventoryExporterAPI interface {
+	ExportInventory(*s3manager.ExportInventoryInput, ...func(*s3manager.InventoryExporter)) (*s3manager.ExportInventoryOutput, error)
+	ExportInventoryWithContext(aws.Context, *s3manager.ExportInventoryInput, ...func(*s3manager.InventoryExporter)) (*s3manager.ExportInventoryOutput, error)
+}
+
+var _ InventoryExporterAPI = (*s3manager.InventoryExporter)(nil)
+
+// DeleterAPI is the interface type for s3manager.Deleter.
+type DeleterAPI interface {
+	DeleteMatching(*s3manager.DeleteMatchingInput, ...func(*s3manager.Deleter)) (*s3manager.DeleteMatchingOutput, error)
+	DeleteMatchingWithContext(aws.Context, *s3manager.DeleteMatchingInput, ...func(*s3manager.Deleter)) (*s3manager.DeleteMatchingOutput, error)
+}
+
+var _ DeleterAPI = (*s3manager.Deleter)(nil)
+
+// ArchiverAPI is the interface type for s3manager.Archiver.
+type ArchiverAPI interface {
+	Pack(*s3manager.PackInput, ...func(*s3manager.Archiver)) (*s3manager.PackOutput, error)
+	PackWithContext(aws.Context, *s3manager.PackInput, ...func(*s3manager.Archiver)) (*s3manager.PackOutput, error)
+	Unpack(*s3manager.UnpackInput, ...func(*s3manager.Archiver)) (*s3manager.UnpackOutput, error)
+	UnpackWithContext(aws.Context, *s3manager.UnpackInput, ...func(*s3manager.Archiver)) (*s3manager.UnpackOutput, error)
+}
+
+var _ ArchiverAPI = (*s3manager.Archiver)(nil)
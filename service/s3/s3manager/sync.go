@@ -0,0 +1,588 @@
+package s3manager
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// DefaultSyncConcurrency is the default number of files to transfer in
+// parallel when syncing with the Syncer.
+const DefaultSyncConcurrency = 5
+
+// The Syncer syncs a local directory tree with the objects of a bucket under
+// a key prefix, transferring only the files which are missing or changed. It
+// is safe to call the Syncer's methods for multiple directories and across
+// concurrent goroutines. Mutating the Syncer's properties is not safe to be
+// done concurrently.
+type Syncer struct {
+	// The number of files to transfer in parallel. If this is set to zero,
+	// the DefaultSyncConcurrency value will be used.
+	Concurrency int
+
+	// Setting this value to true will only compare the directory and the
+	// bucket, without transferring or deleting any file or object. The files
+	// which would be transferred and deleted are returned as usual.
+	DryRun bool
+
+	// An S3 client to use when listing and deleting objects.
+	S3 s3iface.S3API
+
+	// The Uploader to upload files with. Set its Manifest to record the
+	// objects uploaded by the syncer.
+	Uploader *Uploader
+
+	// The Downloader to download objects with.
+	Downloader *Downloader
+
+	// List of request options that will be passed down to the requests
+	// listing and deleting objects made by the syncer.
+	RequestOptions []request.Option
+}
+
+// NewSyncer creates a new Syncer instance to sync directories with. Pass in
+// additional functional options to customize the syncer behavior.
+//
+// Example:
+//     // The session the S3 Syncer will use
+//     sess := session.Must(session.NewSession())
+//
+//     // Create a syncer with the session, uploading files with 10MB parts.
+//     syncer := s3manager.NewSyncer(sess, func(s *s3manager.Syncer) {
+//          s.Uploader.PartSize = 10 * 1024 * 1024
+//     })
+func NewSyncer(c client.ConfigProvider, options ...func(*Syncer)) *Syncer {
+	return NewSyncerWithClient(s3.New(c), options...)
+}
+
+// NewSyncerWithClient creates a new Syncer instance to sync directories with
+// the S3 service client provided. The syncer's Uploader and Downloader are
+// created with the same client.
+func NewSyncerWithClient(svc s3iface.S3API, options ...func(*Syncer)) *Syncer {
+	s := &Syncer{
+		S3:          svc,
+		Concurrency: DefaultSyncConcurrency,
+		Uploader:    NewUploaderWithClient(svc),
+		Downloader:  NewDownloaderWithClient(svc),
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+// SyncInput provides the local directory and the bucket and key prefix to
+// sync, and how to compare them.
+type SyncInput struct {
+	// The bucket of the objects.
+	Bucket *string
+
+	// The key prefix of the objects. The key of an object is the prefix
+	// followed by the slash separated path of its file relative to Dir. A
+	// slash is appended to the prefix if it does not end with one.
+	Prefix *string
+
+	// The local directory of the files.
+	Dir string
+
+	// Setting this value to true deletes the files, or objects, of the
+	// destination which do not exist in the source.
+	Delete bool
+
+	// Only files with a relative path matching one of the patterns are
+	// synced, if set. The patterns are matched with path.Match against the
+	// slash separated relative paths.
+	Include []string
+
+	// Files with a relative path matching any of the patterns are not synced,
+	// nor deleted.
+	Exclude []string
+
+	// Setting this value to true compares files and objects of the same size
+	// by the MD5 digest of the file and the ETag of the object, instead of
+	// their modification times. Objects whose ETag is not an MD5 digest, such
+	// as those uploaded in parts, are still compared by modification time.
+	CompareETag bool
+}
+
+// SyncOutput represents a response from the Syncer's Upload and Download
+// calls.
+type SyncOutput struct {
+	// The relative paths of the files transferred, or which would be
+	// transferred if the syncer is in dry-run mode.
+	Transferred []string
+
+	// The relative paths of the files, or objects, deleted from the
+	// destination, or which would be deleted if the syncer is in dry-run
+	// mode.
+	Deleted []string
+}
+
+// syncEntry is a file, or an object, being synced.
+type syncEntry struct {
+	size    int64
+	modTime time.Time
+
+	// The object's key and ETag.
+	key  string
+	etag string
+
+	// The file's path.
+	path string
+}
+
+// Upload syncs the bucket's objects under the prefix with the files of the
+// directory, uploading the files which do not exist in the bucket, or whose
+// size differs from the object's, or which were modified since the object
+// was. Objects which do not exist in the directory are deleted if the
+// input's Delete is set.
+//
+// The files which could not be synced are returned as a BatchError, along
+// with the output of the files which were.
+//
+// Additional functional options can be provided to configure the individual
+// call. These options are copies of the Syncer instance Upload is called
+// from. Modifying the options will not impact the original Syncer instance.
+//
+// Example:
+//     result, err := syncer.Upload(&s3manager.SyncInput{
+//         Bucket:  aws.String("bucket"),
+//         Prefix:  aws.String("site/"),
+//         Dir:     "public",
+//         Exclude: []string{"*.tmp"},
+//     })
+func (s Syncer) Upload(input *SyncInput, options ...func(*Syncer)) (*SyncOutput, error) {
+	return s.UploadWithContext(aws.BackgroundContext(), input, options...)
+}
+
+// UploadWithContext syncs the bucket's objects with the files of the
+// directory the same as Upload, with the addition of the ability to pass a
+// context.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur.
+func (s Syncer) UploadWithContext(ctx aws.Context, input *SyncInput, options ...func(*Syncer)) (*SyncOutput, error) {
+	s.init(options)
+
+	local, remote, err := s.list(ctx, input, false)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer, del := s.compare(input, local, remote, false)
+	if s.DryRun {
+		return &SyncOutput{Transferred: transfer, Deleted: del}, nil
+	}
+
+	out := &SyncOutput{}
+	errs := s.transfer(input, transfer, out, func(rel string) error {
+		return s.uploadFile(ctx, input, rel, local[rel])
+	})
+	errs = append(errs, s.deleteObjects(ctx, input, remote, del, out)...)
+
+	return syncResult(out, errs)
+}
+
+// Download syncs the files of the directory with the bucket's objects under
+// the prefix, downloading the objects which do not exist in the directory,
+// or whose size differs from the file's, or which were modified since the
+// file was. Files which do not exist in the bucket are deleted if the
+// input's Delete is set.
+//
+// The modification time of a downloaded file is set to the object's. Objects
+// are downloaded to a temporary file in the file's directory, which replaces
+// the file once completed.
+//
+// The files which could not be synced are returned as a BatchError, along
+// with the output of the files which were.
+//
+// Additional functional options can be provided to configure the individual
+// call. These options are copies of the Syncer instance Download is called
+// from. Modifying the options will not impact the original Syncer instance.
+func (s Syncer) Download(input *SyncInput, options ...func(*Syncer)) (*SyncOutput, error) {
+	return s.DownloadWithContext(aws.BackgroundContext(), input, options...)
+}
+
+// DownloadWithContext syncs the files of the directory with the bucket's
+// objects the same as Download, with the addition of the ability to pass a
+// context.
+//
+// The context must be non-nil and will be used for request cancellation. If
+// the context is nil a panic will occur.
+func (s Syncer) DownloadWithContext(ctx aws.Context, input *SyncInput, options ...func(*Syncer)) (*SyncOutput, error) {
+	s.init(options)
+
+	local, remote, err := s.list(ctx, input, true)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer, del := s.compare(input, remote, local, true)
+	if s.DryRun {
+		return &SyncOutput{Transferred: transfer, Deleted: del}, nil
+	}
+
+	out := &SyncOutput{}
+	errs := s.transfer(input, transfer, out, func(rel string) error {
+		return s.downloadFile(ctx, input, rel, remote[rel])
+	})
+	for _, rel := range del {
+		if err := os.Remove(local[rel].path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, newError(err, input.Bucket, aws.String(syncKey(input, rel))))
+			continue
+		}
+		out.Deleted = append(out.Deleted, rel)
+	}
+
+	return syncResult(out, errs)
+}
+
+// init applies the options to the syncer, and sets its defaults.
+func (s *Syncer) init(options []func(*Syncer)) {
+	for _, option := range options {
+		option(s)
+	}
+	s.RequestOptions = append(s.RequestOptions, request.WithAppendUserAgent("S3Manager"))
+
+	if s.Concurrency <= 0 {
+		s.Concurrency = DefaultSyncConcurrency
+	}
+	if s.Uploader == nil {
+		s.Uploader = NewUploaderWithClient(s.S3)
+	}
+	if s.Downloader == nil {
+		s.Downloader = NewDownloaderWithClient(s.S3)
+	}
+}
+
+// syncResult returns the output, and a BatchError of the errors, if any.
+func syncResult(out *SyncOutput, errs []Error) (*SyncOutput, error) {
+	if len(errs) != 0 {
+		return out, NewBatchError("SyncIncomplete", "some files failed to sync", errs)
+	}
+	return out, nil
+}
+
+// list lists the files of the directory and the objects under the prefix,
+// keyed by their relative path, excluding those filtered by the input. The
+// directory is treated as empty if it does not exist and is the destination.
+func (s Syncer) list(ctx aws.Context, input *SyncInput, download bool) (local, remote map[string]*syncEntry, err error) {
+	for _, pattern := range append(append([]string{}, input.Include...), input.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, nil, awserr.New("InvalidParameter", "invalid sync filter pattern "+pattern, err)
+		}
+	}
+
+	local, err = listSyncDir(input)
+	if os.IsNotExist(err) && download {
+		local, err = map[string]*syncEntry{}, nil
+	}
+	if err != nil {
+		return nil, nil, awserr.New("ReadError", "failed to list directory", err)
+	}
+
+	remote = map[string]*syncEntry{}
+	prefix := syncPrefix(input)
+	err = s.S3.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: input.Bucket,
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			key := aws.StringValue(obj.Key)
+			rel := strings.TrimPrefix(key, prefix)
+			if len(rel) == 0 || strings.HasSuffix(rel, "/") || !syncFiltered(input, rel) {
+				// Directory placeholder objects are not synced.
+				continue
+			}
+			remote[rel] = &syncEntry{
+				size:    aws.Int64Value(obj.Size),
+				modTime: aws.TimeValue(obj.LastModified),
+				key:     key,
+				etag:    strings.Trim(aws.StringValue(obj.ETag), `"`),
+			}
+		}
+		return true
+	}, s.RequestOptions...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return local, remote, nil
+}
+
+// listSyncDir lists the regular files of the input's directory tree, keyed by
+// their slash separated path relative to the directory.
+func listSyncDir(input *SyncInput) (map[string]*syncEntry, error) {
+	entries := map[string]*syncEntry{}
+	err := filepath.Walk(input.Dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(input.Dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !syncFiltered(input, rel) {
+			return nil
+		}
+
+		entries[rel] = &syncEntry{size: info.Size(), modTime: info.ModTime(), path: p}
+		return nil
+	})
+
+	return entries, err
+}
+
+// syncFiltered returns whether the relative path passes the input's include
+// and exclude filters.
+func syncFiltered(input *SyncInput, rel string) bool {
+	included := len(input.Include) == 0
+	for _, pattern := range input.Include {
+		if ok, _ := path.Match(pattern, rel); ok {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+
+	for _, pattern := range input.Exclude {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// syncPrefix returns the input's key prefix, ending with a slash if set.
+func syncPrefix(input *SyncInput) string {
+	prefix := aws.StringValue(input.Prefix)
+	if len(prefix) != 0 && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix
+}
+
+// syncKey returns the key of the object of the relative path.
+func syncKey(input *SyncInput, rel string) string {
+	return syncPrefix(input) + rel
+}
+
+// compare returns the sorted relative paths of the source's entries to
+// transfer to the destination, and of the destination's entries to delete.
+func (s Syncer) compare(input *SyncInput, src, dst map[string]*syncEntry, download bool) (transfer, del []string) {
+	for rel, from := range src {
+		to, ok := dst[rel]
+		if !ok || syncChanged(input, from, to, download) {
+			transfer = append(transfer, rel)
+		}
+	}
+
+	if input.Delete {
+		for rel := range dst {
+			if _, ok := src[rel]; !ok {
+				del = append(del, rel)
+			}
+		}
+	}
+
+	sort.Strings(transfer)
+	sort.Strings(del)
+	return transfer, del
+}
+
+// syncChanged returns whether the source entry differs from the destination
+// entry, and should be transferred.
+func syncChanged(input *SyncInput, src, dst *syncEntry, download bool) bool {
+	if src.size != dst.size {
+		return true
+	}
+
+	file, object := src, dst
+	if download {
+		file, object = dst, src
+	}
+
+	if input.CompareETag && isMD5ETag(object.etag) {
+		sum, err := fileMD5(file.path)
+		return err != nil || sum != object.etag
+	}
+
+	return src.modTime.After(dst.modTime)
+}
+
+// fileMD5 returns the hex encoded MD5 digest of the file.
+func fileMD5(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// transfer runs fn for the relative paths concurrently, recording the paths
+// transferred in the output, and returning the errors of those which were
+// not.
+func (s Syncer) transfer(input *SyncInput, rels []string, out *SyncOutput, fn func(rel string) error) []Error {
+	var (
+		wg          sync.WaitGroup
+		m           sync.Mutex
+		errs        []Error
+		transferred = make([]bool, len(rels))
+		ch          = make(chan int)
+	)
+
+	for i := 0; i < s.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range ch {
+				if err := fn(rels[idx]); err != nil {
+					m.Lock()
+					errs = append(errs, newError(err, input.Bucket, aws.String(syncKey(input, rels[idx]))))
+					m.Unlock()
+					continue
+				}
+				transferred[idx] = true
+			}
+		}()
+	}
+
+	for i := range rels {
+		ch <- i
+	}
+	close(ch)
+	wg.Wait()
+
+	for i, rel := range rels {
+		if transferred[i] {
+			out.Transferred = append(out.Transferred, rel)
+		}
+	}
+	return errs
+}
+
+// uploadFile uploads the file to its object.
+func (s Syncer) uploadFile(ctx aws.Context, input *SyncInput, rel string, file *syncEntry) error {
+	f, err := os.Open(file.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = s.Uploader.UploadWithContext(ctx, &UploadInput{
+		Bucket: input.Bucket,
+		Key:    aws.String(syncKey(input, rel)),
+		Body:   f,
+	})
+	return err
+}
+
+// downloadFile downloads the object to its file, through a temporary file.
+func (s Syncer) downloadFile(ctx aws.Context, input *SyncInput, rel string, object *syncEntry) error {
+	if rel != path.Clean(rel) || path.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, "../") {
+		// The key would be written outside of the directory.
+		return awserr.New("InvalidKey", "object key "+object.key+" is not a valid file path", nil)
+	}
+
+	p := filepath.Join(input.Dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(p), "."+filepath.Base(p)+".")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = s.Downloader.DownloadWithContext(ctx, f, &s3.GetObjectInput{
+		Bucket: input.Bucket,
+		Key:    aws.String(object.key),
+	})
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return err
+	}
+	if err := os.Chtimes(f.Name(), object.modTime, object.modTime); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+// deleteObjects deletes the objects of the relative paths, in batches,
+// recording the paths deleted in the output.
+func (s Syncer) deleteObjects(ctx aws.Context, input *SyncInput, remote map[string]*syncEntry, rels []string, out *SyncOutput) []Error {
+	var errs []Error
+	for len(rels) != 0 {
+		batch := rels
+		if len(batch) > MaxBatchSize {
+			batch = batch[:MaxBatchSize]
+		}
+		rels = rels[len(batch):]
+
+		params := &s3.DeleteObjectsInput{Bucket: input.Bucket, Delete: &s3.Delete{}}
+		for _, rel := range batch {
+			params.Delete.Objects = append(params.Delete.Objects, &s3.ObjectIdentifier{
+				Key: aws.String(remote[rel].key),
+			})
+		}
+
+		resp, err := s.S3.DeleteObjectsWithContext(ctx, params, s.RequestOptions...)
+		if err != nil {
+			for _, rel := range batch {
+				errs = append(errs, newError(err, input.Bucket, aws.String(remote[rel].key)))
+			}
+			continue
+		}
+
+		failed := map[string]bool{}
+		for _, e := range resp.Errors {
+			failed[aws.StringValue(e.Key)] = true
+			errs = append(errs, newError(
+				awserr.New(aws.StringValue(e.Code), aws.StringValue(e.Message), nil),
+				input.Bucket, e.Key))
+		}
+		for _, rel := range batch {
+			if !failed[remote[rel].key] {
+				out.Deleted = append(out.Deleted, rel)
+			}
+		}
+	}
+	return errs
+}
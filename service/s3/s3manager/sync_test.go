@@ -0,0 +1,303 @@
+package s3manager_test
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+type syncObject struct {
+	data    []byte
+	modTime time.Time
+}
+
+// syncBucket is an in-memory bucket serving the requests of the Syncer.
+type syncBucket struct {
+	m       sync.Mutex
+	objects map[string]syncObject
+	ops     []string
+}
+
+func (b *syncBucket) client() *s3.S3 {
+	svc := s3.New(unit.Session, &aws.Config{MaxRetries: aws.Int(0)})
+	svc.Handlers.Send.Clear()
+	svc.Handlers.Unmarshal.Clear()
+	svc.Handlers.UnmarshalMeta.Clear()
+	svc.Handlers.UnmarshalError.Clear()
+	svc.Handlers.Send.PushBack(func(r *request.Request) {
+		b.m.Lock()
+		defer b.m.Unlock()
+
+		r.HTTPResponse = &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewReader(nil))}
+
+		switch in := r.Params.(type) {
+		case *s3.ListObjectsV2Input:
+			out := r.Data.(*s3.ListObjectsV2Output)
+			for _, key := range b.keys() {
+				if !strings.HasPrefix(key, aws.StringValue(in.Prefix)) {
+					continue
+				}
+				obj := b.objects[key]
+				out.Contents = append(out.Contents, &s3.Object{
+					Key:          aws.String(key),
+					Size:         aws.Int64(int64(len(obj.data))),
+					LastModified: aws.Time(obj.modTime),
+					ETag:         aws.String(`"` + md5Hex(obj.data) + `"`),
+				})
+			}
+		case *s3.PutObjectInput:
+			in.Body.Seek(0, 0)
+			data, _ := ioutil.ReadAll(in.Body)
+			b.objects[*in.Key] = syncObject{data: data, modTime: time.Now()}
+			b.ops = append(b.ops, "put "+*in.Key)
+		case *s3.GetObjectInput:
+			obj := b.objects[*in.Key]
+			out := r.Data.(*s3.GetObjectOutput)
+			out.LastModified = aws.Time(obj.modTime)
+			start, fin := 0, len(obj.data)
+			if in.Range != nil {
+				m := regexp.MustCompile(`bytes=(\d+)-(\d+)`).FindStringSubmatch(*in.Range)
+				start, _ = strconv.Atoi(m[1])
+				fin, _ = strconv.Atoi(m[2])
+				if fin++; fin > len(obj.data) {
+					fin = len(obj.data)
+				}
+				out.ContentRange = aws.String(fmt.Sprintf("bytes %d-%d/%d", start, fin-1, len(obj.data)))
+			}
+			out.Body = ioutil.NopCloser(bytes.NewReader(obj.data[start:fin]))
+			out.ContentLength = aws.Int64(int64(fin - start))
+			b.ops = append(b.ops, "get "+*in.Key)
+		case *s3.HeadObjectInput:
+			obj := b.objects[*in.Key]
+			out := r.Data.(*s3.HeadObjectOutput)
+			out.ContentLength = aws.Int64(int64(len(obj.data)))
+			out.ETag = aws.String(`"` + md5Hex(obj.data) + `"`)
+			b.ops = append(b.ops, "head "+*in.Key)
+		case *s3.DeleteObjectsInput:
+			for _, o := range in.Delete.Objects {
+				delete(b.objects, *o.Key)
+				b.ops = append(b.ops, "delete "+*o.Key)
+			}
+		}
+	})
+	return svc
+}
+
+func (b *syncBucket) keys() []string {
+	keys := []string{}
+	for key := range b.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (b *syncBucket) takeOps() []string {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	ops := b.ops
+	b.ops = nil
+	sort.Strings(ops)
+	return ops
+}
+
+func md5Hex(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func writeSyncFile(t *testing.T, dir, rel, data string, modTime time.Time) {
+	p := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := ioutil.WriteFile(p, []byte(data), 0644); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := os.Chtimes(p, modTime, modTime); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+}
+
+func newSyncDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "s3manager-sync")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestSyncUpload(t *testing.T) {
+	dir, cleanup := newSyncDir(t)
+	defer cleanup()
+
+	old := time.Now().Add(-time.Hour)
+	writeSyncFile(t, dir, "same.txt", "same", old)
+	writeSyncFile(t, dir, "changed.txt", "changed", old)
+	writeSyncFile(t, dir, "sub/new.txt", "new", old)
+	writeSyncFile(t, dir, "skip.tmp", "skip", old)
+
+	b := &syncBucket{objects: map[string]syncObject{
+		"site/same.txt":    {data: []byte("same"), modTime: time.Now()},
+		"site/changed.txt": {data: []byte("before"), modTime: time.Now()},
+		"site/extra.txt":   {data: []byte("extra"), modTime: time.Now()},
+		"site/keep.tmp":    {data: []byte("keep"), modTime: time.Now()},
+		"other/file.txt":   {data: []byte("other"), modTime: time.Now()},
+	}}
+	syncer := s3manager.NewSyncerWithClient(b.client())
+
+	input := &s3manager.SyncInput{
+		Bucket:  aws.String("bucket"),
+		Prefix:  aws.String("site"),
+		Dir:     dir,
+		Delete:  true,
+		Exclude: []string{"*.tmp"},
+	}
+	out, err := syncer.Upload(input)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := "[changed.txt sub/new.txt]", fmt.Sprint(out.Transferred); e != a {
+		t.Errorf("expect %v transferred, got %v", e, a)
+	}
+	if e, a := "[extra.txt]", fmt.Sprint(out.Deleted); e != a {
+		t.Errorf("expect %v deleted, got %v", e, a)
+	}
+	expect := "[delete site/extra.txt put site/changed.txt put site/sub/new.txt]"
+	if e, a := expect, fmt.Sprint(b.takeOps()); e != a {
+		t.Errorf("expect %v operations, got %v", e, a)
+	}
+	if e, a := "[other/file.txt site/changed.txt site/keep.tmp site/same.txt site/sub/new.txt]", fmt.Sprint(b.keys()); e != a {
+		t.Errorf("expect %v keys, got %v", e, a)
+	}
+
+	// Syncing again transfers nothing.
+	out, err = syncer.Upload(input)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 0, len(out.Transferred)+len(out.Deleted); e != a {
+		t.Errorf("expect nothing synced, got %v", out)
+	}
+}
+
+func TestSyncUploadCompareETag(t *testing.T) {
+	dir, cleanup := newSyncDir(t)
+	defer cleanup()
+
+	// The files are newer than the objects, but only one differs.
+	writeSyncFile(t, dir, "a.txt", "aaaa", time.Now())
+	writeSyncFile(t, dir, "b.txt", "bbbb", time.Now())
+
+	old := time.Now().Add(-time.Hour)
+	b := &syncBucket{objects: map[string]syncObject{
+		"a.txt": {data: []byte("aaaa"), modTime: old},
+		"b.txt": {data: []byte("BBBB"), modTime: old},
+	}}
+	syncer := s3manager.NewSyncerWithClient(b.client())
+
+	out, err := syncer.Upload(&s3manager.SyncInput{
+		Bucket:      aws.String("bucket"),
+		Dir:         dir,
+		CompareETag: true,
+	}, func(s *s3manager.Syncer) {
+		s.DryRun = true
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "[b.txt]", fmt.Sprint(out.Transferred); e != a {
+		t.Errorf("expect %v transferred, got %v", e, a)
+	}
+	if e, a := 0, len(b.takeOps()); e != a {
+		t.Errorf("expect no operations in dry run, got %v", a)
+	}
+}
+
+func TestSyncDownload(t *testing.T) {
+	dir, cleanup := newSyncDir(t)
+	defer cleanup()
+
+	old := time.Now().Add(-time.Hour)
+	writeSyncFile(t, dir, "same.txt", "same", time.Now())
+	writeSyncFile(t, dir, "stale.txt", "stale", old)
+	writeSyncFile(t, dir, "extra.txt", "extra", old)
+
+	modTime := time.Now().Add(-time.Minute).Truncate(time.Second)
+	b := &syncBucket{objects: map[string]syncObject{
+		"same.txt":        {data: []byte("same"), modTime: old},
+		"stale.txt":       {data: []byte("fresh"), modTime: modTime},
+		"sub/new.txt":     {data: []byte("new"), modTime: modTime},
+		"sub/":            {modTime: modTime},
+		"../escape.txt":   {data: []byte("escape"), modTime: modTime},
+		"include/only.md": {data: []byte("md"), modTime: modTime},
+	}}
+	syncer := s3manager.NewSyncerWithClient(b.client())
+
+	out, err := syncer.Download(&s3manager.SyncInput{
+		Bucket:  aws.String("bucket"),
+		Dir:     dir,
+		Delete:  true,
+		Include: []string{"*.txt", "sub/*", "../*"},
+	})
+	batchErr, ok := err.(*s3manager.BatchError)
+	if !ok {
+		t.Fatalf("expect *s3manager.BatchError, got %v", err)
+	}
+	if e, a := 1, len(batchErr.Errors); e != a {
+		t.Fatalf("expect %v errors, got %v", e, a)
+	}
+	if e, a := "../escape.txt", aws.StringValue(batchErr.Errors[0].Key); e != a {
+		t.Errorf("expect %v failed key, got %v", e, a)
+	}
+
+	if e, a := "[stale.txt sub/new.txt]", fmt.Sprint(out.Transferred); e != a {
+		t.Errorf("expect %v transferred, got %v", e, a)
+	}
+	if e, a := "[extra.txt]", fmt.Sprint(out.Deleted); e != a {
+		t.Errorf("expect %v deleted, got %v", e, a)
+	}
+
+	for rel, e := range map[string]string{"same.txt": "same", "stale.txt": "fresh", "sub/new.txt": "new"} {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			t.Fatalf("%s, expect no error, got %v", rel, err)
+		}
+		if a := string(data); e != a {
+			t.Errorf("%s, expect %q, got %q", rel, e, a)
+		}
+	}
+	fi, err := os.Stat(filepath.Join(dir, "stale.txt"))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := modTime, fi.ModTime(); !e.Equal(a) {
+		t.Errorf("expect %v modification time, got %v", e, a)
+	}
+	for _, rel := range []string{"extra.txt", "include/only.md", filepath.Join("..", "escape.txt")} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); !os.IsNotExist(err) {
+			t.Errorf("%s, expect file not to exist, got %v", rel, err)
+		}
+	}
+}
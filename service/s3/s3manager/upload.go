@@ -2,7 +2,9 @@ package s3manager
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
+	"hash"
 	"io"
 	"sort"
 	"sync"
@@ -25,10 +27,21 @@ const MaxUploadParts = 10000
 // Amazon S3.
 const MinUploadPartSize int64 = 1024 * 1024 * 5
 
+// MaxUploadPartSize is the maximum allowed part size when uploading a part to
+// Amazon S3.
+const MaxUploadPartSize int64 = 1024 * 1024 * 1024 * 5
+
 // DefaultUploadPartSize is the default part size to buffer chunks of a
 // payload into.
 const DefaultUploadPartSize = MinUploadPartSize
 
+// PartSizeGrowthInterval is the number of parts after which the part size of
+// an upload of unknown size is doubled, up to MaxUploadPartSize. Growing the
+// part size allows streams of up to 1023 times PartSizeGrowthInterval parts
+// of PartSize to be uploaded within the MaxUploadParts limit, about 4.8TiB
+// with the DefaultUploadPartSize.
+const PartSizeGrowthInterval = 1000
+
 // DefaultUploadConcurrency is the default number of goroutines to spin up when
 // using Upload().
 const DefaultUploadConcurrency = 5
@@ -146,6 +159,23 @@ type UploadInput struct {
 	// at http://docs.aws.amazon.com/AmazonS3/latest/dev/ObjectsinRequesterPaysBuckets.html
 	RequestPayer *string `location:"header" locationName:"x-amz-request-payer" type:"string"`
 
+	// Date on which it will be legal to delete or modify the object. You can only
+	// specify this or the Retention-Period header. If both are specified a 400
+	// error will be returned. If neither is specified the bucket's DefaultRetention
+	// period will be used.
+	RetentionExpirationDate *time.Time `location:"header" locationName:"Retention-Expiration-Date" type:"timestamp" timestampFormat:"rfc822"`
+
+	// A single legal hold to apply to the object. A legal hold is a string of 1
+	// to 64 characters. The object cannot be overwritten or deleted until all legal
+	// holds associated with the object are removed.
+	RetentionLegalHoldId *string `location:"header" locationName:"Retention-Legal-Hold-ID" type:"string"`
+
+	// Retention period to store on the object in seconds. If this field and Retention-Expiration-Date
+	// are specified a 400 error is returned. If neither is specified the bucket's
+	// DefaultRetention period will be used. 0 is a legal value assuming the bucket's
+	// minimum retention period is also 0.
+	RetentionPeriod *int64 `location:"header" locationName:"Retention-Period" type:"integer"`
+
 	// Specifies the algorithm to use to when encrypting the object (e.g., AES256,
 	// aws:kms).
 	SSECustomerAlgorithm *string `location:"header" locationName:"x-amz-server-side-encryption-customer-algorithm" type:"string"`
@@ -216,12 +246,28 @@ type Uploader struct {
 	// The buffer size (in bytes) to use when buffering data into chunks and
 	// sending them as parts to S3. The minimum allowed part size is 5MB, and
 	// if this value is set to zero, the DefaultUploadPartSize value will be used.
+	//
+	// Bodies of unknown size, such as a plain io.Reader, are uploaded with a
+	// single PutObject request if they are no larger than PartSize, and
+	// otherwise with a multipart upload whose part size is doubled every
+	// PartSizeGrowthInterval parts.
 	PartSize int64
 
 	// The number of goroutines to spin up in parallel when sending parts.
 	// If this is set to zero, the DefaultUploadConcurrency value will be used.
 	Concurrency int
 
+	// Setting this value to true will cause the uploader to adjust the number
+	// of parts sent in parallel to the observed throughput, starting from
+	// Concurrency. The number is increased while the throughput improves, and
+	// halved when parts are retried or fail, up to MaxConcurrency.
+	AdaptiveConcurrency bool
+
+	// The maximum number of parts sent in parallel when AdaptiveConcurrency
+	// is set. If this is set to zero, the DefaultMaxAdaptiveConcurrency value
+	// will be used.
+	MaxConcurrency int
+
 	// Setting this value to true will cause the SDK to avoid calling
 	// AbortMultipartUpload on a failure, leaving all successfully uploaded
 	// parts on S3 for manual recovery.
@@ -243,6 +289,52 @@ type Uploader struct {
 	// List of request options that will be passed down to individual API
 	// operation requests made by the uploader.
 	RequestOptions []request.Option
+
+	// BufferProvider provides the buffers parts of the upload's body are
+	// read into, when the body cannot be read from concurrently. If nil, a
+	// PooledBufferProvider shared by all Uploaders will be used.
+	BufferProvider UploadBufferProvider
+
+	// OnProgress is called with the progress of the upload each time a part
+	// has been uploaded, if set. Use ProgressChannel to receive the progress
+	// on a channel instead.
+	OnProgress ProgressFunc
+
+	// Setting this value to true will cause the uploader to compress the
+	// bodies of uploads with gzip, setting their Content-Encoding, if their
+	// content type is one of CompressContentTypes. Uploads whose
+	// ContentEncoding is already set are not compressed. Since the size of
+	// the compressed body is not known in advance, it is uploaded the same
+	// as a body of unknown size.
+	Compress bool
+
+	// The size (in bytes) below which bodies are not compressed. Bodies of
+	// unknown size are always compressed. If this is set to zero, the
+	// DefaultCompressMinSize value will be used.
+	CompressMinSize int64
+
+	// The content types the bodies of uploads are compressed for, such as
+	// "application/json", or "text/*" for all text types. If nil, the
+	// DefaultCompressContentTypes will be used.
+	CompressContentTypes []string
+
+	// QuotaCheck returns the hard quota and usage of buckets, if set. Uploads
+	// of at least QuotaCheckMinSize bytes fail early with a QuotaExceededError,
+	// before any data is uploaded, if they would exceed the hard quota of
+	// their bucket. Bodies of unknown size are checked once the first part
+	// has been read, with the size of the data read.
+	QuotaCheck QuotaFunc
+
+	// The size (in bytes) below which the quota of an upload's bucket is not
+	// checked. If this is set to zero, the DefaultQuotaCheckMinSize value
+	// will be used.
+	QuotaCheckMinSize int64
+
+	// The manifest the objects uploaded by the uploader are recorded in, if
+	// set, with the size and SHA-256 digest of their content, and their
+	// version ID. The digest is computed as the body is read, and bodies
+	// which are io.ReaderAt, such as files, are read twice.
+	Manifest *UploadManifest
 }
 
 // NewUploader creates a new Uploader instance to upload objects to S3. Pass In
@@ -370,7 +462,11 @@ func (u Uploader) UploadWithContext(ctx aws.Context, input *UploadInput, opts ..
 	}
 	i.cfg.RequestOptions = append(i.cfg.RequestOptions, request.WithAppendUserAgent("S3Manager"))
 
-	return i.upload()
+	out, err := i.upload()
+	if err == nil && i.cfg.Manifest != nil {
+		i.cfg.Manifest.record(i.in, i.readerPos, i.digest, out)
+	}
+	return out, err
 }
 
 // UploadWithIterator will upload a batched amount of objects to S3. This operation uses
@@ -437,6 +533,14 @@ type uploader struct {
 
 	readerPos int64 // current reader position
 	totalSize int64 // set to -1 if the size is not known
+	partsRead int   // number of non-empty parts read
+
+	progress *progressTracker
+	limiter  *concurrencyLimiter
+
+	// digest is the SHA-256 digest of the data read, if the upload is
+	// recorded in a manifest.
+	digest hash.Hash
 }
 
 // internal logic for deciding whether to upload a single part or use a
@@ -449,16 +553,33 @@ func (u *uploader) upload() (*UploadOutput, error) {
 		return nil, awserr.New("ConfigError", msg, nil)
 	}
 
+	if u.shouldCompress() {
+		defer u.compressBody().Close()
+	}
+
 	// Do one read to determine if we have more than one part
-	reader, _, err := u.nextReader()
+	reader, n, cleanup, err := u.nextReader()
+	if err == nil || err == io.EOF {
+		size := u.totalSize
+		if size < 0 {
+			size = int64(n)
+		}
+		if qerr := u.checkQuota(size); qerr != nil {
+			cleanup()
+			return nil, qerr
+		}
+	}
 	if err == io.EOF { // single part
-		return u.singlePart(reader)
+		defer cleanup()
+		u.progress.setTotal(int64(n), 1)
+		return u.singlePart(reader, int64(n))
 	} else if err != nil {
+		cleanup()
 		return nil, awserr.New("ReadRequestBody", "read upload data failed", err)
 	}
 
 	mu := multiuploader{uploader: u}
-	return mu.upload(reader)
+	return mu.upload(chunk{buf: reader, num: 1, size: int64(n), cleanup: cleanup})
 }
 
 // init will initialize all default options.
@@ -469,9 +590,22 @@ func (u *uploader) init() {
 	if u.cfg.PartSize == 0 {
 		u.cfg.PartSize = DefaultUploadPartSize
 	}
+	if u.cfg.BufferProvider == nil {
+		u.cfg.BufferProvider = defaultUploadBufferProvider
+	}
 
 	// Try to get the total size for some optimizations
 	u.initSize()
+
+	if u.cfg.Manifest != nil {
+		u.digest = sha256.New()
+	}
+
+	u.progress = newProgressTracker(u.cfg.OnProgress)
+	u.limiter = newConcurrencyLimiter(u.cfg.AdaptiveConcurrency, u.cfg.Concurrency, u.cfg.MaxConcurrency)
+	if u.totalSize >= 0 {
+		u.progress.setTotal(u.totalSize, partCount(u.totalSize, u.cfg.PartSize))
+	}
 }
 
 // initSize tries to detect the total stream size, setting u.totalSize. If
@@ -480,6 +614,9 @@ func (u *uploader) initSize() {
 	u.totalSize = -1
 
 	switch r := u.in.Body.(type) {
+	case readerAtSizer:
+		u.totalSize = r.Size()
+		u.adjustPartSize()
 	case io.Seeker:
 		pos, _ := r.Seek(0, 1)
 		defer r.Seek(pos, 0)
@@ -489,27 +626,77 @@ func (u *uploader) initSize() {
 			return
 		}
 		u.totalSize = n
+		u.adjustPartSize()
+	}
+}
 
-		// Try to adjust partSize if it is too small and account for
-		// integer division truncation.
-		if u.totalSize/u.cfg.PartSize >= int64(u.cfg.MaxUploadParts) {
-			// Add one to the part size to account for remainders
-			// during the size calculation. e.g odd number of bytes.
-			u.cfg.PartSize = (u.totalSize / int64(u.cfg.MaxUploadParts)) + 1
-		}
+// adjustPartSize increases the part size if the total size of the upload
+// would exceed the maximum number of upload parts.
+func (u *uploader) adjustPartSize() {
+	// Try to adjust partSize if it is too small and account for
+	// integer division truncation.
+	if u.totalSize/u.cfg.PartSize >= int64(u.cfg.MaxUploadParts) {
+		// Add one to the part size to account for remainders
+		// during the size calculation. e.g odd number of bytes.
+		u.cfg.PartSize = (u.totalSize / int64(u.cfg.MaxUploadParts)) + 1
 	}
 }
 
+// readerAtSizer is a body which can be read concurrently, and whose total
+// size is known, such as bytes.Reader and io.SectionReader.
+type readerAtSizer interface {
+	io.ReaderAt
+	Size() int64
+}
+
+// noopCleanup is the cleanup of readers which do not use a buffer.
+func noopCleanup() {}
+
 // nextReader returns a seekable reader representing the next packet of data.
 // This operation increases the shared u.readerPos counter, but note that it
 // does not need to be wrapped in a mutex because nextReader is only called
 // from the main thread.
-func (u *uploader) nextReader() (io.ReadSeeker, int, error) {
+//
+// The cleanup function returned must be called once the reader is no longer
+// used, releasing the buffer the packet was read into, if any.
+func (u *uploader) nextReader() (io.ReadSeeker, int, func(), error) {
+	reader, n, cleanup, err := u.nextPart()
+	if u.digest == nil || (err != nil && err != io.EOF) {
+		return reader, n, cleanup, err
+	}
+
+	// Parts are read in order, and the digest of the upload is computed as
+	// they are.
+	if _, herr := io.Copy(u.digest, reader); herr != nil {
+		return reader, n, cleanup, herr
+	}
+	if _, herr := reader.Seek(0, 0); herr != nil { // io.SeekStart
+		return reader, n, cleanup, herr
+	}
+	return reader, n, cleanup, err
+}
+
+// nextPart returns a seekable reader of the next part of the body.
+func (u *uploader) nextPart() (io.ReadSeeker, int, func(), error) {
 	type readerAtSeeker interface {
 		io.ReaderAt
 		io.ReadSeeker
 	}
 	switch r := u.in.Body.(type) {
+	case readerAtSizer:
+		var err error
+
+		n := u.cfg.PartSize
+		if bytesLeft := u.totalSize - u.readerPos; bytesLeft <= n {
+			err = io.EOF
+			n = bytesLeft
+		}
+
+		reader := io.NewSectionReader(r, u.readerPos, n)
+		u.readerPos += n
+
+		return reader, int(n), noopCleanup, err
+
 	case readerAtSeeker:
 		var err error
 
@@ -525,18 +712,43 @@ func (u *uploader) nextReader() (io.ReadSeeker, int, error) {
 
 		reader := io.NewSectionReader(r, u.readerPos, n)
 		u.readerPos += n
+		if n > 0 {
+			u.partsRead++
+		}
 
-		return reader, int(n), err
+		return reader, int(n), noopCleanup, err
 
 	default:
-		part := make([]byte, u.cfg.PartSize)
+		partSize := u.cfg.PartSize
+		if u.totalSize < 0 {
+			partSize = u.streamPartSize()
+		}
+
+		part := u.cfg.BufferProvider.GetBuffer(int(partSize))
 		n, err := readFillBuf(r, part)
 		u.readerPos += int64(n)
+		if n > 0 {
+			u.partsRead++
+		}
 
-		return bytes.NewReader(part[0:n]), n, err
+		cleanup := func() {
+			u.cfg.BufferProvider.PutBuffer(part)
+		}
+
+		return bytes.NewReader(part[0:n]), n, cleanup, err
 	}
 }
 
+// streamPartSize returns the size of the next part of an upload of unknown
+// size, doubling the PartSize every PartSizeGrowthInterval parts.
+func (u *uploader) streamPartSize() int64 {
+	size := u.cfg.PartSize << uint(u.partsRead/PartSizeGrowthInterval)
+	if size > MaxUploadPartSize && MaxUploadPartSize > u.cfg.PartSize {
+		size = MaxUploadPartSize
+	}
+	return size
+}
+
 func readFillBuf(r io.Reader, b []byte) (offset int, err error) {
 	for offset < len(b) && err == nil {
 		var n int
@@ -550,7 +762,7 @@ func readFillBuf(r io.Reader, b []byte) (offset int, err error) {
 // singlePart contains upload logic for uploading a single chunk via
 // a regular PutObject request. Multipart requests require at least two
 // parts, or at least 5MB of data.
-func (u *uploader) singlePart(buf io.ReadSeeker) (*UploadOutput, error) {
+func (u *uploader) singlePart(buf io.ReadSeeker, size int64) (*UploadOutput, error) {
 	params := &s3.PutObjectInput{}
 	awsutil.Copy(params, u.in)
 	params.Body = buf
@@ -563,6 +775,7 @@ func (u *uploader) singlePart(buf io.ReadSeeker) (*UploadOutput, error) {
 	if err := req.Send(); err != nil {
 		return nil, err
 	}
+	u.progress.partCompleted(size)
 
 	url := req.HTTPRequest.URL.String()
 	return &UploadOutput{
@@ -583,8 +796,12 @@ type multiuploader struct {
 
 // keeps track of a single chunk of data being sent to S3.
 type chunk struct {
-	buf io.ReadSeeker
-	num int64
+	buf  io.ReadSeeker
+	num  int64
+	size int64
+
+	// releases the chunk's buffer once the chunk has been sent.
+	cleanup func()
 }
 
 // completedParts is a wrapper to make parts sortable by their part number,
@@ -595,29 +812,30 @@ func (a completedParts) Len() int           { return len(a) }
 func (a completedParts) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a completedParts) Less(i, j int) bool { return *a[i].PartNumber < *a[j].PartNumber }
 
-// upload will perform a multipart upload using the firstBuf buffer containing
+// upload will perform a multipart upload using the firstChunk containing
 // the first chunk of data.
-func (u *multiuploader) upload(firstBuf io.ReadSeeker) (*UploadOutput, error) {
+func (u *multiuploader) upload(firstChunk chunk) (*UploadOutput, error) {
 	params := &s3.CreateMultipartUploadInput{}
 	awsutil.Copy(params, u.in)
 
 	// Create the multipart
 	resp, err := u.cfg.S3.CreateMultipartUploadWithContext(u.ctx, params, u.cfg.RequestOptions...)
 	if err != nil {
+		firstChunk.cleanup()
 		return nil, err
 	}
 	u.uploadID = *resp.UploadId
 
 	// Create the workers
 	ch := make(chan chunk, u.cfg.Concurrency)
-	for i := 0; i < u.cfg.Concurrency; i++ {
+	for i := 0; i < u.limiter.workers(u.cfg.Concurrency); i++ {
 		u.wg.Add(1)
 		go u.readChunk(ch)
 	}
 
 	// Send part 1 to the workers
 	var num int64 = 1
-	ch <- chunk{buf: firstBuf, num: num}
+	ch <- firstChunk
 
 	// Read and queue the rest of the parts
 	for u.geterr() == nil && err == nil {
@@ -638,9 +856,11 @@ func (u *multiuploader) upload(firstBuf io.ReadSeeker) (*UploadOutput, error) {
 
 		var reader io.ReadSeeker
 		var nextChunkLen int
-		reader, nextChunkLen, err = u.nextReader()
+		var cleanup func()
+		reader, nextChunkLen, cleanup, err = u.nextReader()
 
 		if err != nil && err != io.EOF {
+			cleanup()
 			u.seterr(awserr.New(
 				"ReadRequestBody",
 				"read multipart upload data failed",
@@ -648,14 +868,21 @@ func (u *multiuploader) upload(firstBuf io.ReadSeeker) (*UploadOutput, error) {
 			break
 		}
 
+		if err == io.EOF && u.totalSize < 0 {
+			// The size of the body is only known once it has been read
+			// entirely.
+			u.progress.setTotal(u.readerPos, int64(u.partsRead))
+		}
+
 		if nextChunkLen == 0 {
 			// No need to upload empty part, if file was empty to start
 			// with empty single part would of been created and never
 			// started multipart upload.
+			cleanup()
 			break
 		}
 
-		ch <- chunk{buf: reader, num: num}
+		ch <- chunk{buf: reader, num: num, size: int64(nextChunkLen), cleanup: cleanup}
 	}
 
 	// Close the channel, wait for workers, and complete upload
@@ -695,6 +922,8 @@ func (u *multiuploader) readChunk(ch chan chunk) {
 				u.seterr(err)
 			}
 		}
+
+		data.cleanup()
 	}
 }
 
@@ -710,7 +939,12 @@ func (u *multiuploader) send(c chunk) error {
 		SSECustomerKey:       u.in.SSECustomerKey,
 		PartNumber:           &c.num,
 	}
-	resp, err := u.cfg.S3.UploadPartWithContext(u.ctx, params, u.cfg.RequestOptions...)
+
+	var congested bool
+	u.limiter.acquire()
+	resp, err := u.cfg.S3.UploadPartWithContext(u.ctx, params,
+		u.limiter.observe(u.cfg.RequestOptions, &congested)...)
+	u.limiter.release(c.size, congested || err != nil)
 	if err != nil {
 		return err
 	}
@@ -722,6 +956,8 @@ func (u *multiuploader) send(c chunk) error {
 	u.parts = append(u.parts, completed)
 	u.m.Unlock()
 
+	u.progress.partCompleted(c.size)
+
 	return nil
 }
 
@@ -769,10 +1005,13 @@ func (u *multiuploader) complete() *s3.CompleteMultipartUploadOutput {
 	sort.Sort(u.parts)
 
 	params := &s3.CompleteMultipartUploadInput{
-		Bucket:          u.in.Bucket,
-		Key:             u.in.Key,
-		UploadId:        &u.uploadID,
-		MultipartUpload: &s3.CompletedMultipartUpload{Parts: u.parts},
+		Bucket:                  u.in.Bucket,
+		Key:                     u.in.Key,
+		UploadId:                &u.uploadID,
+		MultipartUpload:         &s3.CompletedMultipartUpload{Parts: u.parts},
+		RetentionExpirationDate: u.in.RetentionExpirationDate,
+		RetentionLegalHoldId:    u.in.RetentionLegalHoldId,
+		RetentionPeriod:         u.in.RetentionPeriod,
 	}
 	resp, err := u.cfg.S3.CompleteMultipartUploadWithContext(u.ctx, params, u.cfg.RequestOptions...)
 	if err != nil {
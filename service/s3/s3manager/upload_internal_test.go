@@ -0,0 +1,37 @@
+package s3manager
+
+import "testing"
+
+func TestUploaderStreamPartSize(t *testing.T) {
+	cases := []struct {
+		partSize  int64
+		partsRead int
+		expect    int64
+	}{
+		{MinUploadPartSize, 0, MinUploadPartSize},
+		{MinUploadPartSize, PartSizeGrowthInterval - 1, MinUploadPartSize},
+		{MinUploadPartSize, PartSizeGrowthInterval, 2 * MinUploadPartSize},
+		{MinUploadPartSize, 9*PartSizeGrowthInterval + 999, 512 * MinUploadPartSize},
+		{MaxUploadPartSize / 2, 2 * PartSizeGrowthInterval, MaxUploadPartSize},
+	}
+
+	for i, c := range cases {
+		u := uploader{cfg: Uploader{PartSize: c.partSize}, partsRead: c.partsRead}
+		if e, a := c.expect, u.streamPartSize(); e != a {
+			t.Errorf("%d, expect %v part size, got %v", i, e, a)
+		}
+	}
+}
+
+func TestUploaderStreamCapacity(t *testing.T) {
+	u := uploader{cfg: Uploader{PartSize: DefaultUploadPartSize}}
+
+	var total int64
+	for ; u.partsRead < MaxUploadParts; u.partsRead++ {
+		total += u.streamPartSize()
+	}
+
+	if e, a := 1023*PartSizeGrowthInterval*DefaultUploadPartSize, total; e != a {
+		t.Errorf("expect %v bytes, got %v", e, a)
+	}
+}
@@ -2,9 +2,11 @@ package s3manager_test
 
 import (
 	"bytes"
+	"compress/gzip"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -13,6 +15,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -1001,3 +1004,459 @@ func TestUploadWithContextCanceled(t *testing.T) {
 		t.Errorf("expected error message to contain %q, but did not %q", e, a)
 	}
 }
+
+type countingBufferProvider struct {
+	m         sync.Mutex
+	gets      int
+	puts      int
+	delegated s3manager.UploadBufferProvider
+}
+
+func (p *countingBufferProvider) GetBuffer(size int) []byte {
+	p.m.Lock()
+	p.gets++
+	p.m.Unlock()
+	return p.delegated.GetBuffer(size)
+}
+
+func (p *countingBufferProvider) PutBuffer(b []byte) {
+	p.m.Lock()
+	p.puts++
+	p.m.Unlock()
+	p.delegated.PutBuffer(b)
+}
+
+func TestUploadBufferProvider(t *testing.T) {
+	s, ops, args := loggingSvc(emptyList)
+	provider := &countingBufferProvider{delegated: s3manager.NewPooledBufferProvider()}
+	mgr := s3manager.NewUploaderWithClient(s, func(u *s3manager.Uploader) {
+		u.BufferProvider = provider
+	})
+
+	_, err := mgr.Upload(&s3manager.UploadInput{
+		Bucket: aws.String("Bucket"),
+		Key:    aws.String("Key"),
+		Body:   &sizedReader{size: 1024 * 1024 * 12},
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := 5, len(*ops); e != a {
+		t.Errorf("expect %v operations, got %v", e, a)
+	}
+	if e, a := 3, provider.gets; e != a {
+		t.Errorf("expect %v buffers, got %v", e, a)
+	}
+	if e, a := provider.gets, provider.puts; e != a {
+		t.Errorf("expect %v buffers released, got %v", e, a)
+	}
+
+	var total int
+	for _, arg := range (*args)[1:4] {
+		total += buflen(val(arg, "Body"))
+	}
+	if e, a := 1024*1024*12, total; e != a {
+		t.Errorf("expect %v bytes uploaded, got %v", e, a)
+	}
+}
+
+func TestUploadBufferProvider_Failure(t *testing.T) {
+	s, _, _ := loggingSvc(emptyList)
+	s.Handlers.Send.PushBack(func(r *request.Request) {
+		if r.Operation.Name == "UploadPart" {
+			r.HTTPResponse.StatusCode = 400
+		}
+	})
+	provider := &countingBufferProvider{delegated: s3manager.NewPooledBufferProvider()}
+	mgr := s3manager.NewUploaderWithClient(s, func(u *s3manager.Uploader) {
+		u.BufferProvider = provider
+	})
+
+	_, err := mgr.Upload(&s3manager.UploadInput{
+		Bucket: aws.String("Bucket"),
+		Key:    aws.String("Key"),
+		Body:   &sizedReader{size: 1024 * 1024 * 12},
+	})
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+
+	if e, a := provider.gets, provider.puts; e != a {
+		t.Errorf("expect %v buffers released, got %v", e, a)
+	}
+}
+
+type sizedReaderAt struct {
+	r *bytes.Reader
+}
+
+func (s *sizedReaderAt) Read(p []byte) (int, error) {
+	return 0, fmt.Errorf("expect body to not be read sequentially")
+}
+
+func (s *sizedReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return s.r.ReadAt(p, off)
+}
+
+func (s *sizedReaderAt) Size() int64 {
+	return s.r.Size()
+}
+
+func TestUploadReaderAtSizer(t *testing.T) {
+	s, ops, args := loggingSvc(emptyList)
+	provider := &countingBufferProvider{delegated: s3manager.NewPooledBufferProvider()}
+	mgr := s3manager.NewUploaderWithClient(s, func(u *s3manager.Uploader) {
+		u.BufferProvider = provider
+	})
+
+	_, err := mgr.Upload(&s3manager.UploadInput{
+		Bucket: aws.String("Bucket"),
+		Key:    aws.String("Key"),
+		Body:   &sizedReaderAt{r: bytes.NewReader(buf12MB)},
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := []string{"CreateMultipartUpload", "UploadPart", "UploadPart", "UploadPart", "CompleteMultipartUpload"}, *ops; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := 0, provider.gets; e != a {
+		t.Errorf("expect %v buffers, got %v", e, a)
+	}
+
+	parts := []int{
+		buflen(val((*args)[1], "Body")),
+		buflen(val((*args)[2], "Body")),
+		buflen(val((*args)[3], "Body")),
+	}
+	sort.Ints(parts)
+	if e, a := []int{1024 * 1024 * 2, 1024 * 1024 * 5, 1024 * 1024 * 5}, parts; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestUploadRetentionMulti(t *testing.T) {
+	s, ops, args := loggingSvc(emptyList)
+	u := s3manager.NewUploaderWithClient(s)
+
+	_, err := u.Upload(&s3manager.UploadInput{
+		Bucket:               aws.String("Bucket"),
+		Key:                  aws.String("Key"),
+		Body:                 bytes.NewReader(buf12MB),
+		RetentionLegalHoldId: aws.String("hold"),
+		RetentionPeriod:      aws.Int64(3600),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	last := len(*ops) - 1
+	if e, a := "CompleteMultipartUpload", (*ops)[last]; e != a {
+		t.Fatalf("expect %v, got %v", e, a)
+	}
+	params := (*args)[last].(*s3.CompleteMultipartUploadInput)
+	if e, a := "hold", aws.StringValue(params.RetentionLegalHoldId); e != a {
+		t.Errorf("expect %v legal hold, got %v", e, a)
+	}
+	if e, a := int64(3600), aws.Int64Value(params.RetentionPeriod); e != a {
+		t.Errorf("expect %v retention period, got %v", e, a)
+	}
+}
+
+func TestUploadProgress(t *testing.T) {
+	cases := map[string]io.Reader{
+		"known size":   bytes.NewReader(buf12MB),
+		"unknown size": &sizedReader{size: len(buf12MB)},
+	}
+
+	for name, body := range cases {
+		s, _, _ := loggingSvc(emptyList)
+		u := s3manager.NewUploaderWithClient(s)
+
+		var reports []s3manager.Progress
+		_, err := u.Upload(&s3manager.UploadInput{
+			Bucket: aws.String("Bucket"),
+			Key:    aws.String("Key"),
+			Body:   body,
+		}, func(u *s3manager.Uploader) {
+			u.OnProgress = func(p s3manager.Progress) {
+				reports = append(reports, p)
+			}
+		})
+		if err != nil {
+			t.Fatalf("%s, expect no error, got %v", name, err)
+		}
+
+		if e, a := 3, len(reports); e != a {
+			t.Fatalf("%s, expect %v reports, got %v", name, e, a)
+		}
+		last := reports[len(reports)-1]
+		if e, a := int64(len(buf12MB)), last.BytesTransferred; e != a {
+			t.Errorf("%s, expect %v bytes transferred, got %v", name, e, a)
+		}
+		if e, a := int64(len(buf12MB)), last.TotalBytes; e != a {
+			t.Errorf("%s, expect %v total bytes, got %v", name, e, a)
+		}
+		if e, a := int64(3), last.PartsCompleted; e != a {
+			t.Errorf("%s, expect %v parts completed, got %v", name, e, a)
+		}
+		if e, a := int64(3), last.TotalParts; e != a {
+			t.Errorf("%s, expect %v total parts, got %v", name, e, a)
+		}
+	}
+}
+
+func TestUploadProgressSinglePart(t *testing.T) {
+	s, _, _ := loggingSvc(emptyList)
+	ch := make(chan s3manager.Progress, 10)
+	u := s3manager.NewUploaderWithClient(s, func(u *s3manager.Uploader) {
+		u.OnProgress = s3manager.ProgressChannel(ch)
+	})
+
+	_, err := u.Upload(&s3manager.UploadInput{
+		Bucket: aws.String("Bucket"),
+		Key:    aws.String("Key"),
+		Body:   bytes.NewReader(buf2MB),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	close(ch)
+
+	var reports []s3manager.Progress
+	for p := range ch {
+		reports = append(reports, p)
+	}
+	if e, a := 1, len(reports); e != a {
+		t.Fatalf("expect %v reports, got %v", e, a)
+	}
+	expect := s3manager.Progress{
+		BytesTransferred: int64(len(buf2MB)),
+		TotalBytes:       int64(len(buf2MB)),
+		PartsCompleted:   1,
+		TotalParts:       1,
+	}
+	reports[0].BytesPerSecond, reports[0].Elapsed = 0, 0
+	if e, a := expect, reports[0]; e != a {
+		t.Errorf("expect %+v, got %+v", e, a)
+	}
+}
+
+func TestUploadAdaptiveConcurrency(t *testing.T) {
+	s, ops, _ := loggingSvc(emptyList)
+
+	var m sync.Mutex
+	failed := false
+	s.Handlers.Send.PushBack(func(r *request.Request) {
+		m.Lock()
+		defer m.Unlock()
+
+		// Throttle the first part once, which is retried.
+		if r.Operation.Name == "UploadPart" && !failed {
+			failed = true
+			r.HTTPResponse.StatusCode = 503
+			r.Error = awserr.New("SlowDown", "reduce your request rate", nil)
+		}
+	})
+	s.Handlers.Retry.PushFront(func(r *request.Request) {
+		r.Config.SleepDelay = func(time.Duration) {}
+	})
+
+	u := s3manager.NewUploaderWithClient(s, func(u *s3manager.Uploader) {
+		u.AdaptiveConcurrency = true
+		u.Concurrency = 1
+		u.MaxConcurrency = 2
+	})
+	_, err := u.Upload(&s3manager.UploadInput{
+		Bucket: aws.String("Bucket"),
+		Key:    aws.String("Key"),
+		Body:   bytes.NewReader(buf12MB),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := []string{"CreateMultipartUpload", "UploadPart", "UploadPart", "UploadPart", "UploadPart", "CompleteMultipartUpload"}
+	if e, a := expect, *ops; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v API calls, got %v", e, a)
+	}
+}
+
+type recordingBufferProvider struct {
+	m     sync.Mutex
+	sizes []int
+}
+
+func (p *recordingBufferProvider) GetBuffer(size int) []byte {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.sizes = append(p.sizes, size)
+	return make([]byte, size)
+}
+
+func (p *recordingBufferProvider) PutBuffer([]byte) {}
+
+func TestUploadUnknownSizeStream(t *testing.T) {
+	cases := map[string]struct {
+		size   int
+		expect []string
+	}{
+		"single part": {
+			size:   1024 * 1024 * 2,
+			expect: []string{"PutObject"},
+		},
+		"multipart": {
+			size:   1024 * 1024 * 12,
+			expect: []string{"CreateMultipartUpload", "UploadPart", "UploadPart", "UploadPart", "CompleteMultipartUpload"},
+		},
+	}
+
+	for name, c := range cases {
+		s, ops, args := loggingSvc(emptyList)
+		provider := &recordingBufferProvider{}
+		u := s3manager.NewUploaderWithClient(s, func(u *s3manager.Uploader) {
+			u.BufferProvider = provider
+		})
+
+		_, err := u.Upload(&s3manager.UploadInput{
+			Bucket: aws.String("Bucket"),
+			Key:    aws.String("Key"),
+			Body:   ioutil.NopCloser(&sizedReader{size: c.size}),
+		})
+		if err != nil {
+			t.Fatalf("%s, expect no error, got %v", name, err)
+		}
+		if e, a := c.expect, *ops; !reflect.DeepEqual(e, a) {
+			t.Errorf("%s, expect %v API calls, got %v", name, e, a)
+		}
+
+		var total int
+		for _, arg := range *args {
+			if body := val(arg, "Body"); body != nil {
+				total += buflen(body)
+			}
+		}
+		if e, a := c.size, total; e != a {
+			t.Errorf("%s, expect %v bytes uploaded, got %v", name, e, a)
+		}
+		for _, size := range provider.sizes {
+			if e, a := int(s3manager.DefaultUploadPartSize), size; e != a {
+				t.Errorf("%s, expect %v buffer size, got %v", name, e, a)
+			}
+		}
+	}
+}
+
+func TestUploadCompress(t *testing.T) {
+	text := bytes.Repeat([]byte("hello world\n"), 1024*1024)
+	random := make([]byte, 1024*1024*12)
+	r := rand.New(rand.NewSource(1))
+	for i := range random {
+		random[i] = byte(r.Intn(256))
+	}
+
+	cases := map[string]struct {
+		body        []byte
+		contentType string
+		encoding    string
+		expectOps   []string
+		expectGzip  bool
+	}{
+		"single part": {
+			body: text, contentType: "text/plain; charset=utf-8",
+			expectOps:  []string{"PutObject"},
+			expectGzip: true,
+		},
+		"multipart": {
+			body: random, contentType: "application/json",
+			expectOps:  []string{"CreateMultipartUpload", "UploadPart", "UploadPart", "UploadPart", "CompleteMultipartUpload"},
+			expectGzip: true,
+		},
+		"content type not allowed": {
+			body: text, contentType: "image/png",
+			expectOps: []string{"CreateMultipartUpload", "UploadPart", "UploadPart", "UploadPart", "CompleteMultipartUpload"},
+		},
+		"below min size": {
+			body: text[:512], contentType: "text/plain",
+			expectOps: []string{"PutObject"},
+		},
+		"already encoded": {
+			body: text[:2048], contentType: "text/plain", encoding: "br",
+			expectOps: []string{"PutObject"},
+		},
+	}
+
+	for name, c := range cases {
+		s, ops, args := loggingSvc(emptyList)
+		// The buffers of parts are reused once sent, so record the parts'
+		// bodies as they are sent, in any order.
+		var m sync.Mutex
+		parts := map[int64][]byte{}
+		s.Handlers.Send.PushFront(func(r *request.Request) {
+			m.Lock()
+			defer m.Unlock()
+
+			if body, ok := val(r.Params, "Body").(io.ReadSeeker); ok {
+				num, _ := val(r.Params, "PartNumber").(int64)
+				parts[num], _ = ioutil.ReadAll(body)
+				body.Seek(0, 0)
+			}
+		})
+		u := s3manager.NewUploaderWithClient(s, func(u *s3manager.Uploader) {
+			u.Compress = true
+		})
+
+		input := &s3manager.UploadInput{
+			Bucket:      aws.String("Bucket"),
+			Key:         aws.String("Key"),
+			Body:        bytes.NewReader(c.body),
+			ContentType: aws.String(c.contentType),
+		}
+		if len(c.encoding) != 0 {
+			input.ContentEncoding = aws.String(c.encoding)
+		}
+		if _, err := u.Upload(input); err != nil {
+			t.Fatalf("%s, expect no error, got %v", name, err)
+		}
+		if input.ContentEncoding != nil && len(c.encoding) == 0 {
+			t.Errorf("%s, expect input not to be modified", name)
+		}
+		if e, a := c.expectOps, *ops; !reflect.DeepEqual(e, a) {
+			t.Fatalf("%s, expect %v API calls, got %v", name, e, a)
+		}
+
+		encoding, _ := val((*args)[0], "ContentEncoding").(string)
+		var uploaded []byte
+		for i := int64(0); i <= int64(len(parts)); i++ {
+			uploaded = append(uploaded, parts[i]...)
+		}
+
+		if !c.expectGzip {
+			if e, a := c.encoding, encoding; e != a {
+				t.Errorf("%s, expect %q content encoding, got %q", name, e, a)
+			}
+			if !bytes.Equal(c.body, uploaded) {
+				t.Errorf("%s, expect body uploaded unmodified", name)
+			}
+			continue
+		}
+
+		if e, a := "gzip", encoding; e != a {
+			t.Errorf("%s, expect %q content encoding, got %q", name, e, a)
+		}
+		gr, err := gzip.NewReader(bytes.NewReader(uploaded))
+		if err != nil {
+			t.Fatalf("%s, expect no error, got %v", name, err)
+		}
+		b, err := ioutil.ReadAll(gr)
+		if err != nil {
+			t.Fatalf("%s, expect no error, got %v", name, err)
+		}
+		if !bytes.Equal(c.body, b) {
+			t.Errorf("%s, expect decompressed body to match", name)
+		}
+	}
+}
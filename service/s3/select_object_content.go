@@ -0,0 +1,94 @@
+package s3
+
+import (
+	"bufio"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// ErrCodeNotSupported is the error code returned by SelectObjectContent when
+// the endpoint does not support the operation, such as COS endpoints without
+// SQL pushdown support. The endpoint either rejects the request as not
+// implemented, or responds with a body which is not an event stream.
+const ErrCodeNotSupported = "NotSupported"
+
+// selectNotSupportedCodes are the error codes of endpoints rejecting
+// SelectObjectContent requests as an operation they do not support.
+var selectNotSupportedCodes = map[string]struct{}{
+	"NotImplemented":   {},
+	"MethodNotAllowed": {},
+}
+
+// eventStreamPreludeLen is the length of the prelude of event stream
+// messages: the total and headers lengths, followed by their CRC.
+const eventStreamPreludeLen = 12
+
+// selectNotSupportedError replaces the error of SelectObjectContent requests
+// rejected by the endpoint as not implemented with a NotSupported error.
+func selectNotSupportedError(r *request.Request) {
+	aerr, ok := r.Error.(awserr.RequestFailure)
+	if !ok {
+		return
+	}
+
+	_, unsupported := selectNotSupportedCodes[aerr.Code()]
+	switch aerr.StatusCode() {
+	case http.StatusNotImplemented, http.StatusMethodNotAllowed:
+		unsupported = true
+	}
+	if !unsupported {
+		return
+	}
+
+	r.Error = newSelectNotSupportedError(r, aerr)
+}
+
+// verifySelectEventStream verifies the response body of SelectObjectContent
+// requests starts with an event stream message prelude, failing the request
+// with a NotSupported error otherwise. Endpoints which do not support the
+// operation may ignore the select query and respond without an event stream.
+func verifySelectEventStream(r *request.Request) {
+	if r.Error != nil || r.HTTPResponse == nil || r.HTTPResponse.Body == nil {
+		return
+	}
+
+	body := r.HTTPResponse.Body
+	br := bufio.NewReader(body)
+	prelude, err := br.Peek(eventStreamPreludeLen)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		r.Error = awserr.New(request.ErrCodeSerialization,
+			"failed to read SelectObjectContent event stream", err)
+		return
+	}
+
+	if len(prelude) < eventStreamPreludeLen ||
+		crc32.ChecksumIEEE(prelude[:8]) != binary.BigEndian.Uint32(prelude[8:]) {
+		io.Copy(ioutil.Discard, br)
+		body.Close()
+		r.Error = newSelectNotSupportedError(r, nil)
+		return
+	}
+
+	r.HTTPResponse.Body = struct {
+		io.Reader
+		io.Closer
+	}{br, body}
+}
+
+func newSelectNotSupportedError(r *request.Request, origErr error) error {
+	return requestFailure{
+		RequestFailure: awserr.NewRequestFailure(
+			awserr.New(ErrCodeNotSupported,
+				"SelectObjectContent is not supported by the endpoint", origErr),
+			r.HTTPResponse.StatusCode,
+			r.RequestID,
+		),
+		hostID: r.HTTPResponse.Header.Get("X-Amz-Id-2"),
+	}
+}
@@ -0,0 +1,197 @@
+package s3_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/credentialstest"
+	"github.com/aws/aws-sdk-go/aws/signer/ibm"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/awstesting/unit"
+	"github.com/aws/aws-sdk-go/private/protocol/eventstream"
+	"github.com/aws/aws-sdk-go/private/protocol/eventstream/eventstreamapi"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func newSelectTestSvc(handler http.HandlerFunc) (*s3.S3, func()) {
+	server := httptest.NewServer(handler)
+
+	svc := s3.New(unit.Session, aws.NewConfig().
+		WithEndpoint(server.URL).
+		WithDisableSSL(true).
+		WithMaxRetries(0).
+		WithS3ForcePathStyle(true).
+		WithCredentials(credentials.NewTypedCredentials(&credentialstest.MockProvider{
+			Value: credentials.Value{SessionToken: "token"},
+		}, "ibm-iam")))
+	svc.Handlers.Sign.Remove(v4.SignRequestHandler)
+	svc.Handlers.Sign.PushBackNamed(ibm.SignRequestHandler)
+
+	return svc, server.Close
+}
+
+func newSelectInput() *s3.SelectObjectContentInput {
+	return &s3.SelectObjectContentInput{
+		Bucket:         aws.String("bucket"),
+		Key:            aws.String("key.csv"),
+		Expression:     aws.String("SELECT * FROM S3Object"),
+		ExpressionType: aws.String(s3.ExpressionTypeSql),
+		InputSerialization: &s3.InputSerialization{
+			CSV: &s3.CSVInput{FileHeaderInfo: aws.String(s3.FileHeaderInfoUse)},
+		},
+		OutputSerialization: &s3.OutputSerialization{
+			CSV: &s3.CSVOutput{},
+		},
+	}
+}
+
+func eventMessage(eventType string, payload []byte) eventstream.Message {
+	var msg eventstream.Message
+	msg.Headers.Set(eventstreamapi.MessageTypeHeader, eventstream.StringValue(eventstreamapi.EventMessageType))
+	msg.Headers.Set(eventstreamapi.EventTypeHeader, eventstream.StringValue(eventType))
+	msg.Payload = payload
+	return msg
+}
+
+func TestSelectObjectContent(t *testing.T) {
+	svc, closeFn := newSelectTestSvc(func(w http.ResponseWriter, r *http.Request) {
+		if e, a := "/bucket/key.csv", r.URL.Path; e != a {
+			t.Errorf("expect %v path, got %v", e, a)
+		}
+		if _, ok := r.URL.Query()["select"]; !ok {
+			t.Errorf("expect select query, got %v", r.URL.RawQuery)
+		}
+		if e, a := "2", r.URL.Query().Get("select-type"); e != a {
+			t.Errorf("expect %v select type, got %v", e, a)
+		}
+		if e, a := "Bearer token", r.Header.Get("Authorization"); e != a {
+			t.Errorf("expect %v authorization, got %v", e, a)
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		if e, a := "<Expression>SELECT * FROM S3Object</Expression>", string(b); !strings.Contains(a, e) {
+			t.Errorf("expect body to contain %v, got %v", e, a)
+		}
+
+		enc := eventstream.NewEncoder(w)
+		enc.Encode(eventMessage("Records", []byte("a,b\n")))
+		enc.Encode(eventMessage("Unknown", nil))
+		enc.Encode(eventMessage("Records", []byte("c,d\n")))
+		enc.Encode(eventMessage("Stats", []byte(`<Stats><BytesScanned>10</BytesScanned><BytesProcessed>10</BytesProcessed><BytesReturned>8</BytesReturned></Stats>`)))
+		enc.Encode(eventMessage("End", nil))
+	})
+	defer closeFn()
+
+	out, err := svc.SelectObjectContent(newSelectInput())
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer out.EventStream.Close()
+
+	var records bytes.Buffer
+	var stats *s3.Stats
+	var end bool
+	for event := range out.EventStream.Events() {
+		switch e := event.(type) {
+		case *s3.RecordsEvent:
+			records.Write(e.Payload)
+		case *s3.StatsEvent:
+			stats = e.Details
+		case *s3.EndEvent:
+			end = true
+		}
+	}
+	if err := out.EventStream.Err(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := "a,b\nc,d\n", records.String(); e != a {
+		t.Errorf("expect %q records, got %q", e, a)
+	}
+	if stats == nil {
+		t.Fatalf("expect stats event, got none")
+	}
+	if e, a := int64(8), aws.Int64Value(stats.BytesReturned); e != a {
+		t.Errorf("expect %v bytes returned, got %v", e, a)
+	}
+	if !end {
+		t.Errorf("expect end event")
+	}
+}
+
+func TestSelectObjectContent_StreamError(t *testing.T) {
+	svc, closeFn := newSelectTestSvc(func(w http.ResponseWriter, r *http.Request) {
+		var msg eventstream.Message
+		msg.Headers.Set(eventstreamapi.MessageTypeHeader, eventstream.StringValue(eventstreamapi.ErrorMessageType))
+		msg.Headers.Set(eventstreamapi.ErrorCodeHeader, eventstream.StringValue("InternalError"))
+		msg.Headers.Set(eventstreamapi.ErrorMessageHeader, eventstream.StringValue("failed"))
+		eventstream.NewEncoder(w).Encode(msg)
+	})
+	defer closeFn()
+
+	out, err := svc.SelectObjectContent(newSelectInput())
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer out.EventStream.Close()
+
+	for range out.EventStream.Events() {
+		t.Errorf("expect no events")
+	}
+	err = out.EventStream.Err()
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := "InternalError", err.(awserr.Error).Code(); e != a {
+		t.Errorf("expect %v error code, got %v", e, a)
+	}
+}
+
+func TestSelectObjectContent_NotSupported(t *testing.T) {
+	cases := map[string]struct {
+		Status int
+		Body   string
+	}{
+		"not implemented": {
+			Status: http.StatusNotImplemented,
+			Body:   `<Error><Code>NotImplemented</Code><Message>not implemented</Message></Error>`,
+		},
+		"method not allowed": {
+			Status: http.StatusMethodNotAllowed,
+		},
+		"not an event stream": {
+			Status: http.StatusOK,
+			Body:   "a,b\nc,d\n",
+		},
+		"empty body": {
+			Status: http.StatusOK,
+		},
+	}
+
+	for name, c := range cases {
+		svc, closeFn := newSelectTestSvc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(c.Status)
+			w.Write([]byte(c.Body))
+		})
+
+		_, err := svc.SelectObjectContent(newSelectInput())
+		closeFn()
+		if err == nil {
+			t.Errorf("%s: expect error, got none", name)
+			continue
+		}
+		aerr := err.(awserr.RequestFailure)
+		if e, a := s3.ErrCodeNotSupported, aerr.Code(); e != a {
+			t.Errorf("%s: expect %v error code, got %v", name, e, a)
+		}
+		if e, a := c.Status, aerr.StatusCode(); e != a {
+			t.Errorf("%s: expect %v status code, got %v", name, e, a)
+		}
+	}
+}
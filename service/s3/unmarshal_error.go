@@ -63,14 +63,14 @@ func unmarshalError(r *request.Request) {
 		errMsg = statusText
 	}
 
-	r.Error = requestFailure{
+	r.Error = newConditionalError(requestFailure{
 		RequestFailure: awserr.NewRequestFailure(
 			awserr.New(errCode, errMsg, err),
 			r.HTTPResponse.StatusCode,
 			r.RequestID,
 		),
 		hostID: hostID,
-	}
+	})
 }
 
 // A RequestFailure provides access to the S3 Request ID and Host ID values